@@ -21,9 +21,40 @@ const (
 	Production
 )
 
+type ServiceClientsMode int
+
+const (
+	InProcessClients ServiceClientsMode = iota
+	HTTPClients
+)
+
+type PayoutProviderType int
+
+const (
+	StripeConnectPayouts PayoutProviderType = iota
+	FakePayouts
+)
+
+type RateLimitBackendType int
+
+const (
+	MemoryRateLimit RateLimitBackendType = iota
+	PostgresRateLimit
+)
+
+type OrchestratorBackendType int
+
+const (
+	MemoryOrchestrator OrchestratorBackendType = iota
+	NomadOrchestrator
+	LocalOrchestrator
+)
+
 type ServerConfig struct {
 	Hostname              string
 	Port                  int
+	InternalPort          int
+	TrustedProxies        []string
 	ShutdownTimeout       time.Duration
 	Environment           EnvironmentType
 	AdminEmail            string
@@ -32,6 +63,8 @@ type ServerConfig struct {
 	SubscriptionOn        bool
 	CreatorRevenuePercent float64
 	DollarsGemsRatio      float64
+	GiftsDailyLimit       int
+	RequireAdmin2FA       bool
 }
 
 type DatabaseConfig struct {
@@ -82,18 +115,149 @@ type StripeConfig struct {
 	Zones                            []StripeItem
 }
 
+type PayoutsConfig struct {
+	Provider      PayoutProviderType
+	MinimumAmount float64
+	Currency      string
+}
+
 type GithubConfig struct {
 	GithubAudienceWebhook string
 	GithubRepoURLWebhook  string
 }
 
+type ServiceClientsConfig struct {
+	Mode        ServiceClientsMode
+	AssetsURL   string
+	PaymentsURL string
+	WorldURL    string
+	PlayersURL  string
+	AuthURL     string
+	Timeout     time.Duration
+}
+
+type OIDCProviderConfig struct {
+	Name         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+}
+
+type OIDCConfig struct {
+	Providers []OIDCProviderConfig
+	// RedirectURL is the client page providers send the user back to, it must be registered with every provider.
+	RedirectURL string
+	StateTTL    time.Duration
+}
+
+type AccountDeletionConfig struct {
+	// GracePeriod is how long a deleted account can still be restored before its data is erased.
+	GracePeriod time.Duration
+	// PurgeInterval is how often accounts past their grace period are erased, 0 disables the purge.
+	PurgeInterval time.Duration
+	CancelURL     string
+}
+
+type RateLimitConfig struct {
+	Backend                RateLimitBackendType
+	AuthPerIPPerMinute     int
+	LoginPerEmailPerMinute int
+	SignupPerIPPerHour     int
+	EmailsPerHour          int
+	LockoutThreshold       int
+	LockoutDuration        time.Duration
+	LockoutMaxDuration     time.Duration
+	AccountUnlockURL       string
+}
+
+type OrchestratorConfig struct {
+	Backend OrchestratorBackendType
+	// LocalCommand is the template of the command the local backend runs for each zone server.
+	LocalCommand   string
+	LocalHost      string
+	LocalPortStart int
+	LocalPortCount int
+}
+
+type ReconcilerConfig struct {
+	// Interval is how often the running zone servers are compared with the zone states, 0 disables it.
+	Interval time.Duration
+	// OrphanGracePeriod is how long a job without an active zone is left running, a zone being activated starts its job first.
+	OrphanGracePeriod time.Duration
+}
+
+type ServerRegistryConfig struct {
+	// HeartbeatTimeout is how long a zone server may go without a heartbeat before its zone is marked offline.
+	HeartbeatTimeout time.Duration
+	// HeartbeatCheckInterval is how often servers are checked for missed heartbeats, 0 disables the check.
+	HeartbeatCheckInterval time.Duration
+}
+
+type RolloutConfig struct {
+	// BatchSize is how many zones are updated at once after the canaries.
+	BatchSize int
+	// BatchPause is how long a healthy batch is left running before the next one starts.
+	BatchPause time.Duration
+	// HealthTimeout is how long an updated zone has to pass its health checks before the rollout is rolled back.
+	HealthTimeout time.Duration
+	// CheckInterval is how often the leader replica advances the active rollout, 0 disables rollouts.
+	CheckInterval time.Duration
+	// CanaryZones are the <world id>:<zone id> zones updated first, in a batch of their own.
+	CanaryZones []string
+}
+
+// ZoneTier is the resources a zone server runs with and the subscription slots a zone on it holds.
+type ZoneTier struct {
+	Name        string
+	CPU         int
+	MemoryMB    int
+	MemoryMaxMB int
+	Datacenter  string
+	Slots       int
+}
+
+type ZoneTiersConfig struct {
+	// Default is the tier of the zones that never selected one.
+	Default string
+	Tiers   map[string]ZoneTier
+}
+
+// Tier returns the tier with the given name, the default one when the name is empty.
+func (c *ZoneTiersConfig) Tier(name string) (ZoneTier, bool) {
+	if name == "" {
+		name = c.Default
+	}
+	tier, ok := c.Tiers[name]
+	return tier, ok
+}
+
+// TierOrDefault returns the tier with the given name, the default one when it is not offered.
+func (c *ZoneTiersConfig) TierOrDefault(name string) ZoneTier {
+	if tier, ok := c.Tier(name); ok {
+		return tier
+	}
+	return c.Tiers[c.Default]
+}
+
 type Config struct {
 	Server                       *ServerConfig
 	DB                           *DatabaseConfig
 	Assets                       *AssetsConfig
 	Stripe                       *StripeConfig
+	Payouts                      *PayoutsConfig
 	Github                       *GithubConfig
-	SessionAccessTokenSecretKey  string
+	ServiceClients               *ServiceClientsConfig
+	RateLimit                    *RateLimitConfig
+	OIDC                         *OIDCConfig
+	AccountDeletion              *AccountDeletionConfig
+	Orchestrator                 *OrchestratorConfig
+	Reconciler                   *ReconcilerConfig
+	ServerRegistry               *ServerRegistryConfig
+	Rollout                      *RolloutConfig
+	ZoneTiers                    *ZoneTiersConfig
+	SessionSigningKeysDir        string
+	SessionSigningKeyId          string
 	SessionRefreshTokenSecretKey string
 	SessionAccessTokenDuration   time.Duration
 	SessionRefreshTokenDuration  time.Duration
@@ -103,6 +267,9 @@ type Config struct {
 	EmailLogoURL                 string
 	SupportEmail                 string
 	ServerFixedToken             string
+	ServerFixedTokenEnabled      bool
+	InternalAuthSecret           string
+	InternalAuthMaxSkew          time.Duration
 	NomadAddr                    string
 	NomadToken                   string
 	NomadCertPath                string
@@ -154,6 +321,8 @@ func CreateConfig() *Config {
 	serverConf := &ServerConfig{
 		Hostname:              getEnvOrDefaultString("SERVER_HOSTNAME", "localhost"),
 		Port:                  getEnvOrDefaultInt("SERVER_PORT", 8000),
+		InternalPort:          getEnvOrDefaultInt("SERVER_INTERNAL_PORT", 0),
+		TrustedProxies:        strings.Fields(strings.ReplaceAll(os.Getenv("SERVER_TRUSTED_PROXIES"), ",", " ")),
 		ShutdownTimeout:       getEnvOrDefaultDuration("SERVER_SHUTDOWN_TIMEOUT", time.Second*30),
 		Environment:           getEnvironmentType(os.Getenv("SERVER_ENVIRONMENT")),
 		AdminEmail:            getEnvOrDefaultString("SERVER_ADMIN_EMAIL", ""),
@@ -162,6 +331,8 @@ func CreateConfig() *Config {
 		SubscriptionOn:        getEnvOrDefaultBool("SUBSCRIPTION_ON", true),
 		CreatorRevenuePercent: getEnvOrDefaultFloat("CREATOR_REVENUE_PERCENT", 0.1),
 		DollarsGemsRatio:      getEnvOrDefaultFloat("DOLLARS_GEMS_RATIO", 0.25),
+		GiftsDailyLimit:       getEnvOrDefaultInt("GIFTS_DAILY_LIMIT", 5),
+		RequireAdmin2FA:       getEnvOrDefaultBool("REQUIRE_ADMIN_2FA", false),
 	}
 
 	stripeRealPrices := getEnvOrDefaultBool("STRIPE_REAL_PRICES", true)
@@ -187,11 +358,92 @@ func CreateConfig() *Config {
 		Zones:                            zones,
 	}
 
+	payoutsConf := &PayoutsConfig{
+		Provider:      getPayoutProviderType(os.Getenv("PAYOUT_PROVIDER")),
+		MinimumAmount: getEnvOrDefaultFloat("CREATOR_PAYOUT_MINIMUM", 10),
+		Currency:      getEnvOrDefaultString("CREATOR_PAYOUT_CURRENCY", "usd"),
+	}
+
 	githubConf := &GithubConfig{
 		GithubAudienceWebhook: getEnvOrDefaultString("GITHUB_AUDIENCE_WEBHOOK", "ftr-update-server"),
 		GithubRepoURLWebhook:  getEnvOrDefaultString("GITHUB_REPO_URL_WEBHOOK", "FeedTheRealm-org/game"),
 	}
 
+	internalPort := serverConf.Port
+	if serverConf.InternalPort > 0 {
+		internalPort = serverConf.InternalPort
+	}
+	loopbackURL := "http://127.0.0.1:" + strconv.Itoa(internalPort)
+	serviceClientsConf := &ServiceClientsConfig{
+		Mode:        getServiceClientsMode(os.Getenv("SERVICE_CLIENTS_MODE")),
+		AssetsURL:   getEnvOrDefaultString("ASSETS_SERVICE_URL", loopbackURL),
+		PaymentsURL: getEnvOrDefaultString("PAYMENT_SERVICE_URL", loopbackURL),
+		WorldURL:    getEnvOrDefaultString("WORLD_SERVICE_URL", loopbackURL),
+		PlayersURL:  getEnvOrDefaultString("PLAYERS_SERVICE_URL", loopbackURL),
+		AuthURL:     getEnvOrDefaultString("AUTH_SERVICE_URL", loopbackURL),
+		Timeout:     getEnvOrDefaultDuration("SERVICE_CLIENTS_TIMEOUT", time.Second*10),
+	}
+
+	rateLimitConf := &RateLimitConfig{
+		Backend:                getRateLimitBackendType(os.Getenv("RATE_LIMIT_BACKEND")),
+		AuthPerIPPerMinute:     getEnvOrDefaultInt("RATE_LIMIT_AUTH_PER_IP_PER_MINUTE", 30),
+		LoginPerEmailPerMinute: getEnvOrDefaultInt("RATE_LIMIT_LOGIN_PER_EMAIL_PER_MINUTE", 10),
+		SignupPerIPPerHour:     getEnvOrDefaultInt("RATE_LIMIT_SIGNUP_PER_IP_PER_HOUR", 20),
+		EmailsPerHour:          getEnvOrDefaultInt("RATE_LIMIT_EMAILS_PER_HOUR", 3),
+		LockoutThreshold:       getEnvOrDefaultInt("LOGIN_LOCKOUT_THRESHOLD", 5),
+		LockoutDuration:        getEnvOrDefaultDuration("LOGIN_LOCKOUT_DURATION", time.Minute*15),
+		LockoutMaxDuration:     getEnvOrDefaultDuration("LOGIN_LOCKOUT_MAX_DURATION", time.Hour*24),
+		AccountUnlockURL:       getEnvOrDefaultString("ACCOUNT_UNLOCK_URL", "http://"+serverConf.Hostname+":"+strconv.Itoa(serverConf.Port)+"/auth/unlock"),
+	}
+
+	oidcConf := &OIDCConfig{
+		Providers:   parseOIDCProviders(getEnvOrDefaultString("OIDC_PROVIDERS", "")),
+		RedirectURL: getEnvOrDefaultString("OIDC_REDIRECT_URL", ""),
+		StateTTL:    getEnvOrDefaultDuration("OIDC_STATE_TTL", time.Minute*10),
+	}
+
+	accountDeletionConf := &AccountDeletionConfig{
+		GracePeriod:   getEnvOrDefaultDuration("ACCOUNT_DELETION_GRACE_PERIOD", time.Hour*24*30),
+		PurgeInterval: getEnvOrDefaultDuration("ACCOUNT_DELETION_PURGE_INTERVAL", time.Hour),
+		CancelURL:     getEnvOrDefaultString("ACCOUNT_DELETION_CANCEL_URL", "http://"+serverConf.Hostname+":"+strconv.Itoa(serverConf.Port)+"/auth/deletion/cancel"),
+	}
+
+	orchestratorConf := &OrchestratorConfig{
+		Backend:        getOrchestratorBackendType(os.Getenv("ORCHESTRATOR_BACKEND"), serverConf.Environment),
+		LocalCommand:   os.Getenv("ORCHESTRATOR_LOCAL_COMMAND"),
+		LocalHost:      getEnvOrDefaultString("ORCHESTRATOR_LOCAL_HOST", "127.0.0.1"),
+		LocalPortStart: getEnvOrDefaultInt("ORCHESTRATOR_LOCAL_PORT_START", 7800),
+		LocalPortCount: getEnvOrDefaultInt("ORCHESTRATOR_LOCAL_PORT_COUNT", 100),
+	}
+
+	reconcilerConf := &ReconcilerConfig{
+		Interval:          getEnvOrDefaultDuration("WORLD_RECONCILE_INTERVAL", time.Minute),
+		OrphanGracePeriod: getEnvOrDefaultDuration("WORLD_RECONCILE_ORPHAN_GRACE_PERIOD", time.Minute*2),
+	}
+
+	serverRegistryConf := &ServerRegistryConfig{
+		HeartbeatTimeout:       getEnvOrDefaultDuration("WORLD_SERVER_HEARTBEAT_TIMEOUT", time.Second*90),
+		HeartbeatCheckInterval: getEnvOrDefaultDuration("WORLD_SERVER_HEARTBEAT_CHECK_INTERVAL", time.Second*30),
+	}
+
+	rolloutConf := &RolloutConfig{
+		BatchSize:     getEnvOrDefaultInt("ROLLOUT_BATCH_SIZE", 10),
+		BatchPause:    getEnvOrDefaultDuration("ROLLOUT_BATCH_PAUSE", time.Minute),
+		HealthTimeout: getEnvOrDefaultDuration("ROLLOUT_HEALTH_TIMEOUT", time.Minute*5),
+		CheckInterval: getEnvOrDefaultDuration("ROLLOUT_CHECK_INTERVAL", time.Second*10),
+		CanaryZones:   strings.Fields(strings.ReplaceAll(os.Getenv("ROLLOUT_CANARY_ZONES"), ",", " ")),
+	}
+
+	datacenter := getEnvOrDefaultString("ZONE_TIER_DATACENTER", "dc1")
+	zoneTiersConf := &ZoneTiersConfig{
+		Default: getEnvOrDefaultString("ZONE_TIER_DEFAULT", "medium"),
+		Tiers: map[string]ZoneTier{
+			"small":  getZoneTier("small", ZoneTier{CPU: 450, MemoryMB: 256, MemoryMaxMB: 300, Datacenter: datacenter, Slots: 1}),
+			"medium": getZoneTier("medium", ZoneTier{CPU: 900, MemoryMB: 470, MemoryMaxMB: 512, Datacenter: datacenter, Slots: 1}),
+			"large":  getZoneTier("large", ZoneTier{CPU: 1800, MemoryMB: 1024, MemoryMaxMB: 1200, Datacenter: datacenter, Slots: 2}),
+		},
+	}
+
 	commaSeparatedAllowedOrigins := getEnvOrDefaultString("CORS_ALLOWED_ORIGINS", "*")
 
 	return &Config{
@@ -199,8 +451,19 @@ func CreateConfig() *Config {
 		DB:                           dbc,
 		Assets:                       assetsConf,
 		Stripe:                       stripeConf,
+		Payouts:                      payoutsConf,
 		Github:                       githubConf,
-		SessionAccessTokenSecretKey:  os.Getenv("SESSION_ACCESS_TOKEN_SECRET_KEY"),
+		ServiceClients:               serviceClientsConf,
+		RateLimit:                    rateLimitConf,
+		OIDC:                         oidcConf,
+		AccountDeletion:              accountDeletionConf,
+		Orchestrator:                 orchestratorConf,
+		Reconciler:                   reconcilerConf,
+		ServerRegistry:               serverRegistryConf,
+		Rollout:                      rolloutConf,
+		ZoneTiers:                    zoneTiersConf,
+		SessionSigningKeysDir:        os.Getenv("SESSION_SIGNING_KEYS_DIR"),
+		SessionSigningKeyId:          os.Getenv("SESSION_SIGNING_KEY_ID"),
 		SessionRefreshTokenSecretKey: os.Getenv("SESSION_REFRESH_TOKEN_SECRET_KEY"),
 		SessionAccessTokenDuration:   getEnvOrDefaultDuration("SESSION_ACCESS_TOKEN_DURATION", time.Hour*24),
 		SessionRefreshTokenDuration:  getEnvOrDefaultDuration("SESSION_REFRESH_TOKEN_DURATION", time.Hour*24*30),
@@ -210,6 +473,9 @@ func CreateConfig() *Config {
 		EmailLogoURL:                 getEnvOrDefaultString("EMAIL_LOGO_URL", "https://avatars.githubusercontent.com/u/231922724?s=400&u=5f4eb45fb6dc7cfa42333bfe1dc64a376122e3d0&v=4"),
 		SupportEmail:                 getEnvOrDefaultString("SUPPORT_EMAIL", "atusgames.official@gmail.com"),
 		ServerFixedToken:             os.Getenv("SERVER_FIXED_TOKEN"),
+		ServerFixedTokenEnabled:      getEnvOrDefaultBool("SERVER_FIXED_TOKEN_ENABLED", false),
+		InternalAuthSecret:           os.Getenv("INTERNAL_AUTH_SECRET"),
+		InternalAuthMaxSkew:          getEnvOrDefaultDuration("INTERNAL_AUTH_MAX_SKEW", time.Minute*5),
 		NomadAddr:                    os.Getenv("NOMAD_ADDR"),
 		NomadToken:                   os.Getenv("NOMAD_TOKEN"),
 		NomadCertPath:                os.Getenv("NOMAD_CERT_PATH"),
@@ -273,3 +539,86 @@ func getEnvironmentType(env string) EnvironmentType {
 		return Development
 	}
 }
+
+func getPayoutProviderType(provider string) PayoutProviderType {
+	switch provider {
+	case "fake":
+		return FakePayouts
+	default:
+		return StripeConnectPayouts
+	}
+}
+
+func getServiceClientsMode(mode string) ServiceClientsMode {
+	switch mode {
+	case "http":
+		return HTTPClients
+	default:
+		return InProcessClients
+	}
+}
+
+// getZoneTier reads the ZONE_TIER_<NAME>_* overrides of a tier.
+func getZoneTier(name string, defaults ZoneTier) ZoneTier {
+	prefix := "ZONE_TIER_" + strings.ToUpper(name) + "_"
+	return ZoneTier{
+		Name:        name,
+		CPU:         getEnvOrDefaultInt(prefix+"CPU", defaults.CPU),
+		MemoryMB:    getEnvOrDefaultInt(prefix+"MEMORY", defaults.MemoryMB),
+		MemoryMaxMB: getEnvOrDefaultInt(prefix+"MEMORY_MAX", defaults.MemoryMaxMB),
+		Datacenter:  getEnvOrDefaultString(prefix+"DATACENTER", defaults.Datacenter),
+		Slots:       getEnvOrDefaultInt(prefix+"SLOTS", defaults.Slots),
+	}
+}
+
+// getOrchestratorBackendType defaults to Nomad in production and to the in-memory backend elsewhere.
+func getOrchestratorBackendType(backend string, env EnvironmentType) OrchestratorBackendType {
+	switch backend {
+	case "nomad":
+		return NomadOrchestrator
+	case "local":
+		return LocalOrchestrator
+	case "memory":
+		return MemoryOrchestrator
+	default:
+		if env == Production {
+			return NomadOrchestrator
+		}
+		return MemoryOrchestrator
+	}
+}
+
+func getRateLimitBackendType(backend string) RateLimitBackendType {
+	switch backend {
+	case "postgres":
+		return PostgresRateLimit
+	default:
+		return MemoryRateLimit
+	}
+}
+
+// parseOIDCProviders reads the OIDC_<NAME>_* variables of every provider listed in OIDC_PROVIDERS.
+func parseOIDCProviders(commaSeparatedNames string) []OIDCProviderConfig {
+	providers := []OIDCProviderConfig{}
+	for _, name := range strings.Split(commaSeparatedNames, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		prefix := "OIDC_" + strings.ToUpper(name) + "_"
+		provider := OIDCProviderConfig{
+			Name:         name,
+			Issuer:       os.Getenv(prefix + "ISSUER"),
+			ClientID:     os.Getenv(prefix + "CLIENT_ID"),
+			ClientSecret: os.Getenv(prefix + "CLIENT_SECRET"),
+			Scopes:       strings.Fields(getEnvOrDefaultString(prefix+"SCOPES", "openid email profile")),
+		}
+		if provider.Issuer == "" || provider.ClientID == "" {
+			log.Printf("Warning: OIDC provider %s is missing %sISSUER or %sCLIENT_ID, skipping it", name, prefix, prefix)
+			continue
+		}
+		providers = append(providers, provider)
+	}
+	return providers
+}
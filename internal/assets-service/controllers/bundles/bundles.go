@@ -0,0 +1,336 @@
+package bundles
+
+import (
+	"net/http"
+
+	"github.com/FeedTheRealm-org/core-service/config"
+	"github.com/FeedTheRealm-org/core-service/internal/assets-service/dtos"
+	assets_errors "github.com/FeedTheRealm-org/core-service/internal/assets-service/errors"
+	"github.com/FeedTheRealm-org/core-service/internal/assets-service/models"
+	"github.com/FeedTheRealm-org/core-service/internal/assets-service/services/bundles"
+	"github.com/FeedTheRealm-org/core-service/internal/assets-service/services/promotions"
+	"github.com/FeedTheRealm-org/core-service/internal/common_handlers"
+	"github.com/FeedTheRealm-org/core-service/internal/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type bundlesController struct {
+	conf              *config.Config
+	bundlesService    bundles.BundlesService
+	promotionsService promotions.PromotionsService
+}
+
+// NewBundlesController creates a new instance of BundlesController.
+func NewBundlesController(conf *config.Config, bundlesService bundles.BundlesService, promotionsService promotions.PromotionsService) BundlesController {
+	return &bundlesController{
+		conf:              conf,
+		bundlesService:    bundlesService,
+		promotionsService: promotionsService,
+	}
+}
+
+// CreateBundle godoc
+// @Summary      Create cosmetic bundle
+// @Description  Creates a bundle selling several cosmetics of a world for a single gem price (requires world ownership).
+// @Tags         assets-service
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        bundle body dtos.CreateBundleRequest true "Bundle data"
+// @Success      201  {object}  dtos.BundleResponse
+// @Failure      400  {object} dtos.ErrorResponse
+// @Failure      401  {object} dtos.ErrorResponse
+// @Failure      404  {object} dtos.ErrorResponse
+// @Router       /assets/bundles [post]
+func (bc *bundlesController) CreateBundle(c *gin.Context) {
+	userId, err := common_handlers.GetUserIDFromSession(c)
+	if err != nil {
+		_ = c.Error(errors.NewUnauthorizedError(err.Error()))
+		return
+	}
+
+	req := &dtos.CreateBundleRequest{}
+	if err := c.ShouldBindJSON(req); err != nil {
+		_ = c.Error(errors.NewBadRequestError(err.Error()))
+		return
+	}
+
+	if err := bc.checkWorldAccess(c, *req.WorldId, userId); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	bundle, err := bc.bundlesService.CreateBundle(*req.WorldId, req.Name, req.Price, req.CosmeticIds, userId)
+	if err != nil {
+		switch err.(type) {
+		case *assets_errors.InvalidPrice, *assets_errors.InvalidBundle:
+			_ = c.Error(errors.NewBadRequestError(err.Error()))
+		case *assets_errors.CosmeticNotFound:
+			_ = c.Error(errors.NewNotFoundError("cosmetic not found"))
+		default:
+			_ = c.Error(err)
+		}
+		return
+	}
+
+	res, err := bc.toBundleResponse(bundle)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	common_handlers.HandleSuccessResponse(c, http.StatusCreated, res)
+}
+
+// GetBundleById godoc
+// @Summary      Get bundle by ID
+// @Description  Retrieves a bundle with its cosmetics and current price.
+// @Tags         assets-service
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id path string true "Bundle UUID"
+// @Success      200  {object}  dtos.BundleResponse
+// @Failure      400  {object} dtos.ErrorResponse
+// @Failure      401  {object} dtos.ErrorResponse
+// @Failure      404  {object} dtos.ErrorResponse
+// @Router       /assets/bundles/{id} [get]
+func (bc *bundlesController) GetBundleById(c *gin.Context) {
+	_, err := common_handlers.GetUserIDFromSession(c)
+	if err != nil {
+		_ = c.Error(errors.NewUnauthorizedError(err.Error()))
+		return
+	}
+
+	bundle, ok := bc.getBundleFromParam(c, "id")
+	if !ok {
+		return
+	}
+
+	res, err := bc.toBundleResponse(bundle)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	common_handlers.HandleSuccessResponse(c, http.StatusOK, res)
+}
+
+// GetBundlesListByWorld godoc
+// @Summary      Get bundles by world
+// @Description  Retrieves the bundles sold in a world with their current price.
+// @Tags         assets-service
+// @Security     BearerAuth
+// @Produce      json
+// @Param        world_id path string true "World UUID"
+// @Success      200  {object}  dtos.BundlesListResponse
+// @Failure      400  {object} dtos.ErrorResponse
+// @Failure      401  {object} dtos.ErrorResponse
+// @Router       /assets/bundles/worlds/{world_id} [get]
+func (bc *bundlesController) GetBundlesListByWorld(c *gin.Context) {
+	_, err := common_handlers.GetUserIDFromSession(c)
+	if err != nil {
+		_ = c.Error(errors.NewUnauthorizedError(err.Error()))
+		return
+	}
+
+	worldId, err := uuid.Parse(c.Param("world_id"))
+	if err != nil {
+		_ = c.Error(errors.NewBadRequestError("invalid world_id: " + err.Error()))
+		return
+	}
+
+	bundlesList, err := bc.bundlesService.GetBundlesListByWorld(worldId)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	res := &dtos.BundlesListResponse{
+		Bundles: make([]dtos.BundleResponse, len(bundlesList)),
+	}
+	for idx, bundle := range bundlesList {
+		bundleRes, err := bc.toBundleResponse(bundle)
+		if err != nil {
+			_ = c.Error(err)
+			return
+		}
+		res.Bundles[idx] = *bundleRes
+	}
+
+	common_handlers.HandleSuccessResponse(c, http.StatusOK, res)
+}
+
+// DeleteBundle godoc
+// @Summary      Delete bundle
+// @Description  Deletes a bundle, cosmetics already granted through it are kept (requires world ownership).
+// @Tags         assets-service
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id path string true "Bundle UUID"
+// @Success      204
+// @Failure      400  {object} dtos.ErrorResponse
+// @Failure      401  {object} dtos.ErrorResponse
+// @Failure      404  {object} dtos.ErrorResponse
+// @Router       /assets/bundles/{id} [delete]
+func (bc *bundlesController) DeleteBundle(c *gin.Context) {
+	userId, err := common_handlers.GetUserIDFromSession(c)
+	if err != nil {
+		_ = c.Error(errors.NewUnauthorizedError(err.Error()))
+		return
+	}
+
+	bundle, ok := bc.getBundleFromParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := bc.checkWorldAccess(c, bundle.WorldID, userId); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	if err := bc.bundlesService.DeleteBundle(bundle.Id); err != nil {
+		if _, ok := err.(*assets_errors.BundleNotFound); ok {
+			_ = c.Error(errors.NewNotFoundError("bundle not found"))
+			return
+		}
+		_ = c.Error(err)
+		return
+	}
+
+	common_handlers.HandleBodilessResponse(c, http.StatusNoContent)
+}
+
+// GetBundleByIdInternal godoc
+// @Summary      Get bundle by ID (Internal)
+// @Description  Retrieves a bundle with its current price and cosmetics. Intended for internal service communication.
+// @Tags         assets-service-internal
+// @Security     BearerAuth
+// @Produce      json
+// @Param        bundle_id path string true "Bundle UUID"
+// @Success      200  {object}  dtos.InternalBundleResponse
+// @Failure      400  {object} dtos.ErrorResponse
+// @Failure      404  {object} dtos.ErrorResponse
+// @Router       /assets/internal/bundles/{bundle_id} [get]
+func (bc *bundlesController) GetBundleByIdInternal(c *gin.Context) {
+	bundle, ok := bc.getBundleFromParam(c, "bundle_id")
+	if !ok {
+		return
+	}
+
+	price, err := bc.promotionsService.ResolveBundlePrice(bundle)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	res := &dtos.InternalBundleResponse{
+		BundleId:    bundle.Id,
+		BundlePrice: price.Price,
+		BasePrice:   price.BasePrice,
+		PromotionId: price.PromotionID,
+		CreatedBy:   bundle.CreatedBy,
+		WorldId:     bundle.WorldID,
+		CosmeticIds: bundle.CosmeticIds(),
+	}
+	common_handlers.HandleSuccessResponse(c, http.StatusOK, res)
+}
+
+// GrantBundleForUserInternal godoc
+// @Summary      Grant bundle to user (Internal)
+// @Description  Grants every cosmetic of the bundle the user does not own yet in a single transaction. Intended for internal service communication.
+// @Tags         assets-service-internal
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        user_id path string true "User UUID"
+// @Param        request body dtos.InternalGrantBundleForUserRequest true "Bundle Details"
+// @Success      201  {object}  dtos.InternalGrantBundleForUserResponse
+// @Failure      400  {object} dtos.ErrorResponse
+// @Failure      404  {object} dtos.ErrorResponse
+// @Failure      409  {object} dtos.ErrorResponse
+// @Router       /assets/internal/users/{user_id}/bundles [post]
+func (bc *bundlesController) GrantBundleForUserInternal(c *gin.Context) {
+	userId, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		_ = c.Error(errors.NewBadRequestError("invalid user_id: " + err.Error()))
+		return
+	}
+
+	req := &dtos.InternalGrantBundleForUserRequest{}
+	if err := c.ShouldBindJSON(req); err != nil {
+		_ = c.Error(errors.NewBadRequestError(err.Error()))
+		return
+	}
+
+	granted, err := bc.bundlesService.GrantBundleForUserInternal(userId, req.BundleId)
+	if err != nil {
+		switch err.(type) {
+		case *assets_errors.BundleNotFound:
+			_ = c.Error(errors.NewNotFoundError("bundle not found"))
+		case *assets_errors.BundleAlreadyOwned:
+			_ = c.Error(errors.NewConflictError("every cosmetic of the bundle was already purchased by the user"))
+		default:
+			_ = c.Error(err)
+		}
+		return
+	}
+
+	res := &dtos.InternalGrantBundleForUserResponse{
+		UserId:             userId,
+		BundleId:           req.BundleId,
+		GrantedCosmeticIds: granted,
+	}
+	common_handlers.HandleSuccessResponse(c, http.StatusCreated, res)
+}
+
+/* --- UTILS --- */
+
+func (bc *bundlesController) getBundleFromParam(c *gin.Context, param string) (*models.CosmeticBundle, bool) {
+	bundleId, err := uuid.Parse(c.Param(param))
+	if err != nil {
+		_ = c.Error(errors.NewBadRequestError("invalid " + param + ": " + err.Error()))
+		return nil, false
+	}
+
+	bundle, err := bc.bundlesService.GetBundleById(bundleId)
+	if err != nil {
+		if _, ok := err.(*assets_errors.BundleNotFound); ok {
+			_ = c.Error(errors.NewNotFoundError("bundle not found"))
+			return nil, false
+		}
+		_ = c.Error(err)
+		return nil, false
+	}
+
+	return bundle, true
+}
+
+// checkWorldAccess lets admins manage any world, default cosmetics (nil world) are admin only.
+func (bc *bundlesController) checkWorldAccess(c *gin.Context, worldId uuid.UUID, userId uuid.UUID) error {
+	if err := common_handlers.IsAdminSession(c); err == nil {
+		return nil
+	}
+	if worldId == uuid.Nil {
+		return errors.NewUnauthorizedError("invalid world_id")
+	}
+	return common_handlers.CheckWorldOwnership(c, bc.conf.Server.Port, worldId, userId)
+}
+
+func (bc *bundlesController) toBundleResponse(bundle *models.CosmeticBundle) (*dtos.BundleResponse, error) {
+	price, err := bc.promotionsService.ResolveBundlePrice(bundle)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dtos.BundleResponse{
+		BundleId:    bundle.Id,
+		WorldId:     bundle.WorldID,
+		Name:        bundle.Name,
+		BasePrice:   price.BasePrice,
+		Price:       price.Price,
+		PromotionId: price.PromotionID,
+		CosmeticIds: bundle.CosmeticIds(),
+		CreatedBy:   bundle.CreatedBy,
+		CreatedAt:   bundle.CreatedAt,
+	}, nil
+}
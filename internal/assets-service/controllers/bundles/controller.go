@@ -0,0 +1,24 @@
+package bundles
+
+import "github.com/gin-gonic/gin"
+
+// BundlesController defines the interface for cosmetic bundle HTTP operations.
+type BundlesController interface {
+	// CreateBundle creates a bundle of cosmetics from a world.
+	CreateBundle(c *gin.Context)
+
+	// GetBundleById retrieves a bundle by its ID.
+	GetBundleById(c *gin.Context)
+
+	// GetBundlesListByWorld retrieves the bundles sold in a world.
+	GetBundlesListByWorld(c *gin.Context)
+
+	// DeleteBundle deletes a bundle by its ID.
+	DeleteBundle(c *gin.Context)
+
+	// GetBundleByIdInternal retrieves a bundle with its current price for internal service communication.
+	GetBundleByIdInternal(c *gin.Context)
+
+	// GrantBundleForUserInternal grants the cosmetics of a bundle to a user for internal service communication.
+	GrantBundleForUserInternal(c *gin.Context)
+}
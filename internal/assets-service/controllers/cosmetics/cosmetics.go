@@ -12,22 +12,26 @@ import (
 	"github.com/FeedTheRealm-org/core-service/internal/assets-service/dtos"
 	assets_errors "github.com/FeedTheRealm-org/core-service/internal/assets-service/errors"
 	"github.com/FeedTheRealm-org/core-service/internal/assets-service/services/cosmetics"
+	"github.com/FeedTheRealm-org/core-service/internal/assets-service/services/promotions"
 	"github.com/FeedTheRealm-org/core-service/internal/common_handlers"
 	"github.com/FeedTheRealm-org/core-service/internal/errors"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/permissions"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
 type cosmeticsController struct {
-	conf             *config.Config
-	cosmeticsService cosmetics.CosmeticsService
+	conf              *config.Config
+	cosmeticsService  cosmetics.CosmeticsService
+	promotionsService promotions.PromotionsService
 }
 
 // NewCosmeticsController creates a new instance of CosmeticsController.
-func NewCosmeticsController(conf *config.Config, cosmeticsService cosmetics.CosmeticsService) CosmeticsController {
+func NewCosmeticsController(conf *config.Config, cosmeticsService cosmetics.CosmeticsService, promotionsService promotions.PromotionsService) CosmeticsController {
 	return &cosmeticsController{
-		conf:             conf,
-		cosmeticsService: cosmeticsService,
+		conf:              conf,
+		cosmeticsService:  cosmeticsService,
+		promotionsService: promotionsService,
 	}
 }
 
@@ -547,7 +551,7 @@ func (cc *cosmeticsController) UploadCosmeticByID(c *gin.Context) {
 
 // DeleteCosmetic godoc
 // @Summary      Delete cosmetic
-// @Description  Delete a specific cosmetic by ID (requires ownership)
+// @Description  Delete a specific cosmetic by ID. Requires assets.content.delete.
 // @Tags         assets-service
 // @Security     BearerAuth
 // @Produce      json
@@ -580,7 +584,7 @@ func (cc *cosmeticsController) DeleteCosmetic(c *gin.Context) {
 		return
 	}
 
-	if cosmetic.CreatedBy != userId {
+	if cosmetic.CreatedBy != userId && common_handlers.HasPermission(c, permissions.ASSETS_CONTENT_DELETE) != nil {
 		_ = c.Error(errors.NewUnauthorizedError("user is not authorized to delete this cosmetic"))
 		return
 	}
@@ -636,12 +640,13 @@ func (cc *cosmeticsController) AddCategory(c *gin.Context) {
 		CategoryId:   category.Id,
 		CategoryName: category.Name,
 	}
+	common_handlers.SetAuditTarget(c, category.Id.String())
 	common_handlers.HandleSuccessResponse(c, http.StatusCreated, res)
 }
 
 // GetCosmeticByIdInternal godoc
 // @Summary      Get cosmetic by ID (Internal)
-// @Description  Retrieves a single cosmetic item by its ID. Intended for internal service communication.
+// @Description  Retrieves a single cosmetic item by its ID with any active promotion applied to its price. Intended for internal service communication.
 // @Tags         assets-service-internal
 // @Security     BearerAuth
 // @Accept       json
@@ -669,10 +674,19 @@ func (cc *cosmeticsController) GetCosmeticByIdInternal(c *gin.Context) {
 		return
 	}
 
+	price, err := cc.promotionsService.ResolveCosmeticPrice(cosmetic)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
 	res := &dtos.InternalCosmeticResponse{
 		CosmeticId:    cosmetic.Id,
-		CosmeticPrice: cosmetic.Price,
+		CosmeticPrice: price.Price,
+		BasePrice:     price.BasePrice,
+		PromotionId:   price.PromotionID,
 		CreatedBy:     cosmetic.CreatedBy,
+		WorldId:       cosmetic.WorldID,
 	}
 
 	common_handlers.HandleSuccessResponse(c, http.StatusOK, res)
@@ -704,7 +718,7 @@ func (cc *cosmeticsController) PurshaseCosmeticForUserInternal(c *gin.Context) {
 		return
 	}
 
-	err = cc.cosmeticsService.PurchaseCosmeticForUserInternal(userId, req.CosmeticId)
+	err = cc.cosmeticsService.PurchaseCosmeticForUserInternal(userId, req.CosmeticId, req.GiftedBy)
 	if err != nil {
 		if _, ok := err.(*assets_errors.CosmeticsWasPurchasedBefore); ok {
 			_ = c.Error(errors.NewConflictError("cosmetic was already purchased by the user"))
@@ -717,6 +731,7 @@ func (cc *cosmeticsController) PurshaseCosmeticForUserInternal(c *gin.Context) {
 	res := &dtos.InternalPurchaseCosmeticForUserResponse{
 		UserId:     userId,
 		CosmeticId: req.CosmeticId,
+		GiftedBy:   req.GiftedBy,
 	}
 	common_handlers.HandleSuccessResponse(c, http.StatusCreated, res)
 }
@@ -10,6 +10,7 @@ import (
 	"github.com/FeedTheRealm-org/core-service/internal/assets-service/services/items"
 	"github.com/FeedTheRealm-org/core-service/internal/common_handlers"
 	internalErrors "github.com/FeedTheRealm-org/core-service/internal/errors"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/permissions"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
@@ -193,7 +194,7 @@ func (ic *itemController) UploadItems(c *gin.Context) {
 
 // DeleteItem godoc
 // @Summary      Delete item
-// @Description  Delete a specific item by ID (requires ownership)
+// @Description  Delete a specific item by ID (requires ownership or assets.content.delete)
 // @Tags         assets-service
 // @Security     BearerAuth
 // @Produce      json
@@ -226,7 +227,7 @@ func (ic *itemController) DeleteItem(c *gin.Context) {
 		return
 	}
 
-	if item.CreatedBy != userId {
+	if item.CreatedBy != userId && common_handlers.HasPermission(c, permissions.ASSETS_CONTENT_DELETE) != nil {
 		_ = c.Error(internalErrors.NewUnauthorizedError("user is not authorized to delete this item"))
 		return
 	}
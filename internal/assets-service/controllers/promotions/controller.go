@@ -0,0 +1,15 @@
+package promotions
+
+import "github.com/gin-gonic/gin"
+
+// PromotionsController defines the interface for cosmetic promotion HTTP operations.
+type PromotionsController interface {
+	// CreatePromotion creates a time-limited discount for a world, cosmetic or bundle.
+	CreatePromotion(c *gin.Context)
+
+	// GetPromotionsListByWorld retrieves the promotions of a world.
+	GetPromotionsListByWorld(c *gin.Context)
+
+	// DeletePromotion deletes a promotion by its ID.
+	DeletePromotion(c *gin.Context)
+}
@@ -0,0 +1,211 @@
+package promotions
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/FeedTheRealm-org/core-service/config"
+	"github.com/FeedTheRealm-org/core-service/internal/assets-service/dtos"
+	assets_errors "github.com/FeedTheRealm-org/core-service/internal/assets-service/errors"
+	"github.com/FeedTheRealm-org/core-service/internal/assets-service/models"
+	"github.com/FeedTheRealm-org/core-service/internal/assets-service/services/promotions"
+	"github.com/FeedTheRealm-org/core-service/internal/common_handlers"
+	"github.com/FeedTheRealm-org/core-service/internal/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type promotionsController struct {
+	conf              *config.Config
+	promotionsService promotions.PromotionsService
+}
+
+// NewPromotionsController creates a new instance of PromotionsController.
+func NewPromotionsController(conf *config.Config, promotionsService promotions.PromotionsService) PromotionsController {
+	return &promotionsController{
+		conf:              conf,
+		promotionsService: promotionsService,
+	}
+}
+
+// CreatePromotion godoc
+// @Summary      Create promotion
+// @Description  Creates a percentage discount for a date window on a cosmetic, a bundle, or the whole world when neither is set (requires world ownership).
+// @Tags         assets-service
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        promotion body dtos.CreatePromotionRequest true "Promotion data"
+// @Success      201  {object}  dtos.PromotionResponse
+// @Failure      400  {object} dtos.ErrorResponse
+// @Failure      401  {object} dtos.ErrorResponse
+// @Failure      404  {object} dtos.ErrorResponse
+// @Router       /assets/promotions [post]
+func (pc *promotionsController) CreatePromotion(c *gin.Context) {
+	userId, err := common_handlers.GetUserIDFromSession(c)
+	if err != nil {
+		_ = c.Error(errors.NewUnauthorizedError(err.Error()))
+		return
+	}
+
+	req := &dtos.CreatePromotionRequest{}
+	if err := c.ShouldBindJSON(req); err != nil {
+		_ = c.Error(errors.NewBadRequestError(err.Error()))
+		return
+	}
+
+	if err := pc.checkWorldAccess(c, *req.WorldId, userId); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	promotion, err := pc.promotionsService.CreatePromotion(&models.CosmeticPromotion{
+		WorldID:    *req.WorldId,
+		CosmeticID: req.CosmeticId,
+		BundleID:   req.BundleId,
+		PercentOff: req.PercentOff,
+		StartsAt:   req.StartsAt,
+		EndsAt:     req.EndsAt,
+		CreatedBy:  userId,
+	})
+	if err != nil {
+		switch err.(type) {
+		case *assets_errors.InvalidPromotion:
+			_ = c.Error(errors.NewBadRequestError(err.Error()))
+		case *assets_errors.CosmeticNotFound:
+			_ = c.Error(errors.NewNotFoundError("cosmetic not found"))
+		case *assets_errors.BundleNotFound:
+			_ = c.Error(errors.NewNotFoundError("bundle not found"))
+		default:
+			_ = c.Error(err)
+		}
+		return
+	}
+
+	common_handlers.HandleSuccessResponse(c, http.StatusCreated, toPromotionResponse(promotion))
+}
+
+// GetPromotionsListByWorld godoc
+// @Summary      Get promotions by world
+// @Description  Retrieves the promotions of a world, only the running ones when active is true.
+// @Tags         assets-service
+// @Security     BearerAuth
+// @Produce      json
+// @Param        world_id path string true "World UUID"
+// @Param        active query bool false "Only running promotions" default(false)
+// @Success      200  {object}  dtos.PromotionsListResponse
+// @Failure      400  {object} dtos.ErrorResponse
+// @Failure      401  {object} dtos.ErrorResponse
+// @Router       /assets/promotions/worlds/{world_id} [get]
+func (pc *promotionsController) GetPromotionsListByWorld(c *gin.Context) {
+	_, err := common_handlers.GetUserIDFromSession(c)
+	if err != nil {
+		_ = c.Error(errors.NewUnauthorizedError(err.Error()))
+		return
+	}
+
+	worldId, err := uuid.Parse(c.Param("world_id"))
+	if err != nil {
+		_ = c.Error(errors.NewBadRequestError("invalid world_id: " + err.Error()))
+		return
+	}
+
+	activeOnly, err := strconv.ParseBool(c.DefaultQuery("active", "false"))
+	if err != nil {
+		_ = c.Error(errors.NewBadRequestError("active must be a boolean"))
+		return
+	}
+
+	promotionsList, err := pc.promotionsService.GetPromotionsListByWorld(worldId, activeOnly)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	res := &dtos.PromotionsListResponse{
+		Promotions: make([]dtos.PromotionResponse, len(promotionsList)),
+	}
+	for idx, promotion := range promotionsList {
+		res.Promotions[idx] = *toPromotionResponse(promotion)
+	}
+
+	common_handlers.HandleSuccessResponse(c, http.StatusOK, res)
+}
+
+// DeletePromotion godoc
+// @Summary      Delete promotion
+// @Description  Deletes a promotion, prices go back to their base value right away (requires world ownership).
+// @Tags         assets-service
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id path string true "Promotion UUID"
+// @Success      204
+// @Failure      400  {object} dtos.ErrorResponse
+// @Failure      401  {object} dtos.ErrorResponse
+// @Failure      404  {object} dtos.ErrorResponse
+// @Router       /assets/promotions/{id} [delete]
+func (pc *promotionsController) DeletePromotion(c *gin.Context) {
+	userId, err := common_handlers.GetUserIDFromSession(c)
+	if err != nil {
+		_ = c.Error(errors.NewUnauthorizedError(err.Error()))
+		return
+	}
+
+	promotionId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		_ = c.Error(errors.NewBadRequestError("invalid promotion_id: " + err.Error()))
+		return
+	}
+
+	promotion, err := pc.promotionsService.GetPromotionById(promotionId)
+	if err != nil {
+		if _, ok := err.(*assets_errors.PromotionNotFound); ok {
+			_ = c.Error(errors.NewNotFoundError("promotion not found"))
+			return
+		}
+		_ = c.Error(err)
+		return
+	}
+
+	if err := pc.checkWorldAccess(c, promotion.WorldID, userId); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	if err := pc.promotionsService.DeletePromotion(promotionId); err != nil {
+		if _, ok := err.(*assets_errors.PromotionNotFound); ok {
+			_ = c.Error(errors.NewNotFoundError("promotion not found"))
+			return
+		}
+		_ = c.Error(err)
+		return
+	}
+
+	common_handlers.HandleBodilessResponse(c, http.StatusNoContent)
+}
+
+/* --- UTILS --- */
+
+// checkWorldAccess lets admins manage any world, default cosmetics (nil world) are admin only.
+func (pc *promotionsController) checkWorldAccess(c *gin.Context, worldId uuid.UUID, userId uuid.UUID) error {
+	if err := common_handlers.IsAdminSession(c); err == nil {
+		return nil
+	}
+	if worldId == uuid.Nil {
+		return errors.NewUnauthorizedError("invalid world_id")
+	}
+	return common_handlers.CheckWorldOwnership(c, pc.conf.Server.Port, worldId, userId)
+}
+
+func toPromotionResponse(promotion *models.CosmeticPromotion) *dtos.PromotionResponse {
+	return &dtos.PromotionResponse{
+		PromotionId: promotion.Id,
+		WorldId:     promotion.WorldID,
+		CosmeticId:  promotion.CosmeticID,
+		BundleId:    promotion.BundleID,
+		PercentOff:  promotion.PercentOff,
+		StartsAt:    promotion.StartsAt,
+		EndsAt:      promotion.EndsAt,
+		CreatedBy:   promotion.CreatedBy,
+	}
+}
@@ -0,0 +1,52 @@
+package dtos
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CreateBundleRequest struct {
+	WorldId     *uuid.UUID  `json:"world_id" binding:"required"`
+	Name        string      `json:"name" binding:"required"`
+	Price       int64       `json:"price" binding:"required"`
+	CosmeticIds []uuid.UUID `json:"cosmetic_ids" binding:"required"`
+}
+
+// BundleResponse carries the current price of the bundle, base_price is the price before promotions.
+type BundleResponse struct {
+	BundleId    uuid.UUID   `json:"bundle_id"`
+	WorldId     uuid.UUID   `json:"world_id"`
+	Name        string      `json:"name"`
+	BasePrice   int64       `json:"base_price"`
+	Price       int64       `json:"price"`
+	PromotionId *uuid.UUID  `json:"promotion_id,omitempty"`
+	CosmeticIds []uuid.UUID `json:"cosmetic_ids"`
+	CreatedBy   uuid.UUID   `json:"created_by"`
+	CreatedAt   time.Time   `json:"created_at"`
+}
+
+// BundlesListResponse returns a list of bundles.
+type BundlesListResponse struct {
+	Bundles []BundleResponse `json:"bundles"`
+}
+
+type InternalBundleResponse struct {
+	BundleId    uuid.UUID   `json:"bundle_id"`
+	BundlePrice int64       `json:"bundle_price"`
+	BasePrice   int64       `json:"base_price"`
+	PromotionId *uuid.UUID  `json:"promotion_id,omitempty"`
+	CreatedBy   uuid.UUID   `json:"created_by"`
+	WorldId     uuid.UUID   `json:"world_id"`
+	CosmeticIds []uuid.UUID `json:"cosmetic_ids"`
+}
+
+type InternalGrantBundleForUserRequest struct {
+	BundleId uuid.UUID `json:"bundle_id" binding:"required"`
+}
+
+type InternalGrantBundleForUserResponse struct {
+	UserId             uuid.UUID   `json:"user_id"`
+	BundleId           uuid.UUID   `json:"bundle_id"`
+	GrantedCosmeticIds []uuid.UUID `json:"granted_cosmetic_ids"`
+}
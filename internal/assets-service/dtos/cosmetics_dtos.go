@@ -21,10 +21,14 @@ type CosmeticResponse struct {
 	UpdatedAt   time.Time `json:"updated_at"`
 }
 
+// InternalCosmeticResponse carries the price a buyer pays right now, base_price is the price before promotions.
 type InternalCosmeticResponse struct {
-	CosmeticId    uuid.UUID `json:"cosmetic_id"`
-	CosmeticPrice int64     `json:"cosmetic_price"`
-	CreatedBy     uuid.UUID `json:"created_by"`
+	CosmeticId    uuid.UUID  `json:"cosmetic_id"`
+	CosmeticPrice int64      `json:"cosmetic_price"`
+	BasePrice     int64      `json:"base_price"`
+	PromotionId   *uuid.UUID `json:"promotion_id,omitempty"`
+	CreatedBy     uuid.UUID  `json:"created_by"`
+	WorldId       uuid.UUID  `json:"world_id"`
 }
 
 // CosmeticCategoryListResponse returns a list of sprite categories.
@@ -45,10 +49,12 @@ type CosmeticsEconomySummaryResponse struct {
 }
 
 type InternalPurchaseCosmeticForUserRequest struct {
-	CosmeticId uuid.UUID `json:"cosmetic_id" binding:"required"`
+	CosmeticId uuid.UUID  `json:"cosmetic_id" binding:"required"`
+	GiftedBy   *uuid.UUID `json:"gifted_by,omitempty"`
 }
 
 type InternalPurchaseCosmeticForUserResponse struct {
-	UserId     uuid.UUID `json:"user_id"`
-	CosmeticId uuid.UUID `json:"cosmetic_id"`
+	UserId     uuid.UUID  `json:"user_id"`
+	CosmeticId uuid.UUID  `json:"cosmetic_id"`
+	GiftedBy   *uuid.UUID `json:"gifted_by,omitempty"`
 }
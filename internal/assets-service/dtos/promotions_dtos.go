@@ -0,0 +1,33 @@
+package dtos
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CreatePromotionRequest targets the whole world when neither cosmetic_id nor bundle_id is set.
+type CreatePromotionRequest struct {
+	WorldId    *uuid.UUID `json:"world_id" binding:"required"`
+	CosmeticId *uuid.UUID `json:"cosmetic_id"`
+	BundleId   *uuid.UUID `json:"bundle_id"`
+	PercentOff int        `json:"percent_off" binding:"required"`
+	StartsAt   time.Time  `json:"starts_at" binding:"required"`
+	EndsAt     time.Time  `json:"ends_at" binding:"required"`
+}
+
+type PromotionResponse struct {
+	PromotionId uuid.UUID  `json:"promotion_id"`
+	WorldId     uuid.UUID  `json:"world_id"`
+	CosmeticId  *uuid.UUID `json:"cosmetic_id"`
+	BundleId    *uuid.UUID `json:"bundle_id"`
+	PercentOff  int        `json:"percent_off"`
+	StartsAt    time.Time  `json:"starts_at"`
+	EndsAt      time.Time  `json:"ends_at"`
+	CreatedBy   uuid.UUID  `json:"created_by"`
+}
+
+// PromotionsListResponse returns a list of promotions.
+type PromotionsListResponse struct {
+	Promotions []PromotionResponse `json:"promotions"`
+}
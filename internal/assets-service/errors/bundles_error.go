@@ -0,0 +1,76 @@
+package errors
+
+// BundleNotFound is returned when a requested bundle cannot be found.
+type BundleNotFound struct {
+	details string
+}
+
+func (e *BundleNotFound) Error() string {
+	return e.details
+}
+
+func NewBundleNotFound(details string) *BundleNotFound {
+	return &BundleNotFound{
+		details: details,
+	}
+}
+
+// InvalidBundle is returned when a bundle has an invalid price or set of cosmetics.
+type InvalidBundle struct {
+	details string
+}
+
+func (e *InvalidBundle) Error() string {
+	return e.details
+}
+
+func NewInvalidBundle(details string) *InvalidBundle {
+	return &InvalidBundle{
+		details: details,
+	}
+}
+
+// BundleAlreadyOwned is returned when a user already owns every cosmetic of a bundle.
+type BundleAlreadyOwned struct {
+	details string
+}
+
+func (e *BundleAlreadyOwned) Error() string {
+	return e.details
+}
+
+func NewBundleAlreadyOwned(details string) *BundleAlreadyOwned {
+	return &BundleAlreadyOwned{
+		details: details,
+	}
+}
+
+// PromotionNotFound is returned when a requested promotion cannot be found.
+type PromotionNotFound struct {
+	details string
+}
+
+func (e *PromotionNotFound) Error() string {
+	return e.details
+}
+
+func NewPromotionNotFound(details string) *PromotionNotFound {
+	return &PromotionNotFound{
+		details: details,
+	}
+}
+
+// InvalidPromotion is returned when a promotion has an invalid discount, window or target.
+type InvalidPromotion struct {
+	details string
+}
+
+func (e *InvalidPromotion) Error() string {
+	return e.details
+}
+
+func NewInvalidPromotion(details string) *InvalidPromotion {
+	return &InvalidPromotion{
+		details: details,
+	}
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CosmeticBundle sells several cosmetics of the same world for a single gem price.
+type CosmeticBundle struct {
+	Id        uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	WorldID   uuid.UUID  `gorm:"type:uuid;not null;index"`
+	Name      string     `gorm:"not null"`
+	Price     int64      `gorm:"not null"`
+	Cosmetics []Cosmetic `gorm:"many2many:cosmetic_bundle_items;joinForeignKey:BundleID;joinReferences:CosmeticID"`
+	CreatedAt time.Time  `gorm:"autoCreateTime"`
+	UpdatedAt time.Time  `gorm:"autoUpdateTime"`
+	CreatedBy uuid.UUID  `gorm:"type:uuid;not null"`
+}
+
+func (CosmeticBundle) TableName() string {
+	return "cosmetic_bundles"
+}
+
+// CosmeticIds returns the ids of the cosmetics included in the bundle.
+func (b *CosmeticBundle) CosmeticIds() []uuid.UUID {
+	ids := make([]uuid.UUID, len(b.Cosmetics))
+	for idx, cosmetic := range b.Cosmetics {
+		ids[idx] = cosmetic.Id
+	}
+	return ids
+}
+
+type CosmeticBundleItem struct {
+	BundleID   uuid.UUID `gorm:"type:uuid;not null;primaryKey"`
+	CosmeticID uuid.UUID `gorm:"type:uuid;not null;primaryKey"`
+}
+
+func (CosmeticBundleItem) TableName() string {
+	return "cosmetic_bundle_items"
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	MIN_PROMOTION_PERCENT_OFF = 1
+	MAX_PROMOTION_PERCENT_OFF = 99
+)
+
+// CosmeticPromotion takes a percentage off the price during a date window. It targets a single cosmetic,
+// a single bundle, or every cosmetic and bundle of the world when neither is set.
+type CosmeticPromotion struct {
+	Id         uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	WorldID    uuid.UUID  `gorm:"type:uuid;not null;index"`
+	CosmeticID *uuid.UUID `gorm:"type:uuid"`
+	BundleID   *uuid.UUID `gorm:"type:uuid"`
+	PercentOff int        `gorm:"not null"`
+	StartsAt   time.Time  `gorm:"type:timestamptz;not null"`
+	EndsAt     time.Time  `gorm:"type:timestamptz;not null"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime"`
+	UpdatedAt  time.Time  `gorm:"autoUpdateTime"`
+	CreatedBy  uuid.UUID  `gorm:"type:uuid;not null"`
+}
+
+func (CosmeticPromotion) TableName() string {
+	return "cosmetic_promotions"
+}
+
+// IsActiveAt reports whether the promotion window contains the given instant, the end being exclusive.
+func (p *CosmeticPromotion) IsActiveAt(at time.Time) bool {
+	return !at.Before(p.StartsAt) && at.Before(p.EndsAt)
+}
+
+// ResolvedPrice is the price a buyer pays right now, along with the promotion that produced it if any.
+type ResolvedPrice struct {
+	BasePrice   int64
+	Price       int64
+	PromotionID *uuid.UUID
+}
+
+// ApplyPercentOff discounts the price rounding to the nearest gem, a paid item never drops below one gem.
+func ApplyPercentOff(price int64, percentOff int) int64 {
+	if price <= 0 || percentOff <= 0 {
+		return price
+	}
+
+	discounted := (price*int64(100-percentOff) + 50) / 100
+	if discounted < 1 {
+		return 1
+	}
+	return discounted
+}
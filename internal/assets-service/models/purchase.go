@@ -7,9 +7,10 @@ import (
 )
 
 type Purchase struct {
-	PlayerID     uuid.UUID `gorm:"type:uuid;not null;primaryKey" json:"player_id"`
-	CosmeticID   uuid.UUID `gorm:"type:uuid;not null;primaryKey" json:"cosmetic_id"`
-	PurchaseDate time.Time `gorm:"type:timestamptz;not null;default:now()" json:"purchase_date"`
+	PlayerID     uuid.UUID  `gorm:"type:uuid;not null;primaryKey" json:"player_id"`
+	CosmeticID   uuid.UUID  `gorm:"type:uuid;not null;primaryKey" json:"cosmetic_id"`
+	PurchaseDate time.Time  `gorm:"type:timestamptz;not null;default:now()" json:"purchase_date"`
+	GiftedBy     *uuid.UUID `gorm:"type:uuid" json:"gifted_by,omitempty"`
 }
 
 func (Purchase) TableName() string {
@@ -0,0 +1,8 @@
+package models
+
+// UserData is everything the assets-service stores about a user, as handed out in a data export.
+// Published cosmetics are world content owned by their buyers too, so only purchases are personal data.
+type UserData struct {
+	Purchases []Purchase `json:"purchases"`
+	GiftsSent []Purchase `json:"gifts_sent"`
+}
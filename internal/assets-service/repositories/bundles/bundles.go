@@ -0,0 +1,107 @@
+package bundles
+
+import (
+	"github.com/FeedTheRealm-org/core-service/config"
+	assets_errors "github.com/FeedTheRealm-org/core-service/internal/assets-service/errors"
+	"github.com/FeedTheRealm-org/core-service/internal/assets-service/models"
+	"github.com/FeedTheRealm-org/core-service/internal/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type bundlesRepository struct {
+	conf *config.Config
+	db   *config.DB
+}
+
+// NewBundlesRepository creates a new instance of BundlesRepository.
+func NewBundlesRepository(conf *config.Config, db *config.DB) BundlesRepository {
+	return &bundlesRepository{
+		conf: conf,
+		db:   db,
+	}
+}
+
+func (br *bundlesRepository) CreateBundle(bundle *models.CosmeticBundle, cosmeticIds []uuid.UUID) error {
+	return br.db.Conn.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Cosmetics").Create(bundle).Error; err != nil {
+			return err
+		}
+
+		items := make([]models.CosmeticBundleItem, len(cosmeticIds))
+		for idx, cosmeticId := range cosmeticIds {
+			items[idx] = models.CosmeticBundleItem{BundleID: bundle.Id, CosmeticID: cosmeticId}
+		}
+		if err := tx.Create(&items).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("id IN ?", cosmeticIds).Order("id ASC").Find(&bundle.Cosmetics).Error
+	})
+}
+
+func (br *bundlesRepository) GetBundleById(bundleId uuid.UUID) (*models.CosmeticBundle, error) {
+	var bundle models.CosmeticBundle
+	if err := br.db.Conn.Preload("Cosmetics", func(db *gorm.DB) *gorm.DB {
+		return db.Order("cosmetics.id ASC")
+	}).First(&bundle, "id = ?", bundleId).Error; err != nil {
+		if errors.IsRecordNotFound(err) {
+			return nil, assets_errors.NewBundleNotFound("bundle not found")
+		}
+		return nil, err
+	}
+	return &bundle, nil
+}
+
+func (br *bundlesRepository) GetBundlesListByWorld(worldId uuid.UUID) ([]*models.CosmeticBundle, error) {
+	var bundles []*models.CosmeticBundle
+	if err := br.db.Conn.Preload("Cosmetics", func(db *gorm.DB) *gorm.DB {
+		return db.Order("cosmetics.id ASC")
+	}).Where("world_id = ?", worldId).Order("created_at ASC, id ASC").Find(&bundles).Error; err != nil {
+		return nil, err
+	}
+	return bundles, nil
+}
+
+func (br *bundlesRepository) DeleteBundle(bundleId uuid.UUID) error {
+	result := br.db.Conn.Delete(&models.CosmeticBundle{}, "id = ?", bundleId)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return assets_errors.NewBundleNotFound("bundle not found")
+	}
+	return nil
+}
+
+func (br *bundlesRepository) GrantBundleForUserId(bundleId uuid.UUID, userId uuid.UUID) ([]uuid.UUID, error) {
+	var granted []uuid.UUID
+
+	err := br.db.Conn.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&models.CosmeticBundle{}, "id = ?", bundleId).Error; err != nil {
+			if errors.IsRecordNotFound(err) {
+				return assets_errors.NewBundleNotFound("bundle not found")
+			}
+			return err
+		}
+
+		// A single statement so a concurrent grant of one of the cosmetics cannot fail the whole bundle
+		if err := tx.Raw(`
+			INSERT INTO purchases (player_id, cosmetic_id)
+			SELECT ?, cosmetic_id FROM cosmetic_bundle_items WHERE bundle_id = ?
+			ON CONFLICT DO NOTHING
+			RETURNING cosmetic_id`, userId, bundleId).Scan(&granted).Error; err != nil {
+			return err
+		}
+
+		if len(granted) == 0 {
+			return assets_errors.NewBundleAlreadyOwned("every cosmetic of the bundle was already purchased by the user")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return granted, nil
+}
@@ -0,0 +1,117 @@
+package bundles_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/FeedTheRealm-org/core-service/config"
+	assets_errors "github.com/FeedTheRealm-org/core-service/internal/assets-service/errors"
+	"github.com/FeedTheRealm-org/core-service/internal/assets-service/models"
+	bundlesrepo "github.com/FeedTheRealm-org/core-service/internal/assets-service/repositories/bundles"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/logger"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var bundlesDB *config.DB
+var bundlesRepo bundlesrepo.BundlesRepository
+
+func TestMain(m *testing.M) {
+	logger.InitLogger(false)
+
+	conf := config.CreateConfig()
+	var err error
+	bundlesDB, err = config.NewDB(conf)
+	if err != nil {
+		panic(err)
+	}
+	bundlesRepo = bundlesrepo.NewBundlesRepository(conf, bundlesDB)
+
+	clearBundlesTables()
+	code := m.Run()
+	clearBundlesTables()
+	os.Exit(code)
+}
+
+func clearBundlesTables() {
+	_ = bundlesDB.Conn.Exec("TRUNCATE TABLE cosmetic_bundle_items, cosmetic_bundles, purchases, cosmetics, cosmetics_categories RESTART IDENTITY CASCADE;")
+}
+
+func createWorldCosmetics(t *testing.T, worldId uuid.UUID, count int) []uuid.UUID {
+	t.Helper()
+	category := &models.CosmeticCategory{Name: "category-" + uuid.NewString()[:8]}
+	require.NoError(t, bundlesDB.Conn.Create(category).Error)
+
+	ids := make([]uuid.UUID, count)
+	for i := range ids {
+		cosmetic := &models.Cosmetic{Url: uuid.NewString(), Price: 10, CategoryID: category.Id, WorldID: worldId, CreatedBy: uuid.New()}
+		require.NoError(t, bundlesDB.Conn.Create(cosmetic).Error)
+		ids[i] = cosmetic.Id
+	}
+	return ids
+}
+
+func TestBundlesRepository_CreateAndGet(t *testing.T) {
+	clearBundlesTables()
+	worldId := uuid.New()
+	ids := createWorldCosmetics(t, worldId, 3)
+
+	bundle := &models.CosmeticBundle{WorldID: worldId, Name: "pack", Price: 20, CreatedBy: uuid.New()}
+	require.NoError(t, bundlesRepo.CreateBundle(bundle, ids))
+	assert.Len(t, bundle.Cosmetics, 3)
+
+	stored, err := bundlesRepo.GetBundleById(bundle.Id)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, ids, stored.CosmeticIds())
+
+	list, err := bundlesRepo.GetBundlesListByWorld(worldId)
+	require.NoError(t, err)
+	if assert.Len(t, list, 1) {
+		assert.Len(t, list[0].Cosmetics, 3)
+	}
+}
+
+func TestBundlesRepository_GetBundleById_NotFound(t *testing.T) {
+	_, err := bundlesRepo.GetBundleById(uuid.New())
+	assert.IsType(t, &assets_errors.BundleNotFound{}, err)
+}
+
+func TestBundlesRepository_GrantBundle_SkipsOwnedCosmetics(t *testing.T) {
+	clearBundlesTables()
+	worldId := uuid.New()
+	userId := uuid.New()
+	ids := createWorldCosmetics(t, worldId, 3)
+
+	bundle := &models.CosmeticBundle{WorldID: worldId, Name: "pack", Price: 20, CreatedBy: uuid.New()}
+	require.NoError(t, bundlesRepo.CreateBundle(bundle, ids))
+	require.NoError(t, bundlesDB.Conn.Create(&models.Purchase{PlayerID: userId, CosmeticID: ids[0]}).Error)
+
+	granted, err := bundlesRepo.GrantBundleForUserId(bundle.Id, userId)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, ids[1:], granted)
+
+	var owned int64
+	require.NoError(t, bundlesDB.Conn.Model(&models.Purchase{}).Where("player_id = ?", userId).Count(&owned).Error)
+	assert.Equal(t, int64(3), owned)
+
+	_, err = bundlesRepo.GrantBundleForUserId(bundle.Id, userId)
+	assert.IsType(t, &assets_errors.BundleAlreadyOwned{}, err)
+}
+
+func TestBundlesRepository_GrantBundle_NotFound(t *testing.T) {
+	_, err := bundlesRepo.GrantBundleForUserId(uuid.New(), uuid.New())
+	assert.IsType(t, &assets_errors.BundleNotFound{}, err)
+}
+
+func TestBundlesRepository_DeleteBundle(t *testing.T) {
+	clearBundlesTables()
+	worldId := uuid.New()
+	ids := createWorldCosmetics(t, worldId, 2)
+
+	bundle := &models.CosmeticBundle{WorldID: worldId, Name: "pack", Price: 20, CreatedBy: uuid.New()}
+	require.NoError(t, bundlesRepo.CreateBundle(bundle, ids))
+
+	require.NoError(t, bundlesRepo.DeleteBundle(bundle.Id))
+	assert.IsType(t, &assets_errors.BundleNotFound{}, bundlesRepo.DeleteBundle(bundle.Id))
+}
@@ -0,0 +1,20 @@
+package bundles
+
+import (
+	"github.com/FeedTheRealm-org/core-service/internal/assets-service/models"
+	"github.com/google/uuid"
+)
+
+// BundlesRepository defines the interface for cosmetic bundle database operations.
+type BundlesRepository interface {
+	CreateBundle(bundle *models.CosmeticBundle, cosmeticIds []uuid.UUID) error
+
+	GetBundleById(bundleId uuid.UUID) (*models.CosmeticBundle, error)
+
+	GetBundlesListByWorld(worldId uuid.UUID) ([]*models.CosmeticBundle, error)
+
+	DeleteBundle(bundleId uuid.UUID) error
+
+	// GrantBundleForUserId records a purchase for every cosmetic of the bundle the user does not own yet, all or nothing.
+	GrantBundleForUserId(bundleId uuid.UUID, userId uuid.UUID) ([]uuid.UUID, error)
+}
@@ -117,10 +117,11 @@ func (cr *cosmeticsRepository) AddCategory(categoryName string) (*models.Cosmeti
 	return category, nil
 }
 
-func (cr *cosmeticsRepository) AddPurchaseForUserId(cosmeticId uuid.UUID, userId uuid.UUID) error {
+func (cr *cosmeticsRepository) AddPurchaseForUserId(cosmeticId uuid.UUID, userId uuid.UUID, giftedBy *uuid.UUID) error {
 	purchase := &models.Purchase{
 		CosmeticID: cosmeticId,
 		PlayerID:   userId,
+		GiftedBy:   giftedBy,
 	}
 
 	if err := cr.db.Conn.Where("cosmetic_id = ? AND player_id = ?", cosmeticId, userId).First(&models.Purchase{}).Error; err == nil {
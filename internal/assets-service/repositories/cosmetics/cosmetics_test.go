@@ -100,15 +100,35 @@ func TestCosmeticsRepository_AddPurchaseForUserId(t *testing.T) {
 	assert.NoError(t, cosmeticsRepo.CreateCosmetic(category.Id, uuid.New(), 10, cosmetic, uuid.New()))
 
 	userId := uuid.New()
-	assert.NoError(t, cosmeticsRepo.AddPurchaseForUserId(cosmetic.Id, userId))
+	assert.NoError(t, cosmeticsRepo.AddPurchaseForUserId(cosmetic.Id, userId, nil))
 
-	err = cosmeticsRepo.AddPurchaseForUserId(cosmetic.Id, userId)
+	err = cosmeticsRepo.AddPurchaseForUserId(cosmetic.Id, userId, nil)
 	assert.Error(t, err)
 
 	var conflict *assets_errors.CosmeticsWasPurchasedBefore
 	assert.True(t, errors.As(err, &conflict))
 }
 
+func TestCosmeticsRepository_AddPurchaseForUserId_Gift(t *testing.T) {
+	clearCosmeticsTables()
+
+	category, err := cosmeticsRepo.AddCategory("hats")
+	assert.NoError(t, err)
+
+	cosmetic := &models.Cosmetic{Url: "/hats/gift.png"}
+	assert.NoError(t, cosmeticsRepo.CreateCosmetic(category.Id, uuid.New(), 10, cosmetic, uuid.New()))
+
+	recipientId := uuid.New()
+	gifterId := uuid.New()
+	assert.NoError(t, cosmeticsRepo.AddPurchaseForUserId(cosmetic.Id, recipientId, &gifterId))
+
+	var purchase models.Purchase
+	assert.NoError(t, cosmeticsDB.Conn.Where("cosmetic_id = ? AND player_id = ?", cosmetic.Id, recipientId).First(&purchase).Error)
+	if assert.NotNil(t, purchase.GiftedBy) {
+		assert.Equal(t, gifterId, *purchase.GiftedBy)
+	}
+}
+
 func TestCosmeticsRepository_GetCosmeticsListByCategory_Branches(t *testing.T) {
 	clearCosmeticsTables()
 
@@ -124,7 +144,7 @@ func TestCosmeticsRepository_GetCosmeticsListByCategory_Branches(t *testing.T) {
 	assert.NoError(t, cosmeticsRepo.CreateCosmetic(category.Id, uuid.Nil, 5, defaultCosmetic, uuid.New()))
 	assert.NoError(t, cosmeticsRepo.CreateCosmetic(category.Id, worldId, 7, worldCosmetic, uuid.New()))
 	assert.NoError(t, cosmeticsRepo.CreateCosmetic(category.Id, worldId, 9, playerCosmetic, uuid.New()))
-	assert.NoError(t, cosmeticsRepo.AddPurchaseForUserId(playerCosmetic.Id, playerId))
+	assert.NoError(t, cosmeticsRepo.AddPurchaseForUserId(playerCosmetic.Id, playerId, nil))
 
 	list, total, err := cosmeticsRepo.GetCosmeticsListByCategory(category.Id, nil, nil, 0, 10)
 	assert.NoError(t, err)
@@ -17,7 +17,7 @@ type CosmeticsRepository interface {
 
 	AddCategory(category string) (*models.CosmeticCategory, error)
 
-	AddPurchaseForUserId(cosmeticId uuid.UUID, userId uuid.UUID) error
+	AddPurchaseForUserId(cosmeticId uuid.UUID, userId uuid.UUID, giftedBy *uuid.UUID) error
 
 	GetCategoryById(categoryId uuid.UUID) (*models.CosmeticCategory, error)
 
@@ -0,0 +1,87 @@
+package promotions
+
+import (
+	"time"
+
+	"github.com/FeedTheRealm-org/core-service/config"
+	assets_errors "github.com/FeedTheRealm-org/core-service/internal/assets-service/errors"
+	"github.com/FeedTheRealm-org/core-service/internal/assets-service/models"
+	"github.com/FeedTheRealm-org/core-service/internal/errors"
+	"github.com/google/uuid"
+)
+
+type promotionsRepository struct {
+	conf *config.Config
+	db   *config.DB
+}
+
+// NewPromotionsRepository creates a new instance of PromotionsRepository.
+func NewPromotionsRepository(conf *config.Config, db *config.DB) PromotionsRepository {
+	return &promotionsRepository{
+		conf: conf,
+		db:   db,
+	}
+}
+
+func (pr *promotionsRepository) CreatePromotion(promotion *models.CosmeticPromotion) error {
+	return pr.db.Conn.Create(promotion).Error
+}
+
+func (pr *promotionsRepository) GetPromotionById(promotionId uuid.UUID) (*models.CosmeticPromotion, error) {
+	var promotion models.CosmeticPromotion
+	if err := pr.db.Conn.First(&promotion, "id = ?", promotionId).Error; err != nil {
+		if errors.IsRecordNotFound(err) {
+			return nil, assets_errors.NewPromotionNotFound("promotion not found")
+		}
+		return nil, err
+	}
+	return &promotion, nil
+}
+
+func (pr *promotionsRepository) GetPromotionsListByWorld(worldId uuid.UUID, activeAt *time.Time) ([]*models.CosmeticPromotion, error) {
+	query := pr.db.Conn.Where("world_id = ?", worldId)
+	if activeAt != nil {
+		query = query.Where("starts_at <= ? AND ends_at > ?", *activeAt, *activeAt)
+	}
+
+	var promotions []*models.CosmeticPromotion
+	if err := query.Order("starts_at ASC, id ASC").Find(&promotions).Error; err != nil {
+		return nil, err
+	}
+	return promotions, nil
+}
+
+func (pr *promotionsRepository) DeletePromotion(promotionId uuid.UUID) error {
+	result := pr.db.Conn.Delete(&models.CosmeticPromotion{}, "id = ?", promotionId)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return assets_errors.NewPromotionNotFound("promotion not found")
+	}
+	return nil
+}
+
+func (pr *promotionsRepository) GetBestActivePromotion(worldId uuid.UUID, cosmeticId *uuid.UUID, bundleId *uuid.UUID, at time.Time) (*models.CosmeticPromotion, error) {
+	query := pr.db.Conn.
+		Where("world_id = ? AND starts_at <= ? AND ends_at > ?", worldId, at, at)
+
+	// World-wide promotions apply to every cosmetic and bundle of the world
+	switch {
+	case cosmeticId != nil:
+		query = query.Where("((cosmetic_id IS NULL AND bundle_id IS NULL) OR cosmetic_id = ?)", *cosmeticId)
+	case bundleId != nil:
+		query = query.Where("((cosmetic_id IS NULL AND bundle_id IS NULL) OR bundle_id = ?)", *bundleId)
+	default:
+		query = query.Where("cosmetic_id IS NULL AND bundle_id IS NULL")
+	}
+
+	var promotions []*models.CosmeticPromotion
+	if err := query.Order("percent_off DESC, starts_at ASC, id ASC").Limit(1).Find(&promotions).Error; err != nil {
+		return nil, err
+	}
+	if len(promotions) == 0 {
+		return nil, nil
+	}
+	return promotions[0], nil
+}
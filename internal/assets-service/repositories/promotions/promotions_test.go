@@ -0,0 +1,120 @@
+package promotions_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/FeedTheRealm-org/core-service/config"
+	assets_errors "github.com/FeedTheRealm-org/core-service/internal/assets-service/errors"
+	"github.com/FeedTheRealm-org/core-service/internal/assets-service/models"
+	promotionsrepo "github.com/FeedTheRealm-org/core-service/internal/assets-service/repositories/promotions"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/logger"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var promotionsDB *config.DB
+var promotionsRepo promotionsrepo.PromotionsRepository
+
+func TestMain(m *testing.M) {
+	logger.InitLogger(false)
+
+	conf := config.CreateConfig()
+	var err error
+	promotionsDB, err = config.NewDB(conf)
+	if err != nil {
+		panic(err)
+	}
+	promotionsRepo = promotionsrepo.NewPromotionsRepository(conf, promotionsDB)
+
+	clearPromotionsTables()
+	code := m.Run()
+	clearPromotionsTables()
+	os.Exit(code)
+}
+
+func clearPromotionsTables() {
+	_ = promotionsDB.Conn.Exec("TRUNCATE TABLE cosmetic_promotions, cosmetic_bundle_items, cosmetic_bundles, cosmetics, cosmetics_categories RESTART IDENTITY CASCADE;")
+}
+
+func createCosmetic(t *testing.T, worldId uuid.UUID) uuid.UUID {
+	t.Helper()
+	category := &models.CosmeticCategory{Name: "category-" + uuid.NewString()[:8]}
+	require.NoError(t, promotionsDB.Conn.Create(category).Error)
+	cosmetic := &models.Cosmetic{Url: uuid.NewString(), Price: 10, CategoryID: category.Id, WorldID: worldId, CreatedBy: uuid.New()}
+	require.NoError(t, promotionsDB.Conn.Create(cosmetic).Error)
+	return cosmetic.Id
+}
+
+func createPromotion(t *testing.T, worldId uuid.UUID, cosmeticId *uuid.UUID, percentOff int, startsAt time.Time, endsAt time.Time) *models.CosmeticPromotion {
+	t.Helper()
+	promotion := &models.CosmeticPromotion{
+		WorldID:    worldId,
+		CosmeticID: cosmeticId,
+		PercentOff: percentOff,
+		StartsAt:   startsAt,
+		EndsAt:     endsAt,
+		CreatedBy:  uuid.New(),
+	}
+	require.NoError(t, promotionsRepo.CreatePromotion(promotion))
+	return promotion
+}
+
+func TestPromotionsRepository_GetBestActivePromotion(t *testing.T) {
+	clearPromotionsTables()
+	worldId := uuid.New()
+	cosmeticId := createCosmetic(t, worldId)
+	otherCosmeticId := createCosmetic(t, worldId)
+	now := time.Now()
+
+	worldWide := createPromotion(t, worldId, nil, 10, now.Add(-time.Hour), now.Add(time.Hour))
+	best := createPromotion(t, worldId, &cosmeticId, 30, now.Add(-time.Hour), now.Add(time.Hour))
+	createPromotion(t, worldId, &otherCosmeticId, 50, now.Add(-time.Hour), now.Add(time.Hour))
+	createPromotion(t, worldId, &cosmeticId, 70, now.Add(-2*time.Hour), now.Add(-time.Hour))
+	createPromotion(t, worldId, &cosmeticId, 80, now.Add(time.Hour), now.Add(2*time.Hour))
+
+	promotion, err := promotionsRepo.GetBestActivePromotion(worldId, &cosmeticId, nil, now)
+	require.NoError(t, err)
+	assert.Equal(t, best.Id, promotion.Id)
+
+	bundleId := uuid.New()
+	promotion, err = promotionsRepo.GetBestActivePromotion(worldId, nil, &bundleId, now)
+	require.NoError(t, err)
+	assert.Equal(t, worldWide.Id, promotion.Id)
+
+	promotion, err = promotionsRepo.GetBestActivePromotion(uuid.New(), &cosmeticId, nil, now)
+	require.NoError(t, err)
+	assert.Nil(t, promotion)
+}
+
+func TestPromotionsRepository_GetPromotionsListByWorld(t *testing.T) {
+	clearPromotionsTables()
+	worldId := uuid.New()
+	now := time.Now()
+
+	createPromotion(t, worldId, nil, 10, now.Add(-time.Hour), now.Add(time.Hour))
+	createPromotion(t, worldId, nil, 20, now.Add(time.Hour), now.Add(2*time.Hour))
+
+	all, err := promotionsRepo.GetPromotionsListByWorld(worldId, nil)
+	require.NoError(t, err)
+	assert.Len(t, all, 2)
+
+	active, err := promotionsRepo.GetPromotionsListByWorld(worldId, &now)
+	require.NoError(t, err)
+	if assert.Len(t, active, 1) {
+		assert.Equal(t, 10, active[0].PercentOff)
+	}
+}
+
+func TestPromotionsRepository_Delete(t *testing.T) {
+	clearPromotionsTables()
+	now := time.Now()
+	promotion := createPromotion(t, uuid.New(), nil, 10, now, now.Add(time.Hour))
+
+	require.NoError(t, promotionsRepo.DeletePromotion(promotion.Id))
+	_, err := promotionsRepo.GetPromotionById(promotion.Id)
+	assert.IsType(t, &assets_errors.PromotionNotFound{}, err)
+	assert.IsType(t, &assets_errors.PromotionNotFound{}, promotionsRepo.DeletePromotion(promotion.Id))
+}
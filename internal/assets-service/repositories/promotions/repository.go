@@ -0,0 +1,23 @@
+package promotions
+
+import (
+	"time"
+
+	"github.com/FeedTheRealm-org/core-service/internal/assets-service/models"
+	"github.com/google/uuid"
+)
+
+// PromotionsRepository defines the interface for cosmetic promotion database operations.
+type PromotionsRepository interface {
+	CreatePromotion(promotion *models.CosmeticPromotion) error
+
+	GetPromotionById(promotionId uuid.UUID) (*models.CosmeticPromotion, error)
+
+	// GetPromotionsListByWorld lists the promotions of a world, only the ones active at the given instant when set.
+	GetPromotionsListByWorld(worldId uuid.UUID, activeAt *time.Time) ([]*models.CosmeticPromotion, error)
+
+	DeletePromotion(promotionId uuid.UUID) error
+
+	// GetBestActivePromotion returns the active promotion with the highest discount for the cosmetic or bundle, nil when there is none.
+	GetBestActivePromotion(worldId uuid.UUID, cosmeticId *uuid.UUID, bundleId *uuid.UUID, at time.Time) (*models.CosmeticPromotion, error)
+}
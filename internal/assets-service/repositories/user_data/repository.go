@@ -0,0 +1,14 @@
+package user_data
+
+import (
+	"github.com/FeedTheRealm-org/core-service/internal/assets-service/models"
+	"github.com/google/uuid"
+)
+
+// UserDataRepository defines the export and erasure of everything stored about a user.
+type UserDataRepository interface {
+	GetUserData(userId uuid.UUID) (*models.UserData, error)
+
+	// DeleteUserData removes the purchases of the user and forgets them as the sender of gifts.
+	DeleteUserData(userId uuid.UUID) error
+}
@@ -0,0 +1,46 @@
+package user_data
+
+import (
+	"github.com/FeedTheRealm-org/core-service/config"
+	"github.com/FeedTheRealm-org/core-service/internal/assets-service/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type userDataRepository struct {
+	conf *config.Config
+	db   *config.DB
+}
+
+// NewUserDataRepository creates a new instance of UserDataRepository.
+func NewUserDataRepository(conf *config.Config, db *config.DB) UserDataRepository {
+	return &userDataRepository{
+		conf: conf,
+		db:   db,
+	}
+}
+
+func (r *userDataRepository) GetUserData(userId uuid.UUID) (*models.UserData, error) {
+	data := &models.UserData{
+		Purchases: []models.Purchase{},
+		GiftsSent: []models.Purchase{},
+	}
+
+	if err := r.db.Conn.Where("player_id = ?", userId).Order("purchase_date ASC").Find(&data.Purchases).Error; err != nil {
+		return nil, err
+	}
+	if err := r.db.Conn.Where("gifted_by = ?", userId).Order("purchase_date ASC").Find(&data.GiftsSent).Error; err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (r *userDataRepository) DeleteUserData(userId uuid.UUID) error {
+	return r.db.Conn.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("player_id = ?", userId).Delete(&models.Purchase{}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Purchase{}).Where("gifted_by = ?", userId).Update("gifted_by", nil).Error
+	})
+}
@@ -0,0 +1,65 @@
+package user_data
+
+import (
+	"os"
+	"testing"
+
+	"github.com/FeedTheRealm-org/core-service/config"
+	"github.com/FeedTheRealm-org/core-service/internal/assets-service/models"
+	cosmeticsrepo "github.com/FeedTheRealm-org/core-service/internal/assets-service/repositories/cosmetics"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/logger"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var userDataConf *config.Config
+var userDataDB *config.DB
+var userDataRepo UserDataRepository
+var cosmeticsRepo cosmeticsrepo.CosmeticsRepository
+
+func TestMain(m *testing.M) {
+	logger.InitLogger(false)
+
+	userDataConf = config.CreateConfig()
+	var err error
+	userDataDB, err = config.NewDB(userDataConf)
+	if err != nil {
+		panic(err)
+	}
+	userDataRepo = NewUserDataRepository(userDataConf, userDataDB)
+	cosmeticsRepo = cosmeticsrepo.NewCosmeticsRepository(userDataConf, userDataDB)
+
+	code := m.Run()
+	os.Exit(code)
+}
+
+func TestUserDataRepository_GetAndDeleteUserData(t *testing.T) {
+	category, err := cosmeticsRepo.AddCategory("user-data-" + uuid.NewString())
+	require.NoError(t, err)
+	cosmetic := &models.Cosmetic{Url: "/hats/one.png"}
+	require.NoError(t, cosmeticsRepo.CreateCosmetic(category.Id, uuid.New(), 10, cosmetic, uuid.New()))
+
+	userId := uuid.New()
+	friendId := uuid.New()
+	require.NoError(t, cosmeticsRepo.AddPurchaseForUserId(cosmetic.Id, userId, nil))
+	require.NoError(t, cosmeticsRepo.AddPurchaseForUserId(cosmetic.Id, friendId, &userId))
+
+	data, err := userDataRepo.GetUserData(userId)
+	require.NoError(t, err)
+	assert.Len(t, data.Purchases, 1)
+	require.Len(t, data.GiftsSent, 1)
+	assert.Equal(t, friendId, data.GiftsSent[0].PlayerID)
+
+	require.NoError(t, userDataRepo.DeleteUserData(userId))
+
+	data, err = userDataRepo.GetUserData(userId)
+	require.NoError(t, err)
+	assert.Empty(t, data.Purchases)
+	assert.Empty(t, data.GiftsSent)
+
+	// The friend keeps the gifted cosmetic.
+	var gift models.Purchase
+	require.NoError(t, userDataDB.Conn.Where("cosmetic_id = ? AND player_id = ?", cosmetic.Id, friendId).First(&gift).Error)
+	assert.Nil(t, gift.GiftedBy)
+}
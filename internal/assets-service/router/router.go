@@ -2,27 +2,45 @@ package router
 
 import (
 	"github.com/FeedTheRealm-org/core-service/config"
+	bundles_controller "github.com/FeedTheRealm-org/core-service/internal/assets-service/controllers/bundles"
 	cosmetics_controller "github.com/FeedTheRealm-org/core-service/internal/assets-service/controllers/cosmetics"
 	items_controller "github.com/FeedTheRealm-org/core-service/internal/assets-service/controllers/items"
 	materials_controller "github.com/FeedTheRealm-org/core-service/internal/assets-service/controllers/materials"
 	models_controller "github.com/FeedTheRealm-org/core-service/internal/assets-service/controllers/models"
+	promotions_controller "github.com/FeedTheRealm-org/core-service/internal/assets-service/controllers/promotions"
 	"github.com/FeedTheRealm-org/core-service/internal/assets-service/repositories/bucket"
+	bundles_repo "github.com/FeedTheRealm-org/core-service/internal/assets-service/repositories/bundles"
 	cosmetics_repo "github.com/FeedTheRealm-org/core-service/internal/assets-service/repositories/cosmetics"
 	items_repo "github.com/FeedTheRealm-org/core-service/internal/assets-service/repositories/items"
 	materials_repo "github.com/FeedTheRealm-org/core-service/internal/assets-service/repositories/materials"
 	models_repo "github.com/FeedTheRealm-org/core-service/internal/assets-service/repositories/models"
+	promotions_repo "github.com/FeedTheRealm-org/core-service/internal/assets-service/repositories/promotions"
+	user_data_repo "github.com/FeedTheRealm-org/core-service/internal/assets-service/repositories/user_data"
+	bundles_service "github.com/FeedTheRealm-org/core-service/internal/assets-service/services/bundles"
 	cosmetics_service "github.com/FeedTheRealm-org/core-service/internal/assets-service/services/cosmetics"
 	items_service "github.com/FeedTheRealm-org/core-service/internal/assets-service/services/items"
 	materials_service "github.com/FeedTheRealm-org/core-service/internal/assets-service/services/materials"
 	models_service "github.com/FeedTheRealm-org/core-service/internal/assets-service/services/models"
+	promotions_service "github.com/FeedTheRealm-org/core-service/internal/assets-service/services/promotions"
+	user_data_service "github.com/FeedTheRealm-org/core-service/internal/assets-service/services/user_data"
+	"github.com/FeedTheRealm-org/core-service/internal/common_handlers"
 	"github.com/FeedTheRealm-org/core-service/internal/middleware"
+	"github.com/FeedTheRealm-org/core-service/internal/service_clients"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/permissions"
 	"github.com/gin-gonic/gin"
 )
 
-func SetupEndpointsForCosmeticsService(conf *config.Config, db *config.DB, g *gin.RouterGroup, cosmeticsBucketRepo bucket.BucketRepository) {
+func SetupEndpointsForCosmeticsService(conf *config.Config, db *config.DB, g *gin.RouterGroup, internalGroup *gin.RouterGroup, cosmeticsBucketRepo bucket.BucketRepository, clients *service_clients.Clients) {
 	cosmeticsRepo := cosmetics_repo.NewCosmeticsRepository(conf, db)
+	bundlesRepo := bundles_repo.NewBundlesRepository(conf, db)
+	promotionsRepo := promotions_repo.NewPromotionsRepository(conf, db)
 	cosmeticsService := cosmetics_service.NewCosmeticsService(conf, cosmeticsRepo, cosmeticsBucketRepo)
-	cosmeticsController := cosmetics_controller.NewCosmeticsController(conf, cosmeticsService)
+	bundlesService := bundles_service.NewBundlesService(conf, bundlesRepo, cosmeticsRepo)
+	promotionsService := promotions_service.NewPromotionsService(conf, promotionsRepo, cosmeticsRepo, bundlesRepo)
+	clients.ProvideAssets(cosmetics_service.NewAssetsClient(cosmeticsService, bundlesService, promotionsService))
+	cosmeticsController := cosmetics_controller.NewCosmeticsController(conf, cosmeticsService, promotionsService)
+	bundlesController := bundles_controller.NewBundlesController(conf, bundlesService, promotionsService)
+	promotionsController := promotions_controller.NewPromotionsController(conf, promotionsService)
 
 	/* Cosmetics Endpoints */
 	cosmeticsGroup := g.Group("/cosmetics")
@@ -34,13 +52,37 @@ func SetupEndpointsForCosmeticsService(conf *config.Config, db *config.DB, g *gi
 	cosmeticsGroup.PUT("/categories/:category_id", cosmeticsController.UploadCosmeticData)
 	cosmeticsGroup.PUT("/categories/:category_id/sprites/:sprite_id", cosmeticsController.UploadCosmeticByID)
 
-	/* ADMIN ONLY */
-	cosmeticsGroup.POST("/categories", middleware.AdminCheckMiddleware(), cosmeticsController.AddCategory)
-	cosmeticsGroup.DELETE(":id", middleware.AdminCheckMiddleware(), cosmeticsController.DeleteCosmetic)
+	/* STAFF ONLY */
+	cosmeticsGroup.POST("/categories", middleware.RequirePermission(permissions.ASSETS_CATEGORIES_WRITE), middleware.AuditMiddleware(clients.Audit, "assets.categories.create", "cosmetic_category", ""), cosmeticsController.AddCategory)
+	cosmeticsGroup.DELETE(":id", middleware.RequirePermission(permissions.ASSETS_CONTENT_DELETE), middleware.AuditMiddleware(clients.Audit, "assets.cosmetics.delete", "cosmetic", "id"), cosmeticsController.DeleteCosmetic)
+
+	/* Bundles Endpoints */
+	bundlesGroup := g.Group("/bundles")
+	bundlesGroup.POST("", bundlesController.CreateBundle)
+	bundlesGroup.GET(":id", bundlesController.GetBundleById)
+	bundlesGroup.GET("/worlds/:world_id", bundlesController.GetBundlesListByWorld)
+	bundlesGroup.DELETE(":id", bundlesController.DeleteBundle)
+
+	/* Promotions Endpoints */
+	promotionsGroup := g.Group("/promotions")
+	promotionsGroup.POST("", promotionsController.CreatePromotion)
+	promotionsGroup.GET("/worlds/:world_id", promotionsController.GetPromotionsListByWorld)
+	promotionsGroup.DELETE(":id", promotionsController.DeletePromotion)
+
+	/* Internal Endpoints, only used when services are split out */
+	internalGroup.GET("/cosmetics/:cosmetic_id", cosmeticsController.GetCosmeticByIdInternal)
+	internalGroup.POST("/users/:user_id/cosmetics", cosmeticsController.PurshaseCosmeticForUserInternal)
+	internalGroup.GET("/bundles/:bundle_id", bundlesController.GetBundleByIdInternal)
+	internalGroup.POST("/users/:user_id/bundles", bundlesController.GrantBundleForUserInternal)
+}
+
+func SetupEndpointsForUserData(conf *config.Config, db *config.DB, internalGroup *gin.RouterGroup, clients *service_clients.Clients) {
+	userDataClient := user_data_service.NewUserDataClient(user_data_repo.NewUserDataRepository(conf, db))
+	clients.ProvideUserData(service_clients.AssetsUserData, userDataClient)
 
-	/* Internal Endpoints */
-	g.GET("/internal/cosmetics/:cosmetic_id", cosmeticsController.GetCosmeticByIdInternal)
-	g.POST("/internal/users/:user_id/cosmetics", cosmeticsController.PurshaseCosmeticForUserInternal)
+	/* Internal Endpoints used by the authentication-service to export and erase an account */
+	internalGroup.GET("/users/:user_id/data", common_handlers.ExportUserDataController(userDataClient))
+	internalGroup.DELETE("/users/:user_id/data", common_handlers.EraseUserDataController(userDataClient))
 }
 
 func SetupEndpointsForItemsService(conf *config.Config, db *config.DB, g *gin.RouterGroup, itemsBucketRepo bucket.BucketRepository) {
@@ -84,8 +126,9 @@ func getNewBucketRepository(name string, conf *config.Config) (bucket.BucketRepo
 	return bucket.NewAwsS3BucketRepository(name, conf)
 }
 
-func SetupAssetsServiceRouter(r *gin.Engine, conf *config.Config, db *config.DB) error {
+func SetupAssetsServiceRouter(r *gin.Engine, internal *gin.RouterGroup, conf *config.Config, db *config.DB, clients *service_clients.Clients) error {
 	g := r.Group("/assets")
+	internalGroup := internal.Group("/assets/internal")
 
 	cosmeticsBucketRepo, err := getNewBucketRepository(conf.Assets.CosmeticsBucketName, conf)
 	if err != nil {
@@ -98,7 +141,10 @@ func SetupAssetsServiceRouter(r *gin.Engine, conf *config.Config, db *config.DB)
 	}
 
 	/* Cosmetics endpoints */
-	SetupEndpointsForCosmeticsService(conf, db, g, cosmeticsBucketRepo)
+	SetupEndpointsForCosmeticsService(conf, db, g, internalGroup, cosmeticsBucketRepo, clients)
+
+	/* User data endpoints */
+	SetupEndpointsForUserData(conf, db, internalGroup, clients)
 
 	/* Items endpoints */
 	SetupEndpointsForItemsService(conf, db, g, worldsBucketRepo)
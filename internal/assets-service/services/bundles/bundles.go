@@ -0,0 +1,104 @@
+package bundles
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/FeedTheRealm-org/core-service/config"
+	assets_errors "github.com/FeedTheRealm-org/core-service/internal/assets-service/errors"
+	"github.com/FeedTheRealm-org/core-service/internal/assets-service/models"
+	"github.com/FeedTheRealm-org/core-service/internal/assets-service/repositories/bundles"
+	"github.com/FeedTheRealm-org/core-service/internal/assets-service/repositories/cosmetics"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/logger"
+	"github.com/google/uuid"
+)
+
+const (
+	MIN_BUNDLE_COSMETICS   = 2
+	MAX_BUNDLE_COSMETICS   = 50
+	MAX_BUNDLE_NAME_LENGTH = 64
+)
+
+type bundlesService struct {
+	conf *config.Config
+
+	bundlesRepository   bundles.BundlesRepository
+	cosmeticsRepository cosmetics.CosmeticsRepository
+}
+
+// NewBundlesService creates a new instance of BundlesService.
+func NewBundlesService(conf *config.Config, bundlesRepository bundles.BundlesRepository, cosmeticsRepository cosmetics.CosmeticsRepository) BundlesService {
+	return &bundlesService{
+		conf:                conf,
+		bundlesRepository:   bundlesRepository,
+		cosmeticsRepository: cosmeticsRepository,
+	}
+}
+
+func (bs *bundlesService) CreateBundle(worldId uuid.UUID, name string, price int64, cosmeticIds []uuid.UUID, userId uuid.UUID) (*models.CosmeticBundle, error) {
+	if price <= 0 {
+		return nil, assets_errors.NewInvalidPrice("price must be greater than 0")
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > MAX_BUNDLE_NAME_LENGTH {
+		return nil, assets_errors.NewInvalidBundle(fmt.Sprintf("name must be between 1 and %d characters", MAX_BUNDLE_NAME_LENGTH))
+	}
+
+	unique := make([]uuid.UUID, 0, len(cosmeticIds))
+	seen := make(map[uuid.UUID]bool, len(cosmeticIds))
+	for _, cosmeticId := range cosmeticIds {
+		if !seen[cosmeticId] {
+			seen[cosmeticId] = true
+			unique = append(unique, cosmeticId)
+		}
+	}
+	if len(unique) < MIN_BUNDLE_COSMETICS || len(unique) > MAX_BUNDLE_COSMETICS {
+		return nil, assets_errors.NewInvalidBundle(fmt.Sprintf("a bundle must contain between %d and %d different cosmetics", MIN_BUNDLE_COSMETICS, MAX_BUNDLE_COSMETICS))
+	}
+
+	for _, cosmeticId := range unique {
+		cosmetic, err := bs.cosmeticsRepository.GetCosmeticById(cosmeticId)
+		if err != nil {
+			return nil, err
+		}
+		if cosmetic.WorldID != worldId {
+			return nil, assets_errors.NewInvalidBundle(fmt.Sprintf("cosmetic %s does not belong to the bundle world", cosmeticId))
+		}
+	}
+
+	bundle := &models.CosmeticBundle{
+		WorldID:   worldId,
+		Name:      name,
+		Price:     price,
+		CreatedBy: userId,
+	}
+	if err := bs.bundlesRepository.CreateBundle(bundle, unique); err != nil {
+		logger.Logger.Errorf("Error creating bundle: %v", err)
+		return nil, err
+	}
+
+	return bundle, nil
+}
+
+func (bs *bundlesService) GetBundleById(bundleId uuid.UUID) (*models.CosmeticBundle, error) {
+	return bs.bundlesRepository.GetBundleById(bundleId)
+}
+
+func (bs *bundlesService) GetBundlesListByWorld(worldId uuid.UUID) ([]*models.CosmeticBundle, error) {
+	return bs.bundlesRepository.GetBundlesListByWorld(worldId)
+}
+
+func (bs *bundlesService) DeleteBundle(bundleId uuid.UUID) error {
+	return bs.bundlesRepository.DeleteBundle(bundleId)
+}
+
+func (bs *bundlesService) GrantBundleForUserInternal(userId uuid.UUID, bundleId uuid.UUID) ([]uuid.UUID, error) {
+	granted, err := bs.bundlesRepository.GrantBundleForUserId(bundleId, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Logger.Infof("Granted %d cosmetics of bundle %s to user %s", len(granted), bundleId, userId)
+	return granted, nil
+}
@@ -0,0 +1,167 @@
+package bundles_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/FeedTheRealm-org/core-service/config"
+	assets_errors "github.com/FeedTheRealm-org/core-service/internal/assets-service/errors"
+	"github.com/FeedTheRealm-org/core-service/internal/assets-service/models"
+	"github.com/FeedTheRealm-org/core-service/internal/assets-service/repositories/cosmetics"
+	bundleservice "github.com/FeedTheRealm-org/core-service/internal/assets-service/services/bundles"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/logger"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	logger.InitLogger(false)
+	os.Exit(m.Run())
+}
+
+// fakeCosmeticsRepo only implements the lookups the bundles service relies on.
+type fakeCosmeticsRepo struct {
+	cosmetics.CosmeticsRepository
+	items map[uuid.UUID]*models.Cosmetic
+}
+
+func (f *fakeCosmeticsRepo) GetCosmeticById(cosmeticId uuid.UUID) (*models.Cosmetic, error) {
+	cosmetic, ok := f.items[cosmeticId]
+	if !ok {
+		return nil, assets_errors.NewCosmeticNotFound("cosmetic not found")
+	}
+	return cosmetic, nil
+}
+
+type fakeBundlesRepo struct {
+	created     *models.CosmeticBundle
+	createdIds  []uuid.UUID
+	granted     []uuid.UUID
+	grantErr    error
+	createErr   error
+	getBundleFn func(uuid.UUID) (*models.CosmeticBundle, error)
+}
+
+func (f *fakeBundlesRepo) CreateBundle(bundle *models.CosmeticBundle, cosmeticIds []uuid.UUID) error {
+	if f.createErr != nil {
+		return f.createErr
+	}
+	bundle.Id = uuid.New()
+	f.created = bundle
+	f.createdIds = cosmeticIds
+	return nil
+}
+
+func (f *fakeBundlesRepo) GetBundleById(bundleId uuid.UUID) (*models.CosmeticBundle, error) {
+	if f.getBundleFn == nil {
+		return nil, assets_errors.NewBundleNotFound("bundle not found")
+	}
+	return f.getBundleFn(bundleId)
+}
+
+func (f *fakeBundlesRepo) GetBundlesListByWorld(worldId uuid.UUID) ([]*models.CosmeticBundle, error) {
+	return nil, nil
+}
+
+func (f *fakeBundlesRepo) DeleteBundle(bundleId uuid.UUID) error {
+	return nil
+}
+
+func (f *fakeBundlesRepo) GrantBundleForUserId(bundleId uuid.UUID, userId uuid.UUID) ([]uuid.UUID, error) {
+	if f.grantErr != nil {
+		return nil, f.grantErr
+	}
+	return f.granted, nil
+}
+
+func newWorldCosmetics(worldId uuid.UUID, count int) (*fakeCosmeticsRepo, []uuid.UUID) {
+	repo := &fakeCosmeticsRepo{items: map[uuid.UUID]*models.Cosmetic{}}
+	ids := make([]uuid.UUID, count)
+	for i := range ids {
+		ids[i] = uuid.New()
+		repo.items[ids[i]] = &models.Cosmetic{Id: ids[i], WorldID: worldId, Price: 10}
+	}
+	return repo, ids
+}
+
+func TestBundlesService_CreateBundle_Success(t *testing.T) {
+	worldId := uuid.New()
+	userId := uuid.New()
+	cosmeticsRepo, ids := newWorldCosmetics(worldId, 3)
+	bundlesRepo := &fakeBundlesRepo{}
+	service := bundleservice.NewBundlesService(config.CreateConfig(), bundlesRepo, cosmeticsRepo)
+
+	bundle, err := service.CreateBundle(worldId, "  Starter pack ", 25, append(ids, ids[0]), userId)
+	require.NoError(t, err)
+	assert.Equal(t, "Starter pack", bundle.Name)
+	assert.Equal(t, int64(25), bundle.Price)
+	assert.Equal(t, userId, bundle.CreatedBy)
+	assert.Equal(t, ids, bundlesRepo.createdIds, "duplicated cosmetics are only stored once")
+}
+
+func TestBundlesService_CreateBundle_InvalidPrice(t *testing.T) {
+	worldId := uuid.New()
+	cosmeticsRepo, ids := newWorldCosmetics(worldId, 2)
+	service := bundleservice.NewBundlesService(config.CreateConfig(), &fakeBundlesRepo{}, cosmeticsRepo)
+
+	_, err := service.CreateBundle(worldId, "pack", 0, ids, uuid.New())
+	assert.IsType(t, &assets_errors.InvalidPrice{}, err)
+}
+
+func TestBundlesService_CreateBundle_InvalidName(t *testing.T) {
+	worldId := uuid.New()
+	cosmeticsRepo, ids := newWorldCosmetics(worldId, 2)
+	service := bundleservice.NewBundlesService(config.CreateConfig(), &fakeBundlesRepo{}, cosmeticsRepo)
+
+	_, err := service.CreateBundle(worldId, "   ", 10, ids, uuid.New())
+	assert.IsType(t, &assets_errors.InvalidBundle{}, err)
+}
+
+func TestBundlesService_CreateBundle_NotEnoughCosmetics(t *testing.T) {
+	worldId := uuid.New()
+	cosmeticsRepo, ids := newWorldCosmetics(worldId, 1)
+	service := bundleservice.NewBundlesService(config.CreateConfig(), &fakeBundlesRepo{}, cosmeticsRepo)
+
+	_, err := service.CreateBundle(worldId, "pack", 10, []uuid.UUID{ids[0], ids[0]}, uuid.New())
+	assert.IsType(t, &assets_errors.InvalidBundle{}, err)
+}
+
+func TestBundlesService_CreateBundle_CosmeticFromAnotherWorld(t *testing.T) {
+	worldId := uuid.New()
+	cosmeticsRepo, ids := newWorldCosmetics(worldId, 2)
+	other := uuid.New()
+	cosmeticsRepo.items[other] = &models.Cosmetic{Id: other, WorldID: uuid.New()}
+	bundlesRepo := &fakeBundlesRepo{}
+	service := bundleservice.NewBundlesService(config.CreateConfig(), bundlesRepo, cosmeticsRepo)
+
+	_, err := service.CreateBundle(worldId, "pack", 10, append(ids, other), uuid.New())
+	assert.IsType(t, &assets_errors.InvalidBundle{}, err)
+	assert.Nil(t, bundlesRepo.created)
+}
+
+func TestBundlesService_CreateBundle_CosmeticNotFound(t *testing.T) {
+	worldId := uuid.New()
+	cosmeticsRepo, ids := newWorldCosmetics(worldId, 1)
+	service := bundleservice.NewBundlesService(config.CreateConfig(), &fakeBundlesRepo{}, cosmeticsRepo)
+
+	_, err := service.CreateBundle(worldId, "pack", 10, append(ids, uuid.New()), uuid.New())
+	assert.IsType(t, &assets_errors.CosmeticNotFound{}, err)
+}
+
+func TestBundlesService_GrantBundleForUserInternal(t *testing.T) {
+	granted := []uuid.UUID{uuid.New()}
+	service := bundleservice.NewBundlesService(config.CreateConfig(), &fakeBundlesRepo{granted: granted}, &fakeCosmeticsRepo{})
+
+	ids, err := service.GrantBundleForUserInternal(uuid.New(), uuid.New())
+	require.NoError(t, err)
+	assert.Equal(t, granted, ids)
+}
+
+func TestBundlesService_GrantBundleForUserInternal_AlreadyOwned(t *testing.T) {
+	repo := &fakeBundlesRepo{grantErr: assets_errors.NewBundleAlreadyOwned("already owned")}
+	service := bundleservice.NewBundlesService(config.CreateConfig(), repo, &fakeCosmeticsRepo{})
+
+	_, err := service.GrantBundleForUserInternal(uuid.New(), uuid.New())
+	assert.IsType(t, &assets_errors.BundleAlreadyOwned{}, err)
+}
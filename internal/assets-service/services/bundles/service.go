@@ -0,0 +1,23 @@
+package bundles
+
+import (
+	"github.com/FeedTheRealm-org/core-service/internal/assets-service/models"
+	"github.com/google/uuid"
+)
+
+type BundlesService interface {
+	// CreateBundle validates and stores a new bundle made of cosmetics from the given world.
+	CreateBundle(worldId uuid.UUID, name string, price int64, cosmeticIds []uuid.UUID, userId uuid.UUID) (*models.CosmeticBundle, error)
+
+	// GetBundleById retrieves a bundle and its cosmetics by its ID.
+	GetBundleById(bundleId uuid.UUID) (*models.CosmeticBundle, error)
+
+	// GetBundlesListByWorld retrieves the bundles sold in a world.
+	GetBundlesListByWorld(worldId uuid.UUID) ([]*models.CosmeticBundle, error)
+
+	// DeleteBundle handles the deletion of a bundle by its ID.
+	DeleteBundle(bundleId uuid.UUID) error
+
+	// GrantBundleForUserInternal grants every cosmetic of the bundle the user does not own yet and returns them.
+	GrantBundleForUserInternal(userId uuid.UUID, bundleId uuid.UUID) ([]uuid.UUID, error)
+}
@@ -0,0 +1,104 @@
+package cosmetics
+
+import (
+	assets_errors "github.com/FeedTheRealm-org/core-service/internal/assets-service/errors"
+	"github.com/FeedTheRealm-org/core-service/internal/assets-service/services/bundles"
+	"github.com/FeedTheRealm-org/core-service/internal/assets-service/services/promotions"
+	"github.com/FeedTheRealm-org/core-service/internal/service_clients"
+	"github.com/google/uuid"
+)
+
+type assetsClient struct {
+	cosmeticsService  CosmeticsService
+	bundlesService    bundles.BundlesService
+	promotionsService promotions.PromotionsService
+}
+
+// NewAssetsClient exposes the cosmetics service to the other services running in this process.
+func NewAssetsClient(cosmeticsService CosmeticsService, bundlesService bundles.BundlesService, promotionsService promotions.PromotionsService) service_clients.AssetsClient {
+	return &assetsClient{
+		cosmeticsService:  cosmeticsService,
+		bundlesService:    bundlesService,
+		promotionsService: promotionsService,
+	}
+}
+
+func (c *assetsClient) GetCosmetic(cosmeticId uuid.UUID) (*service_clients.CosmeticInfo, error) {
+	cosmetic, err := c.cosmeticsService.GetCosmeticById(cosmeticId)
+	if err != nil {
+		if _, ok := err.(*assets_errors.CosmeticNotFound); ok {
+			return nil, service_clients.NewCosmeticNotFound("cosmetic not found")
+		}
+		return nil, err
+	}
+
+	price, err := c.promotionsService.ResolveCosmeticPrice(cosmetic)
+	if err != nil {
+		return nil, err
+	}
+
+	return &service_clients.CosmeticInfo{
+		CosmeticId: cosmetic.Id,
+		Price:      price.Price,
+		CreatedBy:  cosmetic.CreatedBy,
+		WorldID:    cosmetic.WorldID,
+	}, nil
+}
+
+func (c *assetsClient) GrantCosmetic(userId uuid.UUID, cosmeticId uuid.UUID) error {
+	return c.grantCosmetic(userId, cosmeticId, nil)
+}
+
+func (c *assetsClient) GiftCosmetic(recipientId uuid.UUID, cosmeticId uuid.UUID, gifterId uuid.UUID) error {
+	return c.grantCosmetic(recipientId, cosmeticId, &gifterId)
+}
+
+func (c *assetsClient) grantCosmetic(userId uuid.UUID, cosmeticId uuid.UUID, giftedBy *uuid.UUID) error {
+	err := c.cosmeticsService.PurchaseCosmeticForUserInternal(userId, cosmeticId, giftedBy)
+	if err != nil {
+		if _, ok := err.(*assets_errors.CosmeticsWasPurchasedBefore); ok {
+			return service_clients.NewCosmeticAlreadyOwned("cosmetic was already purchased by this user")
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (c *assetsClient) GetBundle(bundleId uuid.UUID) (*service_clients.BundleInfo, error) {
+	bundle, err := c.bundlesService.GetBundleById(bundleId)
+	if err != nil {
+		if _, ok := err.(*assets_errors.BundleNotFound); ok {
+			return nil, service_clients.NewBundleNotFound("bundle not found")
+		}
+		return nil, err
+	}
+
+	price, err := c.promotionsService.ResolveBundlePrice(bundle)
+	if err != nil {
+		return nil, err
+	}
+
+	return &service_clients.BundleInfo{
+		BundleId:    bundle.Id,
+		Price:       price.Price,
+		CreatedBy:   bundle.CreatedBy,
+		WorldID:     bundle.WorldID,
+		CosmeticIds: bundle.CosmeticIds(),
+	}, nil
+}
+
+func (c *assetsClient) GrantBundle(userId uuid.UUID, bundleId uuid.UUID) ([]uuid.UUID, error) {
+	granted, err := c.bundlesService.GrantBundleForUserInternal(userId, bundleId)
+	if err != nil {
+		switch err.(type) {
+		case *assets_errors.BundleNotFound:
+			return nil, service_clients.NewBundleNotFound("bundle not found")
+		case *assets_errors.BundleAlreadyOwned:
+			return nil, service_clients.NewBundleAlreadyOwned("every cosmetic of the bundle is already owned by this user")
+		}
+		return nil, err
+	}
+
+	return granted, nil
+}
@@ -27,7 +27,7 @@ type fakeCosmeticsRepo struct {
 	getCosmeticByIdFn               func(uuid.UUID) (*models.Cosmetic, error)
 	getCosmeticsListByWorldFn       func(uuid.UUID, int, int) ([]*models.Cosmetic, int64, error)
 	addCategoryFn                   func(string) (*models.CosmeticCategory, error)
-	addPurchaseForUserIdFn          func(uuid.UUID, uuid.UUID) error
+	addPurchaseForUserIdFn          func(uuid.UUID, uuid.UUID, *uuid.UUID) error
 	createCosmeticFn                func(uuid.UUID, uuid.UUID, int64, *models.Cosmetic, uuid.UUID) error
 	getCosmeticByUrlCategoryWorldFn func(string, uuid.UUID, uuid.UUID) (*models.Cosmetic, error)
 	updateCosmeticFn                func(uuid.UUID, int64, string) error
@@ -76,11 +76,11 @@ func (f *fakeCosmeticsRepo) AddCategory(category string) (*models.CosmeticCatego
 	return f.addCategoryFn(category)
 }
 
-func (f *fakeCosmeticsRepo) AddPurchaseForUserId(cosmeticId uuid.UUID, userId uuid.UUID) error {
+func (f *fakeCosmeticsRepo) AddPurchaseForUserId(cosmeticId uuid.UUID, userId uuid.UUID, giftedBy *uuid.UUID) error {
 	if f.addPurchaseForUserIdFn == nil {
 		panic("AddPurchaseForUserId not set")
 	}
-	return f.addPurchaseForUserIdFn(cosmeticId, userId)
+	return f.addPurchaseForUserIdFn(cosmeticId, userId, giftedBy)
 }
 
 func (f *fakeCosmeticsRepo) GetCategoryById(categoryId uuid.UUID) (*models.CosmeticCategory, error) {
@@ -278,14 +278,14 @@ func TestCosmeticsService_DeleteCosmetic_BucketError(t *testing.T) {
 
 func TestCosmeticsService_PurchaseCosmeticForUserInternal_Error(t *testing.T) {
 	repo := &fakeCosmeticsRepo{
-		addPurchaseForUserIdFn: func(cosmeticId uuid.UUID, userId uuid.UUID) error {
+		addPurchaseForUserIdFn: func(cosmeticId uuid.UUID, userId uuid.UUID, giftedBy *uuid.UUID) error {
 			return assert.AnError
 		},
 	}
 	bucket := &fakeCosmeticsBucketRepo{}
 	service := cosmeticservice.NewCosmeticsService(nil, repo, bucket)
 
-	err := service.PurchaseCosmeticForUserInternal(uuid.New(), uuid.New())
+	err := service.PurchaseCosmeticForUserInternal(uuid.New(), uuid.New(), nil)
 	assert.Error(t, err)
 }
 
@@ -493,7 +493,7 @@ func TestCosmeticsService_UploadCosmeticByID_CreateNew(t *testing.T) {
 func TestCosmeticsService_PurchaseCosmeticForUserInternal_Success(t *testing.T) {
 	called := false
 	repo := &fakeCosmeticsRepo{
-		addPurchaseForUserIdFn: func(cosmeticId uuid.UUID, userId uuid.UUID) error {
+		addPurchaseForUserIdFn: func(cosmeticId uuid.UUID, userId uuid.UUID, giftedBy *uuid.UUID) error {
 			called = true
 			return nil
 		},
@@ -501,7 +501,24 @@ func TestCosmeticsService_PurchaseCosmeticForUserInternal_Success(t *testing.T)
 	bucket := &fakeCosmeticsBucketRepo{}
 	service := cosmeticservice.NewCosmeticsService(nil, repo, bucket)
 
-	err := service.PurchaseCosmeticForUserInternal(uuid.New(), uuid.New())
+	err := service.PurchaseCosmeticForUserInternal(uuid.New(), uuid.New(), nil)
 	assert.NoError(t, err)
 	assert.True(t, called)
 }
+
+func TestCosmeticsService_PurchaseCosmeticForUserInternal_Gift(t *testing.T) {
+	gifterId := uuid.New()
+	var receivedGiftedBy *uuid.UUID
+	repo := &fakeCosmeticsRepo{
+		addPurchaseForUserIdFn: func(cosmeticId uuid.UUID, userId uuid.UUID, giftedBy *uuid.UUID) error {
+			receivedGiftedBy = giftedBy
+			return nil
+		},
+	}
+	bucket := &fakeCosmeticsBucketRepo{}
+	service := cosmeticservice.NewCosmeticsService(nil, repo, bucket)
+
+	err := service.PurchaseCosmeticForUserInternal(uuid.New(), uuid.New(), &gifterId)
+	assert.NoError(t, err)
+	assert.Equal(t, &gifterId, receivedGiftedBy)
+}
@@ -36,6 +36,6 @@ type CosmeticsService interface {
 	// AddCategory handles the addition of a new cosmetic category.
 	AddCategory(category string) (*models.CosmeticCategory, error)
 
-	// PurchaseCosmeticForUserInternal handles the purchase of a cosmetic for a user.
-	PurchaseCosmeticForUserInternal(userId uuid.UUID, cosmeticId uuid.UUID) error
+	// PurchaseCosmeticForUserInternal handles the purchase of a cosmetic for a user, giftedBy is set when another player paid for it.
+	PurchaseCosmeticForUserInternal(userId uuid.UUID, cosmeticId uuid.UUID, giftedBy *uuid.UUID) error
 }
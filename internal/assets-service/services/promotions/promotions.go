@@ -0,0 +1,124 @@
+package promotions
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/FeedTheRealm-org/core-service/config"
+	assets_errors "github.com/FeedTheRealm-org/core-service/internal/assets-service/errors"
+	"github.com/FeedTheRealm-org/core-service/internal/assets-service/models"
+	"github.com/FeedTheRealm-org/core-service/internal/assets-service/repositories/bundles"
+	"github.com/FeedTheRealm-org/core-service/internal/assets-service/repositories/cosmetics"
+	"github.com/FeedTheRealm-org/core-service/internal/assets-service/repositories/promotions"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/logger"
+	"github.com/google/uuid"
+)
+
+type promotionsService struct {
+	conf *config.Config
+
+	promotionsRepository promotions.PromotionsRepository
+	cosmeticsRepository  cosmetics.CosmeticsRepository
+	bundlesRepository    bundles.BundlesRepository
+}
+
+// NewPromotionsService creates a new instance of PromotionsService.
+func NewPromotionsService(
+	conf *config.Config,
+	promotionsRepository promotions.PromotionsRepository,
+	cosmeticsRepository cosmetics.CosmeticsRepository,
+	bundlesRepository bundles.BundlesRepository,
+) PromotionsService {
+	return &promotionsService{
+		conf:                 conf,
+		promotionsRepository: promotionsRepository,
+		cosmeticsRepository:  cosmeticsRepository,
+		bundlesRepository:    bundlesRepository,
+	}
+}
+
+func (ps *promotionsService) CreatePromotion(promotion *models.CosmeticPromotion) (*models.CosmeticPromotion, error) {
+	if promotion.PercentOff < models.MIN_PROMOTION_PERCENT_OFF || promotion.PercentOff > models.MAX_PROMOTION_PERCENT_OFF {
+		return nil, assets_errors.NewInvalidPromotion(fmt.Sprintf("percent_off must be between %d and %d", models.MIN_PROMOTION_PERCENT_OFF, models.MAX_PROMOTION_PERCENT_OFF))
+	}
+	if !promotion.EndsAt.After(promotion.StartsAt) {
+		return nil, assets_errors.NewInvalidPromotion("ends_at must be after starts_at")
+	}
+	if !promotion.EndsAt.After(time.Now()) {
+		return nil, assets_errors.NewInvalidPromotion("ends_at must be in the future")
+	}
+	if promotion.CosmeticID != nil && promotion.BundleID != nil {
+		return nil, assets_errors.NewInvalidPromotion("a promotion targets either a cosmetic or a bundle, not both")
+	}
+
+	if promotion.CosmeticID != nil {
+		cosmetic, err := ps.cosmeticsRepository.GetCosmeticById(*promotion.CosmeticID)
+		if err != nil {
+			return nil, err
+		}
+		if cosmetic.WorldID != promotion.WorldID {
+			return nil, assets_errors.NewInvalidPromotion("cosmetic does not belong to the promotion world")
+		}
+	}
+	if promotion.BundleID != nil {
+		bundle, err := ps.bundlesRepository.GetBundleById(*promotion.BundleID)
+		if err != nil {
+			return nil, err
+		}
+		if bundle.WorldID != promotion.WorldID {
+			return nil, assets_errors.NewInvalidPromotion("bundle does not belong to the promotion world")
+		}
+	}
+
+	if err := ps.promotionsRepository.CreatePromotion(promotion); err != nil {
+		logger.Logger.Errorf("Error creating promotion: %v", err)
+		return nil, err
+	}
+
+	return promotion, nil
+}
+
+func (ps *promotionsService) GetPromotionById(promotionId uuid.UUID) (*models.CosmeticPromotion, error) {
+	return ps.promotionsRepository.GetPromotionById(promotionId)
+}
+
+func (ps *promotionsService) GetPromotionsListByWorld(worldId uuid.UUID, activeOnly bool) ([]*models.CosmeticPromotion, error) {
+	if activeOnly {
+		now := time.Now()
+		return ps.promotionsRepository.GetPromotionsListByWorld(worldId, &now)
+	}
+	return ps.promotionsRepository.GetPromotionsListByWorld(worldId, nil)
+}
+
+func (ps *promotionsService) DeletePromotion(promotionId uuid.UUID) error {
+	return ps.promotionsRepository.DeletePromotion(promotionId)
+}
+
+func (ps *promotionsService) ResolveCosmeticPrice(cosmetic *models.Cosmetic) (*models.ResolvedPrice, error) {
+	promotion, err := ps.promotionsRepository.GetBestActivePromotion(cosmetic.WorldID, &cosmetic.Id, nil, time.Now())
+	if err != nil {
+		logger.Logger.Errorf("Error resolving promotions for cosmetic %s: %v", cosmetic.Id, err)
+		return nil, err
+	}
+	return resolvePrice(cosmetic.Price, promotion), nil
+}
+
+func (ps *promotionsService) ResolveBundlePrice(bundle *models.CosmeticBundle) (*models.ResolvedPrice, error) {
+	promotion, err := ps.promotionsRepository.GetBestActivePromotion(bundle.WorldID, nil, &bundle.Id, time.Now())
+	if err != nil {
+		logger.Logger.Errorf("Error resolving promotions for bundle %s: %v", bundle.Id, err)
+		return nil, err
+	}
+	return resolvePrice(bundle.Price, promotion), nil
+}
+
+func resolvePrice(basePrice int64, promotion *models.CosmeticPromotion) *models.ResolvedPrice {
+	resolved := &models.ResolvedPrice{BasePrice: basePrice, Price: basePrice}
+	if promotion == nil {
+		return resolved
+	}
+
+	resolved.Price = models.ApplyPercentOff(basePrice, promotion.PercentOff)
+	resolved.PromotionID = &promotion.Id
+	return resolved
+}
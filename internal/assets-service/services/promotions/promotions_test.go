@@ -0,0 +1,220 @@
+package promotions_test
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/FeedTheRealm-org/core-service/config"
+	assets_errors "github.com/FeedTheRealm-org/core-service/internal/assets-service/errors"
+	"github.com/FeedTheRealm-org/core-service/internal/assets-service/models"
+	"github.com/FeedTheRealm-org/core-service/internal/assets-service/repositories/bundles"
+	"github.com/FeedTheRealm-org/core-service/internal/assets-service/repositories/cosmetics"
+	promotionservice "github.com/FeedTheRealm-org/core-service/internal/assets-service/services/promotions"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/logger"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	logger.InitLogger(false)
+	os.Exit(m.Run())
+}
+
+type fakePromotionsRepo struct {
+	created   *models.CosmeticPromotion
+	best      *models.CosmeticPromotion
+	bestErr   error
+	lastQuery struct {
+		worldId    uuid.UUID
+		cosmeticId *uuid.UUID
+		bundleId   *uuid.UUID
+	}
+}
+
+func (f *fakePromotionsRepo) CreatePromotion(promotion *models.CosmeticPromotion) error {
+	promotion.Id = uuid.New()
+	f.created = promotion
+	return nil
+}
+
+func (f *fakePromotionsRepo) GetPromotionById(promotionId uuid.UUID) (*models.CosmeticPromotion, error) {
+	return nil, assets_errors.NewPromotionNotFound("promotion not found")
+}
+
+func (f *fakePromotionsRepo) GetPromotionsListByWorld(worldId uuid.UUID, activeAt *time.Time) ([]*models.CosmeticPromotion, error) {
+	return nil, nil
+}
+
+func (f *fakePromotionsRepo) DeletePromotion(promotionId uuid.UUID) error {
+	return nil
+}
+
+func (f *fakePromotionsRepo) GetBestActivePromotion(worldId uuid.UUID, cosmeticId *uuid.UUID, bundleId *uuid.UUID, at time.Time) (*models.CosmeticPromotion, error) {
+	f.lastQuery.worldId = worldId
+	f.lastQuery.cosmeticId = cosmeticId
+	f.lastQuery.bundleId = bundleId
+	return f.best, f.bestErr
+}
+
+// fakeCosmeticsRepo only implements the lookups the promotions service relies on.
+type fakeCosmeticsRepo struct {
+	cosmetics.CosmeticsRepository
+	items map[uuid.UUID]*models.Cosmetic
+}
+
+func (f *fakeCosmeticsRepo) GetCosmeticById(cosmeticId uuid.UUID) (*models.Cosmetic, error) {
+	cosmetic, ok := f.items[cosmeticId]
+	if !ok {
+		return nil, assets_errors.NewCosmeticNotFound("cosmetic not found")
+	}
+	return cosmetic, nil
+}
+
+// fakeBundlesRepo only implements the lookups the promotions service relies on.
+type fakeBundlesRepo struct {
+	bundles.BundlesRepository
+	items map[uuid.UUID]*models.CosmeticBundle
+}
+
+func (f *fakeBundlesRepo) GetBundleById(bundleId uuid.UUID) (*models.CosmeticBundle, error) {
+	bundle, ok := f.items[bundleId]
+	if !ok {
+		return nil, assets_errors.NewBundleNotFound("bundle not found")
+	}
+	return bundle, nil
+}
+
+func newService(promotionsRepo *fakePromotionsRepo, cosmeticsRepo *fakeCosmeticsRepo, bundlesRepo *fakeBundlesRepo) promotionservice.PromotionsService {
+	return promotionservice.NewPromotionsService(config.CreateConfig(), promotionsRepo, cosmeticsRepo, bundlesRepo)
+}
+
+func validPromotion(worldId uuid.UUID) *models.CosmeticPromotion {
+	return &models.CosmeticPromotion{
+		WorldID:    worldId,
+		PercentOff: 20,
+		StartsAt:   time.Now().Add(-time.Hour),
+		EndsAt:     time.Now().Add(24 * time.Hour),
+		CreatedBy:  uuid.New(),
+	}
+}
+
+func TestPromotionsService_CreatePromotion_WorldWide(t *testing.T) {
+	repo := &fakePromotionsRepo{}
+	service := newService(repo, &fakeCosmeticsRepo{}, &fakeBundlesRepo{})
+
+	promotion, err := service.CreatePromotion(validPromotion(uuid.New()))
+	require.NoError(t, err)
+	assert.Equal(t, repo.created, promotion)
+}
+
+func TestPromotionsService_CreatePromotion_InvalidPercent(t *testing.T) {
+	service := newService(&fakePromotionsRepo{}, &fakeCosmeticsRepo{}, &fakeBundlesRepo{})
+
+	for _, percent := range []int{0, 100, -5} {
+		promotion := validPromotion(uuid.New())
+		promotion.PercentOff = percent
+		_, err := service.CreatePromotion(promotion)
+		assert.IsType(t, &assets_errors.InvalidPromotion{}, err, "percent_off %d", percent)
+	}
+}
+
+func TestPromotionsService_CreatePromotion_InvalidWindow(t *testing.T) {
+	service := newService(&fakePromotionsRepo{}, &fakeCosmeticsRepo{}, &fakeBundlesRepo{})
+
+	reversed := validPromotion(uuid.New())
+	reversed.EndsAt = reversed.StartsAt
+	_, err := service.CreatePromotion(reversed)
+	assert.IsType(t, &assets_errors.InvalidPromotion{}, err)
+
+	past := validPromotion(uuid.New())
+	past.StartsAt = time.Now().Add(-48 * time.Hour)
+	past.EndsAt = time.Now().Add(-24 * time.Hour)
+	_, err = service.CreatePromotion(past)
+	assert.IsType(t, &assets_errors.InvalidPromotion{}, err)
+}
+
+func TestPromotionsService_CreatePromotion_BothTargets(t *testing.T) {
+	service := newService(&fakePromotionsRepo{}, &fakeCosmeticsRepo{}, &fakeBundlesRepo{})
+
+	promotion := validPromotion(uuid.New())
+	cosmeticId, bundleId := uuid.New(), uuid.New()
+	promotion.CosmeticID = &cosmeticId
+	promotion.BundleID = &bundleId
+	_, err := service.CreatePromotion(promotion)
+	assert.IsType(t, &assets_errors.InvalidPromotion{}, err)
+}
+
+func TestPromotionsService_CreatePromotion_TargetFromAnotherWorld(t *testing.T) {
+	cosmeticId := uuid.New()
+	bundleId := uuid.New()
+	cosmeticsRepo := &fakeCosmeticsRepo{items: map[uuid.UUID]*models.Cosmetic{cosmeticId: {Id: cosmeticId, WorldID: uuid.New()}}}
+	bundlesRepo := &fakeBundlesRepo{items: map[uuid.UUID]*models.CosmeticBundle{bundleId: {Id: bundleId, WorldID: uuid.New()}}}
+	repo := &fakePromotionsRepo{}
+	service := newService(repo, cosmeticsRepo, bundlesRepo)
+
+	promotion := validPromotion(uuid.New())
+	promotion.CosmeticID = &cosmeticId
+	_, err := service.CreatePromotion(promotion)
+	assert.IsType(t, &assets_errors.InvalidPromotion{}, err)
+
+	promotion = validPromotion(uuid.New())
+	promotion.BundleID = &bundleId
+	_, err = service.CreatePromotion(promotion)
+	assert.IsType(t, &assets_errors.InvalidPromotion{}, err)
+	assert.Nil(t, repo.created)
+}
+
+func TestPromotionsService_CreatePromotion_BundleNotFound(t *testing.T) {
+	service := newService(&fakePromotionsRepo{}, &fakeCosmeticsRepo{}, &fakeBundlesRepo{})
+
+	promotion := validPromotion(uuid.New())
+	bundleId := uuid.New()
+	promotion.BundleID = &bundleId
+	_, err := service.CreatePromotion(promotion)
+	assert.IsType(t, &assets_errors.BundleNotFound{}, err)
+}
+
+func TestPromotionsService_ResolveCosmeticPrice(t *testing.T) {
+	promotionId := uuid.New()
+	repo := &fakePromotionsRepo{best: &models.CosmeticPromotion{Id: promotionId, PercentOff: 25}}
+	service := newService(repo, &fakeCosmeticsRepo{}, &fakeBundlesRepo{})
+	cosmetic := &models.Cosmetic{Id: uuid.New(), WorldID: uuid.New(), Price: 10}
+
+	price, err := service.ResolveCosmeticPrice(cosmetic)
+	require.NoError(t, err)
+	assert.Equal(t, &models.ResolvedPrice{BasePrice: 10, Price: 8, PromotionID: &promotionId}, price)
+	assert.Equal(t, cosmetic.WorldID, repo.lastQuery.worldId)
+	assert.Equal(t, &cosmetic.Id, repo.lastQuery.cosmeticId)
+	assert.Nil(t, repo.lastQuery.bundleId)
+}
+
+func TestPromotionsService_ResolveCosmeticPrice_NoPromotion(t *testing.T) {
+	service := newService(&fakePromotionsRepo{}, &fakeCosmeticsRepo{}, &fakeBundlesRepo{})
+
+	price, err := service.ResolveCosmeticPrice(&models.Cosmetic{Id: uuid.New(), Price: 10})
+	require.NoError(t, err)
+	assert.Equal(t, &models.ResolvedPrice{BasePrice: 10, Price: 10}, price)
+}
+
+func TestPromotionsService_ResolveBundlePrice_NeverFree(t *testing.T) {
+	repo := &fakePromotionsRepo{best: &models.CosmeticPromotion{Id: uuid.New(), PercentOff: 99}}
+	service := newService(repo, &fakeCosmeticsRepo{}, &fakeBundlesRepo{})
+	bundle := &models.CosmeticBundle{Id: uuid.New(), WorldID: uuid.New(), Price: 40}
+
+	price, err := service.ResolveBundlePrice(bundle)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), price.Price)
+	assert.Equal(t, &bundle.Id, repo.lastQuery.bundleId)
+	assert.Nil(t, repo.lastQuery.cosmeticId)
+}
+
+func TestPromotionsService_ResolveBundlePrice_Error(t *testing.T) {
+	repo := &fakePromotionsRepo{bestErr: errors.New("db down")}
+	service := newService(repo, &fakeCosmeticsRepo{}, &fakeBundlesRepo{})
+
+	_, err := service.ResolveBundlePrice(&models.CosmeticBundle{Id: uuid.New(), Price: 40})
+	assert.EqualError(t, err, "db down")
+}
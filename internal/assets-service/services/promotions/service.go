@@ -0,0 +1,26 @@
+package promotions
+
+import (
+	"github.com/FeedTheRealm-org/core-service/internal/assets-service/models"
+	"github.com/google/uuid"
+)
+
+type PromotionsService interface {
+	// CreatePromotion validates and stores a new promotion for a world, cosmetic or bundle.
+	CreatePromotion(promotion *models.CosmeticPromotion) (*models.CosmeticPromotion, error)
+
+	// GetPromotionById retrieves a promotion by its ID.
+	GetPromotionById(promotionId uuid.UUID) (*models.CosmeticPromotion, error)
+
+	// GetPromotionsListByWorld lists the promotions of a world, optionally only the ones running right now.
+	GetPromotionsListByWorld(worldId uuid.UUID, activeOnly bool) ([]*models.CosmeticPromotion, error)
+
+	// DeletePromotion ends a promotion by removing it.
+	DeletePromotion(promotionId uuid.UUID) error
+
+	// ResolveCosmeticPrice returns the price of the cosmetic with the best active promotion applied.
+	ResolveCosmeticPrice(cosmetic *models.Cosmetic) (*models.ResolvedPrice, error)
+
+	// ResolveBundlePrice returns the price of the bundle with the best active promotion applied.
+	ResolveBundlePrice(bundle *models.CosmeticBundle) (*models.ResolvedPrice, error)
+}
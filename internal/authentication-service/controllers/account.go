@@ -1,17 +1,22 @@
 package controllers
 
 import (
+	"math"
 	"net/http"
+	"net/url"
 	"strconv"
 
 	"github.com/FeedTheRealm-org/core-service/config"
 	dtos "github.com/FeedTheRealm-org/core-service/internal/authentication-service/dtos"
+	"github.com/FeedTheRealm-org/core-service/internal/authentication-service/models"
 	"github.com/FeedTheRealm-org/core-service/internal/authentication-service/services"
 	"github.com/FeedTheRealm-org/core-service/internal/common_handlers"
 	"github.com/FeedTheRealm-org/core-service/internal/errors"
 	"github.com/FeedTheRealm-org/core-service/internal/utils/email_sender"
 	"github.com/FeedTheRealm-org/core-service/internal/utils/logger"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/permissions"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 type accountController struct {
@@ -28,6 +33,14 @@ func NewAccountController(conf *config.Config, accountService services.AccountSe
 	}
 }
 
+// sessionDevice describes the client making the request, it is stored on the device session.
+func sessionDevice(c *gin.Context) services.SessionDevice {
+	return services.SessionDevice{
+		UserAgent: c.Request.UserAgent(),
+		IPAddress: c.ClientIP(),
+	}
+}
+
 // @Summary      Create a new account
 // @Description  Creates a new user account with the provided email and password.
 // @Tags         authentication-service
@@ -38,6 +51,7 @@ func NewAccountController(conf *config.Config, accountService services.AccountSe
 // @Failure      400  {object} dtos.ErrorResponse
 // @Failure      401  {object} dtos.ErrorResponse
 // @Failure      409  {object} dtos.ErrorResponse
+// @Failure      429  {object} dtos.ErrorResponse
 // @Failure      500  {object} dtos.ErrorResponse
 // @Router       /auth/signup [post]
 func (ec *accountController) CreateAccount(c *gin.Context) {
@@ -112,15 +126,17 @@ func (ec *accountController) CreateAccount(c *gin.Context) {
 }
 
 // @Summary      Login account
-// @Description  Authenticates a user and returns an access token.
+// @Description  Authenticates a user and returns an access token. Accounts with two-factor authentication get a challenge token to finish the login at /auth/login/2fa instead.
 // @Tags         authentication-service
 // @Accept       json
 // @Produce      json
 // @Param        request body dtos.LoginAccountRequestDTO true "Login credentials"
 // @Success      200  {object}  dtos.LoginAccountResponseDTO
+// @Success      202  {object}  dtos.TwoFactorChallengeResponseDTO
 // @Failure      400  {object} dtos.ErrorResponse
 // @Failure      401  {object} dtos.ErrorResponse
 // @Failure      403  {object} dtos.ErrorResponse
+// @Failure      429  {object} dtos.ErrorResponse
 // @Failure      500  {object} dtos.ErrorResponse
 // @Router       /auth/login [post]
 func (ec *accountController) LoginAccount(c *gin.Context) {
@@ -151,7 +167,7 @@ func (ec *accountController) LoginAccount(c *gin.Context) {
 		isAdminReq = true
 	}
 
-	user, accessToken, refreshToken, err := ec.accountService.LoginAccount(req.Email, req.Password, isAdminReq)
+	user, accessToken, refreshToken, err := ec.accountService.LoginAccount(req.Email, req.Password, isAdminReq, sessionDevice(c))
 	if err != nil {
 		if _, ok := err.(*services.AccountNotFoundError); ok {
 			logger.Logger.Infof("LoginAccount: account not found for email=%s", req.Email)
@@ -159,6 +175,28 @@ func (ec *accountController) LoginAccount(c *gin.Context) {
 			return
 		}
 
+		if e, ok := err.(*services.TwoFactorRequiredError); ok {
+			logger.Logger.Infof("LoginAccount: two-factor code required for email=%s", req.Email)
+			common_handlers.HandleSuccessResponse(c, http.StatusAccepted, &dtos.TwoFactorChallengeResponseDTO{
+				TwoFactorRequired: true,
+				ChallengeToken:    e.ChallengeToken,
+				ExpiresIn:         int(e.ExpiresIn.Seconds()),
+			})
+			return
+		}
+
+		if e, ok := err.(*services.AccountLockedError); ok {
+			logger.Logger.Warnf("LoginAccount: account locked for email=%s", req.Email)
+			ec.rejectLockedLogin(c, e)
+			return
+		}
+
+		if e, ok := err.(*services.AccountDeletionScheduledError); ok {
+			logger.Logger.Infof("LoginAccount: account scheduled for deletion for email=%s", req.Email)
+			_ = c.Error(errors.NewForbiddenError(e.Error()))
+			return
+		}
+
 		if _, ok := err.(*services.AccountNotVerifiedError); ok {
 			logger.Logger.Infof("LoginAccount: account not verified for email=%s", req.Email)
 			_ = c.Error(errors.NewForbiddenError("You must verify your email address before you can log in."))
@@ -176,15 +214,19 @@ func (ec *accountController) LoginAccount(c *gin.Context) {
 
 	logger.Logger.Infof("LoginAccount: login successful for email=%s", req.Email)
 
-	res := &dtos.LoginAccountResponseDTO{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-		Id:           user.Id.String(),
-		Email:        user.Email,
-		CreatedAt:    user.CreatedAt,
-		UpdatedAt:    user.UpdatedAt,
+	common_handlers.HandleSuccessResponse(c, http.StatusOK, ec.loginResponse(user, accessToken, refreshToken))
+}
+
+func (ec *accountController) loginResponse(user *models.User, accessToken string, refreshToken string) *dtos.LoginAccountResponseDTO {
+	return &dtos.LoginAccountResponseDTO{
+		AccessToken:            accessToken,
+		RefreshToken:           refreshToken,
+		Id:                     user.Id.String(),
+		Email:                  user.Email,
+		CreatedAt:              user.CreatedAt,
+		UpdatedAt:              user.UpdatedAt,
+		TwoFactorSetupRequired: ec.accountService.TwoFactorSetupRequired(user),
 	}
-	common_handlers.HandleSuccessResponse(c, http.StatusOK, res)
 }
 
 // @Summary      Check session expiration
@@ -276,6 +318,7 @@ func (ec *accountController) CheckAdminSession(c *gin.Context) {
 // @Failure      400  {object} dtos.ErrorResponse
 // @Failure      401  {object} dtos.ErrorResponse
 // @Failure      404  {object} dtos.ErrorResponse
+// @Failure      429  {object} dtos.ErrorResponse
 // @Failure      500  {object} dtos.ErrorResponse
 // @Router       /auth/verify [post]
 func (ec *accountController) VerifyAccount(c *gin.Context) {
@@ -342,6 +385,7 @@ func (ec *accountController) VerifyAccount(c *gin.Context) {
 // @Success      200  {object}  dtos.RefreshVerificationResponseDTO
 // @Failure      400  {object} dtos.ErrorResponse
 // @Failure      404  {object} dtos.ErrorResponse
+// @Failure      429  {object} dtos.ErrorResponse
 // @Failure      500  {object} dtos.ErrorResponse
 // @Router       /auth/refresh [post]
 func (ec *accountController) RefreshVerification(c *gin.Context) {
@@ -399,6 +443,7 @@ func (ec *accountController) RefreshVerification(c *gin.Context) {
 // @Param        request body dtos.ForgotPasswordRequestDTO true "Email address"
 // @Success      200  {object}  dtos.ForgotPasswordResponseDTO
 // @Failure      400  {object} dtos.ErrorResponse
+// @Failure      429  {object} dtos.ErrorResponse
 // @Failure      500  {object} dtos.ErrorResponse
 // @Router       /auth/password/forgot [post]
 func (ec *accountController) ForgotPassword(c *gin.Context) {
@@ -436,6 +481,62 @@ func (ec *accountController) ForgotPassword(c *gin.Context) {
 	common_handlers.HandleSuccessResponse(c, http.StatusOK, &dtos.ForgotPasswordResponseDTO{Success: true})
 }
 
+// rejectLockedLogin answers 429 with Retry-After, emailing the unlock link on the login that triggered the lockout.
+func (ec *accountController) rejectLockedLogin(c *gin.Context, locked *services.AccountLockedError) {
+	ec.sendAccountLockedEmail(locked)
+	retryAfter := int(math.Ceil(locked.RetryAfter.Seconds()))
+	c.Header("Retry-After", strconv.Itoa(retryAfter))
+	_ = c.Error(errors.NewTooManyRequestsError("Too many failed login attempts. Try again later or use the unlock link sent to your email."))
+}
+
+func (ec *accountController) sendAccountLockedEmail(locked *services.AccountLockedError) {
+	if locked.UnlockToken == "" || ec.conf.Server.Environment == config.Testing {
+		return
+	}
+
+	unlockURL := ec.conf.RateLimit.AccountUnlockURL + "?token=" + url.QueryEscape(locked.UnlockToken)
+	if err := ec.emailService.SendAccountLockedEmail(email_sender.AccountLockedEmailData{
+		BaseEmailData: ec.emailService.CreateBaseEmailData(locked.Email),
+		UnlockURL:     unlockURL,
+		LockedMinutes: int(math.Ceil(locked.RetryAfter.Minutes())),
+	}); err != nil {
+		logger.Logger.Errorf("sendAccountLockedEmail: failed to send account locked email to email=%s: %v", locked.Email, err)
+	}
+}
+
+// @Summary      Unlock a locked account
+// @Description  Lifts a lockout caused by failed logins using the token emailed to the user.
+// @Tags         authentication-service
+// @Produce      json
+// @Param        token query string true "Unlock token from the email"
+// @Success      200  {object}  dtos.UnlockAccountResponseDTO
+// @Failure      400  {object} dtos.ErrorResponse
+// @Failure      429  {object} dtos.ErrorResponse
+// @Failure      500  {object} dtos.ErrorResponse
+// @Router       /auth/unlock [get]
+func (ec *accountController) UnlockAccount(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		_ = c.Error(errors.NewBadRequestError("You must provide an unlock token."))
+		return
+	}
+
+	if err := ec.accountService.UnlockAccount(token); err != nil {
+		switch err.(type) {
+		case *services.InvalidUnlockTokenError:
+			logger.Logger.Info("UnlockAccount: invalid or expired unlock token")
+			_ = c.Error(errors.NewBadRequestError(err.Error()))
+		default:
+			logger.Logger.Errorf("UnlockAccount: service error: %v", err)
+			_ = c.Error(errors.NewInternalServerError("An unexpected error occurred."))
+		}
+		return
+	}
+
+	logger.Logger.Info("UnlockAccount: account unlocked")
+	common_handlers.HandleSuccessResponse(c, http.StatusOK, &dtos.UnlockAccountResponseDTO{Success: true})
+}
+
 // @Summary      Verify password reset code
 // @Description  Validates the OTP sent to the user's email and returns a short-lived reset token on success.
 // @Tags         authentication-service
@@ -445,6 +546,7 @@ func (ec *accountController) ForgotPassword(c *gin.Context) {
 // @Success      200  {object}  dtos.VerifyPasswordCodeResponseDTO
 // @Failure      400  {object} dtos.ErrorResponse
 // @Failure      401  {object} dtos.ErrorResponse
+// @Failure      429  {object} dtos.ErrorResponse
 // @Failure      500  {object} dtos.ErrorResponse
 // @Router       /auth/password/verify-code [post]
 func (ec *accountController) VerifyPasswordCode(c *gin.Context) {
@@ -502,6 +604,7 @@ func (ec *accountController) VerifyPasswordCode(c *gin.Context) {
 // @Success      200  {object}  dtos.ResetPasswordResponseDTO
 // @Failure      400  {object} dtos.ErrorResponse
 // @Failure      401  {object} dtos.ErrorResponse
+// @Failure      429  {object} dtos.ErrorResponse
 // @Failure      500  {object} dtos.ErrorResponse
 // @Router       /auth/password/reset [post]
 func (ec *accountController) ResetPassword(c *gin.Context) {
@@ -551,6 +654,7 @@ func (ec *accountController) ResetPassword(c *gin.Context) {
 
 // @Summary      Refresh access token
 // @Description  Validates the provided refresh token and issues a new access token and refresh token pair.
+// @Description  The refresh token is rotated, presenting an already used refresh token revokes its session.
 // @Tags         authentication-service
 // @Accept       json
 // @Produce      json
@@ -559,7 +663,9 @@ func (ec *accountController) ResetPassword(c *gin.Context) {
 // @Success      200            {object}  dtos.RefreshTokenResponseDTO
 // @Failure      400            {object}  dtos.ErrorResponse
 // @Failure      401            {object}  dtos.ErrorResponse
+// @Failure      403            {object}  dtos.ErrorResponse
 // @Failure      404            {object}  dtos.ErrorResponse
+// @Failure      429  {object} dtos.ErrorResponse
 // @Failure      500            {object}  dtos.ErrorResponse
 // @Router       /auth/refresh-token [post]
 func (ec *accountController) RefreshToken(c *gin.Context) {
@@ -596,42 +702,185 @@ func (ec *accountController) RefreshToken(c *gin.Context) {
 		return
 	}
 
-	logger.Logger.Info("RefreshToken: received request to check session token from header")
-	err := ec.accountService.ValidateRefreshToken(token, req.Email)
+	newAccessToken, newRefreshToken, err := ec.accountService.RefreshToken(token, req.Email, sessionDevice(c))
 	if err != nil {
-		if _, ok := err.(*services.AccountSessionExpired); ok {
+		switch err.(type) {
+		case *services.AccountNotFoundError:
+			logger.Logger.Infof("RefreshToken: account not found for email=%s", req.Email)
+			_ = c.Error(errors.NewNotFoundError("No account exists with the provided email address."))
+		case *services.AccountSessionExpired:
 			logger.Logger.Info("RefreshToken: session token has expired")
 			_ = c.Error(errors.NewUnauthorizedError("Session has expired"))
-			return
+		case *services.AccountSessionInvalid:
+			logger.Logger.Info("RefreshToken: invalid or revoked session token")
+			_ = c.Error(errors.NewUnauthorizedError("Invalid session token"))
+		case *services.AccountNotVerifiedError:
+			_ = c.Error(errors.NewForbiddenError("You must verify your email address before you can log in."))
+		default:
+			logger.Logger.Errorf("RefreshToken: service error for email=%s: %v", req.Email, err)
+			_ = c.Error(errors.NewInternalServerError("An unexpected error occurred."))
 		}
-		logger.Logger.Errorf("RefreshToken: service error: %v", err)
+		return
+	}
+
+	logger.Logger.Infof("RefreshToken: token refreshed successfully for email=%s", req.Email)
+	res := &dtos.RefreshTokenResponseDTO{
+		AccessToken:  newAccessToken,
+		RefreshToken: newRefreshToken,
+	}
+	common_handlers.HandleSuccessResponse(c, http.StatusOK, res)
+}
+
+// @Summary      Logout
+// @Description  Revokes the device session of the access token used, its refresh token stops working.
+// @Tags         authentication-service
+// @Security     BearerAuth
+// @Produce      json
+// @Success      204  {string}  string "No Content"
+// @Failure      401  {object}  dtos.ErrorResponse
+// @Failure      500  {object}  dtos.ErrorResponse
+// @Router       /auth/logout [post]
+func (ec *accountController) Logout(c *gin.Context) {
+	userID, err := common_handlers.GetUserIDFromSession(c)
+	if err != nil {
 		_ = c.Error(errors.NewUnauthorizedError("Invalid session token"))
 		return
 	}
 
-	newAccessToken, newRefreshToken, err := ec.accountService.RefreshToken(req.Email)
+	sessionID, err := common_handlers.GetSessionIDFromSession(c)
 	if err != nil {
-		if _, ok := err.(*services.AccountNotFoundError); ok {
-			logger.Logger.Infof("RefreshToken: account not found for email=%s", req.Email)
-			_ = c.Error(errors.NewNotFoundError("No account exists with the provided email address."))
+		_ = c.Error(errors.NewUnauthorizedError("Invalid session token"))
+		return
+	}
+
+	if err := ec.accountService.Logout(userID, sessionID); err != nil {
+		logger.Logger.Errorf("Logout: failed to revoke session=%s for user=%s: %v", sessionID, userID, err)
+		_ = c.Error(errors.NewInternalServerError("An unexpected error occurred."))
+		return
+	}
+
+	logger.Logger.Infof("Logout: session=%s revoked for user=%s", sessionID, userID)
+	c.SetCookie("jwt", "", -1, "/swagger", "", true, true)
+	common_handlers.HandleBodilessResponse(c, http.StatusNoContent)
+}
+
+// @Summary      List sessions
+// @Description  Lists the active device sessions of the current user.
+// @Tags         authentication-service
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200  {object}  dtos.SessionsListResponseDTO
+// @Failure      401  {object}  dtos.ErrorResponse
+// @Failure      500  {object}  dtos.ErrorResponse
+// @Router       /auth/sessions [get]
+func (ec *accountController) ListSessions(c *gin.Context) {
+	userID, err := common_handlers.GetUserIDFromSession(c)
+	if err != nil {
+		_ = c.Error(errors.NewUnauthorizedError("Invalid session token"))
+		return
+	}
+	currentSessionID, _ := common_handlers.GetSessionIDFromSession(c)
+
+	sessions, err := ec.accountService.ListSessions(userID)
+	if err != nil {
+		logger.Logger.Errorf("ListSessions: failed to list sessions for user=%s: %v", userID, err)
+		_ = c.Error(errors.NewInternalServerError("Failed to list sessions."))
+		return
+	}
+
+	response := dtos.SessionsListResponseDTO{
+		Sessions: make([]dtos.SessionResponseDTO, len(sessions)),
+	}
+	for i, session := range sessions {
+		response.Sessions[i] = dtos.SessionResponseDTO{
+			ID:         session.Id.String(),
+			UserAgent:  session.UserAgent,
+			IPAddress:  session.IPAddress,
+			CreatedAt:  session.CreatedAt,
+			LastUsedAt: session.LastUsedAt,
+			ExpiresAt:  session.ExpiresAt,
+			Current:    session.Id == currentSessionID,
+		}
+	}
+
+	common_handlers.HandleSuccessResponse(c, http.StatusOK, response)
+}
+
+// @Summary      Revoke a session
+// @Description  Revokes one of the current user's device sessions, logging that device out.
+// @Tags         authentication-service
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      string  true  "Session UUID"
+// @Success      204  {string}  string "No Content"
+// @Failure      400  {object}  dtos.ErrorResponse
+// @Failure      401  {object}  dtos.ErrorResponse
+// @Failure      404  {object}  dtos.ErrorResponse
+// @Failure      500  {object}  dtos.ErrorResponse
+// @Router       /auth/sessions/{id} [delete]
+func (ec *accountController) RevokeSession(c *gin.Context) {
+	userID, err := common_handlers.GetUserIDFromSession(c)
+	if err != nil {
+		_ = c.Error(errors.NewUnauthorizedError("Invalid session token"))
+		return
+	}
+
+	sessionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		_ = c.Error(errors.NewBadRequestError("invalid session ID"))
+		return
+	}
+
+	if err := ec.accountService.RevokeSession(userID, sessionID); err != nil {
+		if _, ok := err.(*services.SessionNotFoundError); ok {
+			_ = c.Error(errors.NewNotFoundError("session not found"))
 			return
 		}
+		logger.Logger.Errorf("RevokeSession: failed to revoke session=%s for user=%s: %v", sessionID, userID, err)
+		_ = c.Error(errors.NewInternalServerError("Failed to revoke session."))
+		return
+	}
 
-		logger.Logger.Errorf("RefreshToken: service error for email=%s: %v", req.Email, err)
-		_ = c.Error(errors.NewInternalServerError("An unexpected error occurred."))
+	common_handlers.HandleBodilessResponse(c, http.StatusNoContent)
+}
+
+// @Summary      Revoke all sessions of a user
+// @Description  Logs a user out of every device. Requires auth.users.sessions.revoke.
+// @Tags         authentication-service
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      string  true  "User UUID"
+// @Success      204  {string}  string "No Content"
+// @Failure      400  {object}  dtos.ErrorResponse
+// @Failure      401  {object}  dtos.ErrorResponse
+// @Failure      404  {object}  dtos.ErrorResponse
+// @Failure      500  {object}  dtos.ErrorResponse
+// @Router       /auth/users/{id}/sessions [delete]
+func (ec *accountController) RevokeAllUserSessions(c *gin.Context) {
+	if err := common_handlers.HasPermission(c, permissions.USERS_SESSIONS_REVOKE); err != nil {
+		_ = c.Error(errors.NewUnauthorizedError(err.Error()))
 		return
 	}
 
-	logger.Logger.Infof("RefreshToken: token refreshed successfully for email=%s", req.Email)
-	res := &dtos.RefreshTokenResponseDTO{
-		AccessToken:  newAccessToken,
-		RefreshToken: newRefreshToken,
+	if err := ec.accountService.RevokeAllSessions(c.Param("id")); err != nil {
+		switch err.(type) {
+		case *services.AccountInvalidFormat:
+			_ = c.Error(errors.NewBadRequestError("invalid user ID"))
+		case *services.AccountNotFoundError:
+			_ = c.Error(errors.NewNotFoundError("user not found"))
+		default:
+			logger.Logger.Errorf("RevokeAllUserSessions: failed to revoke sessions for user=%s: %v", c.Param("id"), err)
+			_ = c.Error(errors.NewInternalServerError("Failed to revoke sessions."))
+		}
+		return
 	}
-	common_handlers.HandleSuccessResponse(c, http.StatusOK, res)
+
+	logger.Logger.Infof("RevokeAllUserSessions: all sessions revoked for user=%s", c.Param("id"))
+	common_handlers.HandleBodilessResponse(c, http.StatusNoContent)
 }
 
 // @Summary      List users
-// @Description  Returns a paginated list of users. Admin only.
+// @Description  Returns a paginated list of users. Requires auth.users.read.
 // @Tags         authentication-service
 // @Security     BearerAuth
 // @Produce      json
@@ -644,7 +893,7 @@ func (ec *accountController) RefreshToken(c *gin.Context) {
 // @Failure      500      {object}  dtos.ErrorResponse
 // @Router       /auth/users [get]
 func (ec *accountController) ListUsers(c *gin.Context) {
-	if err := common_handlers.IsAdminSession(c); err != nil {
+	if err := common_handlers.HasPermission(c, permissions.USERS_READ); err != nil {
 		_ = c.Error(errors.NewUnauthorizedError(err.Error()))
 		return
 	}
@@ -731,3 +980,31 @@ func (ec *accountController) UpdateAdminStatus(c *gin.Context) {
 
 	common_handlers.HandleBodilessResponse(c, http.StatusNoContent)
 }
+
+// @Summary      Get user (internal)
+// @Description  Retrieves the email of a user account, only reachable by other services.
+// @Tags         authentication-service-internal
+// @Produce      json
+// @Param        user_id  path      string  true  "User UUID"
+// @Success      200      {object}  dtos.InternalUserResponseDTO
+// @Failure      400      {object}  dtos.ErrorResponse
+// @Failure      404      {object}  dtos.ErrorResponse
+// @Router       /auth/internal/users/{user_id} [get]
+func (ec *accountController) GetUserInternal(c *gin.Context) {
+	userId, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		_ = c.Error(errors.NewBadRequestError("invalid user ID"))
+		return
+	}
+
+	user, err := ec.accountService.GetUserById(userId)
+	if err != nil {
+		_ = c.Error(errors.NewNotFoundError("user not found"))
+		return
+	}
+
+	common_handlers.HandleSuccessResponse(c, http.StatusOK, dtos.InternalUserResponseDTO{
+		ID:    user.Id.String(),
+		Email: user.Email,
+	})
+}
@@ -0,0 +1,149 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/FeedTheRealm-org/core-service/config"
+	dtos "github.com/FeedTheRealm-org/core-service/internal/authentication-service/dtos"
+	"github.com/FeedTheRealm-org/core-service/internal/authentication-service/models"
+	"github.com/FeedTheRealm-org/core-service/internal/authentication-service/services"
+	"github.com/FeedTheRealm-org/core-service/internal/common_handlers"
+	"github.com/FeedTheRealm-org/core-service/internal/errors"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/email_sender"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary      Export account data
+// @Description  Downloads a zip archive with everything stored about the current user across all services.
+// @Tags         authentication-service
+// @Security     BearerAuth
+// @Produce      application/zip
+// @Success      200  {file}    file
+// @Failure      401  {object}  dtos.ErrorResponse
+// @Failure      404  {object}  dtos.ErrorResponse
+// @Failure      500  {object}  dtos.ErrorResponse
+// @Router       /auth/me/export [get]
+func (ec *accountController) ExportUserData(c *gin.Context) {
+	userID, err := common_handlers.GetUserIDFromSession(c)
+	if err != nil {
+		_ = c.Error(errors.NewUnauthorizedError("Invalid session token"))
+		return
+	}
+
+	archive, err := ec.accountService.ExportUserData(userID)
+	if err != nil {
+		switch err.(type) {
+		case *services.AccountNotFoundError:
+			_ = c.Error(errors.NewNotFoundError("Account not found."))
+		default:
+			logger.Logger.Errorf("ExportUserData: failed to export data of user=%s: %v", userID, err)
+			_ = c.Error(errors.NewInternalServerError("Failed to export account data."))
+		}
+		return
+	}
+
+	logger.Logger.Infof("ExportUserData: exported data of user=%s", userID)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="ftr-data-%s.zip"`, userID))
+	c.Data(http.StatusOK, "application/zip", archive)
+}
+
+// @Summary      Delete account
+// @Description  Schedules the current account for deletion after a grace period and signs it out everywhere. A link to cancel the deletion is emailed to the user. Once the grace period ends the data is erased from every service.
+// @Tags         authentication-service
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        request body dtos.DeleteAccountRequestDTO true "Current password, omitted for provider-only accounts"
+// @Success      200  {object}  dtos.AccountDeletionResponseDTO
+// @Failure      400  {object}  dtos.ErrorResponse
+// @Failure      401  {object}  dtos.ErrorResponse
+// @Failure      404  {object}  dtos.ErrorResponse
+// @Failure      409  {object}  dtos.ErrorResponse
+// @Failure      500  {object}  dtos.ErrorResponse
+// @Router       /auth/me [delete]
+func (ec *accountController) DeleteAccount(c *gin.Context) {
+	userID, err := common_handlers.GetUserIDFromSession(c)
+	if err != nil {
+		_ = c.Error(errors.NewUnauthorizedError("Invalid session token"))
+		return
+	}
+
+	req := dtos.DeleteAccountRequestDTO{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(errors.NewBadRequestError("The request body is not valid."))
+		return
+	}
+
+	user, cancelToken, err := ec.accountService.RequestAccountDeletion(userID, req.Password)
+	if err != nil {
+		switch err.(type) {
+		case *services.IncorrectPasswordError:
+			_ = c.Error(errors.NewUnauthorizedError(err.Error()))
+		case *services.AccountDeletionScheduledError:
+			_ = c.Error(errors.NewConflictError(err.Error()))
+		case *services.AccountNotFoundError:
+			_ = c.Error(errors.NewNotFoundError("Account not found."))
+		default:
+			logger.Logger.Errorf("DeleteAccount: failed to schedule deletion of user=%s: %v", userID, err)
+			_ = c.Error(errors.NewInternalServerError("An unexpected error occurred."))
+		}
+		return
+	}
+
+	logger.Logger.Infof("DeleteAccount: deletion of user=%s scheduled at %s", userID, user.DeletionScheduledAt)
+	ec.sendAccountDeletionEmail(user, cancelToken)
+	common_handlers.HandleSuccessResponse(c, http.StatusOK, &dtos.AccountDeletionResponseDTO{
+		DeletionScheduledAt: *user.DeletionScheduledAt,
+	})
+}
+
+func (ec *accountController) sendAccountDeletionEmail(user *models.User, cancelToken string) {
+	if ec.conf.Server.Environment == config.Testing {
+		return
+	}
+
+	cancelURL := ec.conf.AccountDeletion.CancelURL + "?token=" + url.QueryEscape(cancelToken)
+	if err := ec.emailService.SendAccountDeletionEmail(email_sender.AccountDeletionEmailData{
+		BaseEmailData: ec.emailService.CreateBaseEmailData(user.Email),
+		CancelURL:     cancelURL,
+		DeletionDate:  user.DeletionScheduledAt.UTC().Format("January 2, 2006 15:04 MST"),
+	}); err != nil {
+		logger.Logger.Errorf("sendAccountDeletionEmail: failed to send account deletion email to email=%s: %v", user.Email, err)
+	}
+}
+
+// @Summary      Cancel account deletion
+// @Description  Cancels a scheduled account deletion using the token emailed to the user. It only works during the grace period.
+// @Tags         authentication-service
+// @Produce      json
+// @Param        token query string true "Cancel token from the email"
+// @Success      200  {object}  dtos.CancelAccountDeletionResponseDTO
+// @Failure      400  {object}  dtos.ErrorResponse
+// @Failure      429  {object}  dtos.ErrorResponse
+// @Failure      500  {object}  dtos.ErrorResponse
+// @Router       /auth/deletion/cancel [get]
+func (ec *accountController) CancelAccountDeletion(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		_ = c.Error(errors.NewBadRequestError("You must provide a cancel token."))
+		return
+	}
+
+	if err := ec.accountService.CancelAccountDeletion(token); err != nil {
+		switch err.(type) {
+		case *services.InvalidDeletionCancelTokenError:
+			logger.Logger.Info("CancelAccountDeletion: invalid or expired cancel token")
+			_ = c.Error(errors.NewBadRequestError(err.Error()))
+		default:
+			logger.Logger.Errorf("CancelAccountDeletion: service error: %v", err)
+			_ = c.Error(errors.NewInternalServerError("An unexpected error occurred."))
+		}
+		return
+	}
+
+	logger.Logger.Info("CancelAccountDeletion: account deletion canceled")
+	common_handlers.HandleSuccessResponse(c, http.StatusOK, &dtos.CancelAccountDeletionResponseDTO{Success: true})
+}
@@ -0,0 +1,184 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/FeedTheRealm-org/core-service/config"
+	dtos "github.com/FeedTheRealm-org/core-service/internal/authentication-service/dtos"
+	"github.com/FeedTheRealm-org/core-service/internal/authentication-service/models"
+	"github.com/FeedTheRealm-org/core-service/internal/authentication-service/services"
+	"github.com/FeedTheRealm-org/core-service/internal/common_handlers"
+	"github.com/FeedTheRealm-org/core-service/internal/errors"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/email_sender"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary      Change password
+// @Description  Changes the password of the current user. Every other session is signed out. Accounts created through a provider set their first password with the forgot password flow.
+// @Tags         authentication-service
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        request body dtos.ChangePasswordRequestDTO true "Current and new password"
+// @Success      200  {object}  dtos.ChangePasswordResponseDTO
+// @Failure      400  {object}  dtos.ErrorResponse
+// @Failure      401  {object}  dtos.ErrorResponse
+// @Failure      404  {object}  dtos.ErrorResponse
+// @Failure      429  {object}  dtos.ErrorResponse
+// @Failure      500  {object}  dtos.ErrorResponse
+// @Router       /auth/me/password [post]
+func (ec *accountController) ChangePassword(c *gin.Context) {
+	userID, err := common_handlers.GetUserIDFromSession(c)
+	if err != nil {
+		_ = c.Error(errors.NewUnauthorizedError("Invalid session token"))
+		return
+	}
+	sessionID, _ := common_handlers.GetSessionIDFromSession(c)
+
+	req := dtos.ChangePasswordRequestDTO{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(errors.NewBadRequestError("The request body is not valid."))
+		return
+	}
+
+	if err := ec.accountService.ChangePassword(userID, sessionID, req.CurrentPassword, req.NewPassword); err != nil {
+		switch e := err.(type) {
+		case *services.IncorrectPasswordError:
+			_ = c.Error(errors.NewUnauthorizedError(e.Error()))
+		case *services.AccountInvalidFormat:
+			_ = c.Error(errors.NewBadRequestError(e.Msg))
+		case *services.AccountNotFoundError:
+			_ = c.Error(errors.NewNotFoundError("Account not found."))
+		default:
+			logger.Logger.Errorf("ChangePassword: service error for user=%s: %v", userID, err)
+			_ = c.Error(errors.NewInternalServerError("An unexpected error occurred."))
+		}
+		return
+	}
+
+	logger.Logger.Infof("ChangePassword: password changed for user=%s", userID)
+	common_handlers.HandleSuccessResponse(c, http.StatusOK, &dtos.ChangePasswordResponseDTO{Success: true})
+}
+
+// @Summary      Request an email change
+// @Description  Sends a code to the new email that has to be confirmed at /auth/me/email/verify, the current email is notified of the request. The email does not change until then.
+// @Tags         authentication-service
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        request body dtos.ChangeEmailRequestDTO true "New email and current password, the password is omitted for provider-only accounts"
+// @Success      200  {object}  dtos.ChangeEmailResponseDTO
+// @Failure      400  {object}  dtos.ErrorResponse
+// @Failure      401  {object}  dtos.ErrorResponse
+// @Failure      404  {object}  dtos.ErrorResponse
+// @Failure      409  {object}  dtos.ErrorResponse
+// @Failure      429  {object}  dtos.ErrorResponse
+// @Failure      500  {object}  dtos.ErrorResponse
+// @Router       /auth/me/email [post]
+func (ec *accountController) RequestEmailChange(c *gin.Context) {
+	userID, err := common_handlers.GetUserIDFromSession(c)
+	if err != nil {
+		_ = c.Error(errors.NewUnauthorizedError("Invalid session token"))
+		return
+	}
+
+	req := dtos.ChangeEmailRequestDTO{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(errors.NewBadRequestError("The request body is not valid."))
+		return
+	}
+
+	user, change, err := ec.accountService.RequestEmailChange(userID, req.NewEmail, req.Password)
+	if err != nil {
+		switch e := err.(type) {
+		case *services.AccountInvalidFormat:
+			_ = c.Error(errors.NewBadRequestError(e.Msg))
+		case *services.EmailUnchangedError:
+			_ = c.Error(errors.NewBadRequestError(e.Error()))
+		case *services.IncorrectPasswordError:
+			_ = c.Error(errors.NewUnauthorizedError(e.Error()))
+		case *services.EmailAlreadyInUseError:
+			_ = c.Error(errors.NewConflictError(e.Error()))
+		case *services.AccountNotFoundError:
+			_ = c.Error(errors.NewNotFoundError("Account not found."))
+		default:
+			logger.Logger.Errorf("RequestEmailChange: service error for user=%s: %v", userID, err)
+			_ = c.Error(errors.NewInternalServerError("An unexpected error occurred."))
+		}
+		return
+	}
+
+	logger.Logger.Infof("RequestEmailChange: email change requested for user=%s", userID)
+	ec.sendEmailChangeEmails(user, change)
+	common_handlers.HandleSuccessResponse(c, http.StatusOK, &dtos.ChangeEmailResponseDTO{NewEmail: change.NewEmail})
+}
+
+func (ec *accountController) sendEmailChangeEmails(user *models.User, change *models.EmailChange) {
+	if ec.conf.Server.Environment == config.Testing {
+		return
+	}
+	newEmail := change.NewEmail
+
+	if err := ec.emailService.SendEmailChangeVerificationEmail(email_sender.EmailChangeVerificationEmailData{
+		BaseEmailData: ec.emailService.CreateBaseEmailData(newEmail),
+		VerifyCode:    change.VerificationCode,
+	}); err != nil {
+		logger.Logger.Errorf("RequestEmailChange: failed to send email change code to email=%s: %v", newEmail, err)
+	}
+
+	if err := ec.emailService.SendEmailChangeNoticeEmail(email_sender.EmailChangeNoticeEmailData{
+		BaseEmailData: ec.emailService.CreateBaseEmailData(user.Email),
+		NewEmail:      newEmail,
+	}); err != nil {
+		logger.Logger.Errorf("RequestEmailChange: failed to send email change notice to email=%s: %v", user.Email, err)
+	}
+}
+
+// @Summary      Confirm an email change
+// @Description  Applies the pending email change with the code sent to the new email. Every session is signed out and new tokens carrying the new email are returned.
+// @Tags         authentication-service
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        request body dtos.ConfirmEmailChangeRequestDTO true "Code sent to the new email"
+// @Success      200  {object}  dtos.LoginAccountResponseDTO
+// @Failure      400  {object}  dtos.ErrorResponse
+// @Failure      401  {object}  dtos.ErrorResponse
+// @Failure      404  {object}  dtos.ErrorResponse
+// @Failure      409  {object}  dtos.ErrorResponse
+// @Failure      429  {object}  dtos.ErrorResponse
+// @Failure      500  {object}  dtos.ErrorResponse
+// @Router       /auth/me/email/verify [post]
+func (ec *accountController) ConfirmEmailChange(c *gin.Context) {
+	userID, err := common_handlers.GetUserIDFromSession(c)
+	if err != nil {
+		_ = c.Error(errors.NewUnauthorizedError("Invalid session token"))
+		return
+	}
+
+	req := dtos.ConfirmEmailChangeRequestDTO{}
+	if err := c.ShouldBindJSON(&req); err != nil || req.Code == "" {
+		_ = c.Error(errors.NewBadRequestError("You must provide the code sent to the new email."))
+		return
+	}
+
+	user, accessToken, refreshToken, err := ec.accountService.ConfirmEmailChange(userID, req.Code, sessionDevice(c))
+	if err != nil {
+		switch e := err.(type) {
+		case *services.EmailChangeNotFoundError:
+			_ = c.Error(errors.NewNotFoundError(e.Error()))
+		case *services.EmailChangeCodeExpiredError, *services.InvalidEmailChangeCodeError:
+			_ = c.Error(errors.NewUnauthorizedError(e.Error()))
+		case *services.EmailAlreadyInUseError:
+			_ = c.Error(errors.NewConflictError(e.Error()))
+		default:
+			logger.Logger.Errorf("ConfirmEmailChange: service error for user=%s: %v", userID, err)
+			_ = c.Error(errors.NewInternalServerError("An unexpected error occurred."))
+		}
+		return
+	}
+
+	logger.Logger.Infof("ConfirmEmailChange: email changed for user=%s", userID)
+	common_handlers.HandleSuccessResponse(c, http.StatusOK, ec.loginResponse(user, accessToken, refreshToken))
+}
@@ -3,17 +3,21 @@ package controllers
 import (
 	"net/http"
 
+	"github.com/FeedTheRealm-org/core-service/config"
 	"github.com/FeedTheRealm-org/core-service/internal/authentication-service/services"
+	"github.com/FeedTheRealm-org/core-service/internal/errors"
 	"github.com/FeedTheRealm-org/core-service/internal/utils/logger"
 	"github.com/gin-gonic/gin"
 )
 
 type AdminLoginController struct {
+	conf           *config.Config
 	accountService services.AccountService
 }
 
-func NewAdminLoginController(accountService services.AccountService) *AdminLoginController {
+func NewAdminLoginController(conf *config.Config, accountService services.AccountService) *AdminLoginController {
 	return &AdminLoginController{
+		conf:           conf,
 		accountService: accountService,
 	}
 }
@@ -31,6 +35,7 @@ func (ac *AdminLoginController) AdminLoginPageHandler(c *gin.Context) {
 		<form method="POST" action="/auth">
 			<input type="email"    name="email"    placeholder="Email" />
 			<input type="password" name="password" placeholder="Password" />
+			<input type="text"     name="code"     placeholder="2FA code" autocomplete="one-time-code" />
 			<button type="submit">Login</button>
 		</form>
 	`))
@@ -44,16 +49,28 @@ func (ac *AdminLoginController) AdminLoginPageHandler(c *gin.Context) {
 // @Produce      text/html
 // @Param        email formData string true "Admin Email"
 // @Param        password formData string true "Admin Password"
+// @Param        code formData string false "TOTP or recovery code, required when two-factor authentication is enabled"
 // @Success      302  {string}  string "Redirect to /swagger/index.html"
-// @Success      200  {string}  string "Fallback on error"
+// @Failure      401  {object}  dtos.ErrorResponse
+// @Failure      403  {object}  dtos.ErrorResponse
 // @Router       /auth [post]
 func (ac *AdminLoginController) AdminLoginHandler(c *gin.Context) {
 	email := c.PostForm("email")
 	password := c.PostForm("password")
 
-	_, accessToken, _, err := ac.accountService.LoginAccount(email, password, false)
+	user, accessToken, _, err := ac.accountService.LoginAccount(email, password, false, sessionDevice(c))
+	if challenge, ok := err.(*services.TwoFactorRequiredError); ok {
+		user, accessToken, _, err = ac.accountService.CompleteTwoFactorLogin(challenge.ChallengeToken, c.PostForm("code"), sessionDevice(c))
+	}
 	if err != nil {
-		_ = c.Error(err)
+		_ = c.Error(errors.NewUnauthorizedError("Invalid credentials or two-factor code."))
+		return
+	}
+
+	if ac.accountService.TwoFactorSetupRequired(user) {
+		logger.Logger.Warnf("AdminLoginHandler: admin email=%s must enable two-factor authentication", email)
+		_ = c.Error(errors.NewForbiddenError("Two-factor authentication must be enabled before using the admin login."))
+		return
 	}
 
 	c.SetCookie("jwt", accessToken, 3600, "/swagger", "", true, true)
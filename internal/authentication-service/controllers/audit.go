@@ -0,0 +1,167 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	dtos "github.com/FeedTheRealm-org/core-service/internal/authentication-service/dtos"
+	"github.com/FeedTheRealm-org/core-service/internal/authentication-service/models"
+	"github.com/FeedTheRealm-org/core-service/internal/authentication-service/repositories"
+	"github.com/FeedTheRealm-org/core-service/internal/authentication-service/services"
+	"github.com/FeedTheRealm-org/core-service/internal/common_handlers"
+	"github.com/FeedTheRealm-org/core-service/internal/errors"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+type auditController struct {
+	auditService services.AuditService
+}
+
+func NewAuditController(auditService services.AuditService) AuditController {
+	return &auditController{auditService: auditService}
+}
+
+// @Summary      List audit events
+// @Description  Returns the privileged actions taken by staff in every service, newest first. The action filter matches a prefix when it ends in *. Requires audit.read.
+// @Tags         authentication-service
+// @Security     BearerAuth
+// @Produce      json
+// @Param        actor_id     query     string  false  "Only actions taken by this user"
+// @Param        action       query     string  false  "Only this action, e.g. payments.gem_balance.update or payments.*"
+// @Param        target_type  query     string  false  "Only actions on this kind of target"
+// @Param        target_id    query     string  false  "Only actions on this target"
+// @Param        from         query     string  false  "Only actions at or after this RFC 3339 time"
+// @Param        to           query     string  false  "Only actions before this RFC 3339 time"
+// @Param        offset       query     int     false  "Offset for pagination"  default(0)
+// @Param        limit        query     int     false  "Limit for pagination"   default(50)
+// @Success      200          {object}  dtos.AuditEventsListResponseDTO
+// @Failure      400          {object}  dtos.ErrorResponse
+// @Failure      403          {object}  dtos.ErrorResponse
+// @Failure      500          {object}  dtos.ErrorResponse
+// @Router       /admin/audit [get]
+func (ac *auditController) ListAuditEvents(c *gin.Context) {
+	filter := repositories.AuditEventFilter{
+		Action:     c.Query("action"),
+		TargetType: c.Query("target_type"),
+		TargetId:   c.Query("target_id"),
+	}
+
+	var err error
+	if filter.Offset, err = strconv.Atoi(c.DefaultQuery("offset", "0")); err != nil || filter.Offset < 0 {
+		_ = c.Error(errors.NewBadRequestError("invalid offset"))
+		return
+	}
+	if filter.Limit, err = strconv.Atoi(c.DefaultQuery("limit", "50")); err != nil || filter.Limit <= 0 || filter.Limit > 200 {
+		_ = c.Error(errors.NewBadRequestError("invalid limit"))
+		return
+	}
+
+	if actorParam := c.Query("actor_id"); actorParam != "" {
+		actorId, err := uuid.Parse(actorParam)
+		if err != nil {
+			_ = c.Error(errors.NewBadRequestError("invalid actor ID"))
+			return
+		}
+		filter.ActorId = &actorId
+	}
+	if filter.From, err = parseTimeQuery(c, "from"); err != nil {
+		_ = c.Error(errors.NewBadRequestError("invalid from time, expected RFC 3339"))
+		return
+	}
+	if filter.To, err = parseTimeQuery(c, "to"); err != nil {
+		_ = c.Error(errors.NewBadRequestError("invalid to time, expected RFC 3339"))
+		return
+	}
+
+	events, total, err := ac.auditService.ListEvents(filter)
+	if err != nil {
+		if invalidErr, ok := err.(*services.InvalidAuditEventError); ok {
+			_ = c.Error(errors.NewBadRequestError(invalidErr.Error()))
+			return
+		}
+		logger.Logger.Errorf("ListAuditEvents: failed to list audit events: %v", err)
+		_ = c.Error(errors.NewInternalServerError("Failed to list audit events."))
+		return
+	}
+
+	response := dtos.AuditEventsListResponseDTO{
+		Events:     make([]dtos.AuditEventResponseDTO, len(events)),
+		TotalCount: total,
+	}
+	for i, event := range events {
+		response.Events[i] = dtos.AuditEventResponseDTO{
+			ID:         event.Id.String(),
+			ActorID:    event.ActorId.String(),
+			Action:     event.Action,
+			TargetType: event.TargetType,
+			TargetID:   event.TargetId,
+			Before:     []byte(event.Before),
+			After:      []byte(event.After),
+			RequestID:  event.RequestId,
+			IPAddress:  event.IPAddress,
+			OccurredAt: event.OccurredAt,
+		}
+	}
+
+	common_handlers.HandleSuccessResponse(c, http.StatusOK, response)
+}
+
+// @Summary      Record audit event (internal)
+// @Description  Appends a privileged action taken in another service to the audit log, only reachable by other services.
+// @Tags         authentication-service-internal
+// @Accept       json
+// @Param        request  body  dtos.RecordAuditEventRequestDTO  true  "Audit event"
+// @Success      201
+// @Failure      400  {object}  dtos.ErrorResponse
+// @Failure      500  {object}  dtos.ErrorResponse
+// @Router       /auth/internal/audit [post]
+func (ac *auditController) RecordAuditEventInternal(c *gin.Context) {
+	var req dtos.RecordAuditEventRequestDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(errors.NewBadRequestError("invalid request body"))
+		return
+	}
+	actorId, err := uuid.Parse(req.ActorID)
+	if err != nil {
+		_ = c.Error(errors.NewBadRequestError("invalid actor ID"))
+		return
+	}
+
+	err = ac.auditService.RecordEvent(&models.AuditEvent{
+		ActorId:    actorId,
+		Action:     req.Action,
+		TargetType: req.TargetType,
+		TargetId:   req.TargetID,
+		Before:     datatypes.JSON(req.Before),
+		After:      datatypes.JSON(req.After),
+		RequestId:  req.RequestID,
+		IPAddress:  req.IPAddress,
+		OccurredAt: req.OccurredAt,
+	})
+	if err != nil {
+		if invalidErr, ok := err.(*services.InvalidAuditEventError); ok {
+			_ = c.Error(errors.NewBadRequestError(invalidErr.Error()))
+			return
+		}
+		_ = c.Error(errors.NewInternalServerError("Failed to record audit event."))
+		return
+	}
+
+	common_handlers.HandleBodilessResponse(c, http.StatusCreated)
+}
+
+func parseTimeQuery(c *gin.Context, name string) (*time.Time, error) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+	return &parsed, nil
+}
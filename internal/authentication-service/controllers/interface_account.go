@@ -10,9 +10,36 @@ type AccountController interface {
 	VerifyAccount(c *gin.Context)
 	RefreshVerification(c *gin.Context)
 	RefreshToken(c *gin.Context)
+	Logout(c *gin.Context)
+	ListSessions(c *gin.Context)
+	RevokeSession(c *gin.Context)
+	RevokeAllUserSessions(c *gin.Context)
 	ListUsers(c *gin.Context)
 	UpdateAdminStatus(c *gin.Context)
+	GetUserInternal(c *gin.Context)
 	ForgotPassword(c *gin.Context)
 	VerifyPasswordCode(c *gin.Context)
 	ResetPassword(c *gin.Context)
+	UnlockAccount(c *gin.Context)
+	LoginTwoFactor(c *gin.Context)
+	SetupTwoFactor(c *gin.Context)
+	ConfirmTwoFactor(c *gin.Context)
+	ListOIDCProviders(c *gin.Context)
+	AuthorizeOIDC(c *gin.Context)
+	LinkOIDC(c *gin.Context)
+	OIDCCallback(c *gin.Context)
+	OIDCLinkCallback(c *gin.Context)
+	ListIdentities(c *gin.Context)
+	UnlinkIdentity(c *gin.Context)
+	ExportUserData(c *gin.Context)
+	DeleteAccount(c *gin.Context)
+	CancelAccountDeletion(c *gin.Context)
+	ChangePassword(c *gin.Context)
+	RequestEmailChange(c *gin.Context)
+	ConfirmEmailChange(c *gin.Context)
+	ListRoles(c *gin.Context)
+	SaveRole(c *gin.Context)
+	DeleteRole(c *gin.Context)
+	GetUserRoles(c *gin.Context)
+	SetUserRoles(c *gin.Context)
 }
@@ -0,0 +1,8 @@
+package controllers
+
+import "github.com/gin-gonic/gin"
+
+type AuditController interface {
+	ListAuditEvents(c *gin.Context)
+	RecordAuditEventInternal(c *gin.Context)
+}
@@ -0,0 +1,7 @@
+package controllers
+
+import "github.com/gin-gonic/gin"
+
+type KeysController interface {
+	GetJWKS(c *gin.Context)
+}
@@ -0,0 +1,27 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/FeedTheRealm-org/core-service/internal/utils/session"
+	"github.com/gin-gonic/gin"
+)
+
+type keysController struct {
+	jwtManager *session.JWTManager
+}
+
+func NewKeysController(jwtManager *session.JWTManager) KeysController {
+	return &keysController{jwtManager: jwtManager}
+}
+
+// @Summary      Access token signing keys
+// @Description  Returns the public keys access tokens are signed with as a JSON Web Key Set, tokens name their key in the kid header. Keys stay listed after a rotation until the tokens they signed expire.
+// @Tags         authentication-service
+// @Produce      json
+// @Success      200  {object}  session.JWKSet
+// @Router       /.well-known/jwks.json [get]
+func (kc *keysController) GetJWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, kc.jwtManager.JWKS())
+}
@@ -0,0 +1,288 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/FeedTheRealm-org/core-service/config"
+	dtos "github.com/FeedTheRealm-org/core-service/internal/authentication-service/dtos"
+	"github.com/FeedTheRealm-org/core-service/internal/authentication-service/services"
+	"github.com/FeedTheRealm-org/core-service/internal/common_handlers"
+	"github.com/FeedTheRealm-org/core-service/internal/errors"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/email_sender"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// @Summary      List sign-in providers
+// @Description  Returns the names of the configured OpenID Connect providers users can sign in with.
+// @Tags         authentication-service
+// @Produce      json
+// @Success      200  {object}  dtos.OIDCProvidersResponseDTO
+// @Router       /auth/oidc/providers [get]
+func (ec *accountController) ListOIDCProviders(c *gin.Context) {
+	common_handlers.HandleSuccessResponse(c, http.StatusOK, &dtos.OIDCProvidersResponseDTO{
+		Providers: ec.accountService.ListOIDCProviders(),
+	})
+}
+
+// @Summary      Start sign-in with a provider
+// @Description  Returns the provider URL to send the user to. The provider redirects back with a code and state for /auth/oidc/{provider}/callback.
+// @Tags         authentication-service
+// @Produce      json
+// @Param        provider  path  string  true  "Provider name"
+// @Success      200  {object}  dtos.OIDCAuthorizeResponseDTO
+// @Failure      404  {object}  dtos.ErrorResponse
+// @Failure      429  {object}  dtos.ErrorResponse
+// @Failure      500  {object}  dtos.ErrorResponse
+// @Router       /auth/oidc/{provider}/authorize [post]
+func (ec *accountController) AuthorizeOIDC(c *gin.Context) {
+	ec.startOIDC(c, nil)
+}
+
+// @Summary      Start linking a provider
+// @Description  Returns the provider URL to send the logged in user to. The provider redirects back with a code and state for /auth/oidc/{provider}/link/callback.
+// @Tags         authentication-service
+// @Security     BearerAuth
+// @Produce      json
+// @Param        provider  path  string  true  "Provider name"
+// @Success      200  {object}  dtos.OIDCAuthorizeResponseDTO
+// @Failure      401  {object}  dtos.ErrorResponse
+// @Failure      404  {object}  dtos.ErrorResponse
+// @Failure      500  {object}  dtos.ErrorResponse
+// @Router       /auth/oidc/{provider}/link [post]
+func (ec *accountController) LinkOIDC(c *gin.Context) {
+	userID, err := common_handlers.GetUserIDFromSession(c)
+	if err != nil {
+		_ = c.Error(errors.NewUnauthorizedError("Invalid session token"))
+		return
+	}
+	ec.startOIDC(c, &userID)
+}
+
+func (ec *accountController) startOIDC(c *gin.Context, linkUserID *uuid.UUID) {
+	authorizationURL, err := ec.accountService.StartOIDCLogin(c.Param("provider"), linkUserID)
+	if err != nil {
+		if _, ok := err.(*services.UnknownOIDCProviderError); ok {
+			_ = c.Error(errors.NewNotFoundError(err.Error()))
+			return
+		}
+		logger.Logger.Errorf("startOIDC: failed to start sign-in with provider=%s: %v", c.Param("provider"), err)
+		_ = c.Error(errors.NewInternalServerError("An unexpected error occurred."))
+		return
+	}
+
+	common_handlers.HandleSuccessResponse(c, http.StatusOK, &dtos.OIDCAuthorizeResponseDTO{AuthorizationURL: authorizationURL})
+}
+
+func bindOIDCCallback(c *gin.Context) (*dtos.OIDCCallbackRequestDTO, bool) {
+	req := &dtos.OIDCCallbackRequestDTO{}
+	if err := c.ShouldBindJSON(req); err != nil || req.Code == "" || req.State == "" {
+		_ = c.Error(errors.NewBadRequestError("You must provide the code and state returned by the provider."))
+		return nil, false
+	}
+	return req, true
+}
+
+// oidcCallbackError maps the errors shared by the login and link callbacks.
+func oidcCallbackError(c *gin.Context, err error) bool {
+	switch err.(type) {
+	case *services.UnknownOIDCProviderError:
+		_ = c.Error(errors.NewNotFoundError(err.Error()))
+	case *services.InvalidOIDCStateError, *services.OIDCEmailRequiredError:
+		_ = c.Error(errors.NewBadRequestError(err.Error()))
+	case *services.OIDCExchangeFailedError:
+		_ = c.Error(errors.NewUnauthorizedError(err.Error()))
+	case *services.OIDCEmailInUseError, *services.IdentityAlreadyLinkedError:
+		_ = c.Error(errors.NewConflictError(err.Error()))
+	case *services.AccountDeletionScheduledError:
+		_ = c.Error(errors.NewForbiddenError(err.Error()))
+	default:
+		return false
+	}
+	return true
+}
+
+// @Summary      Complete sign-in with a provider
+// @Description  Signs in with the code the provider redirected back with, creating the account on the first sign-in. The email is verified when the provider asserts it. Accounts with two-factor authentication get a challenge token to finish the login at /auth/login/2fa.
+// @Tags         authentication-service
+// @Accept       json
+// @Produce      json
+// @Param        provider  path  string  true  "Provider name"
+// @Param        request body dtos.OIDCCallbackRequestDTO true "Code and state from the provider redirect"
+// @Success      200  {object}  dtos.LoginAccountResponseDTO
+// @Success      202  {object}  dtos.TwoFactorChallengeResponseDTO
+// @Failure      400  {object}  dtos.ErrorResponse
+// @Failure      401  {object}  dtos.ErrorResponse
+// @Failure      403  {object}  dtos.ErrorResponse
+// @Failure      404  {object}  dtos.ErrorResponse
+// @Failure      409  {object}  dtos.ErrorResponse
+// @Failure      429  {object}  dtos.ErrorResponse
+// @Failure      500  {object}  dtos.ErrorResponse
+// @Router       /auth/oidc/{provider}/callback [post]
+func (ec *accountController) OIDCCallback(c *gin.Context) {
+	req, ok := bindOIDCCallback(c)
+	if !ok {
+		return
+	}
+
+	user, accessToken, refreshToken, err := ec.accountService.CompleteOIDCLogin(c.Param("provider"), req.Code, req.State, sessionDevice(c))
+	if err != nil {
+		if oidcCallbackError(c, err) {
+			return
+		}
+		switch e := err.(type) {
+		case *services.TwoFactorRequiredError:
+			common_handlers.HandleSuccessResponse(c, http.StatusAccepted, &dtos.TwoFactorChallengeResponseDTO{
+				TwoFactorRequired: true,
+				ChallengeToken:    e.ChallengeToken,
+				ExpiresIn:         int(e.ExpiresIn.Seconds()),
+			})
+		case *services.OIDCEmailNotVerifiedError:
+			ec.sendOIDCVerificationEmail(e)
+			_ = c.Error(errors.NewForbiddenError("You must verify your email address before you can log in, a code was sent to it."))
+		case *services.AccountLockedError:
+			ec.rejectLockedLogin(c, e)
+		case *services.AccountNotFoundError:
+			_ = c.Error(errors.NewUnauthorizedError(err.Error()))
+		default:
+			logger.Logger.Errorf("OIDCCallback: service error for provider=%s: %v", c.Param("provider"), err)
+			_ = c.Error(errors.NewInternalServerError("An unexpected error occurred."))
+		}
+		return
+	}
+
+	logger.Logger.Infof("OIDCCallback: login successful for email=%s with provider=%s", user.Email, c.Param("provider"))
+	common_handlers.HandleSuccessResponse(c, http.StatusOK, ec.loginResponse(user, accessToken, refreshToken))
+}
+
+func (ec *accountController) sendOIDCVerificationEmail(notVerified *services.OIDCEmailNotVerifiedError) {
+	if ec.conf.Server.Environment == config.Testing {
+		return
+	}
+	err := ec.emailService.SendVerificationEmail(email_sender.VerificationEmailData{
+		BaseEmailData: ec.emailService.CreateBaseEmailData(notVerified.Email),
+		VerifyCode:    notVerified.VerificationCode,
+	})
+	if err != nil {
+		logger.Logger.Errorf("OIDCCallback: failed to send verification email to email=%s: %v", notVerified.Email, err)
+	}
+}
+
+// @Summary      Complete linking a provider
+// @Description  Links the provider account to the current user with the code the provider redirected back with.
+// @Tags         authentication-service
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        provider  path  string  true  "Provider name"
+// @Param        request body dtos.OIDCCallbackRequestDTO true "Code and state from the provider redirect"
+// @Success      201  {object}  dtos.IdentityResponseDTO
+// @Failure      400  {object}  dtos.ErrorResponse
+// @Failure      401  {object}  dtos.ErrorResponse
+// @Failure      404  {object}  dtos.ErrorResponse
+// @Failure      409  {object}  dtos.ErrorResponse
+// @Failure      500  {object}  dtos.ErrorResponse
+// @Router       /auth/oidc/{provider}/link/callback [post]
+func (ec *accountController) OIDCLinkCallback(c *gin.Context) {
+	userID, err := common_handlers.GetUserIDFromSession(c)
+	if err != nil {
+		_ = c.Error(errors.NewUnauthorizedError("Invalid session token"))
+		return
+	}
+
+	req, ok := bindOIDCCallback(c)
+	if !ok {
+		return
+	}
+
+	identity, err := ec.accountService.CompleteOIDCLink(userID, c.Param("provider"), req.Code, req.State)
+	if err != nil {
+		if oidcCallbackError(c, err) {
+			return
+		}
+		logger.Logger.Errorf("OIDCLinkCallback: failed to link provider=%s to user=%s: %v", c.Param("provider"), userID, err)
+		_ = c.Error(errors.NewInternalServerError("An unexpected error occurred."))
+		return
+	}
+
+	common_handlers.HandleSuccessResponse(c, http.StatusCreated, &dtos.IdentityResponseDTO{
+		Provider:  identity.Provider,
+		Email:     identity.Email,
+		CreatedAt: identity.CreatedAt,
+	})
+}
+
+// @Summary      List linked providers
+// @Description  Returns the sign-in providers linked to the current user.
+// @Tags         authentication-service
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200  {object}  dtos.IdentitiesListResponseDTO
+// @Failure      401  {object}  dtos.ErrorResponse
+// @Failure      500  {object}  dtos.ErrorResponse
+// @Router       /auth/identities [get]
+func (ec *accountController) ListIdentities(c *gin.Context) {
+	userID, err := common_handlers.GetUserIDFromSession(c)
+	if err != nil {
+		_ = c.Error(errors.NewUnauthorizedError("Invalid session token"))
+		return
+	}
+
+	identities, err := ec.accountService.ListIdentities(userID)
+	if err != nil {
+		logger.Logger.Errorf("ListIdentities: failed to list identities for user=%s: %v", userID, err)
+		_ = c.Error(errors.NewInternalServerError("Failed to list linked providers."))
+		return
+	}
+
+	response := dtos.IdentitiesListResponseDTO{
+		Identities: make([]dtos.IdentityResponseDTO, len(identities)),
+	}
+	for i, identity := range identities {
+		response.Identities[i] = dtos.IdentityResponseDTO{
+			Provider:  identity.Provider,
+			Email:     identity.Email,
+			CreatedAt: identity.CreatedAt,
+		}
+	}
+
+	common_handlers.HandleSuccessResponse(c, http.StatusOK, response)
+}
+
+// @Summary      Unlink a provider
+// @Description  Removes a linked sign-in provider from the current user. Accounts without a password must keep at least one provider.
+// @Tags         authentication-service
+// @Security     BearerAuth
+// @Produce      json
+// @Param        provider  path  string  true  "Provider name"
+// @Success      204  {string}  string "No Content"
+// @Failure      401  {object}  dtos.ErrorResponse
+// @Failure      404  {object}  dtos.ErrorResponse
+// @Failure      409  {object}  dtos.ErrorResponse
+// @Failure      500  {object}  dtos.ErrorResponse
+// @Router       /auth/identities/{provider} [delete]
+func (ec *accountController) UnlinkIdentity(c *gin.Context) {
+	userID, err := common_handlers.GetUserIDFromSession(c)
+	if err != nil {
+		_ = c.Error(errors.NewUnauthorizedError("Invalid session token"))
+		return
+	}
+
+	if err := ec.accountService.UnlinkIdentity(userID, c.Param("provider")); err != nil {
+		switch err.(type) {
+		case *services.IdentityNotFoundError:
+			_ = c.Error(errors.NewNotFoundError(err.Error()))
+		case *services.LastLoginMethodError:
+			_ = c.Error(errors.NewConflictError(err.Error()))
+		case *services.AccountNotFoundError:
+			_ = c.Error(errors.NewUnauthorizedError("Invalid session token"))
+		default:
+			logger.Logger.Errorf("UnlinkIdentity: failed to unlink provider=%s from user=%s: %v", c.Param("provider"), userID, err)
+			_ = c.Error(errors.NewInternalServerError("Failed to unlink provider."))
+		}
+		return
+	}
+
+	common_handlers.HandleBodilessResponse(c, http.StatusNoContent)
+}
@@ -0,0 +1,232 @@
+package controllers
+
+import (
+	"net/http"
+
+	dtos "github.com/FeedTheRealm-org/core-service/internal/authentication-service/dtos"
+	"github.com/FeedTheRealm-org/core-service/internal/authentication-service/models"
+	"github.com/FeedTheRealm-org/core-service/internal/authentication-service/services"
+	"github.com/FeedTheRealm-org/core-service/internal/common_handlers"
+	"github.com/FeedTheRealm-org/core-service/internal/errors"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/logger"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/permissions"
+	"github.com/gin-gonic/gin"
+)
+
+// roleManagerFromSession describes the session changing roles, it only holds the permissions of its token.
+func roleManagerFromSession(c *gin.Context) (services.RoleManager, error) {
+	userId, err := common_handlers.GetUserIDFromSession(c)
+	if err != nil {
+		return services.RoleManager{}, err
+	}
+	return services.RoleManager{
+		UserID:      userId,
+		IsAdmin:     common_handlers.IsAdminSession(c) == nil,
+		Permissions: common_handlers.GetPermissionsFromSession(c),
+	}, nil
+}
+
+func toRoleResponse(role *models.Role) dtos.RoleResponseDTO {
+	return dtos.RoleResponseDTO{
+		Name:        role.Name,
+		Description: role.Description,
+		Permissions: role.PermissionNames(),
+	}
+}
+
+// @Summary      List roles
+// @Description  Returns the staff roles with their permissions and every permission a role can be granted. Requires auth.roles.manage.
+// @Tags         authentication-service
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200  {object}  dtos.RolesListResponseDTO
+// @Failure      403  {object}  dtos.ErrorResponse
+// @Failure      500  {object}  dtos.ErrorResponse
+// @Router       /auth/roles [get]
+func (ec *accountController) ListRoles(c *gin.Context) {
+	roles, err := ec.accountService.ListRoles()
+	if err != nil {
+		logger.Logger.Errorf("ListRoles: failed to list roles: %v", err)
+		_ = c.Error(errors.NewInternalServerError("Failed to list roles."))
+		return
+	}
+
+	response := dtos.RolesListResponseDTO{
+		Roles:                make([]dtos.RoleResponseDTO, len(roles)),
+		AvailablePermissions: permissions.All,
+	}
+	for i := range roles {
+		response.Roles[i] = toRoleResponse(&roles[i])
+	}
+
+	common_handlers.HandleSuccessResponse(c, http.StatusOK, response)
+}
+
+// @Summary      Create or update a role
+// @Description  Creates the role or replaces its description and permissions. Users get the new permissions when their tokens are refreshed. Requires auth.roles.manage, and only permissions held by the caller can be granted or removed.
+// @Tags         authentication-service
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        name     path      string                   true  "Role name"
+// @Param        request  body      dtos.SaveRoleRequestDTO  true  "Role description and permissions"
+// @Success      200      {object}  dtos.RoleResponseDTO
+// @Failure      400      {object}  dtos.ErrorResponse
+// @Failure      401      {object}  dtos.ErrorResponse
+// @Failure      403      {object}  dtos.ErrorResponse
+// @Failure      500      {object}  dtos.ErrorResponse
+// @Router       /auth/roles/{name} [put]
+func (ec *accountController) SaveRole(c *gin.Context) {
+	manager, err := roleManagerFromSession(c)
+	if err != nil {
+		_ = c.Error(errors.NewUnauthorizedError(err.Error()))
+		return
+	}
+
+	req := dtos.SaveRoleRequestDTO{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(errors.NewBadRequestError("invalid request body"))
+		return
+	}
+
+	role, err := ec.accountService.SaveRole(manager, c.Param("name"), req.Description, req.Permissions)
+	if err != nil {
+		if e, ok := err.(*services.InvalidRoleError); ok {
+			_ = c.Error(errors.NewBadRequestError(e.Msg))
+			return
+		}
+		if e, ok := err.(*services.RoleEscalationError); ok {
+			_ = c.Error(errors.NewForbiddenError(e.Msg))
+			return
+		}
+		_ = c.Error(errors.NewInternalServerError("Failed to save role."))
+		return
+	}
+
+	logger.Logger.Infof("SaveRole: role=%s saved with permissions=%v", role.Name, role.PermissionNames())
+	common_handlers.HandleSuccessResponse(c, http.StatusOK, toRoleResponse(role))
+}
+
+// @Summary      Delete a role
+// @Description  Deletes the role, users that had it lose its permissions when their tokens are refreshed. Requires auth.roles.manage and every permission of the role.
+// @Tags         authentication-service
+// @Security     BearerAuth
+// @Produce      json
+// @Param        name  path      string  true  "Role name"
+// @Success      204   {string}  string "No Content"
+// @Failure      401   {object}  dtos.ErrorResponse
+// @Failure      403   {object}  dtos.ErrorResponse
+// @Failure      404   {object}  dtos.ErrorResponse
+// @Failure      500   {object}  dtos.ErrorResponse
+// @Router       /auth/roles/{name} [delete]
+func (ec *accountController) DeleteRole(c *gin.Context) {
+	manager, err := roleManagerFromSession(c)
+	if err != nil {
+		_ = c.Error(errors.NewUnauthorizedError(err.Error()))
+		return
+	}
+
+	if err := ec.accountService.DeleteRole(manager, c.Param("name")); err != nil {
+		if _, ok := err.(*services.RoleNotFoundError); ok {
+			_ = c.Error(errors.NewNotFoundError("role not found"))
+			return
+		}
+		if e, ok := err.(*services.RoleEscalationError); ok {
+			_ = c.Error(errors.NewForbiddenError(e.Msg))
+			return
+		}
+		_ = c.Error(errors.NewInternalServerError("Failed to delete role."))
+		return
+	}
+
+	logger.Logger.Infof("DeleteRole: role=%s deleted", c.Param("name"))
+	common_handlers.HandleBodilessResponse(c, http.StatusNoContent)
+}
+
+// @Summary      Get user roles
+// @Description  Returns the staff roles of a user. Requires auth.roles.manage.
+// @Tags         authentication-service
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      string  true  "User UUID"
+// @Success      200  {object}  dtos.UserRolesResponseDTO
+// @Failure      400  {object}  dtos.ErrorResponse
+// @Failure      403  {object}  dtos.ErrorResponse
+// @Failure      404  {object}  dtos.ErrorResponse
+// @Failure      500  {object}  dtos.ErrorResponse
+// @Router       /auth/users/{id}/roles [get]
+func (ec *accountController) GetUserRoles(c *gin.Context) {
+	roles, err := ec.accountService.GetUserRoles(c.Param("id"))
+	if err != nil {
+		switch err.(type) {
+		case *services.AccountInvalidFormat:
+			_ = c.Error(errors.NewBadRequestError("invalid user ID"))
+		case *services.AccountNotFoundError:
+			_ = c.Error(errors.NewNotFoundError("user not found"))
+		default:
+			_ = c.Error(errors.NewInternalServerError("Failed to get user roles."))
+		}
+		return
+	}
+
+	if roles == nil {
+		roles = []string{}
+	}
+	common_handlers.HandleSuccessResponse(c, http.StatusOK, &dtos.UserRolesResponseDTO{UserID: c.Param("id"), Roles: roles})
+}
+
+// @Summary      Set user roles
+// @Description  Replaces the staff roles of a user, the new permissions apply when the user's tokens are refreshed. Requires auth.roles.manage, callers cannot change their own roles nor add or remove roles with permissions they do not hold.
+// @Tags         authentication-service
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                    true  "User UUID"
+// @Param        request  body      dtos.UserRolesRequestDTO  true  "Role names, an empty list removes every role"
+// @Success      200      {object}  dtos.UserRolesResponseDTO
+// @Failure      400      {object}  dtos.ErrorResponse
+// @Failure      401      {object}  dtos.ErrorResponse
+// @Failure      403      {object}  dtos.ErrorResponse
+// @Failure      404      {object}  dtos.ErrorResponse
+// @Failure      500      {object}  dtos.ErrorResponse
+// @Router       /auth/users/{id}/roles [put]
+func (ec *accountController) SetUserRoles(c *gin.Context) {
+	manager, err := roleManagerFromSession(c)
+	if err != nil {
+		_ = c.Error(errors.NewUnauthorizedError(err.Error()))
+		return
+	}
+
+	req := dtos.UserRolesRequestDTO{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(errors.NewBadRequestError("invalid request body"))
+		return
+	}
+
+	previousRoles, _ := ec.accountService.GetUserRoles(c.Param("id"))
+
+	if err := ec.accountService.SetUserRoles(manager, c.Param("id"), req.Roles); err != nil {
+		switch e := err.(type) {
+		case *services.AccountInvalidFormat:
+			_ = c.Error(errors.NewBadRequestError("invalid user ID"))
+		case *services.AccountNotFoundError:
+			_ = c.Error(errors.NewNotFoundError("user not found"))
+		case *services.RoleNotFoundError:
+			_ = c.Error(errors.NewBadRequestError("unknown role"))
+		case *services.RoleEscalationError:
+			_ = c.Error(errors.NewForbiddenError(e.Msg))
+		default:
+			logger.Logger.Errorf("SetUserRoles: failed to set roles for user=%s: %v", c.Param("id"), err)
+			_ = c.Error(errors.NewInternalServerError("Failed to set user roles."))
+		}
+		return
+	}
+
+	roles, err := ec.accountService.GetUserRoles(c.Param("id"))
+	if err != nil || roles == nil {
+		roles = []string{}
+	}
+	logger.Logger.Infof("SetUserRoles: user=%s now has roles=%v", c.Param("id"), roles)
+	common_handlers.SetAuditChange(c, previousRoles, roles)
+	common_handlers.HandleSuccessResponse(c, http.StatusOK, &dtos.UserRolesResponseDTO{UserID: c.Param("id"), Roles: roles})
+}
@@ -0,0 +1,146 @@
+package controllers
+
+import (
+	"net/http"
+
+	dtos "github.com/FeedTheRealm-org/core-service/internal/authentication-service/dtos"
+	"github.com/FeedTheRealm-org/core-service/internal/authentication-service/services"
+	"github.com/FeedTheRealm-org/core-service/internal/common_handlers"
+	"github.com/FeedTheRealm-org/core-service/internal/errors"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary      Complete a two-factor login
+// @Description  Finishes a login started at /auth/login with the challenge token and a TOTP or recovery code.
+// @Tags         authentication-service
+// @Accept       json
+// @Produce      json
+// @Param        request body dtos.TwoFactorLoginRequestDTO true "Challenge token and code"
+// @Success      200  {object}  dtos.LoginAccountResponseDTO
+// @Failure      400  {object} dtos.ErrorResponse
+// @Failure      401  {object} dtos.ErrorResponse
+// @Failure      403  {object} dtos.ErrorResponse
+// @Failure      429  {object} dtos.ErrorResponse
+// @Failure      500  {object} dtos.ErrorResponse
+// @Router       /auth/login/2fa [post]
+func (ec *accountController) LoginTwoFactor(c *gin.Context) {
+	req := dtos.TwoFactorLoginRequestDTO{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(errors.NewBadRequestError("The request body is not valid."))
+		return
+	}
+
+	if req.ChallengeToken == "" || req.Code == "" {
+		_ = c.Error(errors.NewBadRequestError("You must provide the challenge token and a code."))
+		return
+	}
+
+	user, accessToken, refreshToken, err := ec.accountService.CompleteTwoFactorLogin(req.ChallengeToken, req.Code, sessionDevice(c))
+	if err != nil {
+		switch e := err.(type) {
+		case *services.InvalidTwoFactorChallengeError:
+			_ = c.Error(errors.NewUnauthorizedError("The login has expired, please log in again."))
+		case *services.InvalidTwoFactorCodeError:
+			_ = c.Error(errors.NewUnauthorizedError("The two-factor code is incorrect."))
+		case *services.AccountNotFoundError:
+			_ = c.Error(errors.NewUnauthorizedError("The email address or password is incorrect."))
+		case *services.AccountDeletionScheduledError:
+			_ = c.Error(errors.NewForbiddenError(e.Error()))
+		case *services.AccountLockedError:
+			logger.Logger.Warn("LoginTwoFactor: account locked after failed two-factor codes")
+			ec.rejectLockedLogin(c, e)
+		default:
+			logger.Logger.Errorf("LoginTwoFactor: service error: %v", err)
+			_ = c.Error(errors.NewInternalServerError("An unexpected error occurred."))
+		}
+		return
+	}
+
+	logger.Logger.Infof("LoginTwoFactor: login successful for email=%s", user.Email)
+	common_handlers.HandleSuccessResponse(c, http.StatusOK, ec.loginResponse(user, accessToken, refreshToken))
+}
+
+// @Summary      Set up two-factor authentication
+// @Description  Generates a TOTP secret for the current user. It is only enabled after a code is confirmed at /auth/2fa/confirm.
+// @Tags         authentication-service
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200  {object}  dtos.TwoFactorSetupResponseDTO
+// @Failure      401  {object} dtos.ErrorResponse
+// @Failure      409  {object} dtos.ErrorResponse
+// @Failure      500  {object} dtos.ErrorResponse
+// @Router       /auth/2fa/setup [post]
+func (ec *accountController) SetupTwoFactor(c *gin.Context) {
+	userID, err := common_handlers.GetUserIDFromSession(c)
+	if err != nil {
+		_ = c.Error(errors.NewUnauthorizedError("Invalid session token"))
+		return
+	}
+
+	secret, uri, err := ec.accountService.SetupTwoFactor(userID)
+	if err != nil {
+		switch err.(type) {
+		case *services.TwoFactorAlreadyEnabledError:
+			_ = c.Error(errors.NewConflictError(err.Error()))
+		case *services.AccountNotFoundError:
+			_ = c.Error(errors.NewUnauthorizedError("Invalid session token"))
+		default:
+			logger.Logger.Errorf("SetupTwoFactor: service error for user=%s: %v", userID, err)
+			_ = c.Error(errors.NewInternalServerError("An unexpected error occurred."))
+		}
+		return
+	}
+
+	common_handlers.HandleSuccessResponse(c, http.StatusOK, &dtos.TwoFactorSetupResponseDTO{
+		Secret:     secret,
+		OtpauthURI: uri,
+	})
+}
+
+// @Summary      Confirm two-factor authentication
+// @Description  Enables two-factor authentication with a code from the authenticator and returns single use recovery codes. Every session is logged out.
+// @Tags         authentication-service
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        request body dtos.TwoFactorConfirmRequestDTO true "Current TOTP code"
+// @Success      200  {object}  dtos.TwoFactorConfirmResponseDTO
+// @Failure      400  {object} dtos.ErrorResponse
+// @Failure      401  {object} dtos.ErrorResponse
+// @Failure      409  {object} dtos.ErrorResponse
+// @Failure      500  {object} dtos.ErrorResponse
+// @Router       /auth/2fa/confirm [post]
+func (ec *accountController) ConfirmTwoFactor(c *gin.Context) {
+	userID, err := common_handlers.GetUserIDFromSession(c)
+	if err != nil {
+		_ = c.Error(errors.NewUnauthorizedError("Invalid session token"))
+		return
+	}
+
+	req := dtos.TwoFactorConfirmRequestDTO{}
+	if err := c.ShouldBindJSON(&req); err != nil || req.Code == "" {
+		_ = c.Error(errors.NewBadRequestError("You must provide the code shown by your authenticator."))
+		return
+	}
+
+	recoveryCodes, err := ec.accountService.ConfirmTwoFactor(userID, req.Code)
+	if err != nil {
+		switch err.(type) {
+		case *services.InvalidTwoFactorCodeError, *services.TwoFactorNotSetUpError:
+			_ = c.Error(errors.NewBadRequestError(err.Error()))
+		case *services.TwoFactorAlreadyEnabledError:
+			_ = c.Error(errors.NewConflictError(err.Error()))
+		case *services.AccountNotFoundError:
+			_ = c.Error(errors.NewUnauthorizedError("Invalid session token"))
+		default:
+			logger.Logger.Errorf("ConfirmTwoFactor: service error for user=%s: %v", userID, err)
+			_ = c.Error(errors.NewInternalServerError("An unexpected error occurred."))
+		}
+		return
+	}
+
+	logger.Logger.Infof("ConfirmTwoFactor: two-factor authentication enabled for user=%s", userID)
+	c.SetCookie("jwt", "", -1, "/swagger", "", true, true)
+	common_handlers.HandleSuccessResponse(c, http.StatusOK, &dtos.TwoFactorConfirmResponseDTO{RecoveryCodes: recoveryCodes})
+}
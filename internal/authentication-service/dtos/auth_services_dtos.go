@@ -1,6 +1,9 @@
 package dtos
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 type CreateAccountRequestDTO struct {
 	Email    string `json:"email"`
@@ -24,6 +27,33 @@ type LoginAccountResponseDTO struct {
 	Email        string    `json:"email"`
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
+	// TwoFactorSetupRequired is set for admins and staff that must enable 2FA before their sessions get admin rights or role permissions.
+	TwoFactorSetupRequired bool `json:"two_factor_setup_required,omitempty"`
+}
+
+type TwoFactorChallengeResponseDTO struct {
+	TwoFactorRequired bool   `json:"two_factor_required"`
+	ChallengeToken    string `json:"challenge_token"`
+	ExpiresIn         int    `json:"expires_in"`
+}
+
+type TwoFactorLoginRequestDTO struct {
+	ChallengeToken string `json:"challenge_token"`
+	// Code is either the current TOTP code or one of the recovery codes.
+	Code string `json:"code"`
+}
+
+type TwoFactorSetupResponseDTO struct {
+	Secret     string `json:"secret"`
+	OtpauthURI string `json:"otpauth_uri"`
+}
+
+type TwoFactorConfirmRequestDTO struct {
+	Code string `json:"code"`
+}
+
+type TwoFactorConfirmResponseDTO struct {
+	RecoveryCodes []string `json:"recovery_codes"`
 }
 
 type CheckSessionResponseDTO struct {
@@ -70,6 +100,11 @@ type UserSummaryResponseDTO struct {
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+type InternalUserResponseDTO struct {
+	ID    string `json:"user_id"`
+	Email string `json:"email"`
+}
+
 type UsersListResponseDTO struct {
 	Users      []UserSummaryResponseDTO `json:"users"`
 	TotalCount int64                    `json:"total_count"`
@@ -87,6 +122,10 @@ type ForgotPasswordResponseDTO struct {
 	Success bool `json:"success"`
 }
 
+type UnlockAccountResponseDTO struct {
+	Success bool `json:"success"`
+}
+
 type VerifyPasswordCodeRequestDTO struct {
 	Email string `json:"email"`
 	Code  string `json:"code"`
@@ -104,3 +143,130 @@ type ResetPasswordRequestDTO struct {
 type ResetPasswordResponseDTO struct {
 	Success bool `json:"success"`
 }
+
+type SessionResponseDTO struct {
+	ID         string    `json:"id"`
+	UserAgent  string    `json:"user_agent"`
+	IPAddress  string    `json:"ip_address"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Current    bool      `json:"current"`
+}
+
+type SessionsListResponseDTO struct {
+	Sessions []SessionResponseDTO `json:"sessions"`
+}
+
+type OIDCProvidersResponseDTO struct {
+	Providers []string `json:"providers"`
+}
+
+type OIDCAuthorizeResponseDTO struct {
+	AuthorizationURL string `json:"authorization_url"`
+}
+
+// OIDCCallbackRequestDTO carries the query parameters the provider redirected back with.
+type OIDCCallbackRequestDTO struct {
+	Code  string `json:"code"`
+	State string `json:"state"`
+}
+
+type IdentityResponseDTO struct {
+	Provider  string    `json:"provider"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type IdentitiesListResponseDTO struct {
+	Identities []IdentityResponseDTO `json:"identities"`
+}
+
+type DeleteAccountRequestDTO struct {
+	Password string `json:"password"`
+}
+
+type AccountDeletionResponseDTO struct {
+	DeletionScheduledAt time.Time `json:"deletion_scheduled_at"`
+}
+
+type CancelAccountDeletionResponseDTO struct {
+	Success bool `json:"success"`
+}
+
+type ChangePasswordRequestDTO struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+type ChangePasswordResponseDTO struct {
+	Success bool `json:"success"`
+}
+
+type ChangeEmailRequestDTO struct {
+	NewEmail string `json:"new_email"`
+	Password string `json:"password"`
+}
+
+type ChangeEmailResponseDTO struct {
+	NewEmail string `json:"new_email"`
+}
+
+type ConfirmEmailChangeRequestDTO struct {
+	Code string `json:"code"`
+}
+
+type RoleResponseDTO struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+}
+
+type RolesListResponseDTO struct {
+	Roles                []RoleResponseDTO `json:"roles"`
+	AvailablePermissions []string          `json:"available_permissions"`
+}
+
+type SaveRoleRequestDTO struct {
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+}
+
+type UserRolesRequestDTO struct {
+	Roles []string `json:"roles"`
+}
+
+type UserRolesResponseDTO struct {
+	UserID string   `json:"user_id"`
+	Roles  []string `json:"roles"`
+}
+
+type AuditEventResponseDTO struct {
+	ID         string          `json:"id"`
+	ActorID    string          `json:"actor_id"`
+	Action     string          `json:"action"`
+	TargetType string          `json:"target_type"`
+	TargetID   string          `json:"target_id"`
+	Before     json.RawMessage `json:"before,omitempty"`
+	After      json.RawMessage `json:"after,omitempty"`
+	RequestID  string          `json:"request_id"`
+	IPAddress  string          `json:"ip_address"`
+	OccurredAt time.Time       `json:"occurred_at"`
+}
+
+type AuditEventsListResponseDTO struct {
+	Events     []AuditEventResponseDTO `json:"events"`
+	TotalCount int64                   `json:"total_count"`
+}
+
+type RecordAuditEventRequestDTO struct {
+	ActorID    string          `json:"actor_id"`
+	Action     string          `json:"action"`
+	TargetType string          `json:"target_type"`
+	TargetID   string          `json:"target_id"`
+	Before     json.RawMessage `json:"before,omitempty"`
+	After      json.RawMessage `json:"after,omitempty"`
+	RequestID  string          `json:"request_id"`
+	IPAddress  string          `json:"ip_address"`
+	OccurredAt time.Time       `json:"occurred_at"`
+}
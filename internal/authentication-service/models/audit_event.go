@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+// AuditEvent is an entry of the append-only log of privileged actions taken in every service.
+// The actor is not a foreign key so the trail outlives deleted staff accounts.
+type AuditEvent struct {
+	Id         uuid.UUID      `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	ActorId    uuid.UUID      `gorm:"type:uuid;not null"`
+	Action     string         `gorm:"not null"`
+	TargetType string         `gorm:"not null;default:''"`
+	TargetId   string         `gorm:"not null;default:''"`
+	Before     datatypes.JSON `gorm:"type:jsonb"`
+	After      datatypes.JSON `gorm:"type:jsonb"`
+	RequestId  string         `gorm:"not null;default:''"`
+	IPAddress  string         `gorm:"column:ip_address;not null;default:''"`
+	OccurredAt time.Time      `gorm:"not null"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EmailChange is a pending change of the account email, it is applied once the code sent to the new address is confirmed.
+type EmailChange struct {
+	UserId           uuid.UUID `gorm:"primaryKey;constraint:OnDelete:CASCADE;"`
+	NewEmail         string    `gorm:"not null"`
+	VerificationCode string    `gorm:"not null;default:''"`
+	Attempts         int       `gorm:"not null;default:0"`
+	CreatedAt        time.Time `gorm:"default:now()"`
+	ExpiresAt        time.Time `gorm:"not null"`
+
+	User User `gorm:"foreignKey:UserId"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Identity links an account of a third-party OIDC provider to a user.
+type Identity struct {
+	Id        uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	UserId    uuid.UUID `gorm:"column:user_id;not null;index"`
+	Provider  string    `gorm:"not null"`
+	Subject   string    `gorm:"not null"`
+	Email     string    `gorm:"not null;default:''"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+
+	User User `gorm:"foreignKey:UserId"`
+}
+
+// OIDCLoginState keeps the PKCE verifier and nonce of an authorization request until the provider
+// redirects back. LinkUserId is set when a logged in user is linking a provider instead of signing in.
+type OIDCLoginState struct {
+	Id           uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	StateHash    string     `gorm:"column:state_hash;not null;unique"`
+	Provider     string     `gorm:"not null"`
+	CodeVerifier string     `gorm:"column:code_verifier;not null"`
+	Nonce        string     `gorm:"not null"`
+	LinkUserId   *uuid.UUID `gorm:"column:link_user_id"`
+	ExpiresAt    time.Time  `gorm:"column:expires_at;not null"`
+	CreatedAt    time.Time  `gorm:"autoCreateTime"`
+}
+
+func (OIDCLoginState) TableName() string {
+	return "oidc_login_states"
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Role groups the permissions granted to staff members, admins hold every permission without needing one.
+type Role struct {
+	Name        string           `gorm:"primaryKey"`
+	Description string           `gorm:"not null;default:''"`
+	CreatedAt   time.Time        `gorm:"default:now()"`
+	Permissions []RolePermission `gorm:"foreignKey:RoleName;references:Name;constraint:OnDelete:CASCADE;"`
+}
+
+type RolePermission struct {
+	RoleName   string `gorm:"primaryKey"`
+	Permission string `gorm:"primaryKey"`
+}
+
+// UserRole assigns a role to a user.
+type UserRole struct {
+	UserId    uuid.UUID `gorm:"primaryKey;constraint:OnDelete:CASCADE;"`
+	RoleName  string    `gorm:"primaryKey"`
+	CreatedAt time.Time `gorm:"default:now()"`
+
+	User User `gorm:"foreignKey:UserId"`
+	Role Role `gorm:"foreignKey:RoleName;references:Name;constraint:OnDelete:CASCADE;"`
+}
+
+// PermissionNames returns the permissions granted by the role.
+func (r *Role) PermissionNames() []string {
+	names := make([]string, len(r.Permissions))
+	for i, permission := range r.Permissions {
+		names[i] = permission.Permission
+	}
+	return names
+}
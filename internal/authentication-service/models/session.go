@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	SESSION_REVOKED_LOGOUT           = "logout"
+	SESSION_REVOKED_BY_USER          = "revoked_by_user"
+	SESSION_REVOKED_BY_ADMIN         = "revoked_by_admin"
+	SESSION_REVOKED_PASSWORD_RESET   = "password_reset"
+	SESSION_REVOKED_TOKEN_REUSE      = "refresh_token_reuse"
+	SESSION_REVOKED_2FA_ENABLED      = "two_factor_enabled"
+	SESSION_REVOKED_ACCOUNT_DELETION = "account_deletion"
+	SESSION_REVOKED_PASSWORD_CHANGE  = "password_change"
+	SESSION_REVOKED_EMAIL_CHANGE     = "email_change"
+)
+
+// Session is a logged in device, it holds the hash of the only refresh token
+// that is currently valid for it.
+type Session struct {
+	Id               uuid.UUID  `gorm:"type:uuid;primaryKey"`
+	UserId           uuid.UUID  `gorm:"column:user_id;not null;index"`
+	RefreshTokenHash string     `gorm:"column:refresh_token_hash;not null"`
+	UserAgent        string     `gorm:"column:user_agent;not null;default:''"`
+	IPAddress        string     `gorm:"column:ip_address;not null;default:''"`
+	CreatedAt        time.Time  `gorm:"autoCreateTime"`
+	LastUsedAt       time.Time  `gorm:"column:last_used_at;not null"`
+	ExpiresAt        time.Time  `gorm:"column:expires_at;not null"`
+	RevokedAt        *time.Time `gorm:"column:revoked_at"`
+	RevokedReason    string     `gorm:"column:revoked_reason;not null;default:''"`
+
+	User User `gorm:"foreignKey:UserId"`
+}
+
+func (s *Session) IsActive(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
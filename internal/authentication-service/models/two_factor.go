@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RecoveryCode is a single use code that replaces a TOTP code when the authenticator is lost.
+type RecoveryCode struct {
+	Id        uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	UserId    uuid.UUID  `gorm:"column:user_id;not null;index"`
+	CodeHash  string     `gorm:"column:code_hash;not null"`
+	UsedAt    *time.Time `gorm:"column:used_at"`
+	CreatedAt time.Time  `gorm:"autoCreateTime"`
+}
+
+// TwoFactorChallenge links the password step of a login to the TOTP step that completes it.
+type TwoFactorChallenge struct {
+	Id        uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	UserId    uuid.UUID  `gorm:"column:user_id;not null;index"`
+	TokenHash string     `gorm:"column:token_hash;not null;unique"`
+	Attempts  int        `gorm:"not null;default:0"`
+	ExpiresAt time.Time  `gorm:"column:expires_at;not null"`
+	UsedAt    *time.Time `gorm:"column:used_at"`
+	CreatedAt time.Time  `gorm:"autoCreateTime"`
+
+	User User `gorm:"foreignKey:UserId"`
+}
+
+func (c *TwoFactorChallenge) IsUsable(now time.Time) bool {
+	return c.UsedAt == nil && now.Before(c.ExpiresAt)
+}
@@ -7,12 +7,33 @@ import (
 )
 
 type User struct {
-	Id                    uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
-	Email                 string    `gorm:"unique;not null"`
-	Password              string    `gorm:"not null"`
-	Verified              bool      `gorm:"default:false"`
-	CreatedAt             time.Time `gorm:"autoCreateTime"`
-	UpdatedAt             time.Time `gorm:"autoUpdateTime"`
-	IsAdmin               bool      `gorm:"default:false"`
-	RefreshTokenUpdatedAt time.Time `gorm:"autoUpdateTime"`
+	Id        uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	Email     string    `gorm:"unique;not null"`
+	Password  string    `gorm:"not null"`
+	Verified  bool      `gorm:"default:false"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+	IsAdmin   bool      `gorm:"default:false"`
+
+	FailedLoginAttempts int        `gorm:"not null;default:0"`
+	LockoutCount        int        `gorm:"not null;default:0"`
+	LockedUntil         *time.Time `gorm:"default:null"`
+	UnlockTokenHash     *string    `gorm:"default:null"`
+
+	TOTPSecret       *string `gorm:"column:totp_secret;default:null"`
+	TOTPEnabled      bool    `gorm:"column:totp_enabled;not null;default:false"`
+	TOTPLastUsedStep int64   `gorm:"column:totp_last_used_step;not null;default:0"`
+
+	DeletionScheduledAt     *time.Time `gorm:"default:null"`
+	DeletionCancelTokenHash *string    `gorm:"default:null"`
+}
+
+// IsLocked reports whether logins are blocked after too many failed attempts.
+func (u *User) IsLocked(now time.Time) bool {
+	return u.LockedUntil != nil && u.LockedUntil.After(now)
+}
+
+// IsDeletionScheduled reports whether the user asked to delete the account and it can still be restored.
+func (u *User) IsDeletionScheduled() bool {
+	return u.DeletionScheduledAt != nil
 }
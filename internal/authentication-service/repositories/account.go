@@ -10,6 +10,7 @@ import (
 	"github.com/FeedTheRealm-org/core-service/internal/utils/logger"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type AccountNotFoundError struct{}
@@ -18,6 +19,30 @@ type AccountNotVerifiedError struct{}
 
 type AccountVerificationExpired struct{}
 
+type SessionNotFoundError struct{}
+
+type TwoFactorChallengeNotFoundError struct{}
+
+type RecoveryCodeNotFoundError struct{}
+
+type TOTPCodeReusedError struct{}
+
+type IdentityNotFoundError struct{}
+
+type IdentityAlreadyLinkedError struct{}
+
+type OIDCLoginStateNotFoundError struct{}
+
+type EmailChangeNotFoundError struct{}
+
+type EmailChangeExpiredError struct{}
+
+type InvalidEmailChangeCodeError struct{}
+
+type EmailAlreadyInUseError struct{}
+
+type RoleNotFoundError struct{}
+
 type DatabaseError struct {
 	message string
 }
@@ -34,6 +59,54 @@ func (e *AccountVerificationExpired) Error() string {
 	return "Account verification has expired"
 }
 
+func (e *SessionNotFoundError) Error() string {
+	return "Session not found"
+}
+
+func (e *TwoFactorChallengeNotFoundError) Error() string {
+	return "Two-factor challenge not found"
+}
+
+func (e *RecoveryCodeNotFoundError) Error() string {
+	return "Recovery code not found or already used"
+}
+
+func (e *TOTPCodeReusedError) Error() string {
+	return "TOTP code was already used"
+}
+
+func (e *IdentityNotFoundError) Error() string {
+	return "Identity not found"
+}
+
+func (e *IdentityAlreadyLinkedError) Error() string {
+	return "Identity is already linked to an account"
+}
+
+func (e *OIDCLoginStateNotFoundError) Error() string {
+	return "OIDC login state not found or expired"
+}
+
+func (e *EmailChangeNotFoundError) Error() string {
+	return "Email change not found"
+}
+
+func (e *EmailChangeExpiredError) Error() string {
+	return "Email change code has expired"
+}
+
+func (e *InvalidEmailChangeCodeError) Error() string {
+	return "Email change code is invalid"
+}
+
+func (e *EmailAlreadyInUseError) Error() string {
+	return "Email is already in use"
+}
+
+func (e *RoleNotFoundError) Error() string {
+	return "Role not found"
+}
+
 func (e *DatabaseError) Error() string {
 	return "Database error occurred: " + e.message
 }
@@ -182,13 +255,6 @@ func (ar *accountRepository) RefreshVerificationCode(user *models.User, verifica
 	return nil
 }
 
-func (ar *accountRepository) UpdateRefreshTokenUpdatedAt(id uuid.UUID, updatedAt time.Time) error {
-	if err := ar.db.Conn.Model(&models.User{}).Where("id = ?", id).Update("refresh_token_updated_at", updatedAt).Error; err != nil {
-		return &DatabaseError{message: err.Error()}
-	}
-	return nil
-}
-
 func (ar *accountRepository) ListAccounts(query string, verified *bool, offset int, limit int) ([]models.User, int64, error) {
 	var users []models.User
 	dbQuery := ar.db.Conn.Model(&models.User{})
@@ -302,3 +368,583 @@ func (ar *accountRepository) UpdatePassword(userID uuid.UUID, hashedPassword str
 	}
 	return nil
 }
+
+func (ar *accountRepository) CreateSession(session *models.Session) error {
+	if err := ar.db.Conn.Create(session).Error; err != nil {
+		return &DatabaseError{message: err.Error()}
+	}
+	return nil
+}
+
+func (ar *accountRepository) GetSessionById(id uuid.UUID) (*models.Session, error) {
+	var session models.Session
+	if err := ar.db.Conn.Where("id = ?", id).First(&session).Error; err != nil {
+		if errors.IsRecordNotFound(err) {
+			return nil, &SessionNotFoundError{}
+		}
+		return nil, &DatabaseError{message: err.Error()}
+	}
+	return &session, nil
+}
+
+// RotateSessionRefreshToken swaps the refresh token of an active session, only if the
+// presented token is still the current one. Two refreshes racing with the same token
+// can therefore never both succeed.
+func (ar *accountRepository) RotateSessionRefreshToken(session *models.Session, previousTokenHash string) error {
+	result := ar.db.Conn.Model(&models.Session{}).
+		Where("id = ? AND refresh_token_hash = ? AND revoked_at IS NULL", session.Id, previousTokenHash).
+		Updates(map[string]interface{}{
+			"refresh_token_hash": session.RefreshTokenHash,
+			"user_agent":         session.UserAgent,
+			"ip_address":         session.IPAddress,
+			"last_used_at":       session.LastUsedAt,
+			"expires_at":         session.ExpiresAt,
+		})
+	if result.Error != nil {
+		return &DatabaseError{message: result.Error.Error()}
+	}
+	if result.RowsAffected == 0 {
+		return &SessionNotFoundError{}
+	}
+	return nil
+}
+
+func (ar *accountRepository) ListActiveSessions(userID uuid.UUID, now time.Time) ([]models.Session, error) {
+	var sessions []models.Session
+	if err := ar.db.Conn.
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, now).
+		Order("last_used_at DESC").
+		Find(&sessions).Error; err != nil {
+		return nil, &DatabaseError{message: err.Error()}
+	}
+	return sessions, nil
+}
+
+func (ar *accountRepository) RevokeSession(userID uuid.UUID, sessionID uuid.UUID, reason string) error {
+	result := ar.db.Conn.Model(&models.Session{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", sessionID, userID).
+		Updates(map[string]interface{}{
+			"revoked_at":     time.Now(),
+			"revoked_reason": reason,
+		})
+	if result.Error != nil {
+		return &DatabaseError{message: result.Error.Error()}
+	}
+	if result.RowsAffected == 0 {
+		return &SessionNotFoundError{}
+	}
+	return nil
+}
+
+func (ar *accountRepository) RevokeAllSessions(userID uuid.UUID, reason string) error {
+	if err := ar.db.Conn.Model(&models.Session{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Updates(map[string]interface{}{
+			"revoked_at":     time.Now(),
+			"revoked_reason": reason,
+		}).Error; err != nil {
+		return &DatabaseError{message: err.Error()}
+	}
+	return nil
+}
+
+// RevokeOtherSessions revokes every active session of the user except the one they are using.
+func (ar *accountRepository) RevokeOtherSessions(userID uuid.UUID, keepSessionID uuid.UUID, reason string) error {
+	if err := ar.db.Conn.Model(&models.Session{}).
+		Where("user_id = ? AND id <> ? AND revoked_at IS NULL", userID, keepSessionID).
+		Updates(map[string]interface{}{
+			"revoked_at":     time.Now(),
+			"revoked_reason": reason,
+		}).Error; err != nil {
+		return &DatabaseError{message: err.Error()}
+	}
+	return nil
+}
+
+// RecordFailedLogin increments the failed login counter and returns its new value.
+func (ar *accountRepository) RecordFailedLogin(userID uuid.UUID) (int, error) {
+	var attempts int
+	result := ar.db.Conn.Raw(
+		"UPDATE users SET failed_login_attempts = failed_login_attempts + 1 WHERE id = ? RETURNING failed_login_attempts",
+		userID,
+	).Scan(&attempts)
+	if result.Error != nil {
+		return 0, &DatabaseError{message: result.Error.Error()}
+	}
+	if result.RowsAffected == 0 {
+		return 0, &AccountNotFoundError{}
+	}
+	return attempts, nil
+}
+
+func (ar *accountRepository) LockAccount(userID uuid.UUID, until time.Time, unlockTokenHash string) error {
+	if err := ar.db.Conn.Model(&models.User{}).
+		Where("id = ?", userID).
+		UpdateColumns(map[string]interface{}{
+			"failed_login_attempts": 0,
+			"lockout_count":         gorm.Expr("lockout_count + 1"),
+			"locked_until":          until,
+			"unlock_token_hash":     unlockTokenHash,
+		}).Error; err != nil {
+		return &DatabaseError{message: err.Error()}
+	}
+	return nil
+}
+
+// ResetFailedLogins clears the failed attempts and lockout history after a successful login.
+func (ar *accountRepository) ResetFailedLogins(userID uuid.UUID) error {
+	if err := ar.db.Conn.Model(&models.User{}).
+		Where("id = ?", userID).
+		UpdateColumns(map[string]interface{}{
+			"failed_login_attempts": 0,
+			"lockout_count":         0,
+			"locked_until":          nil,
+			"unlock_token_hash":     nil,
+		}).Error; err != nil {
+		return &DatabaseError{message: err.Error()}
+	}
+	return nil
+}
+
+// UnlockAccount lifts a lockout still in effect, the lockout count is kept so repeated attacks keep escalating.
+func (ar *accountRepository) UnlockAccount(unlockTokenHash string, now time.Time) error {
+	result := ar.db.Conn.Model(&models.User{}).
+		Where("unlock_token_hash = ? AND locked_until > ?", unlockTokenHash, now).
+		UpdateColumns(map[string]interface{}{
+			"failed_login_attempts": 0,
+			"locked_until":          nil,
+			"unlock_token_hash":     nil,
+		})
+	if result.Error != nil {
+		return &DatabaseError{message: result.Error.Error()}
+	}
+	if result.RowsAffected == 0 {
+		return &AccountNotFoundError{}
+	}
+	return nil
+}
+
+// SetTOTPSecret stores a secret pending confirmation, it replaces any unconfirmed one.
+func (ar *accountRepository) SetTOTPSecret(userID uuid.UUID, secret string) error {
+	if err := ar.db.Conn.Model(&models.User{}).
+		Where("id = ? AND totp_enabled = false", userID).
+		Update("totp_secret", secret).Error; err != nil {
+		return &DatabaseError{message: err.Error()}
+	}
+	return nil
+}
+
+// EnableTOTP turns 2FA on and replaces the recovery codes of the user.
+func (ar *accountRepository) EnableTOTP(userID uuid.UUID, usedStep int64, recoveryCodeHashes []string) error {
+	return ar.db.Conn.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.User{}).
+			Where("id = ?", userID).
+			UpdateColumns(map[string]interface{}{
+				"totp_enabled":        true,
+				"totp_last_used_step": usedStep,
+			}).Error; err != nil {
+			return &DatabaseError{message: err.Error()}
+		}
+
+		if err := tx.Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error; err != nil {
+			return &DatabaseError{message: err.Error()}
+		}
+
+		codes := make([]models.RecoveryCode, 0, len(recoveryCodeHashes))
+		for _, hash := range recoveryCodeHashes {
+			codes = append(codes, models.RecoveryCode{UserId: userID, CodeHash: hash})
+		}
+		if len(codes) > 0 {
+			if err := tx.Create(&codes).Error; err != nil {
+				return &DatabaseError{message: err.Error()}
+			}
+		}
+		return nil
+	})
+}
+
+// UseTOTPStep records the time step of an accepted code, so the same code cannot be replayed.
+func (ar *accountRepository) UseTOTPStep(userID uuid.UUID, step int64) error {
+	result := ar.db.Conn.Model(&models.User{}).
+		Where("id = ? AND totp_last_used_step < ?", userID, step).
+		Update("totp_last_used_step", step)
+	if result.Error != nil {
+		return &DatabaseError{message: result.Error.Error()}
+	}
+	if result.RowsAffected == 0 {
+		return &TOTPCodeReusedError{}
+	}
+	return nil
+}
+
+func (ar *accountRepository) UseRecoveryCode(userID uuid.UUID, codeHash string) error {
+	result := ar.db.Conn.Model(&models.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return &DatabaseError{message: result.Error.Error()}
+	}
+	if result.RowsAffected == 0 {
+		return &RecoveryCodeNotFoundError{}
+	}
+	return nil
+}
+
+func (ar *accountRepository) CreateTwoFactorChallenge(challenge *models.TwoFactorChallenge) error {
+	if err := ar.db.Conn.Create(challenge).Error; err != nil {
+		return &DatabaseError{message: err.Error()}
+	}
+	return nil
+}
+
+func (ar *accountRepository) GetTwoFactorChallengeByTokenHash(tokenHash string) (*models.TwoFactorChallenge, error) {
+	var challenge models.TwoFactorChallenge
+	err := ar.db.Conn.Where("token_hash = ?", tokenHash).First(&challenge).Error
+	if err != nil {
+		if errors.IsRecordNotFound(err) {
+			return nil, &TwoFactorChallengeNotFoundError{}
+		}
+		return nil, &DatabaseError{message: err.Error()}
+	}
+	return &challenge, nil
+}
+
+func (ar *accountRepository) IncrementTwoFactorChallengeAttempts(challengeID uuid.UUID) error {
+	if err := ar.db.Conn.Model(&models.TwoFactorChallenge{}).
+		Where("id = ?", challengeID).
+		UpdateColumn("attempts", gorm.Expr("attempts + 1")).Error; err != nil {
+		return &DatabaseError{message: err.Error()}
+	}
+	return nil
+}
+
+// ConsumeTwoFactorChallenge marks a challenge used, only the first of concurrent completions succeeds.
+func (ar *accountRepository) ConsumeTwoFactorChallenge(challengeID uuid.UUID) error {
+	result := ar.db.Conn.Model(&models.TwoFactorChallenge{}).
+		Where("id = ? AND used_at IS NULL", challengeID).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return &DatabaseError{message: result.Error.Error()}
+	}
+	if result.RowsAffected == 0 {
+		return &TwoFactorChallengeNotFoundError{}
+	}
+	return nil
+}
+
+// CreateAccountWithIdentity creates an account signed up through an OIDC provider, verified
+// when the provider asserted the email, together with its verification code and identity.
+func (ar *accountRepository) CreateAccountWithIdentity(user *models.User, identity *models.Identity, verificationCode string) error {
+	return ar.db.Conn.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return &DatabaseError{message: err.Error()}
+		}
+
+		if err := tx.Create(&models.AccountVerification{UserId: user.Id, VerificationCode: verificationCode}).Error; err != nil {
+			return &DatabaseError{message: err.Error()}
+		}
+
+		identity.UserId = user.Id
+		if err := tx.Create(identity).Error; err != nil {
+			if errors.IsDuplicateEntryError(err) {
+				return &IdentityAlreadyLinkedError{}
+			}
+			return &DatabaseError{message: err.Error()}
+		}
+		return nil
+	})
+}
+
+func (ar *accountRepository) MarkAccountVerified(userID uuid.UUID) error {
+	if err := ar.db.Conn.Model(&models.User{}).
+		Where("id = ?", userID).
+		Update("verified", true).Error; err != nil {
+		return &DatabaseError{message: err.Error()}
+	}
+	return nil
+}
+
+func (ar *accountRepository) CreateIdentity(identity *models.Identity) error {
+	if err := ar.db.Conn.Create(identity).Error; err != nil {
+		if errors.IsDuplicateEntryError(err) {
+			return &IdentityAlreadyLinkedError{}
+		}
+		return &DatabaseError{message: err.Error()}
+	}
+	return nil
+}
+
+func (ar *accountRepository) GetIdentity(provider string, subject string) (*models.Identity, error) {
+	var identity models.Identity
+	err := ar.db.Conn.Where("provider = ? AND subject = ?", provider, subject).First(&identity).Error
+	if err != nil {
+		if errors.IsRecordNotFound(err) {
+			return nil, &IdentityNotFoundError{}
+		}
+		return nil, &DatabaseError{message: err.Error()}
+	}
+	return &identity, nil
+}
+
+func (ar *accountRepository) ListIdentities(userID uuid.UUID) ([]models.Identity, error) {
+	var identities []models.Identity
+	if err := ar.db.Conn.Where("user_id = ?", userID).Order("created_at ASC").Find(&identities).Error; err != nil {
+		return nil, &DatabaseError{message: err.Error()}
+	}
+	return identities, nil
+}
+
+func (ar *accountRepository) DeleteIdentity(userID uuid.UUID, provider string) error {
+	result := ar.db.Conn.Where("user_id = ? AND provider = ?", userID, provider).Delete(&models.Identity{})
+	if result.Error != nil {
+		return &DatabaseError{message: result.Error.Error()}
+	}
+	if result.RowsAffected == 0 {
+		return &IdentityNotFoundError{}
+	}
+	return nil
+}
+
+func (ar *accountRepository) CreateOIDCLoginState(state *models.OIDCLoginState) error {
+	// Abandoned authorization requests are cleaned up as new ones come in.
+	if err := ar.db.Conn.Where("expires_at < ?", time.Now()).Delete(&models.OIDCLoginState{}).Error; err != nil {
+		logger.Logger.Warnf("Failed to delete expired OIDC login states: %v", err)
+	}
+
+	if err := ar.db.Conn.Create(state).Error; err != nil {
+		return &DatabaseError{message: err.Error()}
+	}
+	return nil
+}
+
+// ConsumeOIDCLoginState deletes and returns a pending state, so each one is redeemed at most once.
+func (ar *accountRepository) ConsumeOIDCLoginState(stateHash string, provider string, now time.Time) (*models.OIDCLoginState, error) {
+	var states []models.OIDCLoginState
+	err := ar.db.Conn.Clauses(clause.Returning{}).
+		Where("state_hash = ? AND provider = ? AND expires_at > ?", stateHash, provider, now).
+		Delete(&states).Error
+	if err != nil {
+		return nil, &DatabaseError{message: err.Error()}
+	}
+	if len(states) == 0 {
+		return nil, &OIDCLoginStateNotFoundError{}
+	}
+	return &states[0], nil
+}
+
+// ScheduleAccountDeletion marks the account for deletion at the given time, the token lets the user restore it until then.
+func (ar *accountRepository) ScheduleAccountDeletion(userID uuid.UUID, scheduledAt time.Time, cancelTokenHash string) error {
+	result := ar.db.Conn.Model(&models.User{}).
+		Where("id = ?", userID).
+		UpdateColumns(map[string]interface{}{
+			"deletion_scheduled_at":      scheduledAt,
+			"deletion_cancel_token_hash": cancelTokenHash,
+		})
+	if result.Error != nil {
+		return &DatabaseError{message: result.Error.Error()}
+	}
+	if result.RowsAffected == 0 {
+		return &AccountNotFoundError{}
+	}
+	return nil
+}
+
+// CancelAccountDeletion restores an account whose grace period has not ended yet.
+func (ar *accountRepository) CancelAccountDeletion(cancelTokenHash string, now time.Time) error {
+	result := ar.db.Conn.Model(&models.User{}).
+		Where("deletion_cancel_token_hash = ? AND deletion_scheduled_at > ?", cancelTokenHash, now).
+		UpdateColumns(map[string]interface{}{
+			"deletion_scheduled_at":      nil,
+			"deletion_cancel_token_hash": nil,
+		})
+	if result.Error != nil {
+		return &DatabaseError{message: result.Error.Error()}
+	}
+	if result.RowsAffected == 0 {
+		return &AccountNotFoundError{}
+	}
+	return nil
+}
+
+// ListAccountsDueForDeletion returns up to limit accounts whose grace period ended before now, oldest first.
+func (ar *accountRepository) ListAccountsDueForDeletion(now time.Time, limit int) ([]models.User, error) {
+	var users []models.User
+	if err := ar.db.Conn.
+		Where("deletion_scheduled_at IS NOT NULL AND deletion_scheduled_at <= ?", now).
+		Order("deletion_scheduled_at ASC").
+		Limit(limit).
+		Find(&users).Error; err != nil {
+		return nil, &DatabaseError{message: err.Error()}
+	}
+	return users, nil
+}
+
+// DeleteAccount removes an account scheduled for deletion, sessions, identities and codes are deleted along with it.
+func (ar *accountRepository) DeleteAccount(userID uuid.UUID) error {
+	result := ar.db.Conn.Where("id = ? AND deletion_scheduled_at IS NOT NULL", userID).Delete(&models.User{})
+	if result.Error != nil {
+		return &DatabaseError{message: result.Error.Error()}
+	}
+	if result.RowsAffected == 0 {
+		return &AccountNotFoundError{}
+	}
+	return nil
+}
+
+// CreateEmailChange stores a pending email change, replacing any previous one of the user.
+func (ar *accountRepository) CreateEmailChange(change *models.EmailChange) error {
+	return ar.db.Conn.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", change.UserId).Delete(&models.EmailChange{}).Error; err != nil {
+			return &DatabaseError{message: err.Error()}
+		}
+		if err := tx.Create(change).Error; err != nil {
+			return &DatabaseError{message: err.Error()}
+		}
+		return nil
+	})
+}
+
+// ConfirmEmailChange applies the pending email change of the user when the code matches and returns the new email.
+// The change is discarded after 3 wrong codes.
+func (ar *accountRepository) ConfirmEmailChange(userID uuid.UUID, code string, now time.Time) (string, error) {
+	var change models.EmailChange
+	if err := ar.db.Conn.Where("user_id = ?", userID).First(&change).Error; err != nil {
+		if errors.IsRecordNotFound(err) {
+			return "", &EmailChangeNotFoundError{}
+		}
+		return "", &DatabaseError{message: err.Error()}
+	}
+
+	if change.ExpiresAt.Before(now) {
+		return "", &EmailChangeExpiredError{}
+	}
+
+	if change.VerificationCode != code {
+		change.Attempts += 1
+		if change.Attempts >= 3 {
+			if err := ar.db.Conn.Delete(&change).Error; err != nil {
+				return "", &DatabaseError{message: err.Error()}
+			}
+			return "", &InvalidEmailChangeCodeError{}
+		}
+		if err := ar.db.Conn.Model(&models.EmailChange{}).Where("user_id = ?", userID).Update("attempts", change.Attempts).Error; err != nil {
+			return "", &DatabaseError{message: err.Error()}
+		}
+		return "", &InvalidEmailChangeCodeError{}
+	}
+
+	err := ar.db.Conn.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.User{}).Where("id = ?", userID).Update("email", change.NewEmail).Error; err != nil {
+			if errors.IsDuplicateEntryError(err) {
+				return &EmailAlreadyInUseError{}
+			}
+			return &DatabaseError{message: err.Error()}
+		}
+		if err := tx.Delete(&change).Error; err != nil {
+			return &DatabaseError{message: err.Error()}
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return change.NewEmail, nil
+}
+
+func (ar *accountRepository) ListRoles() ([]models.Role, error) {
+	var roles []models.Role
+	if err := ar.db.Conn.Preload("Permissions").Order("name ASC").Find(&roles).Error; err != nil {
+		return nil, &DatabaseError{message: err.Error()}
+	}
+	return roles, nil
+}
+
+// SaveRole creates the role or replaces the description and permissions of an existing one.
+func (ar *accountRepository) SaveRole(role *models.Role) error {
+	return ar.db.Conn.Transaction(func(tx *gorm.DB) error {
+		err := tx.Omit("Permissions").Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "name"}},
+			DoUpdates: clause.AssignmentColumns([]string{"description"}),
+		}).Create(role).Error
+		if err != nil {
+			return &DatabaseError{message: err.Error()}
+		}
+		if err := tx.Where("role_name = ?", role.Name).Delete(&models.RolePermission{}).Error; err != nil {
+			return &DatabaseError{message: err.Error()}
+		}
+		for i := range role.Permissions {
+			role.Permissions[i].RoleName = role.Name
+		}
+		if len(role.Permissions) > 0 {
+			if err := tx.Create(&role.Permissions).Error; err != nil {
+				return &DatabaseError{message: err.Error()}
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteRole removes the role, users that had it lose its permissions.
+func (ar *accountRepository) DeleteRole(name string) error {
+	result := ar.db.Conn.Where("name = ?", name).Delete(&models.Role{})
+	if result.Error != nil {
+		return &DatabaseError{message: result.Error.Error()}
+	}
+	if result.RowsAffected == 0 {
+		return &RoleNotFoundError{}
+	}
+	return nil
+}
+
+func (ar *accountRepository) ListUserRoles(userID uuid.UUID) ([]string, error) {
+	var roles []string
+	err := ar.db.Conn.Model(&models.UserRole{}).
+		Where("user_id = ?", userID).
+		Order("role_name ASC").
+		Pluck("role_name", &roles).Error
+	if err != nil {
+		return nil, &DatabaseError{message: err.Error()}
+	}
+	return roles, nil
+}
+
+// SetUserRoles replaces the roles of the user, nothing changes when one of the roles does not exist.
+func (ar *accountRepository) SetUserRoles(userID uuid.UUID, roleNames []string) error {
+	return ar.db.Conn.Transaction(func(tx *gorm.DB) error {
+		var found int64
+		if len(roleNames) > 0 {
+			if err := tx.Model(&models.Role{}).Where("name IN ?", roleNames).Count(&found).Error; err != nil {
+				return &DatabaseError{message: err.Error()}
+			}
+		}
+		if int(found) != len(roleNames) {
+			return &RoleNotFoundError{}
+		}
+
+		if err := tx.Where("user_id = ?", userID).Delete(&models.UserRole{}).Error; err != nil {
+			return &DatabaseError{message: err.Error()}
+		}
+		for _, name := range roleNames {
+			if err := tx.Create(&models.UserRole{UserId: userID, RoleName: name}).Error; err != nil {
+				return &DatabaseError{message: err.Error()}
+			}
+		}
+		return nil
+	})
+}
+
+// GetUserPermissions returns the permissions granted by every role of the user.
+func (ar *accountRepository) GetUserPermissions(userID uuid.UUID) ([]string, error) {
+	var permissions []string
+	err := ar.db.Conn.Model(&models.RolePermission{}).
+		Joins("JOIN user_roles ON user_roles.role_name = role_permissions.role_name").
+		Where("user_roles.user_id = ?", userID).
+		Distinct().
+		Order("permission ASC").
+		Pluck("permission", &permissions).Error
+	if err != nil {
+		return nil, &DatabaseError{message: err.Error()}
+	}
+	return permissions, nil
+}
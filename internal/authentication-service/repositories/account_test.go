@@ -44,6 +44,7 @@ func repoTestEmail(prefix string) string {
 }
 
 func cleanupRepoUsers(db *config.DB) {
+	_ = db.Conn.Exec("DELETE FROM sessions WHERE user_id IN (SELECT id FROM users WHERE email LIKE '%@repo.local');")
 	_ = db.Conn.Exec("DELETE FROM password_resets WHERE user_id IN (SELECT id FROM users WHERE email LIKE '%@repo.local');")
 	_ = db.Conn.Exec("DELETE FROM account_verifications WHERE user_id IN (SELECT id FROM users WHERE email LIKE '%@repo.local');")
 	_ = db.Conn.Exec("DELETE FROM users WHERE email LIKE '%@repo.local';")
@@ -175,20 +176,288 @@ func TestAccountRepository_RefreshVerificationCode_UpdateExisting(t *testing.T)
 	assert.Equal(t, "newcode", record.VerificationCode)
 }
 
-func TestAccountRepository_UpdateRefreshTokenUpdatedAtAndAdminStatus(t *testing.T) {
+func TestAccountRepository_UpdateAdminStatus(t *testing.T) {
 	_, repo := setupTest(t)
 
 	user := &models.User{Email: repoTestEmail("admin"), Password: "hashed"}
 	assert.NoError(t, repo.CreateAccount(user, "code"))
-
-	now := time.Now().UTC()
-	assert.NoError(t, repo.UpdateRefreshTokenUpdatedAt(user.Id, now))
 	assert.NoError(t, repo.UpdateAdminStatus(user.Id, true))
 
 	stored, err := repo.GetAccountById(user.Id)
 	assert.NoError(t, err)
 	assert.True(t, stored.IsAdmin)
-	assert.True(t, stored.RefreshTokenUpdatedAt.After(now.Add(-time.Second)))
+}
+
+func newRepoSession(userID uuid.UUID, tokenHash string) *models.Session {
+	now := time.Now()
+	return &models.Session{
+		Id:               uuid.New(),
+		UserId:           userID,
+		RefreshTokenHash: tokenHash,
+		UserAgent:        "test-agent",
+		IPAddress:        "127.0.0.1",
+		LastUsedAt:       now,
+		ExpiresAt:        now.Add(time.Hour),
+	}
+}
+
+func TestAccountRepository_Sessions_RotateAndRevoke(t *testing.T) {
+	_, repo := setupTest(t)
+
+	user := &models.User{Email: repoTestEmail("sessions"), Password: "hashed"}
+	require.NoError(t, repo.CreateAccount(user, "code"))
+
+	first := newRepoSession(user.Id, "hash-1")
+	second := newRepoSession(user.Id, "hash-a")
+	require.NoError(t, repo.CreateSession(first))
+	require.NoError(t, repo.CreateSession(second))
+
+	first.RefreshTokenHash = "hash-2"
+	require.NoError(t, repo.RotateSessionRefreshToken(first, "hash-1"))
+
+	// The rotated-out token can no longer be used to rotate again
+	first.RefreshTokenHash = "hash-3"
+	_, notFound := repo.RotateSessionRefreshToken(first, "hash-1").(*repositories.SessionNotFoundError)
+	assert.True(t, notFound)
+
+	stored, err := repo.GetSessionById(first.Id)
+	require.NoError(t, err)
+	assert.Equal(t, "hash-2", stored.RefreshTokenHash)
+
+	_, notFound = repo.RevokeSession(uuid.New(), first.Id, models.SESSION_REVOKED_BY_USER).(*repositories.SessionNotFoundError)
+	assert.True(t, notFound, "sessions of other users cannot be revoked")
+	require.NoError(t, repo.RevokeSession(user.Id, first.Id, models.SESSION_REVOKED_BY_USER))
+
+	active, err := repo.ListActiveSessions(user.Id, time.Now())
+	require.NoError(t, err)
+	if assert.Len(t, active, 1) {
+		assert.Equal(t, second.Id, active[0].Id)
+	}
+
+	require.NoError(t, repo.RevokeAllSessions(user.Id, models.SESSION_REVOKED_BY_ADMIN))
+	active, err = repo.ListActiveSessions(user.Id, time.Now())
+	require.NoError(t, err)
+	assert.Empty(t, active)
+
+	stored, err = repo.GetSessionById(second.Id)
+	require.NoError(t, err)
+	assert.Equal(t, models.SESSION_REVOKED_BY_ADMIN, stored.RevokedReason)
+}
+
+func TestAccountRepository_Lockout(t *testing.T) {
+	_, repo := setupTest(t)
+
+	user := &models.User{Email: repoTestEmail("lockout"), Password: "hashed"}
+	require.NoError(t, repo.CreateAccount(user, "code"))
+
+	for expected := 1; expected <= 3; expected++ {
+		attempts, err := repo.RecordFailedLogin(user.Id)
+		require.NoError(t, err)
+		assert.Equal(t, expected, attempts)
+	}
+
+	now := time.Now()
+	require.NoError(t, repo.LockAccount(user.Id, now.Add(time.Minute*15), "unlock-hash"))
+	stored, err := repo.GetAccountById(user.Id)
+	require.NoError(t, err)
+	assert.True(t, stored.IsLocked(now))
+	assert.Equal(t, 0, stored.FailedLoginAttempts)
+	assert.Equal(t, 1, stored.LockoutCount)
+
+	_, notFound := repo.UnlockAccount("unlock-hash", now.Add(time.Hour)).(*repositories.AccountNotFoundError)
+	assert.True(t, notFound, "expired lockouts cannot be unlocked")
+
+	require.NoError(t, repo.UnlockAccount("unlock-hash", now))
+	stored, err = repo.GetAccountById(user.Id)
+	require.NoError(t, err)
+	assert.False(t, stored.IsLocked(now))
+	assert.Nil(t, stored.UnlockTokenHash)
+	assert.Equal(t, 1, stored.LockoutCount)
+
+	require.NoError(t, repo.ResetFailedLogins(user.Id))
+	stored, err = repo.GetAccountById(user.Id)
+	require.NoError(t, err)
+	assert.Equal(t, 0, stored.LockoutCount)
+}
+
+func TestAccountRepository_AccountDeletion(t *testing.T) {
+	_, repo := setupTest(t)
+
+	user := &models.User{Email: repoTestEmail("deletion"), Password: "hashed"}
+	require.NoError(t, repo.CreateAccount(user, "code"))
+
+	_, notFound := repo.DeleteAccount(user.Id).(*repositories.AccountNotFoundError)
+	assert.True(t, notFound, "only accounts scheduled for deletion can be deleted")
+
+	now := time.Now()
+	cancelHash := "cancel-" + user.Id.String()
+	require.NoError(t, repo.ScheduleAccountDeletion(user.Id, now.Add(time.Hour), cancelHash))
+	stored, err := repo.GetAccountById(user.Id)
+	require.NoError(t, err)
+	assert.True(t, stored.IsDeletionScheduled())
+
+	due, err := repo.ListAccountsDueForDeletion(now, 10)
+	require.NoError(t, err)
+	assert.NotContains(t, userIds(due), user.Id)
+
+	_, notFound = repo.CancelAccountDeletion(cancelHash, now.Add(2*time.Hour)).(*repositories.AccountNotFoundError)
+	assert.True(t, notFound, "deletions cannot be canceled after the grace period")
+	require.NoError(t, repo.CancelAccountDeletion(cancelHash, now))
+	stored, err = repo.GetAccountById(user.Id)
+	require.NoError(t, err)
+	assert.False(t, stored.IsDeletionScheduled())
+	assert.Nil(t, stored.DeletionCancelTokenHash)
+
+	require.NoError(t, repo.ScheduleAccountDeletion(user.Id, now.Add(-time.Minute), cancelHash))
+	due, err = repo.ListAccountsDueForDeletion(now, 10)
+	require.NoError(t, err)
+	assert.Contains(t, userIds(due), user.Id)
+
+	require.NoError(t, repo.DeleteAccount(user.Id))
+	_, err = repo.GetAccountById(user.Id)
+	assert.Error(t, err)
+}
+
+func userIds(users []models.User) []uuid.UUID {
+	ids := make([]uuid.UUID, len(users))
+	for i, user := range users {
+		ids[i] = user.Id
+	}
+	return ids
+}
+
+func TestAccountRepository_EmailChange(t *testing.T) {
+	_, repo := setupTest(t)
+
+	user := &models.User{Email: repoTestEmail("email-change"), Password: "hashed"}
+	require.NoError(t, repo.CreateAccount(user, "code"))
+	taken := &models.User{Email: repoTestEmail("email-taken"), Password: "hashed"}
+	require.NoError(t, repo.CreateAccount(taken, "code"))
+
+	now := time.Now()
+	_, err := repo.ConfirmEmailChange(user.Id, "123456", now)
+	assert.IsType(t, &repositories.EmailChangeNotFoundError{}, err)
+
+	require.NoError(t, repo.CreateEmailChange(&models.EmailChange{UserId: user.Id, NewEmail: taken.Email, VerificationCode: "111111", ExpiresAt: now.Add(time.Minute)}))
+	_, err = repo.ConfirmEmailChange(user.Id, "111111", now)
+	assert.IsType(t, &repositories.EmailAlreadyInUseError{}, err)
+
+	newEmail := repoTestEmail("email-new")
+	require.NoError(t, repo.CreateEmailChange(&models.EmailChange{UserId: user.Id, NewEmail: newEmail, VerificationCode: "123456", ExpiresAt: now.Add(time.Minute)}))
+	_, err = repo.ConfirmEmailChange(user.Id, "123456", now.Add(time.Hour))
+	assert.IsType(t, &repositories.EmailChangeExpiredError{}, err)
+	_, err = repo.ConfirmEmailChange(user.Id, "000000", now)
+	assert.IsType(t, &repositories.InvalidEmailChangeCodeError{}, err)
+
+	confirmed, err := repo.ConfirmEmailChange(user.Id, "123456", now)
+	require.NoError(t, err)
+	assert.Equal(t, newEmail, confirmed)
+	stored, err := repo.GetAccountById(user.Id)
+	require.NoError(t, err)
+	assert.Equal(t, newEmail, stored.Email)
+
+	_, err = repo.ConfirmEmailChange(user.Id, "123456", now)
+	assert.IsType(t, &repositories.EmailChangeNotFoundError{}, err)
+}
+
+func TestAccountRepository_RevokeOtherSessions(t *testing.T) {
+	_, repo := setupTest(t)
+
+	user := &models.User{Email: repoTestEmail("other-sessions"), Password: "hashed"}
+	require.NoError(t, repo.CreateAccount(user, "code"))
+	current := newRepoSession(user.Id, "current-"+user.Id.String())
+	other := newRepoSession(user.Id, "other-"+user.Id.String())
+	require.NoError(t, repo.CreateSession(current))
+	require.NoError(t, repo.CreateSession(other))
+
+	require.NoError(t, repo.RevokeOtherSessions(user.Id, current.Id, models.SESSION_REVOKED_PASSWORD_CHANGE))
+	active, err := repo.ListActiveSessions(user.Id, time.Now())
+	require.NoError(t, err)
+	require.Len(t, active, 1)
+	assert.Equal(t, current.Id, active[0].Id)
+
+	stored, err := repo.GetSessionById(other.Id)
+	require.NoError(t, err)
+	assert.Equal(t, models.SESSION_REVOKED_PASSWORD_CHANGE, stored.RevokedReason)
+}
+
+func TestAccountRepository_TwoFactor(t *testing.T) {
+	_, repo := setupTest(t)
+
+	user := &models.User{Email: repoTestEmail("2fa"), Password: "hashed"}
+	require.NoError(t, repo.CreateAccount(user, "code"))
+
+	require.NoError(t, repo.SetTOTPSecret(user.Id, "SECRET"))
+	require.NoError(t, repo.EnableTOTP(user.Id, 100, []string{"code-hash-1", "code-hash-2"}))
+	stored, err := repo.GetAccountById(user.Id)
+	require.NoError(t, err)
+	assert.True(t, stored.TOTPEnabled)
+	require.NotNil(t, stored.TOTPSecret)
+	assert.Equal(t, "SECRET", *stored.TOTPSecret)
+
+	require.NoError(t, repo.SetTOTPSecret(user.Id, "OTHER"))
+	stored, err = repo.GetAccountById(user.Id)
+	require.NoError(t, err)
+	assert.Equal(t, "SECRET", *stored.TOTPSecret, "the secret cannot change once enabled")
+
+	_, reused := repo.UseTOTPStep(user.Id, 100).(*repositories.TOTPCodeReusedError)
+	assert.True(t, reused)
+	require.NoError(t, repo.UseTOTPStep(user.Id, 101))
+
+	require.NoError(t, repo.UseRecoveryCode(user.Id, "code-hash-1"))
+	_, notFound := repo.UseRecoveryCode(user.Id, "code-hash-1").(*repositories.RecoveryCodeNotFoundError)
+	assert.True(t, notFound)
+
+	challenge := &models.TwoFactorChallenge{UserId: user.Id, TokenHash: "challenge-" + user.Id.String(), ExpiresAt: time.Now().Add(time.Minute)}
+	require.NoError(t, repo.CreateTwoFactorChallenge(challenge))
+	require.NoError(t, repo.IncrementTwoFactorChallengeAttempts(challenge.Id))
+	found, err := repo.GetTwoFactorChallengeByTokenHash(challenge.TokenHash)
+	require.NoError(t, err)
+	assert.Equal(t, 1, found.Attempts)
+
+	require.NoError(t, repo.ConsumeTwoFactorChallenge(challenge.Id))
+	_, notFound = repo.ConsumeTwoFactorChallenge(challenge.Id).(*repositories.TwoFactorChallengeNotFoundError)
+	assert.True(t, notFound)
+}
+
+func TestAccountRepository_Identities(t *testing.T) {
+	_, repo := setupTest(t)
+
+	user := &models.User{Email: repoTestEmail("oidc"), Verified: true}
+	identity := &models.Identity{Provider: "google", Subject: "sub-" + user.Email, Email: user.Email}
+	require.NoError(t, repo.CreateAccountWithIdentity(user, identity, "code"))
+
+	found, err := repo.GetIdentity("google", identity.Subject)
+	require.NoError(t, err)
+	assert.Equal(t, user.Id, found.UserId)
+
+	_, linked := repo.CreateIdentity(&models.Identity{UserId: user.Id, Provider: "google", Subject: "another"}).(*repositories.IdentityAlreadyLinkedError)
+	assert.True(t, linked, "a user links one account per provider")
+
+	identities, err := repo.ListIdentities(user.Id)
+	require.NoError(t, err)
+	assert.Len(t, identities, 1)
+
+	require.NoError(t, repo.DeleteIdentity(user.Id, "google"))
+	_, notFound := repo.DeleteIdentity(user.Id, "google").(*repositories.IdentityNotFoundError)
+	assert.True(t, notFound)
+}
+
+func TestAccountRepository_OIDCLoginState(t *testing.T) {
+	_, repo := setupTest(t)
+
+	state := &models.OIDCLoginState{StateHash: "state-" + uuid.NewString(), Provider: "google", CodeVerifier: "verifier", Nonce: "nonce", ExpiresAt: time.Now().Add(time.Minute)}
+	require.NoError(t, repo.CreateOIDCLoginState(state))
+
+	_, notFound := repo.ConsumeOIDCLoginState(state.StateHash, "discord", time.Now())
+	assert.IsType(t, &repositories.OIDCLoginStateNotFoundError{}, notFound)
+
+	consumed, err := repo.ConsumeOIDCLoginState(state.StateHash, "google", time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, "verifier", consumed.CodeVerifier)
+
+	_, err = repo.ConsumeOIDCLoginState(state.StateHash, "google", time.Now())
+	assert.IsType(t, &repositories.OIDCLoginStateNotFoundError{}, err)
 }
 
 func TestAccountRepository_ListAccounts_FilterAndVerified(t *testing.T) {
@@ -478,8 +747,8 @@ func TestAccountRepository_DatabaseErrors_Coverage(t *testing.T) {
 		assert.Contains(t, err.Error(), "Database error occurred")
 	})
 
-	t.Run("UpdateRefreshTokenUpdatedAt_DB_Error", func(t *testing.T) {
-		err := badRepo.UpdateRefreshTokenUpdatedAt(id, time.Now())
+	t.Run("ListActiveSessions_DB_Error", func(t *testing.T) {
+		_, err := badRepo.ListActiveSessions(id, time.Now())
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "Database error occurred")
 	})
@@ -538,3 +807,41 @@ func TestAccountRepository_DatabaseErrors_Coverage(t *testing.T) {
 		assert.Contains(t, err.Error(), "Database error occurred")
 	})
 }
+
+func TestAccountRepository_Roles(t *testing.T) {
+	_, repo := setupTest(t)
+
+	user := &models.User{Email: repoTestEmail("roles"), Password: "hashed"}
+	require.NoError(t, repo.CreateAccount(user, "code"))
+
+	support := &models.Role{Name: "support-" + uuid.NewString()[:8], Permissions: []models.RolePermission{{Permission: "auth.users.read"}, {Permission: "payments.balances.read"}}}
+	finance := &models.Role{Name: "finance-" + uuid.NewString()[:8], Permissions: []models.RolePermission{{Permission: "payments.balances.read"}}}
+	require.NoError(t, repo.SaveRole(support))
+	require.NoError(t, repo.SaveRole(finance))
+
+	assert.IsType(t, &repositories.RoleNotFoundError{}, repo.SetUserRoles(user.Id, []string{support.Name, "missing-role"}))
+	require.NoError(t, repo.SetUserRoles(user.Id, []string{support.Name, finance.Name}))
+
+	granted, err := repo.GetUserPermissions(user.Id)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"auth.users.read", "payments.balances.read"}, granted)
+
+	support.Description = "Helps players"
+	support.Permissions = []models.RolePermission{{Permission: "payments.metrics.read"}}
+	require.NoError(t, repo.SaveRole(support))
+	granted, err = repo.GetUserPermissions(user.Id)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"payments.balances.read", "payments.metrics.read"}, granted)
+
+	require.NoError(t, repo.DeleteRole(finance.Name))
+	assert.IsType(t, &repositories.RoleNotFoundError{}, repo.DeleteRole(finance.Name))
+	roles, err := repo.ListUserRoles(user.Id)
+	require.NoError(t, err)
+	assert.Equal(t, []string{support.Name}, roles)
+
+	require.NoError(t, repo.SetUserRoles(user.Id, nil))
+	granted, err = repo.GetUserPermissions(user.Id)
+	require.NoError(t, err)
+	assert.Empty(t, granted)
+	require.NoError(t, repo.DeleteRole(support.Name))
+}
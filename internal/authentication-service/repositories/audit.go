@@ -0,0 +1,68 @@
+package repositories
+
+import (
+	"strings"
+
+	"github.com/FeedTheRealm-org/core-service/config"
+	"github.com/FeedTheRealm-org/core-service/internal/authentication-service/models"
+)
+
+type auditRepository struct {
+	conf *config.Config
+	db   *config.DB
+}
+
+func NewAuditRepository(conf *config.Config, db *config.DB) AuditRepository {
+	return &auditRepository{
+		conf: conf,
+		db:   db,
+	}
+}
+
+func (ar *auditRepository) CreateAuditEvent(event *models.AuditEvent) error {
+	if err := ar.db.Conn.Create(event).Error; err != nil {
+		return &DatabaseError{message: err.Error()}
+	}
+	return nil
+}
+
+// ListAuditEvents returns the events matching the filter, newest first, and how many match in total.
+func (ar *auditRepository) ListAuditEvents(filter AuditEventFilter) ([]models.AuditEvent, int64, error) {
+	query := ar.db.Conn.Model(&models.AuditEvent{})
+	if filter.ActorId != nil {
+		query = query.Where("actor_id = ?", *filter.ActorId)
+	}
+	if prefix, ok := strings.CutSuffix(filter.Action, "*"); ok {
+		query = query.Where("action LIKE ?", escapeLike(prefix)+"%")
+	} else if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.TargetType != "" {
+		query = query.Where("target_type = ?", filter.TargetType)
+	}
+	if filter.TargetId != "" {
+		query = query.Where("target_id = ?", filter.TargetId)
+	}
+	if filter.From != nil {
+		query = query.Where("occurred_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("occurred_at < ?", *filter.To)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, &DatabaseError{message: err.Error()}
+	}
+
+	var events []models.AuditEvent
+	if err := query.Order("occurred_at DESC, id").Offset(filter.Offset).Limit(filter.Limit).Find(&events).Error; err != nil {
+		return nil, 0, &DatabaseError{message: err.Error()}
+	}
+
+	return events, total, nil
+}
+
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
@@ -0,0 +1,48 @@
+package repositories_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/FeedTheRealm-org/core-service/internal/authentication-service/models"
+	"github.com/FeedTheRealm-org/core-service/internal/authentication-service/repositories"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+)
+
+func TestAuditRepository_ListAuditEvents(t *testing.T) {
+	require.NotNil(t, sharedDB, "sharedDB is nil — TestMain did not run")
+	repo := repositories.NewAuditRepository(nil, sharedDB)
+
+	actorId := uuid.New()
+	now := time.Now().UTC().Truncate(time.Second)
+	events := []*models.AuditEvent{
+		{ActorId: actorId, Action: "payments.gem_balances.update", TargetType: "user", TargetId: "1", After: datatypes.JSON(`{"gems":5}`), OccurredAt: now.Add(-2 * time.Hour)},
+		{ActorId: actorId, Action: "payments.payouts.approve", TargetType: "payout", TargetId: "2", OccurredAt: now.Add(-time.Hour)},
+		{ActorId: actorId, Action: "auth.roles.save", TargetType: "role", TargetId: "support", OccurredAt: now},
+	}
+	for _, event := range events {
+		require.NoError(t, repo.CreateAuditEvent(event))
+	}
+
+	listed, total, err := repo.ListAuditEvents(repositories.AuditEventFilter{ActorId: &actorId, Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	require.Len(t, listed, 2)
+	assert.Equal(t, "auth.roles.save", listed[0].Action)
+
+	listed, total, err = repo.ListAuditEvents(repositories.AuditEventFilter{ActorId: &actorId, Action: "payments.*", Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+
+	from := now.Add(-90 * time.Minute)
+	listed, _, err = repo.ListAuditEvents(repositories.AuditEventFilter{ActorId: &actorId, TargetType: "payout", TargetId: "2", From: &from, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	assert.Equal(t, "payments.payouts.approve", listed[0].Action)
+
+	err = sharedDB.Conn.Model(&models.AuditEvent{}).Where("id = ?", events[0].Id).Update("action", "tampered").Error
+	assert.Error(t, err, "audit events must be append-only")
+}
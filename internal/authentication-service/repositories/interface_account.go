@@ -13,7 +13,6 @@ type AccountRepository interface {
 	CreateAccount(user *models.User, verificationCode string) error
 	VerifyAccount(user *models.User, code string, currentTime time.Time) error
 	RefreshVerificationCode(user *models.User, verificationCode string, expiresAt time.Time) error
-	UpdateRefreshTokenUpdatedAt(id uuid.UUID, updatedAt time.Time) error
 	ListAccounts(query string, verified *bool, offset int, limit int) ([]models.User, int64, error)
 	UpdateAdminStatus(id uuid.UUID, isAdmin bool) error
 	CreatePasswordReset(userID uuid.UUID, otpHash string, expiresAt time.Time) (*models.PasswordReset, error)
@@ -23,4 +22,43 @@ type AccountRepository interface {
 	GetPasswordResetByTokenHash(tokenHash string) (*models.PasswordReset, error)
 	InvalidateAllPasswordResets(userID uuid.UUID) error
 	UpdatePassword(userID uuid.UUID, hashedPassword string) error
+	CreateSession(session *models.Session) error
+	GetSessionById(id uuid.UUID) (*models.Session, error)
+	RotateSessionRefreshToken(session *models.Session, previousTokenHash string) error
+	ListActiveSessions(userID uuid.UUID, now time.Time) ([]models.Session, error)
+	RevokeSession(userID uuid.UUID, sessionID uuid.UUID, reason string) error
+	RevokeAllSessions(userID uuid.UUID, reason string) error
+	RevokeOtherSessions(userID uuid.UUID, keepSessionID uuid.UUID, reason string) error
+	RecordFailedLogin(userID uuid.UUID) (int, error)
+	LockAccount(userID uuid.UUID, until time.Time, unlockTokenHash string) error
+	ResetFailedLogins(userID uuid.UUID) error
+	UnlockAccount(unlockTokenHash string, now time.Time) error
+	SetTOTPSecret(userID uuid.UUID, secret string) error
+	EnableTOTP(userID uuid.UUID, usedStep int64, recoveryCodeHashes []string) error
+	UseTOTPStep(userID uuid.UUID, step int64) error
+	UseRecoveryCode(userID uuid.UUID, codeHash string) error
+	CreateTwoFactorChallenge(challenge *models.TwoFactorChallenge) error
+	GetTwoFactorChallengeByTokenHash(tokenHash string) (*models.TwoFactorChallenge, error)
+	IncrementTwoFactorChallengeAttempts(challengeID uuid.UUID) error
+	ConsumeTwoFactorChallenge(challengeID uuid.UUID) error
+	CreateAccountWithIdentity(user *models.User, identity *models.Identity, verificationCode string) error
+	MarkAccountVerified(userID uuid.UUID) error
+	CreateIdentity(identity *models.Identity) error
+	GetIdentity(provider string, subject string) (*models.Identity, error)
+	ListIdentities(userID uuid.UUID) ([]models.Identity, error)
+	DeleteIdentity(userID uuid.UUID, provider string) error
+	CreateOIDCLoginState(state *models.OIDCLoginState) error
+	ConsumeOIDCLoginState(stateHash string, provider string, now time.Time) (*models.OIDCLoginState, error)
+	ScheduleAccountDeletion(userID uuid.UUID, scheduledAt time.Time, cancelTokenHash string) error
+	CancelAccountDeletion(cancelTokenHash string, now time.Time) error
+	ListAccountsDueForDeletion(now time.Time, limit int) ([]models.User, error)
+	DeleteAccount(userID uuid.UUID) error
+	CreateEmailChange(change *models.EmailChange) error
+	ConfirmEmailChange(userID uuid.UUID, code string, now time.Time) (string, error)
+	ListRoles() ([]models.Role, error)
+	SaveRole(role *models.Role) error
+	DeleteRole(name string) error
+	ListUserRoles(userID uuid.UUID) ([]string, error)
+	SetUserRoles(userID uuid.UUID, roleNames []string) error
+	GetUserPermissions(userID uuid.UUID) ([]string, error)
 }
@@ -0,0 +1,26 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/FeedTheRealm-org/core-service/internal/authentication-service/models"
+	"github.com/google/uuid"
+)
+
+// AuditEventFilter narrows the audit events listed, zero values match every event.
+// An action ending in * matches every action with that prefix.
+type AuditEventFilter struct {
+	ActorId    *uuid.UUID
+	Action     string
+	TargetType string
+	TargetId   string
+	From       *time.Time
+	To         *time.Time
+	Offset     int
+	Limit      int
+}
+
+type AuditRepository interface {
+	CreateAuditEvent(event *models.AuditEvent) error
+	ListAuditEvents(filter AuditEventFilter) ([]models.AuditEvent, int64, error)
+}
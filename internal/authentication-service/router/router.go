@@ -1,48 +1,113 @@
 package router
 
 import (
+	"time"
+
 	"github.com/FeedTheRealm-org/core-service/config"
 	"github.com/FeedTheRealm-org/core-service/internal/authentication-service/controllers"
 	"github.com/FeedTheRealm-org/core-service/internal/authentication-service/repositories"
 	"github.com/FeedTheRealm-org/core-service/internal/authentication-service/services"
+	"github.com/FeedTheRealm-org/core-service/internal/middleware"
+	"github.com/FeedTheRealm-org/core-service/internal/service_clients"
 	"github.com/FeedTheRealm-org/core-service/internal/utils/email_sender"
 	"github.com/FeedTheRealm-org/core-service/internal/utils/logger"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/oidc_login"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/permissions"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/rate_limiter"
 	"github.com/FeedTheRealm-org/core-service/internal/utils/session"
 	"github.com/gin-gonic/gin"
 )
 
-func SetupAuthenticationServiceRouter(r *gin.Engine, conf *config.Config, db *config.DB, jwtManager *session.JWTManager) error {
+func SetupAuthenticationServiceRouter(r *gin.Engine, internal *gin.RouterGroup, conf *config.Config, db *config.DB, jwtManager *session.JWTManager, clients *service_clients.Clients) error {
 	g := r.Group("/auth")
+	internalGroup := internal.Group("/auth/internal")
 	accountRepo, err := repositories.NewAccountRepository(conf, db)
 	if err != nil {
 		logger.Logger.Errorf("Failed to connect to DB: %v", err)
 	}
 
-	accountService := services.NewAccountService(conf, accountRepo, jwtManager)
+	accountService := services.NewAccountService(conf, accountRepo, jwtManager, oidc_login.NewProviders(conf), clients)
+	clients.ProvideAccounts(services.NewAccountsClient(accountService))
+	accountService.StartDeletionPurge()
+
+	auditService := services.NewAuditService(repositories.NewAuditRepository(conf, db))
+	clients.ProvideAudit(services.NewAuditClient(auditService))
+	auditController := controllers.NewAuditController(auditService)
 	emailService := email_sender.NewEmailSenderService(conf)
 	accountController := controllers.NewAccountController(conf, accountService, emailService)
 
-	adminController := controllers.NewAdminLoginController(accountService)
+	adminController := controllers.NewAdminLoginController(conf, accountService)
+	keysController := controllers.NewKeysController(jwtManager)
+
+	limiter := rate_limiter.NewRateLimiter(conf, db.Conn)
+	limits := conf.RateLimit
+	perIP := middleware.RateLimitByIP("auth", rate_limiter.Limit{Requests: limits.AuthPerIPPerMinute, Per: time.Minute})
+	loginPerEmail := middleware.RateLimitByEmail("login", rate_limiter.Limit{Requests: limits.LoginPerEmailPerMinute, Per: time.Minute})
+	emailsPerAddress := middleware.RateLimitByEmail("emails", rate_limiter.Limit{Requests: limits.EmailsPerHour, Per: time.Hour})
+	signupPerIP := middleware.RateLimitByIP("signup", rate_limiter.Limit{Requests: limits.SignupPerIPPerHour, Per: time.Hour})
 
-	g.POST("/signup", accountController.CreateAccount)
-	g.POST("/login", accountController.LoginAccount)
-	g.POST("/verify", accountController.VerifyAccount)
-	g.POST("/refresh", accountController.RefreshVerification)
-	g.POST("/refresh-token", accountController.RefreshToken)
+	g.POST("/signup", middleware.RateLimitMiddleware(limiter, perIP, signupPerIP), accountController.CreateAccount)
+	g.POST("/login", middleware.RateLimitMiddleware(limiter, perIP, loginPerEmail), accountController.LoginAccount)
+	g.POST("/login/2fa", middleware.RateLimitMiddleware(limiter, perIP), accountController.LoginTwoFactor)
+	g.POST("/verify", middleware.RateLimitMiddleware(limiter, perIP, loginPerEmail), accountController.VerifyAccount)
+	g.POST("/refresh", middleware.RateLimitMiddleware(limiter, perIP, emailsPerAddress), accountController.RefreshVerification)
+	g.POST("/refresh-token", middleware.RateLimitMiddleware(limiter, perIP), accountController.RefreshToken)
+	g.GET("/unlock", middleware.RateLimitMiddleware(limiter, perIP), accountController.UnlockAccount)
+	g.POST("/logout", accountController.Logout)
+	g.GET("/sessions", accountController.ListSessions)
+	g.DELETE("/sessions/:id", accountController.RevokeSession)
 	g.GET("/check-session", accountController.CheckSessionExpiration)
 	g.GET("/session", accountController.CheckAdminSession)
-	g.GET("/users", accountController.ListUsers)
-	g.PUT("/users/:id/admin", accountController.UpdateAdminStatus)
+	g.GET("/users", middleware.RequirePermission(permissions.USERS_READ), accountController.ListUsers)
+	g.PUT("/users/:id/admin", middleware.AuditMiddleware(clients.Audit, "auth.users.admin.update", "user", "id"), accountController.UpdateAdminStatus)
+	g.DELETE("/users/:id/sessions", middleware.RequirePermission(permissions.USERS_SESSIONS_REVOKE), middleware.AuditMiddleware(clients.Audit, "auth.users.sessions.revoke", "user", "id"), accountController.RevokeAllUserSessions)
 
-	password := g.Group("/password")
+	manageRoles := middleware.RequirePermission(permissions.ROLES_MANAGE)
+	g.GET("/roles", manageRoles, accountController.ListRoles)
+	g.PUT("/roles/:name", manageRoles, middleware.AuditMiddleware(clients.Audit, "auth.roles.save", "role", "name"), accountController.SaveRole)
+	g.DELETE("/roles/:name", manageRoles, middleware.AuditMiddleware(clients.Audit, "auth.roles.delete", "role", "name"), accountController.DeleteRole)
+	g.GET("/users/:id/roles", manageRoles, accountController.GetUserRoles)
+	g.PUT("/users/:id/roles", manageRoles, middleware.AuditMiddleware(clients.Audit, "auth.users.roles.update", "user", "id"), accountController.SetUserRoles)
+
+	r.GET("/.well-known/jwks.json", keysController.GetJWKS)
+	r.GET("/admin/audit", middleware.RequirePermission(permissions.AUDIT_READ), auditController.ListAuditEvents)
+
+	password := g.Group("/password", middleware.RateLimitMiddleware(limiter, perIP))
 	{
-		password.POST("/forgot", accountController.ForgotPassword)
-		password.POST("/verify-code", accountController.VerifyPasswordCode)
+		password.POST("/forgot", middleware.RateLimitMiddleware(limiter, emailsPerAddress), accountController.ForgotPassword)
+		password.POST("/verify-code", middleware.RateLimitMiddleware(limiter, loginPerEmail), accountController.VerifyPasswordCode)
 		password.POST("/reset", accountController.ResetPassword)
 	}
 
+	twoFactor := g.Group("/2fa")
+	{
+		twoFactor.POST("/setup", accountController.SetupTwoFactor)
+		twoFactor.POST("/confirm", middleware.RateLimitMiddleware(limiter, perIP), accountController.ConfirmTwoFactor)
+	}
+
+	oidc := g.Group("/oidc")
+	{
+		oidc.GET("/providers", accountController.ListOIDCProviders)
+		oidc.POST("/:provider/authorize", middleware.RateLimitMiddleware(limiter, perIP), accountController.AuthorizeOIDC)
+		oidc.POST("/:provider/callback", middleware.RateLimitMiddleware(limiter, perIP), accountController.OIDCCallback)
+		oidc.POST("/:provider/link", accountController.LinkOIDC)
+		oidc.POST("/:provider/link/callback", middleware.RateLimitMiddleware(limiter, perIP), accountController.OIDCLinkCallback)
+	}
+	g.GET("/identities", accountController.ListIdentities)
+	g.DELETE("/identities/:provider", accountController.UnlinkIdentity)
+	g.GET("/me/export", accountController.ExportUserData)
+	g.DELETE("/me", accountController.DeleteAccount)
+	g.POST("/me/password", middleware.RateLimitMiddleware(limiter, perIP), accountController.ChangePassword)
+	g.POST("/me/email", middleware.RateLimitMiddleware(limiter, perIP), accountController.RequestEmailChange)
+	g.POST("/me/email/verify", middleware.RateLimitMiddleware(limiter, perIP), accountController.ConfirmEmailChange)
+	g.GET("/deletion/cancel", middleware.RateLimitMiddleware(limiter, perIP), accountController.CancelAccountDeletion)
+
 	g.GET("", adminController.AdminLoginPageHandler)
-	g.POST("", adminController.AdminLoginHandler)
+	g.POST("", middleware.RateLimitMiddleware(limiter, perIP), adminController.AdminLoginHandler)
+
+	// Internal routes, only used when services are split out
+	internalGroup.GET("/users/:user_id", accountController.GetUserInternal)
+	internalGroup.POST("/audit", auditController.RecordAuditEventInternal)
 
 	return nil
 }
@@ -2,7 +2,6 @@ package services
 
 import (
 	"crypto/rand"
-	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
 	"math/big"
@@ -16,7 +15,9 @@ import (
 	code_generator "github.com/FeedTheRealm-org/core-service/internal/authentication-service/utils/code-generator"
 	validator "github.com/FeedTheRealm-org/core-service/internal/authentication-service/utils/credential-validation"
 	"github.com/FeedTheRealm-org/core-service/internal/authentication-service/utils/hashing"
+	"github.com/FeedTheRealm-org/core-service/internal/service_clients"
 	"github.com/FeedTheRealm-org/core-service/internal/utils/logger"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/oidc_login"
 	"github.com/FeedTheRealm-org/core-service/internal/utils/session"
 	"github.com/google/uuid"
 )
@@ -28,9 +29,11 @@ const (
 )
 
 type accountService struct {
-	conf *config.Config
-	repo repositories.AccountRepository
-	jwt  *session.JWTManager
+	conf          *config.Config
+	repo          repositories.AccountRepository
+	jwt           *session.JWTManager
+	oidcProviders map[string]oidc_login.Provider
+	clients       *service_clients.Clients
 }
 
 type AccountNotFoundError struct{}
@@ -69,6 +72,12 @@ func (e *AccountSessionInvalid) Error() string {
 	return "Session is invalid"
 }
 
+type SessionNotFoundError struct{}
+
+func (e *SessionNotFoundError) Error() string {
+	return "Session not found"
+}
+
 type AccountNotVerifiedError struct{}
 
 func (e *AccountNotVerifiedError) Error() string {
@@ -146,7 +155,7 @@ func (s *accountService) seedAdminAccount(conf *config.Config) {
 		return
 	}
 
-	if _, _, _, err := s.LoginAccount(adminEmail, adminPassword, true); err == nil {
+	if _, err := s.repo.GetAccountByEmail(strings.ToLower(adminEmail)); err == nil {
 		logger.Logger.Infof("Admin account already exists with email: %s", adminEmail)
 		return
 	}
@@ -164,11 +173,13 @@ func (s *accountService) seedAdminAccount(conf *config.Config) {
 	}
 }
 
-func NewAccountService(conf *config.Config, repo repositories.AccountRepository, jwtManager *session.JWTManager) AccountService {
+func NewAccountService(conf *config.Config, repo repositories.AccountRepository, jwtManager *session.JWTManager, oidcProviders map[string]oidc_login.Provider, clients *service_clients.Clients) AccountService {
 	newAccountService := &accountService{
-		conf: conf,
-		repo: repo,
-		jwt:  jwtManager,
+		conf:          conf,
+		repo:          repo,
+		jwt:           jwtManager,
+		oidcProviders: oidcProviders,
+		clients:       clients,
 	}
 
 	newAccountService.seedAdminAccount(conf)
@@ -186,6 +197,15 @@ func (s *accountService) GetUserByEmail(email string) (*models.User, error) {
 	return user, nil
 }
 
+func (s *accountService) GetUserById(id uuid.UUID) (*models.User, error) {
+	user, err := s.repo.GetAccountById(id)
+	if err != nil {
+		return nil, &AccountNotFoundError{}
+	}
+
+	return user, nil
+}
+
 func (s *accountService) CreateAccount(email string, password string, isAdmin bool) (*models.User, string, error) {
 	email = strings.ToLower(email)
 	existingUser, err := s.repo.GetAccountByEmail(email)
@@ -242,18 +262,13 @@ func (s *accountService) CreateAccount(email string, password string, isAdmin bo
 		return nil, "", &AccountFailedToCreateError{}
 	}
 
-	functionGenerator := mathrand.Int
-	if s.conf.Server.Environment == config.Testing {
-		functionGenerator = code_generator.StaticGenerateCode
-	}
-
 	user := &models.User{
 		Email:    email,
 		Password: string(hashedPassword),
 		IsAdmin:  isAdmin,
 	}
 
-	verificationCode := code_generator.GenerateCode(functionGenerator)
+	verificationCode := s.newVerificationCode()
 	err = s.repo.CreateAccount(user, verificationCode)
 	if err != nil {
 		return nil, "", &AccountFailedToCreateError{}
@@ -262,35 +277,36 @@ func (s *accountService) CreateAccount(email string, password string, isAdmin bo
 	return user, verificationCode, nil
 }
 
-func (s *accountService) LoginAccount(email string, password string, isAdminReq bool) (*models.User, string, string, error) {
+func (s *accountService) LoginAccount(email string, password string, isAdminReq bool, device SessionDevice) (*models.User, string, string, error) {
 	email = strings.ToLower(email)
 	user, err := s.repo.GetAccountByEmail(email)
 	if err != nil {
 		return nil, "", "", &AccountNotFoundError{}
 	}
 
+	now := time.Now()
+	if user.IsLocked(now) {
+		return nil, "", "", &AccountLockedError{RetryAfter: user.LockedUntil.Sub(now)}
+	}
+
 	isPasswordValid := hashing.VerifyPassword(user.Password, password)
 	if !isPasswordValid {
-		return nil, "", "", &AccountNotFoundError{}
+		return nil, "", "", s.recordFailedLogin(user, now)
 	}
 
 	if !user.Verified && !isAdminReq {
 		return nil, "", "", &AccountNotVerifiedError{}
 	}
 
-	accessToken, err := s.jwt.GenerateAccessToken(user.Id.String(), user.Email, user.IsAdmin)
-	if err != nil {
-		return nil, "", "", &AccountFailedToCreateTokenError{}
+	if user.TOTPEnabled {
+		return nil, "", "", s.startTwoFactorChallenge(user)
 	}
 
-	refreshToken, err := s.jwt.GenerateRefreshToken(user.Id.String(), user.Email, user.IsAdmin)
-	if err != nil {
-		return nil, "", "", &AccountFailedToCreateTokenError{}
-	}
+	s.clearFailedLogins(user)
 
-	err = s.repo.UpdateRefreshTokenUpdatedAt(user.Id, time.Now())
+	accessToken, refreshToken, err := s.startSession(user, device)
 	if err != nil {
-		return nil, "", "", &AccountFailedToCreateTokenError{}
+		return nil, "", "", err
 	}
 
 	return user, accessToken, refreshToken, nil
@@ -309,8 +325,15 @@ func (s *accountService) UpdateAdminStatus(id string, isAdmin bool) error {
 }
 
 func (s *accountService) ValidateAccessToken(token string) error {
-	// If a fixed server token is configured and matches the provided token, is a valid session.
-	if s.conf != nil && s.conf.ServerFixedToken != "" && token == s.conf.ServerFixedToken {
+	// If the deprecated fixed server token is enabled and matches the provided token, is a valid session.
+	if s.conf != nil && s.conf.ServerFixedTokenEnabled && s.conf.ServerFixedToken != "" && token == s.conf.ServerFixedToken {
+		return nil
+	}
+
+	if strings.HasPrefix(token, service_clients.SERVER_TOKEN_PREFIX) {
+		if _, err := s.clients.Servers.VerifyServerToken(token); err != nil {
+			return &AccountSessionInvalid{}
+		}
 		return nil
 	}
 
@@ -322,8 +345,8 @@ func (s *accountService) ValidateAccessToken(token string) error {
 		return &AccountSessionInvalid{}
 	}
 
-	userIDStr, ok := claims["userID"].(string)
-	if !ok || userIDStr == "" {
+	userIDStr := session.Subject(claims)
+	if userIDStr == "" {
 		return &AccountSessionInvalid{}
 	}
 
@@ -337,36 +360,18 @@ func (s *accountService) ValidateAccessToken(token string) error {
 		return &AccountSessionInvalid{}
 	}
 
-	return nil
-}
-
-func (s *accountService) ValidateRefreshToken(token string, email string) error {
-	user, err := s.repo.GetAccountByEmail(email)
-	if err != nil {
-		return &AccountNotFoundError{}
-	}
-
-	claims, err := s.jwt.IsValidateRefreshToken(token, time.Now(), user.RefreshTokenUpdatedAt)
+	sessionIDStr, _ := claims["sid"].(string)
+	sessionID, err := uuid.Parse(sessionIDStr)
 	if err != nil {
-		if _, ok := err.(*session.JWTExpiredTokenError); ok {
-			return &AccountSessionExpired{}
-		}
 		return &AccountSessionInvalid{}
 	}
 
-	userIDStr, ok := claims["userID"].(string)
-	if !ok || userIDStr == "" {
+	activeSession, err := s.repo.GetSessionById(sessionID)
+	if err != nil || activeSession.UserId != userID || activeSession.RevokedAt != nil {
 		return &AccountSessionInvalid{}
 	}
-
-	userID, err := uuid.Parse(userIDStr)
-	if err != nil {
-		return &AccountSessionInvalid{}
-	}
-
-	_, err = s.repo.GetAccountById(userID)
-	if err != nil {
-		return &AccountSessionInvalid{}
+	if !activeSession.IsActive(time.Now()) {
+		return &AccountSessionExpired{}
 	}
 
 	return nil
@@ -408,12 +413,7 @@ func (s *accountService) RefreshVerificationCode(email string) (string, error) {
 		return "", &AccountAlreadyVerifiedError{}
 	}
 
-	functionGenerator := mathrand.Int
-	if s.conf.Server.Environment == config.Testing {
-		functionGenerator = code_generator.StaticGenerateCode
-	}
-
-	newCode := code_generator.GenerateCode(functionGenerator)
+	newCode := s.newVerificationCode()
 	expiry := time.Now().Add(10 * time.Minute)
 
 	if err := s.repo.RefreshVerificationCode(user, newCode, expiry); err != nil {
@@ -423,33 +423,13 @@ func (s *accountService) RefreshVerificationCode(email string) (string, error) {
 	return newCode, nil
 }
 
-func (s *accountService) RefreshToken(email string) (string, string, error) {
-	email = strings.ToLower(email)
-	user, err := s.repo.GetAccountByEmail(email)
-	if err != nil {
-		return "", "", &AccountNotFoundError{}
-	}
-
-	if !user.Verified {
-		return "", "", &AccountNotVerifiedError{}
-	}
-
-	accessToken, err := s.jwt.GenerateAccessToken(user.Id.String(), user.Email, user.IsAdmin)
-	if err != nil {
-		return "", "", &AccountFailedToCreateTokenError{}
-	}
-
-	refreshToken, err := s.jwt.GenerateRefreshToken(user.Id.String(), user.Email, user.IsAdmin)
-	if err != nil {
-		return "", "", &AccountFailedToCreateTokenError{}
-	}
-
-	err = s.repo.UpdateRefreshTokenUpdatedAt(user.Id, time.Now())
-	if err != nil {
-		return "", "", &AccountFailedToCreateTokenError{}
+// newVerificationCode returns the code emailed to verify an account, fixed in the testing environment.
+func (s *accountService) newVerificationCode() string {
+	functionGenerator := mathrand.Int
+	if s.conf.Server.Environment == config.Testing {
+		functionGenerator = code_generator.StaticGenerateCode
 	}
-
-	return accessToken, refreshToken, nil
+	return code_generator.GenerateCode(functionGenerator)
 }
 
 // generateOTP returns a cryptographically secure 6-digit numeric OTP and its bcrypt hash.
@@ -473,9 +453,7 @@ func generateResetToken() (plaintext string, tokenHash string, err error) {
 		return "", "", err
 	}
 	plaintext = hex.EncodeToString(b)
-	sum := sha256.Sum256([]byte(plaintext))
-	tokenHash = hex.EncodeToString(sum[:])
-	return plaintext, tokenHash, nil
+	return plaintext, hashToken(plaintext), nil
 }
 
 // ForgotPassword generates a password reset OTP for the given email.
@@ -566,21 +544,9 @@ func (s *accountService) VerifyPasswordResetCode(email string, code string) (str
 	return tokenPlain, nil
 }
 
-// ResetPassword validates the reset token, updates the password, and revokes all active sessions.
-func (s *accountService) ResetPassword(resetToken string, newPassword string) error {
-	sum := sha256.Sum256([]byte(resetToken))
-	tokenHash := hex.EncodeToString(sum[:])
-
-	reset, err := s.repo.GetPasswordResetByTokenHash(tokenHash)
-	if err != nil {
-		return &PasswordResetNotFoundError{}
-	}
-
-	if reset.ResetTokenExpiresAt == nil || time.Now().After(*reset.ResetTokenExpiresAt) {
-		return &PasswordResetTokenExpiredError{}
-	}
-
-	if err := validator.IsValidPassword(newPassword); err != nil {
+// validateNewPassword checks the password rules for a password set after signup.
+func validateNewPassword(password string) error {
+	if err := validator.IsValidPassword(password); err != nil {
 		if _, ok := err.(*validator.EmptyPasswordError); ok {
 			return &AccountInvalidFormat{Msg: "Empty password"}
 		}
@@ -595,6 +561,23 @@ func (s *accountService) ResetPassword(resetToken string, newPassword string) er
 		}
 		return &AccountInvalidFormat{Msg: "Invalid password"}
 	}
+	return nil
+}
+
+// ResetPassword validates the reset token, updates the password, and revokes all active sessions.
+func (s *accountService) ResetPassword(resetToken string, newPassword string) error {
+	reset, err := s.repo.GetPasswordResetByTokenHash(hashToken(resetToken))
+	if err != nil {
+		return &PasswordResetNotFoundError{}
+	}
+
+	if reset.ResetTokenExpiresAt == nil || time.Now().After(*reset.ResetTokenExpiresAt) {
+		return &PasswordResetTokenExpiredError{}
+	}
+
+	if err := validateNewPassword(newPassword); err != nil {
+		return err
+	}
 
 	hashedPassword, err := hashing.HashPassword(newPassword)
 	if err != nil {
@@ -611,8 +594,8 @@ func (s *accountService) ResetPassword(resetToken string, newPassword string) er
 		logger.Logger.Warnf("ResetPassword: failed to invalidate password resets for user=%s: %v", reset.UserId, err)
 	}
 
-	// Rotate refresh_token_updated_at to force relogin on all devices.
-	if err := s.repo.UpdateRefreshTokenUpdatedAt(reset.UserId, time.Now()); err != nil {
+	// Revoke every device session to force relogin on all devices.
+	if err := s.repo.RevokeAllSessions(reset.UserId, models.SESSION_REVOKED_PASSWORD_RESET); err != nil {
 		logger.Logger.Errorf("ResetPassword: failed to revoke sessions for user=%s: %v", reset.UserId, err)
 	}
 
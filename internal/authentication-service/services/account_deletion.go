@@ -0,0 +1,262 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"time"
+
+	"github.com/FeedTheRealm-org/core-service/internal/authentication-service/models"
+	"github.com/FeedTheRealm-org/core-service/internal/authentication-service/utils/hashing"
+	"github.com/FeedTheRealm-org/core-service/internal/service_clients"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/logger"
+	"github.com/google/uuid"
+)
+
+// purgeBatchSize bounds how many accounts a single purge run erases.
+const purgeBatchSize = 50
+
+type IncorrectPasswordError struct{}
+
+func (e *IncorrectPasswordError) Error() string {
+	return "The password is incorrect"
+}
+
+type AccountDeletionScheduledError struct {
+	DeletionScheduledAt time.Time
+}
+
+func (e *AccountDeletionScheduledError) Error() string {
+	return "Account is scheduled for deletion, use the link in the confirmation email to restore it"
+}
+
+type InvalidDeletionCancelTokenError struct{}
+
+func (e *InvalidDeletionCancelTokenError) Error() string {
+	return "Restore link is invalid or the account was already deleted"
+}
+
+// AccountExport is the account.json file of a data export, secrets and token hashes are left out.
+type AccountExport struct {
+	Id                  uuid.UUID        `json:"id"`
+	Email               string           `json:"email"`
+	Verified            bool             `json:"verified"`
+	IsAdmin             bool             `json:"is_admin"`
+	TwoFactorEnabled    bool             `json:"two_factor_enabled"`
+	CreatedAt           time.Time        `json:"created_at"`
+	UpdatedAt           time.Time        `json:"updated_at"`
+	DeletionScheduledAt *time.Time       `json:"deletion_scheduled_at"`
+	Sessions            []SessionExport  `json:"sessions"`
+	Identities          []IdentityExport `json:"identities"`
+}
+
+type SessionExport struct {
+	Id         uuid.UUID `json:"id"`
+	UserAgent  string    `json:"user_agent"`
+	IPAddress  string    `json:"ip_address"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+type IdentityExport struct {
+	Provider  string    `json:"provider"`
+	Subject   string    `json:"subject"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RequestAccountDeletion schedules the account for deletion after the grace period. The user is signed
+// out everywhere, their zones are stopped and the subscription won't renew, the rest is kept until the purge.
+// Returns the token that restores the account, to be emailed to the user.
+func (s *accountService) RequestAccountDeletion(userId uuid.UUID, password string) (*models.User, string, error) {
+	user, err := s.repo.GetAccountById(userId)
+	if err != nil {
+		return nil, "", &AccountNotFoundError{}
+	}
+	if user.IsDeletionScheduled() {
+		return nil, "", &AccountDeletionScheduledError{DeletionScheduledAt: *user.DeletionScheduledAt}
+	}
+	// Accounts created through a provider have no password, the session is all they can prove.
+	if user.Password != "" && !hashing.VerifyPassword(user.Password, password) {
+		return nil, "", &IncorrectPasswordError{}
+	}
+
+	cancelToken, cancelTokenHash, err := generateResetToken()
+	if err != nil {
+		return nil, "", &AccountFailedToCreateTokenError{}
+	}
+
+	scheduledAt := time.Now().Add(s.conf.AccountDeletion.GracePeriod)
+	if err := s.repo.ScheduleAccountDeletion(userId, scheduledAt, cancelTokenHash); err != nil {
+		return nil, "", err
+	}
+	user.DeletionScheduledAt = &scheduledAt
+
+	if err := s.repo.RevokeAllSessions(userId, models.SESSION_REVOKED_ACCOUNT_DELETION); err != nil {
+		logger.Logger.Errorf("RequestAccountDeletion: failed to revoke sessions of user=%s: %v", userId, err)
+	}
+	if err := s.clients.WorldJobs.StopAllJobsForUser(userId); err != nil {
+		logger.Logger.Errorf("RequestAccountDeletion: failed to stop zones of user=%s: %v", userId, err)
+	}
+	if err := s.clients.Subscriptions.CancelSubscription(userId); err != nil {
+		logger.Logger.Errorf("RequestAccountDeletion: failed to cancel subscription of user=%s: %v", userId, err)
+	}
+
+	logger.Logger.Infof("Account user=%s scheduled for deletion at %s", userId, scheduledAt.Format(time.RFC3339))
+	return user, cancelToken, nil
+}
+
+func (s *accountService) CancelAccountDeletion(token string) error {
+	if token == "" {
+		return &InvalidDeletionCancelTokenError{}
+	}
+	if err := s.repo.CancelAccountDeletion(hashToken(token), time.Now()); err != nil {
+		return &InvalidDeletionCancelTokenError{}
+	}
+	return nil
+}
+
+// ExportUserData bundles everything stored about the user in a zip, account.json from this service
+// and one file per service. The export fails as a whole if any service cannot be reached.
+func (s *accountService) ExportUserData(userId uuid.UUID) ([]byte, error) {
+	user, err := s.repo.GetAccountById(userId)
+	if err != nil {
+		return nil, &AccountNotFoundError{}
+	}
+
+	sessions, err := s.repo.ListActiveSessions(userId, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	identities, err := s.repo.ListIdentities(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	account := AccountExport{
+		Id:                  user.Id,
+		Email:               user.Email,
+		Verified:            user.Verified,
+		IsAdmin:             user.IsAdmin,
+		TwoFactorEnabled:    user.TOTPEnabled,
+		CreatedAt:           user.CreatedAt,
+		UpdatedAt:           user.UpdatedAt,
+		DeletionScheduledAt: user.DeletionScheduledAt,
+		Sessions:            make([]SessionExport, len(sessions)),
+		Identities:          make([]IdentityExport, len(identities)),
+	}
+	for i, session := range sessions {
+		account.Sessions[i] = SessionExport{
+			Id:         session.Id,
+			UserAgent:  session.UserAgent,
+			IPAddress:  session.IPAddress,
+			CreatedAt:  session.CreatedAt,
+			LastUsedAt: session.LastUsedAt,
+			ExpiresAt:  session.ExpiresAt,
+		}
+	}
+	for i, identity := range identities {
+		account.Identities[i] = IdentityExport{
+			Provider:  identity.Provider,
+			Subject:   identity.Subject,
+			Email:     identity.Email,
+			CreatedAt: identity.CreatedAt,
+		}
+	}
+
+	accountJSON, err := json.MarshalIndent(account, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	if err := addExportFile(archive, "account.json", accountJSON); err != nil {
+		return nil, err
+	}
+	for _, service := range service_clients.UserDataServices {
+		data, err := s.clients.UserData[service].ExportUserData(userId)
+		if err != nil {
+			logger.Logger.Errorf("ExportUserData: failed to export %s data of user=%s: %v", service, userId, err)
+			return nil, err
+		}
+		var indented bytes.Buffer
+		if err := json.Indent(&indented, data, "", "  "); err != nil {
+			return nil, err
+		}
+		if err := addExportFile(archive, service+".json", indented.Bytes()); err != nil {
+			return nil, err
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func addExportFile(archive *zip.Writer, name string, content []byte) error {
+	file, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(content)
+	return err
+}
+
+// PurgeDeletedAccounts erases the accounts whose grace period ended. Every service erases its data
+// before the account row is deleted, so an account that fails halfway is retried on the next run.
+func (s *accountService) PurgeDeletedAccounts() (int, error) {
+	users, err := s.repo.ListAccountsDueForDeletion(time.Now(), purgeBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	deleted := 0
+	for _, user := range users {
+		if err := s.eraseAccount(user.Id); err != nil {
+			logger.Logger.Errorf("PurgeDeletedAccounts: failed to erase user=%s: %v", user.Id, err)
+			continue
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
+func (s *accountService) eraseAccount(userId uuid.UUID) error {
+	for _, service := range service_clients.UserDataServices {
+		if err := s.clients.UserData[service].EraseUserData(userId); err != nil {
+			return err
+		}
+	}
+	if err := s.repo.DeleteAccount(userId); err != nil {
+		return err
+	}
+	logger.Logger.Infof("Account user=%s erased", userId)
+	return nil
+}
+
+// StartDeletionPurge runs PurgeDeletedAccounts every configured interval for the lifetime of the process.
+func (s *accountService) StartDeletionPurge() {
+	interval := s.conf.AccountDeletion.PurgeInterval
+	if interval <= 0 {
+		logger.Logger.Info("Account deletion purge disabled")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			deleted, err := s.PurgeDeletedAccounts()
+			if err != nil {
+				logger.Logger.Errorf("Account deletion purge failed: %v", err)
+				continue
+			}
+			if deleted > 0 {
+				logger.Logger.Infof("Account deletion purge erased %d accounts", deleted)
+			}
+		}
+	}()
+}
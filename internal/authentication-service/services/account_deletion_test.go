@@ -0,0 +1,207 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/FeedTheRealm-org/core-service/internal/service_clients"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeWorldJobsClient struct {
+	stopped []uuid.UUID
+}
+
+func (f *fakeWorldJobsClient) StopAllJobsForUser(userId uuid.UUID) error {
+	f.stopped = append(f.stopped, userId)
+	return nil
+}
+
+type fakeSubscriptionsClient struct {
+	canceled []uuid.UUID
+	emails   map[uuid.UUID]string
+}
+
+func (f *fakeSubscriptionsClient) CheckAvailability(userId uuid.UUID) (*service_clients.SlotsAvailability, error) {
+	return &service_clients.SlotsAvailability{}, nil
+}
+
+func (f *fakeSubscriptionsClient) UpdateUsedSlots(userId uuid.UUID, slots int, areUsed bool) error {
+	return nil
+}
+
+func (f *fakeSubscriptionsClient) CancelSubscription(userId uuid.UUID) error {
+	f.canceled = append(f.canceled, userId)
+	return nil
+}
+
+func (f *fakeSubscriptionsClient) UpdateCustomerEmail(userId uuid.UUID, email string) error {
+	if f.emails == nil {
+		f.emails = map[uuid.UUID]string{}
+	}
+	f.emails[userId] = email
+	return nil
+}
+
+// fakeUserDataClient records the erasures of every service in the shared order slice.
+type fakeUserDataClient struct {
+	service   string
+	data      string
+	exportErr error
+	eraseErr  error
+	order     *[]string
+}
+
+func (f *fakeUserDataClient) ExportUserData(userId uuid.UUID) (json.RawMessage, error) {
+	if f.exportErr != nil {
+		return nil, f.exportErr
+	}
+	return json.RawMessage(f.data), nil
+}
+
+func (f *fakeUserDataClient) EraseUserData(userId uuid.UUID) error {
+	if f.eraseErr != nil {
+		return f.eraseErr
+	}
+	*f.order = append(*f.order, f.service)
+	return nil
+}
+
+func provideUserDataClients(svc *accountService) (map[string]*fakeUserDataClient, *[]string) {
+	order := &[]string{}
+	fakes := map[string]*fakeUserDataClient{}
+	for _, service := range service_clients.UserDataServices {
+		fake := &fakeUserDataClient{service: service, data: `{"service":"` + service + `"}`, order: order}
+		fakes[service] = fake
+		svc.clients.ProvideUserData(service, fake)
+	}
+	return fakes, order
+}
+
+func TestAccountService_RequestAccountDeletion(t *testing.T) {
+	repo, user, svc := newSessionTestService(t)
+	worldJobs := &fakeWorldJobsClient{}
+	subscriptions := &fakeSubscriptionsClient{}
+	svc.clients.ProvideWorldJobs(worldJobs)
+	svc.clients.ProvideSubscriptions(subscriptions)
+	loginTestUser(t, svc)
+
+	_, _, err := svc.RequestAccountDeletion(user.Id, "wrong")
+	assert.IsType(t, &IncorrectPasswordError{}, err)
+	assert.False(t, user.IsDeletionScheduled())
+
+	deleted, cancelToken, err := svc.RequestAccountDeletion(user.Id, "Password1")
+	require.NoError(t, err)
+	assert.NotEmpty(t, cancelToken)
+	assert.WithinDuration(t, time.Now().Add(svc.conf.AccountDeletion.GracePeriod), *deleted.DeletionScheduledAt, time.Minute)
+	assert.NotNil(t, repo.onlySession(t).RevokedAt)
+	assert.Equal(t, []uuid.UUID{user.Id}, worldJobs.stopped)
+	assert.Equal(t, []uuid.UUID{user.Id}, subscriptions.canceled)
+
+	_, _, _, err = svc.LoginAccount(user.Email, "Password1", false, SessionDevice{})
+	assert.IsType(t, &AccountDeletionScheduledError{}, err)
+
+	_, _, err = svc.RequestAccountDeletion(user.Id, "Password1")
+	assert.IsType(t, &AccountDeletionScheduledError{}, err)
+}
+
+func TestAccountService_RequestAccountDeletion_ServicesUnavailable(t *testing.T) {
+	_, user, svc := newSessionTestService(t)
+
+	// Stopping zones and the subscription is best effort, the purge erases them anyway.
+	_, _, err := svc.RequestAccountDeletion(user.Id, "Password1")
+	require.NoError(t, err)
+	assert.True(t, user.IsDeletionScheduled())
+}
+
+func TestAccountService_CancelAccountDeletion(t *testing.T) {
+	_, user, svc := newSessionTestService(t)
+	_, cancelToken, err := svc.RequestAccountDeletion(user.Id, "Password1")
+	require.NoError(t, err)
+
+	assert.IsType(t, &InvalidDeletionCancelTokenError{}, svc.CancelAccountDeletion("other"))
+	require.NoError(t, svc.CancelAccountDeletion(cancelToken))
+	assert.False(t, user.IsDeletionScheduled())
+	assert.IsType(t, &InvalidDeletionCancelTokenError{}, svc.CancelAccountDeletion(cancelToken))
+
+	loginTestUser(t, svc)
+}
+
+func TestAccountService_ExportUserData(t *testing.T) {
+	_, user, svc := newSessionTestService(t)
+	provideUserDataClients(svc)
+	loginTestUser(t, svc)
+
+	archive, err := svc.ExportUserData(user.Id)
+	require.NoError(t, err)
+
+	reader, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	require.NoError(t, err)
+	files := map[string]string{}
+	for _, file := range reader.File {
+		opened, err := file.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(opened)
+		require.NoError(t, err)
+		files[file.Name] = string(content)
+	}
+
+	require.Contains(t, files, "account.json")
+	var account AccountExport
+	require.NoError(t, json.Unmarshal([]byte(files["account.json"]), &account))
+	assert.Equal(t, user.Email, account.Email)
+	assert.Len(t, account.Sessions, 1)
+	assert.NotContains(t, files["account.json"], user.Password)
+	for _, service := range service_clients.UserDataServices {
+		assert.JSONEq(t, `{"service":"`+service+`"}`, files[service+".json"])
+	}
+}
+
+func TestAccountService_ExportUserData_ServiceFails(t *testing.T) {
+	_, user, svc := newSessionTestService(t)
+	fakes, _ := provideUserDataClients(svc)
+	fakes[service_clients.AssetsUserData].exportErr = errors.New("assets down")
+
+	_, err := svc.ExportUserData(user.Id)
+	assert.Error(t, err)
+}
+
+func TestAccountService_PurgeDeletedAccounts(t *testing.T) {
+	repo, user, svc := newSessionTestService(t)
+	_, order := provideUserDataClients(svc)
+	_, _, err := svc.RequestAccountDeletion(user.Id, "Password1")
+	require.NoError(t, err)
+
+	// Nothing is erased during the grace period.
+	deleted, err := svc.PurgeDeletedAccounts()
+	require.NoError(t, err)
+	assert.Zero(t, deleted)
+
+	past := time.Now().Add(-time.Minute)
+	user.DeletionScheduledAt = &past
+	deleted, err = svc.PurgeDeletedAccounts()
+	require.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+	assert.Equal(t, service_clients.UserDataServices, *order)
+	assert.NotContains(t, repo.usersByID, user.Id)
+}
+
+func TestAccountService_PurgeDeletedAccounts_KeepsAccountWhenServiceFails(t *testing.T) {
+	repo, user, svc := newSessionTestService(t)
+	fakes, _ := provideUserDataClients(svc)
+	fakes[service_clients.PaymentsUserData].eraseErr = errors.New("stripe down")
+	past := time.Now().Add(-time.Minute)
+	require.NoError(t, repo.ScheduleAccountDeletion(user.Id, past, "hash"))
+
+	deleted, err := svc.PurgeDeletedAccounts()
+	require.NoError(t, err)
+	assert.Zero(t, deleted)
+	assert.Contains(t, repo.usersByID, user.Id)
+}
@@ -0,0 +1,164 @@
+package services
+
+import (
+	"strings"
+	"time"
+
+	"github.com/FeedTheRealm-org/core-service/internal/authentication-service/models"
+	"github.com/FeedTheRealm-org/core-service/internal/authentication-service/repositories"
+	validator "github.com/FeedTheRealm-org/core-service/internal/authentication-service/utils/credential-validation"
+	"github.com/FeedTheRealm-org/core-service/internal/authentication-service/utils/hashing"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/logger"
+	"github.com/google/uuid"
+)
+
+const emailChangeCodeExpiry = 10 * time.Minute
+
+type EmailUnchangedError struct{}
+
+func (e *EmailUnchangedError) Error() string {
+	return "The new email is the same as the current one"
+}
+
+type EmailAlreadyInUseError struct{}
+
+func (e *EmailAlreadyInUseError) Error() string {
+	return "The email is already in use by another account"
+}
+
+type EmailChangeNotFoundError struct{}
+
+func (e *EmailChangeNotFoundError) Error() string {
+	return "There is no pending email change, request a new code"
+}
+
+type EmailChangeCodeExpiredError struct{}
+
+func (e *EmailChangeCodeExpiredError) Error() string {
+	return "The email change code has expired, request a new one"
+}
+
+type InvalidEmailChangeCodeError struct{}
+
+func (e *InvalidEmailChangeCodeError) Error() string {
+	return "The email change code is incorrect"
+}
+
+// ChangePassword replaces the password of a logged in user. Every other session is signed out,
+// the one making the change keeps working.
+func (s *accountService) ChangePassword(userId uuid.UUID, sessionId uuid.UUID, currentPassword string, newPassword string) error {
+	user, err := s.repo.GetAccountById(userId)
+	if err != nil {
+		return &AccountNotFoundError{}
+	}
+	// Accounts created through a provider have no password, they set one with the forgot password flow.
+	if !hashing.VerifyPassword(user.Password, currentPassword) {
+		return &IncorrectPasswordError{}
+	}
+
+	if err := validateNewPassword(newPassword); err != nil {
+		return err
+	}
+
+	hashedPassword, err := hashing.HashPassword(newPassword)
+	if err != nil {
+		return &AccountFailedToCreateError{}
+	}
+
+	if err := s.repo.UpdatePassword(userId, hashedPassword); err != nil {
+		logger.Logger.Errorf("ChangePassword: failed to update password for user=%s: %v", userId, err)
+		return &AccountFailedToCreateError{}
+	}
+
+	if err := s.repo.InvalidateAllPasswordResets(userId); err != nil {
+		logger.Logger.Warnf("ChangePassword: failed to invalidate password resets for user=%s: %v", userId, err)
+	}
+
+	if err := s.repo.RevokeOtherSessions(userId, sessionId, models.SESSION_REVOKED_PASSWORD_CHANGE); err != nil {
+		logger.Logger.Errorf("ChangePassword: failed to revoke other sessions for user=%s: %v", userId, err)
+	}
+
+	logger.Logger.Infof("ChangePassword: password changed for user=%s", userId)
+	return nil
+}
+
+// RequestEmailChange stores the new email of the user until it is confirmed with the code of the returned
+// change, which has to be emailed to the new address.
+func (s *accountService) RequestEmailChange(userId uuid.UUID, newEmail string, password string) (*models.User, *models.EmailChange, error) {
+	newEmail = strings.ToLower(strings.TrimSpace(newEmail))
+	if err := validator.IsValidEmail(newEmail); err != nil {
+		if _, ok := err.(*validator.EmptyEmailError); ok {
+			return nil, nil, &AccountInvalidFormat{Msg: "Empty email"}
+		}
+		return nil, nil, &AccountInvalidFormat{Msg: "Invalid email"}
+	}
+
+	user, err := s.repo.GetAccountById(userId)
+	if err != nil {
+		return nil, nil, &AccountNotFoundError{}
+	}
+	if user.Email == newEmail {
+		return nil, nil, &EmailUnchangedError{}
+	}
+	// Accounts created through a provider have no password, the session is all they can prove.
+	if user.Password != "" && !hashing.VerifyPassword(user.Password, password) {
+		return nil, nil, &IncorrectPasswordError{}
+	}
+	if existing, err := s.repo.GetAccountByEmail(newEmail); err == nil && existing != nil {
+		return nil, nil, &EmailAlreadyInUseError{}
+	}
+
+	change := &models.EmailChange{
+		UserId:           userId,
+		NewEmail:         newEmail,
+		VerificationCode: s.newVerificationCode(),
+		ExpiresAt:        time.Now().Add(emailChangeCodeExpiry),
+	}
+	if err := s.repo.CreateEmailChange(change); err != nil {
+		logger.Logger.Errorf("RequestEmailChange: failed to store email change for user=%s: %v", userId, err)
+		return nil, nil, &AccountFailedToCreateError{}
+	}
+
+	logger.Logger.Infof("RequestEmailChange: email change requested for user=%s", userId)
+	return user, change, nil
+}
+
+// ConfirmEmailChange applies the pending email change when the code matches. Tokens carry the email,
+// so every session is signed out and a new one is opened for the device that confirmed the change.
+func (s *accountService) ConfirmEmailChange(userId uuid.UUID, code string, device SessionDevice) (*models.User, string, string, error) {
+	newEmail, err := s.repo.ConfirmEmailChange(userId, code, time.Now())
+	if err != nil {
+		switch err.(type) {
+		case *repositories.EmailChangeNotFoundError:
+			return nil, "", "", &EmailChangeNotFoundError{}
+		case *repositories.EmailChangeExpiredError:
+			return nil, "", "", &EmailChangeCodeExpiredError{}
+		case *repositories.InvalidEmailChangeCodeError:
+			return nil, "", "", &InvalidEmailChangeCodeError{}
+		case *repositories.EmailAlreadyInUseError:
+			return nil, "", "", &EmailAlreadyInUseError{}
+		}
+		return nil, "", "", err
+	}
+
+	user, err := s.repo.GetAccountById(userId)
+	if err != nil {
+		return nil, "", "", &AccountNotFoundError{}
+	}
+
+	if err := s.clients.Subscriptions.UpdateCustomerEmail(userId, newEmail); err != nil {
+		logger.Logger.Errorf("ConfirmEmailChange: failed to update billing email of user=%s: %v", userId, err)
+	}
+
+	if err := s.repo.RevokeAllSessions(userId, models.SESSION_REVOKED_EMAIL_CHANGE); err != nil {
+		logger.Logger.Errorf("ConfirmEmailChange: failed to revoke sessions of user=%s: %v", userId, err)
+	}
+
+	accessToken, refreshToken, err := s.startSession(user, device)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	logger.Logger.Infof("ConfirmEmailChange: email changed for user=%s", userId)
+	return user, accessToken, refreshToken, nil
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/FeedTheRealm-org/core-service/internal/authentication-service/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sessionIdFromRefreshToken(t *testing.T, svc *accountService, refreshToken string) uuid.UUID {
+	t.Helper()
+	claims, err := svc.jwt.IsValidateRefreshToken(refreshToken, time.Now())
+	require.NoError(t, err)
+	sessionId, err := uuid.Parse(claims["sid"].(string))
+	require.NoError(t, err)
+	return sessionId
+}
+
+func TestAccountService_ChangePassword(t *testing.T) {
+	repo, user, svc := newSessionTestService(t)
+	current := sessionIdFromRefreshToken(t, svc, loginTestUser(t, svc))
+	other := sessionIdFromRefreshToken(t, svc, loginTestUser(t, svc))
+
+	assert.IsType(t, &IncorrectPasswordError{}, svc.ChangePassword(user.Id, current, "wrong", "NewPassword2"))
+	assert.IsType(t, &AccountInvalidFormat{}, svc.ChangePassword(user.Id, current, "Password1", "short"))
+
+	require.NoError(t, svc.ChangePassword(user.Id, current, "Password1", "NewPassword2"))
+	assert.Nil(t, repo.sessions[current].RevokedAt)
+	require.NotNil(t, repo.sessions[other].RevokedAt)
+	assert.Equal(t, models.SESSION_REVOKED_PASSWORD_CHANGE, repo.sessions[other].RevokedReason)
+
+	_, _, _, err := svc.LoginAccount(user.Email, "Password1", false, SessionDevice{})
+	assert.Error(t, err)
+	_, _, _, err = svc.LoginAccount(user.Email, "NewPassword2", false, SessionDevice{})
+	assert.NoError(t, err)
+}
+
+func TestAccountService_RequestEmailChange_Validations(t *testing.T) {
+	repo, user, svc := newSessionTestService(t)
+	taken := &models.User{Id: uuid.New(), Email: "taken@example.com"}
+	repo.usersByEmail[taken.Email] = taken
+	repo.usersByID[taken.Id] = taken
+
+	_, _, err := svc.RequestEmailChange(user.Id, "not-an-email", "Password1")
+	assert.IsType(t, &AccountInvalidFormat{}, err)
+	_, _, err = svc.RequestEmailChange(user.Id, "User@Example.com", "Password1")
+	assert.IsType(t, &EmailUnchangedError{}, err)
+	_, _, err = svc.RequestEmailChange(user.Id, "new@example.com", "wrong")
+	assert.IsType(t, &IncorrectPasswordError{}, err)
+	_, _, err = svc.RequestEmailChange(user.Id, "taken@example.com", "Password1")
+	assert.IsType(t, &EmailAlreadyInUseError{}, err)
+	assert.Empty(t, repo.emailChanges)
+}
+
+func TestAccountService_ConfirmEmailChange(t *testing.T) {
+	repo, user, svc := newSessionTestService(t)
+	subscriptions := &fakeSubscriptionsClient{}
+	svc.clients.ProvideSubscriptions(subscriptions)
+	oldSession := sessionIdFromRefreshToken(t, svc, loginTestUser(t, svc))
+
+	_, change, err := svc.RequestEmailChange(user.Id, " New@Example.com ", "Password1")
+	require.NoError(t, err)
+	assert.Equal(t, "new@example.com", change.NewEmail)
+	code := change.VerificationCode
+
+	_, _, _, err = svc.ConfirmEmailChange(user.Id, "000000", SessionDevice{})
+	assert.IsType(t, &InvalidEmailChangeCodeError{}, err)
+	assert.Equal(t, "user@example.com", user.Email)
+
+	changed, accessToken, refreshToken, err := svc.ConfirmEmailChange(user.Id, code, SessionDevice{UserAgent: "phone"})
+	require.NoError(t, err)
+	assert.Equal(t, "new@example.com", changed.Email)
+	assert.Equal(t, "new@example.com", subscriptions.emails[user.Id])
+
+	claims, err := svc.jwt.IsValidateAccessToken(accessToken, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, "new@example.com", claims["email"])
+	require.NotNil(t, repo.sessions[oldSession].RevokedAt)
+	assert.Equal(t, models.SESSION_REVOKED_EMAIL_CHANGE, repo.sessions[oldSession].RevokedReason)
+
+	_, _, err = svc.RefreshToken(refreshToken, "new@example.com", SessionDevice{})
+	assert.NoError(t, err)
+
+	_, _, _, err = svc.ConfirmEmailChange(user.Id, code, SessionDevice{})
+	assert.IsType(t, &EmailChangeNotFoundError{}, err)
+}
+
+func TestAccountService_ConfirmEmailChange_Expired(t *testing.T) {
+	repo, user, svc := newSessionTestService(t)
+	_, change, err := svc.RequestEmailChange(user.Id, "new@example.com", "Password1")
+	require.NoError(t, err)
+	repo.emailChanges[user.Id].ExpiresAt = time.Now().Add(-time.Minute)
+
+	_, _, _, err = svc.ConfirmEmailChange(user.Id, change.VerificationCode, SessionDevice{})
+	assert.IsType(t, &EmailChangeCodeExpiredError{}, err)
+	assert.Equal(t, "user@example.com", user.Email)
+}
@@ -4,6 +4,7 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"errors"
+	"slices"
 	"testing"
 	"time"
 
@@ -11,6 +12,7 @@ import (
 	"github.com/FeedTheRealm-org/core-service/internal/authentication-service/models"
 	repoerrs "github.com/FeedTheRealm-org/core-service/internal/authentication-service/repositories"
 	"github.com/FeedTheRealm-org/core-service/internal/authentication-service/utils/hashing"
+	"github.com/FeedTheRealm-org/core-service/internal/service_clients"
 	"github.com/FeedTheRealm-org/core-service/internal/utils/session"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
@@ -26,7 +28,16 @@ type fakeAccountRepo struct {
 	createErr        error
 	verifyErr        error
 	refreshErr       error
-	updateRefreshErr error
+	createSessionErr error
+	rotateErr        error
+	sessions         map[uuid.UUID]*models.Session
+	challenges       map[uuid.UUID]*models.TwoFactorChallenge
+	recoveryCodes    map[uuid.UUID]map[string]bool
+	identities       []*models.Identity
+	loginStates      map[string]*models.OIDCLoginState
+	emailChanges     map[uuid.UUID]*models.EmailChange
+	roles            map[string]*models.Role
+	userRoles        map[uuid.UUID][]string
 	updateAdminErr   error
 	listUsers        []models.User
 	listTotal        int64
@@ -44,8 +55,15 @@ type fakeAccountRepo struct {
 
 func newFakeAccountRepo() *fakeAccountRepo {
 	return &fakeAccountRepo{
-		usersByEmail: make(map[string]*models.User),
-		usersByID:    make(map[uuid.UUID]*models.User),
+		usersByEmail:  make(map[string]*models.User),
+		usersByID:     make(map[uuid.UUID]*models.User),
+		sessions:      make(map[uuid.UUID]*models.Session),
+		challenges:    make(map[uuid.UUID]*models.TwoFactorChallenge),
+		recoveryCodes: make(map[uuid.UUID]map[string]bool),
+		loginStates:   make(map[string]*models.OIDCLoginState),
+		emailChanges:  make(map[uuid.UUID]*models.EmailChange),
+		roles:         make(map[string]*models.Role),
+		userRoles:     make(map[uuid.UUID][]string),
 	}
 }
 
@@ -98,17 +116,6 @@ func (f *fakeAccountRepo) RefreshVerificationCode(user *models.User, verificatio
 	return nil
 }
 
-func (f *fakeAccountRepo) UpdateRefreshTokenUpdatedAt(id uuid.UUID, updatedAt time.Time) error {
-	if f.updateRefreshErr != nil {
-		return f.updateRefreshErr
-	}
-	user, ok := f.usersByID[id]
-	if ok {
-		user.RefreshTokenUpdatedAt = updatedAt
-	}
-	return nil
-}
-
 func (f *fakeAccountRepo) ListAccounts(query string, verified *bool, offset int, limit int) ([]models.User, int64, error) {
 	return f.listUsers, f.listTotal, nil
 }
@@ -201,6 +208,401 @@ func (f *fakeAccountRepo) UpdatePassword(userID uuid.UUID, hashedPassword string
 	return nil
 }
 
+func (f *fakeAccountRepo) CreateSession(session *models.Session) error {
+	if f.createSessionErr != nil {
+		return f.createSessionErr
+	}
+	stored := *session
+	f.sessions[session.Id] = &stored
+	return nil
+}
+
+func (f *fakeAccountRepo) GetSessionById(id uuid.UUID) (*models.Session, error) {
+	session, ok := f.sessions[id]
+	if !ok {
+		return nil, &repoerrs.SessionNotFoundError{}
+	}
+	copied := *session
+	return &copied, nil
+}
+
+func (f *fakeAccountRepo) RotateSessionRefreshToken(session *models.Session, previousTokenHash string) error {
+	if f.rotateErr != nil {
+		return f.rotateErr
+	}
+	stored, ok := f.sessions[session.Id]
+	if !ok || stored.RefreshTokenHash != previousTokenHash || stored.RevokedAt != nil {
+		return &repoerrs.SessionNotFoundError{}
+	}
+	*stored = *session
+	return nil
+}
+
+func (f *fakeAccountRepo) ListActiveSessions(userID uuid.UUID, now time.Time) ([]models.Session, error) {
+	var active []models.Session
+	for _, session := range f.sessions {
+		if session.UserId == userID && session.IsActive(now) {
+			active = append(active, *session)
+		}
+	}
+	return active, nil
+}
+
+func (f *fakeAccountRepo) RevokeSession(userID uuid.UUID, sessionID uuid.UUID, reason string) error {
+	session, ok := f.sessions[sessionID]
+	if !ok || session.UserId != userID || session.RevokedAt != nil {
+		return &repoerrs.SessionNotFoundError{}
+	}
+	now := time.Now()
+	session.RevokedAt = &now
+	session.RevokedReason = reason
+	return nil
+}
+
+func (f *fakeAccountRepo) RevokeAllSessions(userID uuid.UUID, reason string) error {
+	for _, session := range f.sessions {
+		if session.UserId == userID && session.RevokedAt == nil {
+			now := time.Now()
+			session.RevokedAt = &now
+			session.RevokedReason = reason
+		}
+	}
+	return nil
+}
+
+func (f *fakeAccountRepo) RevokeOtherSessions(userID uuid.UUID, keepSessionID uuid.UUID, reason string) error {
+	for _, session := range f.sessions {
+		if session.UserId == userID && session.Id != keepSessionID && session.RevokedAt == nil {
+			now := time.Now()
+			session.RevokedAt = &now
+			session.RevokedReason = reason
+		}
+	}
+	return nil
+}
+
+func (f *fakeAccountRepo) RecordFailedLogin(userID uuid.UUID) (int, error) {
+	user, ok := f.usersByID[userID]
+	if !ok {
+		return 0, &repoerrs.AccountNotFoundError{}
+	}
+	user.FailedLoginAttempts++
+	return user.FailedLoginAttempts, nil
+}
+
+func (f *fakeAccountRepo) LockAccount(userID uuid.UUID, until time.Time, unlockTokenHash string) error {
+	user, ok := f.usersByID[userID]
+	if !ok {
+		return &repoerrs.AccountNotFoundError{}
+	}
+	user.FailedLoginAttempts = 0
+	user.LockoutCount++
+	user.LockedUntil = &until
+	user.UnlockTokenHash = &unlockTokenHash
+	return nil
+}
+
+func (f *fakeAccountRepo) ResetFailedLogins(userID uuid.UUID) error {
+	user, ok := f.usersByID[userID]
+	if !ok {
+		return &repoerrs.AccountNotFoundError{}
+	}
+	user.FailedLoginAttempts = 0
+	user.LockoutCount = 0
+	user.LockedUntil = nil
+	user.UnlockTokenHash = nil
+	return nil
+}
+
+func (f *fakeAccountRepo) UnlockAccount(unlockTokenHash string, now time.Time) error {
+	for _, user := range f.usersByID {
+		if user.UnlockTokenHash != nil && *user.UnlockTokenHash == unlockTokenHash && user.IsLocked(now) {
+			user.FailedLoginAttempts = 0
+			user.LockedUntil = nil
+			user.UnlockTokenHash = nil
+			return nil
+		}
+	}
+	return &repoerrs.AccountNotFoundError{}
+}
+
+func (f *fakeAccountRepo) SetTOTPSecret(userID uuid.UUID, secret string) error {
+	user, ok := f.usersByID[userID]
+	if !ok {
+		return &repoerrs.AccountNotFoundError{}
+	}
+	if !user.TOTPEnabled {
+		user.TOTPSecret = &secret
+	}
+	return nil
+}
+
+func (f *fakeAccountRepo) EnableTOTP(userID uuid.UUID, usedStep int64, recoveryCodeHashes []string) error {
+	user, ok := f.usersByID[userID]
+	if !ok {
+		return &repoerrs.AccountNotFoundError{}
+	}
+	user.TOTPEnabled = true
+	user.TOTPLastUsedStep = usedStep
+	f.recoveryCodes[userID] = make(map[string]bool)
+	for _, hash := range recoveryCodeHashes {
+		f.recoveryCodes[userID][hash] = false
+	}
+	return nil
+}
+
+func (f *fakeAccountRepo) UseTOTPStep(userID uuid.UUID, step int64) error {
+	user, ok := f.usersByID[userID]
+	if !ok || user.TOTPLastUsedStep >= step {
+		return &repoerrs.TOTPCodeReusedError{}
+	}
+	user.TOTPLastUsedStep = step
+	return nil
+}
+
+func (f *fakeAccountRepo) UseRecoveryCode(userID uuid.UUID, codeHash string) error {
+	used, ok := f.recoveryCodes[userID][codeHash]
+	if !ok || used {
+		return &repoerrs.RecoveryCodeNotFoundError{}
+	}
+	f.recoveryCodes[userID][codeHash] = true
+	return nil
+}
+
+func (f *fakeAccountRepo) CreateTwoFactorChallenge(challenge *models.TwoFactorChallenge) error {
+	challenge.Id = uuid.New()
+	f.challenges[challenge.Id] = challenge
+	return nil
+}
+
+func (f *fakeAccountRepo) GetTwoFactorChallengeByTokenHash(tokenHash string) (*models.TwoFactorChallenge, error) {
+	for _, challenge := range f.challenges {
+		if challenge.TokenHash == tokenHash {
+			return challenge, nil
+		}
+	}
+	return nil, &repoerrs.TwoFactorChallengeNotFoundError{}
+}
+
+func (f *fakeAccountRepo) IncrementTwoFactorChallengeAttempts(challengeID uuid.UUID) error {
+	if challenge, ok := f.challenges[challengeID]; ok {
+		challenge.Attempts++
+	}
+	return nil
+}
+
+func (f *fakeAccountRepo) ConsumeTwoFactorChallenge(challengeID uuid.UUID) error {
+	challenge, ok := f.challenges[challengeID]
+	if !ok || challenge.UsedAt != nil {
+		return &repoerrs.TwoFactorChallengeNotFoundError{}
+	}
+	now := time.Now()
+	challenge.UsedAt = &now
+	return nil
+}
+
+func (f *fakeAccountRepo) CreateAccountWithIdentity(user *models.User, identity *models.Identity, verificationCode string) error {
+	if err := f.CreateAccount(user, verificationCode); err != nil {
+		return err
+	}
+	identity.UserId = user.Id
+	return f.CreateIdentity(identity)
+}
+
+func (f *fakeAccountRepo) MarkAccountVerified(userID uuid.UUID) error {
+	if user, ok := f.usersByID[userID]; ok {
+		user.Verified = true
+	}
+	return nil
+}
+
+func (f *fakeAccountRepo) CreateIdentity(identity *models.Identity) error {
+	for _, existing := range f.identities {
+		if (existing.Provider == identity.Provider && existing.Subject == identity.Subject) ||
+			(existing.Provider == identity.Provider && existing.UserId == identity.UserId) {
+			return &repoerrs.IdentityAlreadyLinkedError{}
+		}
+	}
+	identity.Id = uuid.New()
+	f.identities = append(f.identities, identity)
+	return nil
+}
+
+func (f *fakeAccountRepo) GetIdentity(provider string, subject string) (*models.Identity, error) {
+	for _, identity := range f.identities {
+		if identity.Provider == provider && identity.Subject == subject {
+			return identity, nil
+		}
+	}
+	return nil, &repoerrs.IdentityNotFoundError{}
+}
+
+func (f *fakeAccountRepo) ListIdentities(userID uuid.UUID) ([]models.Identity, error) {
+	var identities []models.Identity
+	for _, identity := range f.identities {
+		if identity.UserId == userID {
+			identities = append(identities, *identity)
+		}
+	}
+	return identities, nil
+}
+
+func (f *fakeAccountRepo) DeleteIdentity(userID uuid.UUID, provider string) error {
+	for i, identity := range f.identities {
+		if identity.UserId == userID && identity.Provider == provider {
+			f.identities = append(f.identities[:i], f.identities[i+1:]...)
+			return nil
+		}
+	}
+	return &repoerrs.IdentityNotFoundError{}
+}
+
+func (f *fakeAccountRepo) CreateOIDCLoginState(state *models.OIDCLoginState) error {
+	f.loginStates[state.StateHash] = state
+	return nil
+}
+
+func (f *fakeAccountRepo) ConsumeOIDCLoginState(stateHash string, provider string, now time.Time) (*models.OIDCLoginState, error) {
+	state, ok := f.loginStates[stateHash]
+	if !ok || state.Provider != provider || !state.ExpiresAt.After(now) {
+		return nil, &repoerrs.OIDCLoginStateNotFoundError{}
+	}
+	delete(f.loginStates, stateHash)
+	return state, nil
+}
+
+func (f *fakeAccountRepo) ScheduleAccountDeletion(userID uuid.UUID, scheduledAt time.Time, cancelTokenHash string) error {
+	user, ok := f.usersByID[userID]
+	if !ok {
+		return &repoerrs.AccountNotFoundError{}
+	}
+	user.DeletionScheduledAt = &scheduledAt
+	user.DeletionCancelTokenHash = &cancelTokenHash
+	return nil
+}
+
+func (f *fakeAccountRepo) CancelAccountDeletion(cancelTokenHash string, now time.Time) error {
+	for _, user := range f.usersByID {
+		if user.DeletionCancelTokenHash != nil && *user.DeletionCancelTokenHash == cancelTokenHash && user.DeletionScheduledAt.After(now) {
+			user.DeletionScheduledAt = nil
+			user.DeletionCancelTokenHash = nil
+			return nil
+		}
+	}
+	return &repoerrs.AccountNotFoundError{}
+}
+
+func (f *fakeAccountRepo) ListAccountsDueForDeletion(now time.Time, limit int) ([]models.User, error) {
+	users := []models.User{}
+	for _, user := range f.usersByID {
+		if user.DeletionScheduledAt != nil && !user.DeletionScheduledAt.After(now) && len(users) < limit {
+			users = append(users, *user)
+		}
+	}
+	return users, nil
+}
+
+func (f *fakeAccountRepo) DeleteAccount(userID uuid.UUID) error {
+	user, ok := f.usersByID[userID]
+	if !ok || user.DeletionScheduledAt == nil {
+		return &repoerrs.AccountNotFoundError{}
+	}
+	delete(f.usersByID, userID)
+	delete(f.usersByEmail, user.Email)
+	return nil
+}
+
+func (f *fakeAccountRepo) CreateEmailChange(change *models.EmailChange) error {
+	stored := *change
+	f.emailChanges[change.UserId] = &stored
+	return nil
+}
+
+func (f *fakeAccountRepo) ConfirmEmailChange(userID uuid.UUID, code string, now time.Time) (string, error) {
+	change, ok := f.emailChanges[userID]
+	if !ok {
+		return "", &repoerrs.EmailChangeNotFoundError{}
+	}
+	if change.ExpiresAt.Before(now) {
+		return "", &repoerrs.EmailChangeExpiredError{}
+	}
+	if change.VerificationCode != code {
+		change.Attempts++
+		if change.Attempts >= 3 {
+			delete(f.emailChanges, userID)
+		}
+		return "", &repoerrs.InvalidEmailChangeCodeError{}
+	}
+	if _, taken := f.usersByEmail[change.NewEmail]; taken {
+		return "", &repoerrs.EmailAlreadyInUseError{}
+	}
+	user := f.usersByID[userID]
+	delete(f.usersByEmail, user.Email)
+	user.Email = change.NewEmail
+	f.usersByEmail[user.Email] = user
+	delete(f.emailChanges, userID)
+	return change.NewEmail, nil
+}
+
+func (f *fakeAccountRepo) ListRoles() ([]models.Role, error) {
+	roles := make([]models.Role, 0, len(f.roles))
+	for _, role := range f.roles {
+		roles = append(roles, *role)
+	}
+	return roles, nil
+}
+
+func (f *fakeAccountRepo) SaveRole(role *models.Role) error {
+	f.roles[role.Name] = role
+	return nil
+}
+
+func (f *fakeAccountRepo) DeleteRole(name string) error {
+	if _, ok := f.roles[name]; !ok {
+		return &repoerrs.RoleNotFoundError{}
+	}
+	delete(f.roles, name)
+	for userID, names := range f.userRoles {
+		f.userRoles[userID] = slices.DeleteFunc(names, func(n string) bool { return n == name })
+	}
+	return nil
+}
+
+func (f *fakeAccountRepo) ListUserRoles(userID uuid.UUID) ([]string, error) {
+	return f.userRoles[userID], nil
+}
+
+func (f *fakeAccountRepo) SetUserRoles(userID uuid.UUID, roleNames []string) error {
+	for _, name := range roleNames {
+		if _, ok := f.roles[name]; !ok {
+			return &repoerrs.RoleNotFoundError{}
+		}
+	}
+	f.userRoles[userID] = roleNames
+	return nil
+}
+
+func (f *fakeAccountRepo) GetUserPermissions(userID uuid.UUID) ([]string, error) {
+	granted := []string{}
+	for _, name := range f.userRoles[userID] {
+		for _, permission := range f.roles[name].PermissionNames() {
+			if !slices.Contains(granted, permission) {
+				granted = append(granted, permission)
+			}
+		}
+	}
+	return granted, nil
+}
+
+func (f *fakeAccountRepo) onlySession(t *testing.T) *models.Session {
+	t.Helper()
+	require.Len(t, f.sessions, 1)
+	for _, session := range f.sessions {
+		return session
+	}
+	return nil
+}
+
 func resetTokenToHash(token string) string {
 	sum := sha256.Sum256([]byte(token))
 	return hex.EncodeToString(sum[:])
@@ -209,7 +611,7 @@ func resetTokenToHash(token string) string {
 func TestAccountService_LoginAccount_InvalidPassword(t *testing.T) {
 	repo := newFakeAccountRepo()
 	conf := config.CreateConfig()
-	jwtManager := session.NewJWTManager("access", "refresh", time.Minute, time.Hour)
+	jwtManager := session.NewJWTManager(session.NewEphemeralSigningKeys(), "refresh", time.Minute, time.Hour)
 
 	hashed, err := hashing.HashPassword("Password1")
 	require.NoError(t, err)
@@ -218,7 +620,7 @@ func TestAccountService_LoginAccount_InvalidPassword(t *testing.T) {
 	repo.usersByID[user.Id] = user
 
 	svc := &accountService{conf: conf, repo: repo, jwt: jwtManager}
-	_, _, _, err = svc.LoginAccount("user@example.com", "Wrong1", false)
+	_, _, _, err = svc.LoginAccount("user@example.com", "Wrong1", false, SessionDevice{})
 	assert.Error(t, err)
 	_, notFound := err.(*AccountNotFoundError)
 	assert.True(t, notFound)
@@ -227,7 +629,7 @@ func TestAccountService_LoginAccount_InvalidPassword(t *testing.T) {
 func TestAccountService_LoginAccount_NotVerified(t *testing.T) {
 	repo := newFakeAccountRepo()
 	conf := config.CreateConfig()
-	jwtManager := session.NewJWTManager("access", "refresh", time.Minute, time.Hour)
+	jwtManager := session.NewJWTManager(session.NewEphemeralSigningKeys(), "refresh", time.Minute, time.Hour)
 
 	hashed, err := hashing.HashPassword("Password1")
 	require.NoError(t, err)
@@ -236,15 +638,30 @@ func TestAccountService_LoginAccount_NotVerified(t *testing.T) {
 	repo.usersByID[user.Id] = user
 
 	svc := &accountService{conf: conf, repo: repo, jwt: jwtManager}
-	_, _, _, err = svc.LoginAccount("user@example.com", "Password1", false)
+	_, _, _, err = svc.LoginAccount("user@example.com", "Password1", false, SessionDevice{})
 	assert.Error(t, err)
 	_, notVerified := err.(*AccountNotVerifiedError)
 	assert.True(t, notVerified)
 }
 
+func TestAccountService_GetUserById(t *testing.T) {
+	repo := newFakeAccountRepo()
+	user := &models.User{Id: uuid.New(), Email: "user@example.com"}
+	repo.usersByID[user.Id] = user
+	svc := &accountService{conf: config.CreateConfig(), repo: repo, jwt: session.NewJWTManager(session.NewEphemeralSigningKeys(), "b", time.Minute, time.Hour)}
+
+	got, err := svc.GetUserById(user.Id)
+	require.NoError(t, err)
+	assert.Equal(t, user.Email, got.Email)
+
+	_, err = svc.GetUserById(uuid.New())
+	_, notFound := err.(*AccountNotFoundError)
+	assert.True(t, notFound)
+}
+
 func TestAccountService_UpdateAdminStatus_InvalidID(t *testing.T) {
 	repo := newFakeAccountRepo()
-	svc := &accountService{conf: config.CreateConfig(), repo: repo, jwt: session.NewJWTManager("a", "b", time.Minute, time.Hour)}
+	svc := &accountService{conf: config.CreateConfig(), repo: repo, jwt: session.NewJWTManager(session.NewEphemeralSigningKeys(), "b", time.Minute, time.Hour)}
 
 	err := svc.UpdateAdminStatus("not-a-uuid", true)
 	assert.Error(t, err)
@@ -255,10 +672,10 @@ func TestAccountService_UpdateAdminStatus_InvalidID(t *testing.T) {
 func TestAccountService_ValidateAccessToken_Expired(t *testing.T) {
 	repo := newFakeAccountRepo()
 	conf := config.CreateConfig()
-	jwtManager := session.NewJWTManager("access", "refresh", -time.Minute, time.Hour)
+	jwtManager := session.NewJWTManager(session.NewEphemeralSigningKeys(), "refresh", -time.Minute, time.Hour)
 	svc := &accountService{conf: conf, repo: repo, jwt: jwtManager}
 
-	expiredToken, err := jwtManager.GenerateAccessToken("user-id", "user@example.com", false)
+	expiredToken, err := jwtManager.GenerateAccessToken("user-id", "user@example.com", false, nil, uuid.NewString())
 	require.NoError(t, err)
 
 	err = svc.ValidateAccessToken(expiredToken)
@@ -270,10 +687,10 @@ func TestAccountService_ValidateAccessToken_Expired(t *testing.T) {
 func TestAccountService_ValidateAccessToken_InvalidUser(t *testing.T) {
 	repo := newFakeAccountRepo()
 	conf := config.CreateConfig()
-	jwtManager := session.NewJWTManager("access", "refresh", time.Minute, time.Hour)
+	jwtManager := session.NewJWTManager(session.NewEphemeralSigningKeys(), "refresh", time.Minute, time.Hour)
 	svc := &accountService{conf: conf, repo: repo, jwt: jwtManager}
 
-	token, err := jwtManager.GenerateAccessToken(uuid.New().String(), "user@example.com", false)
+	token, err := jwtManager.GenerateAccessToken(uuid.New().String(), "user@example.com", false, nil, uuid.NewString())
 	require.NoError(t, err)
 
 	err = svc.ValidateAccessToken(token)
@@ -286,9 +703,13 @@ func TestAccountService_ValidateAccessToken_FixedToken(t *testing.T) {
 	repo := newFakeAccountRepo()
 	conf := config.CreateConfig()
 	conf.ServerFixedToken = "fixed-token"
-	jwtManager := session.NewJWTManager("access", "refresh", time.Minute, time.Hour)
+	jwtManager := session.NewJWTManager(session.NewEphemeralSigningKeys(), "refresh", time.Minute, time.Hour)
 	svc := &accountService{conf: conf, repo: repo, jwt: jwtManager}
 
+	_, invalid := svc.ValidateAccessToken("fixed-token").(*AccountSessionInvalid)
+	assert.True(t, invalid, "the fixed token is only accepted when the fallback is enabled")
+
+	conf.ServerFixedTokenEnabled = true
 	err := svc.ValidateAccessToken("fixed-token")
 	assert.NoError(t, err)
 }
@@ -296,7 +717,7 @@ func TestAccountService_ValidateAccessToken_FixedToken(t *testing.T) {
 func TestAccountService_ValidateAccessToken_MissingUserID(t *testing.T) {
 	repo := newFakeAccountRepo()
 	conf := config.CreateConfig()
-	jwtManager := session.NewJWTManager("access", "refresh", time.Minute, time.Hour)
+	jwtManager := session.NewJWTManager(session.NewEphemeralSigningKeys(), "refresh", time.Minute, time.Hour)
 	svc := &accountService{conf: conf, repo: repo, jwt: jwtManager}
 
 	claims := jwt.MapClaims{
@@ -313,24 +734,18 @@ func TestAccountService_ValidateAccessToken_MissingUserID(t *testing.T) {
 	assert.True(t, invalid)
 }
 
-func TestAccountService_ValidateRefreshToken_ExpiredByUpdate(t *testing.T) {
-	repo := newFakeAccountRepo()
-	conf := config.CreateConfig()
-	jwtManager := session.NewJWTManager("access", "refresh", time.Minute, time.Hour)
-
-	user := &models.User{Id: uuid.New(), Email: "user@example.com", Verified: true}
-	user.RefreshTokenUpdatedAt = time.Now().Add(time.Minute)
-	repo.usersByEmail[user.Email] = user
-	repo.usersByID[user.Id] = user
-
-	svc := &accountService{conf: conf, repo: repo, jwt: jwtManager}
-	token, err := jwtManager.GenerateRefreshToken(user.Id.String(), user.Email, false)
+func TestAccountService_ValidateAccessToken_RevokedSession(t *testing.T) {
+	repo, user, svc := newSessionTestService(t)
+	loginTestUser(t, svc)
+	activeSession := repo.onlySession(t)
+	accessToken, err := svc.jwt.GenerateAccessToken(user.Id.String(), user.Email, false, nil, activeSession.Id.String())
 	require.NoError(t, err)
 
-	err = svc.ValidateRefreshToken(token, user.Email)
-	assert.Error(t, err)
-	_, expired := err.(*AccountSessionExpired)
-	assert.True(t, expired)
+	assert.NoError(t, svc.ValidateAccessToken(accessToken))
+
+	require.NoError(t, svc.Logout(user.Id, activeSession.Id))
+	_, invalid := svc.ValidateAccessToken(accessToken).(*AccountSessionInvalid)
+	assert.True(t, invalid)
 }
 
 func TestAccountService_RefreshVerificationCode_AlreadyVerified(t *testing.T) {
@@ -340,7 +755,7 @@ func TestAccountService_RefreshVerificationCode_AlreadyVerified(t *testing.T) {
 	repo.usersByEmail[user.Email] = user
 	repo.usersByID[user.Id] = user
 
-	svc := &accountService{conf: conf, repo: repo, jwt: session.NewJWTManager("a", "b", time.Minute, time.Hour)}
+	svc := &accountService{conf: conf, repo: repo, jwt: session.NewJWTManager(session.NewEphemeralSigningKeys(), "b", time.Minute, time.Hour)}
 	_, err := svc.RefreshVerificationCode(user.Email)
 	assert.Error(t, err)
 	_, already := err.(*AccountAlreadyVerifiedError)
@@ -354,7 +769,7 @@ func TestAccountService_ForgotPassword_Success(t *testing.T) {
 	repo.usersByEmail[user.Email] = user
 	repo.usersByID[user.Id] = user
 
-	svc := &accountService{conf: conf, repo: repo, jwt: session.NewJWTManager("a", "b", time.Minute, time.Hour)}
+	svc := &accountService{conf: conf, repo: repo, jwt: session.NewJWTManager(session.NewEphemeralSigningKeys(), "b", time.Minute, time.Hour)}
 	otp, err := svc.ForgotPassword(user.Email)
 	assert.NoError(t, err)
 	assert.Len(t, otp, 6)
@@ -362,7 +777,7 @@ func TestAccountService_ForgotPassword_Success(t *testing.T) {
 
 func TestAccountService_ForgotPassword_NoUser(t *testing.T) {
 	repo := newFakeAccountRepo()
-	svc := &accountService{conf: config.CreateConfig(), repo: repo, jwt: session.NewJWTManager("a", "b", time.Minute, time.Hour)}
+	svc := &accountService{conf: config.CreateConfig(), repo: repo, jwt: session.NewJWTManager(session.NewEphemeralSigningKeys(), "b", time.Minute, time.Hour)}
 
 	otp, err := svc.ForgotPassword("missing@example.com")
 	assert.NoError(t, err)
@@ -385,7 +800,7 @@ func TestAccountService_VerifyPasswordResetCode_Invalid(t *testing.T) {
 		OTPExpiresAt: time.Now().Add(time.Minute),
 	}
 
-	svc := &accountService{conf: conf, repo: repo, jwt: session.NewJWTManager("a", "b", time.Minute, time.Hour)}
+	svc := &accountService{conf: conf, repo: repo, jwt: session.NewJWTManager(session.NewEphemeralSigningKeys(), "b", time.Minute, time.Hour)}
 	_, err = svc.VerifyPasswordResetCode(user.Email, "000000")
 	assert.Error(t, err)
 	_, invalid := err.(*InvalidPasswordResetCodeError)
@@ -408,7 +823,7 @@ func TestAccountService_VerifyPasswordResetCode_Success(t *testing.T) {
 		OTPExpiresAt: time.Now().Add(time.Minute),
 	}
 
-	svc := &accountService{conf: conf, repo: repo, jwt: session.NewJWTManager("a", "b", time.Minute, time.Hour)}
+	svc := &accountService{conf: conf, repo: repo, jwt: session.NewJWTManager(session.NewEphemeralSigningKeys(), "b", time.Minute, time.Hour)}
 	token, err := svc.VerifyPasswordResetCode(user.Email, "123456")
 	assert.NoError(t, err)
 	assert.NotEmpty(t, token)
@@ -435,7 +850,7 @@ func TestAccountService_ResetPassword_ExpiredToken(t *testing.T) {
 	repo, _ := newResetPasswordRepo("sometoken", expiredAt)
 	conf := config.CreateConfig()
 
-	svc := &accountService{conf: conf, repo: repo, jwt: session.NewJWTManager("a", "b", time.Minute, time.Hour)}
+	svc := &accountService{conf: conf, repo: repo, jwt: session.NewJWTManager(session.NewEphemeralSigningKeys(), "b", time.Minute, time.Hour)}
 	err := svc.ResetPassword("sometoken", "Password1")
 	assert.Error(t, err)
 	_, expired := err.(*PasswordResetTokenExpiredError)
@@ -447,7 +862,7 @@ func TestAccountService_ResetPassword_InvalidPassword(t *testing.T) {
 	repo, _ := newResetPasswordRepo("sometoken", validUntil)
 	conf := config.CreateConfig()
 
-	svc := &accountService{conf: conf, repo: repo, jwt: session.NewJWTManager("a", "b", time.Minute, time.Hour)}
+	svc := &accountService{conf: conf, repo: repo, jwt: session.NewJWTManager(session.NewEphemeralSigningKeys(), "b", time.Minute, time.Hour)}
 	err := svc.ResetPassword("sometoken", "short")
 	assert.Error(t, err)
 	_, invalid := err.(*AccountInvalidFormat)
@@ -456,18 +871,23 @@ func TestAccountService_ResetPassword_InvalidPassword(t *testing.T) {
 
 func TestAccountService_ResetPassword_Success(t *testing.T) {
 	validUntil := time.Now().Add(time.Minute)
-	repo, _ := newResetPasswordRepo("sometoken", validUntil)
+	repo, user := newResetPasswordRepo("sometoken", validUntil)
 	conf := config.CreateConfig()
+	repo.sessions[uuid.New()] = &models.Session{UserId: user.Id, ExpiresAt: validUntil}
 
-	svc := &accountService{conf: conf, repo: repo, jwt: session.NewJWTManager("a", "b", time.Minute, time.Hour)}
+	svc := &accountService{conf: conf, repo: repo, jwt: session.NewJWTManager(session.NewEphemeralSigningKeys(), "b", time.Minute, time.Hour)}
 	err := svc.ResetPassword("sometoken", "Password1")
 	assert.NoError(t, err)
+
+	sessions, err := repo.ListActiveSessions(user.Id, time.Now())
+	require.NoError(t, err)
+	assert.Empty(t, sessions, "a password reset logs out every device")
 }
 
 func TestAccountService_CreateAccount_InvalidEmail(t *testing.T) {
 	repo := newFakeAccountRepo()
 	conf := config.CreateConfig()
-	svc := &accountService{conf: conf, repo: repo, jwt: session.NewJWTManager("a", "b", time.Minute, time.Hour)}
+	svc := &accountService{conf: conf, repo: repo, jwt: session.NewJWTManager(session.NewEphemeralSigningKeys(), "b", time.Minute, time.Hour)}
 
 	_, _, err := svc.CreateAccount("", "Password1", false)
 	assert.Error(t, err)
@@ -485,7 +905,7 @@ func TestAccountService_CreateAccount_InvalidEmail(t *testing.T) {
 func TestAccountService_CreateAccount_InvalidPassword(t *testing.T) {
 	repo := newFakeAccountRepo()
 	conf := config.CreateConfig()
-	svc := &accountService{conf: conf, repo: repo, jwt: session.NewJWTManager("a", "b", time.Minute, time.Hour)}
+	svc := &accountService{conf: conf, repo: repo, jwt: session.NewJWTManager(session.NewEphemeralSigningKeys(), "b", time.Minute, time.Hour)}
 
 	_, _, err := svc.CreateAccount("user@example.com", "", false)
 	assert.Error(t, err)
@@ -519,7 +939,7 @@ func TestAccountService_CreateAccount_AlreadyExists(t *testing.T) {
 	repo.usersByEmail[existing.Email] = existing
 	repo.usersByID[existing.Id] = existing
 
-	svc := &accountService{conf: conf, repo: repo, jwt: session.NewJWTManager("a", "b", time.Minute, time.Hour)}
+	svc := &accountService{conf: conf, repo: repo, jwt: session.NewJWTManager(session.NewEphemeralSigningKeys(), "b", time.Minute, time.Hour)}
 	_, _, err := svc.CreateAccount(existing.Email, "Password1", false)
 	assert.Error(t, err)
 	_, exists := err.(*AccountAlreadyExistsError)
@@ -530,7 +950,7 @@ func TestAccountService_CreateAccount_RepoError(t *testing.T) {
 	repo := newFakeAccountRepo()
 	repo.createErr = errors.New("db")
 	conf := config.CreateConfig()
-	svc := &accountService{conf: conf, repo: repo, jwt: session.NewJWTManager("a", "b", time.Minute, time.Hour)}
+	svc := &accountService{conf: conf, repo: repo, jwt: session.NewJWTManager(session.NewEphemeralSigningKeys(), "b", time.Minute, time.Hour)}
 
 	_, _, err := svc.CreateAccount("user@example.com", "Password1", false)
 	assert.Error(t, err)
@@ -538,32 +958,147 @@ func TestAccountService_CreateAccount_RepoError(t *testing.T) {
 	assert.True(t, failed)
 }
 
-func TestAccountService_RefreshToken_NotVerified(t *testing.T) {
+// newSessionTestService returns a service with a verified user that can log in with Password1.
+func newSessionTestService(t *testing.T) (*fakeAccountRepo, *models.User, *accountService) {
+	t.Helper()
 	repo := newFakeAccountRepo()
-	conf := config.CreateConfig()
-	user := &models.User{Id: uuid.New(), Email: "user@example.com", Verified: false}
+	hashed, err := hashing.HashPassword("Password1")
+	require.NoError(t, err)
+	user := &models.User{Id: uuid.New(), Email: "user@example.com", Password: string(hashed), Verified: true}
 	repo.usersByEmail[user.Email] = user
 	repo.usersByID[user.Id] = user
 
-	svc := &accountService{conf: conf, repo: repo, jwt: session.NewJWTManager("a", "b", time.Minute, time.Hour)}
-	_, _, err := svc.RefreshToken(user.Email)
+	svc := &accountService{conf: config.CreateConfig(), repo: repo, jwt: session.NewJWTManager(session.NewEphemeralSigningKeys(), "b", time.Minute, time.Hour), clients: service_clients.NewInProcessClients()}
+	return repo, user, svc
+}
+
+func loginTestUser(t *testing.T, svc *accountService) string {
+	t.Helper()
+	_, _, refreshToken, err := svc.LoginAccount("user@example.com", "Password1", false, SessionDevice{UserAgent: "phone", IPAddress: "10.0.0.1"})
+	require.NoError(t, err)
+	return refreshToken
+}
+
+func TestAccountService_RefreshToken_NotVerified(t *testing.T) {
+	repo, user, svc := newSessionTestService(t)
+	refreshToken := loginTestUser(t, svc)
+	user.Verified = false
+
+	_, _, err := svc.RefreshToken(refreshToken, user.Email, SessionDevice{})
 	assert.Error(t, err)
 	_, notVerified := err.(*AccountNotVerifiedError)
 	assert.True(t, notVerified)
+	assert.Nil(t, repo.onlySession(t).RevokedAt)
 }
 
-func TestAccountService_RefreshToken_Success(t *testing.T) {
-	repo := newFakeAccountRepo()
-	conf := config.CreateConfig()
-	user := &models.User{Id: uuid.New(), Email: "user@example.com", Verified: true}
-	repo.usersByEmail[user.Email] = user
-	repo.usersByID[user.Id] = user
+func TestAccountService_RefreshToken_RotatesToken(t *testing.T) {
+	repo, user, svc := newSessionTestService(t)
+	refreshToken := loginTestUser(t, svc)
 
-	svc := &accountService{conf: conf, repo: repo, jwt: session.NewJWTManager("a", "b", time.Minute, time.Hour)}
-	accessToken, refreshToken, err := svc.RefreshToken(user.Email)
-	assert.NoError(t, err)
+	accessToken, rotatedToken, err := svc.RefreshToken(refreshToken, user.Email, SessionDevice{UserAgent: "tablet", IPAddress: "10.0.0.2"})
+	require.NoError(t, err)
 	assert.NotEmpty(t, accessToken)
-	assert.NotEmpty(t, refreshToken)
+	assert.NotEqual(t, refreshToken, rotatedToken)
+
+	stored := repo.onlySession(t)
+	assert.Equal(t, resetTokenToHash(rotatedToken), stored.RefreshTokenHash)
+	assert.Equal(t, "tablet", stored.UserAgent)
+	assert.Equal(t, "10.0.0.2", stored.IPAddress)
+
+	_, _, err = svc.RefreshToken(rotatedToken, user.Email, SessionDevice{})
+	assert.NoError(t, err)
+}
+
+func TestAccountService_RefreshToken_ReuseRevokesSession(t *testing.T) {
+	repo, user, svc := newSessionTestService(t)
+	refreshToken := loginTestUser(t, svc)
+
+	_, rotatedToken, err := svc.RefreshToken(refreshToken, user.Email, SessionDevice{})
+	require.NoError(t, err)
+
+	_, _, err = svc.RefreshToken(refreshToken, user.Email, SessionDevice{})
+	_, invalid := err.(*AccountSessionInvalid)
+	assert.True(t, invalid)
+	assert.Equal(t, models.SESSION_REVOKED_TOKEN_REUSE, repo.onlySession(t).RevokedReason)
+
+	// The legitimate holder of the rotated token is logged out as well
+	_, _, err = svc.RefreshToken(rotatedToken, user.Email, SessionDevice{})
+	_, invalid = err.(*AccountSessionInvalid)
+	assert.True(t, invalid)
+}
+
+func TestAccountService_RefreshToken_SessionsAreIndependent(t *testing.T) {
+	repo, user, svc := newSessionTestService(t)
+	firstDevice := loginTestUser(t, svc)
+	secondDevice := loginTestUser(t, svc)
+	assert.Len(t, repo.sessions, 2)
+
+	_, _, err := svc.RefreshToken(firstDevice, user.Email, SessionDevice{})
+	assert.NoError(t, err)
+	_, _, err = svc.RefreshToken(secondDevice, user.Email, SessionDevice{})
+	assert.NoError(t, err)
+}
+
+func TestAccountService_RefreshToken_RotateRace(t *testing.T) {
+	repo, user, svc := newSessionTestService(t)
+	refreshToken := loginTestUser(t, svc)
+	repo.rotateErr = &repoerrs.SessionNotFoundError{}
+
+	_, _, err := svc.RefreshToken(refreshToken, user.Email, SessionDevice{})
+	_, invalid := err.(*AccountSessionInvalid)
+	assert.True(t, invalid)
+	assert.Equal(t, models.SESSION_REVOKED_TOKEN_REUSE, repo.onlySession(t).RevokedReason)
+}
+
+func TestAccountService_Sessions_ListAndRevoke(t *testing.T) {
+	repo, user, svc := newSessionTestService(t)
+	loginTestUser(t, svc)
+	loginTestUser(t, svc)
+
+	sessions, err := svc.ListSessions(user.Id)
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+
+	_, notFound := svc.RevokeSession(uuid.New(), sessions[0].Id).(*SessionNotFoundError)
+	assert.True(t, notFound, "other users cannot revoke the session")
+
+	require.NoError(t, svc.RevokeSession(user.Id, sessions[0].Id))
+	_, notFound = svc.RevokeSession(user.Id, sessions[0].Id).(*SessionNotFoundError)
+	assert.True(t, notFound)
+
+	remaining, err := svc.ListSessions(user.Id)
+	require.NoError(t, err)
+	assert.Len(t, remaining, 1)
+
+	require.NoError(t, svc.RevokeAllSessions(user.Id.String()))
+	remaining, err = svc.ListSessions(user.Id)
+	require.NoError(t, err)
+	assert.Empty(t, remaining)
+	for _, session := range repo.sessions {
+		assert.NotNil(t, session.RevokedAt)
+	}
+}
+
+func TestAccountService_Logout_Idempotent(t *testing.T) {
+	repo, user, svc := newSessionTestService(t)
+	refreshToken := loginTestUser(t, svc)
+	sessionId := repo.onlySession(t).Id
+
+	require.NoError(t, svc.Logout(user.Id, sessionId))
+	require.NoError(t, svc.Logout(user.Id, sessionId))
+
+	_, _, err := svc.RefreshToken(refreshToken, user.Email, SessionDevice{})
+	_, invalid := err.(*AccountSessionInvalid)
+	assert.True(t, invalid)
+}
+
+func TestAccountService_RevokeAllSessions_Errors(t *testing.T) {
+	_, _, svc := newSessionTestService(t)
+
+	_, invalid := svc.RevokeAllSessions("not-a-uuid").(*AccountInvalidFormat)
+	assert.True(t, invalid)
+	_, notFound := svc.RevokeAllSessions(uuid.NewString()).(*AccountNotFoundError)
+	assert.True(t, notFound)
 }
 
 func TestAccountService_VerifyAccount_ErrorMapping(t *testing.T) {
@@ -574,7 +1109,7 @@ func TestAccountService_VerifyAccount_ErrorMapping(t *testing.T) {
 	repo.usersByID[user.Id] = user
 	repo.verifyErr = &repoerrs.AccountVerificationExpired{}
 
-	svc := &accountService{conf: conf, repo: repo, jwt: session.NewJWTManager("a", "b", time.Minute, time.Hour)}
+	svc := &accountService{conf: conf, repo: repo, jwt: session.NewJWTManager(session.NewEphemeralSigningKeys(), "b", time.Minute, time.Hour)}
 	_, err := svc.VerifyAccount(user.Email, "code")
 	assert.Error(t, err)
 	_, expired := err.(*VerificationCodeExpiredError)
@@ -589,7 +1124,7 @@ func TestAccountService_VerifyAccount_InvalidCode(t *testing.T) {
 	repo.usersByID[user.Id] = user
 	repo.verifyErr = &repoerrs.AccountNotVerifiedError{}
 
-	svc := &accountService{conf: conf, repo: repo, jwt: session.NewJWTManager("a", "b", time.Minute, time.Hour)}
+	svc := &accountService{conf: conf, repo: repo, jwt: session.NewJWTManager(session.NewEphemeralSigningKeys(), "b", time.Minute, time.Hour)}
 	_, err := svc.VerifyAccount(user.Email, "bad")
 	assert.Error(t, err)
 	_, invalid := err.(*InvalidVerificationCodeError)
@@ -601,7 +1136,7 @@ func TestAccountService_ListAccounts(t *testing.T) {
 	repo.listUsers = []models.User{{Email: "a@example.com"}, {Email: "b@example.com"}}
 	repo.listTotal = 2
 
-	svc := &accountService{conf: config.CreateConfig(), repo: repo, jwt: session.NewJWTManager("a", "b", time.Minute, time.Hour)}
+	svc := &accountService{conf: config.CreateConfig(), repo: repo, jwt: session.NewJWTManager(session.NewEphemeralSigningKeys(), "b", time.Minute, time.Hour)}
 	verified := true
 	users, total, err := svc.ListAccounts("a", &verified, 0, 10)
 	assert.NoError(t, err)
@@ -617,7 +1152,7 @@ func TestAccountService_RefreshVerificationCode_RepoError(t *testing.T) {
 	repo.usersByID[user.Id] = user
 	repo.refreshErr = errors.New("boom")
 
-	svc := &accountService{conf: conf, repo: repo, jwt: session.NewJWTManager("a", "b", time.Minute, time.Hour)}
+	svc := &accountService{conf: conf, repo: repo, jwt: session.NewJWTManager(session.NewEphemeralSigningKeys(), "b", time.Minute, time.Hour)}
 	_, err := svc.RefreshVerificationCode(user.Email)
 	assert.Error(t, err)
 	_, failed := err.(*AccountFailedToCreateError)
@@ -626,7 +1161,7 @@ func TestAccountService_RefreshVerificationCode_RepoError(t *testing.T) {
 
 func TestAccountService_ResetPassword_InvalidToken(t *testing.T) {
 	repo := newFakeAccountRepo()
-	svc := &accountService{conf: config.CreateConfig(), repo: repo, jwt: session.NewJWTManager("a", "b", time.Minute, time.Hour)}
+	svc := &accountService{conf: config.CreateConfig(), repo: repo, jwt: session.NewJWTManager(session.NewEphemeralSigningKeys(), "b", time.Minute, time.Hour)}
 
 	err := svc.ResetPassword("token", "Password1")
 	assert.Error(t, err)
@@ -640,7 +1175,7 @@ func TestAccountService_ResetPassword_RepoError(t *testing.T) {
 	repo.updatePasswordErr = errors.New("boom")
 	conf := config.CreateConfig()
 
-	svc := &accountService{conf: conf, repo: repo, jwt: session.NewJWTManager("a", "b", time.Minute, time.Hour)}
+	svc := &accountService{conf: conf, repo: repo, jwt: session.NewJWTManager(session.NewEphemeralSigningKeys(), "b", time.Minute, time.Hour)}
 	err := svc.ResetPassword("sometoken", "Password1")
 	assert.Error(t, err)
 	_, failed := err.(*AccountFailedToCreateError)
@@ -655,7 +1190,7 @@ func TestAccountService_ForgotPassword_CreateError(t *testing.T) {
 	repo.usersByID[user.Id] = user
 	repo.createResetErr = errors.New("boom")
 
-	svc := &accountService{conf: conf, repo: repo, jwt: session.NewJWTManager("a", "b", time.Minute, time.Hour)}
+	svc := &accountService{conf: conf, repo: repo, jwt: session.NewJWTManager(session.NewEphemeralSigningKeys(), "b", time.Minute, time.Hour)}
 	_, err := svc.ForgotPassword(user.Email)
 	assert.Error(t, err)
 	_, failed := err.(*AccountFailedToCreateError)
@@ -676,7 +1211,7 @@ func TestAccountService_VerifyPasswordResetCode_MaxAttempts(t *testing.T) {
 		Attempts:     5,
 	}
 
-	svc := &accountService{conf: conf, repo: repo, jwt: session.NewJWTManager("a", "b", time.Minute, time.Hour)}
+	svc := &accountService{conf: conf, repo: repo, jwt: session.NewJWTManager(session.NewEphemeralSigningKeys(), "b", time.Minute, time.Hour)}
 	_, err := svc.VerifyPasswordResetCode(user.Email, "123456")
 	assert.Error(t, err)
 	_, maxed := err.(*PasswordResetMaxAttemptsError)
@@ -696,7 +1231,7 @@ func TestAccountService_VerifyPasswordResetCode_Expired(t *testing.T) {
 		OTPExpiresAt: time.Now().Add(-time.Minute),
 	}
 
-	svc := &accountService{conf: conf, repo: repo, jwt: session.NewJWTManager("a", "b", time.Minute, time.Hour)}
+	svc := &accountService{conf: conf, repo: repo, jwt: session.NewJWTManager(session.NewEphemeralSigningKeys(), "b", time.Minute, time.Hour)}
 	_, err := svc.VerifyPasswordResetCode(user.Email, "123456")
 	assert.Error(t, err)
 	_, expired := err.(*PasswordResetExpiredError)
@@ -706,7 +1241,7 @@ func TestAccountService_VerifyPasswordResetCode_Expired(t *testing.T) {
 func TestAccountService_CreateAccount_InvalidPasswordFallback(t *testing.T) {
 	repo := newFakeAccountRepo()
 	conf := config.CreateConfig()
-	svc := &accountService{conf: conf, repo: repo, jwt: session.NewJWTManager("a", "b", time.Minute, time.Hour)}
+	svc := &accountService{conf: conf, repo: repo, jwt: session.NewJWTManager(session.NewEphemeralSigningKeys(), "b", time.Minute, time.Hour)}
 
 	_, _, err := svc.CreateAccount("user@example.com", "Password@", false)
 	assert.Error(t, err)
@@ -718,7 +1253,7 @@ func TestAccountService_CreateAccount_InvalidPasswordFallback(t *testing.T) {
 func TestAccountService_CreateAccount_EmailInvalidError(t *testing.T) {
 	repo := newFakeAccountRepo()
 	conf := config.CreateConfig()
-	svc := &accountService{conf: conf, repo: repo, jwt: session.NewJWTManager("a", "b", time.Minute, time.Hour)}
+	svc := &accountService{conf: conf, repo: repo, jwt: session.NewJWTManager(session.NewEphemeralSigningKeys(), "b", time.Minute, time.Hour)}
 
 	_, _, err := svc.CreateAccount("user@invalid_domain", "Password1", false)
 	assert.Error(t, err)
@@ -729,7 +1264,7 @@ func TestAccountService_CreateAccount_EmailInvalidError(t *testing.T) {
 
 func TestAccountService_RefreshVerificationCode_NotFound(t *testing.T) {
 	repo := newFakeAccountRepo()
-	svc := &accountService{conf: config.CreateConfig(), repo: repo, jwt: session.NewJWTManager("a", "b", time.Minute, time.Hour)}
+	svc := &accountService{conf: config.CreateConfig(), repo: repo, jwt: session.NewJWTManager(session.NewEphemeralSigningKeys(), "b", time.Minute, time.Hour)}
 
 	_, err := svc.RefreshVerificationCode("missing@example.com")
 	assert.Error(t, err)
@@ -737,11 +1272,11 @@ func TestAccountService_RefreshVerificationCode_NotFound(t *testing.T) {
 	assert.True(t, notFound)
 }
 
-func TestAccountService_ValidateRefreshToken_NotFound(t *testing.T) {
+func TestAccountService_RefreshToken_AccountNotFound(t *testing.T) {
 	repo := newFakeAccountRepo()
-	svc := &accountService{conf: config.CreateConfig(), repo: repo, jwt: session.NewJWTManager("a", "b", time.Minute, time.Hour)}
+	svc := &accountService{conf: config.CreateConfig(), repo: repo, jwt: session.NewJWTManager(session.NewEphemeralSigningKeys(), "b", time.Minute, time.Hour)}
 
-	err := svc.ValidateRefreshToken("token", "missing@example.com")
+	_, _, err := svc.RefreshToken("token", "missing@example.com", SessionDevice{})
 	assert.Error(t, err)
 	_, notFound := err.(*AccountNotFoundError)
 	assert.True(t, notFound)
@@ -750,7 +1285,7 @@ func TestAccountService_ValidateRefreshToken_NotFound(t *testing.T) {
 func TestAccountService_LoginAccount_Success(t *testing.T) {
 	repo := newFakeAccountRepo()
 	conf := config.CreateConfig()
-	jwtManager := session.NewJWTManager("access", "refresh", time.Minute, time.Hour)
+	jwtManager := session.NewJWTManager(session.NewEphemeralSigningKeys(), "refresh", time.Minute, time.Hour)
 
 	hashed, err := hashing.HashPassword("Password1")
 	require.NoError(t, err)
@@ -759,7 +1294,7 @@ func TestAccountService_LoginAccount_Success(t *testing.T) {
 	repo.usersByID[user.Id] = user
 
 	svc := &accountService{conf: conf, repo: repo, jwt: jwtManager}
-	gotUser, access, refresh, err := svc.LoginAccount("ok@example.com", "Password1", false)
+	gotUser, access, refresh, err := svc.LoginAccount("ok@example.com", "Password1", false, SessionDevice{})
 	assert.NoError(t, err)
 	assert.NotNil(t, gotUser)
 	assert.NotEmpty(t, access)
@@ -768,28 +1303,28 @@ func TestAccountService_LoginAccount_Success(t *testing.T) {
 
 func TestAccountService_LoginAccount_UserNotFound(t *testing.T) {
 	repo := newFakeAccountRepo()
-	svc := &accountService{conf: config.CreateConfig(), repo: repo, jwt: session.NewJWTManager("a", "b", time.Minute, time.Hour)}
+	svc := &accountService{conf: config.CreateConfig(), repo: repo, jwt: session.NewJWTManager(session.NewEphemeralSigningKeys(), "b", time.Minute, time.Hour)}
 
-	_, _, _, err := svc.LoginAccount("missing@example.com", "Password1", false)
+	_, _, _, err := svc.LoginAccount("missing@example.com", "Password1", false, SessionDevice{})
 	assert.Error(t, err)
 	_, notFound := err.(*AccountNotFoundError)
 	assert.True(t, notFound)
 }
 
-func TestAccountService_LoginAccount_UpdateRefreshError(t *testing.T) {
+func TestAccountService_LoginAccount_CreateSessionError(t *testing.T) {
 	repo := newFakeAccountRepo()
 	conf := config.CreateConfig()
-	jwtManager := session.NewJWTManager("access", "refresh", time.Minute, time.Hour)
+	jwtManager := session.NewJWTManager(session.NewEphemeralSigningKeys(), "refresh", time.Minute, time.Hour)
 
 	hashed, err := hashing.HashPassword("Password1")
 	require.NoError(t, err)
 	user := &models.User{Id: uuid.New(), Email: "u@example.com", Password: string(hashed), Verified: true}
 	repo.usersByEmail[user.Email] = user
 	repo.usersByID[user.Id] = user
-	repo.updateRefreshErr = errors.New("db error")
+	repo.createSessionErr = errors.New("db error")
 
 	svc := &accountService{conf: conf, repo: repo, jwt: jwtManager}
-	_, _, _, err = svc.LoginAccount("u@example.com", "Password1", false)
+	_, _, _, err = svc.LoginAccount("u@example.com", "Password1", false, SessionDevice{})
 	assert.Error(t, err)
 	_, failed := err.(*AccountFailedToCreateTokenError)
 	assert.True(t, failed)
@@ -803,7 +1338,7 @@ func TestAccountService_GetUserByEmail_Found(t *testing.T) {
 	repo.usersByEmail[user.Email] = user
 	repo.usersByID[user.Id] = user
 
-	svc := &accountService{conf: config.CreateConfig(), repo: repo, jwt: session.NewJWTManager("a", "b", time.Minute, time.Hour)}
+	svc := &accountService{conf: config.CreateConfig(), repo: repo, jwt: session.NewJWTManager(session.NewEphemeralSigningKeys(), "b", time.Minute, time.Hour)}
 	got, err := svc.GetUserByEmail("found@example.com")
 	assert.NoError(t, err)
 	assert.Equal(t, user.Email, got.Email)
@@ -811,7 +1346,7 @@ func TestAccountService_GetUserByEmail_Found(t *testing.T) {
 
 func TestAccountService_GetUserByEmail_NotFound(t *testing.T) {
 	repo := newFakeAccountRepo()
-	svc := &accountService{conf: config.CreateConfig(), repo: repo, jwt: session.NewJWTManager("a", "b", time.Minute, time.Hour)}
+	svc := &accountService{conf: config.CreateConfig(), repo: repo, jwt: session.NewJWTManager(session.NewEphemeralSigningKeys(), "b", time.Minute, time.Hour)}
 
 	got, err := svc.GetUserByEmail("missing@example.com")
 	assert.Error(t, err)
@@ -820,44 +1355,42 @@ func TestAccountService_GetUserByEmail_NotFound(t *testing.T) {
 	assert.True(t, notFound)
 }
 
-// ─── ValidateRefreshToken ────────────────────────────────────────────────────
-
-func TestAccountService_ValidateRefreshToken_InvalidClaims(t *testing.T) {
-	repo := newFakeAccountRepo()
-	conf := config.CreateConfig()
-	jwtManager := session.NewJWTManager("access", "refresh", time.Minute, time.Hour)
+// ─── RefreshToken ─────────────────────────────────────────────────────────────
 
-	user := &models.User{Id: uuid.New(), Email: "u@example.com", Verified: true}
-	repo.usersByEmail[user.Email] = user
-	repo.usersByID[user.Id] = user
+func TestAccountService_RefreshToken_InvalidSignature(t *testing.T) {
+	repo, user, svc := newSessionTestService(t)
+	loginTestUser(t, svc)
 
 	// Token firmado con clave distinta → inválido
-	token, err := session.NewJWTManager("other", "other", time.Minute, time.Hour).GenerateRefreshToken(user.Id.String(), user.Email, false)
+	token, err := session.NewJWTManager(session.NewEphemeralSigningKeys(), "other", time.Minute, time.Hour).GenerateRefreshToken(user.Id.String(), user.Email, false, repo.onlySession(t).Id.String())
 	require.NoError(t, err)
 
-	svc := &accountService{conf: conf, repo: repo, jwt: jwtManager}
-	err = svc.ValidateRefreshToken(token, user.Email)
+	_, _, err = svc.RefreshToken(token, user.Email, SessionDevice{})
 	assert.Error(t, err)
 	_, invalid := err.(*AccountSessionInvalid)
 	assert.True(t, invalid)
+	assert.Nil(t, repo.onlySession(t).RevokedAt, "forged tokens must not revoke the session")
 }
 
-func TestAccountService_ValidateRefreshToken_UserNotInDB(t *testing.T) {
-	repo := newFakeAccountRepo()
-	conf := config.CreateConfig()
-	jwtManager := session.NewJWTManager("access", "refresh", time.Minute, time.Hour)
+func TestAccountService_RefreshToken_OtherUsersToken(t *testing.T) {
+	repo, user, svc := newSessionTestService(t)
+	loginTestUser(t, svc)
 
-	knownID := uuid.New()
-	user := &models.User{Id: knownID, Email: "u@example.com"}
-	repo.usersByEmail[user.Email] = user
-	// No lo añadimos a usersByID → GetAccountById fallará
+	token, err := svc.jwt.GenerateRefreshToken(uuid.NewString(), user.Email, false, repo.onlySession(t).Id.String())
+	require.NoError(t, err)
+
+	_, _, err = svc.RefreshToken(token, user.Email, SessionDevice{})
+	_, invalid := err.(*AccountSessionInvalid)
+	assert.True(t, invalid)
+}
+
+func TestAccountService_RefreshToken_UnknownSession(t *testing.T) {
+	_, user, svc := newSessionTestService(t)
 
-	token, err := jwtManager.GenerateRefreshToken(knownID.String(), user.Email, false)
+	token, err := svc.jwt.GenerateRefreshToken(user.Id.String(), user.Email, false, uuid.NewString())
 	require.NoError(t, err)
 
-	svc := &accountService{conf: conf, repo: repo, jwt: jwtManager}
-	err = svc.ValidateRefreshToken(token, user.Email)
-	assert.Error(t, err)
+	_, _, err = svc.RefreshToken(token, user.Email, SessionDevice{})
 	_, invalid := err.(*AccountSessionInvalid)
 	assert.True(t, invalid)
 }
@@ -871,7 +1404,7 @@ func TestAccountService_VerifyAccount_Success(t *testing.T) {
 	repo.usersByEmail[user.Email] = user
 	repo.usersByID[user.Id] = user
 
-	svc := &accountService{conf: conf, repo: repo, jwt: session.NewJWTManager("a", "b", time.Minute, time.Hour)}
+	svc := &accountService{conf: conf, repo: repo, jwt: session.NewJWTManager(session.NewEphemeralSigningKeys(), "b", time.Minute, time.Hour)}
 	verified, err := svc.VerifyAccount(user.Email, "code")
 	assert.NoError(t, err)
 	assert.True(t, verified)
@@ -879,7 +1412,7 @@ func TestAccountService_VerifyAccount_Success(t *testing.T) {
 
 func TestAccountService_VerifyAccount_NotFound(t *testing.T) {
 	repo := newFakeAccountRepo()
-	svc := &accountService{conf: config.CreateConfig(), repo: repo, jwt: session.NewJWTManager("a", "b", time.Minute, time.Hour)}
+	svc := &accountService{conf: config.CreateConfig(), repo: repo, jwt: session.NewJWTManager(session.NewEphemeralSigningKeys(), "b", time.Minute, time.Hour)}
 
 	_, err := svc.VerifyAccount("missing@example.com", "code")
 	assert.Error(t, err)
@@ -895,23 +1428,19 @@ func TestAccountService_UpdateAdminStatus_RepoError(t *testing.T) {
 	user := &models.User{Id: uuid.New(), Email: "u@example.com"}
 	repo.usersByID[user.Id] = user
 
-	svc := &accountService{conf: config.CreateConfig(), repo: repo, jwt: session.NewJWTManager("a", "b", time.Minute, time.Hour)}
+	svc := &accountService{conf: config.CreateConfig(), repo: repo, jwt: session.NewJWTManager(session.NewEphemeralSigningKeys(), "b", time.Minute, time.Hour)}
 	err := svc.UpdateAdminStatus(user.Id.String(), true)
 	assert.Error(t, err)
 }
 
 // ─── RefreshToken ─────────────────────────────────────────────────────────────
 
-func TestAccountService_RefreshToken_UpdateRefreshError(t *testing.T) {
-	repo := newFakeAccountRepo()
-	conf := config.CreateConfig()
-	user := &models.User{Id: uuid.New(), Email: "u@example.com", Verified: true}
-	repo.usersByEmail[user.Email] = user
-	repo.usersByID[user.Id] = user
-	repo.updateRefreshErr = errors.New("boom")
+func TestAccountService_RefreshToken_RotateError(t *testing.T) {
+	repo, user, svc := newSessionTestService(t)
+	refreshToken := loginTestUser(t, svc)
+	repo.rotateErr = errors.New("boom")
 
-	svc := &accountService{conf: conf, repo: repo, jwt: session.NewJWTManager("a", "b", time.Minute, time.Hour)}
-	_, _, err := svc.RefreshToken(user.Email)
+	_, _, err := svc.RefreshToken(refreshToken, user.Email, SessionDevice{})
 	assert.Error(t, err)
 	_, failed := err.(*AccountFailedToCreateTokenError)
 	assert.True(t, failed)
@@ -919,9 +1448,9 @@ func TestAccountService_RefreshToken_UpdateRefreshError(t *testing.T) {
 
 func TestAccountService_RefreshToken_UserNotFound(t *testing.T) {
 	repo := newFakeAccountRepo()
-	svc := &accountService{conf: config.CreateConfig(), repo: repo, jwt: session.NewJWTManager("a", "b", time.Minute, time.Hour)}
+	svc := &accountService{conf: config.CreateConfig(), repo: repo, jwt: session.NewJWTManager(session.NewEphemeralSigningKeys(), "b", time.Minute, time.Hour)}
 
-	_, _, err := svc.RefreshToken("missing@example.com")
+	_, _, err := svc.RefreshToken("token", "missing@example.com", SessionDevice{})
 	assert.Error(t, err)
 	_, notFound := err.(*AccountNotFoundError)
 	assert.True(t, notFound)
@@ -932,7 +1461,7 @@ func TestAccountService_RefreshToken_UserNotFound(t *testing.T) {
 func TestAccountService_CreateAccount_Success(t *testing.T) {
 	repo := newFakeAccountRepo()
 	conf := config.CreateConfig()
-	svc := &accountService{conf: conf, repo: repo, jwt: session.NewJWTManager("a", "b", time.Minute, time.Hour)}
+	svc := &accountService{conf: conf, repo: repo, jwt: session.NewJWTManager(session.NewEphemeralSigningKeys(), "b", time.Minute, time.Hour)}
 
 	user, code, err := svc.CreateAccount("new@example.com", "Password1", false)
 	assert.NoError(t, err)
@@ -950,7 +1479,7 @@ func TestAccountService_ForgotPassword_InvalidateError_Continues(t *testing.T) {
 	repo.usersByID[user.Id] = user
 	repo.invalidateErr = errors.New("warning-only")
 
-	svc := &accountService{conf: conf, repo: repo, jwt: session.NewJWTManager("a", "b", time.Minute, time.Hour)}
+	svc := &accountService{conf: conf, repo: repo, jwt: session.NewJWTManager(session.NewEphemeralSigningKeys(), "b", time.Minute, time.Hour)}
 	otp, err := svc.ForgotPassword(user.Email)
 	assert.NoError(t, err)
 	assert.Len(t, otp, 6)
@@ -960,7 +1489,7 @@ func TestAccountService_ForgotPassword_InvalidateError_Continues(t *testing.T) {
 
 func TestAccountService_VerifyPasswordResetCode_NotFound(t *testing.T) {
 	repo := newFakeAccountRepo()
-	svc := &accountService{conf: config.CreateConfig(), repo: repo, jwt: session.NewJWTManager("a", "b", time.Minute, time.Hour)}
+	svc := &accountService{conf: config.CreateConfig(), repo: repo, jwt: session.NewJWTManager(session.NewEphemeralSigningKeys(), "b", time.Minute, time.Hour)}
 
 	_, err := svc.VerifyPasswordResetCode("missing@example.com", "123456")
 	assert.Error(t, err)
@@ -981,7 +1510,7 @@ func TestAccountService_VerifyPasswordResetCode_AlreadyVerified(t *testing.T) {
 		OTPExpiresAt: time.Now().Add(time.Minute),
 	}
 
-	svc := &accountService{conf: conf, repo: repo, jwt: session.NewJWTManager("a", "b", time.Minute, time.Hour)}
+	svc := &accountService{conf: conf, repo: repo, jwt: session.NewJWTManager(session.NewEphemeralSigningKeys(), "b", time.Minute, time.Hour)}
 	_, err := svc.VerifyPasswordResetCode(user.Email, "123456")
 	assert.Error(t, err)
 	_, notFound := err.(*PasswordResetNotFoundError)
@@ -998,7 +1527,7 @@ func TestAccountService_VerifyAccount_GenericRepoError(t *testing.T) {
 	// Forzamos un error genérico que no sea ni expirado ni inválido
 	repo.verifyErr = errors.New("unexpected database crash")
 
-	svc := &accountService{conf: conf, repo: repo, jwt: session.NewJWTManager("a", "b", time.Minute, time.Hour)}
+	svc := &accountService{conf: conf, repo: repo, jwt: session.NewJWTManager(session.NewEphemeralSigningKeys(), "b", time.Minute, time.Hour)}
 	_, err := svc.VerifyAccount(user.Email, "code")
 	assert.Error(t, err)
 	assert.EqualError(t, err, "unexpected database crash")
@@ -1009,7 +1538,7 @@ func TestAccountService_UpdateAdminStatus_Success(t *testing.T) {
 	user := &models.User{Id: uuid.New(), Email: "admin@example.com", IsAdmin: false}
 	repo.usersByID[user.Id] = user
 
-	svc := &accountService{conf: config.CreateConfig(), repo: repo, jwt: session.NewJWTManager("a", "b", time.Minute, time.Hour)}
+	svc := &accountService{conf: config.CreateConfig(), repo: repo, jwt: session.NewJWTManager(session.NewEphemeralSigningKeys(), "b", time.Minute, time.Hour)}
 	err := svc.UpdateAdminStatus(user.Id.String(), true)
 	assert.NoError(t, err)
 	assert.True(t, user.IsAdmin)
@@ -1020,14 +1549,14 @@ func TestAccountService_UpdateAdminStatus_UserNotFound(t *testing.T) {
 	// UUID válido pero no existe en el fake → UpdateAdminStatus retorna nil (no verifica existencia)
 	validUUID := uuid.New().String()
 
-	svc := &accountService{conf: config.CreateConfig(), repo: repo, jwt: session.NewJWTManager("a", "b", time.Minute, time.Hour)}
+	svc := &accountService{conf: config.CreateConfig(), repo: repo, jwt: session.NewJWTManager(session.NewEphemeralSigningKeys(), "b", time.Minute, time.Hour)}
 	err := svc.UpdateAdminStatus(validUUID, true)
 	assert.NoError(t, err)
 }
 
 func TestAccountService_VerifyPasswordResetCode_UserNotFound(t *testing.T) {
 	repo := newFakeAccountRepo()
-	svc := &accountService{conf: config.CreateConfig(), repo: repo, jwt: session.NewJWTManager("a", "b", time.Minute, time.Hour)}
+	svc := &accountService{conf: config.CreateConfig(), repo: repo, jwt: session.NewJWTManager(session.NewEphemeralSigningKeys(), "b", time.Minute, time.Hour)}
 
 	_, err := svc.VerifyPasswordResetCode("missing@example.com", "123456")
 	assert.Error(t, err)
@@ -1042,7 +1571,7 @@ func TestAccountService_VerifyPasswordResetCode_ActiveResetNotFound(t *testing.T
 	repo.usersByID[user.Id] = user
 	// No le seteamos repo.activeReset, simulando que no hay pedido de reset activo
 
-	svc := &accountService{conf: config.CreateConfig(), repo: repo, jwt: session.NewJWTManager("a", "b", time.Minute, time.Hour)}
+	svc := &accountService{conf: config.CreateConfig(), repo: repo, jwt: session.NewJWTManager(session.NewEphemeralSigningKeys(), "b", time.Minute, time.Hour)}
 	_, err := svc.VerifyPasswordResetCode(user.Email, "123456")
 	assert.Error(t, err)
 	_, notFound := err.(*PasswordResetNotFoundError)
@@ -1063,7 +1592,7 @@ func TestAccountService_VerifyPasswordResetCode_RepoIncAttemptsError(t *testing.
 	// Forzamos error al intentar incrementar intentos en la BD
 	repo.incAttemptsErr = errors.New("db error")
 
-	svc := &accountService{conf: config.CreateConfig(), repo: repo, jwt: session.NewJWTManager("a", "b", time.Minute, time.Hour)}
+	svc := &accountService{conf: config.CreateConfig(), repo: repo, jwt: session.NewJWTManager(session.NewEphemeralSigningKeys(), "b", time.Minute, time.Hour)}
 	_, err := svc.VerifyPasswordResetCode(user.Email, "wrongcode")
 	assert.Error(t, err)
 	_, maxed := err.(*PasswordResetMaxAttemptsError)
@@ -1086,9 +1615,92 @@ func TestAccountService_VerifyPasswordResetCode_RepoMarkVerifiedError(t *testing
 	// El código es correcto, pero falla al guardar el estado verificado en BD
 	repo.markResetErr = errors.New("db error")
 
-	svc := &accountService{conf: config.CreateConfig(), repo: repo, jwt: session.NewJWTManager("a", "b", time.Minute, time.Hour)}
+	svc := &accountService{conf: config.CreateConfig(), repo: repo, jwt: session.NewJWTManager(session.NewEphemeralSigningKeys(), "b", time.Minute, time.Hour)}
 	_, err := svc.VerifyPasswordResetCode(user.Email, "123456")
 	assert.Error(t, err)
 	_, failed := err.(*AccountFailedToCreateTokenError)
 	assert.True(t, failed)
 }
+
+func failLogins(svc *accountService, times int) error {
+	var err error
+	for i := 0; i < times; i++ {
+		_, _, _, err = svc.LoginAccount("user@example.com", "WrongPassword1", false, SessionDevice{})
+	}
+	return err
+}
+
+func TestAccountService_LoginAccount_LocksAfterThreshold(t *testing.T) {
+	_, user, svc := newSessionTestService(t)
+	svc.conf.RateLimit.LockoutThreshold = 3
+	svc.conf.RateLimit.LockoutDuration = time.Minute * 15
+
+	err := failLogins(svc, 2)
+	_, notFound := err.(*AccountNotFoundError)
+	assert.True(t, notFound)
+
+	err = failLogins(svc, 1)
+	locked, ok := err.(*AccountLockedError)
+	require.True(t, ok, "Expected AccountLockedError")
+	assert.Equal(t, time.Minute*15, locked.RetryAfter)
+	assert.NotEmpty(t, locked.UnlockToken)
+	assert.Equal(t, 1, user.LockoutCount)
+
+	// The correct password is rejected while locked and no new unlock email is triggered.
+	_, _, _, err = svc.LoginAccount("user@example.com", "Password1", false, SessionDevice{})
+	locked, ok = err.(*AccountLockedError)
+	require.True(t, ok, "Expected AccountLockedError")
+	assert.Empty(t, locked.UnlockToken)
+}
+
+func TestAccountService_LoginAccount_LockoutEscalates(t *testing.T) {
+	_, user, svc := newSessionTestService(t)
+	svc.conf.RateLimit.LockoutThreshold = 1
+	svc.conf.RateLimit.LockoutDuration = time.Minute * 15
+	svc.conf.RateLimit.LockoutMaxDuration = time.Hour
+
+	user.LockoutCount = 1
+	err := failLogins(svc, 1)
+	locked, ok := err.(*AccountLockedError)
+	require.True(t, ok, "Expected AccountLockedError")
+	assert.Equal(t, time.Minute*30, locked.RetryAfter)
+
+	user.LockedUntil = nil
+	user.LockoutCount = 5
+	err = failLogins(svc, 1)
+	locked, ok = err.(*AccountLockedError)
+	require.True(t, ok, "Expected AccountLockedError")
+	assert.Equal(t, time.Hour, locked.RetryAfter)
+}
+
+func TestAccountService_LoginAccount_SuccessResetsFailedAttempts(t *testing.T) {
+	_, user, svc := newSessionTestService(t)
+	svc.conf.RateLimit.LockoutThreshold = 3
+
+	_ = failLogins(svc, 2)
+	assert.Equal(t, 2, user.FailedLoginAttempts)
+
+	loginTestUser(t, svc)
+	assert.Equal(t, 0, user.FailedLoginAttempts)
+}
+
+func TestAccountService_UnlockAccount(t *testing.T) {
+	_, user, svc := newSessionTestService(t)
+	svc.conf.RateLimit.LockoutThreshold = 1
+
+	err := failLogins(svc, 1)
+	locked, ok := err.(*AccountLockedError)
+	require.True(t, ok, "Expected AccountLockedError")
+
+	err = svc.UnlockAccount("not-the-token")
+	_, invalid := err.(*InvalidUnlockTokenError)
+	assert.True(t, invalid)
+
+	require.NoError(t, svc.UnlockAccount(locked.UnlockToken))
+	assert.False(t, user.IsLocked(time.Now()))
+	loginTestUser(t, svc)
+
+	err = svc.UnlockAccount(locked.UnlockToken)
+	_, invalid = err.(*InvalidUnlockTokenError)
+	assert.True(t, invalid, "Unlock tokens are single use")
+}
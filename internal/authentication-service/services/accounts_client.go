@@ -0,0 +1,27 @@
+package services
+
+import (
+	"github.com/FeedTheRealm-org/core-service/internal/service_clients"
+	"github.com/google/uuid"
+)
+
+type accountsClient struct {
+	accountService AccountService
+}
+
+// NewAccountsClient exposes the account service to the other services running in this process.
+func NewAccountsClient(accountService AccountService) service_clients.AccountsClient {
+	return &accountsClient{accountService: accountService}
+}
+
+func (c *accountsClient) GetAccountEmail(userId uuid.UUID) (string, error) {
+	user, err := c.accountService.GetUserById(userId)
+	if err != nil {
+		if _, ok := err.(*AccountNotFoundError); ok {
+			return "", service_clients.NewAccountNotFound("account not found")
+		}
+		return "", err
+	}
+
+	return user.Email, nil
+}
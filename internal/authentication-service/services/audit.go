@@ -0,0 +1,50 @@
+package services
+
+import (
+	"strings"
+	"time"
+
+	"github.com/FeedTheRealm-org/core-service/internal/authentication-service/models"
+	"github.com/FeedTheRealm-org/core-service/internal/authentication-service/repositories"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/logger"
+)
+
+type InvalidAuditEventError struct {
+	Msg string
+}
+
+func (e *InvalidAuditEventError) Error() string {
+	return e.Msg
+}
+
+type auditService struct {
+	repo repositories.AuditRepository
+}
+
+func NewAuditService(repo repositories.AuditRepository) AuditService {
+	return &auditService{repo: repo}
+}
+
+// RecordEvent appends the event to the audit log, events without a time are stamped now.
+func (s *auditService) RecordEvent(event *models.AuditEvent) error {
+	event.Action = strings.TrimSpace(event.Action)
+	if event.Action == "" {
+		return &InvalidAuditEventError{Msg: "Audit events need an action"}
+	}
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+
+	if err := s.repo.CreateAuditEvent(event); err != nil {
+		logger.Logger.Errorf("RecordEvent: failed to record action=%s by actor=%s: %v", event.Action, event.ActorId, err)
+		return err
+	}
+	return nil
+}
+
+func (s *auditService) ListEvents(filter repositories.AuditEventFilter) ([]models.AuditEvent, int64, error) {
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, 0, &InvalidAuditEventError{Msg: "The start of the time range has to be before its end"}
+	}
+	return s.repo.ListAuditEvents(filter)
+}
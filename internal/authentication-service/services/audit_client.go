@@ -0,0 +1,30 @@
+package services
+
+import (
+	"github.com/FeedTheRealm-org/core-service/internal/authentication-service/models"
+	"github.com/FeedTheRealm-org/core-service/internal/service_clients"
+	"gorm.io/datatypes"
+)
+
+type auditClient struct {
+	auditService AuditService
+}
+
+// NewAuditClient exposes the audit log to the other services running in this process.
+func NewAuditClient(auditService AuditService) service_clients.AuditClient {
+	return &auditClient{auditService: auditService}
+}
+
+func (c *auditClient) RecordEvent(event service_clients.AuditEvent) error {
+	return c.auditService.RecordEvent(&models.AuditEvent{
+		ActorId:    event.ActorId,
+		Action:     event.Action,
+		TargetType: event.TargetType,
+		TargetId:   event.TargetId,
+		Before:     datatypes.JSON(event.Before),
+		After:      datatypes.JSON(event.After),
+		RequestId:  event.RequestId,
+		IPAddress:  event.IPAddress,
+		OccurredAt: event.OccurredAt,
+	})
+}
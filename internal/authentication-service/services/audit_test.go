@@ -0,0 +1,62 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/FeedTheRealm-org/core-service/internal/authentication-service/models"
+	"github.com/FeedTheRealm-org/core-service/internal/authentication-service/repositories"
+	"github.com/FeedTheRealm-org/core-service/internal/service_clients"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeAuditRepository struct {
+	events []models.AuditEvent
+	filter repositories.AuditEventFilter
+}
+
+func (r *fakeAuditRepository) CreateAuditEvent(event *models.AuditEvent) error {
+	r.events = append(r.events, *event)
+	return nil
+}
+
+func (r *fakeAuditRepository) ListAuditEvents(filter repositories.AuditEventFilter) ([]models.AuditEvent, int64, error) {
+	r.filter = filter
+	return r.events, int64(len(r.events)), nil
+}
+
+func TestAuditClient_RecordEvent(t *testing.T) {
+	repo := &fakeAuditRepository{}
+	client := NewAuditClient(NewAuditService(repo))
+
+	assert.IsType(t, &InvalidAuditEventError{}, client.RecordEvent(service_clients.AuditEvent{Action: " "}))
+
+	actorId := uuid.New()
+	require.NoError(t, client.RecordEvent(service_clients.AuditEvent{
+		ActorId:    actorId,
+		Action:     "payments.payouts.approve",
+		TargetType: "payout",
+		TargetId:   "7",
+		After:      []byte(`{"status":"approved"}`),
+	}))
+	require.Len(t, repo.events, 1)
+	assert.Equal(t, actorId, repo.events[0].ActorId)
+	assert.JSONEq(t, `{"status":"approved"}`, string(repo.events[0].After))
+	assert.WithinDuration(t, time.Now(), repo.events[0].OccurredAt, time.Minute)
+}
+
+func TestAuditService_ListEvents(t *testing.T) {
+	repo := &fakeAuditRepository{}
+	svc := NewAuditService(repo)
+
+	from := time.Now()
+	to := from.Add(-time.Hour)
+	_, _, err := svc.ListEvents(repositories.AuditEventFilter{From: &from, To: &to, Limit: 10})
+	assert.IsType(t, &InvalidAuditEventError{}, err)
+
+	_, _, err = svc.ListEvents(repositories.AuditEventFilter{Action: "auth.*", Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, "auth.*", repo.filter.Action)
+}
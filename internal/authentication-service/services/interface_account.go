@@ -2,20 +2,49 @@ package services
 
 import (
 	"github.com/FeedTheRealm-org/core-service/internal/authentication-service/models"
+	"github.com/google/uuid"
 )
 
 type AccountService interface {
 	GetUserByEmail(email string) (*models.User, error)
+	GetUserById(id uuid.UUID) (*models.User, error)
 	CreateAccount(email string, password string, isAdmin bool) (*models.User, string, error)
-	LoginAccount(email string, password string, isAdminReq bool) (*models.User, string, string, error)
+	LoginAccount(email string, password string, isAdminReq bool, device SessionDevice) (*models.User, string, string, error)
 	ValidateAccessToken(token string) error
-	ValidateRefreshToken(token string, email string) error
 	VerifyAccount(email string, code string) (bool, error)
 	RefreshVerificationCode(email string) (string, error)
-	RefreshToken(email string) (string, string, error)
+	RefreshToken(token string, email string, device SessionDevice) (string, string, error)
 	ListAccounts(query string, verified *bool, offset int, limit int) ([]models.User, int64, error)
 	UpdateAdminStatus(id string, isAdmin bool) error
 	ForgotPassword(email string) (otpCode string, err error)
 	VerifyPasswordResetCode(email string, code string) (resetToken string, err error)
 	ResetPassword(resetToken string, newPassword string) error
+	Logout(userId uuid.UUID, sessionId uuid.UUID) error
+	ListSessions(userId uuid.UUID) ([]models.Session, error)
+	RevokeSession(userId uuid.UUID, sessionId uuid.UUID) error
+	RevokeAllSessions(id string) error
+	UnlockAccount(token string) error
+	CompleteTwoFactorLogin(challengeToken string, code string, device SessionDevice) (*models.User, string, string, error)
+	SetupTwoFactor(userId uuid.UUID) (secret string, otpauthURI string, err error)
+	ConfirmTwoFactor(userId uuid.UUID, code string) (recoveryCodes []string, err error)
+	TwoFactorSetupRequired(user *models.User) bool
+	ListOIDCProviders() []string
+	StartOIDCLogin(provider string, linkUserId *uuid.UUID) (authorizationURL string, err error)
+	CompleteOIDCLogin(provider string, code string, state string, device SessionDevice) (*models.User, string, string, error)
+	CompleteOIDCLink(userId uuid.UUID, provider string, code string, state string) (*models.Identity, error)
+	ListIdentities(userId uuid.UUID) ([]models.Identity, error)
+	UnlinkIdentity(userId uuid.UUID, provider string) error
+	RequestAccountDeletion(userId uuid.UUID, password string) (user *models.User, cancelToken string, err error)
+	CancelAccountDeletion(token string) error
+	ExportUserData(userId uuid.UUID) ([]byte, error)
+	PurgeDeletedAccounts() (int, error)
+	StartDeletionPurge()
+	ChangePassword(userId uuid.UUID, sessionId uuid.UUID, currentPassword string, newPassword string) error
+	RequestEmailChange(userId uuid.UUID, newEmail string, password string) (*models.User, *models.EmailChange, error)
+	ConfirmEmailChange(userId uuid.UUID, code string, device SessionDevice) (*models.User, string, string, error)
+	ListRoles() ([]models.Role, error)
+	SaveRole(manager RoleManager, name string, description string, permissions []string) (*models.Role, error)
+	DeleteRole(manager RoleManager, name string) error
+	GetUserRoles(id string) ([]string, error)
+	SetUserRoles(manager RoleManager, id string, roles []string) error
 }
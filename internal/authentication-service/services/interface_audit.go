@@ -0,0 +1,11 @@
+package services
+
+import (
+	"github.com/FeedTheRealm-org/core-service/internal/authentication-service/models"
+	"github.com/FeedTheRealm-org/core-service/internal/authentication-service/repositories"
+)
+
+type AuditService interface {
+	RecordEvent(event *models.AuditEvent) error
+	ListEvents(filter repositories.AuditEventFilter) ([]models.AuditEvent, int64, error)
+}
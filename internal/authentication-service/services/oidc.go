@@ -0,0 +1,294 @@
+package services
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/FeedTheRealm-org/core-service/internal/authentication-service/models"
+	"github.com/FeedTheRealm-org/core-service/internal/authentication-service/repositories"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/logger"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/oidc_login"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/oidc_validation"
+	"github.com/google/uuid"
+)
+
+type UnknownOIDCProviderError struct{}
+
+func (e *UnknownOIDCProviderError) Error() string {
+	return "Unknown sign-in provider"
+}
+
+type InvalidOIDCStateError struct{}
+
+func (e *InvalidOIDCStateError) Error() string {
+	return "Sign-in request is invalid or has expired"
+}
+
+type OIDCExchangeFailedError struct{}
+
+func (e *OIDCExchangeFailedError) Error() string {
+	return "Sign-in with the provider failed"
+}
+
+type OIDCEmailRequiredError struct{}
+
+func (e *OIDCEmailRequiredError) Error() string {
+	return "The provider did not share an email address"
+}
+
+// OIDCEmailInUseError is returned instead of linking by email, the owner of the account
+// has to log in and link the provider so nobody takes over an account through a provider.
+type OIDCEmailInUseError struct{}
+
+func (e *OIDCEmailInUseError) Error() string {
+	return "An account with this email already exists, log in and link the provider instead"
+}
+
+// OIDCEmailNotVerifiedError is returned when the provider did not assert the email,
+// the verification code has to be emailed to the user like on a regular signup.
+type OIDCEmailNotVerifiedError struct {
+	Email            string
+	VerificationCode string
+}
+
+func (e *OIDCEmailNotVerifiedError) Error() string {
+	return "Account is not verified, a verification code was sent to the email"
+}
+
+type IdentityAlreadyLinkedError struct{}
+
+func (e *IdentityAlreadyLinkedError) Error() string {
+	return "The provider account is already linked"
+}
+
+type IdentityNotFoundError struct{}
+
+func (e *IdentityNotFoundError) Error() string {
+	return "The provider is not linked to the account"
+}
+
+type LastLoginMethodError struct{}
+
+func (e *LastLoginMethodError) Error() string {
+	return "Cannot unlink the only way to log in to the account"
+}
+
+func (s *accountService) ListOIDCProviders() []string {
+	names := make([]string, 0, len(s.oidcProviders))
+	for name := range s.oidcProviders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// StartOIDCLogin returns the provider URL the user is redirected to. linkUserId is set
+// when a logged in user links the provider to their account instead of signing in.
+func (s *accountService) StartOIDCLogin(providerName string, linkUserId *uuid.UUID) (string, error) {
+	provider, ok := s.oidcProviders[providerName]
+	if !ok {
+		return "", &UnknownOIDCProviderError{}
+	}
+
+	var tokens [3]string
+	for i := range tokens {
+		token, err := oidc_login.RandomToken()
+		if err != nil {
+			return "", &AccountFailedToCreateTokenError{}
+		}
+		tokens[i] = token
+	}
+	state, nonce, codeVerifier := tokens[0], tokens[1], tokens[2]
+
+	loginState := &models.OIDCLoginState{
+		StateHash:    hashToken(state),
+		Provider:     providerName,
+		CodeVerifier: codeVerifier,
+		Nonce:        nonce,
+		LinkUserId:   linkUserId,
+		ExpiresAt:    time.Now().Add(s.conf.OIDC.StateTTL),
+	}
+	if err := s.repo.CreateOIDCLoginState(loginState); err != nil {
+		return "", err
+	}
+
+	return provider.AuthorizationURL(state, oidc_login.CodeChallenge(codeVerifier), nonce), nil
+}
+
+// redeemOIDCCode consumes the state of the authorization request and exchanges the code for the ID token claims.
+func (s *accountService) redeemOIDCCode(providerName string, code string, state string) (*models.OIDCLoginState, *oidc_validation.IDTokenClaims, error) {
+	provider, ok := s.oidcProviders[providerName]
+	if !ok {
+		return nil, nil, &UnknownOIDCProviderError{}
+	}
+
+	loginState, err := s.repo.ConsumeOIDCLoginState(hashToken(state), providerName, time.Now())
+	if err != nil {
+		return nil, nil, &InvalidOIDCStateError{}
+	}
+
+	claims, err := provider.Exchange(code, loginState.CodeVerifier)
+	if err != nil {
+		logger.Logger.Warnf("redeemOIDCCode: exchange with provider=%s failed: %v", providerName, err)
+		return nil, nil, &OIDCExchangeFailedError{}
+	}
+	if claims.Nonce != loginState.Nonce {
+		return nil, nil, &OIDCExchangeFailedError{}
+	}
+
+	return loginState, claims, nil
+}
+
+// CompleteOIDCLogin signs in the user linked to the provider account, creating the account on
+// the first sign-in. The email is verified when the provider asserts it.
+func (s *accountService) CompleteOIDCLogin(providerName string, code string, state string, device SessionDevice) (*models.User, string, string, error) {
+	loginState, claims, err := s.redeemOIDCCode(providerName, code, state)
+	if err != nil {
+		return nil, "", "", err
+	}
+	if loginState.LinkUserId != nil {
+		return nil, "", "", &InvalidOIDCStateError{}
+	}
+
+	var user *models.User
+	identity, err := s.repo.GetIdentity(providerName, claims.Subject)
+	switch err.(type) {
+	case nil:
+		user, err = s.repo.GetAccountById(identity.UserId)
+		if err != nil {
+			return nil, "", "", &AccountNotFoundError{}
+		}
+	case *repositories.IdentityNotFoundError:
+		user, err = s.createOIDCAccount(providerName, claims)
+		if err != nil {
+			return nil, "", "", err
+		}
+	default:
+		return nil, "", "", err
+	}
+
+	if !user.Verified && bool(claims.EmailVerified) && strings.EqualFold(user.Email, claims.Email) {
+		if err := s.repo.MarkAccountVerified(user.Id); err != nil {
+			return nil, "", "", err
+		}
+		user.Verified = true
+	}
+
+	if !user.Verified {
+		verificationCode, err := s.RefreshVerificationCode(user.Email)
+		if err != nil {
+			return nil, "", "", err
+		}
+		return nil, "", "", &OIDCEmailNotVerifiedError{Email: user.Email, VerificationCode: verificationCode}
+	}
+
+	now := time.Now()
+	if user.IsLocked(now) {
+		return nil, "", "", &AccountLockedError{RetryAfter: user.LockedUntil.Sub(now)}
+	}
+
+	if user.TOTPEnabled {
+		return nil, "", "", s.startTwoFactorChallenge(user)
+	}
+
+	s.clearFailedLogins(user)
+
+	accessToken, refreshToken, err := s.startSession(user, device)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	return user, accessToken, refreshToken, nil
+}
+
+// createOIDCAccount signs up a user from the ID token, the account has no password until one is set through a reset.
+func (s *accountService) createOIDCAccount(providerName string, claims *oidc_validation.IDTokenClaims) (*models.User, error) {
+	email := strings.ToLower(strings.TrimSpace(claims.Email))
+	if email == "" {
+		return nil, &OIDCEmailRequiredError{}
+	}
+
+	if _, err := s.repo.GetAccountByEmail(email); err == nil {
+		return nil, &OIDCEmailInUseError{}
+	}
+
+	user := &models.User{
+		Email:    email,
+		Verified: bool(claims.EmailVerified),
+	}
+	identity := &models.Identity{
+		Provider: providerName,
+		Subject:  claims.Subject,
+		Email:    email,
+	}
+
+	if err := s.repo.CreateAccountWithIdentity(user, identity, s.newVerificationCode()); err != nil {
+		if _, ok := err.(*repositories.IdentityAlreadyLinkedError); ok {
+			return nil, &IdentityAlreadyLinkedError{}
+		}
+		return nil, &AccountFailedToCreateError{}
+	}
+
+	logger.Logger.Infof("Account user=%s created through provider=%s", user.Id, providerName)
+	return user, nil
+}
+
+// CompleteOIDCLink links the provider account to the logged in user that started the request.
+func (s *accountService) CompleteOIDCLink(userId uuid.UUID, providerName string, code string, state string) (*models.Identity, error) {
+	loginState, claims, err := s.redeemOIDCCode(providerName, code, state)
+	if err != nil {
+		return nil, err
+	}
+	if loginState.LinkUserId == nil || *loginState.LinkUserId != userId {
+		return nil, &InvalidOIDCStateError{}
+	}
+
+	identity := &models.Identity{
+		UserId:   userId,
+		Provider: providerName,
+		Subject:  claims.Subject,
+		Email:    strings.ToLower(strings.TrimSpace(claims.Email)),
+	}
+	if err := s.repo.CreateIdentity(identity); err != nil {
+		if _, ok := err.(*repositories.IdentityAlreadyLinkedError); ok {
+			return nil, &IdentityAlreadyLinkedError{}
+		}
+		return nil, err
+	}
+
+	logger.Logger.Infof("Provider=%s linked to user=%s", providerName, userId)
+	return identity, nil
+}
+
+func (s *accountService) ListIdentities(userId uuid.UUID) ([]models.Identity, error) {
+	return s.repo.ListIdentities(userId)
+}
+
+// UnlinkIdentity removes a linked provider, unless the account has no password and it is the last one.
+func (s *accountService) UnlinkIdentity(userId uuid.UUID, providerName string) error {
+	user, err := s.repo.GetAccountById(userId)
+	if err != nil {
+		return &AccountNotFoundError{}
+	}
+
+	if user.Password == "" {
+		identities, err := s.repo.ListIdentities(userId)
+		if err != nil {
+			return err
+		}
+		if len(identities) <= 1 {
+			return &LastLoginMethodError{}
+		}
+	}
+
+	if err := s.repo.DeleteIdentity(userId, providerName); err != nil {
+		if _, ok := err.(*repositories.IdentityNotFoundError); ok {
+			return &IdentityNotFoundError{}
+		}
+		return err
+	}
+
+	logger.Logger.Infof("Provider=%s unlinked from user=%s", providerName, userId)
+	return nil
+}
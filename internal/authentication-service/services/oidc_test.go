@@ -0,0 +1,207 @@
+package services
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/FeedTheRealm-org/core-service/internal/authentication-service/models"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/oidc_login"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/oidc_validation"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeOIDCProvider hands out the configured claims with the nonce of the last authorization request.
+type fakeOIDCProvider struct {
+	subject       string
+	email         string
+	emailVerified bool
+	exchangeErr   error
+
+	nonce        string
+	challenge    string
+	codeVerifier string
+}
+
+func (p *fakeOIDCProvider) Name() string {
+	return "google"
+}
+
+func (p *fakeOIDCProvider) AuthorizationURL(state string, codeChallenge string, nonce string) string {
+	p.nonce = nonce
+	p.challenge = codeChallenge
+	return "https://accounts.example.com/auth?" + url.Values{"state": {state}}.Encode()
+}
+
+func (p *fakeOIDCProvider) Exchange(code string, codeVerifier string) (*oidc_validation.IDTokenClaims, error) {
+	if p.exchangeErr != nil {
+		return nil, p.exchangeErr
+	}
+	p.codeVerifier = codeVerifier
+	return &oidc_validation.IDTokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{Subject: p.subject},
+		Email:            p.email,
+		EmailVerified:    oidc_validation.FlexibleBool(p.emailVerified),
+		Nonce:            p.nonce,
+	}, nil
+}
+
+func newOIDCTestService(t *testing.T) (*fakeAccountRepo, *models.User, *accountService, *fakeOIDCProvider) {
+	t.Helper()
+	repo, user, svc := newSessionTestService(t)
+	provider := &fakeOIDCProvider{subject: "google-123", email: "Player@Example.com", emailVerified: true}
+	svc.oidcProviders = map[string]oidc_login.Provider{"google": provider}
+	return repo, user, svc, provider
+}
+
+func startOIDCState(t *testing.T, svc *accountService, linkUserId *uuid.UUID) string {
+	t.Helper()
+	authorizationURL, err := svc.StartOIDCLogin("google", linkUserId)
+	require.NoError(t, err)
+	parsed, err := url.Parse(authorizationURL)
+	require.NoError(t, err)
+	return parsed.Query().Get("state")
+}
+
+func TestAccountService_StartOIDCLogin_UnknownProvider(t *testing.T) {
+	_, _, svc, _ := newOIDCTestService(t)
+
+	_, err := svc.StartOIDCLogin("steam", nil)
+	assert.IsType(t, &UnknownOIDCProviderError{}, err)
+	assert.Equal(t, []string{"google"}, svc.ListOIDCProviders())
+}
+
+func TestAccountService_CompleteOIDCLogin_CreatesVerifiedAccount(t *testing.T) {
+	repo, _, svc, provider := newOIDCTestService(t)
+	state := startOIDCState(t, svc, nil)
+
+	user, accessToken, refreshToken, err := svc.CompleteOIDCLogin("google", "code", state, SessionDevice{})
+	require.NoError(t, err)
+	assert.NotEmpty(t, accessToken)
+	assert.NotEmpty(t, refreshToken)
+	assert.Equal(t, "player@example.com", user.Email)
+	assert.True(t, user.Verified)
+	assert.Empty(t, user.Password)
+	assert.Equal(t, oidc_login.CodeChallenge(provider.codeVerifier), provider.challenge)
+
+	identities, err := repo.ListIdentities(user.Id)
+	require.NoError(t, err)
+	require.Len(t, identities, 1)
+	assert.Equal(t, "google-123", identities[0].Subject)
+
+	// The same provider account signs in to the same user.
+	again, _, _, err := svc.CompleteOIDCLogin("google", "code", startOIDCState(t, svc, nil), SessionDevice{})
+	require.NoError(t, err)
+	assert.Equal(t, user.Id, again.Id)
+}
+
+func TestAccountService_CompleteOIDCLogin_StateIsSingleUse(t *testing.T) {
+	_, _, svc, _ := newOIDCTestService(t)
+	state := startOIDCState(t, svc, nil)
+
+	_, _, _, err := svc.CompleteOIDCLogin("google", "code", state, SessionDevice{})
+	require.NoError(t, err)
+
+	_, _, _, err = svc.CompleteOIDCLogin("google", "code", state, SessionDevice{})
+	assert.IsType(t, &InvalidOIDCStateError{}, err)
+}
+
+func TestAccountService_CompleteOIDCLogin_NonceMismatch(t *testing.T) {
+	_, _, svc, provider := newOIDCTestService(t)
+	state := startOIDCState(t, svc, nil)
+	provider.nonce = "other"
+
+	_, _, _, err := svc.CompleteOIDCLogin("google", "code", state, SessionDevice{})
+	assert.IsType(t, &OIDCExchangeFailedError{}, err)
+}
+
+func TestAccountService_CompleteOIDCLogin_ExchangeFails(t *testing.T) {
+	_, _, svc, provider := newOIDCTestService(t)
+	provider.exchangeErr = errors.New("invalid_grant")
+
+	_, _, _, err := svc.CompleteOIDCLogin("google", "code", startOIDCState(t, svc, nil), SessionDevice{})
+	assert.IsType(t, &OIDCExchangeFailedError{}, err)
+}
+
+func TestAccountService_CompleteOIDCLogin_DoesNotLinkExistingEmail(t *testing.T) {
+	repo, user, svc, provider := newOIDCTestService(t)
+	provider.email = user.Email
+
+	_, _, _, err := svc.CompleteOIDCLogin("google", "code", startOIDCState(t, svc, nil), SessionDevice{})
+	assert.IsType(t, &OIDCEmailInUseError{}, err)
+	assert.Empty(t, repo.identities)
+}
+
+func TestAccountService_CompleteOIDCLogin_UnverifiedEmail(t *testing.T) {
+	repo, _, svc, provider := newOIDCTestService(t)
+	provider.emailVerified = false
+
+	_, _, _, err := svc.CompleteOIDCLogin("google", "code", startOIDCState(t, svc, nil), SessionDevice{})
+	var notVerified *OIDCEmailNotVerifiedError
+	require.ErrorAs(t, err, &notVerified)
+	assert.Equal(t, "player@example.com", notVerified.Email)
+	assert.NotEmpty(t, notVerified.VerificationCode)
+	assert.False(t, repo.usersByEmail["player@example.com"].Verified)
+	assert.Empty(t, repo.sessions)
+
+	// A later sign-in where the provider asserts the email verifies the account.
+	provider.emailVerified = true
+	user, _, _, err := svc.CompleteOIDCLogin("google", "code", startOIDCState(t, svc, nil), SessionDevice{})
+	require.NoError(t, err)
+	assert.True(t, user.Verified)
+}
+
+func TestAccountService_CompleteOIDCLogin_TwoFactor(t *testing.T) {
+	repo, user, svc, _ := newOIDCTestService(t)
+	user.TOTPEnabled = true
+	require.NoError(t, repo.CreateIdentity(&models.Identity{UserId: user.Id, Provider: "google", Subject: "google-123"}))
+
+	_, _, _, err := svc.CompleteOIDCLogin("google", "code", startOIDCState(t, svc, nil), SessionDevice{})
+	assert.IsType(t, &TwoFactorRequiredError{}, err)
+	assert.Empty(t, repo.sessions)
+}
+
+func TestAccountService_CompleteOIDCLink(t *testing.T) {
+	repo, user, svc, _ := newOIDCTestService(t)
+
+	// A link request cannot be redeemed as a login, nor by another user.
+	_, _, _, err := svc.CompleteOIDCLogin("google", "code", startOIDCState(t, svc, &user.Id), SessionDevice{})
+	assert.IsType(t, &InvalidOIDCStateError{}, err)
+	_, err = svc.CompleteOIDCLink(uuid.New(), "google", "code", startOIDCState(t, svc, &user.Id))
+	assert.IsType(t, &InvalidOIDCStateError{}, err)
+
+	identity, err := svc.CompleteOIDCLink(user.Id, "google", "code", startOIDCState(t, svc, &user.Id))
+	require.NoError(t, err)
+	assert.Equal(t, user.Id, identity.UserId)
+	assert.Equal(t, "player@example.com", identity.Email)
+
+	signedIn, _, _, err := svc.CompleteOIDCLogin("google", "code", startOIDCState(t, svc, nil), SessionDevice{})
+	require.NoError(t, err)
+	assert.Equal(t, user.Id, signedIn.Id)
+
+	other := &models.User{Id: uuid.New(), Email: "other@example.com", Verified: true}
+	repo.usersByID[other.Id] = other
+	_, err = svc.CompleteOIDCLink(other.Id, "google", "code", startOIDCState(t, svc, &other.Id))
+	assert.IsType(t, &IdentityAlreadyLinkedError{}, err)
+}
+
+func TestAccountService_UnlinkIdentity(t *testing.T) {
+	repo, user, svc, _ := newOIDCTestService(t)
+	require.NoError(t, repo.CreateIdentity(&models.Identity{UserId: user.Id, Provider: "google", Subject: "google-123"}))
+
+	assert.IsType(t, &IdentityNotFoundError{}, svc.UnlinkIdentity(user.Id, "discord"))
+	require.NoError(t, svc.UnlinkIdentity(user.Id, "google"))
+	assert.Empty(t, repo.identities)
+}
+
+func TestAccountService_UnlinkIdentity_LastLoginMethod(t *testing.T) {
+	repo, _, svc, _ := newOIDCTestService(t)
+	user, _, _, err := svc.CompleteOIDCLogin("google", "code", startOIDCState(t, svc, nil), SessionDevice{})
+	require.NoError(t, err)
+
+	assert.IsType(t, &LastLoginMethodError{}, svc.UnlinkIdentity(user.Id, "google"))
+	assert.Len(t, repo.identities, 1)
+}
@@ -0,0 +1,225 @@
+package services
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/FeedTheRealm-org/core-service/internal/authentication-service/models"
+	"github.com/FeedTheRealm-org/core-service/internal/authentication-service/repositories"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/logger"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/permissions"
+	"github.com/google/uuid"
+)
+
+var roleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_-]{0,31}$`)
+
+type RoleNotFoundError struct{}
+
+func (e *RoleNotFoundError) Error() string {
+	return "Role not found"
+}
+
+type InvalidRoleError struct {
+	Msg string
+}
+
+func (e *InvalidRoleError) Error() string {
+	return e.Msg
+}
+
+// RoleEscalationError is returned when a role change would hand out permissions the caller does not hold.
+type RoleEscalationError struct {
+	Msg string
+}
+
+func (e *RoleEscalationError) Error() string {
+	return e.Msg
+}
+
+// RoleManager is the session changing roles, it can only hand out or take away permissions it holds itself.
+type RoleManager struct {
+	UserID      uuid.UUID
+	IsAdmin     bool
+	Permissions []string
+}
+
+func (m RoleManager) holds(permission string) bool {
+	return m.IsAdmin || permissions.Grants(m.Permissions, permission)
+}
+
+// checkCanManage fails when the caller does not hold one of the permissions.
+func (m RoleManager) checkCanManage(granted []string) error {
+	for _, permission := range granted {
+		if !m.holds(permission) {
+			return &RoleEscalationError{Msg: "Cannot manage permission " + permission + " without holding it"}
+		}
+	}
+	return nil
+}
+
+func (s *accountService) ListRoles() ([]models.Role, error) {
+	return s.repo.ListRoles()
+}
+
+// SaveRole creates the role or replaces its permissions, every permission has to be a known one
+// held by the manager, as well as the ones the role had before.
+func (s *accountService) SaveRole(manager RoleManager, name string, description string, granted []string) (*models.Role, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if !roleNamePattern.MatchString(name) {
+		return nil, &InvalidRoleError{Msg: "Role names are up to 32 lowercase letters, digits, dashes or underscores"}
+	}
+
+	role := &models.Role{Name: name, Description: strings.TrimSpace(description)}
+	for _, permission := range granted {
+		if !permissions.IsKnown(permission) {
+			return nil, &InvalidRoleError{Msg: "Unknown permission " + permission}
+		}
+		if !slices.ContainsFunc(role.Permissions, func(p models.RolePermission) bool { return p.Permission == permission }) {
+			role.Permissions = append(role.Permissions, models.RolePermission{RoleName: name, Permission: permission})
+		}
+	}
+
+	if err := manager.checkCanManage(role.PermissionNames()); err != nil {
+		return nil, err
+	}
+	existing, err := s.findRole(name)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		if err := manager.checkCanManage(existing.PermissionNames()); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repo.SaveRole(role); err != nil {
+		logger.Logger.Errorf("SaveRole: failed to save role=%s: %v", name, err)
+		return nil, err
+	}
+	return role, nil
+}
+
+func (s *accountService) DeleteRole(manager RoleManager, name string) error {
+	existing, err := s.findRole(name)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		if err := manager.checkCanManage(existing.PermissionNames()); err != nil {
+			return err
+		}
+	}
+
+	if err := s.repo.DeleteRole(name); err != nil {
+		if _, ok := err.(*repositories.RoleNotFoundError); ok {
+			return &RoleNotFoundError{}
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *accountService) GetUserRoles(id string) ([]string, error) {
+	userId, err := uuid.Parse(id)
+	if err != nil {
+		return nil, &AccountInvalidFormat{Msg: "invalid user id"}
+	}
+	if _, err := s.repo.GetAccountById(userId); err != nil {
+		return nil, &AccountNotFoundError{}
+	}
+	return s.repo.ListUserRoles(userId)
+}
+
+// SetUserRoles replaces the roles of a user, the new permissions reach the user's tokens on their next refresh.
+// Managers cannot change their own roles, nor add or remove roles holding permissions they lack.
+func (s *accountService) SetUserRoles(manager RoleManager, id string, roles []string) error {
+	userId, err := uuid.Parse(id)
+	if err != nil {
+		return &AccountInvalidFormat{Msg: "invalid user id"}
+	}
+	if userId == manager.UserID {
+		return &RoleEscalationError{Msg: "Cannot change your own roles"}
+	}
+	if _, err := s.repo.GetAccountById(userId); err != nil {
+		return &AccountNotFoundError{}
+	}
+
+	names := make([]string, 0, len(roles))
+	for _, role := range roles {
+		role = strings.ToLower(strings.TrimSpace(role))
+		if !slices.Contains(names, role) {
+			names = append(names, role)
+		}
+	}
+
+	current, err := s.repo.ListUserRoles(userId)
+	if err != nil {
+		return err
+	}
+	for _, name := range symmetricDifference(current, names) {
+		role, err := s.findRole(name)
+		if err != nil {
+			return err
+		}
+		if role == nil {
+			return &RoleNotFoundError{}
+		}
+		if err := manager.checkCanManage(role.PermissionNames()); err != nil {
+			return err
+		}
+	}
+
+	if err := s.repo.SetUserRoles(userId, names); err != nil {
+		if _, ok := err.(*repositories.RoleNotFoundError); ok {
+			return &RoleNotFoundError{}
+		}
+		return err
+	}
+	return nil
+}
+
+// sessionPermissions returns the permissions carried by the access tokens of the user,
+// a failed lookup issues a token without staff permissions instead of failing the login.
+// Like admin rights, staff permissions need 2FA when it is required by the configuration.
+func (s *accountService) sessionPermissions(user *models.User) []string {
+	if s.conf.Server.RequireAdmin2FA && !user.TOTPEnabled {
+		return nil
+	}
+	granted, err := s.repo.GetUserPermissions(user.Id)
+	if err != nil {
+		logger.Logger.Errorf("sessionPermissions: failed to load permissions of user=%s: %v", user.Id, err)
+		return nil
+	}
+	return granted
+}
+
+// findRole returns the role with the given name, nil when it does not exist.
+func (s *accountService) findRole(name string) (*models.Role, error) {
+	roles, err := s.repo.ListRoles()
+	if err != nil {
+		return nil, err
+	}
+	for i := range roles {
+		if roles[i].Name == name {
+			return &roles[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// symmetricDifference returns the names found in only one of the lists.
+func symmetricDifference(a []string, b []string) []string {
+	changed := []string{}
+	for _, name := range a {
+		if !slices.Contains(b, name) {
+			changed = append(changed, name)
+		}
+	}
+	for _, name := range b {
+		if !slices.Contains(a, name) {
+			changed = append(changed, name)
+		}
+	}
+	return changed
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/FeedTheRealm-org/core-service/internal/utils/permissions"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rootManager is an admin session managing roles of other users.
+var rootManager = RoleManager{UserID: uuid.New(), IsAdmin: true}
+
+func TestAccountService_SaveRole(t *testing.T) {
+	repo, _, svc := newSessionTestService(t)
+
+	_, err := svc.SaveRole(rootManager, "Not Valid", "", nil)
+	assert.IsType(t, &InvalidRoleError{}, err)
+	_, err = svc.SaveRole(rootManager, "support", "", []string{"payments.everything"})
+	assert.IsType(t, &InvalidRoleError{}, err)
+	assert.Empty(t, repo.roles)
+
+	role, err := svc.SaveRole(rootManager, " Support ", "Helps players", []string{permissions.USERS_READ, permissions.USERS_READ, permissions.PAYMENTS_BALANCES_READ})
+	require.NoError(t, err)
+	assert.Equal(t, "support", role.Name)
+	assert.Equal(t, []string{permissions.USERS_READ, permissions.PAYMENTS_BALANCES_READ}, repo.roles["support"].PermissionNames())
+
+	assert.NoError(t, svc.DeleteRole(rootManager, "support"))
+	assert.IsType(t, &RoleNotFoundError{}, svc.DeleteRole(rootManager, "support"))
+}
+
+func TestAccountService_SetUserRoles(t *testing.T) {
+	repo, user, svc := newSessionTestService(t)
+	_, err := svc.SaveRole(rootManager, "finance", "", []string{permissions.PAYMENTS_METRICS_READ})
+	require.NoError(t, err)
+
+	assert.IsType(t, &AccountInvalidFormat{}, svc.SetUserRoles(rootManager, "not-a-uuid", []string{"finance"}))
+	assert.IsType(t, &AccountNotFoundError{}, svc.SetUserRoles(rootManager, uuid.NewString(), []string{"finance"}))
+	assert.IsType(t, &RoleNotFoundError{}, svc.SetUserRoles(rootManager, user.Id.String(), []string{"finance", "operator"}))
+	assert.Empty(t, repo.userRoles[user.Id])
+
+	require.NoError(t, svc.SetUserRoles(rootManager, user.Id.String(), []string{"Finance", "finance"}))
+	roles, err := svc.GetUserRoles(user.Id.String())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"finance"}, roles)
+}
+
+func TestAccountService_LoginAccount_TokenCarriesRolePermissions(t *testing.T) {
+	_, user, svc := newSessionTestService(t)
+	_, err := svc.SaveRole(rootManager, "operator", "", []string{permissions.WORLD_JOBS_WRITE})
+	require.NoError(t, err)
+	require.NoError(t, svc.SetUserRoles(rootManager, user.Id.String(), []string{"operator"}))
+
+	_, accessToken, _, err := svc.LoginAccount(user.Email, "Password1", false, SessionDevice{})
+	require.NoError(t, err)
+
+	claims, err := svc.jwt.IsValidateAccessToken(accessToken, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, false, claims["isAdmin"])
+	assert.Equal(t, []any{permissions.WORLD_JOBS_WRITE}, claims["permissions"])
+}
+
+func TestAccountService_SaveRole_OnlyGrantsHeldPermissions(t *testing.T) {
+	repo, _, svc := newSessionTestService(t)
+	manager := RoleManager{UserID: uuid.New(), Permissions: []string{permissions.ROLES_MANAGE, permissions.USERS_READ}}
+
+	_, err := svc.SaveRole(manager, "superuser", "", permissions.All)
+	assert.IsType(t, &RoleEscalationError{}, err)
+	assert.Empty(t, repo.roles)
+
+	_, err = svc.SaveRole(manager, "support", "", []string{permissions.USERS_READ})
+	require.NoError(t, err)
+
+	// Permissions the manager lacks can neither be taken out of an existing role nor deleted with it
+	_, err = svc.SaveRole(rootManager, "finance", "", []string{permissions.PAYMENTS_BALANCES_WRITE})
+	require.NoError(t, err)
+	_, err = svc.SaveRole(manager, "finance", "", []string{permissions.USERS_READ})
+	assert.IsType(t, &RoleEscalationError{}, err)
+	assert.IsType(t, &RoleEscalationError{}, svc.DeleteRole(manager, "finance"))
+	assert.Equal(t, []string{permissions.PAYMENTS_BALANCES_WRITE}, repo.roles["finance"].PermissionNames())
+}
+
+func TestAccountService_SetUserRoles_RefusesEscalation(t *testing.T) {
+	repo, user, svc := newSessionTestService(t)
+	_, err := svc.SaveRole(rootManager, "support", "", []string{permissions.USERS_READ})
+	require.NoError(t, err)
+	_, err = svc.SaveRole(rootManager, "superuser", "", permissions.All)
+	require.NoError(t, err)
+
+	manager := RoleManager{UserID: uuid.New(), Permissions: []string{permissions.ROLES_MANAGE, permissions.USERS_READ}}
+	assert.IsType(t, &RoleEscalationError{}, svc.SetUserRoles(manager, user.Id.String(), []string{"superuser"}))
+	assert.Empty(t, repo.userRoles[user.Id])
+
+	require.NoError(t, svc.SetUserRoles(manager, user.Id.String(), []string{"support"}))
+	assert.Equal(t, []string{"support"}, repo.userRoles[user.Id])
+
+	// Removing a role the manager could not grant is refused as well
+	require.NoError(t, svc.SetUserRoles(rootManager, user.Id.String(), []string{"support", "superuser"}))
+	assert.IsType(t, &RoleEscalationError{}, svc.SetUserRoles(manager, user.Id.String(), []string{"support"}))
+	assert.Equal(t, []string{"support", "superuser"}, repo.userRoles[user.Id])
+}
+
+func TestAccountService_SetUserRoles_RefusesOwnRoles(t *testing.T) {
+	repo, user, svc := newSessionTestService(t)
+	_, err := svc.SaveRole(rootManager, "support", "", []string{permissions.USERS_READ})
+	require.NoError(t, err)
+
+	self := RoleManager{UserID: user.Id, IsAdmin: true}
+	assert.IsType(t, &RoleEscalationError{}, svc.SetUserRoles(self, user.Id.String(), []string{"support"}))
+	assert.Empty(t, repo.userRoles[user.Id])
+}
+
+func TestAccountService_RequireAdmin2FA_StaffPermissions(t *testing.T) {
+	_, user, svc := newSessionTestService(t)
+	svc.conf.Server.RequireAdmin2FA = true
+	_, err := svc.SaveRole(rootManager, "finance", "", []string{permissions.PAYMENTS_BALANCES_WRITE})
+	require.NoError(t, err)
+	assert.False(t, svc.TwoFactorSetupRequired(user))
+	require.NoError(t, svc.SetUserRoles(rootManager, user.Id.String(), []string{"finance"}))
+
+	_, accessToken, _, err := svc.LoginAccount(user.Email, "Password1", false, SessionDevice{})
+	require.NoError(t, err)
+	claims, err := svc.jwt.IsValidateAccessToken(accessToken, time.Now())
+	require.NoError(t, err)
+	assert.Empty(t, claims["permissions"], "Staff without 2FA get regular sessions")
+	assert.True(t, svc.TwoFactorSetupRequired(user))
+
+	enrollTestUser(t, svc, user)
+	assert.False(t, svc.TwoFactorSetupRequired(user))
+	assert.Equal(t, []string{permissions.PAYMENTS_BALANCES_WRITE}, svc.sessionPermissions(user))
+}
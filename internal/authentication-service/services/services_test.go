@@ -7,6 +7,7 @@ import (
 	"github.com/FeedTheRealm-org/core-service/config"
 	"github.com/FeedTheRealm-org/core-service/internal/authentication-service/repositories"
 	"github.com/FeedTheRealm-org/core-service/internal/authentication-service/services"
+	"github.com/FeedTheRealm-org/core-service/internal/service_clients"
 	"github.com/FeedTheRealm-org/core-service/internal/utils/email_sender"
 	"github.com/FeedTheRealm-org/core-service/internal/utils/logger"
 	"github.com/FeedTheRealm-org/core-service/internal/utils/session"
@@ -19,13 +20,13 @@ func CreateStartAccountService() {
 	conf := config.CreateConfig()
 	logger.InitLogger(false)
 	db, _ := config.NewDB(conf)
-	jwtManager := session.NewJWTManager(conf.SessionAccessTokenSecretKey, conf.SessionRefreshTokenSecretKey, conf.SessionAccessTokenDuration, conf.SessionRefreshTokenDuration)
+	jwtManager := session.NewJWTManager(session.NewEphemeralSigningKeys(), conf.SessionRefreshTokenSecretKey, conf.SessionAccessTokenDuration, conf.SessionRefreshTokenDuration)
 	repo, err := repositories.NewAccountRepository(conf, db)
 	if err != nil {
 		panic(err)
 	}
 
-	accountService = services.NewAccountService(conf, repo, jwtManager)
+	accountService = services.NewAccountService(conf, repo, jwtManager, nil, service_clients.NewInProcessClients())
 }
 
 func CreateStartEmailSenderService() {
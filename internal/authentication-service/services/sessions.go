@@ -0,0 +1,196 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/FeedTheRealm-org/core-service/internal/authentication-service/models"
+	"github.com/FeedTheRealm-org/core-service/internal/authentication-service/repositories"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/logger"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/session"
+	"github.com/google/uuid"
+)
+
+const maxUserAgentLength = 512
+
+// SessionDevice describes the client a session was opened or last refreshed from.
+type SessionDevice struct {
+	UserAgent string
+	IPAddress string
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func (d SessionDevice) normalized() SessionDevice {
+	userAgent := strings.TrimSpace(d.UserAgent)
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+	return SessionDevice{UserAgent: userAgent, IPAddress: strings.TrimSpace(d.IPAddress)}
+}
+
+func (s *accountService) generateTokenPair(user *models.User, sessionId uuid.UUID) (string, string, error) {
+	accessToken, err := s.jwt.GenerateAccessToken(user.Id.String(), user.Email, s.hasAdminAccess(user), s.sessionPermissions(user), sessionId.String())
+	if err != nil {
+		return "", "", &AccountFailedToCreateTokenError{}
+	}
+
+	refreshToken, err := s.jwt.GenerateRefreshToken(user.Id.String(), user.Email, s.hasAdminAccess(user), sessionId.String())
+	if err != nil {
+		return "", "", &AccountFailedToCreateTokenError{}
+	}
+
+	return accessToken, refreshToken, nil
+}
+
+// startSession opens a new device session for the user and returns its access and refresh tokens.
+func (s *accountService) startSession(user *models.User, device SessionDevice) (string, string, error) {
+	if user.IsDeletionScheduled() {
+		return "", "", &AccountDeletionScheduledError{DeletionScheduledAt: *user.DeletionScheduledAt}
+	}
+
+	now := time.Now()
+	device = device.normalized()
+	newSession := &models.Session{
+		Id:         uuid.New(),
+		UserId:     user.Id,
+		UserAgent:  device.UserAgent,
+		IPAddress:  device.IPAddress,
+		LastUsedAt: now,
+		ExpiresAt:  now.Add(s.jwt.RefreshTokenDuration()),
+	}
+
+	accessToken, refreshToken, err := s.generateTokenPair(user, newSession.Id)
+	if err != nil {
+		return "", "", err
+	}
+
+	newSession.RefreshTokenHash = hashToken(refreshToken)
+	if err := s.repo.CreateSession(newSession); err != nil {
+		logger.Logger.Errorf("startSession: failed to create session for user=%s: %v", user.Id, err)
+		return "", "", &AccountFailedToCreateTokenError{}
+	}
+
+	return accessToken, refreshToken, nil
+}
+
+// revokeReusedSession is called when a refresh token that was already rotated out is presented again,
+// which means it leaked. The whole session is revoked so neither party can keep using it.
+func (s *accountService) revokeReusedSession(userId uuid.UUID, sessionId uuid.UUID) error {
+	logger.Logger.Warnf("RefreshToken: refresh token reuse detected for user=%s session=%s, revoking session", userId, sessionId)
+	if err := s.repo.RevokeSession(userId, sessionId, models.SESSION_REVOKED_TOKEN_REUSE); err != nil {
+		if _, ok := err.(*repositories.SessionNotFoundError); !ok {
+			logger.Logger.Errorf("RefreshToken: failed to revoke reused session=%s: %v", sessionId, err)
+		}
+	}
+	return &AccountSessionInvalid{}
+}
+
+// RefreshToken validates the refresh token of a session and rotates it, the presented token
+// stops being valid once a new pair is issued.
+func (s *accountService) RefreshToken(token string, email string, device SessionDevice) (string, string, error) {
+	email = strings.ToLower(email)
+	user, err := s.repo.GetAccountByEmail(email)
+	if err != nil {
+		return "", "", &AccountNotFoundError{}
+	}
+
+	now := time.Now()
+	claims, err := s.jwt.IsValidateRefreshToken(token, now)
+	if err != nil {
+		if _, ok := err.(*session.JWTExpiredTokenError); ok {
+			return "", "", &AccountSessionExpired{}
+		}
+		return "", "", &AccountSessionInvalid{}
+	}
+
+	if session.Subject(claims) != user.Id.String() {
+		return "", "", &AccountSessionInvalid{}
+	}
+
+	sessionIDStr, _ := claims["sid"].(string)
+	sessionId, err := uuid.Parse(sessionIDStr)
+	if err != nil {
+		return "", "", &AccountSessionInvalid{}
+	}
+
+	current, err := s.repo.GetSessionById(sessionId)
+	if err != nil || current.UserId != user.Id || current.RevokedAt != nil {
+		return "", "", &AccountSessionInvalid{}
+	}
+	if !current.IsActive(now) {
+		return "", "", &AccountSessionExpired{}
+	}
+
+	previousTokenHash := hashToken(token)
+	if current.RefreshTokenHash != previousTokenHash {
+		return "", "", s.revokeReusedSession(user.Id, sessionId)
+	}
+
+	if !user.Verified {
+		return "", "", &AccountNotVerifiedError{}
+	}
+
+	accessToken, refreshToken, err := s.generateTokenPair(user, sessionId)
+	if err != nil {
+		return "", "", err
+	}
+
+	device = device.normalized()
+	current.RefreshTokenHash = hashToken(refreshToken)
+	current.UserAgent = device.UserAgent
+	current.IPAddress = device.IPAddress
+	current.LastUsedAt = now
+	current.ExpiresAt = now.Add(s.jwt.RefreshTokenDuration())
+	if err := s.repo.RotateSessionRefreshToken(current, previousTokenHash); err != nil {
+		if _, ok := err.(*repositories.SessionNotFoundError); ok {
+			// Another refresh with the same token won the race
+			return "", "", s.revokeReusedSession(user.Id, sessionId)
+		}
+		logger.Logger.Errorf("RefreshToken: failed to rotate session=%s: %v", sessionId, err)
+		return "", "", &AccountFailedToCreateTokenError{}
+	}
+
+	return accessToken, refreshToken, nil
+}
+
+// Logout revokes the session the caller is using, logging out twice is not an error.
+func (s *accountService) Logout(userId uuid.UUID, sessionId uuid.UUID) error {
+	if err := s.repo.RevokeSession(userId, sessionId, models.SESSION_REVOKED_LOGOUT); err != nil {
+		if _, ok := err.(*repositories.SessionNotFoundError); ok {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *accountService) ListSessions(userId uuid.UUID) ([]models.Session, error) {
+	return s.repo.ListActiveSessions(userId, time.Now())
+}
+
+func (s *accountService) RevokeSession(userId uuid.UUID, sessionId uuid.UUID) error {
+	if err := s.repo.RevokeSession(userId, sessionId, models.SESSION_REVOKED_BY_USER); err != nil {
+		if _, ok := err.(*repositories.SessionNotFoundError); ok {
+			return &SessionNotFoundError{}
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *accountService) RevokeAllSessions(id string) error {
+	userId, err := uuid.Parse(id)
+	if err != nil {
+		return &AccountInvalidFormat{Msg: "invalid user id"}
+	}
+	if _, err := s.repo.GetAccountById(userId); err != nil {
+		return &AccountNotFoundError{}
+	}
+	return s.repo.RevokeAllSessions(userId, models.SESSION_REVOKED_BY_ADMIN)
+}
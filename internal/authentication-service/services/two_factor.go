@@ -0,0 +1,250 @@
+package services
+
+import (
+	"crypto/rand"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/FeedTheRealm-org/core-service/internal/authentication-service/models"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/logger"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/totp"
+	"github.com/google/uuid"
+)
+
+const (
+	twoFactorIssuer               = "Feed The Realm"
+	twoFactorChallengeExpiry      = 5 * time.Minute
+	twoFactorChallengeMaxAttempts = 5
+	recoveryCodeCount             = 10
+	recoveryCodeLength            = 10
+	// recoveryCodeAlphabet leaves out characters that are easy to misread.
+	recoveryCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+)
+
+// TwoFactorRequiredError is returned by the password step of a login when the account has 2FA enabled,
+// the challenge token must be sent back with a TOTP or recovery code to finish logging in.
+type TwoFactorRequiredError struct {
+	ChallengeToken string
+	ExpiresIn      time.Duration
+}
+
+func (e *TwoFactorRequiredError) Error() string {
+	return "Two-factor authentication code required"
+}
+
+type TwoFactorAlreadyEnabledError struct{}
+
+func (e *TwoFactorAlreadyEnabledError) Error() string {
+	return "Two-factor authentication is already enabled"
+}
+
+type TwoFactorNotSetUpError struct{}
+
+func (e *TwoFactorNotSetUpError) Error() string {
+	return "Two-factor authentication has not been set up"
+}
+
+type InvalidTwoFactorCodeError struct{}
+
+func (e *InvalidTwoFactorCodeError) Error() string {
+	return "Invalid two-factor authentication code"
+}
+
+type InvalidTwoFactorChallengeError struct{}
+
+func (e *InvalidTwoFactorChallengeError) Error() string {
+	return "Login challenge is invalid or has expired"
+}
+
+// hasAdminAccess reports whether sessions of the user carry admin rights,
+// admins must have 2FA enabled when it is required by the configuration.
+func (s *accountService) hasAdminAccess(user *models.User) bool {
+	if !user.IsAdmin {
+		return false
+	}
+	return !s.conf.Server.RequireAdmin2FA || user.TOTPEnabled
+}
+
+// TwoFactorSetupRequired reports whether the user is an admin or holds staff roles
+// that only reach their sessions once 2FA is enabled.
+func (s *accountService) TwoFactorSetupRequired(user *models.User) bool {
+	if !s.conf.Server.RequireAdmin2FA || user.TOTPEnabled {
+		return false
+	}
+	if user.IsAdmin {
+		return true
+	}
+	roles, err := s.repo.ListUserRoles(user.Id)
+	if err != nil {
+		logger.Logger.Errorf("TwoFactorSetupRequired: failed to load roles of user=%s: %v", user.Id, err)
+		return false
+	}
+	return len(roles) > 0
+}
+
+// startTwoFactorChallenge records the password step of a login, the challenge token is handed
+// back in a TwoFactorRequiredError so LoginAccount keeps returning it as its only error.
+func (s *accountService) startTwoFactorChallenge(user *models.User) error {
+	token, tokenHash, err := generateResetToken()
+	if err != nil {
+		return &AccountFailedToCreateTokenError{}
+	}
+
+	challenge := &models.TwoFactorChallenge{
+		UserId:    user.Id,
+		TokenHash: tokenHash,
+		ExpiresAt: time.Now().Add(twoFactorChallengeExpiry),
+	}
+	if err := s.repo.CreateTwoFactorChallenge(challenge); err != nil {
+		logger.Logger.Errorf("startTwoFactorChallenge: failed to create challenge for user=%s: %v", user.Id, err)
+		return &AccountFailedToCreateTokenError{}
+	}
+
+	return &TwoFactorRequiredError{ChallengeToken: token, ExpiresIn: twoFactorChallengeExpiry}
+}
+
+// verifySecondFactor accepts a TOTP code not used before or an unused recovery code.
+func (s *accountService) verifySecondFactor(user *models.User, code string, now time.Time) bool {
+	if user.TOTPSecret != nil {
+		if step, ok := totp.Validate(*user.TOTPSecret, code, now); ok {
+			return s.repo.UseTOTPStep(user.Id, step) == nil
+		}
+	}
+
+	normalized := normalizeRecoveryCode(code)
+	if len(normalized) != recoveryCodeLength {
+		return false
+	}
+	return s.repo.UseRecoveryCode(user.Id, hashToken(normalized)) == nil
+}
+
+func (s *accountService) CompleteTwoFactorLogin(challengeToken string, code string, device SessionDevice) (*models.User, string, string, error) {
+	challenge, err := s.repo.GetTwoFactorChallengeByTokenHash(hashToken(challengeToken))
+	if err != nil {
+		return nil, "", "", &InvalidTwoFactorChallengeError{}
+	}
+
+	now := time.Now()
+	if !challenge.IsUsable(now) || challenge.Attempts >= twoFactorChallengeMaxAttempts {
+		return nil, "", "", &InvalidTwoFactorChallengeError{}
+	}
+
+	user, err := s.repo.GetAccountById(challenge.UserId)
+	if err != nil {
+		return nil, "", "", &AccountNotFoundError{}
+	}
+
+	if user.IsLocked(now) {
+		return nil, "", "", &AccountLockedError{RetryAfter: user.LockedUntil.Sub(now)}
+	}
+
+	if !s.verifySecondFactor(user, code, now) {
+		if err := s.repo.IncrementTwoFactorChallengeAttempts(challenge.Id); err != nil {
+			logger.Logger.Errorf("CompleteTwoFactorLogin: failed to count attempt for challenge=%s: %v", challenge.Id, err)
+		}
+		// Wrong codes count towards the account lockout, otherwise new challenges would allow unlimited guesses.
+		if err, locked := s.recordFailedLogin(user, now).(*AccountLockedError); locked {
+			return nil, "", "", err
+		}
+		return nil, "", "", &InvalidTwoFactorCodeError{}
+	}
+
+	if err := s.repo.ConsumeTwoFactorChallenge(challenge.Id); err != nil {
+		return nil, "", "", &InvalidTwoFactorChallengeError{}
+	}
+
+	s.clearFailedLogins(user)
+
+	accessToken, refreshToken, err := s.startSession(user, device)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	return user, accessToken, refreshToken, nil
+}
+
+func (s *accountService) SetupTwoFactor(userId uuid.UUID) (string, string, error) {
+	user, err := s.repo.GetAccountById(userId)
+	if err != nil {
+		return "", "", &AccountNotFoundError{}
+	}
+	if user.TOTPEnabled {
+		return "", "", &TwoFactorAlreadyEnabledError{}
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return "", "", err
+	}
+	if err := s.repo.SetTOTPSecret(user.Id, secret); err != nil {
+		return "", "", err
+	}
+
+	return secret, totp.KeyURI(twoFactorIssuer, user.Email, secret), nil
+}
+
+// ConfirmTwoFactor enables 2FA once the user proves the authenticator works, logs out every session
+// and returns the recovery codes in plain text. They are only stored hashed and cannot be shown again.
+func (s *accountService) ConfirmTwoFactor(userId uuid.UUID, code string) ([]string, error) {
+	user, err := s.repo.GetAccountById(userId)
+	if err != nil {
+		return nil, &AccountNotFoundError{}
+	}
+	if user.TOTPEnabled {
+		return nil, &TwoFactorAlreadyEnabledError{}
+	}
+	if user.TOTPSecret == nil {
+		return nil, &TwoFactorNotSetUpError{}
+	}
+
+	step, ok := totp.Validate(*user.TOTPSecret, code, time.Now())
+	if !ok {
+		return nil, &InvalidTwoFactorCodeError{}
+	}
+
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+		hashes = append(hashes, hashToken(normalizeRecoveryCode(code)))
+	}
+
+	if err := s.repo.EnableTOTP(user.Id, step, hashes); err != nil {
+		return nil, err
+	}
+
+	// Sessions opened with the password alone must not gain admin rights on their next refresh.
+	if err := s.repo.RevokeAllSessions(user.Id, models.SESSION_REVOKED_2FA_ENABLED); err != nil {
+		logger.Logger.Errorf("ConfirmTwoFactor: failed to revoke sessions of user=%s: %v", user.Id, err)
+	}
+
+	logger.Logger.Infof("Two-factor authentication enabled for user=%s", user.Id)
+	return codes, nil
+}
+
+// generateRecoveryCode returns a code formatted as XXXXX-XXXXX.
+func generateRecoveryCode() (string, error) {
+	var sb strings.Builder
+	alphabetSize := big.NewInt(int64(len(recoveryCodeAlphabet)))
+	for i := 0; i < recoveryCodeLength; i++ {
+		if i == recoveryCodeLength/2 {
+			sb.WriteByte('-')
+		}
+		n, err := rand.Int(rand.Reader, alphabetSize)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteByte(recoveryCodeAlphabet[n.Int64()])
+	}
+	return sb.String(), nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/FeedTheRealm-org/core-service/internal/authentication-service/models"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/totp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// enrollTestUser enables 2FA for the session test user and returns the secret and recovery codes.
+func enrollTestUser(t *testing.T, svc *accountService, user *models.User) (string, []string) {
+	t.Helper()
+	secret, _, err := svc.SetupTwoFactor(user.Id)
+	require.NoError(t, err)
+	code, err := totp.CodeAt(secret, totp.Step(time.Now()))
+	require.NoError(t, err)
+	recoveryCodes, err := svc.ConfirmTwoFactor(user.Id, code)
+	require.NoError(t, err)
+	return secret, recoveryCodes
+}
+
+func startTwoFactorLogin(t *testing.T, svc *accountService) string {
+	t.Helper()
+	_, _, _, err := svc.LoginAccount("user@example.com", "Password1", false, SessionDevice{})
+	challenge, ok := err.(*TwoFactorRequiredError)
+	require.True(t, ok, "Expected TwoFactorRequiredError, got %v", err)
+	require.NotEmpty(t, challenge.ChallengeToken)
+	return challenge.ChallengeToken
+}
+
+func TestAccountService_SetupAndConfirmTwoFactor(t *testing.T) {
+	repo, user, svc := newSessionTestService(t)
+	loginTestUser(t, svc)
+
+	secret, uri, err := svc.SetupTwoFactor(user.Id)
+	require.NoError(t, err)
+	assert.Contains(t, uri, "secret="+secret)
+	assert.False(t, user.TOTPEnabled, "2FA is only enabled after confirming a code")
+
+	_, err = svc.ConfirmTwoFactor(user.Id, "000000")
+	_, invalid := err.(*InvalidTwoFactorCodeError)
+	assert.True(t, invalid)
+
+	code, err := totp.CodeAt(secret, totp.Step(time.Now()))
+	require.NoError(t, err)
+	recoveryCodes, err := svc.ConfirmTwoFactor(user.Id, code)
+	require.NoError(t, err)
+	assert.Len(t, recoveryCodes, recoveryCodeCount)
+	assert.True(t, user.TOTPEnabled)
+	assert.Equal(t, models.SESSION_REVOKED_2FA_ENABLED, repo.onlySession(t).RevokedReason)
+
+	_, _, err = svc.SetupTwoFactor(user.Id)
+	_, enabled := err.(*TwoFactorAlreadyEnabledError)
+	assert.True(t, enabled)
+}
+
+func TestAccountService_ConfirmTwoFactor_NotSetUp(t *testing.T) {
+	_, user, svc := newSessionTestService(t)
+
+	_, err := svc.ConfirmTwoFactor(user.Id, "123456")
+	_, notSetUp := err.(*TwoFactorNotSetUpError)
+	assert.True(t, notSetUp)
+}
+
+func TestAccountService_CompleteTwoFactorLogin_WithTOTP(t *testing.T) {
+	_, user, svc := newSessionTestService(t)
+	secret, _ := enrollTestUser(t, svc, user)
+
+	// The code used to confirm enrollment cannot be replayed, the next one is accepted.
+	nextCode, err := totp.CodeAt(secret, totp.Step(time.Now())+1)
+	require.NoError(t, err)
+
+	challenge := startTwoFactorLogin(t, svc)
+	loggedIn, accessToken, refreshToken, err := svc.CompleteTwoFactorLogin(challenge, nextCode, SessionDevice{UserAgent: "phone"})
+	require.NoError(t, err)
+	assert.Equal(t, user.Id, loggedIn.Id)
+	assert.NotEmpty(t, accessToken)
+	assert.NotEmpty(t, refreshToken)
+
+	_, _, _, err = svc.CompleteTwoFactorLogin(challenge, nextCode, SessionDevice{})
+	_, invalidChallenge := err.(*InvalidTwoFactorChallengeError)
+	assert.True(t, invalidChallenge, "Challenges are single use")
+
+	_, _, _, err = svc.CompleteTwoFactorLogin(startTwoFactorLogin(t, svc), nextCode, SessionDevice{})
+	_, invalidCode := err.(*InvalidTwoFactorCodeError)
+	assert.True(t, invalidCode, "TOTP codes cannot be replayed")
+}
+
+func TestAccountService_CompleteTwoFactorLogin_WithRecoveryCode(t *testing.T) {
+	_, user, svc := newSessionTestService(t)
+	_, recoveryCodes := enrollTestUser(t, svc, user)
+
+	_, _, _, err := svc.CompleteTwoFactorLogin(startTwoFactorLogin(t, svc), " "+recoveryCodes[0]+" ", SessionDevice{})
+	require.NoError(t, err)
+
+	_, _, _, err = svc.CompleteTwoFactorLogin(startTwoFactorLogin(t, svc), recoveryCodes[0], SessionDevice{})
+	_, invalidCode := err.(*InvalidTwoFactorCodeError)
+	assert.True(t, invalidCode, "Recovery codes are single use")
+}
+
+func TestAccountService_CompleteTwoFactorLogin_ChallengeAttemptsExhausted(t *testing.T) {
+	_, user, svc := newSessionTestService(t)
+	svc.conf.RateLimit.LockoutThreshold = 0
+	enrollTestUser(t, svc, user)
+
+	challenge := startTwoFactorLogin(t, svc)
+	for i := 0; i < twoFactorChallengeMaxAttempts; i++ {
+		_, _, _, err := svc.CompleteTwoFactorLogin(challenge, "000000", SessionDevice{})
+		_, invalidCode := err.(*InvalidTwoFactorCodeError)
+		assert.True(t, invalidCode)
+	}
+
+	_, _, _, err := svc.CompleteTwoFactorLogin(challenge, "000000", SessionDevice{})
+	_, invalidChallenge := err.(*InvalidTwoFactorChallengeError)
+	assert.True(t, invalidChallenge)
+}
+
+func TestAccountService_CompleteTwoFactorLogin_WrongCodesLockAccount(t *testing.T) {
+	_, user, svc := newSessionTestService(t)
+	svc.conf.RateLimit.LockoutThreshold = 2
+	enrollTestUser(t, svc, user)
+
+	_, _, _, err := svc.CompleteTwoFactorLogin(startTwoFactorLogin(t, svc), "000000", SessionDevice{})
+	_, invalidCode := err.(*InvalidTwoFactorCodeError)
+	assert.True(t, invalidCode)
+
+	_, _, _, err = svc.CompleteTwoFactorLogin(startTwoFactorLogin(t, svc), "000000", SessionDevice{})
+	locked, ok := err.(*AccountLockedError)
+	require.True(t, ok, "Expected AccountLockedError")
+	assert.Equal(t, user.Email, locked.Email)
+	assert.NotEmpty(t, locked.UnlockToken)
+}
+
+func TestAccountService_RequireAdmin2FA(t *testing.T) {
+	_, user, svc := newSessionTestService(t)
+	svc.conf.Server.RequireAdmin2FA = true
+	user.IsAdmin = true
+
+	_, accessToken, _, err := svc.LoginAccount("user@example.com", "Password1", false, SessionDevice{})
+	require.NoError(t, err)
+	claims, err := svc.jwt.IsValidateAccessToken(accessToken, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, false, claims["isAdmin"], "Admins without 2FA get regular sessions")
+	assert.True(t, svc.TwoFactorSetupRequired(user))
+
+	secret, _ := enrollTestUser(t, svc, user)
+	assert.False(t, svc.TwoFactorSetupRequired(user))
+
+	nextCode, err := totp.CodeAt(secret, totp.Step(time.Now())+1)
+	require.NoError(t, err)
+	_, accessToken, _, err = svc.CompleteTwoFactorLogin(startTwoFactorLogin(t, svc), nextCode, SessionDevice{})
+	require.NoError(t, err)
+	claims, err = svc.jwt.IsValidateAccessToken(accessToken, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, true, claims["isAdmin"])
+}
@@ -0,0 +1,43 @@
+package common_handlers
+
+import (
+	"encoding/json"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetAuditChange attaches the state of the audited target before and after the action,
+// either one can be nil. Values that cannot be encoded as JSON are left out.
+func SetAuditChange(ctx *gin.Context, before any, after any) {
+	if before != nil {
+		if encoded, err := json.Marshal(before); err == nil {
+			ctx.Set("auditBefore", json.RawMessage(encoded))
+		}
+	}
+	if after != nil {
+		if encoded, err := json.Marshal(after); err == nil {
+			ctx.Set("auditAfter", json.RawMessage(encoded))
+		}
+	}
+}
+
+// SetAuditTarget overrides the id of the audited target, for actions that create it.
+func SetAuditTarget(ctx *gin.Context, targetId string) {
+	ctx.Set("auditTargetId", targetId)
+}
+
+// GetAuditChange returns the state attached with SetAuditChange.
+func GetAuditChange(ctx *gin.Context) (before json.RawMessage, after json.RawMessage) {
+	if value, ok := ctx.Get("auditBefore"); ok {
+		before = value.(json.RawMessage)
+	}
+	if value, ok := ctx.Get("auditAfter"); ok {
+		after = value.(json.RawMessage)
+	}
+	return before, after
+}
+
+// GetRequestID returns the id the request id middleware assigned to the request.
+func GetRequestID(ctx *gin.Context) string {
+	return ctx.GetString("requestID")
+}
@@ -62,6 +62,21 @@ func TestIsAdminSession(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestHasPermission(t *testing.T) {
+	ctx, _ := setupTestContext(http.MethodGet, "/")
+	ctx.Set("includedJWT", true)
+	ctx.Set("permissions", []string{"payments.metrics.read"})
+
+	assert.NoError(t, HasPermission(ctx, "payments.metrics.read"))
+	assert.IsType(t, &errors.MissingPermissionError{}, HasPermission(ctx, "payments.balances.write"))
+
+	ctx.Set("isAdmin", true)
+	assert.NoError(t, HasPermission(ctx, "payments.balances.write"))
+
+	ctx.Set("invalidJWT", true)
+	assert.IsType(t, &errors.MissingPermissionError{}, HasPermission(ctx, "payments.metrics.read"))
+}
+
 func TestIsServerSession_AllowsServerFlag(t *testing.T) {
 	ctx, _ := setupTestContext(http.MethodGet, "/")
 	ctx.Set("includedJWT", true)
@@ -71,6 +86,16 @@ func TestIsServerSession_AllowsServerFlag(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestIsServerSession_BlocksUserSession(t *testing.T) {
+	ctx, _ := setupTestContext(http.MethodGet, "/")
+	ctx.Set("includedJWT", true)
+	ctx.Set("userID", "2b1c6c5e-8f0a-4f8e-9d7b-6a1f3c2d4e5f")
+	assert.IsType(t, &errors.NotServerSessionError{}, IsServerSession(ctx))
+
+	_, _, scoped := GetServerScope(ctx)
+	assert.False(t, scoped)
+}
+
 func TestGetEmailFromSession(t *testing.T) {
 	ctx, _ := setupTestContext(http.MethodGet, "/")
 	ctx.Set("includedJWT", true)
@@ -92,6 +117,21 @@ func TestGetUserIDFromSession_InvalidUUID(t *testing.T) {
 	assert.True(t, invalid)
 }
 
+func TestGetSessionIDFromSession(t *testing.T) {
+	ctx, _ := setupTestContext(http.MethodGet, "/")
+	ctx.Set("includedJWT", true)
+
+	_, err := GetSessionIDFromSession(ctx)
+	_, invalid := err.(*errors.InvalidSessionError)
+	assert.True(t, invalid, "tokens issued without a session id are rejected")
+
+	sessionID := uuid.New()
+	ctx.Set("sessionID", sessionID.String())
+	got, err := GetSessionIDFromSession(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, sessionID, got)
+}
+
 func TestIsGithubOIDCTokenValid(t *testing.T) {
 	ctx, _ := setupTestContext(http.MethodGet, "/")
 	ctx.Set("invalidGithubOIDC", true)
@@ -2,6 +2,7 @@ package common_handlers
 
 import (
 	"github.com/FeedTheRealm-org/core-service/internal/errors"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/permissions"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
@@ -33,14 +34,45 @@ func IsAdminSession(ctx *gin.Context) error {
 	return nil
 }
 
-// IsServerSession checks if the session has server privileges.
+// HasPermission checks if the session was granted the permission by one of its roles,
+// admin sessions hold every permission.
+func HasPermission(ctx *gin.Context, permission string) error {
+	if err := IsSessionValid(ctx); err != nil {
+		return &errors.MissingPermissionError{Permission: permission}
+	}
+	if ctx.GetBool("isAdmin") || permissions.Grants(ctx.GetStringSlice("permissions"), permission) {
+		return nil
+	}
+	return &errors.MissingPermissionError{Permission: permission}
+}
+
+// GetPermissionsFromSession returns the permissions the roles of the session granted,
+// admin sessions hold every permission without listing them.
+func GetPermissionsFromSession(ctx *gin.Context) []string {
+	if IsSessionValid(ctx) != nil {
+		return nil
+	}
+	return ctx.GetStringSlice("permissions")
+}
+
+// IsServerSession checks if the session has server privileges, user sessions never do.
 func IsServerSession(ctx *gin.Context) error {
-	if err := IsSessionValid(ctx); err != nil && !ctx.GetBool("isServer") {
+	if !IsInSession(ctx) || !ctx.GetBool("isServer") {
 		return &errors.NotServerSessionError{}
 	}
 	return nil
 }
 
+// GetServerScope returns the world zone the server credential of the session was issued for.
+// Scoped is false for sessions using the deprecated fixed server token, which act for every zone.
+func GetServerScope(ctx *gin.Context) (worldId string, zoneId int, scoped bool) {
+	worldId = ctx.GetString("serverWorldID")
+	if worldId == "" {
+		return "", 0, false
+	}
+	return worldId, ctx.GetInt("serverZoneID"), true
+}
+
 // GetUserIDFromSession checks if the session is valid and returns the userID from the session.
 func GetUserIDFromSession(ctx *gin.Context) (uuid.UUID, error) {
 	if err := IsSessionValid(ctx); err != nil {
@@ -54,6 +86,19 @@ func GetUserIDFromSession(ctx *gin.Context) (uuid.UUID, error) {
 	return parsedUserID, nil
 }
 
+// GetSessionIDFromSession checks if the session is valid and returns the id of the device session
+// the access token was issued for.
+func GetSessionIDFromSession(ctx *gin.Context) (uuid.UUID, error) {
+	if err := IsSessionValid(ctx); err != nil {
+		return uuid.Nil, err
+	}
+	parsedSessionID, err := uuid.Parse(ctx.GetString("sessionID"))
+	if err != nil {
+		return uuid.Nil, &errors.InvalidSessionError{}
+	}
+	return parsedSessionID, nil
+}
+
 // GetEmailFromSession checks if the session is valid and returns the email from the session.
 func GetEmailFromSession(ctx *gin.Context) (string, error) {
 	if err := IsSessionValid(ctx); err != nil {
@@ -74,3 +119,8 @@ func IsGithubOIDCTokenValid(ctx *gin.Context) error {
 	}
 	return nil
 }
+
+// GetGithubOIDCTag returns the tag whose workflow sent the GitHub OIDC token, set by the GitHub OIDC middleware.
+func GetGithubOIDCTag(ctx *gin.Context) string {
+	return ctx.GetString("githubOIDCTag")
+}
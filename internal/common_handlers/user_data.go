@@ -0,0 +1,52 @@
+package common_handlers
+
+import (
+	"net/http"
+
+	"github.com/FeedTheRealm-org/core-service/internal/errors"
+	"github.com/FeedTheRealm-org/core-service/internal/service_clients"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ExportUserDataController serves the internal route the authentication-service
+// calls to export what the service stores about the user in the user_id path param.
+func ExportUserDataController(client service_clients.UserDataClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userId, err := uuid.Parse(c.Param("user_id"))
+		if err != nil {
+			_ = c.Error(errors.NewBadRequestError("invalid user ID: " + c.Param("user_id")))
+			return
+		}
+
+		data, err := client.ExportUserData(userId)
+		if err != nil {
+			logger.Logger.Errorf("ExportUserData: failed to export data of user=%s: %v", userId, err)
+			_ = c.Error(errors.NewInternalServerError("Failed to export user data."))
+			return
+		}
+
+		HandleSuccessResponse(c, http.StatusOK, data)
+	}
+}
+
+// EraseUserDataController serves the internal route the authentication-service
+// calls to erase what the service stores about a deleted account.
+func EraseUserDataController(client service_clients.UserDataClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userId, err := uuid.Parse(c.Param("user_id"))
+		if err != nil {
+			_ = c.Error(errors.NewBadRequestError("invalid user ID: " + c.Param("user_id")))
+			return
+		}
+
+		if err := client.EraseUserData(userId); err != nil {
+			logger.Logger.Errorf("EraseUserData: failed to erase data of user=%s: %v", userId, err)
+			_ = c.Error(errors.NewInternalServerError("Failed to erase user data."))
+			return
+		}
+
+		HandleBodilessResponse(c, http.StatusNoContent)
+	}
+}
@@ -48,6 +48,20 @@ func NewConflictError(message string) *HttpError {
 	}
 }
 
+func NewRequestEntityTooLargeError(message string) *HttpError {
+	return &HttpError{
+		Status:  http.StatusRequestEntityTooLarge,
+		Message: message,
+	}
+}
+
+func NewTooManyRequestsError(message string) *HttpError {
+	return &HttpError{
+		Status:  http.StatusTooManyRequests,
+		Message: message,
+	}
+}
+
 func NewInternalServerError(message string) *HttpError {
 	return &HttpError{
 		Status:  http.StatusInternalServerError,
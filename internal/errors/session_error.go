@@ -38,6 +38,14 @@ func (n *NotAdminSessionError) Error() string {
 	return "session does not have admin privileges"
 }
 
+type MissingPermissionError struct {
+	Permission string
+}
+
+func (m *MissingPermissionError) Error() string {
+	return "session does not have the " + m.Permission + " permission"
+}
+
 type InvalidGithubOIDCTokenError struct {
 }
 
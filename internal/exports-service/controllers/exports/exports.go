@@ -114,7 +114,10 @@ func (ec *exportsController) UploadZip(c *gin.Context) {
 		return
 	}
 
-	common_handlers.HandleSuccessResponse(c, http.StatusCreated, buildExportZipResponse(exportZip))
+	response := buildExportZipResponse(exportZip)
+	common_handlers.SetAuditTarget(c, exportAuditTarget(appNameStr, osNameStr, version))
+	common_handlers.SetAuditChange(c, nil, response)
+	common_handlers.HandleSuccessResponse(c, http.StatusCreated, response)
 }
 
 // GetZipPath godoc
@@ -278,6 +281,7 @@ func (ec *exportsController) DeleteZipVersion(c *gin.Context) {
 		return
 	}
 
+	common_handlers.SetAuditTarget(c, exportAuditTarget(appNameStr, osNameStr, version))
 	common_handlers.HandleBodilessResponse(c, http.StatusNoContent)
 }
 
@@ -335,9 +339,15 @@ func (ec *exportsController) SetLatestZipVersion(c *gin.Context) {
 		return
 	}
 
+	common_handlers.SetAuditTarget(c, exportAuditTarget(req.AppName, req.OS, req.Version))
 	common_handlers.HandleSuccessResponse(c, http.StatusOK, buildExportZipResponse(exportZip))
 }
 
+// exportAuditTarget identifies an export in the audit log.
+func exportAuditTarget(appName string, osName string, version string) string {
+	return appName + "/" + osName + "/" + version
+}
+
 func validateVersion(version string) error {
 	if version == "" {
 		return errors.NewBadRequestError("version is required")
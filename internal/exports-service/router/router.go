@@ -7,6 +7,8 @@ import (
 	exports_repo "github.com/FeedTheRealm-org/core-service/internal/exports-service/repositories/exports"
 	exports_service "github.com/FeedTheRealm-org/core-service/internal/exports-service/services/exports"
 	"github.com/FeedTheRealm-org/core-service/internal/middleware"
+	"github.com/FeedTheRealm-org/core-service/internal/service_clients"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/permissions"
 	"github.com/gin-gonic/gin"
 )
 
@@ -17,7 +19,7 @@ func getNewBucketRepository(name string, conf *config.Config) (bucket.BucketRepo
 	return bucket.NewAwsS3BucketRepository(name, conf)
 }
 
-func SetupExportsServiceRouter(r *gin.Engine, conf *config.Config, db *config.DB) error {
+func SetupExportsServiceRouter(r *gin.Engine, conf *config.Config, db *config.DB, clients *service_clients.Clients) error {
 	g := r.Group("/exports")
 
 	worldsBucketRepo, err := getNewBucketRepository(conf.Assets.WorldsBucketName, conf)
@@ -29,11 +31,11 @@ func SetupExportsServiceRouter(r *gin.Engine, conf *config.Config, db *config.DB
 	exportsService := exports_service.NewExportsService(conf, exportsRepo, worldsBucketRepo)
 	exportsController := exports_controller.NewExportsController(conf, exportsService)
 
-	g.PUT("/zip", middleware.AdminCheckMiddleware(), exportsController.UploadZip)
+	g.PUT("/zip", middleware.RequirePermission(permissions.EXPORTS_WRITE), middleware.AuditMiddleware(clients.Audit, "exports.zip.upload", "world_zip", ""), exportsController.UploadZip)
 	g.GET("/zip", exportsController.GetZipPath)
 	g.GET("/zip/versions", exportsController.ListZipVersions)
-	g.DELETE("/zip", middleware.AdminCheckMiddleware(), exportsController.DeleteZipVersion)
-	g.PATCH("/zip/latest", middleware.AdminCheckMiddleware(), exportsController.SetLatestZipVersion)
+	g.DELETE("/zip", middleware.RequirePermission(permissions.EXPORTS_WRITE), middleware.AuditMiddleware(clients.Audit, "exports.zip.delete", "world_zip", ""), exportsController.DeleteZipVersion)
+	g.PATCH("/zip/latest", middleware.RequirePermission(permissions.EXPORTS_WRITE), middleware.AuditMiddleware(clients.Audit, "exports.zip.latest.update", "world_zip", ""), exportsController.SetLatestZipVersion)
 
 	return nil
 }
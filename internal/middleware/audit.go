@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/FeedTheRealm-org/core-service/internal/common_handlers"
+	"github.com/FeedTheRealm-org/core-service/internal/service_clients"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/logger"
+	"github.com/gin-gonic/gin"
+)
+
+const maxAuditedBodySize = 64 << 10
+
+// AuditMiddleware records the action in the audit log once the handler succeeds. The target id is
+// read from the targetParam path parameter. Handlers can attach the state of the target with
+// common_handlers.SetAuditChange, otherwise the JSON body of the request is stored as the new state.
+// A failure to record is logged, the action already happened.
+func AuditMiddleware(client service_clients.AuditClient, action string, targetType string, targetParam string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		body := auditedBody(c)
+
+		c.Next()
+
+		if len(c.Errors) > 0 || c.Writer.Status() >= http.StatusBadRequest {
+			return
+		}
+
+		actorID, err := common_handlers.GetUserIDFromSession(c)
+		if err != nil {
+			return
+		}
+
+		targetID := c.Param(targetParam)
+		if override := c.GetString("auditTargetId"); override != "" {
+			targetID = override
+		}
+
+		before, after := common_handlers.GetAuditChange(c)
+		if after == nil {
+			after = body
+		}
+
+		event := service_clients.AuditEvent{
+			ActorId:    actorID,
+			Action:     action,
+			TargetType: targetType,
+			TargetId:   targetID,
+			Before:     before,
+			After:      after,
+			RequestId:  common_handlers.GetRequestID(c),
+			IPAddress:  c.ClientIP(),
+			OccurredAt: time.Now(),
+		}
+		if err := client.RecordEvent(event); err != nil {
+			logger.Logger.Errorf("AuditMiddleware: failed to record action=%s by actor=%s: %v", action, actorID, err)
+		}
+	}
+}
+
+// auditedBody reads the JSON body of the request and restores it for the handler.
+func auditedBody(c *gin.Context) json.RawMessage {
+	if c.Request.Body == nil || !strings.HasPrefix(c.ContentType(), "application/json") {
+		return nil
+	}
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return nil
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	if len(body) == 0 || len(body) > maxAuditedBodySize || !json.Valid(body) {
+		return nil
+	}
+	return json.RawMessage(body)
+}
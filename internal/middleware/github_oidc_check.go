@@ -33,5 +33,6 @@ func GithubOIDCCheck(ghv *oidc_validation.GitHubOIDCVerifier) gin.HandlerFunc {
 		}
 
 		c.Set("invalidGithubOIDC", false)
+		c.Set("githubOIDCTag", claims.Tag())
 	}
 }
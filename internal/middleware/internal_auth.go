@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/FeedTheRealm-org/core-service/internal/errors"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/internal_auth"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// maxInternalBodySize caps what is buffered before the signature is checked, internal calls only carry small JSON bodies.
+const maxInternalBodySize = 1 << 20
+
+// InternalAuthMiddleware only lets through requests signed by another service with the shared internal secret.
+func InternalAuthMiddleware(signer *internal_auth.RequestSigner) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var body []byte
+		if c.Request.Body != nil {
+			var err error
+			body, err = io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxInternalBodySize))
+			if _, tooLarge := err.(*http.MaxBytesError); tooLarge {
+				c.Abort()
+				_ = c.Error(errors.NewRequestEntityTooLargeError("request body too large"))
+				return
+			}
+			if err != nil {
+				c.Abort()
+				_ = c.Error(errors.NewBadRequestError("failed to read request body"))
+				return
+			}
+			c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		}
+
+		err := signer.Verify(
+			c.Request.Method,
+			c.Request.URL.RequestURI(),
+			body,
+			c.GetHeader(internal_auth.TIMESTAMP_HEADER),
+			c.GetHeader(internal_auth.SIGNATURE_HEADER),
+			time.Now(),
+		)
+		if err != nil {
+			logger.Logger.Warnf("Rejected internal request to %s: %v", c.Request.URL.Path, err)
+			c.Abort()
+			_ = c.Error(errors.NewUnauthorizedError(err.Error()))
+			return
+		}
+
+		c.Next()
+	}
+}
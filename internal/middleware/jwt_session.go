@@ -4,15 +4,18 @@ import (
 	"strings"
 	"time"
 
+	"github.com/FeedTheRealm-org/core-service/internal/service_clients"
 	"github.com/FeedTheRealm-org/core-service/internal/utils/logger"
 	"github.com/FeedTheRealm-org/core-service/internal/utils/session"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 // JWTAuthMiddleware parses the JWT token included in the request header,
 // and populates the gin context with it. If it cant find the header or
 // cant decode the token it passes to the next middleware without setting anything.
-func JWTAuthMiddleware(jwtManager *session.JWTManager, fixedToken string) gin.HandlerFunc {
+// Game server credentials are verified with servers and scope the session to their zone.
+func JWTAuthMiddleware(jwtManager *session.JWTManager, fixedToken string, servers service_clients.ServerCredentialsClient) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		defer c.Next()
 
@@ -38,6 +41,20 @@ func JWTAuthMiddleware(jwtManager *session.JWTManager, fixedToken string) gin.Ha
 			return
 		}
 
+		if strings.HasPrefix(tokenString, service_clients.SERVER_TOKEN_PREFIX) {
+			scope, err := servers.VerifyServerToken(tokenString)
+			if err != nil {
+				logger.Logger.Warnf("Invalid server token: %v", err)
+				c.Set("invalidJWT", true)
+				return
+			}
+			c.Set("userID", uuid.Nil.String())
+			c.Set("isServer", true)
+			c.Set("serverWorldID", scope.WorldId.String())
+			c.Set("serverZoneID", scope.ZoneId)
+			return
+		}
+
 		claims, err := jwtManager.IsValidateAccessToken(tokenString, time.Now())
 		if err != nil {
 			if _, ok := err.(*session.JWTExpiredTokenError); ok {
@@ -48,10 +65,10 @@ func JWTAuthMiddleware(jwtManager *session.JWTManager, fixedToken string) gin.Ha
 			return
 		}
 
-		if userID, ok := claims["userID"].(string); ok {
+		if userID, ok := claims["sub"].(string); ok {
 			c.Set("userID", userID)
 		} else {
-			logger.Logger.Warnln("Missing userID in JWT claims")
+			logger.Logger.Warnln("Missing sub in JWT claims")
 			c.Set("invalidJWT", true)
 		}
 
@@ -62,10 +79,24 @@ func JWTAuthMiddleware(jwtManager *session.JWTManager, fixedToken string) gin.Ha
 			c.Set("invalidJWT", true)
 		}
 
+		if sessionID, ok := claims["sid"].(string); ok {
+			c.Set("sessionID", sessionID)
+		}
+
 		if isAdmin, ok := claims["isAdmin"].(bool); ok && isAdmin {
 			c.Set("isAdmin", true)
 		} else {
 			c.Set("isAdmin", false)
 		}
+
+		permissions := []string{}
+		if granted, ok := claims["permissions"].([]any); ok {
+			for _, permission := range granted {
+				if p, ok := permission.(string); ok {
+					permissions = append(permissions, p)
+				}
+			}
+		}
+		c.Set("permissions", permissions)
 	}
 }
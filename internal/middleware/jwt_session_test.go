@@ -1,23 +1,29 @@
 package middleware_test
 
 import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/pem"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
+	"github.com/FeedTheRealm-org/core-service/internal/common_handlers"
 	"github.com/FeedTheRealm-org/core-service/internal/middleware"
+	"github.com/FeedTheRealm-org/core-service/internal/service_clients"
 	"github.com/FeedTheRealm-org/core-service/internal/utils/logger"
 	"github.com/FeedTheRealm-org/core-service/internal/utils/session"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 )
 
 // TestJWTAuth_NoAuthHeader tests the JWT authentication middleware
 // when no Authorization header is provided.
 func TestJWTAuth_NoAuthHeader(t *testing.T) {
-	r := setupRouterJWT("testsecret", "testsecret", time.Minute, time.Hour, "")
+	r := setupRouterJWT(time.Minute, time.Hour, "")
 
 	req := httptest.NewRequest("GET", "/test", nil)
 	w := httptest.NewRecorder()
@@ -30,10 +36,8 @@ func TestJWTAuth_NoAuthHeader(t *testing.T) {
 // TestJWTAuth_ValidToken tests the JWT authentication middleware
 // when a valid Authorization header is provided.
 func TestJWTAuth_ValidToken(t *testing.T) {
-	accessToken := "testsecret"
-	refreshToken := "testsecret"
-	token := createTestToken(accessToken, "12345", time.Now().Add(time.Hour))
-	r := setupRouterJWT(accessToken, refreshToken, time.Minute, time.Hour, "")
+	token := createTestToken("12345", time.Now().Add(time.Hour))
+	r := setupRouterJWT(time.Minute, time.Hour, "")
 
 	req := httptest.NewRequest("GET", "/test", nil)
 	req.Header.Set("Authorization", "Bearer "+token)
@@ -47,10 +51,8 @@ func TestJWTAuth_ValidToken(t *testing.T) {
 // TestJWTAuth_ExpiredToken tests the JWT authentication middleware
 // when an expired token is provided in the Authorization header.
 func TestJWTAuth_ExpiredToken(t *testing.T) {
-	accessToken := "testsecret"
-	refreshToken := "testsecret"
-	token := createTestToken(accessToken, "expired", time.Now().Add(-time.Hour))
-	r := setupRouterJWT(accessToken, refreshToken, time.Minute, time.Hour, "")
+	token := createTestToken("expired", time.Now().Add(-time.Hour))
+	r := setupRouterJWT(time.Minute, time.Hour, "")
 
 	req := httptest.NewRequest("GET", "/test", nil)
 	req.Header.Set("Authorization", "Bearer "+token)
@@ -62,10 +64,8 @@ func TestJWTAuth_ExpiredToken(t *testing.T) {
 }
 
 func TestJWTAuth_CookieToken(t *testing.T) {
-	accessToken := "testsecret"
-	refreshToken := "testsecret"
-	token := createTestToken(accessToken, "cookie-user", time.Now().Add(time.Hour))
-	r := setupRouterJWT(accessToken, refreshToken, time.Minute, time.Hour, "")
+	token := createTestToken("cookie-user", time.Now().Add(time.Hour))
+	r := setupRouterJWT(time.Minute, time.Hour, "")
 
 	req := httptest.NewRequest("GET", "/test", nil)
 	req.AddCookie(&http.Cookie{Name: "jwt", Value: token})
@@ -76,15 +76,35 @@ func TestJWTAuth_CookieToken(t *testing.T) {
 	assert.Equal(t, "cookie-user", w.Body.String())
 }
 
+func TestJWTAuth_UnknownKeyID(t *testing.T) {
+	_, otherKey, _ := ed25519.GenerateKey(nil)
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, jwt.MapClaims{
+		"sub":   "12345",
+		"email": "12345@example.com",
+		"iss":   session.TOKEN_ISSUER,
+		"aud":   session.ACCESS_TOKEN_AUDIENCE,
+		"exp":   time.Now().Add(time.Hour).Unix(),
+	})
+	token.Header["kid"] = "other"
+	tokenString, _ := token.SignedString(otherKey)
+	r := setupRouterJWT(time.Minute, time.Hour, "")
+
+	req := httptest.NewRequest("GET", "/test", nil)
+	req.Header.Set("Authorization", "Bearer "+tokenString)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 401, w.Code)
+	assert.Equal(t, "invalid", w.Body.String())
+}
+
 func TestJWTAuth_MissingUserIDClaim(t *testing.T) {
-	accessToken := "testsecret"
-	refreshToken := "testsecret"
 	claims := jwt.MapClaims{
 		"email": "user@example.com",
 		"exp":   time.Now().Add(time.Hour).Unix(),
 	}
-	token := createTokenWithClaims(accessToken, claims)
-	r := setupRouterJWT(accessToken, refreshToken, time.Minute, time.Hour, "")
+	token := createTokenWithClaims(claims)
+	r := setupRouterJWT(time.Minute, time.Hour, "")
 
 	req := httptest.NewRequest("GET", "/test", nil)
 	req.Header.Set("Authorization", "Bearer "+token)
@@ -96,14 +116,12 @@ func TestJWTAuth_MissingUserIDClaim(t *testing.T) {
 }
 
 func TestJWTAuth_MissingEmailClaim(t *testing.T) {
-	accessToken := "testsecret"
-	refreshToken := "testsecret"
 	claims := jwt.MapClaims{
-		"userID": "missing-email",
-		"exp":    time.Now().Add(time.Hour).Unix(),
+		"sub": "missing-email",
+		"exp": time.Now().Add(time.Hour).Unix(),
 	}
-	token := createTokenWithClaims(accessToken, claims)
-	r := setupRouterJWT(accessToken, refreshToken, time.Minute, time.Hour, "")
+	token := createTokenWithClaims(claims)
+	r := setupRouterJWT(time.Minute, time.Hour, "")
 
 	req := httptest.NewRequest("GET", "/test", nil)
 	req.Header.Set("Authorization", "Bearer "+token)
@@ -114,34 +132,116 @@ func TestJWTAuth_MissingEmailClaim(t *testing.T) {
 	assert.Equal(t, "invalid", w.Body.String())
 }
 
+// TestJWTAuth_PermissionsClaim tests that the permissions of the token are set in the context.
+func TestJWTAuth_PermissionsClaim(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger.InitLogger(false)
+	token := createTokenWithClaims(jwt.MapClaims{
+		"sub":         "12345",
+		"email":       "12345@example.com",
+		"exp":         time.Now().Add(time.Hour).Unix(),
+		"permissions": []string{"world.jobs.write", "payments.metrics.read"},
+	})
+
+	r := gin.New()
+	r.Use(middleware.JWTAuthMiddleware(session.NewJWTManager(testSigningKeys, "testsecret", time.Minute, time.Hour), "", fakeServerCredentials{}))
+	r.GET("/test", func(c *gin.Context) {
+		c.JSON(200, c.GetStringSlice("permissions"))
+	})
+
+	req := httptest.NewRequest("GET", "/test", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+	assert.JSONEq(t, `["world.jobs.write","payments.metrics.read"]`, w.Body.String())
+}
+
+func TestJWTAuth_ServerTokenIsScopedToItsZone(t *testing.T) {
+	r := gin.New()
+	r.Use(middleware.JWTAuthMiddleware(session.NewJWTManager(testSigningKeys, "testsecret", time.Minute, time.Hour), "", fakeServerCredentials{}))
+	r.GET("/test", func(c *gin.Context) {
+		worldId, zoneId, scoped := common_handlers.GetServerScope(c)
+		if !c.GetBool("isServer") || c.GetBool("invalidJWT") || !scoped {
+			c.String(401, "invalid")
+			return
+		}
+		c.String(200, "%s/%d", worldId, zoneId)
+	})
+
+	req := httptest.NewRequest("GET", "/test", nil)
+	req.Header.Set("Authorization", "Bearer ftrs_valid")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, serverTestWorldID.String()+"/3", w.Body.String())
+
+	req = httptest.NewRequest("GET", "/test", nil)
+	req.Header.Set("Authorization", "Bearer ftrs_revoked")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, 401, w.Code)
+}
+
 /* UTILS */
 
-// createTestToken creates a JWT token with the given secret, userID, and expiration time.
-func createTestToken(secret, userID string, expiration time.Time) string {
-	claims := jwt.MapClaims{
-		"userID": userID,
-		"email":  userID + "@example.com",
-		"exp":    expiration.Unix(),
+var serverTestWorldID = uuid.MustParse("9b0c4f8e-3a52-4d7a-8a47-1e2f3d4c5b6a")
+
+// fakeServerCredentials only accepts the ftrs_valid token, issued for zone 3 of serverTestWorldID.
+type fakeServerCredentials struct{}
+
+func (fakeServerCredentials) VerifyServerToken(token string) (*service_clients.ServerScope, error) {
+	if token != "ftrs_valid" {
+		return nil, service_clients.NewServerCredentialInvalid("invalid server token")
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	ss, _ := token.SignedString([]byte(secret))
-	return ss
+	return &service_clients.ServerScope{WorldId: serverTestWorldID, ZoneId: 3}, nil
+}
+
+// testPrivateKey signs the test tokens, testSigningKeys holds it under the "test" key id.
+var testPrivateKey, testSigningKeys = newTestSigningKeys()
+
+func newTestSigningKeys() (ed25519.PrivateKey, *session.SigningKeys) {
+	_, private, _ := ed25519.GenerateKey(nil)
+	der, _ := x509.MarshalPKCS8PrivateKey(private)
+	key, err := session.ParseSigningKey("test", pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	if err != nil {
+		panic(err)
+	}
+	keys, err := session.NewSigningKeys("test", key)
+	if err != nil {
+		panic(err)
+	}
+	return private, keys
+}
+
+// createTestToken creates a JWT token for userID with the given expiration time.
+func createTestToken(userID string, expiration time.Time) string {
+	return createTokenWithClaims(jwt.MapClaims{
+		"sub":   userID,
+		"email": userID + "@example.com",
+		"exp":   expiration.Unix(),
+	})
 }
 
-func createTokenWithClaims(secret string, claims jwt.MapClaims) string {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	ss, _ := token.SignedString([]byte(secret))
+// createTokenWithClaims signs claims with the test key, adding the issuer and audience checked by the manager.
+func createTokenWithClaims(claims jwt.MapClaims) string {
+	claims["iss"] = session.TOKEN_ISSUER
+	claims["aud"] = session.ACCESS_TOKEN_AUDIENCE
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
+	token.Header["kid"] = "test"
+	ss, _ := token.SignedString(testPrivateKey)
 	return ss
 }
 
 // setupRouterJWT initializes a Gin router with the JWT authentication middleware.
-func setupRouterJWT(accessTokenSecret, refreshTokenSecret string, accessTokenDuration, refreshTokenDuration time.Duration, fixedToken string) *gin.Engine {
+func setupRouterJWT(accessTokenDuration, refreshTokenDuration time.Duration, fixedToken string) *gin.Engine {
 	gin.SetMode(gin.TestMode)
-	jwtManager := session.NewJWTManager(accessTokenSecret, refreshTokenSecret, accessTokenDuration, refreshTokenDuration)
+	jwtManager := session.NewJWTManager(testSigningKeys, "testsecret", accessTokenDuration, refreshTokenDuration)
 	logger.InitLogger(false)
 
 	r := gin.New()
-	r.Use(middleware.JWTAuthMiddleware(jwtManager, fixedToken)) // Include Auth middleware
+	r.Use(middleware.JWTAuthMiddleware(jwtManager, fixedToken, fakeServerCredentials{})) // Include Auth middleware
 	r.GET("/test", func(c *gin.Context) {
 		userID := c.GetString("userID")
 		invalidJWT := c.GetBool("invalidJWT")
@@ -161,10 +261,8 @@ func setupRouterJWT(accessTokenSecret, refreshTokenSecret string, accessTokenDur
 // TestJWTAuth_FixedToken verifies that when a fixed server token is configured
 // the middleware treats it as a valid session without attempting JWT parsing.
 func TestJWTAuth_FixedToken(t *testing.T) {
-	accessToken := "testsecret"
-	refreshToken := "testsecret"
 	fixedToken := "fixed-server-token"
-	r := setupRouterJWT(accessToken, refreshToken, time.Minute, time.Hour, fixedToken)
+	r := setupRouterJWT(time.Minute, time.Hour, fixedToken)
 
 	req := httptest.NewRequest("GET", "/test", nil)
 	req.Header.Set("Authorization", "Bearer "+fixedToken)
@@ -2,15 +2,24 @@ package middleware_test
 
 import (
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/FeedTheRealm-org/core-service/config"
+	"github.com/FeedTheRealm-org/core-service/internal/common_handlers"
 	"github.com/FeedTheRealm-org/core-service/internal/dtos"
 	"github.com/FeedTheRealm-org/core-service/internal/errors"
 	"github.com/FeedTheRealm-org/core-service/internal/middleware"
+	"github.com/FeedTheRealm-org/core-service/internal/service_clients"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/internal_auth"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/logger"
 	"github.com/FeedTheRealm-org/core-service/internal/utils/oidc_validation"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/rate_limiter"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 )
@@ -149,6 +158,42 @@ func TestAdminCheckMiddleware_BlocksNonAdmin(t *testing.T) {
 	assert.Equal(t, http.StatusForbidden, w.Code)
 }
 
+func permissionRouter(isAdmin bool, granted ...string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middleware.ErrorHandlerMiddleware())
+	r.Use(func(c *gin.Context) {
+		c.Set("includedJWT", true)
+		c.Set("isAdmin", isAdmin)
+		c.Set("permissions", granted)
+	})
+	r.GET("/test", middleware.RequirePermission("payments.metrics.read"), func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+	return r
+}
+
+func TestRequirePermission_AllowsGrantedPermission(t *testing.T) {
+	w := httptest.NewRecorder()
+	permissionRouter(false, "payments.balances.read", "payments.metrics.read").ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/test", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestRequirePermission_AllowsAdmin(t *testing.T) {
+	w := httptest.NewRecorder()
+	permissionRouter(true).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/test", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestRequirePermission_BlocksMissingPermission(t *testing.T) {
+	w := httptest.NewRecorder()
+	permissionRouter(false, "payments.balances.read").ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/test", nil))
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
 func TestServerCheckMiddleware_BlocksInvalidSession(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	r := gin.New()
@@ -190,6 +235,51 @@ func TestServerCheckMiddleware_AllowsServer(t *testing.T) {
 	assert.Equal(t, "ok", w.Body.String())
 }
 
+func TestServerCheckMiddleware_BlocksUserSession(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middleware.ErrorHandlerMiddleware())
+	r.Use(func(c *gin.Context) {
+		c.Set("includedJWT", true)
+		c.Set("userID", "2b1c6c5e-8f0a-4f8e-9d7b-6a1f3c2d4e5f")
+	})
+	r.Use(middleware.ServerCheckMiddleware())
+	r.GET("/test", func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/test", nil))
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestServerCheckMiddleware_LimitsScopedServerToItsZone(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middleware.ErrorHandlerMiddleware())
+	r.Use(func(c *gin.Context) {
+		c.Set("includedJWT", true)
+		c.Set("isServer", true)
+		c.Set("serverWorldID", "9b0c4f8e-3a52-4d7a-8a47-1e2f3d4c5b6a")
+		c.Set("serverZoneID", 3)
+	})
+	r.POST("/world/:id/zones/:zone_id/players", middleware.ServerCheckMiddleware(), func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+
+	cases := map[string]int{
+		"/world/9b0c4f8e-3a52-4d7a-8a47-1e2f3d4c5b6a/zones/3/players": http.StatusOK,
+		"/world/9b0c4f8e-3a52-4d7a-8a47-1e2f3d4c5b6a/zones/4/players": http.StatusForbidden,
+		"/world/2b1c6c5e-8f0a-4f8e-9d7b-6a1f3c2d4e5f/zones/3/players": http.StatusForbidden,
+	}
+	for path, status := range cases {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, nil))
+		assert.Equal(t, status, w.Code, path)
+	}
+}
+
 func TestMultipartCleanup_Coverage(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	r := gin.New()
@@ -241,3 +331,302 @@ func TestGithubOIDCCheck_FullCoverage(t *testing.T) {
 		})
 	}
 }
+
+func TestInternalAuthMiddleware_RejectsUnsignedRequest(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger.InitLogger(false)
+	signer := internal_auth.NewRequestSigner("my-internal-secret", time.Minute)
+
+	r := gin.New()
+	r.Use(middleware.ErrorHandlerMiddleware())
+	r.Use(middleware.InternalAuthMiddleware(signer))
+	r.POST("/assets/internal/users/:user_id/cosmetics", func(c *gin.Context) {
+		c.String(http.StatusCreated, "ok")
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/assets/internal/users/abc/cosmetics", strings.NewReader(`{"cosmetic_id":"x"}`))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestInternalAuthMiddleware_AllowsSignedRequest(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger.InitLogger(false)
+	signer := internal_auth.NewRequestSigner("my-internal-secret", time.Minute)
+
+	r := gin.New()
+	r.Use(middleware.ErrorHandlerMiddleware())
+	r.Use(middleware.InternalAuthMiddleware(signer))
+	r.POST("/assets/internal/users/:user_id/cosmetics", func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		c.String(http.StatusCreated, string(body))
+	})
+
+	body := `{"cosmetic_id":"x"}`
+	req := httptest.NewRequest(http.MethodPost, "/assets/internal/users/abc/cosmetics", strings.NewReader(body))
+	signer.SignRequest(req, []byte(body), time.Now())
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, body, w.Body.String())
+}
+
+func TestInternalAuthMiddleware_RejectsOversizedBody(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger.InitLogger(false)
+	signer := internal_auth.NewRequestSigner("my-internal-secret", time.Minute)
+
+	r := gin.New()
+	r.Use(middleware.ErrorHandlerMiddleware())
+	r.Use(middleware.InternalAuthMiddleware(signer))
+	r.POST("/assets/internal/users/:user_id/cosmetics", func(c *gin.Context) {
+		c.String(http.StatusCreated, "ok")
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/assets/internal/users/abc/cosmetics", strings.NewReader(strings.Repeat("a", 2<<20)))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+}
+
+type failingRateLimiter struct{}
+
+func (failingRateLimiter) Allow(key string, limit rate_limiter.Limit) (rate_limiter.Decision, error) {
+	return rate_limiter.Decision{}, assert.AnError
+}
+
+func TestRateLimitMiddleware_RejectsWithRetryAfter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	limiter := rate_limiter.NewMemoryRateLimiter()
+
+	r := gin.New()
+	r.Use(middleware.ErrorHandlerMiddleware())
+	r.POST("/auth/login", middleware.RateLimitMiddleware(limiter,
+		middleware.RateLimitByIP("login", rate_limiter.Limit{Requests: 1, Per: time.Minute}),
+	), func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/auth/login", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	req = httptest.NewRequest(http.MethodPost, "/auth/login", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "60", w.Header().Get("Retry-After"))
+	var payload dtos.ErrorResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &payload))
+	assert.Equal(t, http.StatusTooManyRequests, payload.Status)
+	assert.Equal(t, "/auth/login", payload.Instance)
+}
+
+func TestRateLimitMiddleware_ByIPIgnoresSpoofedForwardedFor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	limiter := rate_limiter.NewMemoryRateLimiter()
+
+	r := gin.New()
+	assert.NoError(t, r.SetTrustedProxies(nil))
+	r.Use(middleware.ErrorHandlerMiddleware())
+	r.POST("/auth/login", middleware.RateLimitMiddleware(limiter,
+		middleware.RateLimitByIP("login", rate_limiter.Limit{Requests: 1, Per: time.Minute}),
+	), func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+
+	send := func(forwardedFor string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/auth/login", nil)
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusOK, send("10.0.0.1").Code)
+	// A new X-Forwarded-For value must not give the same client a new bucket
+	assert.Equal(t, http.StatusTooManyRequests, send("10.0.0.2").Code)
+}
+
+func TestRateLimitMiddleware_ByEmailKeepsBody(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	limiter := rate_limiter.NewMemoryRateLimiter()
+
+	r := gin.New()
+	r.Use(middleware.ErrorHandlerMiddleware())
+	r.POST("/auth/password/forgot", middleware.RateLimitMiddleware(limiter,
+		middleware.RateLimitByEmail("forgot", rate_limiter.Limit{Requests: 1, Per: time.Hour}),
+	), func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		c.String(http.StatusOK, string(body))
+	})
+
+	send := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/auth/password/forgot", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := send(`{"email":"player@example.com"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `{"email":"player@example.com"}`, w.Body.String())
+
+	w = send(`{"email":" Player@Example.com "}`)
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+
+	w = send(`{"email":"other@example.com"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestRateLimitMiddleware_ByEmailCapsBody(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	limiter := rate_limiter.NewMemoryRateLimiter()
+
+	r := gin.New()
+	r.Use(middleware.ErrorHandlerMiddleware())
+	r.POST("/auth/password/forgot", middleware.RateLimitMiddleware(limiter,
+		middleware.RateLimitByEmail("forgot", rate_limiter.Limit{Requests: 1, Per: time.Hour}),
+	), func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		c.String(http.StatusOK, strconv.Itoa(len(body)))
+	})
+
+	body := `{"email":"player@example.com","padding":"` + strings.Repeat("a", 128<<10) + `"}`
+	req := httptest.NewRequest(http.MethodPost, "/auth/password/forgot", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	// The handler only gets the capped part of the body, so it cannot be used to skip the limit
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, strconv.Itoa(64<<10), w.Body.String())
+}
+
+func TestRateLimitMiddleware_FailsOpen(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger.InitLogger(false)
+
+	r := gin.New()
+	r.Use(middleware.ErrorHandlerMiddleware())
+	r.POST("/auth/login", middleware.RateLimitMiddleware(failingRateLimiter{},
+		middleware.RateLimitByIP("login", rate_limiter.Limit{Requests: 1, Per: time.Minute}),
+	), func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+
+	for i := 0; i < 3; i++ {
+		req := httptest.NewRequest(http.MethodPost, "/auth/login", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+}
+
+type recordingAuditClient struct {
+	events []service_clients.AuditEvent
+}
+
+func (r *recordingAuditClient) RecordEvent(event service_clients.AuditEvent) error {
+	r.events = append(r.events, event)
+	return nil
+}
+
+func auditRouter(client service_clients.AuditClient, handler gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middleware.RequestIDMiddleware())
+	r.Use(middleware.ErrorHandlerMiddleware())
+	r.Use(func(c *gin.Context) {
+		c.Set("includedJWT", true)
+		c.Set("userID", "2b1c6c5e-8f0a-4f8e-9d7b-6a1f3c2d4e5f")
+	})
+	r.PUT("/balances/:id", middleware.AuditMiddleware(client, "payments.gem_balances.update", "user", "id"), handler)
+	return r
+}
+
+func TestAuditMiddleware_RecordsSuccessfulAction(t *testing.T) {
+	client := &recordingAuditClient{}
+	r := auditRouter(client, func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		assert.JSONEq(t, `{"gems":50}`, string(body))
+		c.Status(http.StatusNoContent)
+	})
+
+	req := httptest.NewRequest(http.MethodPut, "/balances/42", strings.NewReader(`{"gems":50}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(middleware.REQUEST_ID_HEADER, "req-123")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "req-123", w.Header().Get(middleware.REQUEST_ID_HEADER))
+	if assert.Len(t, client.events, 1) {
+		event := client.events[0]
+		assert.Equal(t, "2b1c6c5e-8f0a-4f8e-9d7b-6a1f3c2d4e5f", event.ActorId.String())
+		assert.Equal(t, "payments.gem_balances.update", event.Action)
+		assert.Equal(t, "user", event.TargetType)
+		assert.Equal(t, "42", event.TargetId)
+		assert.Nil(t, event.Before)
+		assert.JSONEq(t, `{"gems":50}`, string(event.After))
+		assert.Equal(t, "req-123", event.RequestId)
+		assert.False(t, event.OccurredAt.IsZero())
+	}
+}
+
+func TestAuditMiddleware_UsesChangeSetByHandler(t *testing.T) {
+	client := &recordingAuditClient{}
+	r := auditRouter(client, func(c *gin.Context) {
+		common_handlers.SetAuditChange(c, map[string]int{"gems": 10}, map[string]int{"gems": 50})
+		common_handlers.SetAuditTarget(c, "other")
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodPut, "/balances/42", strings.NewReader(`{"gems":50}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if assert.Len(t, client.events, 1) {
+		assert.Equal(t, "other", client.events[0].TargetId)
+		assert.JSONEq(t, `{"gems":10}`, string(client.events[0].Before))
+		assert.JSONEq(t, `{"gems":50}`, string(client.events[0].After))
+		assert.NotEmpty(t, client.events[0].RequestId)
+	}
+}
+
+func TestAuditMiddleware_SkipsFailedAction(t *testing.T) {
+	client := &recordingAuditClient{}
+	r := auditRouter(client, func(c *gin.Context) {
+		_ = c.Error(errors.NewBadRequestError("invalid gems"))
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/balances/42", nil))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Empty(t, client.events)
+}
+
+func TestRequestIDMiddleware_ReplacesInvalidID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middleware.RequestIDMiddleware())
+	r.GET("/test", func(c *gin.Context) {
+		c.String(http.StatusOK, common_handlers.GetRequestID(c))
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set(middleware.REQUEST_ID_HEADER, "not valid\nid")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	requestID := w.Header().Get(middleware.REQUEST_ID_HEADER)
+	assert.NotEqual(t, "not valid\nid", requestID)
+	assert.Equal(t, requestID, w.Body.String())
+}
@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"github.com/FeedTheRealm-org/core-service/internal/common_handlers"
+	"github.com/FeedTheRealm-org/core-service/internal/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// RequirePermission will check if the session was granted the permission before allowing the endpoint,
+// admins are allowed on every endpoint.
+func RequirePermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := common_handlers.HasPermission(c, permission); err != nil {
+			c.Abort()
+			_ = c.Error(errors.NewForbiddenError(err.Error()))
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const REQUEST_ID_HEADER = "X-Request-ID"
+
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// RequestIDMiddleware keeps the request id sent by a proxy or generates one, the id is
+// returned in the response and stored in the context for logs and audit events.
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(REQUEST_ID_HEADER)
+		if !requestIDPattern.MatchString(requestID) {
+			requestID = uuid.NewString()
+		}
+		c.Set("requestID", requestID)
+		c.Header(REQUEST_ID_HEADER, requestID)
+
+		c.Next()
+	}
+}
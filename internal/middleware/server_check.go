@@ -1,12 +1,15 @@
 package middleware
 
 import (
+	"strconv"
+
 	"github.com/FeedTheRealm-org/core-service/internal/common_handlers"
 	"github.com/FeedTheRealm-org/core-service/internal/errors"
 	"github.com/gin-gonic/gin"
 )
 
-// ServerCheckMiddleware will check if the user is server before allowing swagger endpoints
+// ServerCheckMiddleware only lets game servers through. Servers using a per-zone credential
+// are also limited to the world and zone in the id and zone_id path parameters, when present.
 func ServerCheckMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		if err := common_handlers.IsServerSession(c); err != nil {
@@ -15,6 +18,15 @@ func ServerCheckMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		if worldId, zoneId, scoped := common_handlers.GetServerScope(c); scoped {
+			worldParam, zoneParam := c.Param("id"), c.Param("zone_id")
+			if (worldParam != "" && worldParam != worldId) || (zoneParam != "" && zoneParam != strconv.Itoa(zoneId)) {
+				c.Abort()
+				_ = c.Error(errors.NewForbiddenError("server credential is not valid for this zone"))
+				return
+			}
+		}
+
 		c.Next()
 	}
 }
@@ -0,0 +1,17 @@
+package cosmetic_sales
+
+import "github.com/gin-gonic/gin"
+
+type CosmeticSalesController interface {
+	// GetCreatorSales handles the request to retrieve the sales of the current creator's cosmetics over time.
+	GetCreatorSales(ctx *gin.Context)
+
+	// GetCreatorTopSellers handles the request to retrieve the current creator's best selling cosmetics or worlds.
+	GetCreatorTopSellers(ctx *gin.Context)
+
+	// GetSales handles the request to retrieve the platform-wide sales over time.
+	GetSales(ctx *gin.Context)
+
+	// GetTopSellers handles the request to retrieve the platform-wide best selling cosmetics, worlds or creators.
+	GetTopSellers(ctx *gin.Context)
+}
@@ -0,0 +1,304 @@
+package cosmetic_sales
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/FeedTheRealm-org/core-service/internal/common_handlers"
+	"github.com/FeedTheRealm-org/core-service/internal/errors"
+	"github.com/FeedTheRealm-org/core-service/internal/payment-service/dtos"
+	"github.com/FeedTheRealm-org/core-service/internal/payment-service/models"
+	cosmetic_sales_repo "github.com/FeedTheRealm-org/core-service/internal/payment-service/repositories/cosmetic-sales"
+	cosmetic_sales "github.com/FeedTheRealm-org/core-service/internal/payment-service/services/cosmetic-sales"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/permissions"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type cosmeticSalesController struct {
+	service cosmetic_sales.CosmeticSalesService
+}
+
+func NewCosmeticSalesController(service cosmetic_sales.CosmeticSalesService) CosmeticSalesController {
+	return &cosmeticSalesController{service: service}
+}
+
+// GetCreatorSales godoc
+// @Summary      Get current creator sales
+// @Description  Returns the sales of the authenticated creator's cosmetics per day, week or month.
+// @Tags         payment-service
+// @Security     BearerAuth
+// @Produce      json
+// @Param        interval     query     string  false  "Bucket size (day, week, month)" default(day)
+// @Param        from         query     string  false  "Only sales at or after this date (RFC3339 or YYYY-MM-DD)"
+// @Param        to           query     string  false  "Only sales up to this date (RFC3339 or YYYY-MM-DD, inclusive)"
+// @Param        cosmetic_id  query     string  false  "Only sales of this cosmetic"
+// @Param        bundle_id    query     string  false  "Only sales of this bundle"
+// @Param        world_id     query     string  false  "Only sales of cosmetics from this world"
+// @Success      200          {object}  dtos.CosmeticSalesReportResponse
+// @Failure      400          {object}  dtos.ErrorResponse
+// @Failure      401          {object}  dtos.ErrorResponse
+// @Failure      500          {object}  dtos.ErrorResponse
+// @Router       /payments/sales/creators [get]
+func (c *cosmeticSalesController) GetCreatorSales(ctx *gin.Context) {
+	userId, err := common_handlers.GetUserIDFromSession(ctx)
+	if err != nil {
+		_ = ctx.Error(errors.NewUnauthorizedError(err.Error()))
+		return
+	}
+
+	filter, err := parseSalesFilter(ctx, false)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	filter.CreatorID = &userId
+
+	c.salesReport(ctx, filter)
+}
+
+// GetCreatorTopSellers godoc
+// @Summary      Get current creator top sellers
+// @Description  Returns the authenticated creator's best selling cosmetics or worlds.
+// @Tags         payment-service
+// @Security     BearerAuth
+// @Produce      json
+// @Param        group_by     query     string  false  "Aggregate per cosmetic, bundle or world" default(cosmetic)
+// @Param        from         query     string  false  "Only sales at or after this date (RFC3339 or YYYY-MM-DD)"
+// @Param        to           query     string  false  "Only sales up to this date (RFC3339 or YYYY-MM-DD, inclusive)"
+// @Param        cosmetic_id  query     string  false  "Only sales of this cosmetic"
+// @Param        bundle_id    query     string  false  "Only sales of this bundle"
+// @Param        world_id     query     string  false  "Only sales of cosmetics from this world"
+// @Param        limit        query     int     false  "Limit" default(10)
+// @Success      200          {object}  dtos.CosmeticTopSellersResponse
+// @Failure      400          {object}  dtos.ErrorResponse
+// @Failure      401          {object}  dtos.ErrorResponse
+// @Failure      500          {object}  dtos.ErrorResponse
+// @Router       /payments/sales/creators/top [get]
+func (c *cosmeticSalesController) GetCreatorTopSellers(ctx *gin.Context) {
+	userId, err := common_handlers.GetUserIDFromSession(ctx)
+	if err != nil {
+		_ = ctx.Error(errors.NewUnauthorizedError(err.Error()))
+		return
+	}
+
+	filter, err := parseSalesFilter(ctx, false)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	filter.CreatorID = &userId
+
+	// Creators only rank within their own sales
+	if ctx.Query("group_by") == models.SALES_GROUP_BY_CREATOR {
+		_ = ctx.Error(errors.NewBadRequestError("invalid group_by"))
+		return
+	}
+
+	c.topSellers(ctx, filter)
+}
+
+// GetSales godoc
+// @Summary      Get platform sales
+// @Description  Returns the platform-wide cosmetic sales per day, week or month. Requires payments.sales.read.
+// @Tags         payment-service
+// @Security     BearerAuth
+// @Produce      json
+// @Param        interval     query     string  false  "Bucket size (day, week, month)" default(day)
+// @Param        from         query     string  false  "Only sales at or after this date (RFC3339 or YYYY-MM-DD)"
+// @Param        to           query     string  false  "Only sales up to this date (RFC3339 or YYYY-MM-DD, inclusive)"
+// @Param        cosmetic_id  query     string  false  "Only sales of this cosmetic"
+// @Param        bundle_id    query     string  false  "Only sales of this bundle"
+// @Param        world_id     query     string  false  "Only sales of cosmetics from this world"
+// @Param        creator_id   query     string  false  "Only sales of cosmetics from this creator"
+// @Success      200          {object}  dtos.CosmeticSalesReportResponse
+// @Failure      400          {object}  dtos.ErrorResponse
+// @Failure      401          {object}  dtos.ErrorResponse
+// @Failure      500          {object}  dtos.ErrorResponse
+// @Router       /payments/sales [get]
+func (c *cosmeticSalesController) GetSales(ctx *gin.Context) {
+	if err := common_handlers.HasPermission(ctx, permissions.PAYMENTS_SALES_READ); err != nil {
+		_ = ctx.Error(errors.NewUnauthorizedError(err.Error()))
+		return
+	}
+
+	filter, err := parseSalesFilter(ctx, true)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	c.salesReport(ctx, filter)
+}
+
+// GetTopSellers godoc
+// @Summary      Get platform top sellers
+// @Description  Returns the platform-wide best selling cosmetics, worlds or creators. Requires payments.sales.read.
+// @Tags         payment-service
+// @Security     BearerAuth
+// @Produce      json
+// @Param        group_by     query     string  false  "Aggregate per cosmetic, bundle, world or creator" default(cosmetic)
+// @Param        from         query     string  false  "Only sales at or after this date (RFC3339 or YYYY-MM-DD)"
+// @Param        to           query     string  false  "Only sales up to this date (RFC3339 or YYYY-MM-DD, inclusive)"
+// @Param        cosmetic_id  query     string  false  "Only sales of this cosmetic"
+// @Param        bundle_id    query     string  false  "Only sales of this bundle"
+// @Param        world_id     query     string  false  "Only sales of cosmetics from this world"
+// @Param        creator_id   query     string  false  "Only sales of cosmetics from this creator"
+// @Param        limit        query     int     false  "Limit" default(10)
+// @Success      200          {object}  dtos.CosmeticTopSellersResponse
+// @Failure      400          {object}  dtos.ErrorResponse
+// @Failure      401          {object}  dtos.ErrorResponse
+// @Failure      500          {object}  dtos.ErrorResponse
+// @Router       /payments/sales/top [get]
+func (c *cosmeticSalesController) GetTopSellers(ctx *gin.Context) {
+	if err := common_handlers.HasPermission(ctx, permissions.PAYMENTS_SALES_READ); err != nil {
+		_ = ctx.Error(errors.NewUnauthorizedError(err.Error()))
+		return
+	}
+
+	filter, err := parseSalesFilter(ctx, true)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	c.topSellers(ctx, filter)
+}
+
+/* --- UTILS --- */
+
+func (c *cosmeticSalesController) salesReport(ctx *gin.Context, filter cosmetic_sales_repo.CosmeticSalesFilter) {
+	interval := ctx.DefaultQuery("interval", models.SALES_INTERVAL_DAY)
+	if !models.IsValidSalesInterval(interval) {
+		_ = ctx.Error(errors.NewBadRequestError("invalid interval"))
+		return
+	}
+
+	buckets, totals, err := c.service.GetSalesReport(filter, interval)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	creatorShare, _ := totals.CreatorShare.Float64()
+	res := dtos.CosmeticSalesReportResponse{
+		Interval: interval,
+		Buckets:  make([]dtos.CosmeticSalesBucketResponse, len(buckets)),
+		Totals: dtos.CosmeticSalesTotalsResponse{
+			SalesCount:   totals.SalesCount,
+			GemsRevenue:  totals.GemsRevenue,
+			CreatorShare: creatorShare,
+		},
+	}
+	for i, bucket := range buckets {
+		share, _ := bucket.CreatorShare.Float64()
+		res.Buckets[i] = dtos.CosmeticSalesBucketResponse{
+			PeriodStart:  bucket.PeriodStart,
+			SalesCount:   bucket.SalesCount,
+			GemsRevenue:  bucket.GemsRevenue,
+			CreatorShare: share,
+		}
+	}
+
+	common_handlers.HandleSuccessResponse(ctx, http.StatusOK, res)
+}
+
+func (c *cosmeticSalesController) topSellers(ctx *gin.Context, filter cosmetic_sales_repo.CosmeticSalesFilter) {
+	groupBy := ctx.DefaultQuery("group_by", models.SALES_GROUP_BY_COSMETIC)
+	switch groupBy {
+	case models.SALES_GROUP_BY_COSMETIC, models.SALES_GROUP_BY_BUNDLE, models.SALES_GROUP_BY_WORLD, models.SALES_GROUP_BY_CREATOR:
+	default:
+		_ = ctx.Error(errors.NewBadRequestError("invalid group_by"))
+		return
+	}
+
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", "10"))
+	if err != nil || limit <= 0 || limit > 100 {
+		_ = ctx.Error(errors.NewBadRequestError("invalid limit"))
+		return
+	}
+
+	rankings, err := c.service.GetTopSellers(filter, groupBy, limit)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	res := dtos.CosmeticTopSellersResponse{
+		GroupBy:    groupBy,
+		TopSellers: make([]dtos.CosmeticSalesRankingResponse, len(rankings)),
+	}
+	for i, ranking := range rankings {
+		share, _ := ranking.CreatorShare.Float64()
+		res.TopSellers[i] = dtos.CosmeticSalesRankingResponse{
+			ID:           ranking.ID,
+			SalesCount:   ranking.SalesCount,
+			GemsRevenue:  ranking.GemsRevenue,
+			CreatorShare: share,
+		}
+	}
+
+	common_handlers.HandleSuccessResponse(ctx, http.StatusOK, res)
+}
+
+func parseSalesFilter(ctx *gin.Context, allowCreator bool) (cosmetic_sales_repo.CosmeticSalesFilter, error) {
+	filter := cosmetic_sales_repo.CosmeticSalesFilter{}
+
+	ids := map[string]**uuid.UUID{
+		"cosmetic_id": &filter.CosmeticID,
+		"bundle_id":   &filter.BundleID,
+		"world_id":    &filter.WorldID,
+	}
+	if allowCreator {
+		ids["creator_id"] = &filter.CreatorID
+	}
+	for param, target := range ids {
+		value := ctx.Query(param)
+		if value == "" {
+			continue
+		}
+		parsed, err := uuid.Parse(value)
+		if err != nil {
+			return filter, errors.NewBadRequestError("invalid " + param)
+		}
+		*target = &parsed
+	}
+
+	if from := ctx.Query("from"); from != "" {
+		parsed, _, err := parseSalesDate(from)
+		if err != nil {
+			return filter, errors.NewBadRequestError("invalid from date")
+		}
+		filter.From = parsed
+	}
+
+	if to := ctx.Query("to"); to != "" {
+		parsed, dateOnly, err := parseSalesDate(to)
+		if err != nil {
+			return filter, errors.NewBadRequestError("invalid to date")
+		}
+		// A plain date includes the whole day
+		if dateOnly {
+			parsed = parsed.AddDate(0, 0, 1)
+		} else {
+			parsed = parsed.Add(time.Nanosecond)
+		}
+		filter.To = parsed
+	}
+
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return filter, errors.NewBadRequestError("from date must be before to date")
+	}
+
+	return filter, nil
+}
+
+func parseSalesDate(value string) (time.Time, bool, error) {
+	if parsed, err := time.Parse(time.DateOnly, value); err == nil {
+		return parsed, true, nil
+	}
+
+	parsed, err := time.Parse(time.RFC3339, value)
+	return parsed, false, err
+}
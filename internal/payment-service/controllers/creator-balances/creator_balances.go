@@ -7,6 +7,7 @@ import (
 	"github.com/FeedTheRealm-org/core-service/internal/errors"
 	"github.com/FeedTheRealm-org/core-service/internal/payment-service/dtos"
 	creator_balances "github.com/FeedTheRealm-org/core-service/internal/payment-service/services/creator-balances"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/permissions"
 	"github.com/gin-gonic/gin"
 )
 
@@ -53,7 +54,7 @@ func (c *creatorBalancesController) GetBalance(ctx *gin.Context) {
 
 // GetAllBalances godoc
 // @Summary      List creator balances
-// @Description  Returns all creator balances. Admin only.
+// @Description  Returns all creator balances. Requires payments.balances.read.
 // @Tags         payment-service
 // @Security     BearerAuth
 // @Produce      json
@@ -62,7 +63,7 @@ func (c *creatorBalancesController) GetBalance(ctx *gin.Context) {
 // @Failure      500  {object} dtos.ErrorResponse
 // @Router       /payments/balances/creators/all [get]
 func (c *creatorBalancesController) GetAllBalances(ctx *gin.Context) {
-	if err := common_handlers.IsAdminSession(ctx); err != nil {
+	if err := common_handlers.HasPermission(ctx, permissions.PAYMENTS_BALANCES_READ); err != nil {
 		_ = ctx.Error(errors.NewUnauthorizedError(err.Error()))
 		return
 	}
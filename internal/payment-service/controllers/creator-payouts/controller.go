@@ -0,0 +1,26 @@
+package creator_payouts
+
+import "github.com/gin-gonic/gin"
+
+type CreatorPayoutsController interface {
+	// SetPayoutAccount handles the request to set the account the creator is paid to.
+	SetPayoutAccount(ctx *gin.Context)
+
+	// RequestPayout handles the request of a creator to withdraw part of their balance.
+	RequestPayout(ctx *gin.Context)
+
+	// GetPayouts handles the request to list the current creator's payouts.
+	GetPayouts(ctx *gin.Context)
+
+	// GetAllPayouts handles the request to list every payout, used as the admin approval queue.
+	GetAllPayouts(ctx *gin.Context)
+
+	// GetPayout handles the request to retrieve a payout with its history.
+	GetPayout(ctx *gin.Context)
+
+	// ApprovePayout handles the request to approve and pay a payout.
+	ApprovePayout(ctx *gin.Context)
+
+	// RejectPayout handles the request to reject a payout.
+	RejectPayout(ctx *gin.Context)
+}
@@ -0,0 +1,351 @@
+package creator_payouts
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/FeedTheRealm-org/core-service/internal/common_handlers"
+	"github.com/FeedTheRealm-org/core-service/internal/errors"
+	"github.com/FeedTheRealm-org/core-service/internal/payment-service/dtos"
+	payment_errors "github.com/FeedTheRealm-org/core-service/internal/payment-service/errors"
+	"github.com/FeedTheRealm-org/core-service/internal/payment-service/models"
+	creator_payouts_repo "github.com/FeedTheRealm-org/core-service/internal/payment-service/repositories/creator-payouts"
+	creator_payouts "github.com/FeedTheRealm-org/core-service/internal/payment-service/services/creator-payouts"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/permissions"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type creatorPayoutsController struct {
+	service creator_payouts.CreatorPayoutsService
+}
+
+func NewCreatorPayoutsController(service creator_payouts.CreatorPayoutsService) CreatorPayoutsController {
+	return &creatorPayoutsController{service: service}
+}
+
+// SetPayoutAccount godoc
+// @Summary      Set creator payout account
+// @Description  Sets the connected account the authenticated creator's payouts are sent to.
+// @Tags         payment-service
+// @Security     BearerAuth
+// @Accept       json
+// @Param        request  body      dtos.SetPayoutAccountRequest  true  "Payout account"
+// @Success      204      {string}  string "No Content"
+// @Failure      400      {object}  dtos.ErrorResponse
+// @Failure      401      {object}  dtos.ErrorResponse
+// @Failure      500      {object}  dtos.ErrorResponse
+// @Router       /payments/balances/creators/payouts/account [put]
+func (c *creatorPayoutsController) SetPayoutAccount(ctx *gin.Context) {
+	userId, err := common_handlers.GetUserIDFromSession(ctx)
+	if err != nil {
+		_ = ctx.Error(errors.NewUnauthorizedError(err.Error()))
+		return
+	}
+
+	req := dtos.SetPayoutAccountRequest{}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		_ = ctx.Error(errors.NewBadRequestError("invalid request body: " + err.Error()))
+		return
+	}
+
+	if err := c.service.SetPayoutAccount(userId, req.AccountID); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	common_handlers.HandleBodilessResponse(ctx, http.StatusNoContent)
+}
+
+// RequestPayout godoc
+// @Summary      Request a creator payout
+// @Description  Holds the requested amount from the creator balance until an admin reviews the payout.
+// @Tags         payment-service
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        request  body      dtos.CreatePayoutRequest  true  "Payout amount"
+// @Success      201      {object}  dtos.CreatorPayoutResponse
+// @Failure      400      {object}  dtos.ErrorResponse
+// @Failure      401      {object}  dtos.ErrorResponse
+// @Failure      409      {object}  dtos.ErrorResponse
+// @Failure      500      {object}  dtos.ErrorResponse
+// @Router       /payments/balances/creators/payouts [post]
+func (c *creatorPayoutsController) RequestPayout(ctx *gin.Context) {
+	userId, err := common_handlers.GetUserIDFromSession(ctx)
+	if err != nil {
+		_ = ctx.Error(errors.NewUnauthorizedError(err.Error()))
+		return
+	}
+
+	req := dtos.CreatePayoutRequest{}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		_ = ctx.Error(errors.NewBadRequestError("invalid request body: " + err.Error()))
+		return
+	}
+
+	amount := decimal.NewFromFloat(req.Amount)
+	if !amount.Equal(amount.Round(2)) {
+		_ = ctx.Error(errors.NewBadRequestError("amount can have at most two decimals"))
+		return
+	}
+
+	payout, err := c.service.RequestPayout(userId, amount)
+	if err != nil {
+		_ = ctx.Error(mapPayoutError(err))
+		return
+	}
+
+	common_handlers.HandleSuccessResponse(ctx, http.StatusCreated, toPayoutResponse(payout))
+}
+
+// GetPayouts godoc
+// @Summary      List current creator payouts
+// @Description  Returns the authenticated creator's payouts, oldest first.
+// @Tags         payment-service
+// @Security     BearerAuth
+// @Produce      json
+// @Param        status  query     string  false  "Filter by status (requested, approved, paid, rejected)"
+// @Param        offset  query     int     false  "Offset" default(0)
+// @Param        limit   query     int     false  "Limit" default(50)
+// @Success      200     {object}  dtos.CreatorPayoutsListResponse
+// @Failure      400     {object}  dtos.ErrorResponse
+// @Failure      401     {object}  dtos.ErrorResponse
+// @Failure      500     {object}  dtos.ErrorResponse
+// @Router       /payments/balances/creators/payouts [get]
+func (c *creatorPayoutsController) GetPayouts(ctx *gin.Context) {
+	userId, err := common_handlers.GetUserIDFromSession(ctx)
+	if err != nil {
+		_ = ctx.Error(errors.NewUnauthorizedError(err.Error()))
+		return
+	}
+
+	c.listPayouts(ctx, &userId)
+}
+
+// GetAllPayouts godoc
+// @Summary      List all creator payouts
+// @Description  Returns every payout, oldest first. Filter by status=requested for the approval queue. Requires payments.payouts.read.
+// @Tags         payment-service
+// @Security     BearerAuth
+// @Produce      json
+// @Param        status  query     string  false  "Filter by status (requested, approved, paid, rejected)"
+// @Param        offset  query     int     false  "Offset" default(0)
+// @Param        limit   query     int     false  "Limit" default(50)
+// @Success      200     {object}  dtos.CreatorPayoutsListResponse
+// @Failure      400     {object}  dtos.ErrorResponse
+// @Failure      401     {object}  dtos.ErrorResponse
+// @Failure      500     {object}  dtos.ErrorResponse
+// @Router       /payments/balances/creators/payouts/all [get]
+func (c *creatorPayoutsController) GetAllPayouts(ctx *gin.Context) {
+	if err := common_handlers.HasPermission(ctx, permissions.PAYMENTS_PAYOUTS_READ); err != nil {
+		_ = ctx.Error(errors.NewUnauthorizedError(err.Error()))
+		return
+	}
+
+	c.listPayouts(ctx, nil)
+}
+
+// GetPayout godoc
+// @Summary      Get a creator payout
+// @Description  Returns a payout with its status history. Only its creator or an admin can see it.
+// @Tags         payment-service
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      string  true  "Payout ID"
+// @Success      200  {object}  dtos.CreatorPayoutDetailResponse
+// @Failure      400  {object}  dtos.ErrorResponse
+// @Failure      401  {object}  dtos.ErrorResponse
+// @Failure      404  {object}  dtos.ErrorResponse
+// @Failure      500  {object}  dtos.ErrorResponse
+// @Router       /payments/balances/creators/payouts/{id} [get]
+func (c *creatorPayoutsController) GetPayout(ctx *gin.Context) {
+	userId, err := common_handlers.GetUserIDFromSession(ctx)
+	if err != nil {
+		_ = ctx.Error(errors.NewUnauthorizedError(err.Error()))
+		return
+	}
+
+	payoutId, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		_ = ctx.Error(errors.NewBadRequestError("invalid payout id: " + err.Error()))
+		return
+	}
+
+	payout, history, err := c.service.GetPayout(payoutId)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	// Other creators get the same answer as for a missing payout
+	if payout.UserID != userId && common_handlers.HasPermission(ctx, permissions.PAYMENTS_PAYOUTS_READ) != nil {
+		_ = ctx.Error(errors.NewNotFoundError("payout not found"))
+		return
+	}
+
+	res := dtos.CreatorPayoutDetailResponse{
+		CreatorPayoutResponse: toPayoutResponse(payout),
+		History:               make([]dtos.CreatorPayoutEventResponse, len(history)),
+	}
+	for i, event := range history {
+		res.History[i] = dtos.CreatorPayoutEventResponse{
+			Status:    event.Status,
+			ActorID:   event.ActorID,
+			Note:      event.Note,
+			CreatedAt: event.CreatedAt,
+		}
+	}
+
+	common_handlers.HandleSuccessResponse(ctx, http.StatusOK, res)
+}
+
+// ApprovePayout godoc
+// @Summary      Approve a creator payout
+// @Description  Approves a requested payout and transfers it to the creator. Retrying an approved payout retries the transfer. Requires payments.payouts.write.
+// @Tags         payment-service
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      string  true  "Payout ID"
+// @Success      200  {object}  dtos.CreatorPayoutResponse
+// @Failure      400  {object}  dtos.ErrorResponse
+// @Failure      401  {object}  dtos.ErrorResponse
+// @Failure      404  {object}  dtos.ErrorResponse
+// @Failure      409  {object}  dtos.ErrorResponse
+// @Failure      502  {object}  dtos.ErrorResponse
+// @Failure      500  {object}  dtos.ErrorResponse
+// @Router       /payments/balances/creators/payouts/{id}/approve [post]
+func (c *creatorPayoutsController) ApprovePayout(ctx *gin.Context) {
+	adminId, err := common_handlers.GetUserIDFromSession(ctx)
+	if err != nil {
+		_ = ctx.Error(errors.NewUnauthorizedError(err.Error()))
+		return
+	}
+
+	payoutId, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		_ = ctx.Error(errors.NewBadRequestError("invalid payout id: " + err.Error()))
+		return
+	}
+
+	payout, err := c.service.ApprovePayout(payoutId, adminId)
+	if err != nil {
+		_ = ctx.Error(mapPayoutError(err))
+		return
+	}
+
+	common_handlers.HandleSuccessResponse(ctx, http.StatusOK, toPayoutResponse(payout))
+}
+
+// RejectPayout godoc
+// @Summary      Reject a creator payout
+// @Description  Rejects a requested payout and returns the held amount to the creator balance. An approved payout is checked with the payout provider first: it is marked as paid when the transfer went through (409) and as failed, returning the hold, otherwise. Requires payments.payouts.write.
+// @Tags         payment-service
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                    true  "Payout ID"
+// @Param        request  body      dtos.RejectPayoutRequest  true  "Rejection reason"
+// @Success      200      {object}  dtos.CreatorPayoutResponse
+// @Failure      400      {object}  dtos.ErrorResponse
+// @Failure      401      {object}  dtos.ErrorResponse
+// @Failure      404      {object}  dtos.ErrorResponse
+// @Failure      409      {object}  dtos.ErrorResponse
+// @Failure      500      {object}  dtos.ErrorResponse
+// @Failure      502      {object}  dtos.ErrorResponse
+// @Router       /payments/balances/creators/payouts/{id}/reject [post]
+func (c *creatorPayoutsController) RejectPayout(ctx *gin.Context) {
+	adminId, err := common_handlers.GetUserIDFromSession(ctx)
+	if err != nil {
+		_ = ctx.Error(errors.NewUnauthorizedError(err.Error()))
+		return
+	}
+
+	payoutId, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		_ = ctx.Error(errors.NewBadRequestError("invalid payout id: " + err.Error()))
+		return
+	}
+
+	req := dtos.RejectPayoutRequest{}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		_ = ctx.Error(errors.NewBadRequestError("invalid request body: " + err.Error()))
+		return
+	}
+
+	payout, err := c.service.RejectPayout(payoutId, adminId, req.Reason)
+	if err != nil {
+		_ = ctx.Error(mapPayoutError(err))
+		return
+	}
+
+	common_handlers.HandleSuccessResponse(ctx, http.StatusOK, toPayoutResponse(payout))
+}
+
+/* --- UTILS --- */
+
+func (c *creatorPayoutsController) listPayouts(ctx *gin.Context, userId *uuid.UUID) {
+	filter := creator_payouts_repo.CreatorPayoutsFilter{UserID: userId, Status: ctx.Query("status")}
+	switch filter.Status {
+	case "", models.CREATOR_PAYOUT_REQUESTED, models.CREATOR_PAYOUT_APPROVED, models.CREATOR_PAYOUT_PAID, models.CREATOR_PAYOUT_REJECTED, models.CREATOR_PAYOUT_FAILED:
+	default:
+		_ = ctx.Error(errors.NewBadRequestError("invalid status filter"))
+		return
+	}
+
+	offset, err := strconv.Atoi(ctx.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		_ = ctx.Error(errors.NewBadRequestError("invalid offset"))
+		return
+	}
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", "50"))
+	if err != nil || limit <= 0 || limit > 200 {
+		_ = ctx.Error(errors.NewBadRequestError("invalid limit"))
+		return
+	}
+
+	payouts, total, err := c.service.GetPayouts(filter, offset, limit)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	res := dtos.CreatorPayoutsListResponse{
+		Payouts:    make([]dtos.CreatorPayoutResponse, len(payouts)),
+		TotalCount: total,
+	}
+	for i, payout := range payouts {
+		res.Payouts[i] = toPayoutResponse(payout)
+	}
+
+	common_handlers.HandleSuccessResponse(ctx, http.StatusOK, res)
+}
+
+func mapPayoutError(err error) error {
+	switch err.(type) {
+	case *payment_errors.PayoutBelowMinimum, *payment_errors.InsufficientCreatorBalance, *payment_errors.PayoutAccountMissing:
+		return errors.NewBadRequestError(err.Error())
+	case *payment_errors.PayoutAlreadyPending, *payment_errors.InvalidPayoutTransition:
+		return errors.NewConflictError(err.Error())
+	case *payment_errors.PayoutProviderError:
+		return &errors.HttpError{Status: http.StatusBadGateway, Message: err.Error()}
+	default:
+		return err
+	}
+}
+
+func toPayoutResponse(payout *models.CreatorPayout) dtos.CreatorPayoutResponse {
+	amount, _ := payout.Amount.Float64()
+	return dtos.CreatorPayoutResponse{
+		ID:                payout.ID,
+		UserID:            payout.UserID,
+		Amount:            amount,
+		Status:            payout.Status,
+		PayoutAccountID:   payout.PayoutAccountID,
+		ProviderReference: payout.ProviderReference,
+		RejectionReason:   payout.RejectionReason,
+		CreatedAt:         payout.CreatedAt,
+		UpdatedAt:         payout.UpdatedAt,
+	}
+}
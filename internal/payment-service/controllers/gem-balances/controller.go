@@ -12,9 +12,21 @@ type GemBalancesController interface {
 	// UpdateGemBalance handles the request to update a specific user's gem balance.
 	UpdateGemBalance(ctx *gin.Context)
 
+	// GetGemTransactions handles the request to retrieve the current user's gem transaction history.
+	GetGemTransactions(ctx *gin.Context)
+
+	// GetUserGemTransactions handles the request to retrieve a specific user's gem transaction history.
+	GetUserGemTransactions(ctx *gin.Context)
+
 	// PurchaseCosmetic handles the request to purchase a cosmetic item using gems.
 	PurchaseCosmetic(ctx *gin.Context)
 
+	// PurchaseBundle handles the request to purchase a bundle of cosmetics using gems.
+	PurchaseBundle(ctx *gin.Context)
+
+	// GiftCosmetic handles the request to buy a cosmetic for another player using gems.
+	GiftCosmetic(ctx *gin.Context)
+
 	// CreateCheckoutSession handles the request to create a checkout session for purchasing gems.
 	CreateCheckoutSession(ctx *gin.Context)
 
@@ -3,13 +3,19 @@ package gem_balances
 import (
 	"io"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/FeedTheRealm-org/core-service/config"
 	"github.com/FeedTheRealm-org/core-service/internal/common_handlers"
 	"github.com/FeedTheRealm-org/core-service/internal/errors"
 	"github.com/FeedTheRealm-org/core-service/internal/payment-service/dtos"
 	gem_balances_errors "github.com/FeedTheRealm-org/core-service/internal/payment-service/errors"
+	"github.com/FeedTheRealm-org/core-service/internal/payment-service/models"
+	gem_transactions "github.com/FeedTheRealm-org/core-service/internal/payment-service/repositories/gem-transactions"
 	gem_balances "github.com/FeedTheRealm-org/core-service/internal/payment-service/services/gem-balances"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/permissions"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
@@ -28,7 +34,7 @@ func NewGemBalancesController(conf *config.Config, gemBalanceService gem_balance
 
 // GetAllGemBalances godoc
 // @Summary      List all gem balances
-// @Description  Returns all gem balances. Admin only.
+// @Description  Returns all gem balances. Requires payments.balances.read.
 // @Tags         payment-service
 // @Security     BearerAuth
 // @Produce      json
@@ -42,7 +48,7 @@ func (bc *gemBalancesController) GetAllGemBalances(c *gin.Context) {
 		return
 	}
 
-	if err := common_handlers.IsAdminSession(c); err != nil {
+	if err := common_handlers.HasPermission(c, permissions.PAYMENTS_BALANCES_READ); err != nil {
 		_ = c.Error(errors.NewUnauthorizedError(err.Error()))
 		return
 	}
@@ -100,7 +106,7 @@ func (bc *gemBalancesController) GetGemBalanceByUserId(c *gin.Context) {
 
 // UpdateGemBalance godoc
 // @Summary      Update a user's gem balance
-// @Description  Updates gem balance for a user. Admin only.
+// @Description  Updates gem balance for a user. Requires payments.balances.write.
 // @Tags         payment-service
 // @Security     BearerAuth
 // @Accept       json
@@ -113,6 +119,12 @@ func (bc *gemBalancesController) GetGemBalanceByUserId(c *gin.Context) {
 // @Failure      500      {object}  dtos.ErrorResponse
 // @Router       /payments/gems/balances/{id} [put]
 func (bc *gemBalancesController) UpdateGemBalance(c *gin.Context) {
+	adminId, err := common_handlers.GetUserIDFromSession(c)
+	if err != nil {
+		_ = c.Error(errors.NewUnauthorizedError(err.Error()))
+		return
+	}
+
 	userId, err := uuid.Parse(c.Param("id"))
 	if err != nil {
 		_ = c.Error(errors.NewBadRequestError("invalid user_id: " + err.Error()))
@@ -125,7 +137,12 @@ func (bc *gemBalancesController) UpdateGemBalance(c *gin.Context) {
 		return
 	}
 
-	if err := bc.gemBalanceService.UpdateGemBalance(userId, req.Gems); err != nil {
+	var before any
+	if previous, err := bc.gemBalanceService.GetGemBalanceByUserId(userId); err == nil {
+		before = &dtos.GemBalanceResponse{UserId: previous.UserId, Gems: previous.Gems}
+	}
+
+	if err := bc.gemBalanceService.UpdateGemBalance(userId, req.Gems, adminId); err != nil {
 		_ = c.Error(err)
 		return
 	}
@@ -141,6 +158,113 @@ func (bc *gemBalancesController) UpdateGemBalance(c *gin.Context) {
 		Gems:   balance.Gems,
 	}
 
+	common_handlers.SetAuditChange(c, before, res)
+	common_handlers.HandleSuccessResponse(c, 200, res)
+}
+
+// GetGemTransactions godoc
+// @Summary      List current user gem transactions
+// @Description  Returns the authenticated user's gem ledger, newest first.
+// @Tags         payment-service
+// @Security     BearerAuth
+// @Produce      json
+// @Param        type    query     string  false  "Filter by type (stripe_credit, purchase, admin_adjustment, refund)"
+// @Param        from    query     string  false  "Only transactions at or after this date (RFC3339 or YYYY-MM-DD)"
+// @Param        to      query     string  false  "Only transactions up to this date (RFC3339 or YYYY-MM-DD, inclusive)"
+// @Param        offset  query     int     false  "Offset" default(0)
+// @Param        limit   query     int     false  "Limit" default(50)
+// @Success      200     {object}  dtos.GemTransactionsListResponse
+// @Failure      400     {object}  dtos.ErrorResponse
+// @Failure      401     {object}  dtos.ErrorResponse
+// @Failure      500     {object}  dtos.ErrorResponse
+// @Router       /payments/balances/transactions [get]
+func (bc *gemBalancesController) GetGemTransactions(c *gin.Context) {
+	userId, err := common_handlers.GetUserIDFromSession(c)
+	if err != nil {
+		_ = c.Error(errors.NewUnauthorizedError(err.Error()))
+		return
+	}
+
+	filter, offset, limit, err := parseGemTransactionsQuery(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	transactions, total, err := bc.gemBalanceService.GetGemTransactions(userId, filter, offset, limit)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	res := &dtos.GemTransactionsListResponse{
+		Transactions: toGemTransactionResponses(transactions),
+		TotalCount:   total,
+	}
+
+	common_handlers.HandleSuccessResponse(c, 200, res)
+}
+
+// GetUserGemTransactions godoc
+// @Summary      List a user's gem transactions
+// @Description  Returns a user's gem ledger along with how it reconciles with the stored balance. Requires payments.balances.read.
+// @Tags         payment-service
+// @Security     BearerAuth
+// @Produce      json
+// @Param        user_id  path      string  true   "User ID"
+// @Param        type     query     string  false  "Filter by type (stripe_credit, purchase, admin_adjustment, refund)"
+// @Param        from     query     string  false  "Only transactions at or after this date (RFC3339 or YYYY-MM-DD)"
+// @Param        to       query     string  false  "Only transactions up to this date (RFC3339 or YYYY-MM-DD, inclusive)"
+// @Param        offset   query     int     false  "Offset" default(0)
+// @Param        limit    query     int     false  "Limit" default(50)
+// @Success      200      {object}  dtos.AdminGemTransactionsListResponse
+// @Failure      400      {object}  dtos.ErrorResponse
+// @Failure      401      {object}  dtos.ErrorResponse
+// @Failure      500      {object}  dtos.ErrorResponse
+// @Router       /payments/balances/transactions/{user_id} [get]
+func (bc *gemBalancesController) GetUserGemTransactions(c *gin.Context) {
+	userId, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		_ = c.Error(errors.NewBadRequestError("invalid user_id: " + err.Error()))
+		return
+	}
+
+	filter, offset, limit, err := parseGemTransactionsQuery(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	transactions, total, err := bc.gemBalanceService.GetGemTransactions(userId, filter, offset, limit)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	ledgerGems, err := bc.gemBalanceService.GetLedgerBalance(userId)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	// Users that never held gems have no balance row, which reconciles with an empty ledger
+	var gems int64
+	if balance, err := bc.gemBalanceService.GetGemBalanceByUserId(userId); err == nil {
+		gems = balance.Gems
+	} else if httpErr, ok := err.(*errors.HttpError); !ok || httpErr.Status != http.StatusNotFound {
+		_ = c.Error(err)
+		return
+	}
+
+	res := &dtos.AdminGemTransactionsListResponse{
+		UserId:       userId,
+		Gems:         gems,
+		LedgerGems:   ledgerGems,
+		Reconciled:   gems == ledgerGems,
+		Transactions: toGemTransactionResponses(transactions),
+		TotalCount:   total,
+	}
+
 	common_handlers.HandleSuccessResponse(c, 200, res)
 }
 
@@ -155,6 +279,7 @@ func (bc *gemBalancesController) UpdateGemBalance(c *gin.Context) {
 // @Success      200  {object}  dtos.GemBalanceResponse
 // @Failure      400  {object}  dtos.ErrorResponse
 // @Failure      401  {object}  dtos.ErrorResponse
+// @Failure      403  {object}  dtos.ErrorResponse
 // @Failure      404  {object}  dtos.ErrorResponse
 // @Failure      409  {object}  dtos.ErrorResponse
 // @Failure      500  {object}  dtos.ErrorResponse
@@ -180,6 +305,8 @@ func (bc *gemBalancesController) PurchaseCosmetic(c *gin.Context) {
 			_ = c.Error(errors.NewNotFoundError(err.Error()))
 		case *gem_balances_errors.CosmeticAlreadyPurchased:
 			_ = c.Error(errors.NewConflictError(err.Error()))
+		case *gem_balances_errors.GemBalanceLocked:
+			_ = c.Error(errors.NewForbiddenError(err.Error()))
 		default:
 			_ = c.Error(err)
 		}
@@ -200,6 +327,141 @@ func (bc *gemBalancesController) PurchaseCosmetic(c *gin.Context) {
 	common_handlers.HandleSuccessResponse(c, 200, res)
 }
 
+// PurchaseBundle godoc
+// @Summary      Purchase a bundle
+// @Description  Deducts the bundle price, with any active promotion applied, and grants every cosmetic of the bundle the player does not own yet.
+// @Tags         payment-service
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        bundle_id path string true "Bundle UUID"
+// @Success      200  {object}  dtos.BundlePurchaseResponse
+// @Failure      400  {object}  dtos.ErrorResponse
+// @Failure      401  {object}  dtos.ErrorResponse
+// @Failure      403  {object}  dtos.ErrorResponse
+// @Failure      404  {object}  dtos.ErrorResponse
+// @Failure      409  {object}  dtos.ErrorResponse
+// @Failure      500  {object}  dtos.ErrorResponse
+// @Router       /payments/balances/purchase/bundles/{bundle_id} [post]
+func (bc *gemBalancesController) PurchaseBundle(c *gin.Context) {
+	userId, err := common_handlers.GetUserIDFromSession(c)
+	if err != nil {
+		_ = c.Error(errors.NewUnauthorizedError(err.Error()))
+		return
+	}
+
+	bundleId, err := uuid.Parse(c.Param("bundle_id"))
+	if err != nil {
+		_ = c.Error(errors.NewBadRequestError("invalid bundle_id: " + err.Error()))
+		return
+	}
+
+	granted, err := bc.gemBalanceService.PurchaseBundle(userId, bundleId)
+	if err != nil {
+		switch err.(type) {
+		case *gem_balances_errors.InsufficientGems:
+			_ = c.Error(errors.NewBadRequestError(err.Error()))
+		case *gem_balances_errors.BundleNotFound:
+			_ = c.Error(errors.NewNotFoundError(err.Error()))
+		case *gem_balances_errors.CosmeticAlreadyPurchased:
+			_ = c.Error(errors.NewConflictError(err.Error()))
+		case *gem_balances_errors.GemBalanceLocked:
+			_ = c.Error(errors.NewForbiddenError(err.Error()))
+		default:
+			_ = c.Error(err)
+		}
+		return
+	}
+
+	balance, err := bc.gemBalanceService.GetGemBalanceByUserId(userId)
+	if err != nil {
+		_ = c.Error(errors.NewInternalServerError("failed to retrieve updated balance: " + err.Error()))
+		return
+	}
+
+	res := &dtos.BundlePurchaseResponse{
+		UserId:             balance.UserId,
+		Gems:               balance.Gems,
+		GrantedCosmeticIds: granted,
+	}
+
+	common_handlers.HandleSuccessResponse(c, 200, res)
+}
+
+// GiftCosmetic godoc
+// @Summary      Gift a cosmetic
+// @Description  Deducts the cosmetic price from the buyer and grants it to the player with the given character name, who is notified by email. Gifts per day are limited.
+// @Tags         payment-service
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        cosmetic_id path string true "Cosmetic UUID"
+// @Param        request body dtos.GiftCosmeticRequest true "Gift recipient and message"
+// @Success      200  {object}  dtos.GiftCosmeticResponse
+// @Failure      400  {object}  dtos.ErrorResponse
+// @Failure      401  {object}  dtos.ErrorResponse
+// @Failure      403  {object}  dtos.ErrorResponse
+// @Failure      404  {object}  dtos.ErrorResponse
+// @Failure      409  {object}  dtos.ErrorResponse
+// @Failure      429  {object}  dtos.ErrorResponse
+// @Failure      500  {object}  dtos.ErrorResponse
+// @Router       /payments/balances/gift/{cosmetic_id} [post]
+func (bc *gemBalancesController) GiftCosmetic(c *gin.Context) {
+	userId, err := common_handlers.GetUserIDFromSession(c)
+	if err != nil {
+		_ = c.Error(errors.NewUnauthorizedError(err.Error()))
+		return
+	}
+
+	cosmeticId, err := uuid.Parse(c.Param("cosmetic_id"))
+	if err != nil {
+		_ = c.Error(errors.NewBadRequestError("invalid cosmetic_id: " + err.Error()))
+		return
+	}
+
+	req := dtos.GiftCosmeticRequest{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(errors.NewBadRequestError("invalid request body: " + err.Error()))
+		return
+	}
+
+	purchase, err := bc.gemBalanceService.GiftCosmetic(userId, cosmeticId, req.CharacterName, req.Message)
+	if err != nil {
+		switch err.(type) {
+		case *gem_balances_errors.InsufficientGems, *gem_balances_errors.InvalidGift:
+			_ = c.Error(errors.NewBadRequestError(err.Error()))
+		case *gem_balances_errors.CosmeticNotFound, *gem_balances_errors.RecipientNotFound:
+			_ = c.Error(errors.NewNotFoundError(err.Error()))
+		case *gem_balances_errors.CosmeticAlreadyPurchased:
+			_ = c.Error(errors.NewConflictError(err.Error()))
+		case *gem_balances_errors.GemBalanceLocked:
+			_ = c.Error(errors.NewForbiddenError(err.Error()))
+		case *gem_balances_errors.GiftLimitReached:
+			_ = c.Error(errors.NewTooManyRequestsError(err.Error()))
+		default:
+			_ = c.Error(err)
+		}
+		return
+	}
+
+	balance, err := bc.gemBalanceService.GetGemBalanceByUserId(userId)
+	if err != nil {
+		_ = c.Error(errors.NewInternalServerError("failed to retrieve updated balance: " + err.Error()))
+		return
+	}
+
+	res := &dtos.GiftCosmeticResponse{
+		UserId:        balance.UserId,
+		Gems:          balance.Gems,
+		CosmeticId:    cosmeticId,
+		RecipientId:   *purchase.RecipientID,
+		CharacterName: strings.TrimSpace(req.CharacterName),
+		Message:       purchase.GiftMessage,
+	}
+
+	common_handlers.HandleSuccessResponse(c, 200, res)
+}
+
 // CreateCheckoutSession godoc
 // @Summary      Create checkout session
 // @Description  Creates a checkout session URL to purchase a gem pack.
@@ -247,7 +509,7 @@ func (bc *gemBalancesController) CreateCheckoutSession(c *gin.Context) {
 
 // HandleStripeWebhook godoc
 // @Summary      Handle Stripe webhook
-// @Description  Processes Stripe webhook events for completed payments.
+// @Description  Processes Stripe webhook events for completed, refunded and disputed payments.
 // @Tags         payment-service
 // @Accept       json
 // @Produce      json
@@ -277,3 +539,72 @@ func (bc *gemBalancesController) HandleStripeWebhook(c *gin.Context) {
 
 	common_handlers.HandleSuccessResponse(c, 200, &dtos.WebhookResponse{})
 }
+
+/* --- UTILS --- */
+
+func parseGemTransactionsQuery(c *gin.Context) (gem_transactions.GemTransactionsFilter, int, int, error) {
+	filter := gem_transactions.GemTransactionsFilter{Type: c.Query("type")}
+
+	if filter.Type != "" && !models.IsValidGemTransactionType(filter.Type) {
+		return filter, 0, 0, errors.NewBadRequestError("invalid type filter")
+	}
+
+	if from := c.Query("from"); from != "" {
+		parsed, _, err := parseGemTransactionsDate(from)
+		if err != nil {
+			return filter, 0, 0, errors.NewBadRequestError("invalid from date")
+		}
+		filter.From = parsed
+	}
+
+	if to := c.Query("to"); to != "" {
+		parsed, dateOnly, err := parseGemTransactionsDate(to)
+		if err != nil {
+			return filter, 0, 0, errors.NewBadRequestError("invalid to date")
+		}
+		// A plain date includes the whole day
+		if dateOnly {
+			parsed = parsed.AddDate(0, 0, 1)
+		} else {
+			parsed = parsed.Add(time.Nanosecond)
+		}
+		filter.To = parsed
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		return filter, 0, 0, errors.NewBadRequestError("invalid offset")
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit <= 0 || limit > 200 {
+		return filter, 0, 0, errors.NewBadRequestError("invalid limit")
+	}
+
+	return filter, offset, limit, nil
+}
+
+func parseGemTransactionsDate(value string) (time.Time, bool, error) {
+	if parsed, err := time.Parse(time.DateOnly, value); err == nil {
+		return parsed, true, nil
+	}
+
+	parsed, err := time.Parse(time.RFC3339, value)
+	return parsed, false, err
+}
+
+func toGemTransactionResponses(transactions []*models.GemTransaction) []dtos.GemTransactionResponse {
+	res := make([]dtos.GemTransactionResponse, len(transactions))
+	for i, transaction := range transactions {
+		res[i] = dtos.GemTransactionResponse{
+			ID:          transaction.ID,
+			Type:        transaction.Type,
+			Amount:      transaction.Amount,
+			ReferenceID: transaction.ReferenceID,
+			ActorID:     transaction.ActorID,
+			CreatedAt:   transaction.CreatedAt,
+		}
+	}
+
+	return res
+}
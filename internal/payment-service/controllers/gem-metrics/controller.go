@@ -5,4 +5,10 @@ import "github.com/gin-gonic/gin"
 type GemMetricsController interface {
 	// GetMetrics handles the request to retrieve gem metrics.
 	GetMetrics(ctx *gin.Context)
+
+	// GetMetricsHistory handles the request to retrieve gem metrics per day, week or month.
+	GetMetricsHistory(ctx *gin.Context)
+
+	// ExportMetricsHistory handles the request to download gem metrics per day, week or month as CSV.
+	ExportMetricsHistory(ctx *gin.Context)
 }
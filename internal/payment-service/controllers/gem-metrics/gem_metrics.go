@@ -1,12 +1,20 @@
 package gem_metrics
 
 import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/FeedTheRealm-org/core-service/internal/common_handlers"
 	"github.com/FeedTheRealm-org/core-service/internal/errors"
 	"github.com/FeedTheRealm-org/core-service/internal/payment-service/dtos"
+	payment_errors "github.com/FeedTheRealm-org/core-service/internal/payment-service/errors"
+	"github.com/FeedTheRealm-org/core-service/internal/payment-service/models"
 	gem_metrics "github.com/FeedTheRealm-org/core-service/internal/payment-service/services/gem-metrics"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/permissions"
 	"github.com/gin-gonic/gin"
 )
 
@@ -20,7 +28,7 @@ func NewGemMetricsController(service gem_metrics.GemMetricsService) GemMetricsCo
 
 // GetMetrics godoc
 // @Summary      Get gem metrics
-// @Description  Returns aggregated gem metrics. Admin only.
+// @Description  Returns lifetime gem metrics. Requires payments.metrics.read.
 // @Tags         payment-service
 // @Security     BearerAuth
 // @Produce      json
@@ -29,7 +37,7 @@ func NewGemMetricsController(service gem_metrics.GemMetricsService) GemMetricsCo
 // @Failure      500  {object}  dtos.ErrorResponse
 // @Router       /payments/gems/metrics [get]
 func (c *gemMetricsController) GetMetrics(ctx *gin.Context) {
-	if err := common_handlers.IsAdminSession(ctx); err != nil {
+	if err := common_handlers.HasPermission(ctx, permissions.PAYMENTS_METRICS_READ); err != nil {
 		_ = ctx.Error(errors.NewUnauthorizedError(err.Error()))
 		return
 	}
@@ -40,17 +48,145 @@ func (c *gemMetricsController) GetMetrics(ctx *gin.Context) {
 		return
 	}
 
-	flow := 0.0
-	if metrics.GemsBought > 0 {
-		flow = float64(metrics.GemsSpent) / float64(metrics.GemsBought)
-	}
-
 	res := dtos.GemMetricsResponse{
 		GemsBought:  metrics.GemsBought,
 		GemsSpent:   metrics.GemsSpent,
 		GemsRevenue: metrics.GemsRevenue,
-		GemsFlow:    flow,
+		GemsFlow:    gemsFlow(metrics.GemsBought, metrics.GemsSpent),
+	}
+
+	common_handlers.HandleSuccessResponse(ctx, http.StatusOK, res)
+}
+
+// GetMetricsHistory godoc
+// @Summary      Get gem metrics history
+// @Description  Returns gem metrics per day, week or month, rolled up in the billing timezone. Requires payments.metrics.read.
+// @Tags         payment-service
+// @Security     BearerAuth
+// @Produce      json
+// @Param        from         query     string  false  "First day (YYYY-MM-DD), defaults to 30 days before to"
+// @Param        to           query     string  false  "Last day (YYYY-MM-DD, inclusive), defaults to today"
+// @Param        granularity  query     string  false  "Bucket size (day, week, month)" default(day)
+// @Success      200          {object}  dtos.GemMetricsHistoryResponse
+// @Failure      400          {object}  dtos.ErrorResponse
+// @Failure      401          {object}  dtos.ErrorResponse
+// @Failure      500          {object}  dtos.ErrorResponse
+// @Router       /payments/gems/metrics/history [get]
+func (c *gemMetricsController) GetMetricsHistory(ctx *gin.Context) {
+	if err := common_handlers.HasPermission(ctx, permissions.PAYMENTS_METRICS_READ); err != nil {
+		_ = ctx.Error(errors.NewUnauthorizedError(err.Error()))
+		return
+	}
+
+	granularity, buckets, err := c.metricsHistory(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	res := dtos.GemMetricsHistoryResponse{
+		Granularity: granularity,
+		Buckets:     make([]dtos.GemMetricsBucketResponse, len(buckets)),
+	}
+	for i, bucket := range buckets {
+		res.Buckets[i] = dtos.GemMetricsBucketResponse{
+			PeriodStart: bucket.PeriodStart.Format(time.DateOnly),
+			GemsBought:  bucket.GemsBought,
+			GemsSpent:   bucket.GemsSpent,
+			GemsRevenue: bucket.GemsRevenue,
+			GemsFlow:    gemsFlow(bucket.GemsBought, bucket.GemsSpent),
+		}
 	}
 
 	common_handlers.HandleSuccessResponse(ctx, http.StatusOK, res)
 }
+
+// ExportMetricsHistory godoc
+// @Summary      Export gem metrics history
+// @Description  Downloads gem metrics per day, week or month as CSV, rolled up in the billing timezone. Requires payments.metrics.read.
+// @Tags         payment-service
+// @Security     BearerAuth
+// @Produce      text/csv
+// @Param        from         query     string  false  "First day (YYYY-MM-DD), defaults to 30 days before to"
+// @Param        to           query     string  false  "Last day (YYYY-MM-DD, inclusive), defaults to today"
+// @Param        granularity  query     string  false  "Bucket size (day, week, month)" default(day)
+// @Success      200          {string}  string "CSV file"
+// @Failure      400          {object}  dtos.ErrorResponse
+// @Failure      401          {object}  dtos.ErrorResponse
+// @Failure      500          {object}  dtos.ErrorResponse
+// @Router       /payments/gems/metrics/export [get]
+func (c *gemMetricsController) ExportMetricsHistory(ctx *gin.Context) {
+	if err := common_handlers.HasPermission(ctx, permissions.PAYMENTS_METRICS_READ); err != nil {
+		_ = ctx.Error(errors.NewUnauthorizedError(err.Error()))
+		return
+	}
+
+	granularity, buckets, err := c.metricsHistory(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	_ = writer.Write([]string{"period_start", "gems_bought", "gems_spent", "gems_revenue"})
+	for _, bucket := range buckets {
+		_ = writer.Write([]string{
+			bucket.PeriodStart.Format(time.DateOnly),
+			strconv.FormatInt(bucket.GemsBought, 10),
+			strconv.FormatInt(bucket.GemsSpent, 10),
+			strconv.FormatFloat(bucket.GemsRevenue, 'f', 2, 64),
+		})
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		_ = ctx.Error(errors.NewInternalServerError("failed to write gem metrics csv: " + err.Error()))
+		return
+	}
+
+	filename := "gem-metrics-" + granularity
+	if len(buckets) > 0 {
+		filename += fmt.Sprintf("-%s-%s", buckets[0].PeriodStart.Format(time.DateOnly), buckets[len(buckets)-1].PeriodStart.Format(time.DateOnly))
+	}
+	ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.csv"`, filename))
+	ctx.Data(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+}
+
+/* --- UTILS --- */
+
+func (c *gemMetricsController) metricsHistory(ctx *gin.Context) (string, []*models.GemMetricsBucket, error) {
+	granularity := ctx.DefaultQuery("granularity", models.METRICS_GRANULARITY_DAY)
+	if !models.IsValidMetricsGranularity(granularity) {
+		return "", nil, errors.NewBadRequestError("invalid granularity")
+	}
+
+	var from, to time.Time
+	var err error
+	if value := ctx.Query("from"); value != "" {
+		if from, err = time.Parse(time.DateOnly, value); err != nil {
+			return "", nil, errors.NewBadRequestError("invalid from date, expected YYYY-MM-DD")
+		}
+	}
+	if value := ctx.Query("to"); value != "" {
+		if to, err = time.Parse(time.DateOnly, value); err != nil {
+			return "", nil, errors.NewBadRequestError("invalid to date, expected YYYY-MM-DD")
+		}
+	}
+
+	buckets, err := c.service.GetMetricsHistory(from, to, granularity)
+	if err != nil {
+		if _, ok := err.(*payment_errors.InvalidMetricsRange); ok {
+			return "", nil, errors.NewBadRequestError(err.Error())
+		}
+		return "", nil, err
+	}
+
+	return granularity, buckets, nil
+}
+
+func gemsFlow(bought int64, spent int64) float64 {
+	if bought <= 0 {
+		return 0
+	}
+	return float64(spent) / float64(bought)
+}
@@ -112,7 +112,7 @@ func (pc *gemPacksController) GetGemPackById(c *gin.Context) {
 
 // CreateGemPack godoc
 // @Summary      Create gem pack
-// @Description  Creates a new gem pack. Admin only.
+// @Description  Creates a new gem pack. Requires payments.packs.write.
 // @Tags         payment-service
 // @Security     BearerAuth
 // @Accept       json
@@ -147,12 +147,13 @@ func (pc *gemPacksController) CreateGemPack(c *gin.Context) {
 		UpdatedAt: createdPack.UpdatedAt,
 	}
 
+	common_handlers.SetAuditTarget(c, createdPack.Id.String())
 	common_handlers.HandleSuccessResponse(c, 201, res)
 }
 
 // UpdateGemPack godoc
 // @Summary      Update gem pack
-// @Description  Updates an existing gem pack. Admin only.
+// @Description  Updates an existing gem pack. Requires payments.packs.write.
 // @Tags         payment-service
 // @Security     BearerAuth
 // @Accept       json
@@ -200,7 +201,7 @@ func (pc *gemPacksController) UpdateGemPack(c *gin.Context) {
 
 // DeleteGemPack godoc
 // @Summary      Delete gem pack
-// @Description  Deletes a gem pack by ID. Admin only.
+// @Description  Deletes a gem pack by ID. Requires payments.packs.write.
 // @Tags         payment-service
 // @Security     BearerAuth
 // @Produce      json
@@ -11,6 +11,8 @@ type ZonesSubscriptionsController interface {
 	GetPricingInfo(c *gin.Context)
 	CheckInternalAvailability(c *gin.Context)
 	InternalUpdateUsedSlots(c *gin.Context)
+	InternalCancelSubscription(c *gin.Context)
+	InternalUpdateCustomerEmail(c *gin.Context)
 	AdminListSubscriptions(c *gin.Context)
 	AdminCreateSubscription(c *gin.Context)
 	AdminUpdateSlots(c *gin.Context)
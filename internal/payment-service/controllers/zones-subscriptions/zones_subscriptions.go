@@ -301,9 +301,69 @@ func (zc *subscriptionController) InternalUpdateUsedSlots(c *gin.Context) {
 	common_handlers.HandleSuccessResponse(c, 200, gin.H{"status": "ok"})
 }
 
+// InternalCancelSubscription godoc
+// @Summary      Internal cancel subscription
+// @Description  Internal endpoint used by authentication-service to schedule the cancellation of a deleted account's subscription. Users without an active subscription are ignored.
+// @Tags         payment-service-subscriptions
+// @Security     ServerFixedToken
+// @Param        user_id  path      string  true  "User ID"
+// @Success      204      {string}  string  "No Content"
+// @Failure      400      {object}  dtos.ErrorResponse
+// @Failure      500      {object}  dtos.ErrorResponse
+// @Router       /subscriptions/internal/users/{user_id} [delete]
+func (zc *subscriptionController) InternalCancelSubscription(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		_ = c.Error(custom_errors.NewBadRequestError("Invalid user_id in path"))
+		return
+	}
+
+	if err := zc.zonesSubscriptionsService.CancelActiveSubscription(userID); err != nil {
+		logger.Logger.Error("Failed to cancel subscription for user " + userID.String() + ": " + err.Error())
+		_ = c.Error(custom_errors.NewInternalServerError("Failed to cancel subscription"))
+		return
+	}
+
+	common_handlers.HandleBodilessResponse(c, http.StatusNoContent)
+}
+
+// InternalUpdateCustomerEmail godoc
+// @Summary      Internal update customer email
+// @Description  Internal endpoint used by authentication-service to update the Stripe customer email after the user changes the account email. Users without a Stripe customer are ignored.
+// @Tags         payment-service-subscriptions
+// @Security     ServerFixedToken
+// @Accept       json
+// @Param        user_id  path      string                                   true  "User ID"
+// @Param        request  body      dtos.InternalUpdateCustomerEmailRequest  true  "New email"
+// @Success      204      {string}  string  "No Content"
+// @Failure      400      {object}  dtos.ErrorResponse
+// @Failure      500      {object}  dtos.ErrorResponse
+// @Router       /subscriptions/internal/users/{user_id}/email [put]
+func (zc *subscriptionController) InternalUpdateCustomerEmail(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		_ = c.Error(custom_errors.NewBadRequestError("Invalid user_id in path"))
+		return
+	}
+
+	var req dtos.InternalUpdateCustomerEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(custom_errors.NewBadRequestError("invalid request body: " + err.Error()))
+		return
+	}
+
+	if err := zc.zonesSubscriptionsService.UpdateCustomerEmail(userID, req.Email); err != nil {
+		logger.Logger.Error("Failed to update customer email for user " + userID.String() + ": " + err.Error())
+		_ = c.Error(custom_errors.NewInternalServerError("Failed to update customer email"))
+		return
+	}
+
+	common_handlers.HandleBodilessResponse(c, http.StatusNoContent)
+}
+
 // AdminListSubscriptions godoc
 // @Summary      Admin list all subscriptions
-// @Description  Returns a paginated list of every subscription (comp and Stripe alike), admin only.
+// @Description  Returns a paginated list of every subscription (comp and Stripe alike). Requires payments.subscriptions.read.
 // @Tags         payment-service-subscriptions
 // @Security     BearerAuth
 // @Produce      json
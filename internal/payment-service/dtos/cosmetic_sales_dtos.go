@@ -0,0 +1,38 @@
+package dtos
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CosmeticSalesBucketResponse struct {
+	PeriodStart  time.Time `json:"period_start"`
+	SalesCount   int64     `json:"sales_count"`
+	GemsRevenue  int64     `json:"gems_revenue"`
+	CreatorShare float64   `json:"creator_share"`
+}
+
+type CosmeticSalesTotalsResponse struct {
+	SalesCount   int64   `json:"sales_count"`
+	GemsRevenue  int64   `json:"gems_revenue"`
+	CreatorShare float64 `json:"creator_share"`
+}
+
+type CosmeticSalesReportResponse struct {
+	Interval string                        `json:"interval"`
+	Buckets  []CosmeticSalesBucketResponse `json:"buckets"`
+	Totals   CosmeticSalesTotalsResponse   `json:"totals"`
+}
+
+type CosmeticSalesRankingResponse struct {
+	ID           uuid.UUID `json:"id"`
+	SalesCount   int64     `json:"sales_count"`
+	GemsRevenue  int64     `json:"gems_revenue"`
+	CreatorShare float64   `json:"creator_share"`
+}
+
+type CosmeticTopSellersResponse struct {
+	GroupBy    string                         `json:"group_by"`
+	TopSellers []CosmeticSalesRankingResponse `json:"top_sellers"`
+}
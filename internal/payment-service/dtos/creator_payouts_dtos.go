@@ -0,0 +1,48 @@
+package dtos
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SetPayoutAccountRequest struct {
+	AccountID string `json:"account_id" binding:"required,max=255"`
+}
+
+type CreatePayoutRequest struct {
+	Amount float64 `json:"amount" binding:"required,gt=0"`
+}
+
+type RejectPayoutRequest struct {
+	Reason string `json:"reason" binding:"required,max=1000"`
+}
+
+type CreatorPayoutResponse struct {
+	ID                uuid.UUID `json:"id"`
+	UserID            uuid.UUID `json:"user_id"`
+	Amount            float64   `json:"amount"`
+	Status            string    `json:"status"`
+	PayoutAccountID   string    `json:"payout_account_id"`
+	ProviderReference string    `json:"provider_reference"`
+	RejectionReason   string    `json:"rejection_reason"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+type CreatorPayoutEventResponse struct {
+	Status    string     `json:"status"`
+	ActorID   *uuid.UUID `json:"actor_id"`
+	Note      string     `json:"note"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type CreatorPayoutDetailResponse struct {
+	CreatorPayoutResponse
+	History []CreatorPayoutEventResponse `json:"history"`
+}
+
+type CreatorPayoutsListResponse struct {
+	Payouts    []CreatorPayoutResponse `json:"payouts"`
+	TotalCount int64                   `json:"total_count"`
+}
@@ -1,12 +1,39 @@
 package dtos
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type GemBalanceResponse struct {
 	UserId uuid.UUID `json:"user_id"`
 	Gems   int64     `json:"gems"`
 }
 
+// BundlePurchaseResponse returns the updated balance and the cosmetics the bundle granted.
+type BundlePurchaseResponse struct {
+	UserId             uuid.UUID   `json:"user_id"`
+	Gems               int64       `json:"gems"`
+	GrantedCosmeticIds []uuid.UUID `json:"granted_cosmetic_ids"`
+}
+
+// GiftCosmeticRequest names the character that receives the gift and an optional message for them.
+type GiftCosmeticRequest struct {
+	CharacterName string `json:"character_name" binding:"required"`
+	Message       string `json:"message"`
+}
+
+// GiftCosmeticResponse returns the buyer's updated balance and who received the gift.
+type GiftCosmeticResponse struct {
+	UserId        uuid.UUID `json:"user_id"`
+	Gems          int64     `json:"gems"`
+	CosmeticId    uuid.UUID `json:"cosmetic_id"`
+	RecipientId   uuid.UUID `json:"recipient_id"`
+	CharacterName string    `json:"character_name"`
+	Message       string    `json:"message"`
+}
+
 type UpdateGemBalanceRequest struct {
 	Gems int64 `json:"gems" binding:"required"`
 }
@@ -23,3 +50,26 @@ type CheckoutResponse struct {
 
 type WebhookResponse struct {
 }
+
+type GemTransactionResponse struct {
+	ID          uuid.UUID  `json:"id"`
+	Type        string     `json:"type"`
+	Amount      int64      `json:"amount"`
+	ReferenceID string     `json:"reference_id"`
+	ActorID     *uuid.UUID `json:"actor_id"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+type GemTransactionsListResponse struct {
+	Transactions []GemTransactionResponse `json:"transactions"`
+	TotalCount   int64                    `json:"total_count"`
+}
+
+type AdminGemTransactionsListResponse struct {
+	UserId       uuid.UUID                `json:"user_id"`
+	Gems         int64                    `json:"gems"`
+	LedgerGems   int64                    `json:"ledger_gems"`
+	Reconciled   bool                     `json:"reconciled"`
+	Transactions []GemTransactionResponse `json:"transactions"`
+	TotalCount   int64                    `json:"total_count"`
+}
@@ -6,3 +6,16 @@ type GemMetricsResponse struct {
 	GemsRevenue float64 `json:"gems_revenue"`
 	GemsFlow    float64 `json:"gems_flow"`
 }
+
+type GemMetricsBucketResponse struct {
+	PeriodStart string  `json:"period_start"`
+	GemsBought  int64   `json:"gems_bought"`
+	GemsSpent   int64   `json:"gems_spent"`
+	GemsRevenue float64 `json:"gems_revenue"`
+	GemsFlow    float64 `json:"gems_flow"`
+}
+
+type GemMetricsHistoryResponse struct {
+	Granularity string                     `json:"granularity"`
+	Buckets     []GemMetricsBucketResponse `json:"buckets"`
+}
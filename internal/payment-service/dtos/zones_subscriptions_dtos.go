@@ -57,6 +57,10 @@ type InternalUpdateUsedSlotsRequest struct {
 	AreUsed bool `json:"are_used"`
 }
 
+type InternalUpdateCustomerEmailRequest struct {
+	Email string `json:"email" binding:"required"`
+}
+
 type PricingInfoResponse struct {
 	PricePerSlot    float64   `json:"price_per_slot"`
 	NextBillingDate time.Time `json:"next_billing_date"`
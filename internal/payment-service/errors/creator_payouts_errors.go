@@ -0,0 +1,73 @@
+package errors
+
+type PayoutBelowMinimum struct {
+	Message string
+}
+
+func NewPayoutBelowMinimum(message string) error {
+	return &PayoutBelowMinimum{Message: message}
+}
+
+func (e *PayoutBelowMinimum) Error() string {
+	return e.Message
+}
+
+type InsufficientCreatorBalance struct {
+	Message string
+}
+
+func NewInsufficientCreatorBalance(message string) error {
+	return &InsufficientCreatorBalance{Message: message}
+}
+
+func (e *InsufficientCreatorBalance) Error() string {
+	return e.Message
+}
+
+type PayoutAlreadyPending struct {
+	Message string
+}
+
+func NewPayoutAlreadyPending(message string) error {
+	return &PayoutAlreadyPending{Message: message}
+}
+
+func (e *PayoutAlreadyPending) Error() string {
+	return e.Message
+}
+
+type PayoutAccountMissing struct {
+	Message string
+}
+
+func NewPayoutAccountMissing(message string) error {
+	return &PayoutAccountMissing{Message: message}
+}
+
+func (e *PayoutAccountMissing) Error() string {
+	return e.Message
+}
+
+type InvalidPayoutTransition struct {
+	Message string
+}
+
+func NewInvalidPayoutTransition(message string) error {
+	return &InvalidPayoutTransition{Message: message}
+}
+
+func (e *InvalidPayoutTransition) Error() string {
+	return e.Message
+}
+
+type PayoutProviderError struct {
+	Message string
+}
+
+func NewPayoutProviderError(message string) error {
+	return &PayoutProviderError{Message: message}
+}
+
+func (e *PayoutProviderError) Error() string {
+	return e.Message
+}
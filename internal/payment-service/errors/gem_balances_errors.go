@@ -24,6 +24,18 @@ func (e *CosmeticNotFound) Error() string {
 	return e.Message
 }
 
+type BundleNotFound struct {
+	Message string
+}
+
+func NewBundleNotFound(message string) error {
+	return &BundleNotFound{Message: message}
+}
+
+func (e *BundleNotFound) Error() string {
+	return e.Message
+}
+
 type CosmeticAlreadyPurchased struct {
 	Message string
 }
@@ -35,3 +47,51 @@ func NewCosmeticAlreadyPurchased(message string) error {
 func (e *CosmeticAlreadyPurchased) Error() string {
 	return e.Message
 }
+
+type GemBalanceLocked struct {
+	Message string
+}
+
+func NewGemBalanceLocked(message string) error {
+	return &GemBalanceLocked{Message: message}
+}
+
+func (e *GemBalanceLocked) Error() string {
+	return e.Message
+}
+
+type RecipientNotFound struct {
+	Message string
+}
+
+func NewRecipientNotFound(message string) error {
+	return &RecipientNotFound{Message: message}
+}
+
+func (e *RecipientNotFound) Error() string {
+	return e.Message
+}
+
+type InvalidGift struct {
+	Message string
+}
+
+func NewInvalidGift(message string) error {
+	return &InvalidGift{Message: message}
+}
+
+func (e *InvalidGift) Error() string {
+	return e.Message
+}
+
+type GiftLimitReached struct {
+	Message string
+}
+
+func NewGiftLimitReached(message string) error {
+	return &GiftLimitReached{Message: message}
+}
+
+func (e *GiftLimitReached) Error() string {
+	return e.Message
+}
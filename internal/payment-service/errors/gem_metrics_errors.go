@@ -0,0 +1,13 @@
+package errors
+
+type InvalidMetricsRange struct {
+	Message string
+}
+
+func NewInvalidMetricsRange(message string) error {
+	return &InvalidMetricsRange{Message: message}
+}
+
+func (e *InvalidMetricsRange) Error() string {
+	return e.Message
+}
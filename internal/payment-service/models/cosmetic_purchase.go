@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	COSMETIC_PURCHASE_PENDING   = "pending"
+	COSMETIC_PURCHASE_COMPLETED = "completed"
+	COSMETIC_PURCHASE_REFUNDED  = "refunded"
+
+	MAX_GIFT_MESSAGE_LENGTH = 200
+)
+
+// CosmeticPurchase buys either a single cosmetic or a bundle, exactly one of CosmeticID and BundleID is set.
+// UserID always pays, RecipientID is only set when the cosmetic is gifted to another player.
+type CosmeticPurchase struct {
+	ID              uuid.UUID       `json:"id" gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	UserID          uuid.UUID       `json:"user_id" gorm:"type:uuid;not null"`
+	CosmeticID      *uuid.UUID      `json:"cosmetic_id" gorm:"type:uuid"`
+	BundleID        *uuid.UUID      `json:"bundle_id" gorm:"type:uuid"`
+	CreatorID       *uuid.UUID      `json:"creator_id" gorm:"type:uuid"`
+	WorldID         *uuid.UUID      `json:"world_id" gorm:"type:uuid"`
+	RecipientID     *uuid.UUID      `json:"recipient_id,omitempty" gorm:"type:uuid"`
+	GiftMessage     string          `json:"gift_message,omitempty" gorm:"type:varchar(200);not null;default:''"`
+	Price           int64           `json:"price" gorm:"not null"`
+	CreatorEarnings decimal.Decimal `json:"creator_earnings" gorm:"type:numeric(10,2);not null;default:0"`
+	Status          string          `json:"status" gorm:"type:varchar(20);not null;default:'pending'"`
+	CreatedAt       time.Time       `json:"created_at"`
+	UpdatedAt       time.Time       `json:"updated_at"`
+}
+
+func (CosmeticPurchase) TableName() string {
+	return "cosmetic_purchases"
+}
+
+// ItemID returns the id of the cosmetic or bundle being bought.
+func (p *CosmeticPurchase) ItemID() uuid.UUID {
+	if p.BundleID != nil {
+		return *p.BundleID
+	}
+	if p.CosmeticID != nil {
+		return *p.CosmeticID
+	}
+	return uuid.Nil
+}
+
+// IsGift reports whether the purchase was paid for another player.
+func (p *CosmeticPurchase) IsGift() bool {
+	return p.RecipientID != nil
+}
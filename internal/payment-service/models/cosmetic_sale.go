@@ -0,0 +1,72 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	SALES_INTERVAL_DAY   = "day"
+	SALES_INTERVAL_WEEK  = "week"
+	SALES_INTERVAL_MONTH = "month"
+)
+
+const (
+	SALES_GROUP_BY_COSMETIC = "cosmetic"
+	SALES_GROUP_BY_WORLD    = "world"
+	SALES_GROUP_BY_CREATOR  = "creator"
+	SALES_GROUP_BY_BUNDLE   = "bundle"
+)
+
+// CosmeticSale is recorded once per completed cosmetic or bundle purchase and feeds the sales analytics.
+type CosmeticSale struct {
+	ID           uuid.UUID       `json:"id" gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	PurchaseID   uuid.UUID       `json:"purchase_id" gorm:"type:uuid;not null;uniqueIndex"`
+	CosmeticID   *uuid.UUID      `json:"cosmetic_id" gorm:"type:uuid"`
+	BundleID     *uuid.UUID      `json:"bundle_id" gorm:"type:uuid"`
+	WorldID      *uuid.UUID      `json:"world_id" gorm:"type:uuid"`
+	BuyerID      uuid.UUID       `json:"buyer_id" gorm:"type:uuid;not null"`
+	CreatorID    *uuid.UUID      `json:"creator_id" gorm:"type:uuid"`
+	Price        int64           `json:"price" gorm:"not null"`
+	CreatorShare decimal.Decimal `json:"creator_share" gorm:"type:numeric(10,2);not null;default:0"`
+	CreatedAt    time.Time       `json:"created_at"`
+}
+
+func (CosmeticSale) TableName() string {
+	return "cosmetic_sales"
+}
+
+// CosmeticSalesBucket aggregates the sales of one period.
+type CosmeticSalesBucket struct {
+	PeriodStart  time.Time
+	SalesCount   int64
+	GemsRevenue  int64
+	CreatorShare decimal.Decimal
+}
+
+// CosmeticSalesTotals aggregates every sale of a report.
+type CosmeticSalesTotals struct {
+	SalesCount   int64
+	GemsRevenue  int64
+	CreatorShare decimal.Decimal
+}
+
+// CosmeticSalesRanking aggregates the sales of one cosmetic, world or creator.
+type CosmeticSalesRanking struct {
+	ID           uuid.UUID
+	SalesCount   int64
+	GemsRevenue  int64
+	CreatorShare decimal.Decimal
+}
+
+// IsValidSalesInterval reports whether the interval is one of the supported bucket sizes.
+func IsValidSalesInterval(interval string) bool {
+	switch interval {
+	case SALES_INTERVAL_DAY, SALES_INTERVAL_WEEK, SALES_INTERVAL_MONTH:
+		return true
+	default:
+		return false
+	}
+}
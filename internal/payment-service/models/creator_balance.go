@@ -8,8 +8,10 @@ import (
 )
 
 type CreatorBalance struct {
-	UserID    uuid.UUID       `gorm:"type:uuid;primaryKey"`
-	Balance   decimal.Decimal `gorm:"type:numeric(10,2);not null;default:0"`
-	CreatedAt time.Time       `gorm:"autoCreateTime"`
-	UpdatedAt time.Time       `gorm:"autoUpdateTime"`
+	UserID          uuid.UUID       `json:"user_id" gorm:"type:uuid;primaryKey"`
+	Balance         decimal.Decimal `json:"balance" gorm:"type:numeric(10,2);not null;default:0"`
+	HeldBalance     decimal.Decimal `json:"held_balance" gorm:"type:numeric(10,2);not null;default:0"`
+	PayoutAccountID string          `json:"payout_account_id" gorm:"type:varchar(255);not null;default:''"`
+	CreatedAt       time.Time       `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt       time.Time       `json:"updated_at" gorm:"autoUpdateTime"`
 }
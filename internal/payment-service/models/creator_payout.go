@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	CREATOR_PAYOUT_REQUESTED = "requested"
+	CREATOR_PAYOUT_APPROVED  = "approved"
+	CREATOR_PAYOUT_PAID      = "paid"
+	CREATOR_PAYOUT_REJECTED  = "rejected"
+	CREATOR_PAYOUT_FAILED    = "failed"
+)
+
+type CreatorPayout struct {
+	ID                uuid.UUID       `json:"id" gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	UserID            uuid.UUID       `json:"user_id" gorm:"type:uuid;not null"`
+	Amount            decimal.Decimal `json:"amount" gorm:"type:numeric(10,2);not null"`
+	Status            string          `json:"status" gorm:"type:varchar(20);not null;default:'requested'"`
+	PayoutAccountID   string          `json:"payout_account_id" gorm:"type:varchar(255);not null"`
+	ProviderReference string          `json:"provider_reference" gorm:"type:varchar(255);not null;default:''"`
+	RejectionReason   string          `json:"rejection_reason" gorm:"type:text;not null;default:''"`
+	CreatedAt         time.Time       `json:"created_at"`
+	UpdatedAt         time.Time       `json:"updated_at"`
+}
+
+func (CreatorPayout) TableName() string {
+	return "creator_payouts"
+}
+
+// IsPending reports whether the payout still holds part of the creator balance.
+func (p *CreatorPayout) IsPending() bool {
+	return p.Status == CREATOR_PAYOUT_REQUESTED || p.Status == CREATOR_PAYOUT_APPROVED
+}
+
+// CreatorPayoutEvent records every status a payout went through and who moved it there.
+type CreatorPayoutEvent struct {
+	ID        uuid.UUID  `json:"id" gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	PayoutID  uuid.UUID  `json:"payout_id" gorm:"type:uuid;not null"`
+	Status    string     `json:"status" gorm:"type:varchar(20);not null"`
+	ActorID   *uuid.UUID `json:"actor_id" gorm:"type:uuid"`
+	Note      string     `json:"note" gorm:"type:text;not null;default:''"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func (CreatorPayoutEvent) TableName() string {
+	return "creator_payout_events"
+}
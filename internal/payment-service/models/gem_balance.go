@@ -9,6 +9,7 @@ import (
 type GemBalance struct {
 	UserId    uuid.UUID `json:"user_id" gorm:"type:uuid;primaryKey"`
 	Gems      int64     `json:"gems" gorm:"not null;default:0"`
+	Locked    bool      `json:"locked" gorm:"not null;default:false"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
@@ -19,7 +20,7 @@ func (GemBalance) TableName() string {
 
 type ProcessedStripeWebhookEvent struct {
 	EventID   string    `json:"event_id" gorm:"column:event_id;primaryKey"`
-	SessionID string    `json:"session_id" gorm:"column:session_id;uniqueIndex;not null"`
+	SessionID string    `json:"session_id" gorm:"column:session_id;index;not null"`
 	EventType string    `json:"event_type" gorm:"column:event_type;not null"`
 	UserID    uuid.UUID `json:"user_id" gorm:"column:user_id;type:uuid;not null"`
 	CreatedAt time.Time `json:"created_at" gorm:"column:created_at"`
@@ -4,8 +4,22 @@ import (
 	"time"
 )
 
+const (
+	METRICS_GRANULARITY_DAY   = "day"
+	METRICS_GRANULARITY_WEEK  = "week"
+	METRICS_GRANULARITY_MONTH = "month"
+)
+
+// GemMetrics holds gem totals, either lifetime or of a single period.
 type GemMetrics struct {
-	ID          int32     `json:"id" gorm:"primaryKey"`
+	GemsBought  int64
+	GemsSpent   int64
+	GemsRevenue float64
+}
+
+// GemMetricsDaily is the rollup of one calendar day in the billing timezone.
+type GemMetricsDaily struct {
+	Day         time.Time `json:"day" gorm:"type:date;primaryKey"`
 	GemsBought  int64     `json:"gems_bought" gorm:"not null;default:0"`
 	GemsSpent   int64     `json:"gems_spent" gorm:"not null;default:0"`
 	GemsRevenue float64   `json:"gems_revenue" gorm:"type:numeric(12,2);not null;default:0"`
@@ -13,6 +27,24 @@ type GemMetrics struct {
 	UpdatedAt   time.Time `json:"updated_at"`
 }
 
-func (GemMetrics) TableName() string {
-	return "gem_metrics"
+func (GemMetricsDaily) TableName() string {
+	return "gem_metrics_daily"
+}
+
+// GemMetricsBucket holds the gem totals of the day, week or month starting at PeriodStart.
+type GemMetricsBucket struct {
+	PeriodStart time.Time
+	GemsBought  int64
+	GemsSpent   int64
+	GemsRevenue float64
+}
+
+// IsValidMetricsGranularity reports whether the granularity is one of the supported bucket sizes.
+func IsValidMetricsGranularity(granularity string) bool {
+	switch granularity {
+	case METRICS_GRANULARITY_DAY, METRICS_GRANULARITY_WEEK, METRICS_GRANULARITY_MONTH:
+		return true
+	default:
+		return false
+	}
 }
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	GEM_TRANSACTION_STRIPE_CREDIT    = "stripe_credit"
+	GEM_TRANSACTION_PURCHASE         = "purchase"
+	GEM_TRANSACTION_ADMIN_ADJUSTMENT = "admin_adjustment"
+	GEM_TRANSACTION_REFUND           = "refund"
+
+	GEM_TRANSACTION_STRIPE_REFUND       = "stripe_refund"
+	GEM_TRANSACTION_CHARGEBACK          = "chargeback"
+	GEM_TRANSACTION_CHARGEBACK_REVERSAL = "chargeback_reversal"
+)
+
+// GemTransaction is an append-only ledger entry, the sum of a user's amounts equals their gem balance.
+type GemTransaction struct {
+	ID          uuid.UUID  `json:"id" gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	UserID      uuid.UUID  `json:"user_id" gorm:"type:uuid;not null"`
+	Type        string     `json:"type" gorm:"type:varchar(32);not null"`
+	Amount      int64      `json:"amount" gorm:"not null"`
+	ReferenceID string     `json:"reference_id" gorm:"type:varchar(255);not null;default:''"`
+	ActorID     *uuid.UUID `json:"actor_id" gorm:"type:uuid"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+func (GemTransaction) TableName() string {
+	return "gem_transactions"
+}
+
+// IsValidGemTransactionType reports whether the type is one of the known ledger entry types.
+func IsValidGemTransactionType(transactionType string) bool {
+	switch transactionType {
+	case GEM_TRANSACTION_STRIPE_CREDIT, GEM_TRANSACTION_PURCHASE, GEM_TRANSACTION_ADMIN_ADJUSTMENT, GEM_TRANSACTION_REFUND,
+		GEM_TRANSACTION_STRIPE_REFUND, GEM_TRANSACTION_CHARGEBACK, GEM_TRANSACTION_CHARGEBACK_REVERSAL:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,11 @@
+package models
+
+// UserData is everything the payment-service stores about a user, as handed out in a data export.
+type UserData struct {
+	GemBalance        *GemBalance         `json:"gem_balance"`
+	GemTransactions   []GemTransaction    `json:"gem_transactions"`
+	CosmeticPurchases []CosmeticPurchase  `json:"cosmetic_purchases"`
+	CreatorBalance    *CreatorBalance     `json:"creator_balance"`
+	CreatorPayouts    []CreatorPayout     `json:"creator_payouts"`
+	ZonesSubscription *ZonesSubscriptions `json:"zones_subscription"`
+}
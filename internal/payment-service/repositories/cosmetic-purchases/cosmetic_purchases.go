@@ -0,0 +1,224 @@
+package cosmetic_purchases
+
+import (
+	"fmt"
+
+	"github.com/FeedTheRealm-org/core-service/config"
+	"github.com/FeedTheRealm-org/core-service/internal/errors"
+	payment_errors "github.com/FeedTheRealm-org/core-service/internal/payment-service/errors"
+	"github.com/FeedTheRealm-org/core-service/internal/payment-service/models"
+	gem_transactions "github.com/FeedTheRealm-org/core-service/internal/payment-service/repositories/gem-transactions"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type DatabaseError struct {
+	message string
+}
+
+func (e *DatabaseError) Error() string {
+	return "Database error occurred: " + e.message
+}
+
+type cosmeticPurchasesRepository struct {
+	conf *config.Config
+	db   *config.DB
+}
+
+func NewCosmeticPurchasesRepository(conf *config.Config, db *config.DB) CosmeticPurchasesRepository {
+	return &cosmeticPurchasesRepository{
+		conf: conf,
+		db:   db,
+	}
+}
+
+func (r *cosmeticPurchasesRepository) CreatePendingPurchase(purchase *models.CosmeticPurchase) error {
+	return r.createPendingPurchase(purchase, nil)
+}
+
+func (r *cosmeticPurchasesRepository) CreatePendingGiftPurchase(purchase *models.CosmeticPurchase, dailyLimit int) error {
+	return r.createPendingPurchase(purchase, func(tx *gorm.DB) error {
+		// The debit above holds the buyer's balance row, so concurrent gifts are counted one at a time
+		var sent int64
+		if err := tx.Model(&models.CosmeticPurchase{}).
+			Where("user_id = ? AND recipient_id IS NOT NULL AND status <> ? AND created_at > NOW() - INTERVAL '1 day'", purchase.UserID, models.COSMETIC_PURCHASE_REFUNDED).
+			Count(&sent).Error; err != nil {
+			return err
+		}
+		if sent >= int64(dailyLimit) {
+			return payment_errors.NewGiftLimitReached(fmt.Sprintf("daily gift limit of %d reached", dailyLimit))
+		}
+		return nil
+	})
+}
+
+// createPendingPurchase runs beforeCreate, when set, after the buyer was debited and before the purchase is stored.
+func (r *cosmeticPurchasesRepository) createPendingPurchase(purchase *models.CosmeticPurchase, beforeCreate func(tx *gorm.DB) error) error {
+	purchase.Status = models.COSMETIC_PURCHASE_PENDING
+
+	err := r.db.Conn.Transaction(func(tx *gorm.DB) error {
+		// Conditional debit, concurrent purchases can never take the balance below zero
+		result := tx.Model(&models.GemBalance{}).
+			Where("user_id = ? AND gems >= ? AND NOT locked", purchase.UserID, purchase.Price).
+			UpdateColumn("gems", gorm.Expr("gems - ?", purchase.Price))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			var balance models.GemBalance
+			if err := tx.Where("user_id = ?", purchase.UserID).Limit(1).Find(&balance).Error; err != nil {
+				return err
+			}
+			if balance.Locked {
+				return payment_errors.NewGemBalanceLocked("gem balance is locked while a payment dispute is open")
+			}
+			return payment_errors.NewInsufficientGems("insufficient gems for this purchase")
+		}
+
+		if beforeCreate != nil {
+			if err := beforeCreate(tx); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Create(purchase).Error; err != nil {
+			if errors.IsDuplicateEntryError(err) {
+				if purchase.BundleID != nil {
+					return payment_errors.NewCosmeticAlreadyPurchased("bundle was already purchased by this user")
+				}
+				if purchase.IsGift() {
+					return payment_errors.NewCosmeticAlreadyPurchased("the recipient already owns this cosmetic")
+				}
+				return payment_errors.NewCosmeticAlreadyPurchased("cosmetic was already purchased by this user")
+			}
+			return err
+		}
+
+		if err := gem_transactions.RecordTransaction(tx, &models.GemTransaction{
+			UserID:      purchase.UserID,
+			Type:        models.GEM_TRANSACTION_PURCHASE,
+			Amount:      -purchase.Price,
+			ReferenceID: purchase.ItemID().String(),
+			ActorID:     &purchase.UserID,
+		}); err != nil {
+			return err
+		}
+
+		if purchase.CreatorID != nil && purchase.CreatorEarnings.GreaterThan(decimal.Zero) {
+			return addToCreatorBalance(tx, *purchase.CreatorID, purchase.CreatorEarnings)
+		}
+
+		return nil
+	})
+
+	return wrapDatabaseError(err)
+}
+
+func (r *cosmeticPurchasesRepository) CompletePurchase(purchaseId uuid.UUID) error {
+	err := r.db.Conn.Transaction(func(tx *gorm.DB) error {
+		var purchase models.CosmeticPurchase
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", purchaseId).First(&purchase).Error; err != nil {
+			if errors.IsRecordNotFound(err) {
+				return errors.NewNotFoundError("purchase not found")
+			}
+			return err
+		}
+
+		// Completing twice must not record the sale twice
+		if purchase.Status != models.COSMETIC_PURCHASE_PENDING {
+			return nil
+		}
+
+		if err := tx.Model(&purchase).Updates(map[string]interface{}{
+			"status":     models.COSMETIC_PURCHASE_COMPLETED,
+			"updated_at": gorm.Expr("NOW()"),
+		}).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(&models.CosmeticSale{
+			PurchaseID:   purchase.ID,
+			CosmeticID:   purchase.CosmeticID,
+			BundleID:     purchase.BundleID,
+			WorldID:      purchase.WorldID,
+			BuyerID:      purchase.UserID,
+			CreatorID:    purchase.CreatorID,
+			Price:        purchase.Price,
+			CreatorShare: purchase.CreatorEarnings,
+		}).Error
+	})
+
+	return wrapDatabaseError(err)
+}
+
+func (r *cosmeticPurchasesRepository) RefundPurchase(purchaseId uuid.UUID) error {
+	err := r.db.Conn.Transaction(func(tx *gorm.DB) error {
+		var purchase models.CosmeticPurchase
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", purchaseId).First(&purchase).Error; err != nil {
+			if errors.IsRecordNotFound(err) {
+				return errors.NewNotFoundError("purchase not found")
+			}
+			return err
+		}
+
+		// Refunding twice must not credit the buyer twice
+		if purchase.Status != models.COSMETIC_PURCHASE_PENDING {
+			return nil
+		}
+
+		if err := tx.Model(&purchase).Updates(map[string]interface{}{
+			"status":     models.COSMETIC_PURCHASE_REFUNDED,
+			"updated_at": gorm.Expr("NOW()"),
+		}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.GemBalance{}).Where("user_id = ?", purchase.UserID).UpdateColumn("gems", gorm.Expr("gems + ?", purchase.Price)).Error; err != nil {
+			return err
+		}
+
+		if err := gem_transactions.RecordTransaction(tx, &models.GemTransaction{
+			UserID:      purchase.UserID,
+			Type:        models.GEM_TRANSACTION_REFUND,
+			Amount:      purchase.Price,
+			ReferenceID: purchase.ItemID().String(),
+		}); err != nil {
+			return err
+		}
+
+		if purchase.CreatorID != nil && purchase.CreatorEarnings.GreaterThan(decimal.Zero) {
+			return addToCreatorBalance(tx, *purchase.CreatorID, purchase.CreatorEarnings.Neg())
+		}
+
+		return nil
+	})
+
+	return wrapDatabaseError(err)
+}
+
+/* --- UTILS --- */
+
+func addToCreatorBalance(tx *gorm.DB, creatorId uuid.UUID, amount decimal.Decimal) error {
+	return tx.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"balance":    gorm.Expr("creator_balances.balance + ?", amount),
+			"updated_at": gorm.Expr("NOW()"),
+		}),
+	}).Create(&models.CreatorBalance{UserID: creatorId, Balance: amount}).Error
+}
+
+// wrapDatabaseError keeps the domain errors returned inside a transaction and wraps everything else.
+func wrapDatabaseError(err error) error {
+	switch err.(type) {
+	case nil:
+		return nil
+	case *payment_errors.InsufficientGems, *payment_errors.CosmeticAlreadyPurchased, *payment_errors.GemBalanceLocked,
+		*payment_errors.GiftLimitReached, *errors.HttpError:
+		return err
+	default:
+		return &DatabaseError{message: err.Error()}
+	}
+}
@@ -0,0 +1,219 @@
+package cosmetic_purchases
+
+import (
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/FeedTheRealm-org/core-service/config"
+	payment_errors "github.com/FeedTheRealm-org/core-service/internal/payment-service/errors"
+	"github.com/FeedTheRealm-org/core-service/internal/payment-service/models"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/logger"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var cosmeticPurchasesConf *config.Config
+var cosmeticPurchasesDB *config.DB
+var cosmeticPurchasesRepo CosmeticPurchasesRepository
+
+func TestMain(m *testing.M) {
+	logger.InitLogger(false)
+	cosmeticPurchasesConf = config.CreateConfig()
+	var err error
+	cosmeticPurchasesDB, err = config.NewDB(cosmeticPurchasesConf)
+	if err != nil {
+		panic(err)
+	}
+	cosmeticPurchasesRepo = NewCosmeticPurchasesRepository(cosmeticPurchasesConf, cosmeticPurchasesDB)
+
+	code := m.Run()
+	os.Exit(code)
+}
+
+func createBalance(t *testing.T, userID uuid.UUID, gems int64) {
+	t.Helper()
+	require.NoError(t, cosmeticPurchasesDB.Conn.Create(&models.GemBalance{UserId: userID, Gems: gems}).Error)
+}
+
+func getGems(t *testing.T, userID uuid.UUID) int64 {
+	t.Helper()
+	var balance models.GemBalance
+	require.NoError(t, cosmeticPurchasesDB.Conn.Where("user_id = ?", userID).First(&balance).Error)
+	return balance.Gems
+}
+
+func getCreatorBalance(t *testing.T, creatorID uuid.UUID) decimal.Decimal {
+	t.Helper()
+	var balance models.CreatorBalance
+	require.NoError(t, cosmeticPurchasesDB.Conn.Where("user_id = ?", creatorID).First(&balance).Error)
+	return balance.Balance
+}
+
+func TestCosmeticPurchasesRepository_CreateAndComplete(t *testing.T) {
+	userID := uuid.New()
+	creatorID := uuid.New()
+	worldID := uuid.New()
+	createBalance(t, userID, 30)
+
+	purchase := &models.CosmeticPurchase{UserID: userID, CosmeticID: uuidPtr(uuid.New()), CreatorID: &creatorID, WorldID: &worldID, Price: 20, CreatorEarnings: decimal.NewFromFloat(0.5)}
+	require.NoError(t, cosmeticPurchasesRepo.CreatePendingPurchase(purchase))
+	assert.NotEqual(t, uuid.Nil, purchase.ID)
+	assert.Equal(t, int64(10), getGems(t, userID))
+	assert.True(t, getCreatorBalance(t, creatorID).Equal(decimal.NewFromFloat(0.5)))
+
+	require.NoError(t, cosmeticPurchasesRepo.CompletePurchase(purchase.ID))
+
+	var stored models.CosmeticPurchase
+	require.NoError(t, cosmeticPurchasesDB.Conn.First(&stored, "id = ?", purchase.ID).Error)
+	assert.Equal(t, models.COSMETIC_PURCHASE_COMPLETED, stored.Status)
+
+	// Completing again must not record a second sale
+	require.NoError(t, cosmeticPurchasesRepo.CompletePurchase(purchase.ID))
+
+	var sales []models.CosmeticSale
+	require.NoError(t, cosmeticPurchasesDB.Conn.Where("purchase_id = ?", purchase.ID).Find(&sales).Error)
+	if assert.Len(t, sales, 1) {
+		assert.Equal(t, userID, sales[0].BuyerID)
+		assert.Equal(t, worldID, *sales[0].WorldID)
+		assert.Equal(t, int64(20), sales[0].Price)
+		assert.True(t, sales[0].CreatorShare.Equal(decimal.NewFromFloat(0.5)))
+	}
+}
+
+func TestCosmeticPurchasesRepository_InsufficientGems(t *testing.T) {
+	userID := uuid.New()
+	createBalance(t, userID, 5)
+
+	err := cosmeticPurchasesRepo.CreatePendingPurchase(&models.CosmeticPurchase{UserID: userID, CosmeticID: uuidPtr(uuid.New()), Price: 10})
+	_, insufficient := err.(*payment_errors.InsufficientGems)
+	assert.True(t, insufficient)
+	assert.Equal(t, int64(5), getGems(t, userID))
+}
+
+func TestCosmeticPurchasesRepository_LockedBalance(t *testing.T) {
+	userID := uuid.New()
+	require.NoError(t, cosmeticPurchasesDB.Conn.Create(&models.GemBalance{UserId: userID, Gems: 50, Locked: true}).Error)
+
+	err := cosmeticPurchasesRepo.CreatePendingPurchase(&models.CosmeticPurchase{UserID: userID, CosmeticID: uuidPtr(uuid.New()), Price: 10})
+	_, locked := err.(*payment_errors.GemBalanceLocked)
+	assert.True(t, locked)
+	assert.Equal(t, int64(50), getGems(t, userID))
+}
+
+func TestCosmeticPurchasesRepository_AlreadyPurchased(t *testing.T) {
+	userID := uuid.New()
+	cosmeticID := uuid.New()
+	createBalance(t, userID, 30)
+
+	require.NoError(t, cosmeticPurchasesRepo.CreatePendingPurchase(&models.CosmeticPurchase{UserID: userID, CosmeticID: &cosmeticID, Price: 10}))
+
+	err := cosmeticPurchasesRepo.CreatePendingPurchase(&models.CosmeticPurchase{UserID: userID, CosmeticID: &cosmeticID, Price: 10})
+	_, conflict := err.(*payment_errors.CosmeticAlreadyPurchased)
+	assert.True(t, conflict)
+	assert.Equal(t, int64(20), getGems(t, userID), "the second debit must be rolled back")
+}
+
+func TestCosmeticPurchasesRepository_Refund(t *testing.T) {
+	userID := uuid.New()
+	creatorID := uuid.New()
+	cosmeticID := uuid.New()
+	createBalance(t, userID, 30)
+
+	purchase := &models.CosmeticPurchase{UserID: userID, CosmeticID: &cosmeticID, CreatorID: &creatorID, Price: 20, CreatorEarnings: decimal.NewFromInt(2)}
+	require.NoError(t, cosmeticPurchasesRepo.CreatePendingPurchase(purchase))
+
+	require.NoError(t, cosmeticPurchasesRepo.RefundPurchase(purchase.ID))
+	require.NoError(t, cosmeticPurchasesRepo.RefundPurchase(purchase.ID))
+	assert.Equal(t, int64(30), getGems(t, userID))
+	assert.True(t, getCreatorBalance(t, creatorID).IsZero())
+
+	var ledger []models.GemTransaction
+	require.NoError(t, cosmeticPurchasesDB.Conn.Where("user_id = ?", userID).Order("created_at").Find(&ledger).Error)
+	if assert.Len(t, ledger, 2) {
+		assert.Equal(t, models.GEM_TRANSACTION_PURCHASE, ledger[0].Type)
+		assert.Equal(t, int64(-20), ledger[0].Amount)
+		assert.Equal(t, models.GEM_TRANSACTION_REFUND, ledger[1].Type)
+		assert.Equal(t, cosmeticID.String(), ledger[1].ReferenceID)
+	}
+
+	// A refunded purchase does not block buying the cosmetic again
+	assert.NoError(t, cosmeticPurchasesRepo.CreatePendingPurchase(&models.CosmeticPurchase{UserID: userID, CosmeticID: &cosmeticID, Price: 20}))
+}
+
+func TestCosmeticPurchasesRepository_BundleAlreadyPurchased(t *testing.T) {
+	userID := uuid.New()
+	bundleID := uuid.New()
+	createBalance(t, userID, 100)
+
+	purchase := &models.CosmeticPurchase{UserID: userID, BundleID: &bundleID, Price: 40}
+	require.NoError(t, cosmeticPurchasesRepo.CreatePendingPurchase(purchase))
+	require.NoError(t, cosmeticPurchasesRepo.CompletePurchase(purchase.ID))
+
+	err := cosmeticPurchasesRepo.CreatePendingPurchase(&models.CosmeticPurchase{UserID: userID, BundleID: &bundleID, Price: 40})
+	_, conflict := err.(*payment_errors.CosmeticAlreadyPurchased)
+	assert.True(t, conflict)
+	assert.Equal(t, int64(60), getGems(t, userID))
+
+	var sale models.CosmeticSale
+	require.NoError(t, cosmeticPurchasesDB.Conn.Where("purchase_id = ?", purchase.ID).First(&sale).Error)
+	assert.Equal(t, &bundleID, sale.BundleID)
+	assert.Nil(t, sale.CosmeticID)
+
+	var ledger models.GemTransaction
+	require.NoError(t, cosmeticPurchasesDB.Conn.Where("user_id = ?", userID).First(&ledger).Error)
+	assert.Equal(t, bundleID.String(), ledger.ReferenceID)
+}
+
+func TestCosmeticPurchasesRepository_ConcurrentPurchasesNeverOverdraw(t *testing.T) {
+	userID := uuid.New()
+	createBalance(t, userID, 45)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = cosmeticPurchasesRepo.CreatePendingPurchase(&models.CosmeticPurchase{UserID: userID, CosmeticID: uuidPtr(uuid.New()), Price: 10})
+		}()
+	}
+	wg.Wait()
+
+	var count int64
+	require.NoError(t, cosmeticPurchasesDB.Conn.Model(&models.CosmeticPurchase{}).Where("user_id = ?", userID).Count(&count).Error)
+	assert.Equal(t, int64(4), count)
+	assert.Equal(t, int64(5), getGems(t, userID))
+}
+
+func TestCosmeticPurchasesRepository_GiftDoesNotConflictWithOwnPurchase(t *testing.T) {
+	userID := uuid.New()
+	recipientID := uuid.New()
+	cosmeticID := uuid.New()
+	createBalance(t, userID, 30)
+
+	require.NoError(t, cosmeticPurchasesRepo.CreatePendingPurchase(&models.CosmeticPurchase{UserID: userID, CosmeticID: &cosmeticID, Price: 10}))
+	require.NoError(t, cosmeticPurchasesRepo.CreatePendingGiftPurchase(&models.CosmeticPurchase{UserID: userID, RecipientID: &recipientID, CosmeticID: &cosmeticID, Price: 10}, 5))
+
+	err := cosmeticPurchasesRepo.CreatePendingGiftPurchase(&models.CosmeticPurchase{UserID: userID, RecipientID: &recipientID, CosmeticID: &cosmeticID, Price: 10}, 5)
+	_, conflict := err.(*payment_errors.CosmeticAlreadyPurchased)
+	assert.True(t, conflict)
+	assert.Equal(t, int64(10), getGems(t, userID))
+}
+
+func TestCosmeticPurchasesRepository_GiftDailyLimit(t *testing.T) {
+	userID := uuid.New()
+	createBalance(t, userID, 30)
+
+	require.NoError(t, cosmeticPurchasesRepo.CreatePendingGiftPurchase(&models.CosmeticPurchase{UserID: userID, RecipientID: uuidPtr(uuid.New()), CosmeticID: uuidPtr(uuid.New()), Price: 5}, 1))
+
+	err := cosmeticPurchasesRepo.CreatePendingGiftPurchase(&models.CosmeticPurchase{UserID: userID, RecipientID: uuidPtr(uuid.New()), CosmeticID: uuidPtr(uuid.New()), Price: 5}, 1)
+	_, limited := err.(*payment_errors.GiftLimitReached)
+	assert.True(t, limited)
+	assert.Equal(t, int64(25), getGems(t, userID), "the rejected gift must not be charged")
+}
+
+func uuidPtr(id uuid.UUID) *uuid.UUID {
+	return &id
+}
@@ -0,0 +1,20 @@
+package cosmetic_purchases
+
+import (
+	"github.com/FeedTheRealm-org/core-service/internal/payment-service/models"
+	"github.com/google/uuid"
+)
+
+type CosmeticPurchasesRepository interface {
+	// CreatePendingPurchase debits the buyer, credits the creator and stores the purchase as pending in one transaction.
+	CreatePendingPurchase(purchase *models.CosmeticPurchase) error
+
+	// CreatePendingGiftPurchase works like CreatePendingPurchase but fails once the buyer sent dailyLimit gifts in the last 24 hours.
+	CreatePendingGiftPurchase(purchase *models.CosmeticPurchase, dailyLimit int) error
+
+	// CompletePurchase marks a pending purchase as completed once the cosmetic was granted and records its sale.
+	CompletePurchase(purchaseId uuid.UUID) error
+
+	// RefundPurchase reverts the debit and creator credit of a pending purchase.
+	RefundPurchase(purchaseId uuid.UUID) error
+}
@@ -0,0 +1,104 @@
+package cosmetic_sales
+
+import (
+	"fmt"
+
+	"github.com/FeedTheRealm-org/core-service/config"
+	"github.com/FeedTheRealm-org/core-service/internal/payment-service/models"
+	"gorm.io/gorm"
+)
+
+type DatabaseError struct {
+	message string
+}
+
+func (e *DatabaseError) Error() string {
+	return "Database error occurred: " + e.message
+}
+
+var groupByColumns = map[string]string{
+	models.SALES_GROUP_BY_COSMETIC: "cosmetic_id",
+	models.SALES_GROUP_BY_WORLD:    "world_id",
+	models.SALES_GROUP_BY_CREATOR:  "creator_id",
+	models.SALES_GROUP_BY_BUNDLE:   "bundle_id",
+}
+
+type cosmeticSalesRepository struct {
+	conf *config.Config
+	db   *config.DB
+}
+
+func NewCosmeticSalesRepository(conf *config.Config, db *config.DB) CosmeticSalesRepository {
+	return &cosmeticSalesRepository{
+		conf: conf,
+		db:   db,
+	}
+}
+
+func (r *cosmeticSalesRepository) GetSalesBuckets(filter CosmeticSalesFilter, interval string) ([]*models.CosmeticSalesBucket, error) {
+	if !models.IsValidSalesInterval(interval) {
+		return nil, &DatabaseError{message: "invalid sales interval " + interval}
+	}
+
+	var buckets []*models.CosmeticSalesBucket
+	err := r.filtered(filter).
+		Select("date_trunc(?, created_at AT TIME ZONE 'UTC') AS period_start, COUNT(*) AS sales_count, "+
+			"COALESCE(SUM(price), 0) AS gems_revenue, COALESCE(SUM(creator_share), 0) AS creator_share", interval).
+		Group("1").
+		Order("1").
+		Scan(&buckets).Error
+	if err != nil {
+		return nil, &DatabaseError{message: err.Error()}
+	}
+
+	return buckets, nil
+}
+
+func (r *cosmeticSalesRepository) GetTopSellers(filter CosmeticSalesFilter, groupBy string, limit int) ([]*models.CosmeticSalesRanking, error) {
+	column, ok := groupByColumns[groupBy]
+	if !ok {
+		return nil, &DatabaseError{message: "invalid sales grouping " + groupBy}
+	}
+
+	var rankings []*models.CosmeticSalesRanking
+	err := r.filtered(filter).
+		Select(fmt.Sprintf("%s AS id, COUNT(*) AS sales_count, COALESCE(SUM(price), 0) AS gems_revenue, "+
+			"COALESCE(SUM(creator_share), 0) AS creator_share", column)).
+		Where(column + " IS NOT NULL").
+		Group(column).
+		Order("sales_count DESC, gems_revenue DESC, id ASC").
+		Limit(limit).
+		Scan(&rankings).Error
+	if err != nil {
+		return nil, &DatabaseError{message: err.Error()}
+	}
+
+	return rankings, nil
+}
+
+/* --- UTILS --- */
+
+func (r *cosmeticSalesRepository) filtered(filter CosmeticSalesFilter) *gorm.DB {
+	query := r.db.Conn.Model(&models.CosmeticSale{})
+
+	if filter.CosmeticID != nil {
+		query = query.Where("cosmetic_id = ?", *filter.CosmeticID)
+	}
+	if filter.BundleID != nil {
+		query = query.Where("bundle_id = ?", *filter.BundleID)
+	}
+	if filter.WorldID != nil {
+		query = query.Where("world_id = ?", *filter.WorldID)
+	}
+	if filter.CreatorID != nil {
+		query = query.Where("creator_id = ?", *filter.CreatorID)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("created_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("created_at < ?", filter.To)
+	}
+
+	return query
+}
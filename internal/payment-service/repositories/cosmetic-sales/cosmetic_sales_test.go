@@ -0,0 +1,122 @@
+package cosmetic_sales
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/FeedTheRealm-org/core-service/config"
+	"github.com/FeedTheRealm-org/core-service/internal/payment-service/models"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/logger"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var cosmeticSalesConf *config.Config
+var cosmeticSalesDB *config.DB
+var cosmeticSalesRepo CosmeticSalesRepository
+
+func TestMain(m *testing.M) {
+	logger.InitLogger(false)
+	cosmeticSalesConf = config.CreateConfig()
+	var err error
+	cosmeticSalesDB, err = config.NewDB(cosmeticSalesConf)
+	if err != nil {
+		panic(err)
+	}
+	cosmeticSalesRepo = NewCosmeticSalesRepository(cosmeticSalesConf, cosmeticSalesDB)
+
+	code := m.Run()
+	os.Exit(code)
+}
+
+func createSale(t *testing.T, cosmeticID uuid.UUID, worldID uuid.UUID, creatorID uuid.UUID, price int64, createdAt time.Time) {
+	t.Helper()
+	purchase := &models.CosmeticPurchase{
+		UserID:     uuid.New(),
+		CosmeticID: &cosmeticID,
+		Price:      price,
+		Status:     models.COSMETIC_PURCHASE_COMPLETED,
+	}
+	require.NoError(t, cosmeticSalesDB.Conn.Create(purchase).Error)
+	require.NoError(t, cosmeticSalesDB.Conn.Create(&models.CosmeticSale{
+		PurchaseID:   purchase.ID,
+		CosmeticID:   &cosmeticID,
+		WorldID:      &worldID,
+		BuyerID:      purchase.UserID,
+		CreatorID:    &creatorID,
+		Price:        price,
+		CreatorShare: decimal.NewFromInt(price).Div(decimal.NewFromInt(10)),
+		CreatedAt:    createdAt,
+	}).Error)
+}
+
+func TestCosmeticSalesRepository_GetSalesBuckets(t *testing.T) {
+	creatorID := uuid.New()
+	worldID := uuid.New()
+	cosmeticID := uuid.New()
+	day := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+
+	createSale(t, cosmeticID, worldID, creatorID, 10, day)
+	createSale(t, cosmeticID, worldID, creatorID, 20, day.Add(time.Hour))
+	createSale(t, cosmeticID, worldID, creatorID, 30, day.AddDate(0, 0, 1))
+	createSale(t, uuid.New(), uuid.New(), uuid.New(), 99, day)
+
+	filter := CosmeticSalesFilter{CreatorID: &creatorID}
+	buckets, err := cosmeticSalesRepo.GetSalesBuckets(filter, models.SALES_INTERVAL_DAY)
+	require.NoError(t, err)
+	if assert.Len(t, buckets, 2) {
+		assert.True(t, buckets[0].PeriodStart.Equal(time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)))
+		assert.Equal(t, int64(2), buckets[0].SalesCount)
+		assert.Equal(t, int64(30), buckets[0].GemsRevenue)
+		assert.True(t, buckets[0].CreatorShare.Equal(decimal.NewFromInt(3)))
+		assert.Equal(t, int64(1), buckets[1].SalesCount)
+	}
+
+	buckets, err = cosmeticSalesRepo.GetSalesBuckets(filter, models.SALES_INTERVAL_MONTH)
+	require.NoError(t, err)
+	if assert.Len(t, buckets, 1) {
+		assert.Equal(t, int64(3), buckets[0].SalesCount)
+		assert.Equal(t, int64(60), buckets[0].GemsRevenue)
+	}
+
+	filter.From = day.AddDate(0, 0, 1)
+	buckets, err = cosmeticSalesRepo.GetSalesBuckets(filter, models.SALES_INTERVAL_DAY)
+	require.NoError(t, err)
+	if assert.Len(t, buckets, 1) {
+		assert.Equal(t, int64(30), buckets[0].GemsRevenue)
+	}
+}
+
+func TestCosmeticSalesRepository_GetTopSellers(t *testing.T) {
+	creatorID := uuid.New()
+	worldA := uuid.New()
+	worldB := uuid.New()
+	bestSeller := uuid.New()
+	other := uuid.New()
+	now := time.Now()
+
+	createSale(t, bestSeller, worldA, creatorID, 5, now)
+	createSale(t, bestSeller, worldA, creatorID, 5, now)
+	createSale(t, other, worldB, creatorID, 50, now)
+
+	filter := CosmeticSalesFilter{CreatorID: &creatorID}
+	rankings, err := cosmeticSalesRepo.GetTopSellers(filter, models.SALES_GROUP_BY_COSMETIC, 10)
+	require.NoError(t, err)
+	if assert.Len(t, rankings, 2) {
+		assert.Equal(t, bestSeller, rankings[0].ID)
+		assert.Equal(t, int64(2), rankings[0].SalesCount)
+		assert.Equal(t, other, rankings[1].ID)
+	}
+
+	rankings, err = cosmeticSalesRepo.GetTopSellers(filter, models.SALES_GROUP_BY_WORLD, 1)
+	require.NoError(t, err)
+	if assert.Len(t, rankings, 1) {
+		assert.Equal(t, worldA, rankings[0].ID)
+	}
+
+	_, err = cosmeticSalesRepo.GetTopSellers(filter, "buyer", 10)
+	assert.Error(t, err)
+}
@@ -0,0 +1,26 @@
+package cosmetic_sales
+
+import (
+	"time"
+
+	"github.com/FeedTheRealm-org/core-service/internal/payment-service/models"
+	"github.com/google/uuid"
+)
+
+// CosmeticSalesFilter narrows the sales being aggregated, zero values are ignored.
+type CosmeticSalesFilter struct {
+	CosmeticID *uuid.UUID
+	BundleID   *uuid.UUID
+	WorldID    *uuid.UUID
+	CreatorID  *uuid.UUID
+	From       time.Time
+	To         time.Time
+}
+
+type CosmeticSalesRepository interface {
+	// GetSalesBuckets aggregates the sales matching the filter per day, week or month, oldest first.
+	GetSalesBuckets(filter CosmeticSalesFilter, interval string) ([]*models.CosmeticSalesBucket, error)
+
+	// GetTopSellers aggregates the sales matching the filter per cosmetic, bundle, world or creator, best selling first.
+	GetTopSellers(filter CosmeticSalesFilter, groupBy string, limit int) ([]*models.CosmeticSalesRanking, error)
+}
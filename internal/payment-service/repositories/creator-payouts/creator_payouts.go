@@ -0,0 +1,246 @@
+package creator_payouts
+
+import (
+	"fmt"
+
+	"github.com/FeedTheRealm-org/core-service/config"
+	"github.com/FeedTheRealm-org/core-service/internal/errors"
+	payment_errors "github.com/FeedTheRealm-org/core-service/internal/payment-service/errors"
+	"github.com/FeedTheRealm-org/core-service/internal/payment-service/models"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type DatabaseError struct {
+	message string
+}
+
+func (e *DatabaseError) Error() string {
+	return "Database error occurred: " + e.message
+}
+
+type creatorPayoutsRepository struct {
+	conf *config.Config
+	db   *config.DB
+}
+
+func NewCreatorPayoutsRepository(conf *config.Config, db *config.DB) CreatorPayoutsRepository {
+	return &creatorPayoutsRepository{
+		conf: conf,
+		db:   db,
+	}
+}
+
+func (r *creatorPayoutsRepository) SetPayoutAccount(userId uuid.UUID, accountId string) error {
+	err := r.db.Conn.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"payout_account_id": accountId,
+			"updated_at":        gorm.Expr("NOW()"),
+		}),
+	}).Create(&models.CreatorBalance{UserID: userId, Balance: decimal.Zero, PayoutAccountID: accountId}).Error
+
+	return wrapDatabaseError(err)
+}
+
+func (r *creatorPayoutsRepository) GetPayoutAccount(userId uuid.UUID) (string, error) {
+	var balance models.CreatorBalance
+	if err := r.db.Conn.Where("user_id = ?", userId).Limit(1).Find(&balance).Error; err != nil {
+		return "", wrapDatabaseError(err)
+	}
+
+	return balance.PayoutAccountID, nil
+}
+
+func (r *creatorPayoutsRepository) CreatePayoutRequest(payout *models.CreatorPayout) error {
+	payout.Status = models.CREATOR_PAYOUT_REQUESTED
+
+	err := r.db.Conn.Transaction(func(tx *gorm.DB) error {
+		// Conditional hold, concurrent requests can never take the balance below zero
+		result := tx.Model(&models.CreatorBalance{}).
+			Where("user_id = ? AND balance >= ?", payout.UserID, payout.Amount).
+			Updates(map[string]interface{}{
+				"balance":      gorm.Expr("balance - ?", payout.Amount),
+				"held_balance": gorm.Expr("held_balance + ?", payout.Amount),
+				"updated_at":   gorm.Expr("NOW()"),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return payment_errors.NewInsufficientCreatorBalance("insufficient creator balance for this payout")
+		}
+
+		if err := tx.Create(payout).Error; err != nil {
+			if errors.IsDuplicateEntryError(err) {
+				return payment_errors.NewPayoutAlreadyPending("a payout is already pending for this creator")
+			}
+			return err
+		}
+
+		return recordPayoutEvent(tx, payout, &payout.UserID, "")
+	})
+
+	return wrapDatabaseError(err)
+}
+
+func (r *creatorPayoutsRepository) GetPayoutById(payoutId uuid.UUID) (*models.CreatorPayout, error) {
+	var payout models.CreatorPayout
+	if err := r.db.Conn.Where("id = ?", payoutId).First(&payout).Error; err != nil {
+		if errors.IsRecordNotFound(err) {
+			return nil, errors.NewNotFoundError("payout not found")
+		}
+		return nil, wrapDatabaseError(err)
+	}
+
+	return &payout, nil
+}
+
+func (r *creatorPayoutsRepository) ListPayouts(filter CreatorPayoutsFilter, offset int, limit int) ([]*models.CreatorPayout, int64, error) {
+	var payouts []*models.CreatorPayout
+	query := r.db.Conn.Model(&models.CreatorPayout{})
+
+	if filter.UserID != nil {
+		query = query.Where("user_id = ?", *filter.UserID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, wrapDatabaseError(err)
+	}
+
+	if err := query.Order("created_at asc, id asc").Offset(offset).Limit(limit).Find(&payouts).Error; err != nil {
+		return nil, 0, wrapDatabaseError(err)
+	}
+
+	return payouts, total, nil
+}
+
+func (r *creatorPayoutsRepository) ApprovePayout(payoutId uuid.UUID, actorId uuid.UUID) (*models.CreatorPayout, error) {
+	return r.transition(payoutId, []string{models.CREATOR_PAYOUT_REQUESTED}, models.CREATOR_PAYOUT_APPROVED, &actorId, "", nil)
+}
+
+func (r *creatorPayoutsRepository) MarkPayoutPaid(payoutId uuid.UUID, providerReference string, actorId uuid.UUID) (*models.CreatorPayout, error) {
+	return r.transition(payoutId, []string{models.CREATOR_PAYOUT_APPROVED}, models.CREATOR_PAYOUT_PAID, &actorId, providerReference,
+		func(tx *gorm.DB, payout *models.CreatorPayout) error {
+			payout.ProviderReference = providerReference
+			return tx.Model(&models.CreatorBalance{}).Where("user_id = ?", payout.UserID).Updates(map[string]interface{}{
+				"held_balance": gorm.Expr("held_balance - ?", payout.Amount),
+				"updated_at":   gorm.Expr("NOW()"),
+			}).Error
+		},
+	)
+}
+
+func (r *creatorPayoutsRepository) RejectPayout(payoutId uuid.UUID, actorId uuid.UUID, reason string) (*models.CreatorPayout, error) {
+	return r.transition(payoutId, []string{models.CREATOR_PAYOUT_REQUESTED}, models.CREATOR_PAYOUT_REJECTED, &actorId, reason, releaseHold(reason))
+}
+
+func (r *creatorPayoutsRepository) FailPayout(payoutId uuid.UUID, actorId uuid.UUID, reason string) (*models.CreatorPayout, error) {
+	return r.transition(payoutId, []string{models.CREATOR_PAYOUT_APPROVED}, models.CREATOR_PAYOUT_FAILED, &actorId, reason, releaseHold(reason))
+}
+
+func (r *creatorPayoutsRepository) GetPayoutHistory(payoutId uuid.UUID) ([]*models.CreatorPayoutEvent, error) {
+	var events []*models.CreatorPayoutEvent
+	if err := r.db.Conn.Where("payout_id = ?", payoutId).Order("created_at asc, id asc").Find(&events).Error; err != nil {
+		return nil, wrapDatabaseError(err)
+	}
+
+	return events, nil
+}
+
+/* --- UTILS --- */
+
+// transition locks the payout, checks it is in one of the allowed statuses and applies the change with its history entry.
+func (r *creatorPayoutsRepository) transition(
+	payoutId uuid.UUID,
+	from []string,
+	to string,
+	actorId *uuid.UUID,
+	note string,
+	apply func(tx *gorm.DB, payout *models.CreatorPayout) error,
+) (*models.CreatorPayout, error) {
+	var payout models.CreatorPayout
+
+	err := r.db.Conn.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", payoutId).First(&payout).Error; err != nil {
+			if errors.IsRecordNotFound(err) {
+				return errors.NewNotFoundError("payout not found")
+			}
+			return err
+		}
+
+		allowed := false
+		for _, status := range from {
+			if payout.Status == status {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return payment_errors.NewInvalidPayoutTransition(fmt.Sprintf("cannot move a %s payout to %s", payout.Status, to))
+		}
+
+		if apply != nil {
+			if err := apply(tx, &payout); err != nil {
+				return err
+			}
+		}
+
+		payout.Status = to
+		if err := tx.Model(&payout).Updates(map[string]interface{}{
+			"status":             payout.Status,
+			"provider_reference": payout.ProviderReference,
+			"rejection_reason":   payout.RejectionReason,
+			"updated_at":         gorm.Expr("NOW()"),
+		}).Error; err != nil {
+			return err
+		}
+
+		return recordPayoutEvent(tx, &payout, actorId, note)
+	})
+	if err != nil {
+		return nil, wrapDatabaseError(err)
+	}
+
+	return &payout, nil
+}
+
+// releaseHold returns the held amount of the payout to the creator balance.
+func releaseHold(reason string) func(tx *gorm.DB, payout *models.CreatorPayout) error {
+	return func(tx *gorm.DB, payout *models.CreatorPayout) error {
+		payout.RejectionReason = reason
+		return tx.Model(&models.CreatorBalance{}).Where("user_id = ?", payout.UserID).Updates(map[string]interface{}{
+			"balance":      gorm.Expr("balance + ?", payout.Amount),
+			"held_balance": gorm.Expr("held_balance - ?", payout.Amount),
+			"updated_at":   gorm.Expr("NOW()"),
+		}).Error
+	}
+}
+
+func recordPayoutEvent(tx *gorm.DB, payout *models.CreatorPayout, actorId *uuid.UUID, note string) error {
+	return tx.Create(&models.CreatorPayoutEvent{
+		PayoutID: payout.ID,
+		Status:   payout.Status,
+		ActorID:  actorId,
+		Note:     note,
+	}).Error
+}
+
+// wrapDatabaseError keeps the domain errors returned inside a transaction and wraps everything else.
+func wrapDatabaseError(err error) error {
+	switch err.(type) {
+	case nil:
+		return nil
+	case *payment_errors.InsufficientCreatorBalance, *payment_errors.PayoutAlreadyPending,
+		*payment_errors.InvalidPayoutTransition, *errors.HttpError:
+		return err
+	default:
+		return &DatabaseError{message: err.Error()}
+	}
+}
@@ -0,0 +1,162 @@
+package creator_payouts
+
+import (
+	"os"
+	"testing"
+
+	"github.com/FeedTheRealm-org/core-service/config"
+	payment_errors "github.com/FeedTheRealm-org/core-service/internal/payment-service/errors"
+	"github.com/FeedTheRealm-org/core-service/internal/payment-service/models"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/logger"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+var creatorPayoutsConf *config.Config
+var creatorPayoutsDB *config.DB
+var creatorPayoutsRepo CreatorPayoutsRepository
+
+func TestMain(m *testing.M) {
+	logger.InitLogger(false)
+	creatorPayoutsConf = config.CreateConfig()
+	var err error
+	creatorPayoutsDB, err = config.NewDB(creatorPayoutsConf)
+	if err != nil {
+		panic(err)
+	}
+	creatorPayoutsRepo = NewCreatorPayoutsRepository(creatorPayoutsConf, creatorPayoutsDB)
+
+	code := m.Run()
+	os.Exit(code)
+}
+
+func createCreatorWithBalance(t *testing.T, amount int64) uuid.UUID {
+	userID := uuid.New()
+	assert.NoError(t, creatorPayoutsRepo.SetPayoutAccount(userID, "acct_"+userID.String()))
+	assert.NoError(t, creatorPayoutsDB.Conn.Model(&models.CreatorBalance{}).Where("user_id = ?", userID).Update("balance", decimal.NewFromInt(amount)).Error)
+	return userID
+}
+
+func getCreatorBalance(t *testing.T, userID uuid.UUID) models.CreatorBalance {
+	var balance models.CreatorBalance
+	assert.NoError(t, creatorPayoutsDB.Conn.Where("user_id = ?", userID).First(&balance).Error)
+	return balance
+}
+
+func TestCreatorPayoutsRepository_RequestHoldsBalance(t *testing.T) {
+	userID := createCreatorWithBalance(t, 100)
+
+	payout := &models.CreatorPayout{UserID: userID, Amount: decimal.NewFromInt(40), PayoutAccountID: "acct_1"}
+	assert.NoError(t, creatorPayoutsRepo.CreatePayoutRequest(payout))
+	assert.Equal(t, models.CREATOR_PAYOUT_REQUESTED, payout.Status)
+
+	balance := getCreatorBalance(t, userID)
+	assert.True(t, balance.Balance.Equal(decimal.NewFromInt(60)))
+	assert.True(t, balance.HeldBalance.Equal(decimal.NewFromInt(40)))
+}
+
+func TestCreatorPayoutsRepository_RequestInsufficientBalance(t *testing.T) {
+	userID := createCreatorWithBalance(t, 10)
+
+	err := creatorPayoutsRepo.CreatePayoutRequest(&models.CreatorPayout{UserID: userID, Amount: decimal.NewFromInt(40)})
+	assert.IsType(t, &payment_errors.InsufficientCreatorBalance{}, err)
+
+	balance := getCreatorBalance(t, userID)
+	assert.True(t, balance.Balance.Equal(decimal.NewFromInt(10)))
+	assert.True(t, balance.HeldBalance.IsZero())
+}
+
+func TestCreatorPayoutsRepository_OnlyOnePendingPayout(t *testing.T) {
+	userID := createCreatorWithBalance(t, 100)
+
+	assert.NoError(t, creatorPayoutsRepo.CreatePayoutRequest(&models.CreatorPayout{UserID: userID, Amount: decimal.NewFromInt(20)}))
+	err := creatorPayoutsRepo.CreatePayoutRequest(&models.CreatorPayout{UserID: userID, Amount: decimal.NewFromInt(20)})
+	assert.IsType(t, &payment_errors.PayoutAlreadyPending{}, err)
+
+	// The failed request must not keep its hold
+	balance := getCreatorBalance(t, userID)
+	assert.True(t, balance.Balance.Equal(decimal.NewFromInt(80)))
+	assert.True(t, balance.HeldBalance.Equal(decimal.NewFromInt(20)))
+}
+
+func TestCreatorPayoutsRepository_RejectReturnsHold(t *testing.T) {
+	userID := createCreatorWithBalance(t, 100)
+	adminID := uuid.New()
+
+	payout := &models.CreatorPayout{UserID: userID, Amount: decimal.NewFromInt(40)}
+	assert.NoError(t, creatorPayoutsRepo.CreatePayoutRequest(payout))
+
+	rejected, err := creatorPayoutsRepo.RejectPayout(payout.ID, adminID, "invalid account")
+	assert.NoError(t, err)
+	assert.Equal(t, models.CREATOR_PAYOUT_REJECTED, rejected.Status)
+	assert.Equal(t, "invalid account", rejected.RejectionReason)
+
+	balance := getCreatorBalance(t, userID)
+	assert.True(t, balance.Balance.Equal(decimal.NewFromInt(100)))
+	assert.True(t, balance.HeldBalance.IsZero())
+
+	_, err = creatorPayoutsRepo.RejectPayout(payout.ID, adminID, "again")
+	assert.IsType(t, &payment_errors.InvalidPayoutTransition{}, err)
+}
+
+func TestCreatorPayoutsRepository_ApprovedCanOnlyFail(t *testing.T) {
+	userID := createCreatorWithBalance(t, 100)
+	adminID := uuid.New()
+
+	payout := &models.CreatorPayout{UserID: userID, Amount: decimal.NewFromInt(40)}
+	assert.NoError(t, creatorPayoutsRepo.CreatePayoutRequest(payout))
+	_, err := creatorPayoutsRepo.ApprovePayout(payout.ID, adminID)
+	assert.NoError(t, err)
+
+	_, err = creatorPayoutsRepo.RejectPayout(payout.ID, adminID, "too late")
+	assert.IsType(t, &payment_errors.InvalidPayoutTransition{}, err)
+
+	failed, err := creatorPayoutsRepo.FailPayout(payout.ID, adminID, "no transfer was sent")
+	assert.NoError(t, err)
+	assert.Equal(t, models.CREATOR_PAYOUT_FAILED, failed.Status)
+
+	balance := getCreatorBalance(t, userID)
+	assert.True(t, balance.Balance.Equal(decimal.NewFromInt(100)))
+	assert.True(t, balance.HeldBalance.IsZero())
+}
+
+func TestCreatorPayoutsRepository_PaidReleasesHoldAndRecordsHistory(t *testing.T) {
+	userID := createCreatorWithBalance(t, 100)
+	adminID := uuid.New()
+
+	payout := &models.CreatorPayout{UserID: userID, Amount: decimal.NewFromInt(40)}
+	assert.NoError(t, creatorPayoutsRepo.CreatePayoutRequest(payout))
+
+	_, err := creatorPayoutsRepo.MarkPayoutPaid(payout.ID, "tr_1", adminID)
+	assert.IsType(t, &payment_errors.InvalidPayoutTransition{}, err)
+
+	_, err = creatorPayoutsRepo.ApprovePayout(payout.ID, adminID)
+	assert.NoError(t, err)
+	paid, err := creatorPayoutsRepo.MarkPayoutPaid(payout.ID, "tr_1", adminID)
+	assert.NoError(t, err)
+	assert.Equal(t, models.CREATOR_PAYOUT_PAID, paid.Status)
+	assert.Equal(t, "tr_1", paid.ProviderReference)
+
+	balance := getCreatorBalance(t, userID)
+	assert.True(t, balance.Balance.Equal(decimal.NewFromInt(60)))
+	assert.True(t, balance.HeldBalance.IsZero())
+
+	history, err := creatorPayoutsRepo.GetPayoutHistory(payout.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, history, 3) {
+		assert.Equal(t, models.CREATOR_PAYOUT_REQUESTED, history[0].Status)
+		assert.Equal(t, userID, *history[0].ActorID)
+		assert.Equal(t, models.CREATOR_PAYOUT_APPROVED, history[1].Status)
+		assert.Equal(t, models.CREATOR_PAYOUT_PAID, history[2].Status)
+		assert.Equal(t, adminID, *history[2].ActorID)
+	}
+
+	// Once paid, a new payout can be requested
+	assert.NoError(t, creatorPayoutsRepo.CreatePayoutRequest(&models.CreatorPayout{UserID: userID, Amount: decimal.NewFromInt(10)}))
+
+	payouts, total, err := creatorPayoutsRepo.ListPayouts(CreatorPayoutsFilter{UserID: &userID}, 0, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Len(t, payouts, 2)
+}
@@ -0,0 +1,44 @@
+package creator_payouts
+
+import (
+	"github.com/FeedTheRealm-org/core-service/internal/payment-service/models"
+	"github.com/google/uuid"
+)
+
+// CreatorPayoutsFilter narrows the payouts listing, zero values are ignored.
+type CreatorPayoutsFilter struct {
+	UserID *uuid.UUID
+	Status string
+}
+
+type CreatorPayoutsRepository interface {
+	// SetPayoutAccount stores the provider account payouts of the creator are sent to.
+	SetPayoutAccount(userId uuid.UUID, accountId string) error
+
+	// GetPayoutAccount returns the provider account of the creator, empty when none was set.
+	GetPayoutAccount(userId uuid.UUID) (string, error)
+
+	// CreatePayoutRequest holds the amount from the creator balance and stores the payout as requested.
+	CreatePayoutRequest(payout *models.CreatorPayout) error
+
+	// GetPayoutById returns a single payout.
+	GetPayoutById(payoutId uuid.UUID) (*models.CreatorPayout, error)
+
+	// ListPayouts returns a page of payouts, oldest first, and the total count.
+	ListPayouts(filter CreatorPayoutsFilter, offset int, limit int) ([]*models.CreatorPayout, int64, error)
+
+	// ApprovePayout moves a requested payout to approved.
+	ApprovePayout(payoutId uuid.UUID, actorId uuid.UUID) (*models.CreatorPayout, error)
+
+	// MarkPayoutPaid moves an approved payout to paid and releases its hold.
+	MarkPayoutPaid(payoutId uuid.UUID, providerReference string, actorId uuid.UUID) (*models.CreatorPayout, error)
+
+	// RejectPayout moves a requested payout to rejected and returns its hold to the creator balance.
+	RejectPayout(payoutId uuid.UUID, actorId uuid.UUID, reason string) (*models.CreatorPayout, error)
+
+	// FailPayout moves an approved payout whose transfer never happened to failed and returns its hold to the creator balance.
+	FailPayout(payoutId uuid.UUID, actorId uuid.UUID, reason string) (*models.CreatorPayout, error)
+
+	// GetPayoutHistory returns every status change of the payout, oldest first.
+	GetPayoutHistory(payoutId uuid.UUID) ([]*models.CreatorPayoutEvent, error)
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/FeedTheRealm-org/core-service/config"
 	"github.com/FeedTheRealm-org/core-service/internal/errors"
 	"github.com/FeedTheRealm-org/core-service/internal/payment-service/models"
+	gem_transactions "github.com/FeedTheRealm-org/core-service/internal/payment-service/repositories/gem-transactions"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
@@ -61,8 +62,19 @@ func (br *gemBalancesRepository) GetGemBalanceByUserId(userId uuid.UUID) (*model
 	return &balance, nil
 }
 
-func (br *gemBalancesRepository) AddToGemBalance(userId uuid.UUID, gems int64) error {
-	return br.db.Conn.Model(&models.GemBalance{}).Where("user_id = ?", userId).UpdateColumn("gems", gorm.Expr("gems + ?", gems)).Error
+func (br *gemBalancesRepository) AddToGemBalance(transaction *models.GemTransaction) error {
+	err := br.db.Conn.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.GemBalance{}).Where("user_id = ?", transaction.UserID).UpdateColumn("gems", gorm.Expr("gems + ?", transaction.Amount)).Error; err != nil {
+			return err
+		}
+
+		return gem_transactions.RecordTransaction(tx, transaction)
+	})
+	if err != nil {
+		return &DatabaseError{message: err.Error()}
+	}
+
+	return nil
 }
 
 func (br *gemBalancesRepository) ApplyStripeCheckoutCreditIfUnprocessed(userId uuid.UUID, gems int64, eventID string, sessionID string) (bool, error) {
@@ -93,6 +105,63 @@ func (br *gemBalancesRepository) ApplyStripeCheckoutCreditIfUnprocessed(userId u
 			return err
 		}
 
+		if err := gem_transactions.RecordTransaction(tx, &models.GemTransaction{
+			UserID:      userId,
+			Type:        models.GEM_TRANSACTION_STRIPE_CREDIT,
+			Amount:      gems,
+			ReferenceID: sessionID,
+		}); err != nil {
+			return err
+		}
+
+		applied = true
+		return nil
+	})
+
+	if err != nil {
+		return false, &DatabaseError{message: err.Error()}
+	}
+
+	return applied, nil
+}
+
+// ApplyStripeAdjustmentIfUnprocessed records a refund or dispute event once, applying its ledger entry
+// and lock change. The balance is allowed to go negative since the reversed gems may already be spent.
+func (br *gemBalancesRepository) ApplyStripeAdjustmentIfUnprocessed(event *models.ProcessedStripeWebhookEvent, transaction *models.GemTransaction, locked *bool) (bool, error) {
+	applied := false
+
+	err := br.db.Conn.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(event)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.GemBalance{UserId: transaction.UserID, Gems: 0}).Error; err != nil {
+			return err
+		}
+
+		updates := map[string]interface{}{
+			"gems":       gorm.Expr("gems + ?", transaction.Amount),
+			"updated_at": gorm.Expr("NOW()"),
+		}
+		if locked != nil {
+			updates["locked"] = *locked
+		}
+
+		if err := tx.Model(&models.GemBalance{}).Where("user_id = ?", transaction.UserID).UpdateColumns(updates).Error; err != nil {
+			return err
+		}
+
+		if transaction.Amount != 0 {
+			if err := gem_transactions.RecordTransaction(tx, transaction); err != nil {
+				return err
+			}
+		}
+
 		applied = true
 		return nil
 	})
@@ -104,9 +173,37 @@ func (br *gemBalancesRepository) ApplyStripeCheckoutCreditIfUnprocessed(userId u
 	return applied, nil
 }
 
-func (br *gemBalancesRepository) UpsertGemBalance(userId uuid.UUID, newGems int64) error {
-	return br.db.Conn.Clauses(clause.OnConflict{
-		Columns:   []clause.Column{{Name: "user_id"}},
-		DoUpdates: clause.AssignmentColumns([]string{"gems"}),
-	}).Create(&models.GemBalance{UserId: userId, Gems: newGems}).Error
+func (br *gemBalancesRepository) UpsertGemBalance(userId uuid.UUID, newGems int64, actorId uuid.UUID) error {
+	err := br.db.Conn.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.GemBalance{UserId: userId, Gems: 0}).Error; err != nil {
+			return err
+		}
+
+		// Lock the balance so the recorded adjustment matches the value it replaces
+		var balance models.GemBalance
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("user_id = ?", userId).First(&balance).Error; err != nil {
+			return err
+		}
+
+		delta := newGems - balance.Gems
+		if delta == 0 {
+			return nil
+		}
+
+		if err := tx.Model(&balance).UpdateColumn("gems", newGems).Error; err != nil {
+			return err
+		}
+
+		return gem_transactions.RecordTransaction(tx, &models.GemTransaction{
+			UserID:  userId,
+			Type:    models.GEM_TRANSACTION_ADMIN_ADJUSTMENT,
+			Amount:  delta,
+			ActorID: &actorId,
+		})
+	})
+	if err != nil {
+		return &DatabaseError{message: err.Error()}
+	}
+
+	return nil
 }
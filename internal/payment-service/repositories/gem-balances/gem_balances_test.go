@@ -7,6 +7,8 @@ import (
 
 	"github.com/FeedTheRealm-org/core-service/config"
 	core_errors "github.com/FeedTheRealm-org/core-service/internal/errors"
+	"github.com/FeedTheRealm-org/core-service/internal/payment-service/models"
+	gem_transactions "github.com/FeedTheRealm-org/core-service/internal/payment-service/repositories/gem-transactions"
 	"github.com/FeedTheRealm-org/core-service/internal/utils/logger"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -73,7 +75,7 @@ func TestGemBalancesRepository_AddToGemBalance(t *testing.T) {
 	userID := uuid.New()
 	assert.NoError(t, gemBalancesRepo.CreateGemBalance(userID))
 
-	assert.NoError(t, gemBalancesRepo.AddToGemBalance(userID, 25))
+	assert.NoError(t, gemBalancesRepo.AddToGemBalance(&models.GemTransaction{UserID: userID, Type: models.GEM_TRANSACTION_ADMIN_ADJUSTMENT, Amount: 25}))
 	balance, err := gemBalancesRepo.GetGemBalanceByUserId(userID)
 	if err == nil {
 		assert.Equal(t, int64(25), balance.Gems)
@@ -107,15 +109,71 @@ func TestGemBalancesRepository_UpsertGemBalance(t *testing.T) {
 	clearGemBalancesTables()
 
 	userID := uuid.New()
-	assert.NoError(t, gemBalancesRepo.UpsertGemBalance(userID, 15))
+	assert.NoError(t, gemBalancesRepo.UpsertGemBalance(userID, 15, uuid.New()))
 	balance, err := gemBalancesRepo.GetGemBalanceByUserId(userID)
 	if err == nil {
 		assert.Equal(t, int64(15), balance.Gems)
 	}
 
-	assert.NoError(t, gemBalancesRepo.UpsertGemBalance(userID, 99))
+	assert.NoError(t, gemBalancesRepo.UpsertGemBalance(userID, 99, uuid.New()))
 	balance, err = gemBalancesRepo.GetGemBalanceByUserId(userID)
 	if err == nil {
 		assert.Equal(t, int64(99), balance.Gems)
 	}
 }
+
+func TestGemBalancesRepository_MutationsAreRecordedInLedger(t *testing.T) {
+	clearGemBalancesTables()
+
+	userID := uuid.New()
+	adminID := uuid.New()
+	transactionsRepo := gem_transactions.NewGemTransactionsRepository(gemBalancesConf, gemBalancesDB)
+
+	_, err := gemBalancesRepo.ApplyStripeCheckoutCreditIfUnprocessed(userID, 50, "evt_"+userID.String(), "pi_"+userID.String())
+	assert.NoError(t, err)
+	assert.NoError(t, gemBalancesRepo.UpsertGemBalance(userID, 30, adminID))
+	assert.NoError(t, gemBalancesRepo.UpsertGemBalance(userID, 30, adminID))
+
+	transactions, total, err := transactionsRepo.GetTransactionsByUserId(userID, gem_transactions.GemTransactionsFilter{}, 0, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	if assert.Len(t, transactions, 2) {
+		assert.Equal(t, models.GEM_TRANSACTION_ADMIN_ADJUSTMENT, transactions[0].Type)
+		assert.Equal(t, int64(-20), transactions[0].Amount)
+		assert.Equal(t, adminID, *transactions[0].ActorID)
+		assert.Equal(t, models.GEM_TRANSACTION_STRIPE_CREDIT, transactions[1].Type)
+		assert.Equal(t, "pi_"+userID.String(), transactions[1].ReferenceID)
+	}
+
+	ledgerBalance, err := transactionsRepo.GetLedgerBalance(userID)
+	assert.NoError(t, err)
+	balance, err := gemBalancesRepo.GetGemBalanceByUserId(userID)
+	assert.NoError(t, err)
+	assert.Equal(t, balance.Gems, ledgerBalance)
+}
+
+func TestGemBalancesRepository_ApplyStripeAdjustmentIfUnprocessed(t *testing.T) {
+	clearGemBalancesTables()
+
+	userID := uuid.New()
+	paymentIntentID := "pi_" + userID.String()
+	_, err := gemBalancesRepo.ApplyStripeCheckoutCreditIfUnprocessed(userID, 50, "evt_credit_"+userID.String(), paymentIntentID)
+	assert.NoError(t, err)
+
+	locked := true
+	event := &models.ProcessedStripeWebhookEvent{EventID: "evt_dispute_" + userID.String(), SessionID: paymentIntentID, EventType: "charge.dispute.created", UserID: userID}
+	transaction := &models.GemTransaction{UserID: userID, Type: models.GEM_TRANSACTION_CHARGEBACK, Amount: -80, ReferenceID: paymentIntentID}
+
+	applied, err := gemBalancesRepo.ApplyStripeAdjustmentIfUnprocessed(event, transaction, &locked)
+	assert.NoError(t, err)
+	assert.True(t, applied)
+
+	applied, err = gemBalancesRepo.ApplyStripeAdjustmentIfUnprocessed(event, &models.GemTransaction{UserID: userID, Type: models.GEM_TRANSACTION_CHARGEBACK, Amount: -80, ReferenceID: paymentIntentID}, &locked)
+	assert.NoError(t, err)
+	assert.False(t, applied)
+
+	balance, err := gemBalancesRepo.GetGemBalanceByUserId(userID)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(-30), balance.Gems)
+	assert.True(t, balance.Locked)
+}
@@ -9,7 +9,8 @@ type GemBalancesRepository interface {
 	CreateGemBalance(userId uuid.UUID) error
 	GetAllGemBalances() ([]*models.GemBalance, error)
 	GetGemBalanceByUserId(userId uuid.UUID) (*models.GemBalance, error)
-	AddToGemBalance(userId uuid.UUID, gems int64) error
+	AddToGemBalance(transaction *models.GemTransaction) error
 	ApplyStripeCheckoutCreditIfUnprocessed(userId uuid.UUID, gems int64, eventID string, sessionID string) (bool, error)
-	UpsertGemBalance(userId uuid.UUID, gems int64) error
+	ApplyStripeAdjustmentIfUnprocessed(event *models.ProcessedStripeWebhookEvent, transaction *models.GemTransaction, locked *bool) (bool, error)
+	UpsertGemBalance(userId uuid.UUID, gems int64, actorId uuid.UUID) error
 }
@@ -1,61 +1,82 @@
 package gem_metrics
 
 import (
+	"time"
+
 	"github.com/FeedTheRealm-org/core-service/config"
 	"github.com/FeedTheRealm-org/core-service/internal/payment-service/models"
-	"gorm.io/gorm"
-	"gorm.io/gorm/clause"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/logger"
 )
 
 type gemMetricsRepository struct {
 	conf *config.Config
 	db   *config.DB
+	loc  *time.Location
 }
 
 func NewGemMetricsRepository(conf *config.Config, db *config.DB) GemMetricsRepository {
-	return &gemMetricsRepository{conf: conf, db: db}
+	loc, err := time.LoadLocation(conf.Stripe.StripeBillingTimezone)
+	if err != nil {
+		logger.Logger.Warnf("Invalid billing timezone %q, gem metrics roll up in UTC: %v", conf.Stripe.StripeBillingTimezone, err)
+		loc = time.UTC
+	}
+
+	return &gemMetricsRepository{conf: conf, db: db, loc: loc}
 }
 
 func (r *gemMetricsRepository) GetMetrics() (*models.GemMetrics, error) {
-	metrics := models.GemMetrics{ID: 1}
-	if err := r.db.Conn.Where("id = ?", 1).FirstOrCreate(&metrics).Error; err != nil {
+	var metrics models.GemMetrics
+	err := r.db.Conn.Model(&models.GemMetricsDaily{}).
+		Select("COALESCE(SUM(gems_bought), 0) AS gems_bought, COALESCE(SUM(gems_spent), 0) AS gems_spent, " +
+			"COALESCE(SUM(gems_revenue), 0) AS gems_revenue").
+		Scan(&metrics).Error
+	if err != nil {
 		return nil, err
 	}
 	return &metrics, nil
 }
 
-func (r *gemMetricsRepository) AddGemsBoughtAndRevenue(gems int64, revenue float64) error {
-	newMetrics := models.GemMetrics{
-		ID:          1,
-		GemsBought:  gems,
-		GemsRevenue: revenue,
+func (r *gemMetricsRepository) GetMetricsRange(from time.Time, to time.Time, granularity string) ([]*models.GemMetricsBucket, error) {
+	var buckets []*models.GemMetricsBucket
+	err := r.db.Conn.Model(&models.GemMetricsDaily{}).
+		Select("date_trunc(?, day)::date AS period_start, SUM(gems_bought) AS gems_bought, SUM(gems_spent) AS gems_spent, "+
+			"SUM(gems_revenue) AS gems_revenue", granularity).
+		Where("day BETWEEN ?::date AND ?::date", from.Format(time.DateOnly), to.Format(time.DateOnly)).
+		Group("1").
+		Order("1").
+		Scan(&buckets).Error
+	if err != nil {
+		return nil, err
 	}
+	return buckets, nil
+}
 
-	result := r.db.Conn.Clauses(clause.OnConflict{
-		Columns: []clause.Column{{Name: "id"}},
-		DoUpdates: clause.Assignments(map[string]interface{}{
-			"gems_bought":  gorm.Expr("gem_metrics.gems_bought + ?", gems),
-			"gems_revenue": gorm.Expr("gem_metrics.gems_revenue + ?", revenue),
-			"updated_at":   gorm.Expr("NOW()"),
-		}),
-	}).Create(&newMetrics)
-
-	return result.Error
+func (r *gemMetricsRepository) AddGemsBoughtAndRevenue(gems int64, revenue float64) error {
+	return r.addToDay(r.today(), gems, 0, revenue)
 }
 
 func (r *gemMetricsRepository) AddGemsSpent(gems int64) error {
-	newMetrics := models.GemMetrics{
-		ID:        1,
-		GemsSpent: gems,
-	}
+	return r.addToDay(r.today(), 0, gems, 0)
+}
+
+/* --- UTILS --- */
 
-	result := r.db.Conn.Clauses(clause.OnConflict{
-		Columns: []clause.Column{{Name: "id"}},
-		DoUpdates: clause.Assignments(map[string]interface{}{
-			"gems_spent": gorm.Expr("gem_metrics.gems_spent + ?", gems),
-			"updated_at": gorm.Expr("NOW()"),
-		}),
-	}).Create(&newMetrics)
+// today returns the current calendar date in the billing timezone.
+func (r *gemMetricsRepository) today() time.Time {
+	now := time.Now().In(r.loc)
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
 
-	return result.Error
+func (r *gemMetricsRepository) addToDay(day time.Time, bought int64, spent int64, revenue float64) error {
+	// The day is sent as a plain date so the session timezone cannot shift it
+	return r.db.Conn.Exec(`
+		INSERT INTO gem_metrics_daily (day, gems_bought, gems_spent, gems_revenue)
+		VALUES (?::date, ?, ?, ?)
+		ON CONFLICT (day) DO UPDATE SET
+			gems_bought = gem_metrics_daily.gems_bought + EXCLUDED.gems_bought,
+			gems_spent = gem_metrics_daily.gems_spent + EXCLUDED.gems_spent,
+			gems_revenue = gem_metrics_daily.gems_revenue + EXCLUDED.gems_revenue,
+			updated_at = NOW()`,
+		day.Format(time.DateOnly), bought, spent, revenue,
+	).Error
 }
@@ -3,8 +3,10 @@ package gem_metrics
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/FeedTheRealm-org/core-service/config"
+	"github.com/FeedTheRealm-org/core-service/internal/payment-service/models"
 	"github.com/FeedTheRealm-org/core-service/internal/utils/logger"
 	"github.com/stretchr/testify/assert"
 )
@@ -30,7 +32,7 @@ func TestMain(m *testing.M) {
 }
 
 func clearGemMetricsTables() {
-	_ = gemMetricsDB.Conn.Exec("TRUNCATE TABLE gem_metrics RESTART IDENTITY CASCADE;")
+	_ = gemMetricsDB.Conn.Exec("TRUNCATE TABLE gem_metrics_daily;")
 }
 
 func TestGemMetricsRepository_GetMetricsAndUpdates(t *testing.T) {
@@ -38,7 +40,6 @@ func TestGemMetricsRepository_GetMetricsAndUpdates(t *testing.T) {
 
 	metrics, err := gemMetricsRepo.GetMetrics()
 	assert.NoError(t, err)
-	assert.Equal(t, int32(1), metrics.ID)
 	assert.Equal(t, int64(0), metrics.GemsBought)
 	assert.Equal(t, int64(0), metrics.GemsSpent)
 	assert.Equal(t, float64(0), metrics.GemsRevenue)
@@ -51,4 +52,45 @@ func TestGemMetricsRepository_GetMetricsAndUpdates(t *testing.T) {
 	assert.Equal(t, int64(10), metrics.GemsBought)
 	assert.Equal(t, int64(3), metrics.GemsSpent)
 	assert.Equal(t, float64(2.5), metrics.GemsRevenue)
+
+	var rollups []models.GemMetricsDaily
+	assert.NoError(t, gemMetricsDB.Conn.Find(&rollups).Error)
+	assert.Len(t, rollups, 1)
+}
+
+func TestGemMetricsRepository_GetMetricsRange(t *testing.T) {
+	clearGemMetricsTables()
+	repo := gemMetricsRepo.(*gemMetricsRepository)
+
+	// 2026-03-02 is a Monday
+	monday := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	assert.NoError(t, repo.addToDay(monday, 10, 0, 2.5))
+	assert.NoError(t, repo.addToDay(monday, 0, 4, 0))
+	assert.NoError(t, repo.addToDay(monday.AddDate(0, 0, 2), 20, 1, 5))
+	assert.NoError(t, repo.addToDay(monday.AddDate(0, 0, 7), 30, 0, 7.5))
+
+	buckets, err := gemMetricsRepo.GetMetricsRange(monday, monday.AddDate(0, 0, 7), models.METRICS_GRANULARITY_DAY)
+	assert.NoError(t, err)
+	if assert.Len(t, buckets, 3) {
+		assert.True(t, buckets[0].PeriodStart.Equal(monday))
+		assert.Equal(t, int64(10), buckets[0].GemsBought)
+		assert.Equal(t, int64(4), buckets[0].GemsSpent)
+		assert.Equal(t, 2.5, buckets[0].GemsRevenue)
+	}
+
+	buckets, err = gemMetricsRepo.GetMetricsRange(monday, monday.AddDate(0, 0, 7), models.METRICS_GRANULARITY_WEEK)
+	assert.NoError(t, err)
+	if assert.Len(t, buckets, 2) {
+		assert.True(t, buckets[0].PeriodStart.Equal(monday))
+		assert.Equal(t, int64(30), buckets[0].GemsBought)
+		assert.Equal(t, int64(5), buckets[0].GemsSpent)
+		assert.Equal(t, 7.5, buckets[0].GemsRevenue)
+	}
+
+	buckets, err = gemMetricsRepo.GetMetricsRange(monday.AddDate(0, 0, 1), monday.AddDate(0, 0, 6), models.METRICS_GRANULARITY_MONTH)
+	assert.NoError(t, err)
+	if assert.Len(t, buckets, 1) {
+		assert.True(t, buckets[0].PeriodStart.Equal(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)))
+		assert.Equal(t, int64(20), buckets[0].GemsBought)
+	}
 }
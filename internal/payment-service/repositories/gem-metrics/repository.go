@@ -1,9 +1,22 @@
 package gem_metrics
 
-import "github.com/FeedTheRealm-org/core-service/internal/payment-service/models"
+import (
+	"time"
+
+	"github.com/FeedTheRealm-org/core-service/internal/payment-service/models"
+)
 
 type GemMetricsRepository interface {
+	// GetMetrics returns the lifetime totals across every daily rollup.
 	GetMetrics() (*models.GemMetrics, error)
+
+	// GetMetricsRange returns the totals per day, week or month between two calendar dates, both inclusive.
+	// Periods without activity are omitted.
+	GetMetricsRange(from time.Time, to time.Time, granularity string) ([]*models.GemMetricsBucket, error)
+
+	// AddGemsBoughtAndRevenue adds to today's rollup in the billing timezone.
 	AddGemsBoughtAndRevenue(gems int64, revenue float64) error
+
+	// AddGemsSpent adds to today's rollup in the billing timezone.
 	AddGemsSpent(gems int64) error
 }
@@ -0,0 +1,81 @@
+package gem_transactions
+
+import (
+	"github.com/FeedTheRealm-org/core-service/config"
+	"github.com/FeedTheRealm-org/core-service/internal/payment-service/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type DatabaseError struct {
+	message string
+}
+
+func (e *DatabaseError) Error() string {
+	return "Database error occurred: " + e.message
+}
+
+type gemTransactionsRepository struct {
+	conf *config.Config
+	db   *config.DB
+}
+
+func NewGemTransactionsRepository(conf *config.Config, db *config.DB) GemTransactionsRepository {
+	return &gemTransactionsRepository{
+		conf: conf,
+		db:   db,
+	}
+}
+
+// RecordTransaction appends a ledger entry, callers pass the transaction that mutates the balance.
+func RecordTransaction(tx *gorm.DB, transaction *models.GemTransaction) error {
+	return tx.Create(transaction).Error
+}
+
+func (r *gemTransactionsRepository) GetTransactionsByUserId(userId uuid.UUID, filter GemTransactionsFilter, offset int, limit int) ([]*models.GemTransaction, int64, error) {
+	var transactions []*models.GemTransaction
+	query := r.db.Conn.Model(&models.GemTransaction{}).Where("user_id = ?", userId)
+
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("created_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("created_at < ?", filter.To)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, &DatabaseError{message: err.Error()}
+	}
+
+	if err := query.Order("created_at desc, id desc").Offset(offset).Limit(limit).Find(&transactions).Error; err != nil {
+		return nil, 0, &DatabaseError{message: err.Error()}
+	}
+
+	return transactions, total, nil
+}
+
+func (r *gemTransactionsRepository) GetTransactionsByReference(referenceId string) ([]*models.GemTransaction, error) {
+	var transactions []*models.GemTransaction
+
+	if err := r.db.Conn.Where("reference_id = ?", referenceId).Order("created_at asc, id asc").Find(&transactions).Error; err != nil {
+		return nil, &DatabaseError{message: err.Error()}
+	}
+
+	return transactions, nil
+}
+
+func (r *gemTransactionsRepository) GetLedgerBalance(userId uuid.UUID) (int64, error) {
+	var balance int64
+	if err := r.db.Conn.Model(&models.GemTransaction{}).
+		Where("user_id = ?", userId).
+		Select("COALESCE(SUM(amount), 0)").
+		Scan(&balance).Error; err != nil {
+		return 0, &DatabaseError{message: err.Error()}
+	}
+
+	return balance, nil
+}
@@ -0,0 +1,78 @@
+package gem_transactions
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/FeedTheRealm-org/core-service/config"
+	"github.com/FeedTheRealm-org/core-service/internal/payment-service/models"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/logger"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var gemTransactionsConf *config.Config
+var gemTransactionsDB *config.DB
+var gemTransactionsRepo GemTransactionsRepository
+
+func TestMain(m *testing.M) {
+	logger.InitLogger(false)
+	gemTransactionsConf = config.CreateConfig()
+	var err error
+	gemTransactionsDB, err = config.NewDB(gemTransactionsConf)
+	if err != nil {
+		panic(err)
+	}
+	gemTransactionsRepo = NewGemTransactionsRepository(gemTransactionsConf, gemTransactionsDB)
+
+	code := m.Run()
+	os.Exit(code)
+}
+
+func recordTransaction(t *testing.T, userID uuid.UUID, transactionType string, amount int64, createdAt time.Time) {
+	t.Helper()
+	assert.NoError(t, RecordTransaction(gemTransactionsDB.Conn, &models.GemTransaction{
+		UserID:    userID,
+		Type:      transactionType,
+		Amount:    amount,
+		CreatedAt: createdAt,
+	}))
+}
+
+func TestGemTransactionsRepository_FiltersAndPagination(t *testing.T) {
+	userID := uuid.New()
+	day := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	recordTransaction(t, userID, models.GEM_TRANSACTION_STRIPE_CREDIT, 100, day)
+	recordTransaction(t, userID, models.GEM_TRANSACTION_PURCHASE, -30, day.Add(time.Hour))
+	recordTransaction(t, userID, models.GEM_TRANSACTION_PURCHASE, -20, day.AddDate(0, 0, 2))
+	recordTransaction(t, uuid.New(), models.GEM_TRANSACTION_PURCHASE, -5, day)
+
+	transactions, total, err := gemTransactionsRepo.GetTransactionsByUserId(userID, GemTransactionsFilter{}, 0, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	if assert.Len(t, transactions, 2) {
+		assert.Equal(t, int64(-20), transactions[0].Amount)
+		assert.Equal(t, int64(-30), transactions[1].Amount)
+	}
+
+	transactions, total, err = gemTransactionsRepo.GetTransactionsByUserId(userID, GemTransactionsFilter{
+		Type: models.GEM_TRANSACTION_PURCHASE,
+		To:   day.AddDate(0, 0, 1),
+	}, 0, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	if assert.Len(t, transactions, 1) {
+		assert.Equal(t, int64(-30), transactions[0].Amount)
+	}
+
+	balance, err := gemTransactionsRepo.GetLedgerBalance(userID)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(50), balance)
+}
+
+func TestGemTransactionsRepository_GetLedgerBalance_Empty(t *testing.T) {
+	balance, err := gemTransactionsRepo.GetLedgerBalance(uuid.New())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), balance)
+}
@@ -0,0 +1,26 @@
+package gem_transactions
+
+import (
+	"time"
+
+	"github.com/FeedTheRealm-org/core-service/internal/payment-service/models"
+	"github.com/google/uuid"
+)
+
+// GemTransactionsFilter narrows a user's transaction history, zero values are ignored.
+type GemTransactionsFilter struct {
+	Type string
+	From time.Time
+	To   time.Time
+}
+
+type GemTransactionsRepository interface {
+	// GetTransactionsByUserId returns a page of the user's transactions, newest first, and the total count.
+	GetTransactionsByUserId(userId uuid.UUID, filter GemTransactionsFilter, offset int, limit int) ([]*models.GemTransaction, int64, error)
+
+	// GetTransactionsByReference returns every transaction recorded against the reference, oldest first.
+	GetTransactionsByReference(referenceId string) ([]*models.GemTransaction, error)
+
+	// GetLedgerBalance returns the sum of every transaction of the user.
+	GetLedgerBalance(userId uuid.UUID) (int64, error)
+}
@@ -0,0 +1,11 @@
+package user_data
+
+import (
+	"github.com/FeedTheRealm-org/core-service/internal/payment-service/models"
+	"github.com/google/uuid"
+)
+
+type UserDataRepository interface {
+	GetUserData(userId uuid.UUID) (*models.UserData, error)
+	DeleteUserData(userId uuid.UUID) error
+}
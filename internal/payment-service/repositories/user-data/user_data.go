@@ -0,0 +1,83 @@
+package user_data
+
+import (
+	"github.com/FeedTheRealm-org/core-service/config"
+	"github.com/FeedTheRealm-org/core-service/internal/errors"
+	"github.com/FeedTheRealm-org/core-service/internal/payment-service/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type userDataRepository struct {
+	conf *config.Config
+	db   *config.DB
+}
+
+func NewUserDataRepository(conf *config.Config, db *config.DB) UserDataRepository {
+	return &userDataRepository{conf: conf, db: db}
+}
+
+func (r *userDataRepository) GetUserData(userId uuid.UUID) (*models.UserData, error) {
+	data := &models.UserData{
+		GemTransactions:   []models.GemTransaction{},
+		CosmeticPurchases: []models.CosmeticPurchase{},
+		CreatorPayouts:    []models.CreatorPayout{},
+	}
+
+	var gemBalance models.GemBalance
+	if err := r.db.Conn.Where("user_id = ?", userId).First(&gemBalance).Error; err == nil {
+		data.GemBalance = &gemBalance
+	} else if !errors.IsRecordNotFound(err) {
+		return nil, err
+	}
+
+	var creatorBalance models.CreatorBalance
+	if err := r.db.Conn.Where("user_id = ?", userId).First(&creatorBalance).Error; err == nil {
+		data.CreatorBalance = &creatorBalance
+	} else if !errors.IsRecordNotFound(err) {
+		return nil, err
+	}
+
+	var subscription models.ZonesSubscriptions
+	if err := r.db.Conn.Where("user_id = ?", userId).First(&subscription).Error; err == nil {
+		data.ZonesSubscription = &subscription
+	} else if !errors.IsRecordNotFound(err) {
+		return nil, err
+	}
+
+	if err := r.db.Conn.Where("user_id = ?", userId).Order("created_at ASC").Find(&data.GemTransactions).Error; err != nil {
+		return nil, err
+	}
+	if err := r.db.Conn.Where("user_id = ? OR recipient_id = ?", userId, userId).Order("created_at ASC").Find(&data.CosmeticPurchases).Error; err != nil {
+		return nil, err
+	}
+	if err := r.db.Conn.Where("user_id = ?", userId).Order("created_at ASC").Find(&data.CreatorPayouts).Error; err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// DeleteUserData removes the balances and subscription of the user. The gem ledger, purchases and
+// payouts are financial records that are kept, only stripped of the free text and payout account.
+func (r *userDataRepository) DeleteUserData(userId uuid.UUID) error {
+	return r.db.Conn.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userId).Delete(&models.GemBalance{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", userId).Delete(&models.CreatorBalance{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", userId).Delete(&models.ZonesSubscriptions{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.CosmeticPurchase{}).
+			Where("user_id = ? OR recipient_id = ?", userId, userId).
+			Update("gift_message", "").Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.CreatorPayout{}).
+			Where("user_id = ?", userId).
+			Update("payout_account_id", "").Error
+	})
+}
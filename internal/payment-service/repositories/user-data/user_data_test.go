@@ -0,0 +1,72 @@
+package user_data
+
+import (
+	"os"
+	"testing"
+
+	"github.com/FeedTheRealm-org/core-service/config"
+	"github.com/FeedTheRealm-org/core-service/internal/payment-service/models"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/logger"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var userDataConf *config.Config
+var userDataDB *config.DB
+var userDataRepo UserDataRepository
+
+func TestMain(m *testing.M) {
+	logger.InitLogger(false)
+	userDataConf = config.CreateConfig()
+	var err error
+	userDataDB, err = config.NewDB(userDataConf)
+	if err != nil {
+		panic(err)
+	}
+	userDataRepo = NewUserDataRepository(userDataConf, userDataDB)
+
+	code := m.Run()
+	os.Exit(code)
+}
+
+func TestUserDataRepository_GetUserData_Empty(t *testing.T) {
+	data, err := userDataRepo.GetUserData(uuid.New())
+	require.NoError(t, err)
+	assert.Nil(t, data.GemBalance)
+	assert.Nil(t, data.CreatorBalance)
+	assert.Nil(t, data.ZonesSubscription)
+	assert.Empty(t, data.GemTransactions)
+}
+
+func TestUserDataRepository_GetAndDeleteUserData(t *testing.T) {
+	userID := uuid.New()
+	cosmeticID := uuid.New()
+	require.NoError(t, userDataDB.Conn.Create(&models.GemBalance{UserId: userID, Gems: 50}).Error)
+	require.NoError(t, userDataDB.Conn.Create(&models.CreatorBalance{UserID: userID, Balance: decimal.NewFromInt(3), PayoutAccountID: "acct_1"}).Error)
+	require.NoError(t, userDataDB.Conn.Create(&models.GemTransaction{UserID: userID, Type: models.GEM_TRANSACTION_STRIPE_CREDIT, Amount: 50}).Error)
+	require.NoError(t, userDataDB.Conn.Create(&models.CosmeticPurchase{UserID: uuid.New(), RecipientID: &userID, CosmeticID: &cosmeticID, GiftMessage: "hi", Price: 10}).Error)
+	require.NoError(t, userDataDB.Conn.Create(&models.CreatorPayout{UserID: userID, Amount: decimal.NewFromInt(1), PayoutAccountID: "acct_1"}).Error)
+
+	data, err := userDataRepo.GetUserData(userID)
+	require.NoError(t, err)
+	require.NotNil(t, data.GemBalance)
+	assert.Equal(t, int64(50), data.GemBalance.Gems)
+	require.NotNil(t, data.CreatorBalance)
+	assert.Len(t, data.GemTransactions, 1)
+	assert.Len(t, data.CosmeticPurchases, 1)
+	assert.Len(t, data.CreatorPayouts, 1)
+
+	require.NoError(t, userDataRepo.DeleteUserData(userID))
+
+	data, err = userDataRepo.GetUserData(userID)
+	require.NoError(t, err)
+	assert.Nil(t, data.GemBalance)
+	assert.Nil(t, data.CreatorBalance)
+	assert.Len(t, data.GemTransactions, 1)
+	require.Len(t, data.CosmeticPurchases, 1)
+	assert.Empty(t, data.CosmeticPurchases[0].GiftMessage)
+	require.Len(t, data.CreatorPayouts, 1)
+	assert.Empty(t, data.CreatorPayouts[0].PayoutAccountID)
+}
@@ -2,27 +2,40 @@ package router
 
 import (
 	"github.com/FeedTheRealm-org/core-service/config"
+	"github.com/FeedTheRealm-org/core-service/internal/common_handlers"
 	"github.com/FeedTheRealm-org/core-service/internal/middleware"
+	cosmetic_sales_controller "github.com/FeedTheRealm-org/core-service/internal/payment-service/controllers/cosmetic-sales"
 	creator_balances_controller "github.com/FeedTheRealm-org/core-service/internal/payment-service/controllers/creator-balances"
+	creator_payouts_controller "github.com/FeedTheRealm-org/core-service/internal/payment-service/controllers/creator-payouts"
 	gem_balances_controller "github.com/FeedTheRealm-org/core-service/internal/payment-service/controllers/gem-balances"
 	gem_metrics_controller "github.com/FeedTheRealm-org/core-service/internal/payment-service/controllers/gem-metrics"
 	gem_packs_controller "github.com/FeedTheRealm-org/core-service/internal/payment-service/controllers/gem-packs"
 	zones_subscriptions_controller "github.com/FeedTheRealm-org/core-service/internal/payment-service/controllers/zones-subscriptions"
+	cosmetic_purchases_repo "github.com/FeedTheRealm-org/core-service/internal/payment-service/repositories/cosmetic-purchases"
+	cosmetic_sales_repo "github.com/FeedTheRealm-org/core-service/internal/payment-service/repositories/cosmetic-sales"
 	creator_balances_repo "github.com/FeedTheRealm-org/core-service/internal/payment-service/repositories/creator-balances"
+	creator_payouts_repo "github.com/FeedTheRealm-org/core-service/internal/payment-service/repositories/creator-payouts"
 	gem_balances_repo "github.com/FeedTheRealm-org/core-service/internal/payment-service/repositories/gem-balances"
 	gem_metrics_repo "github.com/FeedTheRealm-org/core-service/internal/payment-service/repositories/gem-metrics"
 	gem_packs_repo "github.com/FeedTheRealm-org/core-service/internal/payment-service/repositories/gem-packs"
+	gem_transactions_repo "github.com/FeedTheRealm-org/core-service/internal/payment-service/repositories/gem-transactions"
+	user_data_repo "github.com/FeedTheRealm-org/core-service/internal/payment-service/repositories/user-data"
 	zones_subscriptions_repo "github.com/FeedTheRealm-org/core-service/internal/payment-service/repositories/zones-subscriptions"
+	cosmetic_sales_service "github.com/FeedTheRealm-org/core-service/internal/payment-service/services/cosmetic-sales"
 	creator_balances_service "github.com/FeedTheRealm-org/core-service/internal/payment-service/services/creator-balances"
+	creator_payouts_service "github.com/FeedTheRealm-org/core-service/internal/payment-service/services/creator-payouts"
 	gem_balances_service "github.com/FeedTheRealm-org/core-service/internal/payment-service/services/gem-balances"
 	gem_metrics_service "github.com/FeedTheRealm-org/core-service/internal/payment-service/services/gem-metrics"
 	gem_packs_service "github.com/FeedTheRealm-org/core-service/internal/payment-service/services/gem-packs"
+	user_data_service "github.com/FeedTheRealm-org/core-service/internal/payment-service/services/user-data"
 	zones_subscriptions_service "github.com/FeedTheRealm-org/core-service/internal/payment-service/services/zones-subscriptions"
+	"github.com/FeedTheRealm-org/core-service/internal/service_clients"
 	"github.com/FeedTheRealm-org/core-service/internal/utils/email_sender"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/permissions"
 	"github.com/gin-gonic/gin"
 )
 
-func SetupGemPacksServiceRouter(conf *config.Config, db *config.DB, g *gin.RouterGroup) {
+func SetupGemPacksServiceRouter(conf *config.Config, db *config.DB, g *gin.RouterGroup, clients *service_clients.Clients) {
 	packsRepo := gem_packs_repo.NewGemPacksRepository(conf, db)
 	gemGemPacksService := gem_packs_service.NewGemPacksService(conf, packsRepo)
 	gemGemPacksController := gem_packs_controller.NewGemPacksController(conf, gemGemPacksService)
@@ -31,21 +44,22 @@ func SetupGemPacksServiceRouter(conf *config.Config, db *config.DB, g *gin.Route
 	packsGroup := g.Group("/packs")
 	packsGroup.GET("", gemGemPacksController.GetAllGemPacks)
 	packsGroup.GET("/:id", gemGemPacksController.GetGemPackById)
-	packsGroup.POST("", middleware.AdminCheckMiddleware(), gemGemPacksController.CreateGemPack)
-	packsGroup.PUT("/:id", middleware.AdminCheckMiddleware(), gemGemPacksController.UpdateGemPack)
-	packsGroup.DELETE("/:id", middleware.AdminCheckMiddleware(), gemGemPacksController.DeleteGemPack)
+	packsGroup.POST("", middleware.RequirePermission(permissions.PAYMENTS_PACKS_WRITE), middleware.AuditMiddleware(clients.Audit, "payments.gem_packs.create", "gem_pack", "id"), gemGemPacksController.CreateGemPack)
+	packsGroup.PUT("/:id", middleware.RequirePermission(permissions.PAYMENTS_PACKS_WRITE), middleware.AuditMiddleware(clients.Audit, "payments.gem_packs.update", "gem_pack", "id"), gemGemPacksController.UpdateGemPack)
+	packsGroup.DELETE("/:id", middleware.RequirePermission(permissions.PAYMENTS_PACKS_WRITE), middleware.AuditMiddleware(clients.Audit, "payments.gem_packs.delete", "gem_pack", "id"), gemGemPacksController.DeleteGemPack)
 }
 
-func SetupBalancesServiceRouter(conf *config.Config, db *config.DB, paymentGroup *gin.RouterGroup, gemsGroup *gin.RouterGroup) {
+func SetupBalancesServiceRouter(conf *config.Config, db *config.DB, paymentGroup *gin.RouterGroup, gemsGroup *gin.RouterGroup, clients *service_clients.Clients) {
 	gemBalancesRepo := gem_balances_repo.NewGemBalancesRepository(conf, db)
 	gemMetricsRepo := gem_metrics_repo.NewGemMetricsRepository(conf, db)
-	creatorBalanceRepo := creator_balances_repo.NewCreatorBalancesRepository(conf, db)
+	cosmeticPurchasesRepo := cosmetic_purchases_repo.NewCosmeticPurchasesRepository(conf, db)
+	gemTransactionsRepo := gem_transactions_repo.NewGemTransactionsRepository(conf, db)
 	packsRepo := gem_packs_repo.NewGemPacksRepository(conf, db)
 
 	emailSender := email_sender.NewEmailSenderService(conf)
 
 	gemBalancesService := gem_balances_service.NewGemBalancesService(
-		conf, gemBalancesRepo, gemMetricsRepo, packsRepo, creatorBalanceRepo, emailSender,
+		conf, gemBalancesRepo, gemMetricsRepo, packsRepo, cosmeticPurchasesRepo, gemTransactionsRepo, emailSender, clients.Assets, clients.Players, clients.Accounts,
 	)
 
 	gemBalancesController := gem_balances_controller.NewGemBalancesController(conf, gemBalancesService)
@@ -53,21 +67,28 @@ func SetupBalancesServiceRouter(conf *config.Config, db *config.DB, paymentGroup
 	/* Balances Endpoints */
 	balancesGroup := gemsGroup.Group("/balances")
 	balancesGroup.GET("", gemBalancesController.GetGemBalanceByUserId)
-	balancesGroup.GET("/all", middleware.AdminCheckMiddleware(), gemBalancesController.GetAllGemBalances)
-	balancesGroup.PUT("/:id", middleware.AdminCheckMiddleware(), gemBalancesController.UpdateGemBalance)
+	balancesGroup.GET("/all", middleware.RequirePermission(permissions.PAYMENTS_BALANCES_READ), gemBalancesController.GetAllGemBalances)
+	balancesGroup.PUT("/:id", middleware.RequirePermission(permissions.PAYMENTS_BALANCES_WRITE), middleware.AuditMiddleware(clients.Audit, "payments.gem_balances.update", "user", "id"), gemBalancesController.UpdateGemBalance)
+
+	/* Transaction History Endpoints */
+	paymentGroup.GET("/balances/transactions", gemBalancesController.GetGemTransactions)
+	paymentGroup.GET("/balances/transactions/:user_id", middleware.RequirePermission(permissions.PAYMENTS_BALANCES_READ), gemBalancesController.GetUserGemTransactions)
 
 	/* Purchase Endpoints */
 	balancesGroup.POST("/purchase/:cosmetic_id", gemBalancesController.PurchaseCosmetic)
+	balancesGroup.POST("/purchase/bundles/:bundle_id", gemBalancesController.PurchaseBundle)
+	balancesGroup.POST("/gift/:cosmetic_id", gemBalancesController.GiftCosmetic)
 
 	/* Webhook Endpoint for Gems */
 	paymentGroup.POST("/checkout", gemBalancesController.CreateCheckoutSession)
 	paymentGroup.POST("/webhook/stripe", gemBalancesController.HandleStripeWebhook)
 }
 
-func SetupSubscriptionsServiceRouter(conf *config.Config, db *config.DB, subscriptionGroup *gin.RouterGroup) {
+func SetupSubscriptionsServiceRouter(conf *config.Config, db *config.DB, subscriptionGroup *gin.RouterGroup, internalGroup *gin.RouterGroup, clients *service_clients.Clients) {
 	zonesSubscriptionsRepo := zones_subscriptions_repo.NewSubscriptionRepository(conf, db)
 	emailSender := email_sender.NewEmailSenderService(conf)
-	zonesSubscriptionsService := zones_subscriptions_service.NewSubscriptionService(conf, zonesSubscriptionsRepo, emailSender)
+	zonesSubscriptionsService := zones_subscriptions_service.NewSubscriptionService(conf, zonesSubscriptionsRepo, emailSender, clients.WorldJobs)
+	clients.ProvideSubscriptions(zones_subscriptions_service.NewSubscriptionsClient(zonesSubscriptionsService))
 	zonesSubscriptionsController := zones_subscriptions_controller.NewZonesSubscriptionsController(conf, zonesSubscriptionsService)
 
 	// External / user-facing subscription routes
@@ -82,15 +103,16 @@ func SetupSubscriptionsServiceRouter(conf *config.Config, db *config.DB, subscri
 	subscriptionGroup.POST("/webhook/stripe", zonesSubscriptionsController.HandleWebhook)
 
 	// Admin routes for subscription management
-	subscriptionGroup.GET("/admin/users", middleware.AdminCheckMiddleware(), zonesSubscriptionsController.AdminListSubscriptions)
-	subscriptionGroup.POST("/admin/users/:user_id", middleware.AdminCheckMiddleware(), zonesSubscriptionsController.AdminCreateSubscription)
-	subscriptionGroup.DELETE("/admin/users/:user_id", middleware.AdminCheckMiddleware(), zonesSubscriptionsController.AdminCancelSubscription)
-	subscriptionGroup.PUT("/admin/users/:user_id/slots", middleware.AdminCheckMiddleware(), zonesSubscriptionsController.AdminUpdateSlots)
+	subscriptionGroup.GET("/admin/users", middleware.RequirePermission(permissions.PAYMENTS_SUBSCRIPTIONS_READ), zonesSubscriptionsController.AdminListSubscriptions)
+	subscriptionGroup.POST("/admin/users/:user_id", middleware.RequirePermission(permissions.PAYMENTS_SUBSCRIPTIONS_WRITE), middleware.AuditMiddleware(clients.Audit, "payments.subscriptions.create", "user", "user_id"), zonesSubscriptionsController.AdminCreateSubscription)
+	subscriptionGroup.DELETE("/admin/users/:user_id", middleware.RequirePermission(permissions.PAYMENTS_SUBSCRIPTIONS_WRITE), middleware.AuditMiddleware(clients.Audit, "payments.subscriptions.cancel", "user", "user_id"), zonesSubscriptionsController.AdminCancelSubscription)
+	subscriptionGroup.PUT("/admin/users/:user_id/slots", middleware.RequirePermission(permissions.PAYMENTS_SUBSCRIPTIONS_WRITE), middleware.AuditMiddleware(clients.Audit, "payments.subscriptions.slots.update", "user", "user_id"), zonesSubscriptionsController.AdminUpdateSlots)
 
-	// Internal routes bypassed by JWT
-	internalGroup := subscriptionGroup.Group("/internal")
+	// Internal routes bypassed by JWT, only used when services are split out
 	internalGroup.GET("/users/:user_id/status", zonesSubscriptionsController.CheckInternalAvailability)
 	internalGroup.PUT("/users/:user_id/used-slots", zonesSubscriptionsController.InternalUpdateUsedSlots)
+	internalGroup.DELETE("/users/:user_id", zonesSubscriptionsController.InternalCancelSubscription)
+	internalGroup.PUT("/users/:user_id/email", zonesSubscriptionsController.InternalUpdateCustomerEmail)
 }
 
 func SetupCreatorBalancesRouter(conf *config.Config, db *config.DB, paymentGroup *gin.RouterGroup) {
@@ -100,28 +122,78 @@ func SetupCreatorBalancesRouter(conf *config.Config, db *config.DB, paymentGroup
 
 	/* Creator Balances Endpoints */
 	paymentGroup.GET("/balances/creators", creatorBalancesController.GetBalance)
-	paymentGroup.GET("/balances/creators/all", middleware.AdminCheckMiddleware(), creatorBalancesController.GetAllBalances)
+	paymentGroup.GET("/balances/creators/all", middleware.RequirePermission(permissions.PAYMENTS_BALANCES_READ), creatorBalancesController.GetAllBalances)
+}
+
+func SetupCreatorPayoutsRouter(conf *config.Config, db *config.DB, paymentGroup *gin.RouterGroup, clients *service_clients.Clients) {
+	creatorPayoutsRepo := creator_payouts_repo.NewCreatorPayoutsRepository(conf, db)
+	payoutProvider := creator_payouts_service.NewPayoutProvider(conf)
+	creatorPayoutsService := creator_payouts_service.NewCreatorPayoutsService(conf, creatorPayoutsRepo, payoutProvider)
+	creatorPayoutsController := creator_payouts_controller.NewCreatorPayoutsController(creatorPayoutsService)
+
+	/* Creator Payouts Endpoints */
+	payoutsGroup := paymentGroup.Group("/balances/creators/payouts")
+	payoutsGroup.PUT("/account", creatorPayoutsController.SetPayoutAccount)
+	payoutsGroup.POST("", creatorPayoutsController.RequestPayout)
+	payoutsGroup.GET("", creatorPayoutsController.GetPayouts)
+	payoutsGroup.GET("/all", middleware.RequirePermission(permissions.PAYMENTS_PAYOUTS_READ), creatorPayoutsController.GetAllPayouts)
+	payoutsGroup.GET("/:id", creatorPayoutsController.GetPayout)
+	payoutsGroup.POST("/:id/approve", middleware.RequirePermission(permissions.PAYMENTS_PAYOUTS_WRITE), middleware.AuditMiddleware(clients.Audit, "payments.payouts.approve", "payout", "id"), creatorPayoutsController.ApprovePayout)
+	payoutsGroup.POST("/:id/reject", middleware.RequirePermission(permissions.PAYMENTS_PAYOUTS_WRITE), middleware.AuditMiddleware(clients.Audit, "payments.payouts.reject", "payout", "id"), creatorPayoutsController.RejectPayout)
 }
 
 func SetupGemsMetricsRouter(conf *config.Config, db *config.DB, gemsGroup *gin.RouterGroup) {
 	gemMetricsRepo := gem_metrics_repo.NewGemMetricsRepository(conf, db)
-	gemMetricsService := gem_metrics_service.NewGemMetricsService(gemMetricsRepo)
+	gemMetricsService := gem_metrics_service.NewGemMetricsService(conf, gemMetricsRepo)
 	gemMetricsController := gem_metrics_controller.NewGemMetricsController(gemMetricsService)
 
 	/* Gem Metrics Endpoints */
-	gemsGroup.GET("/metrics", middleware.AdminCheckMiddleware(), gemMetricsController.GetMetrics)
+	gemsGroup.GET("/metrics", middleware.RequirePermission(permissions.PAYMENTS_METRICS_READ), gemMetricsController.GetMetrics)
+	gemsGroup.GET("/metrics/history", middleware.RequirePermission(permissions.PAYMENTS_METRICS_READ), gemMetricsController.GetMetricsHistory)
+	gemsGroup.GET("/metrics/export", middleware.RequirePermission(permissions.PAYMENTS_METRICS_READ), gemMetricsController.ExportMetricsHistory)
+}
+
+func SetupCosmeticSalesRouter(conf *config.Config, db *config.DB, paymentGroup *gin.RouterGroup) {
+	cosmeticSalesRepo := cosmetic_sales_repo.NewCosmeticSalesRepository(conf, db)
+	cosmeticSalesService := cosmetic_sales_service.NewCosmeticSalesService(cosmeticSalesRepo)
+	cosmeticSalesController := cosmetic_sales_controller.NewCosmeticSalesController(cosmeticSalesService)
+
+	/* Cosmetic Sales Endpoints */
+	salesGroup := paymentGroup.Group("/sales")
+	salesGroup.GET("", middleware.RequirePermission(permissions.PAYMENTS_SALES_READ), cosmeticSalesController.GetSales)
+	salesGroup.GET("/top", middleware.RequirePermission(permissions.PAYMENTS_SALES_READ), cosmeticSalesController.GetTopSellers)
+	salesGroup.GET("/creators", cosmeticSalesController.GetCreatorSales)
+	salesGroup.GET("/creators/top", cosmeticSalesController.GetCreatorTopSellers)
+}
+
+func SetupUserDataRouter(conf *config.Config, db *config.DB, internalGroup *gin.RouterGroup, clients *service_clients.Clients) {
+	userDataRepo := user_data_repo.NewUserDataRepository(conf, db)
+	zonesSubscriptionsRepo := zones_subscriptions_repo.NewSubscriptionRepository(conf, db)
+	emailSender := email_sender.NewEmailSenderService(conf)
+	zonesSubscriptionsService := zones_subscriptions_service.NewSubscriptionService(conf, zonesSubscriptionsRepo, emailSender, clients.WorldJobs)
+	userDataClient := user_data_service.NewUserDataClient(userDataRepo, zonesSubscriptionsService)
+	clients.ProvideUserData(service_clients.PaymentsUserData, userDataClient)
+
+	// Internal routes used by the authentication-service to export and erase an account
+	internalGroup.GET("/users/:user_id/data", common_handlers.ExportUserDataController(userDataClient))
+	internalGroup.DELETE("/users/:user_id/data", common_handlers.EraseUserDataController(userDataClient))
 }
 
-func SetupPaymentServiceRouter(r *gin.Engine, conf *config.Config, db *config.DB) error {
+func SetupPaymentServiceRouter(r *gin.Engine, internal *gin.RouterGroup, conf *config.Config, db *config.DB, clients *service_clients.Clients) error {
 	paymentGroup := r.Group("/payments")
 	subscriptionGroup := r.Group("/subscriptions")
 	gemsGroup := paymentGroup.Group("/gems")
+	subscriptionInternalGroup := internal.Group("/subscriptions/internal")
+	paymentInternalGroup := internal.Group("/payments/internal")
 
-	SetupGemPacksServiceRouter(conf, db, gemsGroup)
-	SetupBalancesServiceRouter(conf, db, paymentGroup, gemsGroup)
-	SetupSubscriptionsServiceRouter(conf, db, subscriptionGroup)
+	SetupGemPacksServiceRouter(conf, db, gemsGroup, clients)
+	SetupBalancesServiceRouter(conf, db, paymentGroup, gemsGroup, clients)
+	SetupSubscriptionsServiceRouter(conf, db, subscriptionGroup, subscriptionInternalGroup, clients)
 	SetupCreatorBalancesRouter(conf, db, paymentGroup)
+	SetupCreatorPayoutsRouter(conf, db, paymentGroup, clients)
 	SetupGemsMetricsRouter(conf, db, gemsGroup)
+	SetupCosmeticSalesRouter(conf, db, paymentGroup)
+	SetupUserDataRouter(conf, db, paymentInternalGroup, clients)
 
 	return nil
 }
@@ -0,0 +1,43 @@
+package cosmetic_sales
+
+import (
+	"github.com/FeedTheRealm-org/core-service/internal/payment-service/models"
+	cosmetic_sales "github.com/FeedTheRealm-org/core-service/internal/payment-service/repositories/cosmetic-sales"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/logger"
+	"github.com/shopspring/decimal"
+)
+
+type cosmeticSalesService struct {
+	repo cosmetic_sales.CosmeticSalesRepository
+}
+
+func NewCosmeticSalesService(repo cosmetic_sales.CosmeticSalesRepository) CosmeticSalesService {
+	return &cosmeticSalesService{repo: repo}
+}
+
+func (s *cosmeticSalesService) GetSalesReport(filter cosmetic_sales.CosmeticSalesFilter, interval string) ([]*models.CosmeticSalesBucket, *models.CosmeticSalesTotals, error) {
+	buckets, err := s.repo.GetSalesBuckets(filter, interval)
+	if err != nil {
+		logger.Logger.Error("Failed to retrieve cosmetic sales: " + err.Error())
+		return nil, nil, err
+	}
+
+	totals := &models.CosmeticSalesTotals{CreatorShare: decimal.Zero}
+	for _, bucket := range buckets {
+		totals.SalesCount += bucket.SalesCount
+		totals.GemsRevenue += bucket.GemsRevenue
+		totals.CreatorShare = totals.CreatorShare.Add(bucket.CreatorShare)
+	}
+
+	return buckets, totals, nil
+}
+
+func (s *cosmeticSalesService) GetTopSellers(filter cosmetic_sales.CosmeticSalesFilter, groupBy string, limit int) ([]*models.CosmeticSalesRanking, error) {
+	rankings, err := s.repo.GetTopSellers(filter, groupBy, limit)
+	if err != nil {
+		logger.Logger.Error("Failed to retrieve top sellers by " + groupBy + ": " + err.Error())
+		return nil, err
+	}
+
+	return rankings, nil
+}
@@ -0,0 +1,86 @@
+package cosmetic_sales
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/FeedTheRealm-org/core-service/internal/payment-service/models"
+	cosmetic_sales "github.com/FeedTheRealm-org/core-service/internal/payment-service/repositories/cosmetic-sales"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/logger"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMain(m *testing.M) {
+	logger.InitLogger(false)
+	os.Exit(m.Run())
+}
+
+type fakeCosmeticSalesRepo struct {
+	buckets     []*models.CosmeticSalesBucket
+	rankings    []*models.CosmeticSalesRanking
+	err         error
+	lastFilter  cosmetic_sales.CosmeticSalesFilter
+	lastGroupBy string
+}
+
+func (f *fakeCosmeticSalesRepo) GetSalesBuckets(filter cosmetic_sales.CosmeticSalesFilter, interval string) ([]*models.CosmeticSalesBucket, error) {
+	f.lastFilter = filter
+	return f.buckets, f.err
+}
+
+func (f *fakeCosmeticSalesRepo) GetTopSellers(filter cosmetic_sales.CosmeticSalesFilter, groupBy string, limit int) ([]*models.CosmeticSalesRanking, error) {
+	f.lastFilter = filter
+	f.lastGroupBy = groupBy
+	return f.rankings, f.err
+}
+
+func TestGetSalesReport_SumsTotals(t *testing.T) {
+	repo := &fakeCosmeticSalesRepo{buckets: []*models.CosmeticSalesBucket{
+		{PeriodStart: time.Now(), SalesCount: 2, GemsRevenue: 30, CreatorShare: decimal.NewFromFloat(0.75)},
+		{PeriodStart: time.Now(), SalesCount: 1, GemsRevenue: 10, CreatorShare: decimal.NewFromFloat(0.25)},
+	}}
+	service := NewCosmeticSalesService(repo)
+	creatorID := uuid.New()
+
+	buckets, totals, err := service.GetSalesReport(cosmetic_sales.CosmeticSalesFilter{CreatorID: &creatorID}, models.SALES_INTERVAL_DAY)
+	assert.NoError(t, err)
+	assert.Len(t, buckets, 2)
+	assert.Equal(t, int64(3), totals.SalesCount)
+	assert.Equal(t, int64(40), totals.GemsRevenue)
+	assert.True(t, totals.CreatorShare.Equal(decimal.NewFromInt(1)))
+	assert.Equal(t, &creatorID, repo.lastFilter.CreatorID)
+}
+
+func TestGetSalesReport_Empty(t *testing.T) {
+	service := NewCosmeticSalesService(&fakeCosmeticSalesRepo{})
+
+	buckets, totals, err := service.GetSalesReport(cosmetic_sales.CosmeticSalesFilter{}, models.SALES_INTERVAL_WEEK)
+	assert.NoError(t, err)
+	assert.Empty(t, buckets)
+	assert.Equal(t, int64(0), totals.SalesCount)
+	assert.True(t, totals.CreatorShare.IsZero())
+}
+
+func TestGetSalesReport_RepoError(t *testing.T) {
+	service := NewCosmeticSalesService(&fakeCosmeticSalesRepo{err: errors.New("db down")})
+
+	_, _, err := service.GetSalesReport(cosmetic_sales.CosmeticSalesFilter{}, models.SALES_INTERVAL_DAY)
+	assert.Error(t, err)
+}
+
+func TestGetTopSellers_PassesGrouping(t *testing.T) {
+	worldID := uuid.New()
+	repo := &fakeCosmeticSalesRepo{rankings: []*models.CosmeticSalesRanking{{ID: worldID, SalesCount: 4}}}
+	service := NewCosmeticSalesService(repo)
+
+	rankings, err := service.GetTopSellers(cosmetic_sales.CosmeticSalesFilter{}, models.SALES_GROUP_BY_WORLD, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, models.SALES_GROUP_BY_WORLD, repo.lastGroupBy)
+	if assert.Len(t, rankings, 1) {
+		assert.Equal(t, worldID, rankings[0].ID)
+	}
+}
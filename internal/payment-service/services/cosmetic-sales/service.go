@@ -0,0 +1,14 @@
+package cosmetic_sales
+
+import (
+	"github.com/FeedTheRealm-org/core-service/internal/payment-service/models"
+	cosmetic_sales "github.com/FeedTheRealm-org/core-service/internal/payment-service/repositories/cosmetic-sales"
+)
+
+type CosmeticSalesService interface {
+	// GetSalesReport retrieves the sales matching the filter per interval along with their totals.
+	GetSalesReport(filter cosmetic_sales.CosmeticSalesFilter, interval string) ([]*models.CosmeticSalesBucket, *models.CosmeticSalesTotals, error)
+
+	// GetTopSellers retrieves the best selling cosmetics, worlds or creators matching the filter.
+	GetTopSellers(filter cosmetic_sales.CosmeticSalesFilter, groupBy string, limit int) ([]*models.CosmeticSalesRanking, error)
+}
@@ -0,0 +1,171 @@
+package creator_payouts
+
+import (
+	"fmt"
+
+	"github.com/FeedTheRealm-org/core-service/config"
+	payment_errors "github.com/FeedTheRealm-org/core-service/internal/payment-service/errors"
+	"github.com/FeedTheRealm-org/core-service/internal/payment-service/models"
+	creator_payouts "github.com/FeedTheRealm-org/core-service/internal/payment-service/repositories/creator-payouts"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/logger"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type creatorPayoutsService struct {
+	conf     *config.Config
+	repo     creator_payouts.CreatorPayoutsRepository
+	provider PayoutProvider
+}
+
+func NewCreatorPayoutsService(conf *config.Config, repo creator_payouts.CreatorPayoutsRepository, provider PayoutProvider) CreatorPayoutsService {
+	return &creatorPayoutsService{
+		conf:     conf,
+		repo:     repo,
+		provider: provider,
+	}
+}
+
+func (s *creatorPayoutsService) SetPayoutAccount(userId uuid.UUID, accountId string) error {
+	if err := s.repo.SetPayoutAccount(userId, accountId); err != nil {
+		logger.Logger.Error("Failed to set payout account for creator " + userId.String() + ": " + err.Error())
+		return err
+	}
+
+	logger.Logger.Info("Updated payout account for creator " + userId.String())
+	return nil
+}
+
+func (s *creatorPayoutsService) RequestPayout(userId uuid.UUID, amount decimal.Decimal) (*models.CreatorPayout, error) {
+	minimum := decimal.NewFromFloat(s.conf.Payouts.MinimumAmount)
+	if amount.LessThan(minimum) {
+		return nil, payment_errors.NewPayoutBelowMinimum(fmt.Sprintf("payouts must be at least %s", minimum.StringFixed(2)))
+	}
+
+	accountId, err := s.repo.GetPayoutAccount(userId)
+	if err != nil {
+		logger.Logger.Error("Failed to retrieve payout account for creator " + userId.String() + ": " + err.Error())
+		return nil, err
+	}
+	if accountId == "" {
+		return nil, payment_errors.NewPayoutAccountMissing("a payout account must be set before requesting a payout")
+	}
+
+	payout := &models.CreatorPayout{
+		UserID:          userId,
+		Amount:          amount,
+		PayoutAccountID: accountId,
+	}
+	if err := s.repo.CreatePayoutRequest(payout); err != nil {
+		logger.Logger.Error("Failed to request payout for creator " + userId.String() + ": " + err.Error())
+		return nil, err
+	}
+
+	logger.Logger.Info(fmt.Sprintf("Creator %s requested payout %s of %s", userId, payout.ID, amount.StringFixed(2)))
+	return payout, nil
+}
+
+func (s *creatorPayoutsService) GetPayouts(filter creator_payouts.CreatorPayoutsFilter, offset int, limit int) ([]*models.CreatorPayout, int64, error) {
+	payouts, total, err := s.repo.ListPayouts(filter, offset, limit)
+	if err != nil {
+		logger.Logger.Error("Failed to list payouts: " + err.Error())
+		return nil, 0, err
+	}
+
+	return payouts, total, nil
+}
+
+func (s *creatorPayoutsService) GetPayout(payoutId uuid.UUID) (*models.CreatorPayout, []*models.CreatorPayoutEvent, error) {
+	payout, err := s.repo.GetPayoutById(payoutId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	history, err := s.repo.GetPayoutHistory(payoutId)
+	if err != nil {
+		logger.Logger.Error("Failed to retrieve history of payout " + payoutId.String() + ": " + err.Error())
+		return nil, nil, err
+	}
+
+	return payout, history, nil
+}
+
+func (s *creatorPayoutsService) ApprovePayout(payoutId uuid.UUID, adminId uuid.UUID) (*models.CreatorPayout, error) {
+	payout, err := s.repo.GetPayoutById(payoutId)
+	if err != nil {
+		return nil, err
+	}
+
+	if payout.Status == models.CREATOR_PAYOUT_REQUESTED {
+		payout, err = s.repo.ApprovePayout(payoutId, adminId)
+		if err != nil {
+			logger.Logger.Error("Failed to approve payout " + payoutId.String() + ": " + err.Error())
+			return nil, err
+		}
+	} else if payout.Status != models.CREATOR_PAYOUT_APPROVED {
+		return nil, payment_errors.NewInvalidPayoutTransition(fmt.Sprintf("cannot approve a %s payout", payout.Status))
+	}
+
+	// The payout stays approved when the transfer fails so it can be retried with the same idempotency key
+	reference, err := s.provider.Transfer(payout.PayoutAccountID, payout.Amount, payout.ID.String())
+	if err != nil {
+		logger.Logger.Error("Failed to transfer payout " + payoutId.String() + ": " + err.Error())
+		return nil, payment_errors.NewPayoutProviderError("payout was approved but the transfer failed: " + err.Error())
+	}
+
+	payout, err = s.repo.MarkPayoutPaid(payoutId, reference, adminId)
+	if err != nil {
+		logger.Logger.Error(fmt.Sprintf("Payout %s was transferred as %s but could not be marked as paid: %s", payoutId, reference, err.Error()))
+		return nil, err
+	}
+
+	logger.Logger.Info(fmt.Sprintf("Payout %s of creator %s paid as %s", payoutId, payout.UserID, reference))
+	return payout, nil
+}
+
+func (s *creatorPayoutsService) RejectPayout(payoutId uuid.UUID, adminId uuid.UUID, reason string) (*models.CreatorPayout, error) {
+	payout, err := s.repo.GetPayoutById(payoutId)
+	if err != nil {
+		return nil, err
+	}
+
+	if payout.Status == models.CREATOR_PAYOUT_APPROVED {
+		return s.failApprovedPayout(payout, adminId, reason)
+	}
+
+	payout, err = s.repo.RejectPayout(payoutId, adminId, reason)
+	if err != nil {
+		logger.Logger.Error("Failed to reject payout " + payoutId.String() + ": " + err.Error())
+		return nil, err
+	}
+
+	logger.Logger.Info(fmt.Sprintf("Payout %s of creator %s rejected", payoutId, payout.UserID))
+	return payout, nil
+}
+
+// failApprovedPayout settles an approved payout with the provider, the hold is only released when no transfer was sent.
+func (s *creatorPayoutsService) failApprovedPayout(payout *models.CreatorPayout, adminId uuid.UUID, reason string) (*models.CreatorPayout, error) {
+	reference, found, err := s.provider.FindTransfer(payout.ID.String())
+	if err != nil {
+		logger.Logger.Error("Failed to look up the transfer of payout " + payout.ID.String() + ": " + err.Error())
+		return nil, payment_errors.NewPayoutProviderError("could not check whether the payout was transferred: " + err.Error())
+	}
+
+	if found {
+		if _, err := s.repo.MarkPayoutPaid(payout.ID, reference, adminId); err != nil {
+			logger.Logger.Error(fmt.Sprintf("Payout %s was transferred as %s but could not be marked as paid: %s", payout.ID, reference, err.Error()))
+			return nil, err
+		}
+		logger.Logger.Info(fmt.Sprintf("Payout %s of creator %s was already transferred as %s, marked as paid", payout.ID, payout.UserID, reference))
+		return nil, payment_errors.NewInvalidPayoutTransition(fmt.Sprintf("payout was already transferred as %s and has been marked as paid", reference))
+	}
+
+	failed, err := s.repo.FailPayout(payout.ID, adminId, reason)
+	if err != nil {
+		logger.Logger.Error("Failed to mark payout " + payout.ID.String() + " as failed: " + err.Error())
+		return nil, err
+	}
+
+	logger.Logger.Info(fmt.Sprintf("Payout %s of creator %s failed without a transfer", payout.ID, failed.UserID))
+	return failed, nil
+}
@@ -0,0 +1,309 @@
+package creator_payouts
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/FeedTheRealm-org/core-service/config"
+	payment_errors "github.com/FeedTheRealm-org/core-service/internal/payment-service/errors"
+	"github.com/FeedTheRealm-org/core-service/internal/payment-service/models"
+	creator_payouts "github.com/FeedTheRealm-org/core-service/internal/payment-service/repositories/creator-payouts"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/logger"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMain(m *testing.M) {
+	logger.InitLogger(false)
+	os.Exit(m.Run())
+}
+
+type fakeCreatorPayoutsRepo struct {
+	accounts map[uuid.UUID]string
+	balances map[uuid.UUID]decimal.Decimal
+	held     map[uuid.UUID]decimal.Decimal
+	payouts  map[uuid.UUID]*models.CreatorPayout
+	events   []*models.CreatorPayoutEvent
+}
+
+func newFakeCreatorPayoutsRepo() *fakeCreatorPayoutsRepo {
+	return &fakeCreatorPayoutsRepo{
+		accounts: map[uuid.UUID]string{},
+		balances: map[uuid.UUID]decimal.Decimal{},
+		held:     map[uuid.UUID]decimal.Decimal{},
+		payouts:  map[uuid.UUID]*models.CreatorPayout{},
+	}
+}
+
+func (f *fakeCreatorPayoutsRepo) SetPayoutAccount(userId uuid.UUID, accountId string) error {
+	f.accounts[userId] = accountId
+	return nil
+}
+
+func (f *fakeCreatorPayoutsRepo) GetPayoutAccount(userId uuid.UUID) (string, error) {
+	return f.accounts[userId], nil
+}
+
+func (f *fakeCreatorPayoutsRepo) CreatePayoutRequest(payout *models.CreatorPayout) error {
+	if f.balances[payout.UserID].LessThan(payout.Amount) {
+		return payment_errors.NewInsufficientCreatorBalance("insufficient creator balance")
+	}
+	f.balances[payout.UserID] = f.balances[payout.UserID].Sub(payout.Amount)
+	f.held[payout.UserID] = f.held[payout.UserID].Add(payout.Amount)
+	payout.ID = uuid.New()
+	payout.Status = models.CREATOR_PAYOUT_REQUESTED
+	f.payouts[payout.ID] = payout
+	f.record(payout.ID, payout.Status, nil)
+	return nil
+}
+
+func (f *fakeCreatorPayoutsRepo) GetPayoutById(payoutId uuid.UUID) (*models.CreatorPayout, error) {
+	payout, ok := f.payouts[payoutId]
+	if !ok {
+		return nil, errors.New("payout not found")
+	}
+	copied := *payout
+	return &copied, nil
+}
+
+func (f *fakeCreatorPayoutsRepo) ListPayouts(filter creator_payouts.CreatorPayoutsFilter, offset int, limit int) ([]*models.CreatorPayout, int64, error) {
+	payouts := []*models.CreatorPayout{}
+	for _, payout := range f.payouts {
+		payouts = append(payouts, payout)
+	}
+	return payouts, int64(len(payouts)), nil
+}
+
+func (f *fakeCreatorPayoutsRepo) ApprovePayout(payoutId uuid.UUID, actorId uuid.UUID) (*models.CreatorPayout, error) {
+	return f.transition(payoutId, models.CREATOR_PAYOUT_REQUESTED, models.CREATOR_PAYOUT_APPROVED, actorId, func(p *models.CreatorPayout) {})
+}
+
+func (f *fakeCreatorPayoutsRepo) MarkPayoutPaid(payoutId uuid.UUID, providerReference string, actorId uuid.UUID) (*models.CreatorPayout, error) {
+	return f.transition(payoutId, models.CREATOR_PAYOUT_APPROVED, models.CREATOR_PAYOUT_PAID, actorId, func(p *models.CreatorPayout) {
+		p.ProviderReference = providerReference
+		f.held[p.UserID] = f.held[p.UserID].Sub(p.Amount)
+	})
+}
+
+func (f *fakeCreatorPayoutsRepo) RejectPayout(payoutId uuid.UUID, actorId uuid.UUID, reason string) (*models.CreatorPayout, error) {
+	return f.transition(payoutId, models.CREATOR_PAYOUT_REQUESTED, models.CREATOR_PAYOUT_REJECTED, actorId, f.releaseHold(reason))
+}
+
+func (f *fakeCreatorPayoutsRepo) FailPayout(payoutId uuid.UUID, actorId uuid.UUID, reason string) (*models.CreatorPayout, error) {
+	return f.transition(payoutId, models.CREATOR_PAYOUT_APPROVED, models.CREATOR_PAYOUT_FAILED, actorId, f.releaseHold(reason))
+}
+
+func (f *fakeCreatorPayoutsRepo) releaseHold(reason string) func(*models.CreatorPayout) {
+	return func(p *models.CreatorPayout) {
+		p.RejectionReason = reason
+		f.held[p.UserID] = f.held[p.UserID].Sub(p.Amount)
+		f.balances[p.UserID] = f.balances[p.UserID].Add(p.Amount)
+	}
+}
+
+func (f *fakeCreatorPayoutsRepo) GetPayoutHistory(payoutId uuid.UUID) ([]*models.CreatorPayoutEvent, error) {
+	history := []*models.CreatorPayoutEvent{}
+	for _, event := range f.events {
+		if event.PayoutID == payoutId {
+			history = append(history, event)
+		}
+	}
+	return history, nil
+}
+
+func (f *fakeCreatorPayoutsRepo) transition(payoutId uuid.UUID, from string, to string, actorId uuid.UUID, apply func(*models.CreatorPayout)) (*models.CreatorPayout, error) {
+	payout, ok := f.payouts[payoutId]
+	if !ok || payout.Status != from {
+		return nil, payment_errors.NewInvalidPayoutTransition("cannot move payout to " + to)
+	}
+	apply(payout)
+	payout.Status = to
+	f.record(payoutId, to, &actorId)
+	copied := *payout
+	return &copied, nil
+}
+
+func (f *fakeCreatorPayoutsRepo) record(payoutId uuid.UUID, status string, actorId *uuid.UUID) {
+	f.events = append(f.events, &models.CreatorPayoutEvent{PayoutID: payoutId, Status: status, ActorID: actorId})
+}
+
+func newTestPayoutsService(repo *fakeCreatorPayoutsRepo, provider PayoutProvider) *creatorPayoutsService {
+	return &creatorPayoutsService{
+		conf:     &config.Config{Payouts: &config.PayoutsConfig{MinimumAmount: 10}},
+		repo:     repo,
+		provider: provider,
+	}
+}
+
+func TestRequestPayout_BelowMinimum(t *testing.T) {
+	repo := newFakeCreatorPayoutsRepo()
+	userId := uuid.New()
+	repo.accounts[userId] = "acct_1"
+	repo.balances[userId] = decimal.NewFromInt(100)
+	service := newTestPayoutsService(repo, NewFakePayoutProvider())
+
+	_, err := service.RequestPayout(userId, decimal.NewFromFloat(9.99))
+	assert.IsType(t, &payment_errors.PayoutBelowMinimum{}, err)
+	assert.True(t, repo.balances[userId].Equal(decimal.NewFromInt(100)))
+}
+
+func TestRequestPayout_MissingAccount(t *testing.T) {
+	repo := newFakeCreatorPayoutsRepo()
+	userId := uuid.New()
+	repo.balances[userId] = decimal.NewFromInt(100)
+	service := newTestPayoutsService(repo, NewFakePayoutProvider())
+
+	_, err := service.RequestPayout(userId, decimal.NewFromInt(50))
+	assert.IsType(t, &payment_errors.PayoutAccountMissing{}, err)
+}
+
+func TestRequestPayout_HoldsBalance(t *testing.T) {
+	repo := newFakeCreatorPayoutsRepo()
+	userId := uuid.New()
+	repo.accounts[userId] = "acct_1"
+	repo.balances[userId] = decimal.NewFromInt(100)
+	service := newTestPayoutsService(repo, NewFakePayoutProvider())
+
+	payout, err := service.RequestPayout(userId, decimal.NewFromInt(40))
+	assert.NoError(t, err)
+	assert.Equal(t, models.CREATOR_PAYOUT_REQUESTED, payout.Status)
+	assert.Equal(t, "acct_1", payout.PayoutAccountID)
+	assert.True(t, repo.balances[userId].Equal(decimal.NewFromInt(60)))
+	assert.True(t, repo.held[userId].Equal(decimal.NewFromInt(40)))
+}
+
+func TestApprovePayout_TransfersAndMarksPaid(t *testing.T) {
+	repo := newFakeCreatorPayoutsRepo()
+	userId := uuid.New()
+	repo.accounts[userId] = "acct_1"
+	repo.balances[userId] = decimal.NewFromInt(100)
+	provider := NewFakePayoutProvider()
+	service := newTestPayoutsService(repo, provider)
+
+	payout, err := service.RequestPayout(userId, decimal.NewFromInt(40))
+	assert.NoError(t, err)
+
+	paid, err := service.ApprovePayout(payout.ID, uuid.New())
+	assert.NoError(t, err)
+	assert.Equal(t, models.CREATOR_PAYOUT_PAID, paid.Status)
+	assert.Equal(t, "fake_tr_"+payout.ID.String(), paid.ProviderReference)
+	assert.True(t, repo.held[userId].IsZero())
+	if assert.Len(t, provider.Transfers(), 1) {
+		assert.Equal(t, "acct_1", provider.Transfers()[0].AccountID)
+		assert.True(t, provider.Transfers()[0].Amount.Equal(decimal.NewFromInt(40)))
+	}
+
+	_, history, err := service.GetPayout(payout.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, history, 3) {
+		assert.Equal(t, models.CREATOR_PAYOUT_REQUESTED, history[0].Status)
+		assert.Equal(t, models.CREATOR_PAYOUT_APPROVED, history[1].Status)
+		assert.Equal(t, models.CREATOR_PAYOUT_PAID, history[2].Status)
+	}
+
+	_, err = service.ApprovePayout(payout.ID, uuid.New())
+	assert.IsType(t, &payment_errors.InvalidPayoutTransition{}, err)
+}
+
+func TestApprovePayout_ProviderErrorKeepsApprovedAndRetries(t *testing.T) {
+	repo := newFakeCreatorPayoutsRepo()
+	userId := uuid.New()
+	repo.accounts[userId] = "acct_1"
+	repo.balances[userId] = decimal.NewFromInt(100)
+	provider := NewFakePayoutProvider()
+	provider.Err = errors.New("provider unavailable")
+	service := newTestPayoutsService(repo, provider)
+
+	payout, err := service.RequestPayout(userId, decimal.NewFromInt(40))
+	assert.NoError(t, err)
+
+	_, err = service.ApprovePayout(payout.ID, uuid.New())
+	assert.IsType(t, &payment_errors.PayoutProviderError{}, err)
+	assert.Equal(t, models.CREATOR_PAYOUT_APPROVED, repo.payouts[payout.ID].Status)
+	assert.True(t, repo.held[userId].Equal(decimal.NewFromInt(40)))
+
+	provider.Err = nil
+	paid, err := service.ApprovePayout(payout.ID, uuid.New())
+	assert.NoError(t, err)
+	assert.Equal(t, models.CREATOR_PAYOUT_PAID, paid.Status)
+	assert.Len(t, provider.Transfers(), 1)
+}
+
+func TestRejectPayout_ReturnsHold(t *testing.T) {
+	repo := newFakeCreatorPayoutsRepo()
+	userId := uuid.New()
+	repo.accounts[userId] = "acct_1"
+	repo.balances[userId] = decimal.NewFromInt(100)
+	provider := NewFakePayoutProvider()
+	service := newTestPayoutsService(repo, provider)
+
+	payout, err := service.RequestPayout(userId, decimal.NewFromInt(40))
+	assert.NoError(t, err)
+
+	rejected, err := service.RejectPayout(payout.ID, uuid.New(), "account could not be verified")
+	assert.NoError(t, err)
+	assert.Equal(t, models.CREATOR_PAYOUT_REJECTED, rejected.Status)
+	assert.Equal(t, "account could not be verified", rejected.RejectionReason)
+	assert.True(t, repo.balances[userId].Equal(decimal.NewFromInt(100)))
+	assert.True(t, repo.held[userId].IsZero())
+
+	_, err = service.ApprovePayout(payout.ID, uuid.New())
+	assert.IsType(t, &payment_errors.InvalidPayoutTransition{}, err)
+	assert.Empty(t, provider.Transfers())
+}
+
+func TestRejectPayout_ApprovedWithoutTransferFails(t *testing.T) {
+	repo := newFakeCreatorPayoutsRepo()
+	userId := uuid.New()
+	repo.accounts[userId] = "acct_1"
+	repo.balances[userId] = decimal.NewFromInt(100)
+	provider := NewFakePayoutProvider()
+	provider.Err = errors.New("provider unavailable")
+	service := newTestPayoutsService(repo, provider)
+
+	payout, err := service.RequestPayout(userId, decimal.NewFromInt(40))
+	assert.NoError(t, err)
+	_, err = service.ApprovePayout(payout.ID, uuid.New())
+	assert.IsType(t, &payment_errors.PayoutProviderError{}, err)
+
+	// The hold is kept while the provider cannot confirm what happened
+	_, err = service.RejectPayout(payout.ID, uuid.New(), "account closed")
+	assert.IsType(t, &payment_errors.PayoutProviderError{}, err)
+	assert.Equal(t, models.CREATOR_PAYOUT_APPROVED, repo.payouts[payout.ID].Status)
+	assert.True(t, repo.held[userId].Equal(decimal.NewFromInt(40)))
+
+	provider.Err = nil
+	failed, err := service.RejectPayout(payout.ID, uuid.New(), "account closed")
+	assert.NoError(t, err)
+	assert.Equal(t, models.CREATOR_PAYOUT_FAILED, failed.Status)
+	assert.True(t, repo.balances[userId].Equal(decimal.NewFromInt(100)))
+	assert.True(t, repo.held[userId].IsZero())
+	assert.Empty(t, provider.Transfers())
+}
+
+func TestRejectPayout_ApprovedAlreadyTransferredIsMarkedPaid(t *testing.T) {
+	repo := newFakeCreatorPayoutsRepo()
+	userId := uuid.New()
+	repo.accounts[userId] = "acct_1"
+	repo.balances[userId] = decimal.NewFromInt(100)
+	provider := NewFakePayoutProvider()
+	service := newTestPayoutsService(repo, provider)
+
+	payout, err := service.RequestPayout(userId, decimal.NewFromInt(40))
+	assert.NoError(t, err)
+	_, err = repo.ApprovePayout(payout.ID, uuid.New())
+	assert.NoError(t, err)
+
+	// The transfer went through but its response never came back
+	reference, err := provider.Transfer("acct_1", payout.Amount, payout.ID.String())
+	assert.NoError(t, err)
+
+	_, err = service.RejectPayout(payout.ID, uuid.New(), "taking too long")
+	assert.IsType(t, &payment_errors.InvalidPayoutTransition{}, err)
+	assert.Equal(t, models.CREATOR_PAYOUT_PAID, repo.payouts[payout.ID].Status)
+	assert.Equal(t, reference, repo.payouts[payout.ID].ProviderReference)
+	assert.True(t, repo.balances[userId].Equal(decimal.NewFromInt(60)))
+	assert.True(t, repo.held[userId].IsZero())
+}
@@ -0,0 +1,130 @@
+package creator_payouts
+
+import (
+	"sync"
+
+	"github.com/FeedTheRealm-org/core-service/config"
+	"github.com/shopspring/decimal"
+	"github.com/stripe/stripe-go/v85"
+	"github.com/stripe/stripe-go/v85/transfer"
+)
+
+// PayoutProvider moves money from the platform to a creator account.
+type PayoutProvider interface {
+	// Transfer sends the amount to the account and returns the provider reference of the transfer.
+	// Retrying with the same idempotency key must not send the money twice.
+	Transfer(accountId string, amount decimal.Decimal, idempotencyKey string) (string, error)
+
+	// FindTransfer looks up the transfer sent with the idempotency key and returns its reference when it exists.
+	FindTransfer(idempotencyKey string) (string, bool, error)
+}
+
+func NewPayoutProvider(conf *config.Config) PayoutProvider {
+	switch conf.Payouts.Provider {
+	case config.FakePayouts:
+		return NewFakePayoutProvider()
+	default:
+		return NewStripeConnectPayoutProvider(conf)
+	}
+}
+
+type stripeConnectPayoutProvider struct {
+	conf *config.Config
+}
+
+func NewStripeConnectPayoutProvider(conf *config.Config) PayoutProvider {
+	stripe.Key = conf.Stripe.StripeApiKey
+	return &stripeConnectPayoutProvider{conf: conf}
+}
+
+func (p *stripeConnectPayoutProvider) Transfer(accountId string, amount decimal.Decimal, idempotencyKey string) (string, error) {
+	params := &stripe.TransferParams{
+		Amount:        stripe.Int64(amount.Mul(decimal.NewFromInt(100)).IntPart()),
+		Currency:      stripe.String(p.conf.Payouts.Currency),
+		Destination:   stripe.String(accountId),
+		TransferGroup: stripe.String("creator_payout_" + idempotencyKey),
+	}
+	params.SetIdempotencyKey("creator_payout_" + idempotencyKey)
+	params.AddMetadata("payout_id", idempotencyKey)
+
+	tr, err := transfer.New(params)
+	if err != nil {
+		return "", err
+	}
+
+	return tr.ID, nil
+}
+
+func (p *stripeConnectPayoutProvider) FindTransfer(idempotencyKey string) (string, bool, error) {
+	params := &stripe.TransferListParams{TransferGroup: stripe.String("creator_payout_" + idempotencyKey)}
+	params.Limit = stripe.Int64(1)
+
+	iter := transfer.List(params)
+	for iter.Next() {
+		return iter.Transfer().ID, true, nil
+	}
+	if err := iter.Err(); err != nil {
+		return "", false, err
+	}
+
+	return "", false, nil
+}
+
+// FakePayoutTransfer is a transfer recorded by the fake provider.
+type FakePayoutTransfer struct {
+	AccountID string
+	Amount    decimal.Decimal
+	Reference string
+}
+
+// FakePayoutProvider records transfers in memory without moving money, for tests and local development.
+type FakePayoutProvider struct {
+	mu        sync.Mutex
+	Err       error
+	transfers map[string]FakePayoutTransfer
+}
+
+func NewFakePayoutProvider() *FakePayoutProvider {
+	return &FakePayoutProvider{transfers: map[string]FakePayoutTransfer{}}
+}
+
+func (p *FakePayoutProvider) Transfer(accountId string, amount decimal.Decimal, idempotencyKey string) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.Err != nil {
+		return "", p.Err
+	}
+
+	if existing, ok := p.transfers[idempotencyKey]; ok {
+		return existing.Reference, nil
+	}
+
+	reference := "fake_tr_" + idempotencyKey
+	p.transfers[idempotencyKey] = FakePayoutTransfer{AccountID: accountId, Amount: amount, Reference: reference}
+	return reference, nil
+}
+
+func (p *FakePayoutProvider) FindTransfer(idempotencyKey string) (string, bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.Err != nil {
+		return "", false, p.Err
+	}
+
+	existing, ok := p.transfers[idempotencyKey]
+	return existing.Reference, ok, nil
+}
+
+// Transfers returns every distinct transfer sent so far.
+func (p *FakePayoutProvider) Transfers() []FakePayoutTransfer {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	transfers := make([]FakePayoutTransfer, 0, len(p.transfers))
+	for _, tr := range p.transfers {
+		transfers = append(transfers, tr)
+	}
+	return transfers
+}
@@ -0,0 +1,31 @@
+package creator_payouts
+
+import (
+	"github.com/FeedTheRealm-org/core-service/internal/payment-service/models"
+	creator_payouts "github.com/FeedTheRealm-org/core-service/internal/payment-service/repositories/creator-payouts"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type CreatorPayoutsService interface {
+	// SetPayoutAccount stores the provider account the creator wants to be paid to.
+	SetPayoutAccount(userId uuid.UUID, accountId string) error
+
+	// RequestPayout holds the amount from the creator balance until an admin reviews the payout.
+	RequestPayout(userId uuid.UUID, amount decimal.Decimal) (*models.CreatorPayout, error)
+
+	// GetPayouts retrieves a page of payouts and the total count.
+	GetPayouts(filter creator_payouts.CreatorPayoutsFilter, offset int, limit int) ([]*models.CreatorPayout, int64, error)
+
+	// GetPayout retrieves a payout along with its status history.
+	GetPayout(payoutId uuid.UUID) (*models.CreatorPayout, []*models.CreatorPayoutEvent, error)
+
+	// ApprovePayout approves a requested payout and sends it through the payout provider.
+	// Approving an already approved payout retries the transfer.
+	ApprovePayout(payoutId uuid.UUID, adminId uuid.UUID) (*models.CreatorPayout, error)
+
+	// RejectPayout rejects a requested payout and returns the held amount to the creator balance.
+	// An approved payout is checked with the provider first, it is marked as paid when the transfer went through
+	// and as failed, releasing the hold, only when the provider confirms no transfer was sent.
+	RejectPayout(payoutId uuid.UUID, adminId uuid.UUID, reason string) (*models.CreatorPayout, error)
+}
@@ -1,10 +1,8 @@
 package gem_balances
 
 import (
-	"bytes"
 	"encoding/json"
 	"fmt"
-	"net/http"
 	"time"
 
 	"github.com/google/uuid"
@@ -16,21 +14,27 @@ import (
 	"github.com/FeedTheRealm-org/core-service/config"
 	gem_balances_errors "github.com/FeedTheRealm-org/core-service/internal/payment-service/errors"
 	"github.com/FeedTheRealm-org/core-service/internal/payment-service/models"
-	creator_balances_repo "github.com/FeedTheRealm-org/core-service/internal/payment-service/repositories/creator-balances"
+	cosmetic_purchases "github.com/FeedTheRealm-org/core-service/internal/payment-service/repositories/cosmetic-purchases"
 	gem_balances "github.com/FeedTheRealm-org/core-service/internal/payment-service/repositories/gem-balances"
 	gem_metrics "github.com/FeedTheRealm-org/core-service/internal/payment-service/repositories/gem-metrics"
 	gem_packs "github.com/FeedTheRealm-org/core-service/internal/payment-service/repositories/gem-packs"
+	gem_transactions "github.com/FeedTheRealm-org/core-service/internal/payment-service/repositories/gem-transactions"
+	"github.com/FeedTheRealm-org/core-service/internal/service_clients"
 	"github.com/FeedTheRealm-org/core-service/internal/utils/email_sender"
 	"github.com/FeedTheRealm-org/core-service/internal/utils/logger"
 )
 
 type gemBalancesService struct {
-	conf                *config.Config
-	gemBalancesRepo     gem_balances.GemBalancesRepository
-	gemMetricsRepo      gem_metrics.GemMetricsRepository
-	packsRepo           gem_packs.GemPacksRepository
-	creatorBalancesRepo creator_balances_repo.CreatorBalancesRepository
-	emailSender         email_sender.EmailSenderService
+	conf                  *config.Config
+	gemBalancesRepo       gem_balances.GemBalancesRepository
+	gemMetricsRepo        gem_metrics.GemMetricsRepository
+	packsRepo             gem_packs.GemPacksRepository
+	cosmeticPurchasesRepo cosmetic_purchases.CosmeticPurchasesRepository
+	gemTransactionsRepo   gem_transactions.GemTransactionsRepository
+	emailSender           email_sender.EmailSenderService
+	assetsClient          service_clients.AssetsClient
+	playersClient         service_clients.PlayersClient
+	accountsClient        service_clients.AccountsClient
 }
 
 const DATE_FORMAT = "2006-01-02 15:04:05 MST"
@@ -40,17 +44,25 @@ func NewGemBalancesService(
 	gemBalancesRepo gem_balances.GemBalancesRepository,
 	gemMetricsRepo gem_metrics.GemMetricsRepository,
 	packsRepo gem_packs.GemPacksRepository,
-	creatorBalancesRepo creator_balances_repo.CreatorBalancesRepository,
+	cosmeticPurchasesRepo cosmetic_purchases.CosmeticPurchasesRepository,
+	gemTransactionsRepo gem_transactions.GemTransactionsRepository,
 	emailSender email_sender.EmailSenderService,
+	assetsClient service_clients.AssetsClient,
+	playersClient service_clients.PlayersClient,
+	accountsClient service_clients.AccountsClient,
 ) GemBalancesService {
 	stripe.Key = conf.Stripe.StripeApiKey
 	return &gemBalancesService{
-		conf:                conf,
-		gemBalancesRepo:     gemBalancesRepo,
-		gemMetricsRepo:      gemMetricsRepo,
-		packsRepo:           packsRepo,
-		creatorBalancesRepo: creatorBalancesRepo,
-		emailSender:         emailSender,
+		conf:                  conf,
+		gemBalancesRepo:       gemBalancesRepo,
+		gemMetricsRepo:        gemMetricsRepo,
+		packsRepo:             packsRepo,
+		cosmeticPurchasesRepo: cosmeticPurchasesRepo,
+		gemTransactionsRepo:   gemTransactionsRepo,
+		emailSender:           emailSender,
+		assetsClient:          assetsClient,
+		playersClient:         playersClient,
+		accountsClient:        accountsClient,
 	}
 }
 
@@ -88,138 +100,194 @@ func (bs *gemBalancesService) CreateGemBalance(userId uuid.UUID) error {
 	return nil
 }
 
-func (bs *gemBalancesService) UpdateGemBalance(userId uuid.UUID, gems int64) error {
-	err := bs.gemBalancesRepo.UpsertGemBalance(userId, gems)
+func (bs *gemBalancesService) UpdateGemBalance(userId uuid.UUID, gems int64, actorId uuid.UUID) error {
+	err := bs.gemBalancesRepo.UpsertGemBalance(userId, gems, actorId)
 	if err != nil {
 		logger.Logger.Error("Failed to update balance for user " + userId.String() + ": " + err.Error())
 		return err
 	}
 
-	logger.Logger.Info("Successfully updated balance for user " + userId.String())
+	logger.Logger.Info("Successfully updated balance for user " + userId.String() + " by " + actorId.String())
 	return nil
 }
 
-func (bs *gemBalancesService) PurchaseCosmetic(userId uuid.UUID, cosmeticId uuid.UUID) error {
-	price, creatorId, err := bs.fetchCosmeticPrice(cosmeticId)
+func (bs *gemBalancesService) GetGemTransactions(userId uuid.UUID, filter gem_transactions.GemTransactionsFilter, offset int, limit int) ([]*models.GemTransaction, int64, error) {
+	transactions, total, err := bs.gemTransactionsRepo.GetTransactionsByUserId(userId, filter, offset, limit)
 	if err != nil {
-		return err
+		logger.Logger.Error("Failed to retrieve gem transactions for user " + userId.String() + ": " + err.Error())
+		return nil, 0, err
 	}
 
-	if err := bs.ensureSufficientBalance(userId, price); err != nil {
-		return err
-	}
+	return transactions, total, nil
+}
 
-	if err := bs.issueCosmeticPurchase(userId, cosmeticId); err != nil {
-		return err
+func (bs *gemBalancesService) GetLedgerBalance(userId uuid.UUID) (int64, error) {
+	balance, err := bs.gemTransactionsRepo.GetLedgerBalance(userId)
+	if err != nil {
+		logger.Logger.Error("Failed to retrieve ledger balance for user " + userId.String() + ": " + err.Error())
+		return 0, err
 	}
 
-	if err := bs.gemBalancesRepo.AddToGemBalance(userId, -price); err != nil {
-		logger.Logger.Error("Failed to deduct gems after purchase: " + err.Error())
+	return balance, nil
+}
+
+func (bs *gemBalancesService) PurchaseCosmetic(userId uuid.UUID, cosmeticId uuid.UUID) error {
+	cosmetic, err := bs.fetchCosmetic(cosmeticId)
+	if err != nil {
 		return err
 	}
 
-	if creatorId != uuid.Nil && price > 0 {
-		creatorEarnings := decimal.NewFromInt(price).
-			Mul(decimal.NewFromFloat(bs.conf.Server.CreatorRevenuePercent)).
-			Mul(decimal.NewFromFloat(bs.conf.Server.DollarsGemsRatio))
-
-		if creatorEarnings.GreaterThan(decimal.Zero) {
-			if err := bs.creatorBalancesRepo.AddBalance(creatorId, creatorEarnings); err != nil {
-				logger.Logger.Error("Failed to add earnings to creator balance: " + err.Error())
-				return fmt.Errorf("failed to credit creator %s for cosmetic purchase %s by user %s: %w", creatorId, cosmeticId, userId, err)
-			}
-		}
+	purchase := bs.newPurchase(userId, cosmetic.Price, cosmetic.CreatedBy, cosmetic.WorldID)
+	purchase.CosmeticID = &cosmeticId
 
-		err = bs.gemMetricsRepo.AddGemsSpent(price)
-		if err != nil {
-			logger.Logger.Error("Failed to update gem metrics for user " + userId.String() + ": " + err.Error())
-		}
+	err = bs.processPurchase(purchase, func() error {
+		return bs.issueCosmeticPurchase(userId, cosmeticId)
+	})
+	if err != nil {
+		return err
 	}
 
 	logger.Logger.Info(fmt.Sprintf("Successfully purchased cosmetic %s for user %s", cosmeticId, userId))
 	return nil
 }
 
-func (bs *gemBalancesService) fetchCosmeticPrice(cosmeticId uuid.UUID) (int64, uuid.UUID, error) {
-	url := fmt.Sprintf("http://127.0.0.1:%d/assets/internal/cosmetics/%s", bs.conf.Server.Port, cosmeticId.String())
-	resp, err := http.Get(url)
+func (bs *gemBalancesService) PurchaseBundle(userId uuid.UUID, bundleId uuid.UUID) ([]uuid.UUID, error) {
+	bundle, err := bs.fetchBundle(bundleId)
 	if err != nil {
-		logger.Logger.Error("Failed to fetch cosmetic details: " + err.Error())
-		return 0, uuid.Nil, err
+		return nil, err
 	}
-	defer func() {
-		if closeErr := resp.Body.Close(); closeErr != nil {
-			logger.Logger.Error("Failed to close response body: " + closeErr.Error())
-		}
-	}()
 
-	if resp.StatusCode == http.StatusNotFound {
-		return 0, uuid.Nil, gem_balances_errors.NewCosmeticNotFound("cosmetic not found")
-	} else if resp.StatusCode != http.StatusOK {
-		errStr := fmt.Sprintf("Failed to get cosmetic, status code: %d", resp.StatusCode)
-		logger.Logger.Error(errStr)
-		return 0, uuid.Nil, fmt.Errorf("%s", errStr)
+	purchase := bs.newPurchase(userId, bundle.Price, bundle.CreatedBy, bundle.WorldID)
+	purchase.BundleID = &bundleId
+
+	var granted []uuid.UUID
+	err = bs.processPurchase(purchase, func() error {
+		var grantErr error
+		granted, grantErr = bs.issueBundlePurchase(userId, bundleId)
+		return grantErr
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	var cosmeticResp struct {
-		Data struct {
-			CosmeticId    uuid.UUID `json:"cosmetic_id"`
-			CosmeticPrice int64     `json:"cosmetic_price"`
-			CreatedBy     uuid.UUID `json:"created_by"`
-		} `json:"data"`
+	logger.Logger.Info(fmt.Sprintf("Successfully purchased bundle %s for user %s, granted %d cosmetics", bundleId, userId, len(granted)))
+	return granted, nil
+}
+
+// newPurchase prices a cosmetic or bundle purchase and the share owed to its creator.
+func (bs *gemBalancesService) newPurchase(userId uuid.UUID, price int64, creatorId uuid.UUID, worldId uuid.UUID) *models.CosmeticPurchase {
+	purchase := &models.CosmeticPurchase{
+		UserID: userId,
+		Price:  price,
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&cosmeticResp); err != nil {
-		logger.Logger.Error("Failed to decode cosmetic response: " + err.Error())
-		return 0, uuid.Nil, err
+	if worldId != uuid.Nil {
+		purchase.WorldID = &worldId
 	}
-
-	return cosmeticResp.Data.CosmeticPrice, cosmeticResp.Data.CreatedBy, nil
+	if creatorId != uuid.Nil && price > 0 {
+		purchase.CreatorID = &creatorId
+		purchase.CreatorEarnings = decimal.NewFromInt(price).
+			Mul(decimal.NewFromFloat(bs.conf.Server.CreatorRevenuePercent)).
+			Mul(decimal.NewFromFloat(bs.conf.Server.DollarsGemsRatio))
+	}
+	return purchase
 }
 
-func (bs *gemBalancesService) ensureSufficientBalance(userId uuid.UUID, price int64) error {
-	balance, err := bs.gemBalancesRepo.GetGemBalanceByUserId(userId)
+// processPurchase debits the buyer, grants the items through issue and refunds the buyer when the grant fails.
+func (bs *gemBalancesService) processPurchase(purchase *models.CosmeticPurchase, issue func() error) error {
+	// Debit, creator credit and purchase record are committed together before granting the items
+	var err error
+	if purchase.IsGift() {
+		err = bs.cosmeticPurchasesRepo.CreatePendingGiftPurchase(purchase, bs.conf.Server.GiftsDailyLimit)
+	} else {
+		err = bs.cosmeticPurchasesRepo.CreatePendingPurchase(purchase)
+	}
 	if err != nil {
-		logger.Logger.Error("Failed to get user balance: " + err.Error())
+		logger.Logger.Error("Failed to debit gems for purchase: " + err.Error())
+		return err
+	}
+
+	if err := issue(); err != nil {
+		bs.refundCosmeticPurchase(purchase)
 		return err
 	}
 
-	if int64(balance.Gems) < price {
-		return gem_balances_errors.NewInsufficientGems("insufficient gems to purchase this cosmetic")
+	if err := bs.cosmeticPurchasesRepo.CompletePurchase(purchase.ID); err != nil {
+		// The items are granted and paid for, only the purchase status is left behind
+		logger.Logger.Error(fmt.Sprintf("Failed to mark purchase %s as completed: %s", purchase.ID, err.Error()))
+	}
+
+	if purchase.CreatorID != nil {
+		if err := bs.gemMetricsRepo.AddGemsSpent(purchase.Price); err != nil {
+			logger.Logger.Error("Failed to update gem metrics for user " + purchase.UserID.String() + ": " + err.Error())
+		}
 	}
 
 	return nil
 }
 
-func (bs *gemBalancesService) issueCosmeticPurchase(userId uuid.UUID, cosmeticId uuid.UUID) error {
-	purchaseReqBody := map[string]string{
-		"cosmetic_id": cosmeticId.String(),
+// refundCosmeticPurchase compensates a purchase whose cosmetic or bundle could not be granted.
+func (bs *gemBalancesService) refundCosmeticPurchase(purchase *models.CosmeticPurchase) {
+	if err := bs.cosmeticPurchasesRepo.RefundPurchase(purchase.ID); err != nil {
+		logger.Logger.Error(fmt.Sprintf("Failed to refund cosmetic purchase %s for user %s, needs manual review: %s", purchase.ID, purchase.UserID, err.Error()))
+		return
 	}
-	reqBodyBytes, err := json.Marshal(purchaseReqBody)
+
+	logger.Logger.Info(fmt.Sprintf("Refunded cosmetic purchase %s for user %s", purchase.ID, purchase.UserID))
+}
+
+func (bs *gemBalancesService) fetchCosmetic(cosmeticId uuid.UUID) (*service_clients.CosmeticInfo, error) {
+	cosmetic, err := bs.assetsClient.GetCosmetic(cosmeticId)
 	if err != nil {
-		return err
+		if _, ok := err.(*service_clients.CosmeticNotFound); ok {
+			return nil, gem_balances_errors.NewCosmeticNotFound("cosmetic not found")
+		}
+		logger.Logger.Error("Failed to fetch cosmetic details: " + err.Error())
+		return nil, err
 	}
 
-	purchaseUrl := fmt.Sprintf("http://127.0.0.1:%d/assets/internal/users/%s/cosmetics", bs.conf.Server.Port, userId.String())
-	postResp, err := http.Post(purchaseUrl, "application/json", bytes.NewBuffer(reqBodyBytes))
+	return cosmetic, nil
+}
+
+func (bs *gemBalancesService) issueCosmeticPurchase(userId uuid.UUID, cosmeticId uuid.UUID) error {
+	err := bs.assetsClient.GrantCosmetic(userId, cosmeticId)
 	if err != nil {
-		logger.Logger.Error("Failed to issue cosmetic purchase: " + err.Error())
+		if _, ok := err.(*service_clients.CosmeticAlreadyOwned); ok {
+			return gem_balances_errors.NewCosmeticAlreadyPurchased("cosmetic was already purchased by this user")
+		}
+		logger.Logger.Error("Failed to record cosmetic purchase: " + err.Error())
 		return err
 	}
-	defer func() {
-		if closeErr := postResp.Body.Close(); closeErr != nil {
-			logger.Logger.Error("Failed to close response body: " + closeErr.Error())
+
+	return nil
+}
+
+func (bs *gemBalancesService) fetchBundle(bundleId uuid.UUID) (*service_clients.BundleInfo, error) {
+	bundle, err := bs.assetsClient.GetBundle(bundleId)
+	if err != nil {
+		if _, ok := err.(*service_clients.BundleNotFound); ok {
+			return nil, gem_balances_errors.NewBundleNotFound("bundle not found")
 		}
-	}()
+		logger.Logger.Error("Failed to fetch bundle details: " + err.Error())
+		return nil, err
+	}
 
-	if postResp.StatusCode == http.StatusConflict {
-		return gem_balances_errors.NewCosmeticAlreadyPurchased("cosmetic was already purchased by this user")
-	} else if postResp.StatusCode != http.StatusCreated {
-		errStr := fmt.Sprintf("Failed to record cosmetic purchase, status code: %d", postResp.StatusCode)
-		logger.Logger.Error(errStr)
-		return fmt.Errorf("%s", errStr)
+	return bundle, nil
+}
+
+func (bs *gemBalancesService) issueBundlePurchase(userId uuid.UUID, bundleId uuid.UUID) ([]uuid.UUID, error) {
+	granted, err := bs.assetsClient.GrantBundle(userId, bundleId)
+	if err != nil {
+		switch err.(type) {
+		case *service_clients.BundleAlreadyOwned:
+			return nil, gem_balances_errors.NewCosmeticAlreadyPurchased("every cosmetic of the bundle was already purchased by this user")
+		case *service_clients.BundleNotFound:
+			return nil, gem_balances_errors.NewBundleNotFound("bundle not found")
+		}
+		logger.Logger.Error("Failed to record bundle purchase: " + err.Error())
+		return nil, err
 	}
 
-	return nil
+	return granted, nil
 }
 
 func (bs *gemBalancesService) CreateCheckoutSession(userId uuid.UUID, email string, packId uuid.UUID, successUrl string, cancelUrl string) (string, error) {
@@ -410,6 +478,30 @@ func (bs *gemBalancesService) HandleWebhook(payload []byte, signature string) er
 		}
 
 		logger.Logger.Info("Processing Stripe checkout session expired event for session ID " + session.ID)
+	case "charge.refunded":
+		var charge stripe.Charge
+		if err := json.Unmarshal(event.Data.Raw, &charge); err != nil {
+			logger.Logger.Error("Failed to parse Stripe webhook event data: " + err.Error())
+			return err
+		}
+
+		return bs.handleChargeRefunded(event, &charge)
+	case "charge.dispute.created":
+		var dispute stripe.Dispute
+		if err := json.Unmarshal(event.Data.Raw, &dispute); err != nil {
+			logger.Logger.Error("Failed to parse Stripe webhook event data: " + err.Error())
+			return err
+		}
+
+		return bs.handleDisputeCreated(event, &dispute)
+	case "charge.dispute.closed":
+		var dispute stripe.Dispute
+		if err := json.Unmarshal(event.Data.Raw, &dispute); err != nil {
+			logger.Logger.Error("Failed to parse Stripe webhook event data: " + err.Error())
+			return err
+		}
+
+		return bs.handleDisputeClosed(event, &dispute)
 	default:
 		logger.Logger.Info("Received unhandled Stripe webhook event type: " + event.Type)
 	}
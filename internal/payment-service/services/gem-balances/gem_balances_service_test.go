@@ -7,22 +7,108 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"net/http"
-	"net/http/httptest"
-	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/FeedTheRealm-org/core-service/config"
 	gem_balances_errors "github.com/FeedTheRealm-org/core-service/internal/payment-service/errors"
 	"github.com/FeedTheRealm-org/core-service/internal/payment-service/models"
+	gem_transactions_repo "github.com/FeedTheRealm-org/core-service/internal/payment-service/repositories/gem-transactions"
+	"github.com/FeedTheRealm-org/core-service/internal/service_clients"
 	"github.com/FeedTheRealm-org/core-service/internal/utils/email_sender"
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 )
 
+type fakeAssetsClient struct {
+	mu             sync.Mutex
+	cosmetic       *service_clients.CosmeticInfo
+	getErr         error
+	grantErr       error
+	granted        bool
+	giftedBy       *uuid.UUID
+	bundle         *service_clients.BundleInfo
+	grantedBundle  []uuid.UUID
+	getBundleErr   error
+	grantBundleErr error
+}
+
+func (f *fakeAssetsClient) GetCosmetic(cosmeticId uuid.UUID) (*service_clients.CosmeticInfo, error) {
+	if f.getErr != nil {
+		return nil, f.getErr
+	}
+	return f.cosmetic, nil
+}
+
+func (f *fakeAssetsClient) GrantCosmetic(userId uuid.UUID, cosmeticId uuid.UUID) error {
+	if f.grantErr != nil {
+		return f.grantErr
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.granted = true
+	return nil
+}
+
+func (f *fakeAssetsClient) GiftCosmetic(recipientId uuid.UUID, cosmeticId uuid.UUID, gifterId uuid.UUID) error {
+	if f.grantErr != nil {
+		return f.grantErr
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.granted = true
+	f.giftedBy = &gifterId
+	return nil
+}
+
+func (f *fakeAssetsClient) GetBundle(bundleId uuid.UUID) (*service_clients.BundleInfo, error) {
+	if f.getBundleErr != nil {
+		return nil, f.getBundleErr
+	}
+	return f.bundle, nil
+}
+
+func (f *fakeAssetsClient) GrantBundle(userId uuid.UUID, bundleId uuid.UUID) ([]uuid.UUID, error) {
+	if f.grantBundleErr != nil {
+		return nil, f.grantBundleErr
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.granted = true
+	return f.grantedBundle, nil
+}
+
+type fakePlayersClient struct {
+	players map[string]uuid.UUID
+}
+
+func (f *fakePlayersClient) GetPlayerByCharacterName(characterName string) (*service_clients.PlayerInfo, error) {
+	userId, ok := f.players[characterName]
+	if !ok {
+		return nil, service_clients.NewPlayerNotFound("character not found")
+	}
+	return &service_clients.PlayerInfo{UserId: userId, CharacterName: characterName}, nil
+}
+
+func (f *fakePlayersClient) GetPlayer(userId uuid.UUID) (*service_clients.PlayerInfo, error) {
+	for name, id := range f.players {
+		if id == userId {
+			return &service_clients.PlayerInfo{UserId: id, CharacterName: name}, nil
+		}
+	}
+	return nil, service_clients.NewPlayerNotFound("character not found")
+}
+
+type fakeAccountsClient struct{}
+
+func (f *fakeAccountsClient) GetAccountEmail(userId uuid.UUID) (string, error) {
+	return userId.String() + "@example.com", nil
+}
+
 type fakeGemBalancesRepo struct {
 	balances       map[uuid.UUID]*models.GemBalance
 	getErr         error
@@ -36,6 +122,9 @@ type fakeGemBalancesRepo struct {
 	applyErr       error
 	createErr      error
 	createdBalance bool
+	adjustments    []*models.GemTransaction
+	adjustEvents   map[string]bool
+	adjustErr      error
 }
 
 func (f *fakeGemBalancesRepo) CreateGemBalance(userId uuid.UUID) error {
@@ -72,15 +161,15 @@ func (f *fakeGemBalancesRepo) GetGemBalanceByUserId(userId uuid.UUID) (*models.G
 	return bal, nil
 }
 
-func (f *fakeGemBalancesRepo) AddToGemBalance(userId uuid.UUID, gems int64) error {
+func (f *fakeGemBalancesRepo) AddToGemBalance(transaction *models.GemTransaction) error {
 	if f.addErr != nil {
 		return f.addErr
 	}
 	f.addCalled = true
-	f.addDelta = gems
-	bal, ok := f.balances[userId]
+	f.addDelta = transaction.Amount
+	bal, ok := f.balances[transaction.UserID]
 	if ok {
-		bal.Gems += gems
+		bal.Gems += transaction.Amount
 	}
 	return nil
 }
@@ -93,7 +182,34 @@ func (f *fakeGemBalancesRepo) ApplyStripeCheckoutCreditIfUnprocessed(userId uuid
 	return f.applyResponse, nil
 }
 
-func (f *fakeGemBalancesRepo) UpsertGemBalance(userId uuid.UUID, gems int64) error {
+func (f *fakeGemBalancesRepo) ApplyStripeAdjustmentIfUnprocessed(event *models.ProcessedStripeWebhookEvent, transaction *models.GemTransaction, locked *bool) (bool, error) {
+	if f.adjustErr != nil {
+		return false, f.adjustErr
+	}
+	if f.adjustEvents == nil {
+		f.adjustEvents = map[string]bool{}
+	}
+	if f.adjustEvents[event.EventID] {
+		return false, nil
+	}
+	f.adjustEvents[event.EventID] = true
+	if f.balances == nil {
+		f.balances = map[uuid.UUID]*models.GemBalance{}
+	}
+	bal, ok := f.balances[transaction.UserID]
+	if !ok {
+		bal = &models.GemBalance{UserId: transaction.UserID}
+		f.balances[transaction.UserID] = bal
+	}
+	bal.Gems += transaction.Amount
+	if locked != nil {
+		bal.Locked = *locked
+	}
+	f.adjustments = append(f.adjustments, transaction)
+	return true, nil
+}
+
+func (f *fakeGemBalancesRepo) UpsertGemBalance(userId uuid.UUID, gems int64, actorId uuid.UUID) error {
 	if f.upsertErr != nil {
 		return f.upsertErr
 	}
@@ -105,18 +221,26 @@ func (f *fakeGemBalancesRepo) UpsertGemBalance(userId uuid.UUID, gems int64) err
 }
 
 type fakeGemMetricsRepo struct {
-	spentCalled  bool
-	boughtCalled bool
-	spentErr     error
-	boughtErr    error
+	spentCalled   bool
+	boughtCalled  bool
+	spentErr      error
+	boughtErr     error
+	boughtGems    int64
+	boughtRevenue float64
 }
 
 func (f *fakeGemMetricsRepo) GetMetrics() (*models.GemMetrics, error) {
 	return nil, nil
 }
 
+func (f *fakeGemMetricsRepo) GetMetricsRange(from time.Time, to time.Time, granularity string) ([]*models.GemMetricsBucket, error) {
+	return nil, nil
+}
+
 func (f *fakeGemMetricsRepo) AddGemsBoughtAndRevenue(gems int64, revenue float64) error {
 	f.boughtCalled = true
+	f.boughtGems += gems
+	f.boughtRevenue += revenue
 	return f.boughtErr
 }
 
@@ -153,22 +277,106 @@ func (f *fakeGemPacksRepo) DeleteGemPack(id uuid.UUID) error {
 	return nil
 }
 
-type fakeCreatorBalancesRepo struct {
-	addCalled bool
-	addErr    error
+// fakeCosmeticPurchasesRepo mimics the purchase transaction over an in-memory balance.
+type fakeCosmeticPurchasesRepo struct {
+	mu              sync.Mutex
+	balances        map[uuid.UUID]int64
+	creatorEarnings map[uuid.UUID]decimal.Decimal
+	purchases       map[uuid.UUID]*models.CosmeticPurchase
+	createErr       error
+	completeErr     error
+	refundErr       error
 }
 
-func (f *fakeCreatorBalancesRepo) AddBalance(userId uuid.UUID, amount decimal.Decimal) error {
-	f.addCalled = true
-	return f.addErr
+func newFakeCosmeticPurchasesRepo(userId uuid.UUID, gems int64) *fakeCosmeticPurchasesRepo {
+	return &fakeCosmeticPurchasesRepo{
+		balances:        map[uuid.UUID]int64{userId: gems},
+		creatorEarnings: map[uuid.UUID]decimal.Decimal{},
+		purchases:       map[uuid.UUID]*models.CosmeticPurchase{},
+	}
+}
+
+func (f *fakeCosmeticPurchasesRepo) CreatePendingPurchase(purchase *models.CosmeticPurchase) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.createErr != nil {
+		return f.createErr
+	}
+	if f.balances[purchase.UserID] < purchase.Price {
+		return gem_balances_errors.NewInsufficientGems("insufficient gems to purchase this cosmetic")
+	}
+	for _, p := range f.purchases {
+		if owner(p) == owner(purchase) && p.ItemID() == purchase.ItemID() && p.Status != models.COSMETIC_PURCHASE_REFUNDED {
+			return gem_balances_errors.NewCosmeticAlreadyPurchased("cosmetic was already purchased by this user")
+		}
+	}
+
+	f.balances[purchase.UserID] -= purchase.Price
+	if purchase.CreatorID != nil {
+		f.creatorEarnings[*purchase.CreatorID] = f.creatorEarnings[*purchase.CreatorID].Add(purchase.CreatorEarnings)
+	}
+	purchase.ID = uuid.New()
+	purchase.Status = models.COSMETIC_PURCHASE_PENDING
+	f.purchases[purchase.ID] = purchase
+	return nil
+}
+
+func (f *fakeCosmeticPurchasesRepo) CreatePendingGiftPurchase(purchase *models.CosmeticPurchase, dailyLimit int) error {
+	f.mu.Lock()
+	sent := 0
+	for _, p := range f.purchases {
+		if p.UserID == purchase.UserID && p.IsGift() && p.Status != models.COSMETIC_PURCHASE_REFUNDED {
+			sent++
+		}
+	}
+	f.mu.Unlock()
+	if sent >= dailyLimit {
+		return gem_balances_errors.NewGiftLimitReached("daily gift limit reached")
+	}
+	return f.CreatePendingPurchase(purchase)
 }
 
-func (f *fakeCreatorBalancesRepo) GetBalance(userId uuid.UUID) (decimal.Decimal, error) {
-	return decimal.Zero, nil
+func owner(purchase *models.CosmeticPurchase) uuid.UUID {
+	if purchase.IsGift() {
+		return *purchase.RecipientID
+	}
+	return purchase.UserID
 }
 
-func (f *fakeCreatorBalancesRepo) GetAllBalances() ([]models.CreatorBalance, error) {
-	return nil, nil
+func (f *fakeCosmeticPurchasesRepo) CompletePurchase(purchaseId uuid.UUID) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.completeErr != nil {
+		return f.completeErr
+	}
+	f.purchases[purchaseId].Status = models.COSMETIC_PURCHASE_COMPLETED
+	return nil
+}
+
+func (f *fakeCosmeticPurchasesRepo) RefundPurchase(purchaseId uuid.UUID) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.refundErr != nil {
+		return f.refundErr
+	}
+	purchase := f.purchases[purchaseId]
+	if purchase.Status != models.COSMETIC_PURCHASE_PENDING {
+		return nil
+	}
+	purchase.Status = models.COSMETIC_PURCHASE_REFUNDED
+	f.balances[purchase.UserID] += purchase.Price
+	if purchase.CreatorID != nil {
+		f.creatorEarnings[*purchase.CreatorID] = f.creatorEarnings[*purchase.CreatorID].Sub(purchase.CreatorEarnings)
+	}
+	return nil
+}
+
+func (f *fakeCosmeticPurchasesRepo) statuses() []string {
+	var statuses []string
+	for _, p := range f.purchases {
+		statuses = append(statuses, p.Status)
+	}
+	return statuses
 }
 
 type fakeEmailSender struct {
@@ -176,6 +384,8 @@ type fakeEmailSender struct {
 	sendGemPurchaseFailedErr    error
 	sendGemPurchaseCalled       bool
 	sendGemPurchaseFailedCalled bool
+	gemReversalEmails           []email_sender.GemReversalEmailData
+	giftEmails                  []email_sender.GiftReceivedEmailData
 }
 
 func (f *fakeEmailSender) CreateBaseEmailData(toEmail string) email_sender.BaseEmailData {
@@ -186,6 +396,22 @@ func (f *fakeEmailSender) SendPasswordResetEmail(data email_sender.PasswordReset
 	return nil
 }
 
+func (f *fakeEmailSender) SendAccountLockedEmail(data email_sender.AccountLockedEmailData) error {
+	return nil
+}
+
+func (f *fakeEmailSender) SendAccountDeletionEmail(data email_sender.AccountDeletionEmailData) error {
+	return nil
+}
+
+func (f *fakeEmailSender) SendEmailChangeVerificationEmail(data email_sender.EmailChangeVerificationEmailData) error {
+	return nil
+}
+
+func (f *fakeEmailSender) SendEmailChangeNoticeEmail(data email_sender.EmailChangeNoticeEmailData) error {
+	return nil
+}
+
 func (f *fakeEmailSender) SendVerificationEmail(data email_sender.VerificationEmailData) error {
 	return nil
 }
@@ -200,6 +426,16 @@ func (f *fakeEmailSender) SendGemPurchaseFailedEmail(data email_sender.GemPurcha
 	return f.sendGemPurchaseFailedErr
 }
 
+func (f *fakeEmailSender) SendGemReversalEmail(data email_sender.GemReversalEmailData) error {
+	f.gemReversalEmails = append(f.gemReversalEmails, data)
+	return nil
+}
+
+func (f *fakeEmailSender) SendGiftReceivedEmail(data email_sender.GiftReceivedEmailData) error {
+	f.giftEmails = append(f.giftEmails, data)
+	return nil
+}
+
 func (f *fakeEmailSender) SendSubscriptionStartedEmail(data email_sender.SubscriptionStartedData) error {
 	return nil
 }
@@ -254,7 +490,7 @@ func TestGemBalancesService_UpdateGemBalance_Error(t *testing.T) {
 	repo := &fakeGemBalancesRepo{upsertErr: errors.New("boom")}
 	service := &gemBalancesService{conf: conf, gemBalancesRepo: repo}
 
-	err := service.UpdateGemBalance(uuid.New(), 10)
+	err := service.UpdateGemBalance(uuid.New(), 10, uuid.New())
 	assert.Error(t, err)
 }
 
@@ -262,50 +498,32 @@ func TestGemBalancesService_PurchaseCosmetic_Success(t *testing.T) {
 	userID := uuid.New()
 	cosmeticID := uuid.New()
 	creatorID := uuid.New()
-
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if strings.Contains(r.URL.Path, "/assets/internal/cosmetics/") {
-			payload := map[string]any{
-				"data": map[string]any{
-					"cosmetic_id":    cosmeticID,
-					"cosmetic_price": int64(10),
-					"created_by":     creatorID,
-				},
-			}
-			_ = json.NewEncoder(w).Encode(payload)
-			return
-		}
-		if strings.Contains(r.URL.Path, "/assets/internal/users/") {
-			w.WriteHeader(http.StatusCreated)
-			return
-		}
-		w.WriteHeader(http.StatusNotFound)
-	}))
-	defer server.Close()
+	worldID := uuid.New()
 
 	conf := config.CreateConfig()
-	portStr := strings.TrimPrefix(server.URL, "http://127.0.0.1:")
-	port, _ := strconv.Atoi(portStr)
-	conf.Server.Port = port
+	assets := &fakeAssetsClient{cosmetic: &service_clients.CosmeticInfo{CosmeticId: cosmeticID, Price: 10, CreatedBy: creatorID, WorldID: worldID}}
 	conf.Server.CreatorRevenuePercent = 1.0
 	conf.Server.DollarsGemsRatio = 1.0
 
-	gemRepo := &fakeGemBalancesRepo{balances: map[uuid.UUID]*models.GemBalance{userID: {UserId: userID, Gems: 20}}}
+	purchasesRepo := newFakeCosmeticPurchasesRepo(userID, 20)
 	metricsRepo := &fakeGemMetricsRepo{}
-	creatorRepo := &fakeCreatorBalancesRepo{}
 	service := &gemBalancesService{
-		conf:                conf,
-		gemBalancesRepo:     gemRepo,
-		gemMetricsRepo:      metricsRepo,
-		creatorBalancesRepo: creatorRepo,
-		emailSender:         &fakeEmailSender{},
+		conf:                  conf,
+		assetsClient:          assets,
+		gemMetricsRepo:        metricsRepo,
+		cosmeticPurchasesRepo: purchasesRepo,
+		emailSender:           &fakeEmailSender{},
 	}
 
 	err := service.PurchaseCosmetic(userID, cosmeticID)
 	assert.NoError(t, err)
-	assert.True(t, gemRepo.addCalled)
-	assert.Equal(t, int64(-10), gemRepo.addDelta)
-	assert.True(t, creatorRepo.addCalled)
+	assert.True(t, assets.granted)
+	assert.Equal(t, int64(10), purchasesRepo.balances[userID])
+	assert.True(t, decimal.NewFromInt(10).Equal(purchasesRepo.creatorEarnings[creatorID]))
+	assert.Equal(t, []string{models.COSMETIC_PURCHASE_COMPLETED}, purchasesRepo.statuses())
+	for _, purchase := range purchasesRepo.purchases {
+		assert.Equal(t, &worldID, purchase.WorldID)
+	}
 	assert.True(t, metricsRepo.spentCalled)
 }
 
@@ -313,142 +531,235 @@ func TestGemBalancesService_PurchaseCosmetic_CosmeticNotFound(t *testing.T) {
 	userID := uuid.New()
 	cosmeticID := uuid.New()
 
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusNotFound)
-	}))
-	defer server.Close()
-
 	conf := config.CreateConfig()
-	portStr := strings.TrimPrefix(server.URL, "http://127.0.0.1:")
-	port, _ := strconv.Atoi(portStr)
-	conf.Server.Port = port
+	assets := &fakeAssetsClient{getErr: service_clients.NewCosmeticNotFound("cosmetic not found")}
 
-	gemRepo := &fakeGemBalancesRepo{balances: map[uuid.UUID]*models.GemBalance{userID: {UserId: userID, Gems: 10}}}
-	service := &gemBalancesService{conf: conf, gemBalancesRepo: gemRepo}
+	purchasesRepo := newFakeCosmeticPurchasesRepo(userID, 10)
+	service := &gemBalancesService{conf: conf, assetsClient: assets, cosmeticPurchasesRepo: purchasesRepo}
 
 	err := service.PurchaseCosmetic(userID, cosmeticID)
 	assert.Error(t, err)
 	_, notFound := err.(*gem_balances_errors.CosmeticNotFound)
 	assert.True(t, notFound)
+	assert.Empty(t, purchasesRepo.purchases)
 }
 
 func TestGemBalancesService_PurchaseCosmetic_InsufficientBalance(t *testing.T) {
 	userID := uuid.New()
 	cosmeticID := uuid.New()
 
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		payload := map[string]any{
-			"data": map[string]any{
-				"cosmetic_id":    cosmeticID,
-				"cosmetic_price": int64(50),
-				"created_by":     uuid.Nil,
-			},
-		}
-		_ = json.NewEncoder(w).Encode(payload)
-	}))
-	defer server.Close()
-
 	conf := config.CreateConfig()
-	portStr := strings.TrimPrefix(server.URL, "http://127.0.0.1:")
-	port, _ := strconv.Atoi(portStr)
-	conf.Server.Port = port
+	assets := &fakeAssetsClient{cosmetic: &service_clients.CosmeticInfo{CosmeticId: cosmeticID, Price: 50, CreatedBy: uuid.Nil}}
 
-	gemRepo := &fakeGemBalancesRepo{balances: map[uuid.UUID]*models.GemBalance{userID: {UserId: userID, Gems: 10}}}
-	service := &gemBalancesService{conf: conf, gemBalancesRepo: gemRepo}
+	purchasesRepo := newFakeCosmeticPurchasesRepo(userID, 10)
+	service := &gemBalancesService{conf: conf, assetsClient: assets, cosmeticPurchasesRepo: purchasesRepo}
 
 	err := service.PurchaseCosmetic(userID, cosmeticID)
 	assert.Error(t, err)
 	_, insufficient := err.(*gem_balances_errors.InsufficientGems)
 	assert.True(t, insufficient)
+	assert.False(t, assets.granted)
+	assert.Equal(t, int64(10), purchasesRepo.balances[userID])
 }
 
 func TestGemBalancesService_PurchaseCosmetic_AlreadyPurchased(t *testing.T) {
 	userID := uuid.New()
 	cosmeticID := uuid.New()
 
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if strings.Contains(r.URL.Path, "/assets/internal/cosmetics/") {
-			payload := map[string]any{
-				"data": map[string]any{
-					"cosmetic_id":    cosmeticID,
-					"cosmetic_price": int64(1),
-					"created_by":     uuid.Nil,
-				},
-			}
-			_ = json.NewEncoder(w).Encode(payload)
-			return
-		}
-		if strings.Contains(r.URL.Path, "/assets/internal/users/") {
-			w.WriteHeader(http.StatusConflict)
-			return
-		}
-		w.WriteHeader(http.StatusNotFound)
-	}))
-	defer server.Close()
-
 	conf := config.CreateConfig()
-	portStr := strings.TrimPrefix(server.URL, "http://127.0.0.1:")
-	port, _ := strconv.Atoi(portStr)
-	conf.Server.Port = port
+	assets := &fakeAssetsClient{cosmetic: &service_clients.CosmeticInfo{CosmeticId: cosmeticID, Price: 1, CreatedBy: uuid.Nil}, grantErr: service_clients.NewCosmeticAlreadyOwned("cosmetic was already purchased by this user")}
 
-	gemRepo := &fakeGemBalancesRepo{balances: map[uuid.UUID]*models.GemBalance{userID: {UserId: userID, Gems: 10}}}
-	service := &gemBalancesService{conf: conf, gemBalancesRepo: gemRepo}
+	purchasesRepo := newFakeCosmeticPurchasesRepo(userID, 10)
+	service := &gemBalancesService{conf: conf, assetsClient: assets, cosmeticPurchasesRepo: purchasesRepo}
 
 	err := service.PurchaseCosmetic(userID, cosmeticID)
 	assert.Error(t, err)
 	_, conflict := err.(*gem_balances_errors.CosmeticAlreadyPurchased)
 	assert.True(t, conflict)
+	assert.Equal(t, int64(10), purchasesRepo.balances[userID])
+	assert.Equal(t, []string{models.COSMETIC_PURCHASE_REFUNDED}, purchasesRepo.statuses())
 }
 
-func TestGemBalancesService_PurchaseCosmetic_AddBalanceError(t *testing.T) {
+func TestGemBalancesService_PurchaseCosmetic_CreatePurchaseError(t *testing.T) {
 	userID := uuid.New()
 	cosmeticID := uuid.New()
 
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if strings.Contains(r.URL.Path, "/assets/internal/cosmetics/") {
-			payload := map[string]any{
-				"data": map[string]any{
-					"cosmetic_id":    cosmeticID,
-					"cosmetic_price": int64(1),
-					"created_by":     uuid.Nil,
-				},
-			}
-			_ = json.NewEncoder(w).Encode(payload)
-			return
-		}
-		if strings.Contains(r.URL.Path, "/assets/internal/users/") {
-			w.WriteHeader(http.StatusCreated)
-			return
-		}
-		w.WriteHeader(http.StatusNotFound)
-	}))
-	defer server.Close()
+	conf := config.CreateConfig()
+	assets := &fakeAssetsClient{cosmetic: &service_clients.CosmeticInfo{CosmeticId: cosmeticID, Price: 1, CreatedBy: uuid.Nil}}
+
+	purchasesRepo := newFakeCosmeticPurchasesRepo(userID, 10)
+	purchasesRepo.createErr = errors.New("boom")
+	service := &gemBalancesService{conf: conf, assetsClient: assets, cosmeticPurchasesRepo: purchasesRepo}
+
+	err := service.PurchaseCosmetic(userID, cosmeticID)
+	assert.Error(t, err)
+	assert.False(t, assets.granted)
+}
+
+func TestGemBalancesService_PurchaseCosmetic_GrantErrorRefunds(t *testing.T) {
+	userID := uuid.New()
+	cosmeticID := uuid.New()
+	creatorID := uuid.New()
 
 	conf := config.CreateConfig()
-	portStr := strings.TrimPrefix(server.URL, "http://127.0.0.1:")
-	port, _ := strconv.Atoi(portStr)
-	conf.Server.Port = port
+	conf.Server.CreatorRevenuePercent = 1.0
+	conf.Server.DollarsGemsRatio = 1.0
+	assets := &fakeAssetsClient{
+		cosmetic: &service_clients.CosmeticInfo{CosmeticId: cosmeticID, Price: 10, CreatedBy: creatorID},
+		grantErr: service_clients.NewServiceUnavailable("failed to reach assets service to record purchase"),
+	}
 
-	gemRepo := &fakeGemBalancesRepo{addErr: errors.New("boom"), balances: map[uuid.UUID]*models.GemBalance{userID: {UserId: userID, Gems: 10}}}
-	service := &gemBalancesService{conf: conf, gemBalancesRepo: gemRepo}
+	purchasesRepo := newFakeCosmeticPurchasesRepo(userID, 10)
+	service := &gemBalancesService{conf: conf, assetsClient: assets, cosmeticPurchasesRepo: purchasesRepo}
 
 	err := service.PurchaseCosmetic(userID, cosmeticID)
 	assert.Error(t, err)
+	assert.Equal(t, int64(10), purchasesRepo.balances[userID])
+	assert.True(t, purchasesRepo.creatorEarnings[creatorID].IsZero())
+	assert.Equal(t, []string{models.COSMETIC_PURCHASE_REFUNDED}, purchasesRepo.statuses())
 }
 
-func TestGemBalancesService_FetchCosmeticPrice_BadJSON(t *testing.T) {
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		_, _ = w.Write([]byte("not-json"))
-	}))
-	defer server.Close()
+func TestGemBalancesService_PurchaseCosmetic_RefundError(t *testing.T) {
+	userID := uuid.New()
+	cosmeticID := uuid.New()
+
+	conf := config.CreateConfig()
+	assets := &fakeAssetsClient{
+		cosmetic: &service_clients.CosmeticInfo{CosmeticId: cosmeticID, Price: 10, CreatedBy: uuid.Nil},
+		grantErr: errors.New("grant failed"),
+	}
+
+	purchasesRepo := newFakeCosmeticPurchasesRepo(userID, 10)
+	purchasesRepo.refundErr = errors.New("refund failed")
+	service := &gemBalancesService{conf: conf, assetsClient: assets, cosmeticPurchasesRepo: purchasesRepo}
+
+	err := service.PurchaseCosmetic(userID, cosmeticID)
+	assert.EqualError(t, err, "grant failed")
+	assert.Equal(t, []string{models.COSMETIC_PURCHASE_PENDING}, purchasesRepo.statuses())
+}
+
+func TestGemBalancesService_PurchaseCosmetic_CompleteError(t *testing.T) {
+	userID := uuid.New()
+	cosmeticID := uuid.New()
 
 	conf := config.CreateConfig()
-	portStr := strings.TrimPrefix(server.URL, "http://127.0.0.1:")
-	port, _ := strconv.Atoi(portStr)
-	conf.Server.Port = port
+	assets := &fakeAssetsClient{cosmetic: &service_clients.CosmeticInfo{CosmeticId: cosmeticID, Price: 10, CreatedBy: uuid.Nil}}
 
-	service := &gemBalancesService{conf: conf}
-	_, _, err := service.fetchCosmeticPrice(uuid.New())
+	purchasesRepo := newFakeCosmeticPurchasesRepo(userID, 10)
+	purchasesRepo.completeErr = errors.New("boom")
+	service := &gemBalancesService{conf: conf, assetsClient: assets, cosmeticPurchasesRepo: purchasesRepo}
+
+	err := service.PurchaseCosmetic(userID, cosmeticID)
+	assert.NoError(t, err)
+	assert.True(t, assets.granted)
+	assert.Equal(t, int64(0), purchasesRepo.balances[userID])
+}
+
+func TestGemBalancesService_PurchaseCosmetic_Concurrent(t *testing.T) {
+	userID := uuid.New()
+	conf := config.CreateConfig()
+	assets := &fakeAssetsClient{cosmetic: &service_clients.CosmeticInfo{Price: 10, CreatedBy: uuid.Nil}}
+
+	purchasesRepo := newFakeCosmeticPurchasesRepo(userID, 35)
+	service := &gemBalancesService{conf: conf, assetsClient: assets, cosmeticPurchasesRepo: purchasesRepo}
+
+	var wg sync.WaitGroup
+	var succeeded atomic.Int32
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := service.PurchaseCosmetic(userID, uuid.New()); err == nil {
+				succeeded.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(3), succeeded.Load())
+	assert.Equal(t, int64(5), purchasesRepo.balances[userID])
+}
+
+func TestGemBalancesService_PurchaseBundle_Success(t *testing.T) {
+	userID := uuid.New()
+	bundleID := uuid.New()
+	creatorID := uuid.New()
+	worldID := uuid.New()
+	granted := []uuid.UUID{uuid.New(), uuid.New()}
+
+	conf := config.CreateConfig()
+	conf.Server.CreatorRevenuePercent = 0.5
+	conf.Server.DollarsGemsRatio = 1.0
+	assets := &fakeAssetsClient{
+		bundle:        &service_clients.BundleInfo{BundleId: bundleID, Price: 30, CreatedBy: creatorID, WorldID: worldID, CosmeticIds: append(granted, uuid.New())},
+		grantedBundle: granted,
+	}
+
+	purchasesRepo := newFakeCosmeticPurchasesRepo(userID, 50)
+	metricsRepo := &fakeGemMetricsRepo{}
+	service := &gemBalancesService{conf: conf, assetsClient: assets, gemMetricsRepo: metricsRepo, cosmeticPurchasesRepo: purchasesRepo}
+
+	ids, err := service.PurchaseBundle(userID, bundleID)
+	assert.NoError(t, err)
+	assert.Equal(t, granted, ids)
+	assert.Equal(t, int64(20), purchasesRepo.balances[userID])
+	assert.True(t, decimal.NewFromInt(15).Equal(purchasesRepo.creatorEarnings[creatorID]))
+	assert.Equal(t, []string{models.COSMETIC_PURCHASE_COMPLETED}, purchasesRepo.statuses())
+	for _, purchase := range purchasesRepo.purchases {
+		assert.Equal(t, &bundleID, purchase.BundleID)
+		assert.Nil(t, purchase.CosmeticID)
+		assert.Equal(t, &worldID, purchase.WorldID)
+	}
+	assert.True(t, metricsRepo.spentCalled)
+}
+
+func TestGemBalancesService_PurchaseBundle_NotFound(t *testing.T) {
+	assets := &fakeAssetsClient{getBundleErr: service_clients.NewBundleNotFound("bundle not found")}
+	service := &gemBalancesService{conf: config.CreateConfig(), assetsClient: assets}
+
+	_, err := service.PurchaseBundle(uuid.New(), uuid.New())
+	_, ok := err.(*gem_balances_errors.BundleNotFound)
+	assert.True(t, ok)
+}
+
+func TestGemBalancesService_PurchaseBundle_AlreadyOwnedRefunds(t *testing.T) {
+	userID := uuid.New()
+	bundleID := uuid.New()
+
+	assets := &fakeAssetsClient{
+		bundle:         &service_clients.BundleInfo{BundleId: bundleID, Price: 30, CreatedBy: uuid.New()},
+		grantBundleErr: service_clients.NewBundleAlreadyOwned("every cosmetic of the bundle is already owned by this user"),
+	}
+
+	purchasesRepo := newFakeCosmeticPurchasesRepo(userID, 30)
+	service := &gemBalancesService{conf: config.CreateConfig(), assetsClient: assets, cosmeticPurchasesRepo: purchasesRepo}
+
+	_, err := service.PurchaseBundle(userID, bundleID)
+	_, conflict := err.(*gem_balances_errors.CosmeticAlreadyPurchased)
+	assert.True(t, conflict)
+	assert.Equal(t, int64(30), purchasesRepo.balances[userID])
+	assert.Equal(t, []string{models.COSMETIC_PURCHASE_REFUNDED}, purchasesRepo.statuses())
+}
+
+func TestGemBalancesService_PurchaseBundle_InsufficientBalance(t *testing.T) {
+	userID := uuid.New()
+	assets := &fakeAssetsClient{bundle: &service_clients.BundleInfo{BundleId: uuid.New(), Price: 30}}
+
+	purchasesRepo := newFakeCosmeticPurchasesRepo(userID, 10)
+	service := &gemBalancesService{conf: config.CreateConfig(), assetsClient: assets, cosmeticPurchasesRepo: purchasesRepo}
+
+	_, err := service.PurchaseBundle(userID, uuid.New())
+	_, insufficient := err.(*gem_balances_errors.InsufficientGems)
+	assert.True(t, insufficient)
+	assert.False(t, assets.granted)
+}
+
+func TestGemBalancesService_FetchCosmeticPrice_BadJSON(t *testing.T) {
+	conf := config.CreateConfig()
+	assets := &fakeAssetsClient{getErr: errors.New("failed to decode cosmetic response")}
+
+	service := &gemBalancesService{conf: conf, assetsClient: assets}
+	_, err := service.fetchCosmetic(uuid.New())
 	assert.Error(t, err)
 }
 
@@ -530,25 +841,6 @@ func webhookConf(secret string) *config.Config {
 	return conf
 }
 
-func TestGemBalancesService_EnsureSufficientBalance_RepoError(t *testing.T) {
-	conf := config.CreateConfig()
-	repo := &fakeGemBalancesRepo{getErr: errors.New("boom")}
-	service := &gemBalancesService{conf: conf, gemBalancesRepo: repo}
-
-	err := service.ensureSufficientBalance(uuid.New(), 10)
-	assert.Error(t, err)
-}
-
-func TestGemBalancesService_EnsureSufficientBalance_Sufficient(t *testing.T) {
-	userID := uuid.New()
-	conf := config.CreateConfig()
-	repo := &fakeGemBalancesRepo{balances: map[uuid.UUID]*models.GemBalance{userID: {UserId: userID, Gems: 100}}}
-	service := &gemBalancesService{conf: conf, gemBalancesRepo: repo}
-
-	err := service.ensureSufficientBalance(userID, 50)
-	assert.NoError(t, err)
-}
-
 func TestGemBalancesService_GetTodayDate_ValidTimezone(t *testing.T) {
 	conf := config.CreateConfig()
 	conf.Stripe.StripeBillingTimezone = "UTC"
@@ -561,49 +853,35 @@ func TestGemBalancesService_GetTodayDate_ValidTimezone(t *testing.T) {
 
 func TestGemBalancesService_FetchCosmeticPrice_HTTPError(t *testing.T) {
 	conf := config.CreateConfig()
-	conf.Server.Port = 1
-	service := &gemBalancesService{conf: conf}
+	assets := &fakeAssetsClient{getErr: service_clients.NewServiceUnavailable("failed to reach assets service to fetch cosmetic"), grantErr: service_clients.NewServiceUnavailable("failed to reach assets service to record purchase")}
+	service := &gemBalancesService{conf: conf, assetsClient: assets}
 
-	_, _, err := service.fetchCosmeticPrice(uuid.New())
+	_, err := service.fetchCosmetic(uuid.New())
 	assert.Error(t, err)
 }
 
 func TestGemBalancesService_FetchCosmeticPrice_NonOKNon404(t *testing.T) {
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusInternalServerError)
-	}))
-	defer server.Close()
-
 	conf := config.CreateConfig()
-	portStr := strings.TrimPrefix(server.URL, "http://127.0.0.1:")
-	port, _ := strconv.Atoi(portStr)
-	conf.Server.Port = port
-	service := &gemBalancesService{conf: conf}
+	assets := &fakeAssetsClient{getErr: errors.New("failed to get cosmetic, status code: 500"), grantErr: errors.New("failed to record cosmetic purchase, status code: 500")}
+	service := &gemBalancesService{conf: conf, assetsClient: assets}
 
-	_, _, err := service.fetchCosmeticPrice(uuid.New())
+	_, err := service.fetchCosmetic(uuid.New())
 	assert.Error(t, err)
 }
 
 func TestGemBalancesService_IssueCosmeticPurchase_HTTPError(t *testing.T) {
 	conf := config.CreateConfig()
-	conf.Server.Port = 1
-	service := &gemBalancesService{conf: conf}
+	assets := &fakeAssetsClient{getErr: service_clients.NewServiceUnavailable("failed to reach assets service to fetch cosmetic"), grantErr: service_clients.NewServiceUnavailable("failed to reach assets service to record purchase")}
+	service := &gemBalancesService{conf: conf, assetsClient: assets}
 
 	err := service.issueCosmeticPurchase(uuid.New(), uuid.New())
 	assert.Error(t, err)
 }
 
 func TestGemBalancesService_IssueCosmeticPurchase_BadStatus(t *testing.T) {
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusInternalServerError)
-	}))
-	defer server.Close()
-
 	conf := config.CreateConfig()
-	portStr := strings.TrimPrefix(server.URL, "http://127.0.0.1:")
-	port, _ := strconv.Atoi(portStr)
-	conf.Server.Port = port
-	service := &gemBalancesService{conf: conf}
+	assets := &fakeAssetsClient{getErr: errors.New("failed to get cosmetic, status code: 500"), grantErr: errors.New("failed to record cosmetic purchase, status code: 500")}
+	service := &gemBalancesService{conf: conf, assetsClient: assets}
 
 	err := service.issueCosmeticPurchase(uuid.New(), uuid.New())
 	assert.Error(t, err)
@@ -613,48 +891,25 @@ func TestGemBalancesService_PurchaseCosmetic_NoCreator(t *testing.T) {
 	userID := uuid.New()
 	cosmeticID := uuid.New()
 
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if strings.Contains(r.URL.Path, "/assets/internal/cosmetics/") {
-			payload := map[string]any{
-				"data": map[string]any{
-					"cosmetic_id":    cosmeticID,
-					"cosmetic_price": int64(10),
-					"created_by":     uuid.Nil,
-				},
-			}
-			_ = json.NewEncoder(w).Encode(payload)
-			return
-		}
-		if strings.Contains(r.URL.Path, "/assets/internal/users/") {
-			w.WriteHeader(http.StatusCreated)
-			return
-		}
-		w.WriteHeader(http.StatusNotFound)
-	}))
-	defer server.Close()
-
 	conf := config.CreateConfig()
-	portStr := strings.TrimPrefix(server.URL, "http://127.0.0.1:")
-	port, _ := strconv.Atoi(portStr)
-	conf.Server.Port = port
+	assets := &fakeAssetsClient{cosmetic: &service_clients.CosmeticInfo{CosmeticId: cosmeticID, Price: 10, CreatedBy: uuid.Nil}}
 	conf.Server.CreatorRevenuePercent = 1.0
 	conf.Server.DollarsGemsRatio = 1.0
 
-	gemRepo := &fakeGemBalancesRepo{balances: map[uuid.UUID]*models.GemBalance{userID: {UserId: userID, Gems: 20}}}
+	purchasesRepo := newFakeCosmeticPurchasesRepo(userID, 20)
 	metricsRepo := &fakeGemMetricsRepo{}
-	creatorRepo := &fakeCreatorBalancesRepo{}
 	service := &gemBalancesService{
-		conf:                conf,
-		gemBalancesRepo:     gemRepo,
-		gemMetricsRepo:      metricsRepo,
-		creatorBalancesRepo: creatorRepo,
-		emailSender:         &fakeEmailSender{},
+		conf:                  conf,
+		assetsClient:          assets,
+		gemMetricsRepo:        metricsRepo,
+		cosmeticPurchasesRepo: purchasesRepo,
+		emailSender:           &fakeEmailSender{},
 	}
 
 	err := service.PurchaseCosmetic(userID, cosmeticID)
 	assert.NoError(t, err)
-	assert.False(t, creatorRepo.addCalled)
-	assert.True(t, gemRepo.addCalled)
+	assert.Empty(t, purchasesRepo.creatorEarnings)
+	assert.Equal(t, int64(10), purchasesRepo.balances[userID])
 }
 
 func TestGemBalancesService_PurchaseCosmetic_ZeroPrice(t *testing.T) {
@@ -662,86 +917,20 @@ func TestGemBalancesService_PurchaseCosmetic_ZeroPrice(t *testing.T) {
 	cosmeticID := uuid.New()
 	creatorID := uuid.New()
 
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if strings.Contains(r.URL.Path, "/assets/internal/cosmetics/") {
-			payload := map[string]any{
-				"data": map[string]any{
-					"cosmetic_id":    cosmeticID,
-					"cosmetic_price": int64(0),
-					"created_by":     creatorID,
-				},
-			}
-			_ = json.NewEncoder(w).Encode(payload)
-			return
-		}
-		if strings.Contains(r.URL.Path, "/assets/internal/users/") {
-			w.WriteHeader(http.StatusCreated)
-			return
-		}
-		w.WriteHeader(http.StatusNotFound)
-	}))
-	defer server.Close()
-
 	conf := config.CreateConfig()
-	portStr := strings.TrimPrefix(server.URL, "http://127.0.0.1:")
-	port, _ := strconv.Atoi(portStr)
-	conf.Server.Port = port
+	assets := &fakeAssetsClient{cosmetic: &service_clients.CosmeticInfo{CosmeticId: cosmeticID, Price: 0, CreatedBy: creatorID}}
 
-	gemRepo := &fakeGemBalancesRepo{balances: map[uuid.UUID]*models.GemBalance{userID: {UserId: userID, Gems: 20}}}
-	creatorRepo := &fakeCreatorBalancesRepo{}
+	purchasesRepo := newFakeCosmeticPurchasesRepo(userID, 20)
 	service := &gemBalancesService{
-		conf:                conf,
-		gemBalancesRepo:     gemRepo,
-		creatorBalancesRepo: creatorRepo,
+		conf:                  conf,
+		assetsClient:          assets,
+		cosmeticPurchasesRepo: purchasesRepo,
 	}
 
 	err := service.PurchaseCosmetic(userID, cosmeticID)
 	assert.NoError(t, err)
-	assert.False(t, creatorRepo.addCalled)
-}
-
-func TestGemBalancesService_PurchaseCosmetic_CreatorBalanceError(t *testing.T) {
-	userID := uuid.New()
-	cosmeticID := uuid.New()
-	creatorID := uuid.New()
-
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if strings.Contains(r.URL.Path, "/assets/internal/cosmetics/") {
-			payload := map[string]any{
-				"data": map[string]any{
-					"cosmetic_id":    cosmeticID,
-					"cosmetic_price": int64(10),
-					"created_by":     creatorID,
-				},
-			}
-			_ = json.NewEncoder(w).Encode(payload)
-			return
-		}
-		if strings.Contains(r.URL.Path, "/assets/internal/users/") {
-			w.WriteHeader(http.StatusCreated)
-			return
-		}
-		w.WriteHeader(http.StatusNotFound)
-	}))
-	defer server.Close()
-
-	conf := config.CreateConfig()
-	portStr := strings.TrimPrefix(server.URL, "http://127.0.0.1:")
-	port, _ := strconv.Atoi(portStr)
-	conf.Server.Port = port
-	conf.Server.CreatorRevenuePercent = 1.0
-	conf.Server.DollarsGemsRatio = 1.0
-
-	gemRepo := &fakeGemBalancesRepo{balances: map[uuid.UUID]*models.GemBalance{userID: {UserId: userID, Gems: 20}}}
-	creatorRepo := &fakeCreatorBalancesRepo{addErr: errors.New("boom")}
-	service := &gemBalancesService{
-		conf:                conf,
-		gemBalancesRepo:     gemRepo,
-		creatorBalancesRepo: creatorRepo,
-	}
-
-	err := service.PurchaseCosmetic(userID, cosmeticID)
-	assert.Error(t, err)
+	assert.Empty(t, purchasesRepo.creatorEarnings)
+	assert.Equal(t, int64(20), purchasesRepo.balances[userID])
 }
 
 func TestGemBalancesService_PurchaseCosmetic_MetricsError(t *testing.T) {
@@ -749,41 +938,18 @@ func TestGemBalancesService_PurchaseCosmetic_MetricsError(t *testing.T) {
 	cosmeticID := uuid.New()
 	creatorID := uuid.New()
 
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if strings.Contains(r.URL.Path, "/assets/internal/cosmetics/") {
-			payload := map[string]any{
-				"data": map[string]any{
-					"cosmetic_id":    cosmeticID,
-					"cosmetic_price": int64(10),
-					"created_by":     creatorID,
-				},
-			}
-			_ = json.NewEncoder(w).Encode(payload)
-			return
-		}
-		if strings.Contains(r.URL.Path, "/assets/internal/users/") {
-			w.WriteHeader(http.StatusCreated)
-			return
-		}
-		w.WriteHeader(http.StatusNotFound)
-	}))
-	defer server.Close()
-
 	conf := config.CreateConfig()
-	portStr := strings.TrimPrefix(server.URL, "http://127.0.0.1:")
-	port, _ := strconv.Atoi(portStr)
-	conf.Server.Port = port
+	assets := &fakeAssetsClient{cosmetic: &service_clients.CosmeticInfo{CosmeticId: cosmeticID, Price: 10, CreatedBy: creatorID}}
 	conf.Server.CreatorRevenuePercent = 1.0
 	conf.Server.DollarsGemsRatio = 1.0
 
-	gemRepo := &fakeGemBalancesRepo{balances: map[uuid.UUID]*models.GemBalance{userID: {UserId: userID, Gems: 20}}}
 	metricsRepo := &fakeGemMetricsRepo{spentErr: errors.New("metrics error")}
 	service := &gemBalancesService{
-		conf:                conf,
-		gemBalancesRepo:     gemRepo,
-		gemMetricsRepo:      metricsRepo,
-		creatorBalancesRepo: &fakeCreatorBalancesRepo{},
-		emailSender:         &fakeEmailSender{},
+		conf:                  conf,
+		assetsClient:          assets,
+		gemMetricsRepo:        metricsRepo,
+		cosmeticPurchasesRepo: newFakeCosmeticPurchasesRepo(userID, 20),
+		emailSender:           &fakeEmailSender{},
 	}
 
 	err := service.PurchaseCosmetic(userID, cosmeticID)
@@ -1300,3 +1466,370 @@ func TestGemBalancesService_HandleWebhook_DefaultEvent(t *testing.T) {
 	err := service.HandleWebhook(payload, sig)
 	assert.NoError(t, err)
 }
+
+type fakeGemTransactionsRepo struct {
+	transactions []*models.GemTransaction
+	lastFilter   gem_transactions_repo.GemTransactionsFilter
+	err          error
+}
+
+func (f *fakeGemTransactionsRepo) GetTransactionsByUserId(userId uuid.UUID, filter gem_transactions_repo.GemTransactionsFilter, offset int, limit int) ([]*models.GemTransaction, int64, error) {
+	if f.err != nil {
+		return nil, 0, f.err
+	}
+	f.lastFilter = filter
+	return f.transactions, int64(len(f.transactions)), nil
+}
+
+func (f *fakeGemTransactionsRepo) GetTransactionsByReference(referenceId string) ([]*models.GemTransaction, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	var list []*models.GemTransaction
+	for _, transaction := range f.transactions {
+		if transaction.ReferenceID == referenceId {
+			list = append(list, transaction)
+		}
+	}
+	return list, nil
+}
+
+func (f *fakeGemTransactionsRepo) GetLedgerBalance(userId uuid.UUID) (int64, error) {
+	if f.err != nil {
+		return 0, f.err
+	}
+	var total int64
+	for _, transaction := range f.transactions {
+		total += transaction.Amount
+	}
+	return total, nil
+}
+
+func TestGemBalancesService_GetGemTransactions(t *testing.T) {
+	userID := uuid.New()
+	repo := &fakeGemTransactionsRepo{transactions: []*models.GemTransaction{
+		{UserID: userID, Type: models.GEM_TRANSACTION_STRIPE_CREDIT, Amount: 50},
+		{UserID: userID, Type: models.GEM_TRANSACTION_PURCHASE, Amount: -20},
+	}}
+	service := &gemBalancesService{conf: config.CreateConfig(), gemTransactionsRepo: repo}
+
+	filter := gem_transactions_repo.GemTransactionsFilter{Type: models.GEM_TRANSACTION_PURCHASE}
+	transactions, total, err := service.GetGemTransactions(userID, filter, 0, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Len(t, transactions, 2)
+	assert.Equal(t, filter, repo.lastFilter)
+
+	ledgerBalance, err := service.GetLedgerBalance(userID)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(30), ledgerBalance)
+}
+
+func TestGemBalancesService_GetGemTransactions_Error(t *testing.T) {
+	service := &gemBalancesService{conf: config.CreateConfig(), gemTransactionsRepo: &fakeGemTransactionsRepo{err: errors.New("boom")}}
+
+	transactions, _, err := service.GetGemTransactions(uuid.New(), gem_transactions_repo.GemTransactionsFilter{}, 0, 10)
+	assert.Error(t, err)
+	assert.Nil(t, transactions)
+
+	_, err = service.GetLedgerBalance(uuid.New())
+	assert.Error(t, err)
+}
+
+func buildStripeEventPayloadWithID(eventID string, eventType string, obj interface{}) []byte {
+	objBytes, _ := json.Marshal(obj)
+	payload := stripeEventPayload{
+		ID:         eventID,
+		Object:     "event",
+		Type:       eventType,
+		APIVersion: "2026-04-22.dahlia",
+	}
+	payload.Data.Object = objBytes
+	bytes, _ := json.Marshal(payload)
+	return bytes
+}
+
+func newReversalTestService(secret string, userID uuid.UUID, balance int64) (*gemBalancesService, *fakeGemBalancesRepo, *fakeGemTransactionsRepo, *fakeGemMetricsRepo, *fakeEmailSender) {
+	balancesRepo := &fakeGemBalancesRepo{balances: map[uuid.UUID]*models.GemBalance{userID: {UserId: userID, Gems: balance}}}
+	transactionsRepo := &fakeGemTransactionsRepo{transactions: []*models.GemTransaction{
+		{UserID: userID, Type: models.GEM_TRANSACTION_STRIPE_CREDIT, Amount: 100, ReferenceID: "pi_reversal"},
+	}}
+	metricsRepo := &fakeGemMetricsRepo{}
+	emailSender := &fakeEmailSender{}
+
+	service := &gemBalancesService{
+		conf:                webhookConf(secret),
+		gemBalancesRepo:     balancesRepo,
+		gemTransactionsRepo: transactionsRepo,
+		gemMetricsRepo:      metricsRepo,
+		emailSender:         emailSender,
+	}
+	return service, balancesRepo, transactionsRepo, metricsRepo, emailSender
+}
+
+func sendStripeEvent(t *testing.T, service *gemBalancesService, secret string, eventID string, eventType string, obj interface{}) {
+	t.Helper()
+	payload := buildStripeEventPayloadWithID(eventID, eventType, obj)
+	assert.NoError(t, service.HandleWebhook(payload, generateStripeSignature(secret, payload)))
+}
+
+func refundedCharge(amountRefunded int64) map[string]interface{} {
+	return map[string]interface{}{
+		"id":              "ch_reversal",
+		"object":          "charge",
+		"amount":          1000,
+		"amount_refunded": amountRefunded,
+		"payment_intent":  "pi_reversal",
+		"metadata":        map[string]string{"email": "test@example.com"},
+	}
+}
+
+func TestGemBalancesService_HandleWebhook_ChargeRefunded_ClawsBackSpentGems(t *testing.T) {
+	secret := "whsec_test_secret"
+	userID := uuid.New()
+	service, balancesRepo, _, metricsRepo, emailSender := newReversalTestService(secret, userID, 30)
+
+	sendStripeEvent(t, service, secret, "evt_refund", "charge.refunded", refundedCharge(1000))
+
+	assert.Equal(t, int64(-70), balancesRepo.balances[userID].Gems)
+	if assert.Len(t, balancesRepo.adjustments, 1) {
+		assert.Equal(t, models.GEM_TRANSACTION_STRIPE_REFUND, balancesRepo.adjustments[0].Type)
+		assert.Equal(t, "pi_reversal", balancesRepo.adjustments[0].ReferenceID)
+	}
+	assert.Equal(t, int64(-100), metricsRepo.boughtGems)
+	assert.InDelta(t, -10.0, metricsRepo.boughtRevenue, 0.001)
+	if assert.Len(t, emailSender.gemReversalEmails, 1) {
+		assert.Equal(t, "test@example.com", emailSender.gemReversalEmails[0].ToEmail)
+		assert.Equal(t, int64(100), emailSender.gemReversalEmails[0].GemAmount)
+		assert.Equal(t, int64(-70), emailSender.gemReversalEmails[0].TotalGems)
+	}
+
+	// Stripe retries the same event, it must not be applied twice
+	sendStripeEvent(t, service, secret, "evt_refund", "charge.refunded", refundedCharge(1000))
+	assert.Equal(t, int64(-70), balancesRepo.balances[userID].Gems)
+	assert.Len(t, balancesRepo.adjustments, 1)
+}
+
+func TestGemBalancesService_HandleWebhook_ChargeRefunded_Partial(t *testing.T) {
+	secret := "whsec_test_secret"
+	userID := uuid.New()
+	service, balancesRepo, transactionsRepo, _, _ := newReversalTestService(secret, userID, 100)
+
+	sendStripeEvent(t, service, secret, "evt_refund_1", "charge.refunded", refundedCharge(250))
+	assert.Equal(t, int64(75), balancesRepo.balances[userID].Gems)
+	transactionsRepo.transactions = append(transactionsRepo.transactions, balancesRepo.adjustments...)
+
+	// Stripe reports the cumulative refunded amount
+	sendStripeEvent(t, service, secret, "evt_refund_2", "charge.refunded", refundedCharge(1000))
+	assert.Equal(t, int64(0), balancesRepo.balances[userID].Gems)
+	assert.Len(t, balancesRepo.adjustments, 2)
+}
+
+func TestGemBalancesService_HandleWebhook_ChargeRefunded_UnknownPaymentIntent(t *testing.T) {
+	secret := "whsec_test_secret"
+	userID := uuid.New()
+	service, balancesRepo, _, metricsRepo, _ := newReversalTestService(secret, userID, 100)
+
+	charge := refundedCharge(1000)
+	charge["payment_intent"] = "pi_unrelated"
+	sendStripeEvent(t, service, secret, "evt_refund", "charge.refunded", charge)
+
+	assert.Equal(t, int64(100), balancesRepo.balances[userID].Gems)
+	assert.Empty(t, balancesRepo.adjustments)
+	assert.False(t, metricsRepo.boughtCalled)
+}
+
+func TestGemBalancesService_HandleWebhook_ChargeRefunded_LookupError(t *testing.T) {
+	secret := "whsec_test_secret"
+	userID := uuid.New()
+	service, _, transactionsRepo, _, _ := newReversalTestService(secret, userID, 100)
+	transactionsRepo.err = errors.New("boom")
+
+	payload := buildStripeEventPayloadWithID("evt_refund", "charge.refunded", refundedCharge(1000))
+	assert.Error(t, service.HandleWebhook(payload, generateStripeSignature(secret, payload)))
+}
+
+func dispute(status string) map[string]interface{} {
+	return map[string]interface{}{
+		"id":             "dp_reversal",
+		"object":         "dispute",
+		"amount":         1000,
+		"status":         status,
+		"payment_intent": "pi_reversal",
+		"charge": map[string]interface{}{
+			"id":       "ch_reversal",
+			"object":   "charge",
+			"metadata": map[string]string{"email": "test@example.com"},
+		},
+	}
+}
+
+func TestGemBalancesService_HandleWebhook_DisputeWon(t *testing.T) {
+	secret := "whsec_test_secret"
+	userID := uuid.New()
+	service, balancesRepo, transactionsRepo, metricsRepo, emailSender := newReversalTestService(secret, userID, 40)
+
+	sendStripeEvent(t, service, secret, "evt_dispute_created", "charge.dispute.created", dispute("needs_response"))
+	assert.Equal(t, int64(-60), balancesRepo.balances[userID].Gems)
+	assert.True(t, balancesRepo.balances[userID].Locked)
+	assert.Len(t, emailSender.gemReversalEmails, 1)
+	transactionsRepo.transactions = append(transactionsRepo.transactions, balancesRepo.adjustments...)
+
+	sendStripeEvent(t, service, secret, "evt_dispute_closed", "charge.dispute.closed", dispute("won"))
+	assert.Equal(t, int64(40), balancesRepo.balances[userID].Gems)
+	assert.False(t, balancesRepo.balances[userID].Locked)
+	if assert.Len(t, balancesRepo.adjustments, 2) {
+		assert.Equal(t, models.GEM_TRANSACTION_CHARGEBACK_REVERSAL, balancesRepo.adjustments[1].Type)
+	}
+	assert.Equal(t, int64(0), metricsRepo.boughtGems)
+}
+
+func TestGemBalancesService_HandleWebhook_DisputeLost(t *testing.T) {
+	secret := "whsec_test_secret"
+	userID := uuid.New()
+	service, balancesRepo, transactionsRepo, metricsRepo, _ := newReversalTestService(secret, userID, 40)
+
+	sendStripeEvent(t, service, secret, "evt_dispute_created", "charge.dispute.created", dispute("needs_response"))
+	transactionsRepo.transactions = append(transactionsRepo.transactions, balancesRepo.adjustments...)
+
+	sendStripeEvent(t, service, secret, "evt_dispute_closed", "charge.dispute.closed", dispute("lost"))
+	assert.Equal(t, int64(-60), balancesRepo.balances[userID].Gems)
+	assert.False(t, balancesRepo.balances[userID].Locked)
+	assert.Equal(t, int64(-100), metricsRepo.boughtGems)
+}
+
+func TestGemBalancesService_HandleWebhook_DisputeAfterRefund(t *testing.T) {
+	secret := "whsec_test_secret"
+	userID := uuid.New()
+	service, balancesRepo, transactionsRepo, metricsRepo, _ := newReversalTestService(secret, userID, 100)
+
+	sendStripeEvent(t, service, secret, "evt_refund", "charge.refunded", refundedCharge(500))
+	transactionsRepo.transactions = append(transactionsRepo.transactions, balancesRepo.adjustments...)
+
+	// Only the part that was not refunded is taken by the dispute
+	sendStripeEvent(t, service, secret, "evt_dispute_created", "charge.dispute.created", dispute("needs_response"))
+	assert.Equal(t, int64(0), balancesRepo.balances[userID].Gems)
+	assert.Equal(t, int64(-100), metricsRepo.boughtGems)
+	assert.InDelta(t, -10.0, metricsRepo.boughtRevenue, 0.001)
+	transactionsRepo.transactions = append(transactionsRepo.transactions, balancesRepo.adjustments[1:]...)
+
+	// Winning the dispute only restores the revenue of the disputed gems
+	sendStripeEvent(t, service, secret, "evt_dispute_closed", "charge.dispute.closed", dispute("won"))
+	assert.Equal(t, int64(50), balancesRepo.balances[userID].Gems)
+	assert.Equal(t, int64(-50), metricsRepo.boughtGems)
+	assert.InDelta(t, -5.0, metricsRepo.boughtRevenue, 0.001)
+}
+
+func newGiftTestService(purchasesRepo *fakeCosmeticPurchasesRepo, assets *fakeAssetsClient, players *fakePlayersClient, emails *fakeEmailSender) *gemBalancesService {
+	conf := config.CreateConfig()
+	conf.Server.GiftsDailyLimit = 2
+	return &gemBalancesService{
+		conf:                  conf,
+		assetsClient:          assets,
+		gemMetricsRepo:        &fakeGemMetricsRepo{},
+		cosmeticPurchasesRepo: purchasesRepo,
+		emailSender:           emails,
+		playersClient:         players,
+		accountsClient:        &fakeAccountsClient{},
+	}
+}
+
+func TestGemBalancesService_GiftCosmetic_Success(t *testing.T) {
+	userID := uuid.New()
+	recipientID := uuid.New()
+	cosmeticID := uuid.New()
+
+	assets := &fakeAssetsClient{cosmetic: &service_clients.CosmeticInfo{CosmeticId: cosmeticID, Price: 10, CreatedBy: uuid.Nil}}
+	players := &fakePlayersClient{players: map[string]uuid.UUID{"Gifter": userID, "Lucky": recipientID}}
+	emails := &fakeEmailSender{}
+	purchasesRepo := newFakeCosmeticPurchasesRepo(userID, 20)
+	service := newGiftTestService(purchasesRepo, assets, players, emails)
+
+	purchase, err := service.GiftCosmetic(userID, cosmeticID, " Lucky ", "  enjoy!  ")
+	assert.NoError(t, err)
+	assert.Equal(t, &recipientID, purchase.RecipientID)
+	assert.Equal(t, "enjoy!", purchase.GiftMessage)
+	assert.Equal(t, &userID, assets.giftedBy)
+	assert.Equal(t, int64(10), purchasesRepo.balances[userID])
+	assert.Equal(t, []string{models.COSMETIC_PURCHASE_COMPLETED}, purchasesRepo.statuses())
+	if assert.Len(t, emails.giftEmails, 1) {
+		assert.Equal(t, "Gifter", emails.giftEmails[0].GifterName)
+		assert.Equal(t, "enjoy!", emails.giftEmails[0].Message)
+	}
+}
+
+func TestGemBalancesService_GiftCosmetic_ToSelf(t *testing.T) {
+	userID := uuid.New()
+
+	assets := &fakeAssetsClient{cosmetic: &service_clients.CosmeticInfo{Price: 10, CreatedBy: uuid.Nil}}
+	players := &fakePlayersClient{players: map[string]uuid.UUID{"Gifter": userID}}
+	purchasesRepo := newFakeCosmeticPurchasesRepo(userID, 20)
+	service := newGiftTestService(purchasesRepo, assets, players, &fakeEmailSender{})
+
+	_, err := service.GiftCosmetic(userID, uuid.New(), "Gifter", "")
+	_, invalid := err.(*gem_balances_errors.InvalidGift)
+	assert.True(t, invalid)
+	assert.Empty(t, purchasesRepo.purchases)
+}
+
+func TestGemBalancesService_GiftCosmetic_RecipientNotFound(t *testing.T) {
+	userID := uuid.New()
+
+	assets := &fakeAssetsClient{cosmetic: &service_clients.CosmeticInfo{Price: 10, CreatedBy: uuid.Nil}}
+	purchasesRepo := newFakeCosmeticPurchasesRepo(userID, 20)
+	service := newGiftTestService(purchasesRepo, assets, &fakePlayersClient{}, &fakeEmailSender{})
+
+	_, err := service.GiftCosmetic(userID, uuid.New(), "Nobody", "")
+	_, notFound := err.(*gem_balances_errors.RecipientNotFound)
+	assert.True(t, notFound)
+	assert.Equal(t, int64(20), purchasesRepo.balances[userID])
+}
+
+func TestGemBalancesService_GiftCosmetic_MessageTooLong(t *testing.T) {
+	userID := uuid.New()
+
+	purchasesRepo := newFakeCosmeticPurchasesRepo(userID, 20)
+	service := newGiftTestService(purchasesRepo, &fakeAssetsClient{}, &fakePlayersClient{}, &fakeEmailSender{})
+
+	_, err := service.GiftCosmetic(userID, uuid.New(), "Lucky", strings.Repeat("a", models.MAX_GIFT_MESSAGE_LENGTH+1))
+	_, invalid := err.(*gem_balances_errors.InvalidGift)
+	assert.True(t, invalid)
+}
+
+func TestGemBalancesService_GiftCosmetic_DailyLimitReached(t *testing.T) {
+	userID := uuid.New()
+	players := &fakePlayersClient{players: map[string]uuid.UUID{"A": uuid.New(), "B": uuid.New(), "C": uuid.New()}}
+	assets := &fakeAssetsClient{cosmetic: &service_clients.CosmeticInfo{Price: 1, CreatedBy: uuid.Nil}}
+	purchasesRepo := newFakeCosmeticPurchasesRepo(userID, 20)
+	service := newGiftTestService(purchasesRepo, assets, players, &fakeEmailSender{})
+
+	cosmeticID := uuid.New()
+	_, err := service.GiftCosmetic(userID, cosmeticID, "A", "")
+	assert.NoError(t, err)
+	_, err = service.GiftCosmetic(userID, cosmeticID, "B", "")
+	assert.NoError(t, err)
+
+	_, err = service.GiftCosmetic(userID, cosmeticID, "C", "")
+	_, limited := err.(*gem_balances_errors.GiftLimitReached)
+	assert.True(t, limited)
+	assert.Equal(t, int64(18), purchasesRepo.balances[userID])
+}
+
+func TestGemBalancesService_GiftCosmetic_RecipientAlreadyOwnsRefunds(t *testing.T) {
+	userID := uuid.New()
+	players := &fakePlayersClient{players: map[string]uuid.UUID{"Lucky": uuid.New()}}
+	assets := &fakeAssetsClient{
+		cosmetic: &service_clients.CosmeticInfo{Price: 5, CreatedBy: uuid.Nil},
+		grantErr: service_clients.NewCosmeticAlreadyOwned("cosmetic was already purchased by this user"),
+	}
+	purchasesRepo := newFakeCosmeticPurchasesRepo(userID, 20)
+	emails := &fakeEmailSender{}
+	service := newGiftTestService(purchasesRepo, assets, players, emails)
+
+	_, err := service.GiftCosmetic(userID, uuid.New(), "Lucky", "")
+	_, conflict := err.(*gem_balances_errors.CosmeticAlreadyPurchased)
+	assert.True(t, conflict)
+	assert.Equal(t, int64(20), purchasesRepo.balances[userID])
+	assert.Equal(t, []string{models.COSMETIC_PURCHASE_REFUNDED}, purchasesRepo.statuses())
+	assert.Empty(t, emails.giftEmails)
+}
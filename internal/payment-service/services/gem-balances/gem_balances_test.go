@@ -2,12 +2,16 @@ package gem_balances
 
 import (
 	"os"
+	"sync"
 	"testing"
 
 	"github.com/FeedTheRealm-org/core-service/config"
-	creator_balances_repo "github.com/FeedTheRealm-org/core-service/internal/payment-service/repositories/creator-balances"
+	"github.com/FeedTheRealm-org/core-service/internal/payment-service/models"
+	cosmetic_purchases_repo "github.com/FeedTheRealm-org/core-service/internal/payment-service/repositories/cosmetic-purchases"
 	gem_balances_repo "github.com/FeedTheRealm-org/core-service/internal/payment-service/repositories/gem-balances"
 	gem_packs_repo "github.com/FeedTheRealm-org/core-service/internal/payment-service/repositories/gem-packs"
+	gem_transactions_repo "github.com/FeedTheRealm-org/core-service/internal/payment-service/repositories/gem-transactions"
+	"github.com/FeedTheRealm-org/core-service/internal/service_clients"
 	"github.com/FeedTheRealm-org/core-service/internal/utils/logger"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -17,7 +21,8 @@ var gemBalancesConf *config.Config
 var gemBalancesDB *config.DB
 var gemBalancesRepo gem_balances_repo.GemBalancesRepository
 var gemPacksRepo gem_packs_repo.GemPacksRepository
-var creatorBalancesRepo creator_balances_repo.CreatorBalancesRepository
+var cosmeticPurchasesRepo cosmetic_purchases_repo.CosmeticPurchasesRepository
+var gemTransactionsRepo gem_transactions_repo.GemTransactionsRepository
 var gemBalancesSvc *gemBalancesService
 
 func TestMain(m *testing.M) {
@@ -30,12 +35,14 @@ func TestMain(m *testing.M) {
 	}
 	gemBalancesRepo = gem_balances_repo.NewGemBalancesRepository(gemBalancesConf, gemBalancesDB)
 	gemPacksRepo = gem_packs_repo.NewGemPacksRepository(gemBalancesConf, gemBalancesDB)
-	creatorBalancesRepo = creator_balances_repo.NewCreatorBalancesRepository(gemBalancesConf, gemBalancesDB)
+	cosmeticPurchasesRepo = cosmetic_purchases_repo.NewCosmeticPurchasesRepository(gemBalancesConf, gemBalancesDB)
+	gemTransactionsRepo = gem_transactions_repo.NewGemTransactionsRepository(gemBalancesConf, gemBalancesDB)
 	gemBalancesSvc = &gemBalancesService{
-		conf:                gemBalancesConf,
-		gemBalancesRepo:     gemBalancesRepo,
-		packsRepo:           gemPacksRepo,
-		creatorBalancesRepo: creatorBalancesRepo,
+		conf:                  gemBalancesConf,
+		gemBalancesRepo:       gemBalancesRepo,
+		packsRepo:             gemPacksRepo,
+		cosmeticPurchasesRepo: cosmeticPurchasesRepo,
+		gemTransactionsRepo:   gemTransactionsRepo,
 	}
 
 	clearGemBalancesTables()
@@ -83,10 +90,61 @@ func TestGemBalances_Update(t *testing.T) {
 	clearGemBalancesTables()
 	userID := uuid.New()
 
-	err := gemBalancesSvc.UpdateGemBalance(userID, 42)
+	err := gemBalancesSvc.UpdateGemBalance(userID, 42, uuid.New())
 	assert.NoError(t, err)
 
 	balance, err := gemBalancesRepo.GetGemBalanceByUserId(userID)
 	assert.NoError(t, err)
 	assert.Equal(t, int64(42), balance.Gems)
+
+	ledgerBalance, err := gemBalancesSvc.GetLedgerBalance(userID)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), ledgerBalance)
+}
+
+func TestGemBalances_PurchaseCosmetic_ConcurrentNeverOverdraws(t *testing.T) {
+	clearGemBalancesTables()
+	userID := uuid.New()
+	assert.NoError(t, gemBalancesRepo.CreateGemBalance(userID))
+	assert.NoError(t, gemBalancesRepo.AddToGemBalance(&models.GemTransaction{UserID: userID, Type: models.GEM_TRANSACTION_ADMIN_ADJUSTMENT, Amount: 25}))
+
+	service := &gemBalancesService{
+		conf:                  gemBalancesConf,
+		gemBalancesRepo:       gemBalancesRepo,
+		cosmeticPurchasesRepo: cosmeticPurchasesRepo,
+		assetsClient:          &fakeAssetsClient{cosmetic: &service_clients.CosmeticInfo{Price: 10, CreatedBy: uuid.Nil}},
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = service.PurchaseCosmetic(userID, uuid.New())
+		}()
+	}
+	wg.Wait()
+
+	balance, err := gemBalancesRepo.GetGemBalanceByUserId(userID)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), balance.Gems)
+}
+
+func TestGemBalances_GetGemTransactions_FiltersByType(t *testing.T) {
+	clearGemBalancesTables()
+	userID := uuid.New()
+	cosmeticID := uuid.New()
+	assert.NoError(t, gemBalancesSvc.UpdateGemBalance(userID, 40, uuid.New()))
+	assert.NoError(t, cosmeticPurchasesRepo.CreatePendingPurchase(&models.CosmeticPurchase{UserID: userID, CosmeticID: &cosmeticID, Price: 15}))
+
+	transactions, total, err := gemBalancesSvc.GetGemTransactions(userID, gem_transactions_repo.GemTransactionsFilter{Type: models.GEM_TRANSACTION_PURCHASE}, 0, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	if assert.Len(t, transactions, 1) {
+		assert.Equal(t, int64(-15), transactions[0].Amount)
+	}
+
+	ledgerBalance, err := gemBalancesSvc.GetLedgerBalance(userID)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(25), ledgerBalance)
 }
@@ -0,0 +1,112 @@
+package gem_balances
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	gem_balances_errors "github.com/FeedTheRealm-org/core-service/internal/payment-service/errors"
+	"github.com/FeedTheRealm-org/core-service/internal/payment-service/models"
+	"github.com/FeedTheRealm-org/core-service/internal/service_clients"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/email_sender"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/logger"
+	"github.com/google/uuid"
+)
+
+const unknownGifterName = "A fellow adventurer"
+
+func (bs *gemBalancesService) GiftCosmetic(userId uuid.UUID, cosmeticId uuid.UUID, characterName string, message string) (*models.CosmeticPurchase, error) {
+	message = strings.TrimSpace(message)
+	if utf8.RuneCountInString(message) > models.MAX_GIFT_MESSAGE_LENGTH {
+		return nil, gem_balances_errors.NewInvalidGift(fmt.Sprintf("gift message must be at most %d characters", models.MAX_GIFT_MESSAGE_LENGTH))
+	}
+
+	recipient, err := bs.fetchRecipient(characterName)
+	if err != nil {
+		return nil, err
+	}
+	if recipient.UserId == userId {
+		return nil, gem_balances_errors.NewInvalidGift("cannot gift a cosmetic to yourself")
+	}
+
+	cosmetic, err := bs.fetchCosmetic(cosmeticId)
+	if err != nil {
+		return nil, err
+	}
+
+	purchase := bs.newPurchase(userId, cosmetic.Price, cosmetic.CreatedBy, cosmetic.WorldID)
+	purchase.CosmeticID = &cosmeticId
+	purchase.RecipientID = &recipient.UserId
+	purchase.GiftMessage = message
+
+	err = bs.processPurchase(purchase, func() error {
+		return bs.issueCosmeticGift(recipient.UserId, cosmeticId, userId)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Logger.Info(fmt.Sprintf("User %s gifted cosmetic %s to user %s", userId, cosmeticId, recipient.UserId))
+	bs.sendGiftReceivedEmail(purchase)
+
+	return purchase, nil
+}
+
+func (bs *gemBalancesService) fetchRecipient(characterName string) (*service_clients.PlayerInfo, error) {
+	characterName = strings.TrimSpace(characterName)
+	if characterName == "" {
+		return nil, gem_balances_errors.NewInvalidGift("recipient character name is required")
+	}
+
+	recipient, err := bs.playersClient.GetPlayerByCharacterName(characterName)
+	if err != nil {
+		if _, ok := err.(*service_clients.PlayerNotFound); ok {
+			return nil, gem_balances_errors.NewRecipientNotFound("no player found with character name " + characterName)
+		}
+		logger.Logger.Error("Failed to fetch gift recipient: " + err.Error())
+		return nil, err
+	}
+
+	return recipient, nil
+}
+
+func (bs *gemBalancesService) issueCosmeticGift(recipientId uuid.UUID, cosmeticId uuid.UUID, gifterId uuid.UUID) error {
+	err := bs.assetsClient.GiftCosmetic(recipientId, cosmeticId, gifterId)
+	if err != nil {
+		if _, ok := err.(*service_clients.CosmeticAlreadyOwned); ok {
+			return gem_balances_errors.NewCosmeticAlreadyPurchased("the recipient already owns this cosmetic")
+		}
+		logger.Logger.Error("Failed to record cosmetic gift: " + err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// sendGiftReceivedEmail notifies the recipient, the gift is already delivered so failures are only logged.
+func (bs *gemBalancesService) sendGiftReceivedEmail(purchase *models.CosmeticPurchase) {
+	recipientId := *purchase.RecipientID
+	email, err := bs.accountsClient.GetAccountEmail(recipientId)
+	if err != nil {
+		logger.Logger.Warn("No email found to notify user " + recipientId.String() + " about their gift: " + err.Error())
+		return
+	}
+
+	gifterName := unknownGifterName
+	if gifter, err := bs.playersClient.GetPlayer(purchase.UserID); err == nil {
+		gifterName = gifter.CharacterName
+	}
+
+	err = bs.emailSender.SendGiftReceivedEmail(
+		email_sender.GiftReceivedEmailData{
+			BaseEmailData: bs.emailSender.CreateBaseEmailData(email),
+			GifterName:    gifterName,
+			Message:       purchase.GiftMessage,
+			CosmeticID:    purchase.ItemID().String(),
+			GiftDate:      bs.getTodayDate(),
+		},
+	)
+	if err != nil {
+		logger.Logger.Error("Failed to send gift email for user " + recipientId.String() + ": " + err.Error())
+	}
+}
@@ -0,0 +1,228 @@
+package gem_balances
+
+import (
+	"fmt"
+
+	"github.com/stripe/stripe-go/v85"
+	"github.com/stripe/stripe-go/v85/charge"
+
+	"github.com/FeedTheRealm-org/core-service/internal/payment-service/models"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/email_sender"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/logger"
+)
+
+// stripeCreditHistory holds what was credited for a payment intent and how much was already reversed.
+type stripeCreditHistory struct {
+	credit     *models.GemTransaction
+	refunded   int64
+	chargeback int64
+}
+
+// handleChargeRefunded claws back the gems of a refunded payment, proportionally for partial refunds.
+// Stripe reports the cumulative refunded amount, so only the part not yet reversed is taken.
+func (bs *gemBalancesService) handleChargeRefunded(event stripe.Event, charge *stripe.Charge) error {
+	history, err := bs.getStripeCreditHistory(charge.PaymentIntent)
+	if err != nil || history == nil {
+		return err
+	}
+
+	target := history.credit.Amount
+	if charge.Amount > 0 && charge.AmountRefunded < charge.Amount {
+		target = history.credit.Amount * charge.AmountRefunded / charge.Amount
+	}
+
+	// Gems already taken by a dispute are not taken again
+	gems := min(target-history.refunded, history.credit.Amount-history.refunded-history.chargeback)
+	if gems <= 0 {
+		logger.Logger.Info("Nothing left to claw back for refunded payment intent " + charge.PaymentIntent.ID)
+		return nil
+	}
+
+	applied, err := bs.applyStripeReversal(event, history.credit, models.GEM_TRANSACTION_STRIPE_REFUND, -gems, nil)
+	if err != nil || !applied {
+		return err
+	}
+
+	bs.reverseGemMetrics(gems, float64(charge.Amount)/100*float64(gems)/float64(history.credit.Amount))
+	bs.sendGemReversalEmail(chargeEmail(charge), history.credit, gems, "refunded")
+
+	logger.Logger.Info(fmt.Sprintf("Clawed back %d gems from user %s for refunded payment intent %s", gems, history.credit.UserID, charge.PaymentIntent.ID))
+	return nil
+}
+
+// handleDisputeCreated claws back whatever was not refunded yet and locks the balance until the dispute closes.
+// Revenue is reversed in the same proportion as the gems, so earlier partial refunds are not counted twice.
+func (bs *gemBalancesService) handleDisputeCreated(event stripe.Event, dispute *stripe.Dispute) error {
+	history, err := bs.getStripeCreditHistory(dispute.PaymentIntent)
+	if err != nil || history == nil {
+		return err
+	}
+
+	gems := max(history.credit.Amount-history.refunded-history.chargeback, 0)
+
+	locked := true
+	applied, err := bs.applyStripeReversal(event, history.credit, models.GEM_TRANSACTION_CHARGEBACK, -gems, &locked)
+	if err != nil || !applied {
+		return err
+	}
+
+	if gems > 0 {
+		bs.reverseGemMetrics(gems, float64(dispute.Amount)/100*float64(gems)/float64(history.credit.Amount))
+		bs.sendGemReversalEmail(bs.disputeEmail(dispute), history.credit, gems, "disputed")
+	}
+
+	logger.Logger.Info(fmt.Sprintf("Clawed back %d gems and locked balance of user %s for disputed payment intent %s", gems, history.credit.UserID, dispute.PaymentIntent.ID))
+	return nil
+}
+
+// handleDisputeClosed unlocks the balance, giving the clawed back gems back when the dispute was won.
+func (bs *gemBalancesService) handleDisputeClosed(event stripe.Event, dispute *stripe.Dispute) error {
+	history, err := bs.getStripeCreditHistory(dispute.PaymentIntent)
+	if err != nil || history == nil {
+		return err
+	}
+
+	var gems int64
+	if dispute.Status == stripe.DisputeStatusWon || dispute.Status == stripe.DisputeStatusWarningClosed {
+		gems = history.chargeback
+	}
+
+	locked := false
+	applied, err := bs.applyStripeReversal(event, history.credit, models.GEM_TRANSACTION_CHARGEBACK_REVERSAL, gems, &locked)
+	if err != nil || !applied {
+		return err
+	}
+
+	if gems > 0 {
+		if err := bs.gemMetricsRepo.AddGemsBoughtAndRevenue(gems, float64(dispute.Amount)/100*float64(gems)/float64(history.credit.Amount)); err != nil {
+			logger.Logger.Error("Failed to restore gem metrics for user " + history.credit.UserID.String() + ": " + err.Error())
+		}
+	}
+
+	logger.Logger.Info(fmt.Sprintf("Dispute for payment intent %s closed as %s, restored %d gems to user %s", dispute.PaymentIntent.ID, dispute.Status, gems, history.credit.UserID))
+	return nil
+}
+
+// getStripeCreditHistory returns nil when the payment intent never credited gems, such as unrelated charges.
+func (bs *gemBalancesService) getStripeCreditHistory(paymentIntent *stripe.PaymentIntent) (*stripeCreditHistory, error) {
+	if paymentIntent == nil || paymentIntent.ID == "" {
+		logger.Logger.Info("Ignoring Stripe event without a payment intent")
+		return nil, nil
+	}
+
+	transactions, err := bs.gemTransactionsRepo.GetTransactionsByReference(paymentIntent.ID)
+	if err != nil {
+		logger.Logger.Error("Failed to retrieve gem transactions for payment intent " + paymentIntent.ID + ": " + err.Error())
+		return nil, err
+	}
+
+	history := &stripeCreditHistory{}
+	for _, transaction := range transactions {
+		switch transaction.Type {
+		case models.GEM_TRANSACTION_STRIPE_CREDIT:
+			history.credit = transaction
+		case models.GEM_TRANSACTION_STRIPE_REFUND:
+			history.refunded -= transaction.Amount
+		case models.GEM_TRANSACTION_CHARGEBACK:
+			history.chargeback -= transaction.Amount
+		case models.GEM_TRANSACTION_CHARGEBACK_REVERSAL:
+			history.chargeback -= transaction.Amount
+		}
+	}
+
+	if history.credit == nil {
+		logger.Logger.Info("Ignoring Stripe event for payment intent " + paymentIntent.ID + " that did not credit gems")
+		return nil, nil
+	}
+
+	return history, nil
+}
+
+func (bs *gemBalancesService) applyStripeReversal(event stripe.Event, credit *models.GemTransaction, transactionType string, gems int64, locked *bool) (bool, error) {
+	applied, err := bs.gemBalancesRepo.ApplyStripeAdjustmentIfUnprocessed(
+		&models.ProcessedStripeWebhookEvent{
+			EventID:   event.ID,
+			SessionID: credit.ReferenceID,
+			EventType: string(event.Type),
+			UserID:    credit.UserID,
+		},
+		&models.GemTransaction{
+			UserID:      credit.UserID,
+			Type:        transactionType,
+			Amount:      gems,
+			ReferenceID: credit.ReferenceID,
+		},
+		locked,
+	)
+	if err != nil {
+		logger.Logger.Error("Failed to apply Stripe " + string(event.Type) + " for user " + credit.UserID.String() + ": " + err.Error())
+		return false, err
+	}
+
+	if !applied {
+		logger.Logger.Info("Ignoring duplicate Stripe " + string(event.Type) + " event: " + event.ID)
+	}
+
+	return applied, nil
+}
+
+func (bs *gemBalancesService) reverseGemMetrics(gems int64, revenue float64) {
+	if err := bs.gemMetricsRepo.AddGemsBoughtAndRevenue(-gems, -revenue); err != nil {
+		logger.Logger.Error("Failed to reverse gem metrics: " + err.Error())
+	}
+}
+
+func (bs *gemBalancesService) sendGemReversalEmail(email string, credit *models.GemTransaction, gems int64, reason string) {
+	if email == "" {
+		logger.Logger.Warn("No email found to notify user " + credit.UserID.String() + " about reversed gems")
+		return
+	}
+
+	var totalGems int64
+	if balance, err := bs.gemBalancesRepo.GetGemBalanceByUserId(credit.UserID); err == nil {
+		totalGems = balance.Gems
+	}
+
+	err := bs.emailSender.SendGemReversalEmail(
+		email_sender.GemReversalEmailData{
+			BaseEmailData: bs.emailSender.CreateBaseEmailData(email),
+			GemAmount:     gems,
+			TotalGems:     totalGems,
+			Reason:        reason,
+			TransactionID: credit.ReferenceID,
+			ReversalDate:  bs.getTodayDate(),
+		},
+	)
+	if err != nil {
+		logger.Logger.Error("Failed to send gem reversal email for user " + credit.UserID.String() + ": " + err.Error())
+	}
+}
+
+// disputeEmail fetches the disputed charge when the event only carries its ID.
+func (bs *gemBalancesService) disputeEmail(dispute *stripe.Dispute) string {
+	if email := chargeEmail(dispute.Charge); email != "" || dispute.Charge == nil || dispute.Charge.ID == "" {
+		return email
+	}
+
+	disputedCharge, err := charge.Get(dispute.Charge.ID, nil)
+	if err != nil {
+		logger.Logger.Error("Failed to retrieve disputed charge " + dispute.Charge.ID + ": " + err.Error())
+		return ""
+	}
+
+	return chargeEmail(disputedCharge)
+}
+
+// chargeEmail prefers the email stored in the checkout metadata, which Stripe copies to the charge.
+func chargeEmail(charge *stripe.Charge) string {
+	if charge == nil {
+		return ""
+	}
+	if email := charge.Metadata["email"]; email != "" {
+		return email
+	}
+	if charge.BillingDetails != nil && charge.BillingDetails.Email != "" {
+		return charge.BillingDetails.Email
+	}
+	return charge.ReceiptEmail
+}
@@ -2,6 +2,7 @@ package gem_balances
 
 import (
 	"github.com/FeedTheRealm-org/core-service/internal/payment-service/models"
+	gem_transactions "github.com/FeedTheRealm-org/core-service/internal/payment-service/repositories/gem-transactions"
 	"github.com/google/uuid"
 )
 
@@ -15,12 +16,24 @@ type GemBalancesService interface {
 	// CreateBalance creates a new balance record for a user.
 	CreateGemBalance(userId uuid.UUID) error
 
-	// UpdateBalance updates the balance for a specific user.
-	UpdateGemBalance(userId uuid.UUID, gems int64) error
+	// UpdateBalance updates the balance for a specific user, recording the adjustment under the acting admin.
+	UpdateGemBalance(userId uuid.UUID, gems int64, actorId uuid.UUID) error
+
+	// GetGemTransactions retrieves a page of the user's gem ledger and the total count.
+	GetGemTransactions(userId uuid.UUID, filter gem_transactions.GemTransactionsFilter, offset int, limit int) ([]*models.GemTransaction, int64, error)
+
+	// GetLedgerBalance retrieves the balance derived from the user's gem ledger.
+	GetLedgerBalance(userId uuid.UUID) (int64, error)
 
 	// PurchaseCosmetic processes the purchase of a cosmetic item using gems.
 	PurchaseCosmetic(userId uuid.UUID, cosmeticId uuid.UUID) error
 
+	// PurchaseBundle processes the purchase of a bundle using gems and returns the cosmetics granted by it.
+	PurchaseBundle(userId uuid.UUID, bundleId uuid.UUID) ([]uuid.UUID, error)
+
+	// GiftCosmetic charges the user for a cosmetic granted to the player with the given character name and notifies them.
+	GiftCosmetic(userId uuid.UUID, cosmeticId uuid.UUID, characterName string, message string) (*models.CosmeticPurchase, error)
+
 	// CreateCheckoutSession creates a new checkout session for a user.
 	CreateCheckoutSession(userId uuid.UUID, email string, packageId uuid.UUID, successUrl string, cancelUrl string) (string, error)
 
@@ -1,18 +1,112 @@
 package gem_metrics
 
 import (
+	"fmt"
+	"time"
+
+	"github.com/FeedTheRealm-org/core-service/config"
+	payment_errors "github.com/FeedTheRealm-org/core-service/internal/payment-service/errors"
 	"github.com/FeedTheRealm-org/core-service/internal/payment-service/models"
 	gem_metrics "github.com/FeedTheRealm-org/core-service/internal/payment-service/repositories/gem-metrics"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/logger"
+)
+
+const (
+	defaultHistoryDays = 30
+	maxHistoryDays     = 3 * 366
 )
 
 type gemMetricsService struct {
+	conf *config.Config
 	repo gem_metrics.GemMetricsRepository
 }
 
-func NewGemMetricsService(repo gem_metrics.GemMetricsRepository) GemMetricsService {
-	return &gemMetricsService{repo: repo}
+func NewGemMetricsService(conf *config.Config, repo gem_metrics.GemMetricsRepository) GemMetricsService {
+	return &gemMetricsService{conf: conf, repo: repo}
 }
 
 func (s *gemMetricsService) GetMetrics() (*models.GemMetrics, error) {
 	return s.repo.GetMetrics()
 }
+
+func (s *gemMetricsService) GetMetricsHistory(from time.Time, to time.Time, granularity string) ([]*models.GemMetricsBucket, error) {
+	if !models.IsValidMetricsGranularity(granularity) {
+		return nil, payment_errors.NewInvalidMetricsRange("granularity must be day, week or month")
+	}
+
+	if to.IsZero() {
+		to = s.today()
+	}
+	if from.IsZero() {
+		from = to.AddDate(0, 0, -(defaultHistoryDays - 1))
+	}
+	if to.Before(from) {
+		return nil, payment_errors.NewInvalidMetricsRange("from date must not be after to date")
+	}
+	if to.Sub(from) > maxHistoryDays*24*time.Hour {
+		return nil, payment_errors.NewInvalidMetricsRange(fmt.Sprintf("range cannot exceed %d days", maxHistoryDays))
+	}
+
+	buckets, err := s.repo.GetMetricsRange(from, to, granularity)
+	if err != nil {
+		logger.Logger.Error("Failed to retrieve gem metrics history: " + err.Error())
+		return nil, err
+	}
+
+	return fillMetricsBuckets(buckets, from, to, granularity), nil
+}
+
+/* --- UTILS --- */
+
+// today returns the current calendar date in the billing timezone.
+func (s *gemMetricsService) today() time.Time {
+	loc, err := time.LoadLocation(s.conf.Stripe.StripeBillingTimezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	now := time.Now().In(loc)
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// fillMetricsBuckets returns one bucket per period between from and to, keeping the stored totals.
+func fillMetricsBuckets(buckets []*models.GemMetricsBucket, from time.Time, to time.Time, granularity string) []*models.GemMetricsBucket {
+	stored := make(map[string]*models.GemMetricsBucket, len(buckets))
+	for _, bucket := range buckets {
+		stored[bucket.PeriodStart.Format(time.DateOnly)] = bucket
+	}
+
+	filled := []*models.GemMetricsBucket{}
+	for period := periodStart(from, granularity); !period.After(to); period = nextPeriod(period, granularity) {
+		if bucket, ok := stored[period.Format(time.DateOnly)]; ok {
+			filled = append(filled, bucket)
+			continue
+		}
+		filled = append(filled, &models.GemMetricsBucket{PeriodStart: period})
+	}
+
+	return filled
+}
+
+// periodStart truncates a date like Postgres date_trunc does, weeks start on Monday.
+func periodStart(day time.Time, granularity string) time.Time {
+	switch granularity {
+	case models.METRICS_GRANULARITY_WEEK:
+		offset := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -offset)
+	case models.METRICS_GRANULARITY_MONTH:
+		return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return day
+	}
+}
+
+func nextPeriod(period time.Time, granularity string) time.Time {
+	switch granularity {
+	case models.METRICS_GRANULARITY_WEEK:
+		return period.AddDate(0, 0, 7)
+	case models.METRICS_GRANULARITY_MONTH:
+		return period.AddDate(0, 1, 0)
+	default:
+		return period.AddDate(0, 0, 1)
+	}
+}
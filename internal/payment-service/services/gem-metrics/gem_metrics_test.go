@@ -2,15 +2,29 @@ package gem_metrics
 
 import (
 	"errors"
+	"os"
 	"testing"
+	"time"
 
+	"github.com/FeedTheRealm-org/core-service/config"
+	payment_errors "github.com/FeedTheRealm-org/core-service/internal/payment-service/errors"
 	"github.com/FeedTheRealm-org/core-service/internal/payment-service/models"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/logger"
 	"github.com/stretchr/testify/assert"
 )
 
+func TestMain(m *testing.M) {
+	logger.InitLogger(false)
+	os.Exit(m.Run())
+}
+
 type fakeGemMetricsRepo struct {
-	metrics *models.GemMetrics
-	err     error
+	metrics     *models.GemMetrics
+	buckets     []*models.GemMetricsBucket
+	err         error
+	rangeFrom   time.Time
+	rangeTo     time.Time
+	granularity string
 }
 
 func (f *fakeGemMetricsRepo) GetMetrics() (*models.GemMetrics, error) {
@@ -20,6 +34,16 @@ func (f *fakeGemMetricsRepo) GetMetrics() (*models.GemMetrics, error) {
 	return f.metrics, nil
 }
 
+func (f *fakeGemMetricsRepo) GetMetricsRange(from time.Time, to time.Time, granularity string) ([]*models.GemMetricsBucket, error) {
+	f.rangeFrom = from
+	f.rangeTo = to
+	f.granularity = granularity
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.buckets, nil
+}
+
 func (f *fakeGemMetricsRepo) AddGemsBoughtAndRevenue(gems int64, revenue float64) error {
 	return nil
 }
@@ -28,20 +52,93 @@ func (f *fakeGemMetricsRepo) AddGemsSpent(gems int64) error {
 	return nil
 }
 
+func newTestMetricsService(repo *fakeGemMetricsRepo) GemMetricsService {
+	conf := &config.Config{Stripe: &config.StripeConfig{StripeBillingTimezone: "UTC"}}
+	return NewGemMetricsService(conf, repo)
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
 func TestGemMetricsService_GetMetrics(t *testing.T) {
-	repo := &fakeGemMetricsRepo{metrics: &models.GemMetrics{ID: 1, GemsBought: 10}}
-	svc := NewGemMetricsService(repo)
+	repo := &fakeGemMetricsRepo{metrics: &models.GemMetrics{GemsBought: 10}}
+	svc := newTestMetricsService(repo)
 
 	metrics, err := svc.GetMetrics()
 	assert.NoError(t, err)
-	assert.Equal(t, int32(1), metrics.ID)
 	assert.Equal(t, int64(10), metrics.GemsBought)
 }
 
 func TestGemMetricsService_GetMetrics_Error(t *testing.T) {
 	repo := &fakeGemMetricsRepo{err: errors.New("boom")}
-	svc := NewGemMetricsService(repo)
+	svc := newTestMetricsService(repo)
 
 	_, err := svc.GetMetrics()
 	assert.Error(t, err)
 }
+
+func TestGemMetricsService_GetMetricsHistory_FillsMissingDays(t *testing.T) {
+	repo := &fakeGemMetricsRepo{buckets: []*models.GemMetricsBucket{
+		{PeriodStart: date(2026, 3, 3), GemsBought: 10, GemsRevenue: 2.5},
+	}}
+	svc := newTestMetricsService(repo)
+
+	buckets, err := svc.GetMetricsHistory(date(2026, 3, 2), date(2026, 3, 4), models.METRICS_GRANULARITY_DAY)
+	assert.NoError(t, err)
+	if assert.Len(t, buckets, 3) {
+		assert.Equal(t, date(2026, 3, 2), buckets[0].PeriodStart)
+		assert.Equal(t, int64(0), buckets[0].GemsBought)
+		assert.Equal(t, int64(10), buckets[1].GemsBought)
+		assert.Equal(t, date(2026, 3, 4), buckets[2].PeriodStart)
+	}
+}
+
+func TestGemMetricsService_GetMetricsHistory_WeeksStartOnMonday(t *testing.T) {
+	repo := &fakeGemMetricsRepo{}
+	svc := newTestMetricsService(repo)
+
+	// 2026-03-04 is a Wednesday
+	buckets, err := svc.GetMetricsHistory(date(2026, 3, 4), date(2026, 3, 16), models.METRICS_GRANULARITY_WEEK)
+	assert.NoError(t, err)
+	if assert.Len(t, buckets, 3) {
+		assert.Equal(t, date(2026, 3, 2), buckets[0].PeriodStart)
+		assert.Equal(t, date(2026, 3, 16), buckets[2].PeriodStart)
+	}
+	assert.Equal(t, models.METRICS_GRANULARITY_WEEK, repo.granularity)
+}
+
+func TestGemMetricsService_GetMetricsHistory_Months(t *testing.T) {
+	svc := newTestMetricsService(&fakeGemMetricsRepo{})
+
+	buckets, err := svc.GetMetricsHistory(date(2026, 1, 31), date(2026, 3, 1), models.METRICS_GRANULARITY_MONTH)
+	assert.NoError(t, err)
+	if assert.Len(t, buckets, 3) {
+		assert.Equal(t, date(2026, 1, 1), buckets[0].PeriodStart)
+		assert.Equal(t, date(2026, 2, 1), buckets[1].PeriodStart)
+		assert.Equal(t, date(2026, 3, 1), buckets[2].PeriodStart)
+	}
+}
+
+func TestGemMetricsService_GetMetricsHistory_DefaultsToLast30Days(t *testing.T) {
+	repo := &fakeGemMetricsRepo{}
+	svc := newTestMetricsService(repo)
+
+	buckets, err := svc.GetMetricsHistory(time.Time{}, time.Time{}, models.METRICS_GRANULARITY_DAY)
+	assert.NoError(t, err)
+	assert.Len(t, buckets, 30)
+	assert.Equal(t, repo.rangeTo.AddDate(0, 0, -29), repo.rangeFrom)
+}
+
+func TestGemMetricsService_GetMetricsHistory_InvalidRange(t *testing.T) {
+	svc := newTestMetricsService(&fakeGemMetricsRepo{})
+
+	_, err := svc.GetMetricsHistory(date(2026, 3, 4), date(2026, 3, 2), models.METRICS_GRANULARITY_DAY)
+	assert.IsType(t, &payment_errors.InvalidMetricsRange{}, err)
+
+	_, err = svc.GetMetricsHistory(date(2020, 1, 1), date(2026, 1, 1), models.METRICS_GRANULARITY_MONTH)
+	assert.IsType(t, &payment_errors.InvalidMetricsRange{}, err)
+
+	_, err = svc.GetMetricsHistory(date(2026, 1, 1), date(2026, 2, 1), "year")
+	assert.IsType(t, &payment_errors.InvalidMetricsRange{}, err)
+}
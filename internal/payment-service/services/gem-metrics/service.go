@@ -1,7 +1,17 @@
 package gem_metrics
 
-import "github.com/FeedTheRealm-org/core-service/internal/payment-service/models"
+import (
+	"time"
+
+	"github.com/FeedTheRealm-org/core-service/internal/payment-service/models"
+)
 
 type GemMetricsService interface {
+	// GetMetrics retrieves the lifetime gem totals.
 	GetMetrics() (*models.GemMetrics, error)
+
+	// GetMetricsHistory retrieves the gem totals per day, week or month between two calendar dates, both inclusive.
+	// A zero to defaults to today in the billing timezone and a zero from to the 30 days ending at to.
+	// Every period in the range is returned, including those without activity.
+	GetMetricsHistory(from time.Time, to time.Time, granularity string) ([]*models.GemMetricsBucket, error)
 }
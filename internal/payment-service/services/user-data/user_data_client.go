@@ -0,0 +1,37 @@
+package user_data
+
+import (
+	"encoding/json"
+
+	user_data_repo "github.com/FeedTheRealm-org/core-service/internal/payment-service/repositories/user-data"
+	zones_subscriptions_service "github.com/FeedTheRealm-org/core-service/internal/payment-service/services/zones-subscriptions"
+	"github.com/FeedTheRealm-org/core-service/internal/service_clients"
+	"github.com/google/uuid"
+)
+
+type userDataClient struct {
+	repo                user_data_repo.UserDataRepository
+	subscriptionService zones_subscriptions_service.SubscriptionService
+}
+
+// NewUserDataClient exposes the export and erasure of the payment data of a user to the authentication-service.
+func NewUserDataClient(repo user_data_repo.UserDataRepository, subscriptionService zones_subscriptions_service.SubscriptionService) service_clients.UserDataClient {
+	return &userDataClient{repo: repo, subscriptionService: subscriptionService}
+}
+
+func (c *userDataClient) ExportUserData(userId uuid.UUID) (json.RawMessage, error) {
+	data, err := c.repo.GetUserData(userId)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(data)
+}
+
+// EraseUserData ends the Stripe subscription right away before dropping it, the grace period
+// already let the scheduled cancellation run for most users.
+func (c *userDataClient) EraseUserData(userId uuid.UUID) error {
+	if err := c.subscriptionService.EndSubscription(userId); err != nil {
+		return err
+	}
+	return c.repo.DeleteUserData(userId)
+}
@@ -0,0 +1,73 @@
+package user_data
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/FeedTheRealm-org/core-service/internal/payment-service/models"
+	zones_subscriptions_service "github.com/FeedTheRealm-org/core-service/internal/payment-service/services/zones-subscriptions"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeUserDataRepo struct {
+	data    *models.UserData
+	deleted []uuid.UUID
+}
+
+func (f *fakeUserDataRepo) GetUserData(userId uuid.UUID) (*models.UserData, error) {
+	return f.data, nil
+}
+
+func (f *fakeUserDataRepo) DeleteUserData(userId uuid.UUID) error {
+	f.deleted = append(f.deleted, userId)
+	return nil
+}
+
+// fakeSubscriptionService only implements the calls made by the user data client.
+type fakeSubscriptionService struct {
+	zones_subscriptions_service.SubscriptionService
+	endErr error
+	ended  []uuid.UUID
+}
+
+func (f *fakeSubscriptionService) EndSubscription(userId uuid.UUID) error {
+	if f.endErr != nil {
+		return f.endErr
+	}
+	f.ended = append(f.ended, userId)
+	return nil
+}
+
+func TestUserDataClient_ExportUserData(t *testing.T) {
+	userID := uuid.New()
+	repo := &fakeUserDataRepo{data: &models.UserData{GemBalance: &models.GemBalance{UserId: userID, Gems: 7}}}
+	client := NewUserDataClient(repo, &fakeSubscriptionService{})
+
+	raw, err := client.ExportUserData(userID)
+	require.NoError(t, err)
+
+	var exported map[string]any
+	require.NoError(t, json.Unmarshal(raw, &exported))
+	assert.Equal(t, float64(7), exported["gem_balance"].(map[string]any)["gems"])
+}
+
+func TestUserDataClient_EraseUserData(t *testing.T) {
+	userID := uuid.New()
+	repo := &fakeUserDataRepo{}
+	subscriptions := &fakeSubscriptionService{}
+
+	require.NoError(t, NewUserDataClient(repo, subscriptions).EraseUserData(userID))
+	assert.Equal(t, []uuid.UUID{userID}, subscriptions.ended)
+	assert.Equal(t, []uuid.UUID{userID}, repo.deleted)
+}
+
+func TestUserDataClient_EraseUserData_KeepsDataWhenStripeFails(t *testing.T) {
+	repo := &fakeUserDataRepo{}
+	subscriptions := &fakeSubscriptionService{endErr: errors.New("stripe down")}
+
+	assert.Error(t, NewUserDataClient(repo, subscriptions).EraseUserData(uuid.New()))
+	assert.Empty(t, repo.deleted)
+}
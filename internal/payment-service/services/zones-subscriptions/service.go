@@ -17,6 +17,9 @@ type SubscriptionService interface {
 	CreateCheckoutSession(userID uuid.UUID, email string, slots int, successUrl string, cancelUrl string) (string, error)
 	CancelSubscription(userID uuid.UUID) (*models.ZonesSubscriptions, error)
 	ReactivateSubscription(userID uuid.UUID) (*models.ZonesSubscriptions, error)
+	CancelActiveSubscription(userID uuid.UUID) error
+	EndSubscription(userID uuid.UUID) error
+	UpdateCustomerEmail(userID uuid.UUID, email string) error
 	AdminCreateSubscription(userID uuid.UUID, email string, slots int) (*models.ZonesSubscriptions, error)
 	AdminUpdateSlots(userID uuid.UUID, newSlots int) (*models.ZonesSubscriptions, error)
 	AdminCancelSubscription(userID uuid.UUID) (*models.ZonesSubscriptions, error)
@@ -0,0 +1,43 @@
+package zones_subscriptions
+
+import (
+	"github.com/FeedTheRealm-org/core-service/internal/errors"
+	"github.com/FeedTheRealm-org/core-service/internal/service_clients"
+	"github.com/google/uuid"
+)
+
+type subscriptionsClient struct {
+	subscriptionService SubscriptionService
+}
+
+// NewSubscriptionsClient exposes the subscription service to the other services running in this process.
+func NewSubscriptionsClient(subscriptionService SubscriptionService) service_clients.SubscriptionsClient {
+	return &subscriptionsClient{subscriptionService: subscriptionService}
+}
+
+func (c *subscriptionsClient) CheckAvailability(userId uuid.UUID) (*service_clients.SlotsAvailability, error) {
+	allowed, freeSlots, err := c.subscriptionService.CheckAvalibility(userId)
+	if err != nil {
+		if errors.IsRecordNotFound(err) {
+			return nil, service_clients.NewSubscriptionNotFound("user does not have an active subscription")
+		}
+		return nil, err
+	}
+
+	return &service_clients.SlotsAvailability{
+		Allowed:   allowed,
+		FreeSlots: freeSlots,
+	}, nil
+}
+
+func (c *subscriptionsClient) UpdateUsedSlots(userId uuid.UUID, slots int, areUsed bool) error {
+	return c.subscriptionService.UpdateUsedSlots(userId, slots, areUsed)
+}
+
+func (c *subscriptionsClient) CancelSubscription(userId uuid.UUID) error {
+	return c.subscriptionService.CancelActiveSubscription(userId)
+}
+
+func (c *subscriptionsClient) UpdateCustomerEmail(userId uuid.UUID, email string) error {
+	return c.subscriptionService.UpdateCustomerEmail(userId, email)
+}
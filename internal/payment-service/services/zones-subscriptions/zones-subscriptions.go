@@ -6,12 +6,13 @@ import (
 	"errors"
 	"fmt"
 	"math"
-	"net/http"
 	"time"
 
 	"github.com/FeedTheRealm-org/core-service/config"
+	custom_errors "github.com/FeedTheRealm-org/core-service/internal/errors"
 	"github.com/FeedTheRealm-org/core-service/internal/payment-service/models"
 	zones_subscriptions "github.com/FeedTheRealm-org/core-service/internal/payment-service/repositories/zones-subscriptions"
+	"github.com/FeedTheRealm-org/core-service/internal/service_clients"
 	"github.com/FeedTheRealm-org/core-service/internal/utils/email_sender"
 	"github.com/FeedTheRealm-org/core-service/internal/utils/logger"
 	"github.com/google/uuid"
@@ -33,18 +34,19 @@ func (e *CannotExceedTotalSlotsError) Error() string {
 }
 
 type zoneSubscriptionService struct {
-	conf        *config.Config
-	repo        zones_subscriptions.ZonesSubscriptionsRepository
-	emailSender email_sender.EmailSenderService
+	conf            *config.Config
+	repo            zones_subscriptions.ZonesSubscriptionsRepository
+	emailSender     email_sender.EmailSenderService
+	worldJobsClient service_clients.WorldJobsClient
 }
 
 const DATE_FORMAT = "2006-01-02 15:04:05 MST"
 const MIN_PRORATED_AMOUNT_CENTS = 60
 const StatusPendingCancellation = "pending_cancellation"
 
-func NewSubscriptionService(conf *config.Config, repo zones_subscriptions.ZonesSubscriptionsRepository, emailSender email_sender.EmailSenderService) SubscriptionService {
+func NewSubscriptionService(conf *config.Config, repo zones_subscriptions.ZonesSubscriptionsRepository, emailSender email_sender.EmailSenderService, worldJobsClient service_clients.WorldJobsClient) SubscriptionService {
 	stripe.Key = conf.Stripe.StripeApiKey
-	return &zoneSubscriptionService{conf: conf, repo: repo, emailSender: emailSender}
+	return &zoneSubscriptionService{conf: conf, repo: repo, emailSender: emailSender, worldJobsClient: worldJobsClient}
 }
 
 func (zs *zoneSubscriptionService) calculateProratedAmount(slots int) int64 {
@@ -320,6 +322,80 @@ func (zs *zoneSubscriptionService) CancelSubscription(userID uuid.UUID) (*models
 	return sub, nil
 }
 
+// CancelActiveSubscription schedules the cancellation of a paid subscription when its owner deletes the account,
+// users without one or with a comp subscription are left as they are.
+func (zs *zoneSubscriptionService) CancelActiveSubscription(userID uuid.UUID) error {
+	sub, err := zs.repo.GetByUserID(userID)
+	if err != nil {
+		if custom_errors.IsRecordNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	if sub.StripeSubscriptionID == "" || sub.Status != stripe.SubscriptionStatusActive {
+		return nil
+	}
+
+	_, err = zs.CancelSubscription(userID)
+	return err
+}
+
+// EndSubscription cancels the Stripe subscription of the user right away, once the account is erased.
+func (zs *zoneSubscriptionService) EndSubscription(userID uuid.UUID) error {
+	sub, err := zs.repo.GetByUserID(userID)
+	if err != nil {
+		if custom_errors.IsRecordNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	if sub.StripeSubscriptionID == "" || sub.Status == stripe.SubscriptionStatusCanceled {
+		return nil
+	}
+
+	_, err = subscription.Cancel(sub.StripeSubscriptionID, &stripe.SubscriptionCancelParams{
+		InvoiceNow: stripe.Bool(false),
+		Prorate:    stripe.Bool(false),
+	})
+	if err != nil {
+		var stripeErr *stripe.Error
+		if errors.As(err, &stripeErr) && stripeErr.HTTPStatusCode == 404 {
+			logger.Logger.Infof("Stripe subscription %s not found, skipping cancel: %v", sub.StripeSubscriptionID, err)
+			return nil
+		}
+		logger.Logger.Errorf("Failed to cancel Stripe subscription %s for user %s: %v", sub.StripeSubscriptionID, userID, err)
+		return err
+	}
+
+	logger.Logger.Infof("Stripe subscription %s of user %s cancelled", sub.StripeSubscriptionID, userID)
+	return nil
+}
+
+// UpdateCustomerEmail changes the email of the user's Stripe customer so invoices reach the new address.
+func (zs *zoneSubscriptionService) UpdateCustomerEmail(userID uuid.UUID, email string) error {
+	sub, err := zs.repo.GetByUserID(userID)
+	if err != nil {
+		if custom_errors.IsRecordNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	if sub.StripeCustomerID == "" {
+		return nil
+	}
+
+	if _, err := customer.Update(sub.StripeCustomerID, &stripe.CustomerParams{Email: stripe.String(email)}); err != nil {
+		logger.Logger.Errorf("Failed to update email of Stripe customer %s for user %s: %v", sub.StripeCustomerID, userID, err)
+		return err
+	}
+
+	logger.Logger.Infof("Stripe customer %s of user %s updated with the new email", sub.StripeCustomerID, userID)
+	return nil
+}
+
 func (zs *zoneSubscriptionService) ReactivateSubscription(userID uuid.UUID) (*models.ZonesSubscriptions, error) {
 	sub, err := zs.repo.GetByUserID(userID)
 	if err != nil {
@@ -357,20 +433,10 @@ func (zs *zoneSubscriptionService) stopAllJobs(sub *models.ZonesSubscriptions) e
 	if sub.UsedSlots != 0 {
 		logger.Logger.Warnf("User %s has %d used slots during stopAllJobs, resetting to 0", sub.UserID, sub.UsedSlots)
 
-		url := fmt.Sprintf("http://127.0.0.1:%d/world/internal/users/%s/stop-jobs", zs.conf.Server.Port, sub.UserID)
-		resp, err := http.Get(url)
-		if err != nil {
-			logger.Logger.Errorf("Failed to send stop-jobs internal request for user %s: %v", sub.UserID, err)
+		if err := zs.worldJobsClient.StopAllJobsForUser(sub.UserID); err != nil {
+			logger.Logger.Errorf("Failed to stop jobs for user %s: %v", sub.UserID, err)
 			return err
 		}
-		defer func() {
-			_ = resp.Body.Close()
-		}()
-
-		if resp.StatusCode != http.StatusOK {
-			logger.Logger.Errorf("Received non-OK status %d from stop-jobs internal request for user %s", resp.StatusCode, sub.UserID)
-			return fmt.Errorf("failed to stop jobs for user %s, status code: %d", sub.UserID, resp.StatusCode)
-		}
 
 		sub.UsedSlots = 0
 		if _, err := zs.repo.Update(sub); err != nil {
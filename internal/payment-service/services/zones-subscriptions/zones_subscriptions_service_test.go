@@ -9,7 +9,6 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
-	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -21,8 +20,19 @@ import (
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stripe/stripe-go/v85"
+	"gorm.io/gorm"
 )
 
+type fakeWorldJobsClient struct {
+	stopErr   error
+	stopCalls []uuid.UUID
+}
+
+func (f *fakeWorldJobsClient) StopAllJobsForUser(userId uuid.UUID) error {
+	f.stopCalls = append(f.stopCalls, userId)
+	return f.stopErr
+}
+
 type fakeZonesRepo struct {
 	getByUserID      *models.ZonesSubscriptions
 	getByUserErr     error
@@ -82,6 +92,22 @@ func (f *fakeZonesEmailSender) SendPasswordResetEmail(data email_sender.Password
 	return nil
 }
 
+func (f *fakeZonesEmailSender) SendAccountLockedEmail(data email_sender.AccountLockedEmailData) error {
+	return nil
+}
+
+func (f *fakeZonesEmailSender) SendAccountDeletionEmail(data email_sender.AccountDeletionEmailData) error {
+	return nil
+}
+
+func (f *fakeZonesEmailSender) SendEmailChangeVerificationEmail(data email_sender.EmailChangeVerificationEmailData) error {
+	return nil
+}
+
+func (f *fakeZonesEmailSender) SendEmailChangeNoticeEmail(data email_sender.EmailChangeNoticeEmailData) error {
+	return nil
+}
+
 func (f *fakeZonesEmailSender) SendVerificationEmail(data email_sender.VerificationEmailData) error {
 	return nil
 }
@@ -94,6 +120,10 @@ func (f *fakeZonesEmailSender) SendGemPurchaseFailedEmail(data email_sender.GemP
 	return nil
 }
 
+func (f *fakeZonesEmailSender) SendGemReversalEmail(data email_sender.GemReversalEmailData) error {
+	return nil
+}
+
 func (f *fakeZonesEmailSender) SendSubscriptionStartedEmail(data email_sender.SubscriptionStartedData) error {
 	f.sendStartedCalled = true
 	return nil
@@ -117,10 +147,14 @@ func (f *fakeZonesEmailSender) SendSubscriptionCancelledEmail(data email_sender.
 	return nil
 }
 
+func (f *fakeZonesEmailSender) SendGiftReceivedEmail(data email_sender.GiftReceivedEmailData) error {
+	return nil
+}
+
 func TestSubscriptionService_CreateCheckoutSession_ActiveSubscription(t *testing.T) {
 	conf := config.CreateConfig()
 	repo := &fakeZonesRepo{getByUserID: &models.ZonesSubscriptions{Status: stripe.SubscriptionStatusActive}}
-	service := NewSubscriptionService(conf, repo, &fakeZonesEmailSender{})
+	service := NewSubscriptionService(conf, repo, &fakeZonesEmailSender{}, &fakeWorldJobsClient{})
 
 	url, err := service.CreateCheckoutSession(uuid.New(), "user@example.com", 2, "ok", "cancel")
 	assert.Error(t, err)
@@ -130,7 +164,7 @@ func TestSubscriptionService_CreateCheckoutSession_ActiveSubscription(t *testing
 func TestSubscriptionService_EnsureCustomer_ReturnsExisting(t *testing.T) {
 	conf := config.CreateConfig()
 	repo := &fakeZonesRepo{}
-	service := NewSubscriptionService(conf, repo, &fakeZonesEmailSender{}).(*zoneSubscriptionService)
+	service := NewSubscriptionService(conf, repo, &fakeZonesEmailSender{}, &fakeWorldJobsClient{}).(*zoneSubscriptionService)
 
 	existing := &models.ZonesSubscriptions{StripeCustomerID: "cust_123", TotalSlots: 2}
 	result, err := service.ensureCustomer(uuid.New(), "user@example.com", 2, existing)
@@ -139,6 +173,23 @@ func TestSubscriptionService_EnsureCustomer_ReturnsExisting(t *testing.T) {
 	assert.False(t, repo.createCalled)
 }
 
+func TestSubscriptionService_UpdateCustomerEmail_WithoutCustomer(t *testing.T) {
+	conf := config.CreateConfig()
+
+	repo := &fakeZonesRepo{getByUserErr: gorm.ErrRecordNotFound}
+	service := NewSubscriptionService(conf, repo, &fakeZonesEmailSender{}, &fakeWorldJobsClient{})
+	assert.NoError(t, service.UpdateCustomerEmail(uuid.New(), "new@example.com"))
+
+	repo = &fakeZonesRepo{getByUserID: &models.ZonesSubscriptions{Status: "comp"}}
+	service = NewSubscriptionService(conf, repo, &fakeZonesEmailSender{}, &fakeWorldJobsClient{})
+	assert.NoError(t, service.UpdateCustomerEmail(uuid.New(), "new@example.com"))
+	assert.False(t, repo.updateCalled)
+
+	repo = &fakeZonesRepo{getByUserErr: errors.New("db down")}
+	service = NewSubscriptionService(conf, repo, &fakeZonesEmailSender{}, &fakeWorldJobsClient{})
+	assert.Error(t, service.UpdateCustomerEmail(uuid.New(), "new@example.com"))
+}
+
 func TestSubscriptionService_GetByUserID_PendingStatus(t *testing.T) {
 	conf := config.CreateConfig()
 	conf.Server.SubscriptionOn = true
@@ -153,7 +204,7 @@ func TestSubscriptionService_GetByUserID_PendingStatus(t *testing.T) {
 		Status:               "pending",
 		AmountDue:            decimal.Zero,
 	}}
-	service := NewSubscriptionService(conf, repo, &fakeZonesEmailSender{})
+	service := NewSubscriptionService(conf, repo, &fakeZonesEmailSender{}, &fakeWorldJobsClient{})
 
 	sub, err := service.GetByUserID(userID)
 	assert.NoError(t, err)
@@ -163,7 +214,7 @@ func TestSubscriptionService_GetByUserID_PendingStatus(t *testing.T) {
 
 func TestSubscriptionService_GetPricingInfo(t *testing.T) {
 	conf := config.CreateConfig()
-	service := NewSubscriptionService(conf, &fakeZonesRepo{}, &fakeZonesEmailSender{})
+	service := NewSubscriptionService(conf, &fakeZonesRepo{}, &fakeZonesEmailSender{}, &fakeWorldJobsClient{})
 
 	price, next := service.GetPricingInfo()
 	assert.True(t, price > 0)
@@ -179,7 +230,7 @@ func TestSubscriptionService_UpdateUsedSlots_ExceedsTotal(t *testing.T) {
 		UsedSlots:  1,
 		Status:     stripe.SubscriptionStatusActive,
 	}}
-	service := NewSubscriptionService(conf, repo, &fakeZonesEmailSender{})
+	service := NewSubscriptionService(conf, repo, &fakeZonesEmailSender{}, &fakeWorldJobsClient{})
 
 	err := service.UpdateUsedSlots(userID, 2, true)
 	assert.Error(t, err)
@@ -195,7 +246,7 @@ func TestSubscriptionService_UpdateUsedSlots_UpdateError(t *testing.T) {
 	repo := &fakeZonesRepo{
 		getByUserErr: errors.New("boom"),
 	}
-	service := NewSubscriptionService(conf, repo, &fakeZonesEmailSender{})
+	service := NewSubscriptionService(conf, repo, &fakeZonesEmailSender{}, &fakeWorldJobsClient{})
 
 	_, err := service.GetByUserID(userID)
 	assert.Error(t, err)
@@ -205,7 +256,7 @@ func TestSubscriptionService_CheckAvailability_Active(t *testing.T) {
 	conf := config.CreateConfig()
 	userID := uuid.New()
 	repo := &fakeZonesRepo{getByUserID: &models.ZonesSubscriptions{UserID: userID, TotalSlots: 5, UsedSlots: 2, Status: stripe.SubscriptionStatusActive}}
-	service := NewSubscriptionService(conf, repo, &fakeZonesEmailSender{})
+	service := NewSubscriptionService(conf, repo, &fakeZonesEmailSender{}, &fakeWorldJobsClient{})
 
 	allowed, freeSlots, err := service.CheckAvalibility(userID)
 	assert.NoError(t, err)
@@ -217,7 +268,7 @@ func TestSubscriptionService_CheckAvailability_Inactive(t *testing.T) {
 	conf := config.CreateConfig()
 	userID := uuid.New()
 	repo := &fakeZonesRepo{getByUserID: &models.ZonesSubscriptions{UserID: userID, TotalSlots: 5, UsedSlots: 0, Status: stripe.SubscriptionStatusPastDue}}
-	service := NewSubscriptionService(conf, repo, &fakeZonesEmailSender{})
+	service := NewSubscriptionService(conf, repo, &fakeZonesEmailSender{}, &fakeWorldJobsClient{})
 
 	allowed, freeSlots, err := service.CheckAvalibility(userID)
 	assert.NoError(t, err)
@@ -241,7 +292,7 @@ func TestSubscriptionService_GetByUserID_UpdateError(t *testing.T) {
 		},
 		updateErr: errors.New("boom"),
 	}
-	service := NewSubscriptionService(conf, repo, &fakeZonesEmailSender{})
+	service := NewSubscriptionService(conf, repo, &fakeZonesEmailSender{}, &fakeWorldJobsClient{})
 
 	_, err := service.GetByUserID(userID)
 	assert.Error(t, err)
@@ -250,16 +301,8 @@ func TestSubscriptionService_GetByUserID_UpdateError(t *testing.T) {
 func TestSubscriptionService_StopAllJobs_HTTPError(t *testing.T) {
 	conf := config.CreateConfig()
 	repo := &fakeZonesRepo{}
-	service := NewSubscriptionService(conf, repo, &fakeZonesEmailSender{}).(*zoneSubscriptionService)
-
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusInternalServerError)
-	}))
-	defer server.Close()
-
-	portStr := strings.TrimPrefix(server.URL, "http://127.0.0.1:")
-	port, _ := strconv.Atoi(portStr)
-	conf.Server.Port = port
+	worldJobs := &fakeWorldJobsClient{stopErr: errors.New("boom")}
+	service := NewSubscriptionService(conf, repo, &fakeZonesEmailSender{}, worldJobs).(*zoneSubscriptionService)
 
 	sub := &models.ZonesSubscriptions{UserID: uuid.New(), UsedSlots: 1}
 	err := service.stopAllJobs(sub)
@@ -269,16 +312,8 @@ func TestSubscriptionService_StopAllJobs_HTTPError(t *testing.T) {
 func TestSubscriptionService_StopAllJobs_ResetsSlots(t *testing.T) {
 	conf := config.CreateConfig()
 	repo := &fakeZonesRepo{}
-	service := NewSubscriptionService(conf, repo, &fakeZonesEmailSender{}).(*zoneSubscriptionService)
-
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-	}))
-	defer server.Close()
-
-	portStr := strings.TrimPrefix(server.URL, "http://127.0.0.1:")
-	port, _ := strconv.Atoi(portStr)
-	conf.Server.Port = port
+	worldJobs := &fakeWorldJobsClient{}
+	service := NewSubscriptionService(conf, repo, &fakeZonesEmailSender{}, worldJobs).(*zoneSubscriptionService)
 
 	sub := &models.ZonesSubscriptions{UserID: uuid.New(), UsedSlots: 2}
 	repo.updateErr = nil
@@ -286,6 +321,7 @@ func TestSubscriptionService_StopAllJobs_ResetsSlots(t *testing.T) {
 	assert.NoError(t, err)
 	assert.True(t, repo.updateCalled)
 	assert.Equal(t, 0, sub.UsedSlots)
+	assert.Equal(t, []uuid.UUID{sub.UserID}, worldJobs.stopCalls)
 }
 
 func TestSubscriptionService_UpdateSlots_Validations(t *testing.T) {
@@ -334,7 +370,7 @@ func TestSubscriptionService_UpdateSlots_Validations(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			repo := &fakeZonesRepo{getByUserID: tt.sub, getByUserErr: tt.repoErr}
-			service := NewSubscriptionService(conf, repo, &fakeZonesEmailSender{})
+			service := NewSubscriptionService(conf, repo, &fakeZonesEmailSender{}, &fakeWorldJobsClient{})
 			_, err := service.UpdateSlots(userID, tt.newSlots)
 			assert.Error(t, err)
 			assert.Contains(t, err.Error(), tt.expectedErr)
@@ -377,7 +413,7 @@ func TestSubscriptionService_CancelSubscription_Validations(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			repo := &fakeZonesRepo{getByUserID: tt.sub, getByUserErr: tt.repoErr}
-			service := NewSubscriptionService(conf, repo, &fakeZonesEmailSender{})
+			service := NewSubscriptionService(conf, repo, &fakeZonesEmailSender{}, &fakeWorldJobsClient{})
 			_, err := service.CancelSubscription(userID)
 			assert.Error(t, err)
 			assert.Contains(t, err.Error(), tt.expectedErr)
@@ -388,7 +424,7 @@ func TestSubscriptionService_CancelSubscription_Validations(t *testing.T) {
 func TestSubscriptionService_HandleWebhook_InvalidSignature(t *testing.T) {
 	conf := config.CreateConfig()
 	conf.Stripe.StripeSubscriptionsWebhookSecret = "whsec_test"
-	service := NewSubscriptionService(conf, &fakeZonesRepo{}, &fakeZonesEmailSender{})
+	service := NewSubscriptionService(conf, &fakeZonesRepo{}, &fakeZonesEmailSender{}, &fakeWorldJobsClient{})
 
 	payload := []byte(`{"id":"evt_test","type":"customer.subscription.created"}`)
 	signature := "t=123,v1=invalid_signature"
@@ -434,7 +470,7 @@ func webhookConf(secret string) *config.Config {
 func TestSubscriptionService_HandleWebhook_SignatureError(t *testing.T) {
 	secret := "whsec_test_secret"
 	conf := webhookConf(secret)
-	service := NewSubscriptionService(conf, &fakeZonesRepo{}, &fakeZonesEmailSender{})
+	service := NewSubscriptionService(conf, &fakeZonesRepo{}, &fakeZonesEmailSender{}, &fakeWorldJobsClient{})
 
 	payload := []byte(`{"id":"evt_test","type":"customer.subscription.updated"}`)
 	sig := "t=123,v1=firma_invalida_totalmente"
@@ -447,7 +483,7 @@ func TestSubscriptionService_HandleWebhook_SignatureError(t *testing.T) {
 func TestSubscriptionService_HandleWebhook_UnhandledEvent(t *testing.T) {
 	secret := "whsec_test_secret"
 	conf := webhookConf(secret)
-	service := NewSubscriptionService(conf, &fakeZonesRepo{}, &fakeZonesEmailSender{})
+	service := NewSubscriptionService(conf, &fakeZonesRepo{}, &fakeZonesEmailSender{}, &fakeWorldJobsClient{})
 
 	obj := map[string]interface{}{
 		"id": "sub_test",
@@ -493,7 +529,7 @@ func TestSubscriptionService_HandleWebhook_SubscriptionUpdated(t *testing.T) {
 		getByUserID:      sub,
 		getByStripeSubID: sub,
 	}
-	service := NewSubscriptionService(conf, repo, &fakeZonesEmailSender{})
+	service := NewSubscriptionService(conf, repo, &fakeZonesEmailSender{}, &fakeWorldJobsClient{})
 
 	obj := map[string]interface{}{
 		"id":     "sub_123",
@@ -525,14 +561,6 @@ func TestSubscriptionService_HandleWebhook_SubscriptionDeleted(t *testing.T) {
 
 	userID := uuid.New()
 
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-	}))
-	defer server.Close()
-	portStr := strings.TrimPrefix(server.URL, "http://127.0.0.1:")
-	port, _ := strconv.Atoi(portStr)
-	conf.Server.Port = port
-
 	sub := &models.ZonesSubscriptions{
 		UserID:               userID,
 		StripeSubscriptionID: "sub_123",
@@ -543,7 +571,7 @@ func TestSubscriptionService_HandleWebhook_SubscriptionDeleted(t *testing.T) {
 		getByUserID:      sub,
 		getByStripeSubID: sub,
 	}
-	service := NewSubscriptionService(conf, repo, &fakeZonesEmailSender{})
+	service := NewSubscriptionService(conf, repo, &fakeZonesEmailSender{}, &fakeWorldJobsClient{})
 
 	obj := map[string]interface{}{
 		"id":     "sub_123",
@@ -600,7 +628,7 @@ func TestSubscriptionService_HandleWebhook_SubscriptionCreated(t *testing.T) {
 		},
 	}
 	emailSender := &fakeZonesEmailSender{}
-	service := NewSubscriptionService(conf, repo, emailSender)
+	service := NewSubscriptionService(conf, repo, emailSender, &fakeWorldJobsClient{})
 
 	obj := map[string]interface{}{
 		"id":     "sub_new_123",
@@ -630,7 +658,7 @@ func TestSubscriptionService_HandleWebhook_InvoicePaid(t *testing.T) {
 	conf := webhookConf(secret)
 
 	emailSender := &fakeZonesEmailSender{}
-	service := NewSubscriptionService(conf, &fakeZonesRepo{}, emailSender)
+	service := NewSubscriptionService(conf, &fakeZonesRepo{}, emailSender, &fakeWorldJobsClient{})
 
 	obj := map[string]interface{}{
 		"id":             "in_paid_123",
@@ -667,16 +695,8 @@ func TestSubscriptionService_HandleWebhook_InvoicePaymentFailed(t *testing.T) {
 	}))
 	stripe.Key = "sk_test_dummy"
 
-	internalServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-	}))
-	defer internalServer.Close()
-
 	secret := "whsec_test_secret"
 	conf := webhookConf(secret)
-	portStr := strings.TrimPrefix(internalServer.URL, "http://127.0.0.1:")
-	port, _ := strconv.Atoi(portStr)
-	conf.Server.Port = port
 
 	repo := &fakeZonesRepo{
 		getByStripeSubID: &models.ZonesSubscriptions{
@@ -686,7 +706,7 @@ func TestSubscriptionService_HandleWebhook_InvoicePaymentFailed(t *testing.T) {
 		},
 	}
 	emailSender := &fakeZonesEmailSender{}
-	service := NewSubscriptionService(conf, repo, emailSender)
+	service := NewSubscriptionService(conf, repo, emailSender, &fakeWorldJobsClient{})
 
 	obj := map[string]interface{}{
 		"id":             "in_fail_123",
@@ -717,7 +737,7 @@ func TestSubscriptionService_GetByUserID_NotFound(t *testing.T) {
 	conf.Server.Environment = config.Development
 
 	repo := &fakeZonesRepo{getByUserErr: errors.New("not found")}
-	service := NewSubscriptionService(conf, repo, &fakeZonesEmailSender{})
+	service := NewSubscriptionService(conf, repo, &fakeZonesEmailSender{}, &fakeWorldJobsClient{})
 
 	_, err := service.GetByUserID(uuid.New())
 	assert.Error(t, err)
@@ -739,7 +759,7 @@ func TestSubscriptionService_GetByUserID_CanceledUsesLocalCalc(t *testing.T) {
 			AmountDue:            decimal.Zero,
 		},
 	}
-	service := NewSubscriptionService(conf, repo, &fakeZonesEmailSender{})
+	service := NewSubscriptionService(conf, repo, &fakeZonesEmailSender{}, &fakeWorldJobsClient{})
 
 	sub, err := service.GetByUserID(userID)
 	assert.NoError(t, err)
@@ -751,7 +771,7 @@ func TestSubscriptionService_GetByUserID_CanceledUsesLocalCalc(t *testing.T) {
 func TestSubscriptionService_CheckAvailability_GetError(t *testing.T) {
 	conf := config.CreateConfig()
 	repo := &fakeZonesRepo{getByUserErr: errors.New("db error")}
-	service := NewSubscriptionService(conf, repo, &fakeZonesEmailSender{})
+	service := NewSubscriptionService(conf, repo, &fakeZonesEmailSender{}, &fakeWorldJobsClient{})
 
 	allowed, slots, err := service.CheckAvalibility(uuid.New())
 	assert.Error(t, err)
@@ -768,7 +788,7 @@ func TestSubscriptionService_CheckAvailability_NoFreeSlots(t *testing.T) {
 		UsedSlots:  3,
 		Status:     stripe.SubscriptionStatusActive,
 	}}
-	service := NewSubscriptionService(conf, repo, &fakeZonesEmailSender{})
+	service := NewSubscriptionService(conf, repo, &fakeZonesEmailSender{}, &fakeWorldJobsClient{})
 
 	allowed, freeSlots, err := service.CheckAvalibility(userID)
 	assert.NoError(t, err)
@@ -787,7 +807,7 @@ func TestSubscriptionService_UpdateUsedSlots_DecreaseClamp(t *testing.T) {
 		UsedSlots:  1,
 		Status:     stripe.SubscriptionStatusActive,
 	}}
-	service := NewSubscriptionService(conf, repo, &fakeZonesEmailSender{})
+	service := NewSubscriptionService(conf, repo, &fakeZonesEmailSender{}, &fakeWorldJobsClient{})
 
 	err := service.UpdateUsedSlots(userID, 10, false)
 	assert.NoError(t, err)
@@ -797,7 +817,7 @@ func TestSubscriptionService_UpdateUsedSlots_DecreaseClamp(t *testing.T) {
 func TestSubscriptionService_UpdateUsedSlots_GetError(t *testing.T) {
 	conf := config.CreateConfig()
 	repo := &fakeZonesRepo{getByUserErr: errors.New("db error")}
-	service := NewSubscriptionService(conf, repo, &fakeZonesEmailSender{})
+	service := NewSubscriptionService(conf, repo, &fakeZonesEmailSender{}, &fakeWorldJobsClient{})
 
 	err := service.UpdateUsedSlots(uuid.New(), 1, true)
 	assert.Error(t, err)
@@ -808,7 +828,7 @@ func TestSubscriptionService_UpdateUsedSlots_GetError(t *testing.T) {
 func TestSubscriptionService_CancelSubscription_GetError(t *testing.T) {
 	conf := config.CreateConfig()
 	repo := &fakeZonesRepo{getByUserErr: errors.New("db error")}
-	service := NewSubscriptionService(conf, repo, &fakeZonesEmailSender{})
+	service := NewSubscriptionService(conf, repo, &fakeZonesEmailSender{}, &fakeWorldJobsClient{})
 
 	_, err := service.CancelSubscription(uuid.New())
 	assert.Error(t, err)
@@ -821,7 +841,7 @@ func TestSubscriptionService_GetPricingInfo_NonZero(t *testing.T) {
 	conf.Stripe.StripeZonePrice = 9.99
 	conf.Stripe.StripeBillingAnchorDay = 15
 	conf.Stripe.StripeBillingTimezone = "UTC"
-	service := NewSubscriptionService(conf, &fakeZonesRepo{}, &fakeZonesEmailSender{})
+	service := NewSubscriptionService(conf, &fakeZonesRepo{}, &fakeZonesEmailSender{}, &fakeWorldJobsClient{})
 
 	price, next := service.GetPricingInfo()
 	assert.Equal(t, 9.99, price)
@@ -834,7 +854,7 @@ func TestSubscriptionService_NextBillingDate_PastAnchorRollsOver(t *testing.T) {
 	conf := config.CreateConfig()
 	conf.Stripe.StripeBillingTimezone = "UTC"
 	conf.Stripe.StripeBillingAnchorDay = 1
-	service := NewSubscriptionService(conf, &fakeZonesRepo{}, &fakeZonesEmailSender{}).(*zoneSubscriptionService)
+	service := NewSubscriptionService(conf, &fakeZonesRepo{}, &fakeZonesEmailSender{}, &fakeWorldJobsClient{}).(*zoneSubscriptionService)
 
 	next := service.nextBillingDate()
 	assert.True(t, next.After(time.Now().UTC()))
@@ -845,7 +865,7 @@ func TestSubscriptionService_NextBillingDate_InvalidTimezone(t *testing.T) {
 	conf := config.CreateConfig()
 	conf.Stripe.StripeBillingTimezone = "Invalid/Zone"
 	conf.Stripe.StripeBillingAnchorDay = 15
-	service := NewSubscriptionService(conf, &fakeZonesRepo{}, &fakeZonesEmailSender{}).(*zoneSubscriptionService)
+	service := NewSubscriptionService(conf, &fakeZonesRepo{}, &fakeZonesEmailSender{}, &fakeWorldJobsClient{}).(*zoneSubscriptionService)
 
 	next := service.nextBillingDate()
 	assert.True(t, next.After(time.Now().Add(-time.Minute)))
@@ -868,7 +888,7 @@ func TestSubscriptionService_GetByUserID_SubscriptionOffProduction(t *testing.T)
 			AmountDue:            decimal.Zero,
 		},
 	}
-	service := NewSubscriptionService(conf, repo, &fakeZonesEmailSender{})
+	service := NewSubscriptionService(conf, repo, &fakeZonesEmailSender{}, &fakeWorldJobsClient{})
 
 	sub, err := service.GetByUserID(userID)
 	assert.NoError(t, err)
@@ -880,7 +900,7 @@ func TestSubscriptionService_GetByUserID_SubscriptionOffProduction(t *testing.T)
 func TestSubscriptionService_HandleWebhook_SubscriptionUpdated_MissingUserID(t *testing.T) {
 	secret := "whsec_test_secret"
 	conf := webhookConf(secret)
-	service := NewSubscriptionService(conf, &fakeZonesRepo{}, &fakeZonesEmailSender{})
+	service := NewSubscriptionService(conf, &fakeZonesRepo{}, &fakeZonesEmailSender{}, &fakeWorldJobsClient{})
 
 	obj := map[string]interface{}{
 		"id":       "sub_123",
@@ -901,7 +921,7 @@ func TestSubscriptionService_HandleWebhook_SubscriptionUpdated_MissingUserID(t *
 func TestSubscriptionService_HandleWebhook_SubscriptionCreated_MissingUserID(t *testing.T) {
 	secret := "whsec_test_secret"
 	conf := webhookConf(secret)
-	service := NewSubscriptionService(conf, &fakeZonesRepo{}, &fakeZonesEmailSender{})
+	service := NewSubscriptionService(conf, &fakeZonesRepo{}, &fakeZonesEmailSender{}, &fakeWorldJobsClient{})
 
 	obj := map[string]interface{}{
 		"id":       "sub_new",
@@ -922,7 +942,7 @@ func TestSubscriptionService_HandleWebhook_InvoicePaid_NoEmail(t *testing.T) {
 	secret := "whsec_test_secret"
 	conf := webhookConf(secret)
 	emailSender := &fakeZonesEmailSender{}
-	service := NewSubscriptionService(conf, &fakeZonesRepo{}, emailSender)
+	service := NewSubscriptionService(conf, &fakeZonesRepo{}, emailSender, &fakeWorldJobsClient{})
 
 	obj := map[string]interface{}{
 		"id":             "in_noemail",
@@ -957,7 +977,7 @@ func TestSubscriptionService_HandleWebhook_SubscriptionDeleted_NoEmail(t *testin
 		getByStripeSubID: sub,
 	}
 	emailSender := &fakeZonesEmailSender{}
-	service := NewSubscriptionService(conf, repo, emailSender)
+	service := NewSubscriptionService(conf, repo, emailSender, &fakeWorldJobsClient{})
 
 	obj := map[string]interface{}{
 		"id":       "sub_del_noemail",
@@ -980,7 +1000,7 @@ func TestSubscriptionService_CalculateProratedAmount(t *testing.T) {
 	conf.Stripe.StripeZonePrice = 10.0
 	conf.Stripe.StripeBillingAnchorDay = 15
 	conf.Stripe.StripeBillingTimezone = "UTC"
-	service := NewSubscriptionService(conf, &fakeZonesRepo{}, &fakeZonesEmailSender{}).(*zoneSubscriptionService)
+	service := NewSubscriptionService(conf, &fakeZonesRepo{}, &fakeZonesEmailSender{}, &fakeWorldJobsClient{}).(*zoneSubscriptionService)
 
 	amount := service.calculateProratedAmount(3)
 	assert.True(t, amount >= 0)
@@ -990,7 +1010,7 @@ func TestSubscriptionService_CreateCheckoutSession_GetByUserIDError(t *testing.T
 	conf := config.CreateConfig()
 	// GetByUserID falla → sub = nil → ensureCustomer con nil → intenta crear customer en Stripe → falla
 	repo := &fakeZonesRepo{getByUserErr: errors.New("db error")}
-	service := NewSubscriptionService(conf, repo, &fakeZonesEmailSender{})
+	service := NewSubscriptionService(conf, repo, &fakeZonesEmailSender{}, &fakeWorldJobsClient{})
 
 	_, err := service.CreateCheckoutSession(uuid.New(), "user@example.com", 2, "ok", "cancel")
 	assert.Error(t, err)
@@ -999,7 +1019,7 @@ func TestSubscriptionService_CreateCheckoutSession_GetByUserIDError(t *testing.T
 func TestSubscriptionService_EnsureCustomer_ExistingSubNoCustomerID(t *testing.T) {
 	conf := config.CreateConfig()
 	repo := &fakeZonesRepo{}
-	service := NewSubscriptionService(conf, repo, &fakeZonesEmailSender{}).(*zoneSubscriptionService)
+	service := NewSubscriptionService(conf, repo, &fakeZonesEmailSender{}, &fakeWorldJobsClient{}).(*zoneSubscriptionService)
 
 	// existingSub != nil pero StripeCustomerID == "" → intenta crear customer en Stripe → falla por no tener key
 	existing := &models.ZonesSubscriptions{
@@ -1014,7 +1034,7 @@ func TestSubscriptionService_EnsureCustomer_ExistingSubNoCustomerID(t *testing.T
 func TestSubscriptionService_ReactivateSubscription_NotFound(t *testing.T) {
 	conf := config.CreateConfig()
 	repo := &fakeZonesRepo{getByUserErr: errors.New("not found")}
-	service := NewSubscriptionService(conf, repo, &fakeZonesEmailSender{})
+	service := NewSubscriptionService(conf, repo, &fakeZonesEmailSender{}, &fakeWorldJobsClient{})
 
 	_, err := service.ReactivateSubscription(uuid.New())
 	assert.Error(t, err)
@@ -1025,7 +1045,7 @@ func TestSubscriptionService_ReactivateSubscription_MissingStripeID(t *testing.T
 	repo := &fakeZonesRepo{getByUserID: &models.ZonesSubscriptions{
 		Status: StatusPendingCancellation,
 	}}
-	service := NewSubscriptionService(conf, repo, &fakeZonesEmailSender{})
+	service := NewSubscriptionService(conf, repo, &fakeZonesEmailSender{}, &fakeWorldJobsClient{})
 
 	_, err := service.ReactivateSubscription(uuid.New())
 	assert.Error(t, err)
@@ -1038,7 +1058,7 @@ func TestSubscriptionService_ReactivateSubscription_NotPendingCancellation(t *te
 		StripeSubscriptionID: "sub_123",
 		Status:               stripe.SubscriptionStatusActive,
 	}}
-	service := NewSubscriptionService(conf, repo, &fakeZonesEmailSender{})
+	service := NewSubscriptionService(conf, repo, &fakeZonesEmailSender{}, &fakeWorldJobsClient{})
 
 	_, err := service.ReactivateSubscription(uuid.New())
 	assert.Error(t, err)
@@ -1048,16 +1068,8 @@ func TestSubscriptionService_ReactivateSubscription_NotPendingCancellation(t *te
 func TestSubscriptionService_StopAllJobs_UpdateError(t *testing.T) {
 	conf := config.CreateConfig()
 	repo := &fakeZonesRepo{updateErr: errors.New("db error")}
-	service := NewSubscriptionService(conf, repo, &fakeZonesEmailSender{}).(*zoneSubscriptionService)
-
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-	}))
-	defer server.Close()
-
-	portStr := strings.TrimPrefix(server.URL, "http://127.0.0.1:")
-	port, _ := strconv.Atoi(portStr)
-	conf.Server.Port = port
+	worldJobs := &fakeWorldJobsClient{}
+	service := NewSubscriptionService(conf, repo, &fakeZonesEmailSender{}, worldJobs).(*zoneSubscriptionService)
 
 	sub := &models.ZonesSubscriptions{UserID: uuid.New(), UsedSlots: 1}
 	err := service.stopAllJobs(sub)
@@ -1068,7 +1080,7 @@ func TestSubscriptionService_StopAllJobs_UpdateError(t *testing.T) {
 func TestSubscriptionService_HandleWebhook_InvoicePaymentFailed_NoSubscriptionID(t *testing.T) {
 	secret := "whsec_test_secret"
 	conf := webhookConf(secret)
-	service := NewSubscriptionService(conf, &fakeZonesRepo{}, &fakeZonesEmailSender{})
+	service := NewSubscriptionService(conf, &fakeZonesRepo{}, &fakeZonesEmailSender{}, &fakeWorldJobsClient{})
 
 	obj := map[string]interface{}{
 		"id":            "in_fail_nosub",
@@ -1104,16 +1116,8 @@ func TestSubscriptionService_HandleWebhook_InvoicePaymentFailed_NoEmail(t *testi
 	}))
 	stripe.Key = "sk_test_dummy"
 
-	internalServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-	}))
-	defer internalServer.Close()
-
 	secret := "whsec_test_secret"
 	conf := webhookConf(secret)
-	portStr := strings.TrimPrefix(internalServer.URL, "http://127.0.0.1:")
-	port, _ := strconv.Atoi(portStr)
-	conf.Server.Port = port
 
 	emailSender := &fakeZonesEmailSender{}
 	repo := &fakeZonesRepo{
@@ -1123,7 +1127,7 @@ func TestSubscriptionService_HandleWebhook_InvoicePaymentFailed_NoEmail(t *testi
 			StripeSubscriptionID: "sub_fail_noemail",
 		},
 	}
-	service := NewSubscriptionService(conf, repo, emailSender)
+	service := NewSubscriptionService(conf, repo, emailSender, &fakeWorldJobsClient{})
 
 	obj := map[string]interface{}{
 		"id":             "in_fail_noemail",
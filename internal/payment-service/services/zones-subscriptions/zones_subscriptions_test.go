@@ -1,9 +1,6 @@
 package zones_subscriptions
 
 import (
-	"net/http"
-	"net/http/httptest"
-	"net/url"
 	"os"
 	"strconv"
 	"testing"
@@ -37,7 +34,7 @@ func TestMain(m *testing.M) {
 	}
 	testRepo = zones_subscriptions_repo.NewSubscriptionRepository(testConf, testDB)
 	testEmailServer = email_sender.NewEmailSenderService(testConf)
-	testSvc = NewSubscriptionService(testConf, testRepo, testEmailServer).(*zoneSubscriptionService)
+	testSvc = NewSubscriptionService(testConf, testRepo, testEmailServer, &fakeWorldJobsClient{}).(*zoneSubscriptionService)
 
 	clearZonesTables()
 	code := m.Run()
@@ -191,7 +188,7 @@ func TestStopAllJobs_ResetsSlots(t *testing.T) {
 	clearZonesTables()
 	userID := uuid.New()
 
-	setupStopJobsServer(t, userID)
+	setupStopJobsClient(t, userID)
 
 	createZoneSubscription(t, &models.ZonesSubscriptions{
 		UserID:           userID,
@@ -224,34 +221,20 @@ func TestNextBillingDate_AnchorInFuture(t *testing.T) {
 	assert.True(t, next.After(time.Now().UTC()))
 }
 
-func setupStopJobsServer(t *testing.T, userID uuid.UUID) {
+func setupStopJobsClient(t *testing.T, userID uuid.UUID) {
 	t.Helper()
 
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		expectedPath := "/world/internal/users/" + userID.String() + "/stop-jobs"
-		if r.URL.Path != expectedPath {
-			t.Errorf("unexpected path: %s", r.URL.Path)
-			w.WriteHeader(http.StatusNotFound)
-			return
-		}
-		w.WriteHeader(http.StatusOK)
-	}))
-
-	parsed, err := url.Parse(server.URL)
-	if err != nil {
-		t.Fatalf("failed to parse server url: %v", err)
-	}
-	port, err := strconv.Atoi(parsed.Port())
-	if err != nil {
-		t.Fatalf("failed to parse server port: %v", err)
-	}
-
-	originalPort := testConf.Server.Port
-	testConf.Server.Port = port
+	worldJobs := &fakeWorldJobsClient{}
+	originalClient := testSvc.worldJobsClient
+	testSvc.worldJobsClient = worldJobs
 
 	t.Cleanup(func() {
-		server.Close()
-		testConf.Server.Port = originalPort
+		testSvc.worldJobsClient = originalClient
+		for _, id := range worldJobs.stopCalls {
+			if id != userID {
+				t.Errorf("unexpected stop-jobs user: %s", id)
+			}
+		}
 	})
 }
 
@@ -485,7 +468,7 @@ func TestAdminCancelSubscription_AdminGranted_Immediate(t *testing.T) {
 	clearZonesTables()
 	userID := uuid.New()
 
-	setupStopJobsServer(t, userID)
+	setupStopJobsClient(t, userID)
 
 	createZoneSubscription(t, &models.ZonesSubscriptions{
 		UserID:           userID,
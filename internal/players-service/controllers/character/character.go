@@ -171,3 +171,74 @@ func (c *characterController) GetCharacterInfo(ctx *gin.Context) {
 
 	common_handlers.HandleSuccessResponse(ctx, http.StatusOK, res)
 }
+
+// GetCharacterByNameInternal godoc
+// @Summary      Get character by name (internal)
+// @Description  Resolves a character name to the user that owns it, only reachable by other services.
+// @Tags         players-service-internal
+// @Produce      json
+// @Param        character_name query string true "Character name"
+// @Success      200  {object}  dtos.InternalCharacterResponse
+// @Failure      400  {object} dtos.ErrorResponse
+// @Failure      404  {object} dtos.ErrorResponse
+// @Router       /player/internal/characters [get]
+func (c *characterController) GetCharacterByNameInternal(ctx *gin.Context) {
+	characterName := ctx.Query("character_name")
+	if characterName == "" {
+		_ = ctx.Error(errors.NewBadRequestError("character_name is required"))
+		return
+	}
+
+	characterInfo, err := c.characterService.GetCharacterInfoByName(characterName)
+	if err != nil {
+		if _, ok := err.(*player_errors.CharacterInfoNotFound); ok {
+			_ = ctx.Error(errors.NewNotFoundError("character not found"))
+			return
+		}
+		_ = ctx.Error(err)
+		return
+	}
+
+	res := &dtos.InternalCharacterResponse{
+		UserId:        characterInfo.UserId,
+		CharacterName: characterInfo.CharacterName,
+	}
+
+	common_handlers.HandleSuccessResponse(ctx, http.StatusOK, res)
+}
+
+// GetCharacterByUserInternal godoc
+// @Summary      Get character by user (internal)
+// @Description  Retrieves the character name of a user, only reachable by other services.
+// @Tags         players-service-internal
+// @Produce      json
+// @Param        user_id path string true "User UUID"
+// @Success      200  {object}  dtos.InternalCharacterResponse
+// @Failure      400  {object} dtos.ErrorResponse
+// @Failure      404  {object} dtos.ErrorResponse
+// @Router       /player/internal/characters/{user_id} [get]
+func (c *characterController) GetCharacterByUserInternal(ctx *gin.Context) {
+	userId, err := uuid.Parse(ctx.Param("user_id"))
+	if err != nil {
+		_ = ctx.Error(errors.NewBadRequestError("invalid user ID: " + ctx.Param("user_id")))
+		return
+	}
+
+	characterInfo, _, err := c.characterService.GetCharacterInfo(userId)
+	if err != nil {
+		switch err.(type) {
+		case *player_errors.CharacterInfoNotFound, *player_errors.CategorySpritesNotFound:
+			_ = ctx.Error(errors.NewNotFoundError("character not found"))
+		default:
+			_ = ctx.Error(err)
+		}
+		return
+	}
+
+	res := &dtos.InternalCharacterResponse{
+		UserId:        characterInfo.UserId,
+		CharacterName: characterInfo.CharacterName,
+	}
+
+	common_handlers.HandleSuccessResponse(ctx, http.StatusOK, res)
+}
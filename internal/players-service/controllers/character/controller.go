@@ -9,4 +9,10 @@ type CharacterController interface {
 
 	// GetCharacterInfo retrieves character information.
 	GetCharacterInfo(c *gin.Context)
+
+	// GetCharacterByNameInternal resolves a character name to its user for other services.
+	GetCharacterByNameInternal(c *gin.Context)
+
+	// GetCharacterByUserInternal retrieves the character of a user for other services.
+	GetCharacterByUserInternal(c *gin.Context)
 }
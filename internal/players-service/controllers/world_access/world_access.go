@@ -75,7 +75,7 @@ func (c *worldAccessController) IssueWorldJoinToken(ctx *gin.Context) {
 
 // ConsumeWorldJoinToken godoc
 // @Summary      Consume one-time world join token
-// @Description  Validates and burns a one-time token, returning the associated user ID.
+// @Description  Validates and burns a one-time token, returning the associated user ID. Only game servers can consume tokens, and only the ones issued for their world.
 // @Tags         players-service
 // @Security     BearerAuth
 // @Accept       json
@@ -83,12 +83,12 @@ func (c *worldAccessController) IssueWorldJoinToken(ctx *gin.Context) {
 // @Param        request body dtos.ConsumeWorldJoinTokenRequest true "Consume world join token DTO"
 // @Success      200  {object}  dtos.ConsumeWorldJoinTokenResponse
 // @Failure      400  {object} dtos.ErrorResponse
-// @Failure      401  {object} dtos.ErrorResponse
+// @Failure      403  {object} dtos.ErrorResponse
 // @Failure      404  {object} dtos.ErrorResponse
 // @Router       /player/world-access/token/consume [post]
 func (c *worldAccessController) ConsumeWorldJoinToken(ctx *gin.Context) {
-	if err := common_handlers.IsSessionValid(ctx); err != nil {
-		_ = ctx.Error(errors.NewUnauthorizedError(err.Error()))
+	if err := common_handlers.IsServerSession(ctx); err != nil {
+		_ = ctx.Error(errors.NewForbiddenError(err.Error()))
 		return
 	}
 
@@ -98,7 +98,8 @@ func (c *worldAccessController) ConsumeWorldJoinToken(ctx *gin.Context) {
 		return
 	}
 
-	token, err := c.worldAccessService.ConsumeWorldJoinToken(req.TokenId)
+	serverWorldId, _, _ := common_handlers.GetServerScope(ctx)
+	token, err := c.worldAccessService.ConsumeWorldJoinToken(req.TokenId, serverWorldId)
 	if err != nil {
 		switch err.(type) {
 		case *player_errors.WorldJoinTokenInvalid:
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/FeedTheRealm-org/core-service/internal/players-service/models"
+	"github.com/google/uuid"
 )
 
 // PatchCharacterInfoRequest represents the request payload for updating character information.
@@ -27,3 +28,9 @@ type CharacterInfoResponse struct {
 	CreatedAt       time.Time                `json:"created_at"`
 	UpdatedAt       time.Time                `json:"updated_at"`
 }
+
+// InternalCharacterResponse represents the character lookup returned to other services.
+type InternalCharacterResponse struct {
+	UserId        uuid.UUID `json:"user_id"`
+	CharacterName string    `json:"character_name"`
+}
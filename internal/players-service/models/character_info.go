@@ -52,22 +52,22 @@ func (c *CharacterColorHsv) Scan(value any) error {
 }
 
 type CharacterInfo struct {
-	UserId        uuid.UUID         `gorm:"type:uuid;primaryKey"`
-	CharacterName string            `gorm:"unique;not null"`
-	CharacterBio  string            `gorm:"not null"`
+	UserId        uuid.UUID         `gorm:"type:uuid;primaryKey" json:"user_id"`
+	CharacterName string            `gorm:"unique;not null" json:"character_name"`
+	CharacterBio  string            `gorm:"not null" json:"character_bio"`
 	SkinColor     CharacterColorHsv `gorm:"type:jsonb;not null" json:"skin_color"`
 	HairColor     CharacterColorHsv `gorm:"type:jsonb;not null" json:"hair_color"`
 	EyeColor      CharacterColorHsv `gorm:"type:jsonb;not null" json:"eye_color"`
-	CreatedAt     time.Time         `gorm:"autoCreateTime"`
-	UpdatedAt     time.Time         `gorm:"autoUpdateTime"`
+	CreatedAt     time.Time         `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time         `gorm:"autoUpdateTime" json:"updated_at"`
 }
 
 type CategorySprite struct {
-	UserId     uuid.UUID `gorm:"type:uuid;primaryKey"`
-	CategoryId uuid.UUID `gorm:"type:uuid;primaryKey"`
-	SpriteId   uuid.UUID `gorm:"type:uuid"`
-	CreatedAt  time.Time `gorm:"autoCreateTime"`
-	UpdatedAt  time.Time `gorm:"autoUpdateTime"`
+	UserId     uuid.UUID `gorm:"type:uuid;primaryKey" json:"user_id"`
+	CategoryId uuid.UUID `gorm:"type:uuid;primaryKey" json:"category_id"`
+	SpriteId   uuid.UUID `gorm:"type:uuid" json:"sprite_id"`
+	CreatedAt  time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt  time.Time `gorm:"autoUpdateTime" json:"updated_at"`
 }
 
 // CategorySpritesToMap is a helper function to convert CategorySprite slice to map
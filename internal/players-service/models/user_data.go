@@ -0,0 +1,8 @@
+package models
+
+// UserData is everything the players-service stores about a user, as handed out in a data export.
+type UserData struct {
+	CharacterInfo   *CharacterInfo   `json:"character_info"`
+	CategorySprites []CategorySprite `json:"category_sprites"`
+	WorldJoinTokens []WorldJoinToken `json:"world_join_tokens"`
+}
@@ -8,11 +8,11 @@ import (
 
 // WorldJoinToken stores a one-time token used to safely resolve a user ID on game-server join.
 type WorldJoinToken struct {
-	TokenId    uuid.UUID  `gorm:"type:uuid;primaryKey"`
-	UserId     uuid.UUID  `gorm:"type:uuid;not null;index"`
-	WorldId    string     `gorm:"not null;index"`
-	ExpiresAt  time.Time  `gorm:"not null;index"`
-	ConsumedAt *time.Time `gorm:"index"`
-	CreatedAt  time.Time  `gorm:"autoCreateTime"`
-	UpdatedAt  time.Time  `gorm:"autoUpdateTime"`
+	TokenId    uuid.UUID  `gorm:"type:uuid;primaryKey" json:"token_id"`
+	UserId     uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	WorldId    string     `gorm:"not null;index" json:"world_id"`
+	ExpiresAt  time.Time  `gorm:"not null;index" json:"expires_at"`
+	ConsumedAt *time.Time `gorm:"index" json:"consumed_at"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt  time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
 }
@@ -43,6 +43,17 @@ func (cr *characterRepository) GetCharacterInfo(userId uuid.UUID) (*models.Chara
 	return &characterInfo, nil
 }
 
+func (cr *characterRepository) GetCharacterInfoByName(characterName string) (*models.CharacterInfo, error) {
+	var characterInfo models.CharacterInfo
+	if err := cr.db.Conn.Where("character_name = ?", characterName).First(&characterInfo).Error; err != nil {
+		if errors.IsRecordNotFound(err) {
+			return nil, player_errors.NewCharacterInfoNotFound(err.Error())
+		}
+		return nil, err
+	}
+	return &characterInfo, nil
+}
+
 func (cr *characterRepository) UpdateCategorySprites(newCategorySprites []models.CategorySprite) error {
 	return cr.db.Conn.Clauses(clause.OnConflict{
 		Columns:   []clause.Column{{Name: "user_id"}, {Name: "category_id"}},
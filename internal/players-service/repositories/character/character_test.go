@@ -91,6 +91,22 @@ func TestCharacterRepository_GetCharacterInfo_NotFound(t *testing.T) {
 	assert.True(t, errors.As(err, &notFound))
 }
 
+func TestCharacterRepository_GetCharacterInfoByName(t *testing.T) {
+	clearCharacterTables()
+
+	userID := uuid.New()
+	name := "Hero-" + uuid.NewString()
+	createCharacterInfo(t, userID, name)
+
+	stored, err := characterRepo.GetCharacterInfoByName(name)
+	assert.NoError(t, err)
+	assert.Equal(t, userID, stored.UserId)
+
+	_, err = characterRepo.GetCharacterInfoByName("Missing-" + uuid.NewString())
+	var notFound *player_errors.CharacterInfoNotFound
+	assert.True(t, errors.As(err, &notFound))
+}
+
 func TestCharacterRepository_CategorySprites(t *testing.T) {
 	clearCharacterTables()
 
@@ -13,6 +13,9 @@ type CharacterRepository interface {
 	// GetCharacterInfo retrieves character information.
 	GetCharacterInfo(userId uuid.UUID) (*models.CharacterInfo, error)
 
+	// GetCharacterInfoByName retrieves the character information matching the exact character name.
+	GetCharacterInfoByName(characterName string) (*models.CharacterInfo, error)
+
 	// UpdateCategorySprites updates the category sprites for a user.
 	UpdateCategorySprites(newCategorySprite []models.CategorySprite) error
 
@@ -0,0 +1,15 @@
+package user_data
+
+import (
+	"github.com/FeedTheRealm-org/core-service/internal/players-service/models"
+	"github.com/google/uuid"
+)
+
+// UserDataRepository defines the export and erasure of everything stored about a user.
+type UserDataRepository interface {
+	// GetUserData retrieves the character, equipped sprites and join tokens of a user.
+	GetUserData(userId uuid.UUID) (*models.UserData, error)
+
+	// DeleteUserData removes the character, equipped sprites and join tokens of a user.
+	DeleteUserData(userId uuid.UUID) error
+}
@@ -0,0 +1,57 @@
+package user_data
+
+import (
+	"github.com/FeedTheRealm-org/core-service/config"
+	"github.com/FeedTheRealm-org/core-service/internal/errors"
+	"github.com/FeedTheRealm-org/core-service/internal/players-service/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type userDataRepository struct {
+	conf *config.Config
+	db   *config.DB
+}
+
+// NewUserDataRepository creates a new instance of UserDataRepository.
+func NewUserDataRepository(conf *config.Config, db *config.DB) UserDataRepository {
+	return &userDataRepository{
+		conf: conf,
+		db:   db,
+	}
+}
+
+func (r *userDataRepository) GetUserData(userId uuid.UUID) (*models.UserData, error) {
+	data := &models.UserData{
+		CategorySprites: []models.CategorySprite{},
+		WorldJoinTokens: []models.WorldJoinToken{},
+	}
+
+	var characterInfo models.CharacterInfo
+	if err := r.db.Conn.Where("user_id = ?", userId).First(&characterInfo).Error; err == nil {
+		data.CharacterInfo = &characterInfo
+	} else if !errors.IsRecordNotFound(err) {
+		return nil, err
+	}
+
+	if err := r.db.Conn.Where("user_id = ?", userId).Find(&data.CategorySprites).Error; err != nil {
+		return nil, err
+	}
+	if err := r.db.Conn.Where("user_id = ?", userId).Order("created_at ASC").Find(&data.WorldJoinTokens).Error; err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (r *userDataRepository) DeleteUserData(userId uuid.UUID) error {
+	return r.db.Conn.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userId).Delete(&models.WorldJoinToken{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", userId).Delete(&models.CategorySprite{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userId).Delete(&models.CharacterInfo{}).Error
+	})
+}
@@ -0,0 +1,60 @@
+package user_data
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/FeedTheRealm-org/core-service/config"
+	"github.com/FeedTheRealm-org/core-service/internal/players-service/models"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/logger"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var userDataConf *config.Config
+var userDataDB *config.DB
+var userDataRepo UserDataRepository
+
+func TestMain(m *testing.M) {
+	logger.InitLogger(false)
+	userDataConf = config.CreateConfig()
+	var err error
+	userDataDB, err = config.NewDB(userDataConf)
+	if err != nil {
+		panic(err)
+	}
+	userDataRepo = NewUserDataRepository(userDataConf, userDataDB)
+
+	code := m.Run()
+	os.Exit(code)
+}
+
+func TestUserDataRepository_GetAndDeleteUserData(t *testing.T) {
+	userID := uuid.New()
+	require.NoError(t, userDataDB.Conn.Create(&models.CharacterInfo{
+		UserId:        userID,
+		CharacterName: "Hero-" + uuid.NewString(),
+		CharacterBio:  "bio",
+		SkinColor:     models.DefaultCharacterColorHsv(),
+		HairColor:     models.DefaultCharacterColorHsv(),
+		EyeColor:      models.DefaultCharacterColorHsv(),
+	}).Error)
+	require.NoError(t, userDataDB.Conn.Create(&models.CategorySprite{UserId: userID, CategoryId: uuid.New(), SpriteId: uuid.New()}).Error)
+	require.NoError(t, userDataDB.Conn.Create(&models.WorldJoinToken{TokenId: uuid.New(), UserId: userID, WorldId: "world", ExpiresAt: time.Now().Add(time.Minute)}).Error)
+
+	data, err := userDataRepo.GetUserData(userID)
+	require.NoError(t, err)
+	require.NotNil(t, data.CharacterInfo)
+	assert.Len(t, data.CategorySprites, 1)
+	assert.Len(t, data.WorldJoinTokens, 1)
+
+	require.NoError(t, userDataRepo.DeleteUserData(userID))
+
+	data, err = userDataRepo.GetUserData(userID)
+	require.NoError(t, err)
+	assert.Nil(t, data.CharacterInfo)
+	assert.Empty(t, data.CategorySprites)
+	assert.Empty(t, data.WorldJoinTokens)
+}
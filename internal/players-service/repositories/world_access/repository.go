@@ -10,5 +10,6 @@ import (
 // WorldAccessRepository defines persistence operations for world join tokens.
 type WorldAccessRepository interface {
 	CreateWorldJoinToken(token *models.WorldJoinToken) error
-	ConsumeWorldJoinToken(tokenId uuid.UUID, now time.Time) (*models.WorldJoinToken, error)
+	// ConsumeWorldJoinToken burns the token, a non-empty worldId only matches tokens issued for that world.
+	ConsumeWorldJoinToken(tokenId uuid.UUID, worldId string, now time.Time) (*models.WorldJoinToken, error)
 }
@@ -25,13 +25,15 @@ func (wr *worldAccessRepository) CreateWorldJoinToken(token *models.WorldJoinTok
 	return wr.db.Conn.Create(token).Error
 }
 
-func (wr *worldAccessRepository) ConsumeWorldJoinToken(tokenId uuid.UUID, now time.Time) (*models.WorldJoinToken, error) {
+func (wr *worldAccessRepository) ConsumeWorldJoinToken(tokenId uuid.UUID, worldId string, now time.Time) (*models.WorldJoinToken, error) {
 	var token models.WorldJoinToken
 
 	err := wr.db.Conn.Transaction(func(tx *gorm.DB) error {
-		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
-			Where("token_id = ?", tokenId).
-			First(&token).Error; err != nil {
+		query := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("token_id = ?", tokenId)
+		if worldId != "" {
+			query = query.Where("world_id = ?", worldId)
+		}
+		if err := query.First(&token).Error; err != nil {
 			if core_errors.IsRecordNotFound(err) {
 				return player_errors.NewWorldJoinTokenNotFound("world join token not found")
 			}
@@ -83,7 +83,7 @@ func TestWorldAccessRepository_CreateAndConsume(t *testing.T) {
 
 	createWorldJoinToken(t, token)
 
-	consumed, err := worldAccessRepo.ConsumeWorldJoinToken(tokenID, now)
+	consumed, err := worldAccessRepo.ConsumeWorldJoinToken(tokenID, "", now)
 	require.NoError(t, err)
 	require.NotNil(t, consumed)
 	assert.Equal(t, tokenID, consumed.TokenId)
@@ -93,7 +93,7 @@ func TestWorldAccessRepository_CreateAndConsume(t *testing.T) {
 func TestWorldAccessRepository_Consume_NotFound(t *testing.T) {
 	clearWorldAccessTables()
 
-	_, err := worldAccessRepo.ConsumeWorldJoinToken(uuid.New(), time.Now().UTC())
+	_, err := worldAccessRepo.ConsumeWorldJoinToken(uuid.New(), "", time.Now().UTC())
 	assert.Error(t, err)
 	var notFound *player_errors.WorldJoinTokenNotFound
 	assert.True(t, errors.As(err, &notFound))
@@ -114,7 +114,7 @@ func TestWorldAccessRepository_Consume_Expired(t *testing.T) {
 	}
 	createWorldJoinToken(t, token)
 
-	_, err := worldAccessRepo.ConsumeWorldJoinToken(tokenID, time.Now().UTC())
+	_, err := worldAccessRepo.ConsumeWorldJoinToken(tokenID, "", time.Now().UTC())
 	assert.Error(t, err)
 	var expired *player_errors.WorldJoinTokenExpired
 	assert.True(t, errors.As(err, &expired))
@@ -137,7 +137,7 @@ func TestWorldAccessRepository_Consume_AlreadyConsumed(t *testing.T) {
 	}
 	createWorldJoinToken(t, token)
 
-	_, err := worldAccessRepo.ConsumeWorldJoinToken(tokenID, time.Now().UTC())
+	_, err := worldAccessRepo.ConsumeWorldJoinToken(tokenID, "", time.Now().UTC())
 	assert.Error(t, err)
 	var consumed *player_errors.WorldJoinTokenConsumed
 	assert.True(t, errors.As(err, &consumed))
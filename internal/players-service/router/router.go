@@ -2,24 +2,33 @@ package router
 
 import (
 	"github.com/FeedTheRealm-org/core-service/config"
+	"github.com/FeedTheRealm-org/core-service/internal/common_handlers"
+	"github.com/FeedTheRealm-org/core-service/internal/middleware"
 	character_controller "github.com/FeedTheRealm-org/core-service/internal/players-service/controllers/character"
 	world_access_controller "github.com/FeedTheRealm-org/core-service/internal/players-service/controllers/world_access"
 	character_repo "github.com/FeedTheRealm-org/core-service/internal/players-service/repositories/character"
+	user_data_repo "github.com/FeedTheRealm-org/core-service/internal/players-service/repositories/user_data"
 	world_access_repo "github.com/FeedTheRealm-org/core-service/internal/players-service/repositories/world_access"
 	character_service "github.com/FeedTheRealm-org/core-service/internal/players-service/services/character"
+	user_data_service "github.com/FeedTheRealm-org/core-service/internal/players-service/services/user_data"
 	world_access_service "github.com/FeedTheRealm-org/core-service/internal/players-service/services/world_access"
+	"github.com/FeedTheRealm-org/core-service/internal/service_clients"
 	"github.com/gin-gonic/gin"
 )
 
-func SetupPlayerServiceRouter(r *gin.Engine, conf *config.Config, db *config.DB) error {
+func SetupPlayerServiceRouter(r *gin.Engine, internal *gin.RouterGroup, conf *config.Config, db *config.DB, clients *service_clients.Clients) error {
 	g := r.Group("/player")
+	internalGroup := internal.Group("/player/internal")
 
 	characterRepo := character_repo.NewCharacterRepository(conf, db)
 	characterService := character_service.NewCharacterService(conf, characterRepo)
+	clients.ProvidePlayers(character_service.NewPlayersClient(characterService))
 	characterController := character_controller.NewCharacterController(conf, characterService)
 	worldAccessRepo := world_access_repo.NewWorldAccessRepository(conf, db)
 	worldAccessService := world_access_service.NewWorldAccessService(conf, worldAccessRepo, characterRepo)
 	worldAccessController := world_access_controller.NewWorldAccessController(conf, worldAccessService)
+	userDataClient := user_data_service.NewUserDataClient(user_data_repo.NewUserDataRepository(conf, db))
+	clients.ProvideUserData(service_clients.PlayersUserData, userDataClient)
 
 	characterGroup := g.Group("/character")
 	characterGroup.PATCH("", characterController.PatchCharacterInfo)
@@ -28,7 +37,13 @@ func SetupPlayerServiceRouter(r *gin.Engine, conf *config.Config, db *config.DB)
 
 	worldAccessGroup := g.Group("/world-access")
 	worldAccessGroup.POST("/token", worldAccessController.IssueWorldJoinToken)
-	worldAccessGroup.POST("/token/consume", worldAccessController.ConsumeWorldJoinToken)
+	worldAccessGroup.POST("/token/consume", middleware.ServerCheckMiddleware(), worldAccessController.ConsumeWorldJoinToken)
+
+	// Internal routes, only used when services are split out
+	internalGroup.GET("/characters", characterController.GetCharacterByNameInternal)
+	internalGroup.GET("/characters/:user_id", characterController.GetCharacterByUserInternal)
+	internalGroup.GET("/users/:user_id/data", common_handlers.ExportUserDataController(userDataClient))
+	internalGroup.DELETE("/users/:user_id/data", common_handlers.EraseUserDataController(userDataClient))
 
 	return nil
 }
@@ -1,6 +1,8 @@
 package character
 
 import (
+	"strings"
+
 	"github.com/FeedTheRealm-org/core-service/config"
 	"github.com/FeedTheRealm-org/core-service/internal/players-service/models"
 	"github.com/FeedTheRealm-org/core-service/internal/players-service/repositories/character"
@@ -55,3 +57,7 @@ func (cs *characterService) GetCharacterInfo(userId uuid.UUID) (*models.Characte
 	return info, categorySprites, nil
 
 }
+
+func (cs *characterService) GetCharacterInfoByName(characterName string) (*models.CharacterInfo, error) {
+	return cs.characterRepository.GetCharacterInfoByName(strings.TrimSpace(characterName))
+}
@@ -33,6 +33,7 @@ type fakeCharacterRepo struct {
 	returnSprites        []models.CategorySprite
 	lastDeleteUserID     uuid.UUID
 	lastDeleteCategoryID []uuid.UUID
+	lastCharacterName    string
 }
 
 func (f *fakeCharacterRepo) UpdateCharacterInfo(newCharacterInfo *models.CharacterInfo) error {
@@ -49,6 +50,15 @@ func (f *fakeCharacterRepo) GetCharacterInfo(userId uuid.UUID) (*models.Characte
 	return f.returnInfo, nil
 }
 
+func (f *fakeCharacterRepo) GetCharacterInfoByName(characterName string) (*models.CharacterInfo, error) {
+	f.getInfoCalled = true
+	f.lastCharacterName = characterName
+	if f.getInfoErr != nil {
+		return nil, f.getInfoErr
+	}
+	return f.returnInfo, nil
+}
+
 func (f *fakeCharacterRepo) UpdateCategorySprites(newCategorySprites []models.CategorySprite) error {
 	f.updateSpritesCalled = true
 	return f.updateSpritesErr
@@ -123,3 +133,14 @@ func TestCharacterService_GetCharacterInfo_PropagatesError(t *testing.T) {
 	_, _, err := svc.GetCharacterInfo(uuid.New())
 	assert.Error(t, err)
 }
+
+func TestCharacterService_GetCharacterInfoByName_TrimsName(t *testing.T) {
+	info := &models.CharacterInfo{UserId: uuid.New(), CharacterName: "Hero"}
+	repo := &fakeCharacterRepo{returnInfo: info}
+	svc := NewCharacterService(config.CreateConfig(), repo)
+
+	got, err := svc.GetCharacterInfoByName("  Hero ")
+	assert.NoError(t, err)
+	assert.Equal(t, info, got)
+	assert.Equal(t, "Hero", repo.lastCharacterName)
+}
@@ -0,0 +1,41 @@
+package character
+
+import (
+	player_errors "github.com/FeedTheRealm-org/core-service/internal/players-service/errors"
+	"github.com/FeedTheRealm-org/core-service/internal/players-service/models"
+	"github.com/FeedTheRealm-org/core-service/internal/service_clients"
+	"github.com/google/uuid"
+)
+
+type playersClient struct {
+	characterService CharacterService
+}
+
+// NewPlayersClient exposes the character service to the other services running in this process.
+func NewPlayersClient(characterService CharacterService) service_clients.PlayersClient {
+	return &playersClient{characterService: characterService}
+}
+
+func (c *playersClient) GetPlayerByCharacterName(characterName string) (*service_clients.PlayerInfo, error) {
+	return toPlayerInfo(c.characterService.GetCharacterInfoByName(characterName))
+}
+
+func (c *playersClient) GetPlayer(userId uuid.UUID) (*service_clients.PlayerInfo, error) {
+	info, _, err := c.characterService.GetCharacterInfo(userId)
+	return toPlayerInfo(info, err)
+}
+
+func toPlayerInfo(info *models.CharacterInfo, err error) (*service_clients.PlayerInfo, error) {
+	if err != nil {
+		switch err.(type) {
+		case *player_errors.CharacterInfoNotFound, *player_errors.CategorySpritesNotFound:
+			return nil, service_clients.NewPlayerNotFound("character not found")
+		}
+		return nil, err
+	}
+
+	return &service_clients.PlayerInfo{
+		UserId:        info.UserId,
+		CharacterName: info.CharacterName,
+	}, nil
+}
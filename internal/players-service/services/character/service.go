@@ -12,4 +12,7 @@ type CharacterService interface {
 
 	// GetCharacterInfo retrieves character information.
 	GetCharacterInfo(userId uuid.UUID) (*models.CharacterInfo, []models.CategorySprite, error)
+
+	// GetCharacterInfoByName retrieves the character information matching the character name.
+	GetCharacterInfoByName(characterName string) (*models.CharacterInfo, error)
 }
@@ -0,0 +1,30 @@
+package user_data
+
+import (
+	"encoding/json"
+
+	user_data_repo "github.com/FeedTheRealm-org/core-service/internal/players-service/repositories/user_data"
+	"github.com/FeedTheRealm-org/core-service/internal/service_clients"
+	"github.com/google/uuid"
+)
+
+type userDataClient struct {
+	repo user_data_repo.UserDataRepository
+}
+
+// NewUserDataClient exposes the export and erasure of the player data of a user to the authentication-service.
+func NewUserDataClient(repo user_data_repo.UserDataRepository) service_clients.UserDataClient {
+	return &userDataClient{repo: repo}
+}
+
+func (c *userDataClient) ExportUserData(userId uuid.UUID) (json.RawMessage, error) {
+	data, err := c.repo.GetUserData(userId)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(data)
+}
+
+func (c *userDataClient) EraseUserData(userId uuid.UUID) error {
+	return c.repo.DeleteUserData(userId)
+}
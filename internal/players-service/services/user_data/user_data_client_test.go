@@ -0,0 +1,50 @@
+package user_data
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/FeedTheRealm-org/core-service/internal/players-service/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeUserDataRepo struct {
+	data    *models.UserData
+	deleted []uuid.UUID
+}
+
+func (f *fakeUserDataRepo) GetUserData(userId uuid.UUID) (*models.UserData, error) {
+	return f.data, nil
+}
+
+func (f *fakeUserDataRepo) DeleteUserData(userId uuid.UUID) error {
+	f.deleted = append(f.deleted, userId)
+	return nil
+}
+
+func TestUserDataClient_ExportUserData(t *testing.T) {
+	userID := uuid.New()
+	repo := &fakeUserDataRepo{data: &models.UserData{
+		CharacterInfo:   &models.CharacterInfo{UserId: userID, CharacterName: "Hero"},
+		CategorySprites: []models.CategorySprite{},
+		WorldJoinTokens: []models.WorldJoinToken{},
+	}}
+
+	raw, err := NewUserDataClient(repo).ExportUserData(userID)
+	require.NoError(t, err)
+
+	var exported map[string]any
+	require.NoError(t, json.Unmarshal(raw, &exported))
+	assert.Equal(t, "Hero", exported["character_info"].(map[string]any)["character_name"])
+	assert.Empty(t, exported["category_sprites"])
+}
+
+func TestUserDataClient_EraseUserData(t *testing.T) {
+	userID := uuid.New()
+	repo := &fakeUserDataRepo{}
+
+	require.NoError(t, NewUserDataClient(repo).EraseUserData(userID))
+	assert.Equal(t, []uuid.UUID{userID}, repo.deleted)
+}
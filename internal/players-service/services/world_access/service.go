@@ -8,5 +8,6 @@ import (
 // WorldAccessService defines world join token business operations.
 type WorldAccessService interface {
 	IssueWorldJoinToken(userId uuid.UUID, worldId string) (*models.WorldJoinToken, error)
-	ConsumeWorldJoinToken(tokenId string) (*models.WorldJoinToken, error)
+	// ConsumeWorldJoinToken burns the token, servers scoped to a world pass it to only consume tokens issued for it.
+	ConsumeWorldJoinToken(tokenId string, worldId string) (*models.WorldJoinToken, error)
 }
@@ -56,11 +56,11 @@ func (ws *worldAccessService) IssueWorldJoinToken(userId uuid.UUID, worldId stri
 	return token, nil
 }
 
-func (ws *worldAccessService) ConsumeWorldJoinToken(tokenId string) (*models.WorldJoinToken, error) {
+func (ws *worldAccessService) ConsumeWorldJoinToken(tokenId string, worldId string) (*models.WorldJoinToken, error) {
 	tokenUUID, err := uuid.Parse(strings.TrimSpace(tokenId))
 	if err != nil {
 		return nil, player_errors.NewWorldJoinTokenInvalid("invalid token_id")
 	}
 
-	return ws.worldAccessRepository.ConsumeWorldJoinToken(tokenUUID, time.Now().UTC())
+	return ws.worldAccessRepository.ConsumeWorldJoinToken(tokenUUID, worldId, time.Now().UTC())
 }
@@ -13,12 +13,13 @@ import (
 )
 
 type fakeWorldAccessRepo struct {
-	createToken *models.WorldJoinToken
-	createErr   error
-	consumeID   uuid.UUID
-	consumeTime time.Time
-	consumeErr  error
-	consumeResp *models.WorldJoinToken
+	createToken    *models.WorldJoinToken
+	createErr      error
+	consumeID      uuid.UUID
+	consumeWorldID string
+	consumeTime    time.Time
+	consumeErr     error
+	consumeResp    *models.WorldJoinToken
 }
 
 func (f *fakeWorldAccessRepo) CreateWorldJoinToken(token *models.WorldJoinToken) error {
@@ -26,8 +27,9 @@ func (f *fakeWorldAccessRepo) CreateWorldJoinToken(token *models.WorldJoinToken)
 	return f.createErr
 }
 
-func (f *fakeWorldAccessRepo) ConsumeWorldJoinToken(tokenId uuid.UUID, now time.Time) (*models.WorldJoinToken, error) {
+func (f *fakeWorldAccessRepo) ConsumeWorldJoinToken(tokenId uuid.UUID, worldId string, now time.Time) (*models.WorldJoinToken, error) {
 	f.consumeID = tokenId
+	f.consumeWorldID = worldId
 	f.consumeTime = now
 	if f.consumeErr != nil {
 		return nil, f.consumeErr
@@ -50,6 +52,10 @@ func (f *fakeCharacterRepo) GetCharacterInfo(userId uuid.UUID) (*models.Characte
 	return &models.CharacterInfo{UserId: userId}, nil
 }
 
+func (f *fakeCharacterRepo) GetCharacterInfoByName(characterName string) (*models.CharacterInfo, error) {
+	return nil, player_errors.NewCharacterInfoNotFound("character not found")
+}
+
 func (f *fakeCharacterRepo) UpdateCategorySprites(newCategorySprites []models.CategorySprite) error {
 	return nil
 }
@@ -102,7 +108,7 @@ func TestWorldAccessService_ConsumeWorldJoinToken_InvalidTokenId(t *testing.T) {
 	characterRepo := &fakeCharacterRepo{}
 	svc := NewWorldAccessService(config.CreateConfig(), repo, characterRepo)
 
-	_, err := svc.ConsumeWorldJoinToken("not-a-uuid")
+	_, err := svc.ConsumeWorldJoinToken("not-a-uuid", "")
 	assert.Error(t, err)
 	var invalid *player_errors.WorldJoinTokenInvalid
 	assert.True(t, errors.As(err, &invalid))
@@ -114,8 +120,9 @@ func TestWorldAccessService_ConsumeWorldJoinToken_CallsRepo(t *testing.T) {
 	svc := NewWorldAccessService(config.CreateConfig(), repo, characterRepo)
 
 	id := uuid.New()
-	token, err := svc.ConsumeWorldJoinToken(id.String())
+	token, err := svc.ConsumeWorldJoinToken(id.String(), "world-1")
 	assert.NoError(t, err)
 	assert.Equal(t, repo.consumeResp, token)
 	assert.Equal(t, id, repo.consumeID)
+	assert.Equal(t, "world-1", repo.consumeWorldID)
 }
@@ -1,6 +1,8 @@
 package router
 
 import (
+	"fmt"
+
 	"github.com/FeedTheRealm-org/core-service/config"
 	assetsRouter "github.com/FeedTheRealm-org/core-service/internal/assets-service/router"
 	authRouter "github.com/FeedTheRealm-org/core-service/internal/authentication-service/router"
@@ -9,21 +11,33 @@ import (
 	"github.com/FeedTheRealm-org/core-service/internal/middleware"
 	paymentsRouter "github.com/FeedTheRealm-org/core-service/internal/payment-service/router"
 	playersRouter "github.com/FeedTheRealm-org/core-service/internal/players-service/router"
+	"github.com/FeedTheRealm-org/core-service/internal/service_clients"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/internal_auth"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/logger"
 	"github.com/FeedTheRealm-org/core-service/internal/utils/session"
 	worldRouter "github.com/FeedTheRealm-org/core-service/internal/world-service/router"
 	"github.com/gin-gonic/gin"
 )
 
-func SetupRouter(r *gin.Engine, conf *config.Config, db *config.DB) error {
-	jwtManager := session.NewJWTManager(conf.SessionAccessTokenSecretKey, conf.SessionRefreshTokenSecretKey, conf.SessionAccessTokenDuration, conf.SessionRefreshTokenDuration)
+// SetupRouter registers every service on r. Internal routes go to internalEngine,
+// which can be r itself or a separate engine served on its own port.
+func SetupRouter(r *gin.Engine, internalEngine *gin.Engine, conf *config.Config, db *config.DB) error {
+	signingKeys, err := sessionSigningKeys(conf)
+	if err != nil {
+		return err
+	}
+	jwtManager := session.NewJWTManager(signingKeys, conf.SessionRefreshTokenSecretKey, conf.SessionAccessTokenDuration, conf.SessionRefreshTokenDuration)
+	signer := internal_auth.NewRequestSigner(conf.InternalAuthSecret, conf.InternalAuthMaxSkew)
+	clients := newServiceClients(conf, signer)
 
 	// Setup global middleware
+	r.Use(middleware.RequestIDMiddleware())
 	r.Use(middleware.ErrorHandlerMiddleware())
 	r.Use(middleware.MultipartCleanupMiddleware())
 
 	r.Use(middleware.CORSMiddleware(conf))
 
-	r.Use(middleware.JWTAuthMiddleware(jwtManager, conf.ServerFixedToken))
+	r.Use(middleware.JWTAuthMiddleware(jwtManager, serverFixedToken(conf), clients.Servers))
 
 	// Setup service routers
 	r.NoRoute(common_handlers.NotFoundController)
@@ -31,27 +45,29 @@ func SetupRouter(r *gin.Engine, conf *config.Config, db *config.DB) error {
 	// Health check
 	r.GET("/health", common_handlers.HealthController)
 
-	if err := authRouter.SetupAuthenticationServiceRouter(r, conf, db, jwtManager); err != nil {
+	internal := setupInternalRouter(r, internalEngine, signer)
+
+	if err := authRouter.SetupAuthenticationServiceRouter(r, internal, conf, db, jwtManager, clients); err != nil {
 		return err
 	}
 
-	if err := paymentsRouter.SetupPaymentServiceRouter(r, conf, db); err != nil {
+	if err := paymentsRouter.SetupPaymentServiceRouter(r, internal, conf, db, clients); err != nil {
 		return err
 	}
 
-	if err := playersRouter.SetupPlayerServiceRouter(r, conf, db); err != nil {
+	if err := playersRouter.SetupPlayerServiceRouter(r, internal, conf, db, clients); err != nil {
 		return err
 	}
 
-	if err := worldRouter.SetupWorldServiceRouter(r, conf, db); err != nil {
+	if err := worldRouter.SetupWorldServiceRouter(r, internal, conf, db, clients); err != nil {
 		return err
 	}
 
-	if err := assetsRouter.SetupAssetsServiceRouter(r, conf, db); err != nil {
+	if err := assetsRouter.SetupAssetsServiceRouter(r, internal, conf, db, clients); err != nil {
 		return err
 	}
 
-	if err := exportsRouter.SetupExportsServiceRouter(r, conf, db); err != nil {
+	if err := exportsRouter.SetupExportsServiceRouter(r, conf, db, clients); err != nil {
 		return err
 	}
 
@@ -59,3 +75,53 @@ func SetupRouter(r *gin.Engine, conf *config.Config, db *config.DB) error {
 
 	return nil
 }
+
+// newServiceClients selects how services reach each other: directly in this
+// process (monolith) or through their internal HTTP routes (split services).
+func newServiceClients(conf *config.Config, signer *internal_auth.RequestSigner) *service_clients.Clients {
+	if conf.ServiceClients.Mode == config.HTTPClients {
+		return service_clients.NewHTTPClients(conf.ServiceClients, signer)
+	}
+	return service_clients.NewInProcessClients()
+}
+
+// setupInternalRouter returns the group every service registers its internal routes on.
+// Requests must be signed by another service regardless of the engine they are served from.
+func setupInternalRouter(r *gin.Engine, internalEngine *gin.Engine, signer *internal_auth.RequestSigner) *gin.RouterGroup {
+	if internalEngine != r {
+		internalEngine.Use(middleware.ErrorHandlerMiddleware())
+		internalEngine.NoRoute(common_handlers.NotFoundController)
+		internalEngine.GET("/health", common_handlers.HealthController)
+	}
+
+	return internalEngine.Group("", middleware.InternalAuthMiddleware(signer))
+}
+
+// serverFixedToken returns the shared game server token when the deprecated fallback is enabled.
+// Game servers should use the per-zone credential issued when their job starts instead.
+func serverFixedToken(conf *config.Config) string {
+	if !conf.ServerFixedTokenEnabled || conf.ServerFixedToken == "" {
+		return ""
+	}
+	logger.Logger.Warn("SERVER_FIXED_TOKEN_ENABLED is deprecated, game servers should authenticate with their per-zone credential")
+	return conf.ServerFixedToken
+}
+
+// sessionSigningKeys loads the keys access tokens are signed with. Outside production a
+// missing keys dir falls back to a throwaway key, tokens it signs die with the process.
+// The authentication service is always served, so the keys must be able to sign.
+func sessionSigningKeys(conf *config.Config) (*session.SigningKeys, error) {
+	if conf.SessionSigningKeysDir == "" && conf.Server.Environment != config.Production {
+		logger.Logger.Warn("SESSION_SIGNING_KEYS_DIR is not set, signing access tokens with a throwaway key")
+		return session.NewEphemeralSigningKeys(), nil
+	}
+
+	keys, err := session.LoadSigningKeys(conf.SessionSigningKeysDir, conf.SessionSigningKeyId)
+	if err != nil {
+		return nil, err
+	}
+	if !keys.CanSign() {
+		return nil, fmt.Errorf("SESSION_SIGNING_KEY_ID must name the key in %s that signs access tokens", conf.SessionSigningKeysDir)
+	}
+	return keys, nil
+}
@@ -0,0 +1,41 @@
+package router
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/FeedTheRealm-org/core-service/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeSigningKey(t *testing.T, dir string, id string) {
+	t.Helper()
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, id+".pem"), pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600))
+}
+
+func TestSessionSigningKeys_RequiresActiveKey(t *testing.T) {
+	dir := t.TempDir()
+	writeSigningKey(t, dir, "2026-10")
+
+	conf := &config.Config{
+		Server:                &config.ServerConfig{Environment: config.Production},
+		SessionSigningKeysDir: dir,
+	}
+	_, err := sessionSigningKeys(conf)
+	assert.Error(t, err)
+
+	conf.SessionSigningKeyId = "2026-10"
+	keys, err := sessionSigningKeys(conf)
+	require.NoError(t, err)
+	assert.True(t, keys.CanSign())
+}
@@ -13,9 +13,10 @@ import (
 )
 
 type Server struct {
-	conf *config.Config
-	db   *config.DB
-	srv  *http.Server
+	conf        *config.Config
+	db          *config.DB
+	srv         *http.Server
+	internalSrv *http.Server
 }
 
 func NewServer(conf *config.Config) (*Server, error) {
@@ -44,16 +45,42 @@ func (s *Server) Start() error {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
-	r := gin.Default()
+	r, err := newEngine(s.conf)
+	if err != nil {
+		return err
+	}
 	multipartMemoryBytes := int64(8 << 20)
 	if s.conf.Assets != nil && s.conf.Assets.MultipartMemoryBytes > 0 {
 		multipartMemoryBytes = s.conf.Assets.MultipartMemoryBytes
 	}
 	r.MaxMultipartMemory = multipartMemoryBytes
-	if err := router.SetupRouter(r, s.conf, s.db); err != nil {
+
+	// Internal routes get their own listener when configured, so they can be kept off the public network
+	internalEngine := r
+	if s.conf.Server.InternalPort > 0 {
+		if internalEngine, err = newEngine(s.conf); err != nil {
+			return err
+		}
+	}
+
+	if err := router.SetupRouter(r, internalEngine, s.conf, s.db); err != nil {
 		return err
 	}
 
+	if internalEngine != r {
+		s.internalSrv = &http.Server{
+			Addr:    "0.0.0.0:" + strconv.Itoa(s.conf.Server.InternalPort),
+			Handler: internalEngine,
+		}
+
+		go func() {
+			logger.Logger.Info("Starting internal server on port " + strconv.Itoa(s.conf.Server.InternalPort))
+			if err := s.internalSrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				logger.Logger.Errorf("Internal server failed: %v", err)
+			}
+		}()
+	}
+
 	s.srv = &http.Server{
 		Addr:    "0.0.0.0:" + strconv.Itoa(s.conf.Server.Port),
 		Handler: r,
@@ -68,6 +95,17 @@ func (s *Server) Start() error {
 	return nil
 }
 
+// newEngine only reads the client IP from X-Forwarded-For when the request comes from a trusted proxy,
+// otherwise any client could pick the IP used by the rate limits.
+func newEngine(conf *config.Config) (*gin.Engine, error) {
+	engine := gin.Default()
+	if err := engine.SetTrustedProxies(conf.Server.TrustedProxies); err != nil {
+		logger.Logger.Errorf("Invalid trusted proxies: %v", err)
+		return nil, err
+	}
+	return engine, nil
+}
+
 func (s *Server) Shutdown() {
 	logger.Logger.Info("Shutting down server")
 	ctx, cancel := context.WithTimeout(context.Background(), s.conf.Server.ShutdownTimeout)
@@ -79,6 +117,12 @@ func (s *Server) Shutdown() {
 		logger.Logger.Info("Database connection closed")
 	}
 
+	if s.internalSrv != nil {
+		if err := s.internalSrv.Shutdown(ctx); err != nil {
+			logger.Logger.Errorf("Internal server forced to shutdown: %v", err)
+		}
+	}
+
 	if err := s.srv.Shutdown(ctx); err != nil {
 		logger.Logger.Errorf("Server forced to shutdown: %v", err)
 	} else {
@@ -0,0 +1,136 @@
+package service_clients
+
+// SubscriptionNotFound is returned when the user has no subscription to check.
+type SubscriptionNotFound struct {
+	details string
+}
+
+func (e *SubscriptionNotFound) Error() string {
+	return e.details
+}
+
+func NewSubscriptionNotFound(details string) *SubscriptionNotFound {
+	return &SubscriptionNotFound{
+		details: details,
+	}
+}
+
+// CosmeticNotFound is returned when the requested cosmetic does not exist.
+type CosmeticNotFound struct {
+	details string
+}
+
+func (e *CosmeticNotFound) Error() string {
+	return e.details
+}
+
+func NewCosmeticNotFound(details string) *CosmeticNotFound {
+	return &CosmeticNotFound{
+		details: details,
+	}
+}
+
+// CosmeticAlreadyOwned is returned when the user already owns the cosmetic being granted.
+type CosmeticAlreadyOwned struct {
+	details string
+}
+
+func (e *CosmeticAlreadyOwned) Error() string {
+	return e.details
+}
+
+func NewCosmeticAlreadyOwned(details string) *CosmeticAlreadyOwned {
+	return &CosmeticAlreadyOwned{
+		details: details,
+	}
+}
+
+// BundleNotFound is returned when the requested bundle does not exist.
+type BundleNotFound struct {
+	details string
+}
+
+func (e *BundleNotFound) Error() string {
+	return e.details
+}
+
+func NewBundleNotFound(details string) *BundleNotFound {
+	return &BundleNotFound{
+		details: details,
+	}
+}
+
+// BundleAlreadyOwned is returned when the user already owns every cosmetic of the bundle being granted.
+type BundleAlreadyOwned struct {
+	details string
+}
+
+func (e *BundleAlreadyOwned) Error() string {
+	return e.details
+}
+
+func NewBundleAlreadyOwned(details string) *BundleAlreadyOwned {
+	return &BundleAlreadyOwned{
+		details: details,
+	}
+}
+
+// PlayerNotFound is returned when no character matches the requested name or user.
+type PlayerNotFound struct {
+	details string
+}
+
+func (e *PlayerNotFound) Error() string {
+	return e.details
+}
+
+func NewPlayerNotFound(details string) *PlayerNotFound {
+	return &PlayerNotFound{
+		details: details,
+	}
+}
+
+// AccountNotFound is returned when the requested account does not exist.
+type AccountNotFound struct {
+	details string
+}
+
+func (e *AccountNotFound) Error() string {
+	return e.details
+}
+
+func NewAccountNotFound(details string) *AccountNotFound {
+	return &AccountNotFound{
+		details: details,
+	}
+}
+
+// ServerCredentialInvalid is returned when a game server token was never issued or has been revoked.
+type ServerCredentialInvalid struct {
+	details string
+}
+
+func (e *ServerCredentialInvalid) Error() string {
+	return e.details
+}
+
+func NewServerCredentialInvalid(details string) *ServerCredentialInvalid {
+	return &ServerCredentialInvalid{
+		details: details,
+	}
+}
+
+// ServiceUnavailable is returned when the target service cannot be reached.
+type ServiceUnavailable struct {
+	details string
+}
+
+func (e *ServiceUnavailable) Error() string {
+	return e.details
+}
+
+func NewServiceUnavailable(details string) *ServiceUnavailable {
+	return &ServiceUnavailable{
+		details: details,
+	}
+}
@@ -0,0 +1,505 @@
+package service_clients
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/FeedTheRealm-org/core-service/internal/dtos"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/internal_auth"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/logger"
+	"github.com/google/uuid"
+)
+
+type slotsAvailabilityResponse struct {
+	Allowed   bool `json:"allowed"`
+	FreeSlots int  `json:"free_slots"`
+}
+
+type updateUsedSlotsRequest struct {
+	Slots   int  `json:"slots"`
+	AreUsed bool `json:"are_used"`
+}
+
+type updateCustomerEmailRequest struct {
+	Email string `json:"email"`
+}
+
+type cosmeticResponse struct {
+	CosmeticId    uuid.UUID `json:"cosmetic_id"`
+	CosmeticPrice int64     `json:"cosmetic_price"`
+	CreatedBy     uuid.UUID `json:"created_by"`
+	WorldId       uuid.UUID `json:"world_id"`
+}
+
+type grantCosmeticRequest struct {
+	CosmeticId uuid.UUID  `json:"cosmetic_id"`
+	GiftedBy   *uuid.UUID `json:"gifted_by,omitempty"`
+}
+
+type bundleResponse struct {
+	BundleId    uuid.UUID   `json:"bundle_id"`
+	BundlePrice int64       `json:"bundle_price"`
+	CreatedBy   uuid.UUID   `json:"created_by"`
+	WorldId     uuid.UUID   `json:"world_id"`
+	CosmeticIds []uuid.UUID `json:"cosmetic_ids"`
+}
+
+type grantBundleRequest struct {
+	BundleId uuid.UUID `json:"bundle_id"`
+}
+
+type grantBundleResponse struct {
+	GrantedCosmeticIds []uuid.UUID `json:"granted_cosmetic_ids"`
+}
+
+type playerResponse struct {
+	UserId        uuid.UUID `json:"user_id"`
+	CharacterName string    `json:"character_name"`
+}
+
+type accountEmailResponse struct {
+	UserId uuid.UUID `json:"user_id"`
+	Email  string    `json:"email"`
+}
+
+/* --- Subscriptions --- */
+
+type httpSubscriptionsClient struct {
+	baseURL    string
+	httpClient *http.Client
+	signer     *internal_auth.RequestSigner
+}
+
+func (c *httpSubscriptionsClient) CheckAvailability(userId uuid.UUID) (*SlotsAvailability, error) {
+	url := fmt.Sprintf("%s/subscriptions/internal/users/%s/status", c.baseURL, userId)
+	resp, err := doRequest(c.httpClient, c.signer, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, NewServiceUnavailable("failed to reach payment service to verify slots")
+	}
+	defer closeBody(resp)
+
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusNotFound {
+		return nil, NewSubscriptionNotFound("user does not have an active subscription")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to reach payment service to verify slots: status %d", resp.StatusCode)
+	}
+
+	var envelope dtos.DataEnvelope[slotsAvailabilityResponse]
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return nil, fmt.Errorf("failed to decode slots response: %w", err)
+	}
+
+	return &SlotsAvailability{
+		Allowed:   envelope.Data.Allowed,
+		FreeSlots: envelope.Data.FreeSlots,
+	}, nil
+}
+
+func (c *httpSubscriptionsClient) UpdateUsedSlots(userId uuid.UUID, slots int, areUsed bool) error {
+	url := fmt.Sprintf("%s/subscriptions/internal/users/%s/used-slots", c.baseURL, userId)
+	resp, err := doRequest(c.httpClient, c.signer, http.MethodPut, url, updateUsedSlotsRequest{Slots: slots, AreUsed: areUsed})
+	if err != nil {
+		return NewServiceUnavailable("failed to reach payment service to update used slots")
+	}
+	defer closeBody(resp)
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to update used slots, payment service returned status: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+func (c *httpSubscriptionsClient) CancelSubscription(userId uuid.UUID) error {
+	url := fmt.Sprintf("%s/subscriptions/internal/users/%s", c.baseURL, userId)
+	resp, err := doRequest(c.httpClient, c.signer, http.MethodDelete, url, nil)
+	if err != nil {
+		return NewServiceUnavailable("failed to reach payment service to cancel the subscription")
+	}
+	defer closeBody(resp)
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("failed to cancel subscription, payment service returned status: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+func (c *httpSubscriptionsClient) UpdateCustomerEmail(userId uuid.UUID, email string) error {
+	url := fmt.Sprintf("%s/subscriptions/internal/users/%s/email", c.baseURL, userId)
+	resp, err := doRequest(c.httpClient, c.signer, http.MethodPut, url, updateCustomerEmailRequest{Email: email})
+	if err != nil {
+		return NewServiceUnavailable("failed to reach payment service to update the customer email")
+	}
+	defer closeBody(resp)
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("failed to update customer email, payment service returned status: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+/* --- Assets --- */
+
+type httpAssetsClient struct {
+	baseURL    string
+	httpClient *http.Client
+	signer     *internal_auth.RequestSigner
+}
+
+func (c *httpAssetsClient) GetCosmetic(cosmeticId uuid.UUID) (*CosmeticInfo, error) {
+	url := fmt.Sprintf("%s/assets/internal/cosmetics/%s", c.baseURL, cosmeticId)
+	resp, err := doRequest(c.httpClient, c.signer, http.MethodGet, url, nil)
+	if err != nil {
+		logger.Logger.Error("Failed to fetch cosmetic details: " + err.Error())
+		return nil, NewServiceUnavailable("failed to reach assets service to fetch cosmetic")
+	}
+	defer closeBody(resp)
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, NewCosmeticNotFound("cosmetic not found")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get cosmetic, status code: %d", resp.StatusCode)
+	}
+
+	var envelope dtos.DataEnvelope[cosmeticResponse]
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return nil, fmt.Errorf("failed to decode cosmetic response: %w", err)
+	}
+
+	return &CosmeticInfo{
+		CosmeticId: envelope.Data.CosmeticId,
+		Price:      envelope.Data.CosmeticPrice,
+		CreatedBy:  envelope.Data.CreatedBy,
+		WorldID:    envelope.Data.WorldId,
+	}, nil
+}
+
+func (c *httpAssetsClient) GrantCosmetic(userId uuid.UUID, cosmeticId uuid.UUID) error {
+	return c.grantCosmetic(userId, grantCosmeticRequest{CosmeticId: cosmeticId})
+}
+
+func (c *httpAssetsClient) GiftCosmetic(recipientId uuid.UUID, cosmeticId uuid.UUID, gifterId uuid.UUID) error {
+	return c.grantCosmetic(recipientId, grantCosmeticRequest{CosmeticId: cosmeticId, GiftedBy: &gifterId})
+}
+
+func (c *httpAssetsClient) grantCosmetic(userId uuid.UUID, body grantCosmeticRequest) error {
+	url := fmt.Sprintf("%s/assets/internal/users/%s/cosmetics", c.baseURL, userId)
+	resp, err := doRequest(c.httpClient, c.signer, http.MethodPost, url, body)
+	if err != nil {
+		logger.Logger.Error("Failed to issue cosmetic purchase: " + err.Error())
+		return NewServiceUnavailable("failed to reach assets service to record purchase")
+	}
+	defer closeBody(resp)
+
+	if resp.StatusCode == http.StatusConflict {
+		return NewCosmeticAlreadyOwned("cosmetic was already purchased by this user")
+	}
+	if resp.StatusCode != http.StatusCreated {
+		return fmt.Errorf("failed to record cosmetic purchase, status code: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+func (c *httpAssetsClient) GetBundle(bundleId uuid.UUID) (*BundleInfo, error) {
+	url := fmt.Sprintf("%s/assets/internal/bundles/%s", c.baseURL, bundleId)
+	resp, err := doRequest(c.httpClient, c.signer, http.MethodGet, url, nil)
+	if err != nil {
+		logger.Logger.Error("Failed to fetch bundle details: " + err.Error())
+		return nil, NewServiceUnavailable("failed to reach assets service to fetch bundle")
+	}
+	defer closeBody(resp)
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, NewBundleNotFound("bundle not found")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get bundle, status code: %d", resp.StatusCode)
+	}
+
+	var envelope dtos.DataEnvelope[bundleResponse]
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return nil, fmt.Errorf("failed to decode bundle response: %w", err)
+	}
+
+	return &BundleInfo{
+		BundleId:    envelope.Data.BundleId,
+		Price:       envelope.Data.BundlePrice,
+		CreatedBy:   envelope.Data.CreatedBy,
+		WorldID:     envelope.Data.WorldId,
+		CosmeticIds: envelope.Data.CosmeticIds,
+	}, nil
+}
+
+func (c *httpAssetsClient) GrantBundle(userId uuid.UUID, bundleId uuid.UUID) ([]uuid.UUID, error) {
+	url := fmt.Sprintf("%s/assets/internal/users/%s/bundles", c.baseURL, userId)
+	resp, err := doRequest(c.httpClient, c.signer, http.MethodPost, url, grantBundleRequest{BundleId: bundleId})
+	if err != nil {
+		logger.Logger.Error("Failed to issue bundle purchase: " + err.Error())
+		return nil, NewServiceUnavailable("failed to reach assets service to record purchase")
+	}
+	defer closeBody(resp)
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, NewBundleNotFound("bundle not found")
+	}
+	if resp.StatusCode == http.StatusConflict {
+		return nil, NewBundleAlreadyOwned("every cosmetic of the bundle is already owned by this user")
+	}
+	if resp.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("failed to record bundle purchase, status code: %d", resp.StatusCode)
+	}
+
+	var envelope dtos.DataEnvelope[grantBundleResponse]
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return nil, fmt.Errorf("failed to decode bundle purchase response: %w", err)
+	}
+
+	return envelope.Data.GrantedCosmeticIds, nil
+}
+
+/* --- World jobs --- */
+
+type httpWorldJobsClient struct {
+	baseURL    string
+	httpClient *http.Client
+	signer     *internal_auth.RequestSigner
+}
+
+func (c *httpWorldJobsClient) StopAllJobsForUser(userId uuid.UUID) error {
+	url := fmt.Sprintf("%s/world/internal/users/%s/stop-jobs", c.baseURL, userId)
+	resp, err := doRequest(c.httpClient, c.signer, http.MethodGet, url, nil)
+	if err != nil {
+		logger.Logger.Errorf("Failed to send stop-jobs internal request for user %s: %v", userId, err)
+		return NewServiceUnavailable("failed to reach world service to stop jobs")
+	}
+	defer closeBody(resp)
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to stop jobs for user %s, status code: %d", userId, resp.StatusCode)
+	}
+
+	return nil
+}
+
+/* --- Server credentials --- */
+
+type verifyServerTokenRequest struct {
+	Token string `json:"token"`
+}
+
+type serverScopeResponse struct {
+	WorldId uuid.UUID `json:"world_id"`
+	ZoneId  int       `json:"zone_id"`
+}
+
+type httpServerCredentialsClient struct {
+	baseURL    string
+	httpClient *http.Client
+	signer     *internal_auth.RequestSigner
+}
+
+func (c *httpServerCredentialsClient) VerifyServerToken(token string) (*ServerScope, error) {
+	endpoint := fmt.Sprintf("%s/world/internal/servers/verify", c.baseURL)
+	resp, err := doRequest(c.httpClient, c.signer, http.MethodPost, endpoint, verifyServerTokenRequest{Token: token})
+	if err != nil {
+		logger.Logger.Error("Failed to verify server token: " + err.Error())
+		return nil, NewServiceUnavailable("failed to reach world service to verify server token")
+	}
+	defer closeBody(resp)
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return nil, NewServerCredentialInvalid("invalid server token")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to verify server token, status code: %d", resp.StatusCode)
+	}
+
+	var envelope dtos.DataEnvelope[serverScopeResponse]
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return nil, fmt.Errorf("failed to decode server token response: %w", err)
+	}
+
+	return &ServerScope{WorldId: envelope.Data.WorldId, ZoneId: envelope.Data.ZoneId}, nil
+}
+
+/* --- Players --- */
+
+type httpPlayersClient struct {
+	baseURL    string
+	httpClient *http.Client
+	signer     *internal_auth.RequestSigner
+}
+
+func (c *httpPlayersClient) GetPlayerByCharacterName(characterName string) (*PlayerInfo, error) {
+	return c.getPlayer(fmt.Sprintf("%s/player/internal/characters?character_name=%s", c.baseURL, url.QueryEscape(characterName)))
+}
+
+func (c *httpPlayersClient) GetPlayer(userId uuid.UUID) (*PlayerInfo, error) {
+	return c.getPlayer(fmt.Sprintf("%s/player/internal/characters/%s", c.baseURL, userId))
+}
+
+func (c *httpPlayersClient) getPlayer(endpoint string) (*PlayerInfo, error) {
+	resp, err := doRequest(c.httpClient, c.signer, http.MethodGet, endpoint, nil)
+	if err != nil {
+		logger.Logger.Error("Failed to fetch player: " + err.Error())
+		return nil, NewServiceUnavailable("failed to reach players service to fetch character")
+	}
+	defer closeBody(resp)
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, NewPlayerNotFound("character not found")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get character, status code: %d", resp.StatusCode)
+	}
+
+	var envelope dtos.DataEnvelope[playerResponse]
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return nil, fmt.Errorf("failed to decode character response: %w", err)
+	}
+
+	return &PlayerInfo{
+		UserId:        envelope.Data.UserId,
+		CharacterName: envelope.Data.CharacterName,
+	}, nil
+}
+
+/* --- Accounts --- */
+
+type httpAccountsClient struct {
+	baseURL    string
+	httpClient *http.Client
+	signer     *internal_auth.RequestSigner
+}
+
+func (c *httpAccountsClient) GetAccountEmail(userId uuid.UUID) (string, error) {
+	endpoint := fmt.Sprintf("%s/auth/internal/users/%s", c.baseURL, userId)
+	resp, err := doRequest(c.httpClient, c.signer, http.MethodGet, endpoint, nil)
+	if err != nil {
+		logger.Logger.Error("Failed to fetch account email: " + err.Error())
+		return "", NewServiceUnavailable("failed to reach authentication service to fetch account")
+	}
+	defer closeBody(resp)
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "", NewAccountNotFound("account not found")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to get account, status code: %d", resp.StatusCode)
+	}
+
+	var envelope dtos.DataEnvelope[accountEmailResponse]
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return "", fmt.Errorf("failed to decode account response: %w", err)
+	}
+
+	return envelope.Data.Email, nil
+}
+
+/* --- Audit --- */
+
+type httpAuditClient struct {
+	baseURL    string
+	httpClient *http.Client
+	signer     *internal_auth.RequestSigner
+}
+
+func (c *httpAuditClient) RecordEvent(event AuditEvent) error {
+	endpoint := fmt.Sprintf("%s/auth/internal/audit", c.baseURL)
+	resp, err := doRequest(c.httpClient, c.signer, http.MethodPost, endpoint, event)
+	if err != nil {
+		return NewServiceUnavailable("failed to reach authentication service to record an audit event")
+	}
+	defer closeBody(resp)
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("failed to record audit event, authentication service returned status: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+/* --- User data --- */
+
+type httpUserDataClient struct {
+	baseURL    string
+	service    string
+	httpClient *http.Client
+	signer     *internal_auth.RequestSigner
+}
+
+func (c *httpUserDataClient) ExportUserData(userId uuid.UUID) (json.RawMessage, error) {
+	url := fmt.Sprintf("%s/internal/users/%s/data", c.baseURL, userId)
+	resp, err := doRequest(c.httpClient, c.signer, http.MethodGet, url, nil)
+	if err != nil {
+		logger.Logger.Errorf("Failed to export user data of user %s from %s service: %v", userId, c.service, err)
+		return nil, NewServiceUnavailable("failed to reach " + c.service + " service to export user data")
+	}
+	defer closeBody(resp)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to export user data from %s service, status code: %d", c.service, resp.StatusCode)
+	}
+
+	var envelope dtos.DataEnvelope[json.RawMessage]
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return nil, fmt.Errorf("failed to decode user data response: %w", err)
+	}
+
+	return envelope.Data, nil
+}
+
+func (c *httpUserDataClient) EraseUserData(userId uuid.UUID) error {
+	url := fmt.Sprintf("%s/internal/users/%s/data", c.baseURL, userId)
+	resp, err := doRequest(c.httpClient, c.signer, http.MethodDelete, url, nil)
+	if err != nil {
+		logger.Logger.Errorf("Failed to erase user data of user %s from %s service: %v", userId, c.service, err)
+		return NewServiceUnavailable("failed to reach " + c.service + " service to erase user data")
+	}
+	defer closeBody(resp)
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("failed to erase user data from %s service, status code: %d", c.service, resp.StatusCode)
+	}
+
+	return nil
+}
+
+/* --- UTILS --- */
+
+func doRequest(httpClient *http.Client, signer *internal_auth.RequestSigner, method string, url string, body any) (*http.Response, error) {
+	var jsonData []byte
+	if body != nil {
+		var err error
+		jsonData, err = json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	req, err := http.NewRequest(method, url, bytes.NewReader(jsonData))
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	signer.SignRequest(req, jsonData, time.Now())
+
+	return httpClient.Do(req)
+}
+
+func closeBody(resp *http.Response) {
+	if err := resp.Body.Close(); err != nil {
+		logger.Logger.Error("Failed to close response body: " + err.Error())
+	}
+}
@@ -0,0 +1,416 @@
+package service_clients
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/FeedTheRealm-org/core-service/config"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/internal_auth"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/logger"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	logger.InitLogger(false)
+	os.Exit(m.Run())
+}
+
+var testSigner = internal_auth.NewRequestSigner("test-internal-secret", time.Minute)
+
+func newTestHTTPClients(url string) *Clients {
+	return NewHTTPClients(&config.ServiceClientsConfig{
+		Mode:        config.HTTPClients,
+		AssetsURL:   url,
+		PaymentsURL: url,
+		WorldURL:    url,
+		PlayersURL:  url,
+		AuthURL:     url,
+		Timeout:     time.Second,
+	}, testSigner)
+}
+
+func TestHTTPClients_SignRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		err := testSigner.Verify(r.Method, r.URL.RequestURI(), body, r.Header.Get(internal_auth.TIMESTAMP_HEADER), r.Header.Get(internal_auth.SIGNATURE_HEADER), time.Now())
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	assert.NoError(t, newTestHTTPClients(server.URL).Subscriptions.UpdateUsedSlots(uuid.New(), 1, true))
+}
+
+func TestHTTPSubscriptionsClient_CheckAvailability_Success(t *testing.T) {
+	userID := uuid.New()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/subscriptions/internal/users/"+userID.String()+"/status", r.URL.Path)
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"data": map[string]any{"allowed": true, "free_slots": 3},
+		})
+	}))
+	defer server.Close()
+
+	availability, err := newTestHTTPClients(server.URL).Subscriptions.CheckAvailability(userID)
+	require.NoError(t, err)
+	assert.True(t, availability.Allowed)
+	assert.Equal(t, 3, availability.FreeSlots)
+}
+
+func TestHTTPSubscriptionsClient_CheckAvailability_Unauthorized(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	_, err := newTestHTTPClients(server.URL).Subscriptions.CheckAvailability(uuid.New())
+	var notFound *SubscriptionNotFound
+	assert.ErrorAs(t, err, &notFound)
+}
+
+func TestHTTPSubscriptionsClient_CheckAvailability_ServerError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	_, err := newTestHTTPClients(server.URL).Subscriptions.CheckAvailability(uuid.New())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to reach payment service")
+}
+
+func TestHTTPSubscriptionsClient_CheckAvailability_DecodeError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("not-json"))
+	}))
+	defer server.Close()
+
+	_, err := newTestHTTPClients(server.URL).Subscriptions.CheckAvailability(uuid.New())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to decode")
+}
+
+func TestHTTPSubscriptionsClient_ConnectionError(t *testing.T) {
+	clients := newTestHTTPClients("http://127.0.0.1:1")
+
+	_, err := clients.Subscriptions.CheckAvailability(uuid.New())
+	var unavailable *ServiceUnavailable
+	assert.ErrorAs(t, err, &unavailable)
+
+	err = clients.Subscriptions.UpdateUsedSlots(uuid.New(), 1, true)
+	assert.ErrorAs(t, err, &unavailable)
+}
+
+func TestHTTPSubscriptionsClient_UpdateUsedSlots(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		var body updateUsedSlotsRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, updateUsedSlotsRequest{Slots: 2, AreUsed: true}, body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	assert.NoError(t, newTestHTTPClients(server.URL).Subscriptions.UpdateUsedSlots(uuid.New(), 2, true))
+}
+
+func TestHTTPAssetsClient_GetCosmetic(t *testing.T) {
+	cosmeticID := uuid.New()
+	creatorID := uuid.New()
+	worldID := uuid.New()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"data": map[string]any{"cosmetic_id": cosmeticID, "cosmetic_price": 25, "created_by": creatorID, "world_id": worldID},
+		})
+	}))
+	defer server.Close()
+
+	cosmetic, err := newTestHTTPClients(server.URL).Assets.GetCosmetic(cosmeticID)
+	require.NoError(t, err)
+	assert.Equal(t, &CosmeticInfo{CosmeticId: cosmeticID, Price: 25, CreatedBy: creatorID, WorldID: worldID}, cosmetic)
+}
+
+func TestHTTPAssetsClient_GetCosmetic_NotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	_, err := newTestHTTPClients(server.URL).Assets.GetCosmetic(uuid.New())
+	var notFound *CosmeticNotFound
+	assert.ErrorAs(t, err, &notFound)
+}
+
+func TestHTTPAssetsClient_GrantCosmetic_Conflict(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+	}))
+	defer server.Close()
+
+	err := newTestHTTPClients(server.URL).Assets.GrantCosmetic(uuid.New(), uuid.New())
+	var owned *CosmeticAlreadyOwned
+	assert.ErrorAs(t, err, &owned)
+}
+
+func TestHTTPAssetsClient_GrantCosmetic_BadStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	err := newTestHTTPClients(server.URL).Assets.GrantCosmetic(uuid.New(), uuid.New())
+	assert.Error(t, err)
+}
+
+func TestHTTPAssetsClient_GiftCosmetic(t *testing.T) {
+	recipientID := uuid.New()
+	gifterID := uuid.New()
+	cosmeticID := uuid.New()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/assets/internal/users/"+recipientID.String()+"/cosmetics", r.URL.Path)
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		assert.Equal(t, cosmeticID.String(), body["cosmetic_id"])
+		assert.Equal(t, gifterID.String(), body["gifted_by"])
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	assert.NoError(t, newTestHTTPClients(server.URL).Assets.GiftCosmetic(recipientID, cosmeticID, gifterID))
+}
+
+func TestHTTPAssetsClient_GetBundle(t *testing.T) {
+	bundleID := uuid.New()
+	creatorID := uuid.New()
+	worldID := uuid.New()
+	cosmeticIDs := []uuid.UUID{uuid.New(), uuid.New()}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/assets/internal/bundles/"+bundleID.String(), r.URL.Path)
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"data": map[string]any{"bundle_id": bundleID, "bundle_price": 40, "created_by": creatorID, "world_id": worldID, "cosmetic_ids": cosmeticIDs},
+		})
+	}))
+	defer server.Close()
+
+	bundle, err := newTestHTTPClients(server.URL).Assets.GetBundle(bundleID)
+	require.NoError(t, err)
+	assert.Equal(t, &BundleInfo{BundleId: bundleID, Price: 40, CreatedBy: creatorID, WorldID: worldID, CosmeticIds: cosmeticIDs}, bundle)
+}
+
+func TestHTTPAssetsClient_GrantBundle(t *testing.T) {
+	bundleID := uuid.New()
+	granted := []uuid.UUID{uuid.New()}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body grantBundleRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, bundleID, body.BundleId)
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"granted_cosmetic_ids": granted}})
+	}))
+	defer server.Close()
+
+	ids, err := newTestHTTPClients(server.URL).Assets.GrantBundle(uuid.New(), bundleID)
+	require.NoError(t, err)
+	assert.Equal(t, granted, ids)
+}
+
+func TestHTTPAssetsClient_GrantBundle_Conflict(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+	}))
+	defer server.Close()
+
+	_, err := newTestHTTPClients(server.URL).Assets.GrantBundle(uuid.New(), uuid.New())
+	var owned *BundleAlreadyOwned
+	assert.ErrorAs(t, err, &owned)
+}
+
+func TestHTTPWorldJobsClient_StopAllJobsForUser(t *testing.T) {
+	userID := uuid.New()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/world/internal/users/"+userID.String()+"/stop-jobs" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	clients := newTestHTTPClients(server.URL)
+	assert.NoError(t, clients.WorldJobs.StopAllJobsForUser(userID))
+	assert.Error(t, clients.WorldJobs.StopAllJobsForUser(uuid.New()))
+}
+
+func TestHTTPPlayersClient_GetPlayerByCharacterName(t *testing.T) {
+	userID := uuid.New()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/player/internal/characters", r.URL.Path)
+		assert.Equal(t, "Sir Gifts&Co", r.URL.Query().Get("character_name"))
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"data": map[string]any{"user_id": userID, "character_name": "Sir Gifts&Co"},
+		})
+	}))
+	defer server.Close()
+
+	player, err := newTestHTTPClients(server.URL).Players.GetPlayerByCharacterName("Sir Gifts&Co")
+	require.NoError(t, err)
+	assert.Equal(t, userID, player.UserId)
+}
+
+func TestHTTPPlayersClient_GetPlayerByCharacterName_NotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	_, err := newTestHTTPClients(server.URL).Players.GetPlayerByCharacterName("nobody")
+	var notFound *PlayerNotFound
+	assert.ErrorAs(t, err, &notFound)
+}
+
+func TestHTTPPlayersClient_GetPlayer(t *testing.T) {
+	userID := uuid.New()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/player/internal/characters/"+userID.String(), r.URL.Path)
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"data": map[string]any{"user_id": userID, "character_name": "Hero"},
+		})
+	}))
+	defer server.Close()
+
+	player, err := newTestHTTPClients(server.URL).Players.GetPlayer(userID)
+	require.NoError(t, err)
+	assert.Equal(t, "Hero", player.CharacterName)
+}
+
+func TestHTTPAccountsClient_GetAccountEmail(t *testing.T) {
+	userID := uuid.New()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/auth/internal/users/"+userID.String(), r.URL.Path)
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"data": map[string]any{"user_id": userID, "email": "player@example.com"},
+		})
+	}))
+	defer server.Close()
+
+	email, err := newTestHTTPClients(server.URL).Accounts.GetAccountEmail(userID)
+	require.NoError(t, err)
+	assert.Equal(t, "player@example.com", email)
+}
+
+func TestHTTPSubscriptionsClient_CancelSubscription(t *testing.T) {
+	userID := uuid.New()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method)
+		assert.Equal(t, "/subscriptions/internal/users/"+userID.String(), r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	assert.NoError(t, newTestHTTPClients(server.URL).Subscriptions.CancelSubscription(userID))
+}
+
+func TestHTTPSubscriptionsClient_UpdateCustomerEmail(t *testing.T) {
+	userID := uuid.New()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, "/subscriptions/internal/users/"+userID.String()+"/email", r.URL.Path)
+		var body updateCustomerEmailRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "new@example.com", body.Email)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	assert.NoError(t, newTestHTTPClients(server.URL).Subscriptions.UpdateCustomerEmail(userID, "new@example.com"))
+}
+
+func TestHTTPAuditClient_RecordEvent(t *testing.T) {
+	actorID := uuid.New()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/auth/internal/audit", r.URL.Path)
+		var body AuditEvent
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, actorID, body.ActorId)
+		assert.Equal(t, "world.job.stop", body.Action)
+		assert.JSONEq(t, `{"zone_id":3}`, string(body.After))
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	err := newTestHTTPClients(server.URL).Audit.RecordEvent(AuditEvent{
+		ActorId: actorID,
+		Action:  "world.job.stop",
+		After:   json.RawMessage(`{"zone_id":3}`),
+	})
+	assert.NoError(t, err)
+}
+
+func TestHTTPServerCredentialsClient_VerifyServerToken(t *testing.T) {
+	worldID := uuid.New()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/world/internal/servers/verify", r.URL.Path)
+		var body verifyServerTokenRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		if body.Token != "ftrs_valid" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"data": map[string]any{"world_id": worldID, "zone_id": 2},
+		})
+	}))
+	defer server.Close()
+
+	clients := newTestHTTPClients(server.URL)
+	scope, err := clients.Servers.VerifyServerToken("ftrs_valid")
+	assert.NoError(t, err)
+	assert.Equal(t, &ServerScope{WorldId: worldID, ZoneId: 2}, scope)
+
+	_, err = clients.Servers.VerifyServerToken("ftrs_revoked")
+	var invalid *ServerCredentialInvalid
+	assert.ErrorAs(t, err, &invalid)
+}
+
+func TestHTTPUserDataClient_ExportAndErase(t *testing.T) {
+	userID := uuid.New()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/player/internal/users/"+userID.String()+"/data", r.URL.Path)
+		switch r.Method {
+		case http.MethodGet:
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"data": map[string]any{"character_name": "Hero"},
+			})
+		case http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	client := newTestHTTPClients(server.URL).UserData[PlayersUserData]
+	data, err := client.ExportUserData(userID)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"character_name":"Hero"}`, string(data))
+	assert.NoError(t, client.EraseUserData(userID))
+}
+
+func TestHTTPUserDataClient_Erase_BadStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	assert.Error(t, newTestHTTPClients(server.URL).UserData[WorldUserData].EraseUserData(uuid.New()))
+}
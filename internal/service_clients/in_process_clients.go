@@ -0,0 +1,174 @@
+package service_clients
+
+import (
+	"encoding/json"
+
+	"github.com/google/uuid"
+)
+
+// localClients holds the implementations registered by the services running in this process.
+// They are resolved on every call since services depend on each other in a cycle at setup.
+type localClients struct {
+	subscriptions SubscriptionsClient
+	assets        AssetsClient
+	worldJobs     WorldJobsClient
+	servers       ServerCredentialsClient
+	players       PlayersClient
+	accounts      AccountsClient
+	audit         AuditClient
+	userData      map[string]UserDataClient
+}
+
+type inProcessSubscriptionsClient struct {
+	local *localClients
+}
+
+func (c *inProcessSubscriptionsClient) CheckAvailability(userId uuid.UUID) (*SlotsAvailability, error) {
+	if c.local.subscriptions == nil {
+		return nil, NewServiceUnavailable("payment-service is not running in this process")
+	}
+	return c.local.subscriptions.CheckAvailability(userId)
+}
+
+func (c *inProcessSubscriptionsClient) UpdateUsedSlots(userId uuid.UUID, slots int, areUsed bool) error {
+	if c.local.subscriptions == nil {
+		return NewServiceUnavailable("payment-service is not running in this process")
+	}
+	return c.local.subscriptions.UpdateUsedSlots(userId, slots, areUsed)
+}
+
+func (c *inProcessSubscriptionsClient) CancelSubscription(userId uuid.UUID) error {
+	if c.local.subscriptions == nil {
+		return NewServiceUnavailable("payment-service is not running in this process")
+	}
+	return c.local.subscriptions.CancelSubscription(userId)
+}
+
+func (c *inProcessSubscriptionsClient) UpdateCustomerEmail(userId uuid.UUID, email string) error {
+	if c.local.subscriptions == nil {
+		return NewServiceUnavailable("payment-service is not running in this process")
+	}
+	return c.local.subscriptions.UpdateCustomerEmail(userId, email)
+}
+
+type inProcessAssetsClient struct {
+	local *localClients
+}
+
+func (c *inProcessAssetsClient) GetCosmetic(cosmeticId uuid.UUID) (*CosmeticInfo, error) {
+	if c.local.assets == nil {
+		return nil, NewServiceUnavailable("assets-service is not running in this process")
+	}
+	return c.local.assets.GetCosmetic(cosmeticId)
+}
+
+func (c *inProcessAssetsClient) GrantCosmetic(userId uuid.UUID, cosmeticId uuid.UUID) error {
+	if c.local.assets == nil {
+		return NewServiceUnavailable("assets-service is not running in this process")
+	}
+	return c.local.assets.GrantCosmetic(userId, cosmeticId)
+}
+
+func (c *inProcessAssetsClient) GiftCosmetic(recipientId uuid.UUID, cosmeticId uuid.UUID, gifterId uuid.UUID) error {
+	if c.local.assets == nil {
+		return NewServiceUnavailable("assets-service is not running in this process")
+	}
+	return c.local.assets.GiftCosmetic(recipientId, cosmeticId, gifterId)
+}
+
+func (c *inProcessAssetsClient) GetBundle(bundleId uuid.UUID) (*BundleInfo, error) {
+	if c.local.assets == nil {
+		return nil, NewServiceUnavailable("assets-service is not running in this process")
+	}
+	return c.local.assets.GetBundle(bundleId)
+}
+
+func (c *inProcessAssetsClient) GrantBundle(userId uuid.UUID, bundleId uuid.UUID) ([]uuid.UUID, error) {
+	if c.local.assets == nil {
+		return nil, NewServiceUnavailable("assets-service is not running in this process")
+	}
+	return c.local.assets.GrantBundle(userId, bundleId)
+}
+
+type inProcessWorldJobsClient struct {
+	local *localClients
+}
+
+func (c *inProcessWorldJobsClient) StopAllJobsForUser(userId uuid.UUID) error {
+	if c.local.worldJobs == nil {
+		return NewServiceUnavailable("world-service is not running in this process")
+	}
+	return c.local.worldJobs.StopAllJobsForUser(userId)
+}
+
+type inProcessServerCredentialsClient struct {
+	local *localClients
+}
+
+func (c *inProcessServerCredentialsClient) VerifyServerToken(token string) (*ServerScope, error) {
+	if c.local.servers == nil {
+		return nil, NewServiceUnavailable("world-service is not running in this process")
+	}
+	return c.local.servers.VerifyServerToken(token)
+}
+
+type inProcessPlayersClient struct {
+	local *localClients
+}
+
+func (c *inProcessPlayersClient) GetPlayerByCharacterName(characterName string) (*PlayerInfo, error) {
+	if c.local.players == nil {
+		return nil, NewServiceUnavailable("players-service is not running in this process")
+	}
+	return c.local.players.GetPlayerByCharacterName(characterName)
+}
+
+func (c *inProcessPlayersClient) GetPlayer(userId uuid.UUID) (*PlayerInfo, error) {
+	if c.local.players == nil {
+		return nil, NewServiceUnavailable("players-service is not running in this process")
+	}
+	return c.local.players.GetPlayer(userId)
+}
+
+type inProcessAccountsClient struct {
+	local *localClients
+}
+
+func (c *inProcessAccountsClient) GetAccountEmail(userId uuid.UUID) (string, error) {
+	if c.local.accounts == nil {
+		return "", NewServiceUnavailable("authentication-service is not running in this process")
+	}
+	return c.local.accounts.GetAccountEmail(userId)
+}
+
+type inProcessAuditClient struct {
+	local *localClients
+}
+
+func (c *inProcessAuditClient) RecordEvent(event AuditEvent) error {
+	if c.local.audit == nil {
+		return NewServiceUnavailable("authentication-service is not running in this process")
+	}
+	return c.local.audit.RecordEvent(event)
+}
+
+type inProcessUserDataClient struct {
+	local   *localClients
+	service string
+}
+
+func (c *inProcessUserDataClient) ExportUserData(userId uuid.UUID) (json.RawMessage, error) {
+	client, ok := c.local.userData[c.service]
+	if !ok {
+		return nil, NewServiceUnavailable(c.service + " service is not running in this process")
+	}
+	return client.ExportUserData(userId)
+}
+
+func (c *inProcessUserDataClient) EraseUserData(userId uuid.UUID) error {
+	client, ok := c.local.userData[c.service]
+	if !ok {
+		return NewServiceUnavailable(c.service + " service is not running in this process")
+	}
+	return client.EraseUserData(userId)
+}
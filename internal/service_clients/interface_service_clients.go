@@ -0,0 +1,146 @@
+package service_clients
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SlotsAvailability describes the zone slots a user can still activate.
+type SlotsAvailability struct {
+	Allowed   bool
+	FreeSlots int
+}
+
+// CosmeticInfo holds the cosmetic data other services need to sell it.
+type CosmeticInfo struct {
+	CosmeticId uuid.UUID
+	Price      int64
+	CreatedBy  uuid.UUID
+	WorldID    uuid.UUID
+}
+
+// BundleInfo holds the bundle data other services need to sell it, Price already has any active promotion applied.
+type BundleInfo struct {
+	BundleId    uuid.UUID
+	Price       int64
+	CreatedBy   uuid.UUID
+	WorldID     uuid.UUID
+	CosmeticIds []uuid.UUID
+}
+
+// PlayerInfo identifies the account behind a character.
+type PlayerInfo struct {
+	UserId        uuid.UUID
+	CharacterName string
+}
+
+// SubscriptionsClient gives access to the zone subscriptions owned by the payment-service.
+type SubscriptionsClient interface {
+	// CheckAvailability returns whether the user has an active subscription and how many slots are free.
+	CheckAvailability(userId uuid.UUID) (*SlotsAvailability, error)
+
+	// UpdateUsedSlots adds or releases used slots from the user's subscription.
+	UpdateUsedSlots(userId uuid.UUID, slots int, areUsed bool) error
+
+	// CancelSubscription schedules the cancellation of the user's subscription at the end of the billing period.
+	// Users without an active subscription are ignored.
+	CancelSubscription(userId uuid.UUID) error
+
+	// UpdateCustomerEmail keeps the billing email of the user in sync after an email change.
+	// Users that never started a checkout are ignored.
+	UpdateCustomerEmail(userId uuid.UUID, email string) error
+}
+
+// AssetsClient gives access to the cosmetics owned by the assets-service.
+type AssetsClient interface {
+	// GetCosmetic retrieves the current price and creator of a cosmetic.
+	GetCosmetic(cosmeticId uuid.UUID) (*CosmeticInfo, error)
+
+	// GrantCosmetic records that the user owns the cosmetic.
+	GrantCosmetic(userId uuid.UUID, cosmeticId uuid.UUID) error
+
+	// GiftCosmetic records that the recipient owns the cosmetic, paid for by the gifter.
+	GiftCosmetic(recipientId uuid.UUID, cosmeticId uuid.UUID, gifterId uuid.UUID) error
+
+	// GetBundle retrieves the current price, creator and contents of a bundle.
+	GetBundle(bundleId uuid.UUID) (*BundleInfo, error)
+
+	// GrantBundle atomically grants every cosmetic of the bundle the user does not own yet and returns them.
+	GrantBundle(userId uuid.UUID, bundleId uuid.UUID) ([]uuid.UUID, error)
+}
+
+// WorldJobsClient gives access to the zone jobs owned by the world-service.
+type WorldJobsClient interface {
+	// StopAllJobsForUser deactivates every active zone of the user's worlds.
+	StopAllJobsForUser(userId uuid.UUID) error
+}
+
+// SERVER_TOKEN_PREFIX starts every credential issued to a game server, telling them apart from user JWTs.
+const SERVER_TOKEN_PREFIX = "ftrs_"
+
+// ServerScope is the world zone a game server credential was issued for.
+type ServerScope struct {
+	WorldId uuid.UUID
+	ZoneId  int
+}
+
+// ServerCredentialsClient verifies the credentials the world-service issues to the game servers it launches.
+type ServerCredentialsClient interface {
+	// VerifyServerToken returns the zone the token was issued for, ServerCredentialInvalid if it is unknown or revoked.
+	VerifyServerToken(token string) (*ServerScope, error)
+}
+
+// PlayersClient gives access to the characters owned by the players-service.
+type PlayersClient interface {
+	// GetPlayerByCharacterName resolves the user behind a character name.
+	GetPlayerByCharacterName(characterName string) (*PlayerInfo, error)
+
+	// GetPlayer retrieves the character of a user.
+	GetPlayer(userId uuid.UUID) (*PlayerInfo, error)
+}
+
+// AccountsClient gives access to the accounts owned by the authentication-service.
+type AccountsClient interface {
+	// GetAccountEmail retrieves the email address of the user's account.
+	GetAccountEmail(userId uuid.UUID) (string, error)
+}
+
+// AuditEvent describes a privileged action taken by a staff member, server tokens act as the nil user.
+type AuditEvent struct {
+	ActorId    uuid.UUID       `json:"actor_id"`
+	Action     string          `json:"action"`
+	TargetType string          `json:"target_type"`
+	TargetId   string          `json:"target_id"`
+	Before     json.RawMessage `json:"before,omitempty"`
+	After      json.RawMessage `json:"after,omitempty"`
+	RequestId  string          `json:"request_id"`
+	IPAddress  string          `json:"ip_address"`
+	OccurredAt time.Time       `json:"occurred_at"`
+}
+
+// AuditClient gives access to the audit log owned by the authentication-service.
+type AuditClient interface {
+	// RecordEvent appends the event to the audit log.
+	RecordEvent(event AuditEvent) error
+}
+
+// Services that keep data about users, listed in the order their data is erased.
+const (
+	WorldUserData    = "world"
+	PlayersUserData  = "players"
+	AssetsUserData   = "assets"
+	PaymentsUserData = "payments"
+)
+
+var UserDataServices = []string{WorldUserData, PlayersUserData, AssetsUserData, PaymentsUserData}
+
+// UserDataClient gives access to the data a service keeps about a user, for data exports and account deletion.
+type UserDataClient interface {
+	// ExportUserData returns everything the service stores about the user as a JSON document.
+	ExportUserData(userId uuid.UUID) (json.RawMessage, error)
+
+	// EraseUserData deletes or anonymizes everything the service stores about the user.
+	EraseUserData(userId uuid.UUID) error
+}
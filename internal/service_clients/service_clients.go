@@ -0,0 +1,121 @@
+package service_clients
+
+import (
+	"net/http"
+
+	"github.com/FeedTheRealm-org/core-service/config"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/internal_auth"
+)
+
+// Clients groups the clients a service uses to reach the other services.
+type Clients struct {
+	Subscriptions SubscriptionsClient
+	Assets        AssetsClient
+	WorldJobs     WorldJobsClient
+	Servers       ServerCredentialsClient
+	Players       PlayersClient
+	Accounts      AccountsClient
+	Audit         AuditClient
+	// UserData holds the client of every service in UserDataServices.
+	UserData map[string]UserDataClient
+
+	local *localClients
+}
+
+// NewInProcessClients creates clients that call the services running in this same process.
+// Each service router registers its own implementation through the Provide methods.
+func NewInProcessClients() *Clients {
+	local := &localClients{userData: make(map[string]UserDataClient)}
+	userData := make(map[string]UserDataClient, len(UserDataServices))
+	for _, service := range UserDataServices {
+		userData[service] = &inProcessUserDataClient{local: local, service: service}
+	}
+	return &Clients{
+		Subscriptions: &inProcessSubscriptionsClient{local: local},
+		Assets:        &inProcessAssetsClient{local: local},
+		WorldJobs:     &inProcessWorldJobsClient{local: local},
+		Servers:       &inProcessServerCredentialsClient{local: local},
+		Players:       &inProcessPlayersClient{local: local},
+		Accounts:      &inProcessAccountsClient{local: local},
+		Audit:         &inProcessAuditClient{local: local},
+		UserData:      userData,
+		local:         local,
+	}
+}
+
+// NewHTTPClients creates clients that call the internal routes of each service over HTTP.
+// Every request is signed so the internal auth middleware of the target service accepts it.
+func NewHTTPClients(conf *config.ServiceClientsConfig, signer *internal_auth.RequestSigner) *Clients {
+	httpClient := &http.Client{Timeout: conf.Timeout}
+	return &Clients{
+		Subscriptions: &httpSubscriptionsClient{baseURL: conf.PaymentsURL, httpClient: httpClient, signer: signer},
+		Assets:        &httpAssetsClient{baseURL: conf.AssetsURL, httpClient: httpClient, signer: signer},
+		WorldJobs:     &httpWorldJobsClient{baseURL: conf.WorldURL, httpClient: httpClient, signer: signer},
+		Servers:       &httpServerCredentialsClient{baseURL: conf.WorldURL, httpClient: httpClient, signer: signer},
+		Players:       &httpPlayersClient{baseURL: conf.PlayersURL, httpClient: httpClient, signer: signer},
+		Accounts:      &httpAccountsClient{baseURL: conf.AuthURL, httpClient: httpClient, signer: signer},
+		Audit:         &httpAuditClient{baseURL: conf.AuthURL, httpClient: httpClient, signer: signer},
+		UserData: map[string]UserDataClient{
+			WorldUserData:    &httpUserDataClient{baseURL: conf.WorldURL + "/world", service: WorldUserData, httpClient: httpClient, signer: signer},
+			PlayersUserData:  &httpUserDataClient{baseURL: conf.PlayersURL + "/player", service: PlayersUserData, httpClient: httpClient, signer: signer},
+			AssetsUserData:   &httpUserDataClient{baseURL: conf.AssetsURL + "/assets", service: AssetsUserData, httpClient: httpClient, signer: signer},
+			PaymentsUserData: &httpUserDataClient{baseURL: conf.PaymentsURL + "/payments", service: PaymentsUserData, httpClient: httpClient, signer: signer},
+		},
+	}
+}
+
+// ProvideSubscriptions registers the in-process subscriptions implementation, ignored for HTTP clients.
+func (c *Clients) ProvideSubscriptions(client SubscriptionsClient) {
+	if c.local != nil {
+		c.local.subscriptions = client
+	}
+}
+
+// ProvideAssets registers the in-process assets implementation, ignored for HTTP clients.
+func (c *Clients) ProvideAssets(client AssetsClient) {
+	if c.local != nil {
+		c.local.assets = client
+	}
+}
+
+// ProvideWorldJobs registers the in-process world jobs implementation, ignored for HTTP clients.
+func (c *Clients) ProvideWorldJobs(client WorldJobsClient) {
+	if c.local != nil {
+		c.local.worldJobs = client
+	}
+}
+
+// ProvideServers registers the in-process server credentials implementation, ignored for HTTP clients.
+func (c *Clients) ProvideServers(client ServerCredentialsClient) {
+	if c.local != nil {
+		c.local.servers = client
+	}
+}
+
+// ProvidePlayers registers the in-process players implementation, ignored for HTTP clients.
+func (c *Clients) ProvidePlayers(client PlayersClient) {
+	if c.local != nil {
+		c.local.players = client
+	}
+}
+
+// ProvideAccounts registers the in-process accounts implementation, ignored for HTTP clients.
+func (c *Clients) ProvideAccounts(client AccountsClient) {
+	if c.local != nil {
+		c.local.accounts = client
+	}
+}
+
+// ProvideAudit registers the in-process audit implementation, ignored for HTTP clients.
+func (c *Clients) ProvideAudit(client AuditClient) {
+	if c.local != nil {
+		c.local.audit = client
+	}
+}
+
+// ProvideUserData registers the in-process user data implementation of a service, ignored for HTTP clients.
+func (c *Clients) ProvideUserData(service string, client UserDataClient) {
+	if c.local != nil {
+		c.local.userData[service] = client
+	}
+}
@@ -0,0 +1,76 @@
+package service_clients
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeWorldJobsClient struct {
+	stopped []uuid.UUID
+}
+
+func (f *fakeWorldJobsClient) StopAllJobsForUser(userId uuid.UUID) error {
+	f.stopped = append(f.stopped, userId)
+	return nil
+}
+
+func TestInProcessClients_NotProvided(t *testing.T) {
+	clients := NewInProcessClients()
+	var unavailable *ServiceUnavailable
+
+	_, err := clients.Subscriptions.CheckAvailability(uuid.New())
+	assert.ErrorAs(t, err, &unavailable)
+
+	_, err = clients.Assets.GetCosmetic(uuid.New())
+	assert.ErrorAs(t, err, &unavailable)
+
+	err = clients.WorldJobs.StopAllJobsForUser(uuid.New())
+	assert.ErrorAs(t, err, &unavailable)
+
+	_, err = clients.Players.GetPlayerByCharacterName("someone")
+	assert.ErrorAs(t, err, &unavailable)
+
+	_, err = clients.Accounts.GetAccountEmail(uuid.New())
+	assert.ErrorAs(t, err, &unavailable)
+
+	err = clients.Subscriptions.CancelSubscription(uuid.New())
+	assert.ErrorAs(t, err, &unavailable)
+
+	err = clients.Subscriptions.UpdateCustomerEmail(uuid.New(), "new@example.com")
+	assert.ErrorAs(t, err, &unavailable)
+
+	err = clients.Audit.RecordEvent(AuditEvent{Action: "payments.gem_balance.update"})
+	assert.ErrorAs(t, err, &unavailable)
+
+	_, err = clients.Servers.VerifyServerToken("ftrs_token")
+	assert.ErrorAs(t, err, &unavailable)
+
+	for _, service := range UserDataServices {
+		_, err = clients.UserData[service].ExportUserData(uuid.New())
+		assert.ErrorAs(t, err, &unavailable)
+		err = clients.UserData[service].EraseUserData(uuid.New())
+		assert.ErrorAs(t, err, &unavailable)
+	}
+}
+
+func TestInProcessClients_ProvidedAfterCreation(t *testing.T) {
+	clients := NewInProcessClients()
+	worldJobs := clients.WorldJobs
+
+	fake := &fakeWorldJobsClient{}
+	clients.ProvideWorldJobs(fake)
+
+	userID := uuid.New()
+	assert.NoError(t, worldJobs.StopAllJobsForUser(userID))
+	assert.Equal(t, []uuid.UUID{userID}, fake.stopped)
+}
+
+func TestHTTPClients_ProvideIgnored(t *testing.T) {
+	clients := newTestHTTPClients("http://127.0.0.1:1")
+	clients.ProvideWorldJobs(&fakeWorldJobsClient{})
+
+	_, isHTTP := clients.WorldJobs.(*httpWorldJobsClient)
+	assert.True(t, isHTTP)
+}
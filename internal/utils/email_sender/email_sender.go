@@ -114,6 +114,44 @@ func (s *emailSenderService) SendPasswordResetEmail(data PasswordResetEmailData)
 	return renderAndSend(s.conf, data.ToEmail, "Feed The Realm - Password Reset Code", "password_reset_email", data)
 }
 
+type AccountLockedEmailData struct {
+	BaseEmailData
+	UnlockURL     string
+	LockedMinutes int
+}
+
+func (s *emailSenderService) SendAccountLockedEmail(data AccountLockedEmailData) error {
+	return renderAndSend(s.conf, data.ToEmail, "Feed The Realm - Account Locked", "account_locked", data)
+}
+
+type AccountDeletionEmailData struct {
+	BaseEmailData
+	CancelURL    string
+	DeletionDate string
+}
+
+func (s *emailSenderService) SendAccountDeletionEmail(data AccountDeletionEmailData) error {
+	return renderAndSend(s.conf, data.ToEmail, "Feed The Realm - Account Deletion Scheduled", "account_deletion", data)
+}
+
+type EmailChangeVerificationEmailData struct {
+	BaseEmailData
+	VerifyCode string
+}
+
+func (s *emailSenderService) SendEmailChangeVerificationEmail(data EmailChangeVerificationEmailData) error {
+	return renderAndSend(s.conf, data.ToEmail, "Feed The Realm - Confirm Your New Email", "email_change_verification", data)
+}
+
+type EmailChangeNoticeEmailData struct {
+	BaseEmailData
+	NewEmail string
+}
+
+func (s *emailSenderService) SendEmailChangeNoticeEmail(data EmailChangeNoticeEmailData) error {
+	return renderAndSend(s.conf, data.ToEmail, "Feed The Realm - Email Change Requested", "email_change_notice", data)
+}
+
 type GemPurchaseEmailData struct {
 	BaseEmailData
 	GemAmount     int64
@@ -139,6 +177,31 @@ func (s *emailSenderService) SendGemPurchaseFailedEmail(data GemPurchaseFailedEm
 	return renderAndSend(s.conf, data.ToEmail, "Feed The Realm - Gem Purchase Failed", "gem_rejected", data)
 }
 
+type GemReversalEmailData struct {
+	BaseEmailData
+	GemAmount     int64
+	TotalGems     int64
+	Reason        string
+	TransactionID string
+	ReversalDate  string
+}
+
+func (s *emailSenderService) SendGemReversalEmail(data GemReversalEmailData) error {
+	return renderAndSend(s.conf, data.ToEmail, "Feed The Realm - Gems Reversed", "gem_reversed", data)
+}
+
+type GiftReceivedEmailData struct {
+	BaseEmailData
+	GifterName string
+	Message    string
+	CosmeticID string
+	GiftDate   string
+}
+
+func (s *emailSenderService) SendGiftReceivedEmail(data GiftReceivedEmailData) error {
+	return renderAndSend(s.conf, data.ToEmail, "Feed The Realm - You Received a Gift", "gift_received", data)
+}
+
 type SubscriptionStartedData struct {
 	BaseEmailData
 	ZoneCount             int64
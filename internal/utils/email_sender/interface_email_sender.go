@@ -10,12 +10,30 @@ type EmailSenderService interface {
 	// SendVerificationEmail sends a verification email to the user with the provided data.
 	SendVerificationEmail(data VerificationEmailData) error
 
+	// SendAccountLockedEmail sends an email with an unlock link after the account was locked for failed logins.
+	SendAccountLockedEmail(data AccountLockedEmailData) error
+
+	// SendAccountDeletionEmail sends an email confirming the account deletion with a link to restore it during the grace period.
+	SendAccountDeletionEmail(data AccountDeletionEmailData) error
+
+	// SendEmailChangeVerificationEmail sends the code that confirms a new account email to that address.
+	SendEmailChangeVerificationEmail(data EmailChangeVerificationEmailData) error
+
+	// SendEmailChangeNoticeEmail warns the current account email that a change to another address was requested.
+	SendEmailChangeNoticeEmail(data EmailChangeNoticeEmailData) error
+
 	// SendGemPurchaseEmail sends an email to the user confirming their gem purchase with the provided data.
 	SendGemPurchaseEmail(data GemPurchaseEmailData) error
 
 	// SendGemPurchaseFailedEmail sends an email to the user notifying them of a failed gem purchase with the provided data.
 	SendGemPurchaseFailedEmail(data GemPurchaseFailedEmailData) error
 
+	// SendGemReversalEmail sends an email to the user notifying them that gems were removed after a refund or dispute.
+	SendGemReversalEmail(data GemReversalEmailData) error
+
+	// SendGiftReceivedEmail sends an email to the recipient of a gift with the gifter and their message.
+	SendGiftReceivedEmail(data GiftReceivedEmailData) error
+
 	// SendSubscriptionStartedEmail sends an email to the user confirming their subscription start with the provided data.
 	SendSubscriptionStartedEmail(data SubscriptionStartedData) error
 
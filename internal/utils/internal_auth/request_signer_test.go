@@ -0,0 +1,73 @@
+package internal_auth
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func signedRequest(t *testing.T, signer *RequestSigner, body []byte, now time.Time) *http.Request {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPut, "http://payments/subscriptions/internal/users/abc/used-slots", nil)
+	require.NoError(t, err)
+	signer.SignRequest(req, body, now)
+	return req
+}
+
+func TestRequestSigner_Valid(t *testing.T) {
+	signer := NewRequestSigner("my-internal-secret", time.Minute)
+	body := []byte(`{"slots":1}`)
+	now := time.Now()
+	req := signedRequest(t, signer, body, now)
+
+	err := signer.Verify(req.Method, req.URL.RequestURI(), body, req.Header.Get(TIMESTAMP_HEADER), req.Header.Get(SIGNATURE_HEADER), now)
+	assert.NoError(t, err)
+}
+
+func TestRequestSigner_TamperedBody(t *testing.T) {
+	signer := NewRequestSigner("my-internal-secret", time.Minute)
+	now := time.Now()
+	req := signedRequest(t, signer, []byte(`{"slots":1}`), now)
+
+	err := signer.Verify(req.Method, req.URL.RequestURI(), []byte(`{"slots":100}`), req.Header.Get(TIMESTAMP_HEADER), req.Header.Get(SIGNATURE_HEADER), now)
+	_, isInvalid := err.(*InternalAuthInvalidSignatureError)
+	assert.True(t, isInvalid, "Expected InternalAuthInvalidSignatureError")
+}
+
+func TestRequestSigner_OtherSecret(t *testing.T) {
+	now := time.Now()
+	req := signedRequest(t, NewRequestSigner("attacker-secret", time.Minute), nil, now)
+
+	err := NewRequestSigner("my-internal-secret", time.Minute).Verify(req.Method, req.URL.RequestURI(), nil, req.Header.Get(TIMESTAMP_HEADER), req.Header.Get(SIGNATURE_HEADER), now)
+	assert.Error(t, err)
+}
+
+func TestRequestSigner_Expired(t *testing.T) {
+	signer := NewRequestSigner("my-internal-secret", time.Minute)
+	now := time.Now()
+	req := signedRequest(t, signer, nil, now)
+
+	err := signer.Verify(req.Method, req.URL.RequestURI(), nil, req.Header.Get(TIMESTAMP_HEADER), req.Header.Get(SIGNATURE_HEADER), now.Add(time.Minute*2))
+	_, isExpired := err.(*InternalAuthExpiredSignatureError)
+	assert.True(t, isExpired, "Expected InternalAuthExpiredSignatureError")
+}
+
+func TestRequestSigner_MissingHeaders(t *testing.T) {
+	signer := NewRequestSigner("my-internal-secret", time.Minute)
+
+	err := signer.Verify(http.MethodGet, "/world/internal/users/abc/stop-jobs", nil, "", "", time.Now())
+	_, isMissing := err.(*InternalAuthMissingSignatureError)
+	assert.True(t, isMissing, "Expected InternalAuthMissingSignatureError")
+}
+
+func TestRequestSigner_EmptySecretRejectsEverything(t *testing.T) {
+	signer := NewRequestSigner("", time.Minute)
+	now := time.Now()
+	req := signedRequest(t, signer, nil, now)
+
+	err := signer.Verify(req.Method, req.URL.RequestURI(), nil, req.Header.Get(TIMESTAMP_HEADER), req.Header.Get(SIGNATURE_HEADER), now)
+	assert.Error(t, err)
+}
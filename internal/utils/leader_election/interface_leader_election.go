@@ -0,0 +1,12 @@
+package leader_election
+
+import "context"
+
+// Elector picks a single replica to run a background task among every core-service replica.
+type Elector interface {
+	// IsLeader reports whether this replica leads, trying to take the leadership when nobody holds it.
+	IsLeader(ctx context.Context) bool
+
+	// Release gives up the leadership so another replica can take it.
+	Release()
+}
@@ -0,0 +1,82 @@
+package leader_election
+
+import (
+	"context"
+	"database/sql"
+	"hash/fnv"
+	"sync"
+
+	"github.com/FeedTheRealm-org/core-service/internal/utils/logger"
+	"gorm.io/gorm"
+)
+
+// PostgresElector elects the replica holding a session advisory lock. The lock is held on a
+// dedicated connection, when that connection drops Postgres releases the lock and another
+// replica takes over on its next try.
+type PostgresElector struct {
+	db   *sql.DB
+	name string
+	key  int64
+
+	mu   sync.Mutex
+	conn *sql.Conn
+}
+
+// NewPostgresElector creates an elector for the task identified by name.
+func NewPostgresElector(db *gorm.DB, name string) (*PostgresElector, error) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(name))
+	return &PostgresElector{db: sqlDB, name: name, key: int64(hash.Sum64())}, nil
+}
+
+func (e *PostgresElector) IsLeader(ctx context.Context) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.conn != nil {
+		if err := e.conn.PingContext(ctx); err == nil {
+			return true
+		}
+		logger.Logger.Warnf("Lost the %s leadership, its connection dropped", e.name)
+		_ = e.conn.Close()
+		e.conn = nil
+	}
+
+	conn, err := e.db.Conn(ctx)
+	if err != nil {
+		logger.Logger.Errorf("Failed to get a connection for the %s leadership: %v", e.name, err)
+		return false
+	}
+
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", e.key).Scan(&acquired); err != nil || !acquired {
+		if err != nil {
+			logger.Logger.Errorf("Failed to take the %s leadership: %v", e.name, err)
+		}
+		_ = conn.Close()
+		return false
+	}
+
+	logger.Logger.Infof("Took the %s leadership", e.name)
+	e.conn = conn
+	return true
+}
+
+func (e *PostgresElector) Release() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.conn == nil {
+		return
+	}
+	if _, err := e.conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", e.key); err != nil {
+		logger.Logger.Errorf("Failed to release the %s leadership: %v", e.name, err)
+	}
+	_ = e.conn.Close()
+	e.conn = nil
+}
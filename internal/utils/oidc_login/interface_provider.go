@@ -0,0 +1,12 @@
+package oidc_login
+
+import "github.com/FeedTheRealm-org/core-service/internal/utils/oidc_validation"
+
+// Provider runs the authorization code flow against an OpenID Connect provider.
+type Provider interface {
+	Name() string
+	// AuthorizationURL is where the user is sent to sign in with the provider.
+	AuthorizationURL(state string, codeChallenge string, nonce string) string
+	// Exchange redeems the authorization code and returns the verified ID token claims.
+	Exchange(code string, codeVerifier string) (*oidc_validation.IDTokenClaims, error)
+}
@@ -0,0 +1,171 @@
+package oidc_login
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/FeedTheRealm-org/core-service/config"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/logger"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/oidc_validation"
+)
+
+const requestTimeout = 10 * time.Second
+
+type ProviderDiscoveryError struct {
+	message string
+}
+
+func (e *ProviderDiscoveryError) Error() string {
+	return "failed to discover OIDC provider: " + e.message
+}
+
+type TokenExchangeError struct {
+	message string
+}
+
+func (e *TokenExchangeError) Error() string {
+	return "failed to exchange authorization code: " + e.message
+}
+
+type discoveryDocument struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type tokenResponse struct {
+	IDToken string `json:"id_token"`
+}
+
+type oidcProvider struct {
+	conf        config.OIDCProviderConfig
+	redirectURL string
+	discovery   discoveryDocument
+	verifier    *oidc_validation.Verifier
+	client      *http.Client
+}
+
+// NewProviders discovers every configured provider, providers that cannot be reached are skipped.
+func NewProviders(conf *config.Config) map[string]Provider {
+	providers := make(map[string]Provider)
+	for _, providerConf := range conf.OIDC.Providers {
+		provider, err := NewProvider(providerConf, conf.OIDC.RedirectURL, &http.Client{Timeout: requestTimeout})
+		if err != nil {
+			logger.Logger.Errorf("Skipping OIDC provider %s: %v", providerConf.Name, err)
+			continue
+		}
+		providers[providerConf.Name] = provider
+	}
+	return providers
+}
+
+// NewProvider reads the provider configuration from its issuer /.well-known/openid-configuration.
+func NewProvider(conf config.OIDCProviderConfig, redirectURL string, client *http.Client) (Provider, error) {
+	discoveryURL := strings.TrimSuffix(conf.Issuer, "/") + "/.well-known/openid-configuration"
+	res, err := client.Get(discoveryURL)
+	if err != nil {
+		return nil, &ProviderDiscoveryError{message: err.Error()}
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, &ProviderDiscoveryError{message: fmt.Sprintf("unexpected status %d", res.StatusCode)}
+	}
+
+	var discovery discoveryDocument
+	if err := json.NewDecoder(res.Body).Decode(&discovery); err != nil {
+		return nil, &ProviderDiscoveryError{message: err.Error()}
+	}
+	if discovery.Issuer != conf.Issuer {
+		return nil, &ProviderDiscoveryError{message: "issuer mismatch " + discovery.Issuer}
+	}
+
+	verifier, err := oidc_validation.NewVerifier(discovery.Issuer, discovery.JWKSURI, conf.ClientID)
+	if err != nil {
+		return nil, &ProviderDiscoveryError{message: err.Error()}
+	}
+
+	return &oidcProvider{
+		conf:        conf,
+		redirectURL: redirectURL,
+		discovery:   discovery,
+		verifier:    verifier,
+		client:      client,
+	}, nil
+}
+
+func (p *oidcProvider) Name() string {
+	return p.conf.Name
+}
+
+func (p *oidcProvider) AuthorizationURL(state string, codeChallenge string, nonce string) string {
+	params := url.Values{}
+	params.Set("response_type", "code")
+	params.Set("client_id", p.conf.ClientID)
+	params.Set("redirect_uri", p.redirectURL)
+	params.Set("scope", strings.Join(p.conf.Scopes, " "))
+	params.Set("state", state)
+	params.Set("nonce", nonce)
+	params.Set("code_challenge", codeChallenge)
+	params.Set("code_challenge_method", "S256")
+
+	separator := "?"
+	if strings.Contains(p.discovery.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return p.discovery.AuthorizationEndpoint + separator + params.Encode()
+}
+
+func (p *oidcProvider) Exchange(code string, codeVerifier string) (*oidc_validation.IDTokenClaims, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.redirectURL)
+	form.Set("client_id", p.conf.ClientID)
+	form.Set("code_verifier", codeVerifier)
+	if p.conf.ClientSecret != "" {
+		form.Set("client_secret", p.conf.ClientSecret)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, p.discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, &TokenExchangeError{message: err.Error()}
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return nil, &TokenExchangeError{message: err.Error()}
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, 1<<20))
+	if err != nil {
+		return nil, &TokenExchangeError{message: err.Error()}
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, &TokenExchangeError{message: fmt.Sprintf("unexpected status %d", res.StatusCode)}
+	}
+
+	var tokens tokenResponse
+	if err := json.Unmarshal(body, &tokens); err != nil || tokens.IDToken == "" {
+		return nil, &TokenExchangeError{message: "response has no id_token"}
+	}
+
+	claims := &oidc_validation.IDTokenClaims{}
+	if err := p.verifier.Verify(tokens.IDToken, claims); err != nil {
+		return nil, &TokenExchangeError{message: err.Error()}
+	}
+	if claims.Subject == "" {
+		return nil, &TokenExchangeError{message: "id_token has no subject"}
+	}
+
+	return claims, nil
+}
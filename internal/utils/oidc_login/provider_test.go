@@ -0,0 +1,168 @@
+package oidc_login
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/FeedTheRealm-org/core-service/config"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testClientID    = "client-id"
+	testRedirectURL = "http://localhost:3000/auth/callback"
+	testKeyID       = "test-key"
+)
+
+type fakeIssuer struct {
+	server       *httptest.Server
+	key          *rsa.PrivateKey
+	codeVerifier string
+	idTokenClaim jwt.MapClaims
+}
+
+func newFakeIssuer(t *testing.T) *fakeIssuer {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	issuer := &fakeIssuer{key: key}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 issuer.server.URL,
+			"authorization_endpoint": issuer.server.URL + "/authorize",
+			"token_endpoint":         issuer.server.URL + "/token",
+			"jwks_uri":               issuer.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": testKeyID,
+				"use": "sig",
+				"alg": "RS256",
+				"n":   base64.RawURLEncoding.EncodeToString(key.PublicKey.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.PublicKey.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		if r.PostForm.Get("code") != "good-code" || r.PostForm.Get("code_verifier") != issuer.codeVerifier ||
+			r.PostForm.Get("redirect_uri") != testRedirectURL {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, issuer.idTokenClaim)
+		token.Header["kid"] = testKeyID
+		signed, err := token.SignedString(key)
+		require.NoError(t, err)
+		_ = json.NewEncoder(w).Encode(map[string]string{"id_token": signed, "access_token": "unused"})
+	})
+	issuer.server = httptest.NewServer(mux)
+	t.Cleanup(issuer.server.Close)
+	return issuer
+}
+
+func (f *fakeIssuer) provider(t *testing.T) Provider {
+	t.Helper()
+	provider, err := NewProvider(config.OIDCProviderConfig{
+		Name:     "fake",
+		Issuer:   f.server.URL,
+		ClientID: testClientID,
+		Scopes:   []string{"openid", "email"},
+	}, testRedirectURL, f.server.Client())
+	require.NoError(t, err)
+	return provider
+}
+
+func TestProvider_AuthorizationURL(t *testing.T) {
+	issuer := newFakeIssuer(t)
+	provider := issuer.provider(t)
+
+	authURL, err := url.Parse(provider.AuthorizationURL("the-state", "the-challenge", "the-nonce"))
+	require.NoError(t, err)
+	query := authURL.Query()
+	assert.Equal(t, issuer.server.URL+"/authorize", authURL.Scheme+"://"+authURL.Host+authURL.Path)
+	assert.Equal(t, "code", query.Get("response_type"))
+	assert.Equal(t, testClientID, query.Get("client_id"))
+	assert.Equal(t, testRedirectURL, query.Get("redirect_uri"))
+	assert.Equal(t, "openid email", query.Get("scope"))
+	assert.Equal(t, "the-state", query.Get("state"))
+	assert.Equal(t, "the-nonce", query.Get("nonce"))
+	assert.Equal(t, "the-challenge", query.Get("code_challenge"))
+	assert.Equal(t, "S256", query.Get("code_challenge_method"))
+}
+
+func TestProvider_Exchange(t *testing.T) {
+	issuer := newFakeIssuer(t)
+	provider := issuer.provider(t)
+	issuer.codeVerifier = "verifier"
+	issuer.idTokenClaim = jwt.MapClaims{
+		"iss":            issuer.server.URL,
+		"aud":            testClientID,
+		"sub":            "subject-1",
+		"exp":            time.Now().Add(time.Minute).Unix(),
+		"email":          "player@example.com",
+		"email_verified": "true",
+		"nonce":          "the-nonce",
+	}
+
+	claims, err := provider.Exchange("good-code", "verifier")
+	require.NoError(t, err)
+	assert.Equal(t, "subject-1", claims.Subject)
+	assert.Equal(t, "player@example.com", claims.Email)
+	assert.True(t, bool(claims.EmailVerified))
+	assert.Equal(t, "the-nonce", claims.Nonce)
+
+	_, err = provider.Exchange("good-code", "wrong-verifier")
+	_, exchangeFailed := err.(*TokenExchangeError)
+	assert.True(t, exchangeFailed)
+}
+
+func TestProvider_Exchange_RejectsTokenForOtherClient(t *testing.T) {
+	issuer := newFakeIssuer(t)
+	provider := issuer.provider(t)
+	issuer.codeVerifier = "verifier"
+	issuer.idTokenClaim = jwt.MapClaims{
+		"iss": issuer.server.URL,
+		"aud": "another-client",
+		"sub": "subject-1",
+		"exp": time.Now().Add(time.Minute).Unix(),
+	}
+
+	_, err := provider.Exchange("good-code", "verifier")
+	_, exchangeFailed := err.(*TokenExchangeError)
+	assert.True(t, exchangeFailed)
+}
+
+func TestNewProvider_IssuerMismatch(t *testing.T) {
+	issuer := newFakeIssuer(t)
+
+	_, err := NewProvider(config.OIDCProviderConfig{Name: "fake", Issuer: issuer.server.URL + "/", ClientID: testClientID}, testRedirectURL, issuer.server.Client())
+	_, discoveryFailed := err.(*ProviderDiscoveryError)
+	assert.True(t, discoveryFailed)
+}
+
+func TestCodeChallenge_RFC7636Vector(t *testing.T) {
+	assert.Equal(t, "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM", CodeChallenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"))
+
+	first, err := RandomToken()
+	require.NoError(t, err)
+	second, err := RandomToken()
+	require.NoError(t, err)
+	assert.NotEqual(t, first, second)
+	assert.Len(t, first, 43)
+}
@@ -1,11 +1,9 @@
 package oidc_validation
 
 import (
-	"errors"
 	"strings"
 
 	"github.com/FeedTheRealm-org/core-service/config"
-	"github.com/MicahParks/keyfunc/v2"
 	"github.com/golang-jwt/jwt/v5"
 )
 
@@ -13,12 +11,12 @@ const (
 	GITHUB_ISSUER   = "https://token.actions.githubusercontent.com"
 	GITHUB_JWKS_URL = "https://token.actions.githubusercontent.com/.well-known/jwks"
 	MAIN_BRANCH_REF = "refs/heads/main"
+	TAG_REF_PREFIX  = "refs/tags/"
 )
 
 type GitHubOIDCVerifier struct {
-	audience string
+	verifier *Verifier
 	repo     string
-	jwks     *keyfunc.JWKS
 }
 
 type GitHubClaims struct {
@@ -26,34 +24,25 @@ type GitHubClaims struct {
 	BaseRef    string `json:"base_ref"`
 	Repository string `json:"repository"`
 	RefType    string `json:"ref_type"`
+	Ref        string `json:"ref"`
 }
 
 func NewGitHubOIDCVerifier(conf *config.Config) (*GitHubOIDCVerifier, error) {
-	jwks, err := keyfunc.Get(GITHUB_JWKS_URL, keyfunc.Options{})
+	verifier, err := NewVerifier(GITHUB_ISSUER, GITHUB_JWKS_URL, conf.Github.GithubAudienceWebhook)
 	if err != nil {
 		return nil, err
 	}
 
 	return &GitHubOIDCVerifier{
-		audience: conf.Github.GithubAudienceWebhook,
+		verifier: verifier,
 		repo:     conf.Github.GithubRepoURLWebhook,
-		jwks:     jwks,
 	}, nil
 }
 
 func (v *GitHubOIDCVerifier) IsValidToken(tokenString string) (GitHubClaims, error) {
 	claims := &GitHubClaims{}
-
-	token, err := jwt.ParseWithClaims(tokenString, claims, v.jwks.Keyfunc,
-		jwt.WithAudience(v.audience),
-		jwt.WithIssuer(GITHUB_ISSUER),
-		jwt.WithExpirationRequired(),
-	)
-	if err != nil || !token.Valid {
-		return *claims, errors.New("invalid token")
-	}
-
-	return *claims, nil
+	err := v.verifier.Verify(tokenString, claims)
+	return *claims, err
 }
 
 func (v *GitHubOIDCVerifier) IsValidRepo(claims *GitHubClaims) bool {
@@ -63,3 +52,11 @@ func (v *GitHubOIDCVerifier) IsValidRepo(claims *GitHubClaims) bool {
 func (v *GitHubOIDCVerifier) IsTriggerATag(claims *GitHubClaims) bool {
 	return claims.RefType == "tag"
 }
+
+// Tag returns the name of the tag that triggered the workflow, empty when it was not a tag.
+func (c *GitHubClaims) Tag() string {
+	if c.RefType != "tag" {
+		return ""
+	}
+	return strings.TrimPrefix(c.Ref, TAG_REF_PREFIX)
+}
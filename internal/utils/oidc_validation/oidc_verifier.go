@@ -0,0 +1,76 @@
+package oidc_validation
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/MicahParks/keyfunc/v2"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Verifier checks the signature, issuer, audience and expiry of tokens issued by an OIDC provider.
+type Verifier struct {
+	issuer   string
+	audience string
+	keyfunc  jwt.Keyfunc
+}
+
+// IDTokenClaims are the standard claims of an OpenID Connect ID token used to sign users in.
+type IDTokenClaims struct {
+	jwt.RegisteredClaims
+	Email         string       `json:"email"`
+	EmailVerified FlexibleBool `json:"email_verified"`
+	Nonce         string       `json:"nonce"`
+}
+
+// FlexibleBool accepts booleans sent as JSON strings, which some providers do for email_verified.
+type FlexibleBool bool
+
+func (b *FlexibleBool) UnmarshalJSON(data []byte) error {
+	var value bool
+	if err := json.Unmarshal(data, &value); err == nil {
+		*b = FlexibleBool(value)
+		return nil
+	}
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+	*b = FlexibleBool(text == "true")
+	return nil
+}
+
+// NewVerifier fetches the provider JWKS, unknown key ids trigger a refresh so key rotations are picked up.
+func NewVerifier(issuer string, jwksURL string, audience string) (*Verifier, error) {
+	jwks, err := keyfunc.Get(jwksURL, keyfunc.Options{
+		RefreshUnknownKID: true,
+		RefreshRateLimit:  time.Minute * 5,
+		RefreshTimeout:    time.Second * 10,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return NewVerifierWithKeyfunc(issuer, audience, jwks.Keyfunc), nil
+}
+
+// NewVerifierWithKeyfunc builds a verifier from an already resolved key source.
+func NewVerifierWithKeyfunc(issuer string, audience string, keyfunc jwt.Keyfunc) *Verifier {
+	return &Verifier{issuer: issuer, audience: audience, keyfunc: keyfunc}
+}
+
+// Verify parses tokenString into claims and checks it was issued for this verifier audience.
+func (v *Verifier) Verify(tokenString string, claims jwt.Claims) error {
+	if v == nil || v.keyfunc == nil {
+		return errors.New("verifier not configured")
+	}
+	token, err := jwt.ParseWithClaims(tokenString, claims, v.keyfunc,
+		jwt.WithAudience(v.audience),
+		jwt.WithIssuer(v.issuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil || !token.Valid {
+		return errors.New("invalid token")
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package permissions
+
+import "slices"
+
+// Permissions granted to staff roles, admins are superusers and hold all of them.
+const (
+	USERS_READ            = "auth.users.read"
+	USERS_SESSIONS_REVOKE = "auth.users.sessions.revoke"
+	ROLES_MANAGE          = "auth.roles.manage"
+
+	ASSETS_CATEGORIES_WRITE = "assets.categories.write"
+	ASSETS_CONTENT_DELETE   = "assets.content.delete"
+
+	PAYMENTS_BALANCES_READ       = "payments.balances.read"
+	PAYMENTS_BALANCES_WRITE      = "payments.balances.write"
+	PAYMENTS_PACKS_WRITE         = "payments.packs.write"
+	PAYMENTS_SUBSCRIPTIONS_READ  = "payments.subscriptions.read"
+	PAYMENTS_SUBSCRIPTIONS_WRITE = "payments.subscriptions.write"
+	PAYMENTS_PAYOUTS_READ        = "payments.payouts.read"
+	PAYMENTS_PAYOUTS_WRITE       = "payments.payouts.write"
+	PAYMENTS_SALES_READ          = "payments.sales.read"
+	PAYMENTS_METRICS_READ        = "payments.metrics.read"
+
+	WORLD_JOBS_READ      = "world.jobs.read"
+	WORLD_JOBS_WRITE     = "world.jobs.write"
+	WORLD_DATABASE_RESET = "world.database.reset"
+
+	EXPORTS_WRITE = "exports.write"
+
+	AUDIT_READ = "audit.read"
+)
+
+// All lists every permission a role can be granted.
+var All = []string{
+	USERS_READ,
+	USERS_SESSIONS_REVOKE,
+	ROLES_MANAGE,
+	ASSETS_CATEGORIES_WRITE,
+	ASSETS_CONTENT_DELETE,
+	PAYMENTS_BALANCES_READ,
+	PAYMENTS_BALANCES_WRITE,
+	PAYMENTS_PACKS_WRITE,
+	PAYMENTS_SUBSCRIPTIONS_READ,
+	PAYMENTS_SUBSCRIPTIONS_WRITE,
+	PAYMENTS_PAYOUTS_READ,
+	PAYMENTS_PAYOUTS_WRITE,
+	PAYMENTS_SALES_READ,
+	PAYMENTS_METRICS_READ,
+	WORLD_JOBS_READ,
+	WORLD_JOBS_WRITE,
+	WORLD_DATABASE_RESET,
+	EXPORTS_WRITE,
+	AUDIT_READ,
+}
+
+// IsKnown reports whether the permission exists.
+func IsKnown(permission string) bool {
+	return slices.Contains(All, permission)
+}
+
+// Grants reports whether the granted permissions include the required one.
+func Grants(granted []string, required string) bool {
+	return slices.Contains(granted, required)
+}
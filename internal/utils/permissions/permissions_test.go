@@ -0,0 +1,20 @@
+package permissions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsKnown(t *testing.T) {
+	assert.True(t, IsKnown(PAYMENTS_BALANCES_READ))
+	assert.False(t, IsKnown("payments.everything"))
+}
+
+func TestGrants(t *testing.T) {
+	granted := []string{USERS_READ, PAYMENTS_BALANCES_READ}
+
+	assert.True(t, Grants(granted, PAYMENTS_BALANCES_READ))
+	assert.False(t, Grants(granted, PAYMENTS_BALANCES_WRITE))
+	assert.False(t, Grants(nil, USERS_READ))
+}
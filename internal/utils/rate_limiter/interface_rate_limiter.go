@@ -0,0 +1,6 @@
+package rate_limiter
+
+// RateLimiter decides whether a request identified by key is allowed under the given limit.
+type RateLimiter interface {
+	Allow(key string, limit Limit) (Decision, error)
+}
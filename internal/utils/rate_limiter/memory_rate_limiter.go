@@ -0,0 +1,56 @@
+package rate_limiter
+
+import (
+	"sync"
+	"time"
+)
+
+const memorySweepInterval = time.Minute * 10
+
+// MemoryRateLimiter keeps buckets in process memory, limits are not shared between instances.
+type MemoryRateLimiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func NewMemoryRateLimiter() *MemoryRateLimiter {
+	return &MemoryRateLimiter{
+		buckets:   make(map[string]*bucket),
+		lastSweep: time.Now(),
+		now:       time.Now,
+	}
+}
+
+func (m *MemoryRateLimiter) Allow(key string, limit Limit) (Decision, error) {
+	if limit.disabled() {
+		return Decision{Allowed: true}, nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	m.sweep(now)
+
+	b, exists := m.buckets[key]
+	if !exists {
+		b = limit.newBucket(now)
+		m.buckets[key] = b
+	}
+	return limit.take(b, now), nil
+}
+
+// sweep drops buckets idle long enough to be full again, so keys do not pile up forever.
+func (m *MemoryRateLimiter) sweep(now time.Time) {
+	if now.Sub(m.lastSweep) < memorySweepInterval {
+		return
+	}
+	for key, b := range m.buckets {
+		if now.Sub(b.updatedAt) > bucketIdleTTL {
+			delete(m.buckets, key)
+		}
+	}
+	m.lastSweep = now
+}
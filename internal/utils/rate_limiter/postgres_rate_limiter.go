@@ -0,0 +1,70 @@
+package rate_limiter
+
+import (
+	"math/rand"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// postgresSweepChance is the probability that a check also deletes idle buckets.
+const postgresSweepChance = 0.01
+
+type rateLimitBucket struct {
+	Key       string    `gorm:"column:key;primaryKey"`
+	Tokens    float64   `gorm:"column:tokens"`
+	UpdatedAt time.Time `gorm:"column:updated_at"`
+}
+
+func (rateLimitBucket) TableName() string {
+	return "rate_limit_buckets"
+}
+
+// PostgresRateLimiter stores buckets in the rate_limit_buckets table so limits hold across instances.
+type PostgresRateLimiter struct {
+	db  *gorm.DB
+	now func() time.Time
+}
+
+func NewPostgresRateLimiter(db *gorm.DB) *PostgresRateLimiter {
+	return &PostgresRateLimiter{db: db, now: time.Now}
+}
+
+func (p *PostgresRateLimiter) Allow(key string, limit Limit) (Decision, error) {
+	if limit.disabled() {
+		return Decision{Allowed: true}, nil
+	}
+
+	now := p.now()
+	var decision Decision
+	err := p.db.Transaction(func(tx *gorm.DB) error {
+		initial := rateLimitBucket{Key: key, Tokens: float64(limit.Requests), UpdatedAt: now}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&initial).Error; err != nil {
+			return err
+		}
+
+		var row rateLimitBucket
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("key = ?", key).
+			First(&row).Error; err != nil {
+			return err
+		}
+
+		b := &bucket{tokens: row.Tokens, updatedAt: row.UpdatedAt}
+		decision = limit.take(b, now)
+
+		return tx.Model(&rateLimitBucket{}).
+			Where("key = ?", key).
+			Updates(map[string]interface{}{"tokens": b.tokens, "updated_at": b.updatedAt}).Error
+	})
+	if err != nil {
+		return Decision{}, err
+	}
+
+	if rand.Float64() < postgresSweepChance {
+		p.db.Where("updated_at < ?", now.Add(-bucketIdleTTL)).Delete(&rateLimitBucket{})
+	}
+
+	return decision, nil
+}
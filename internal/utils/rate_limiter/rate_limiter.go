@@ -0,0 +1,67 @@
+package rate_limiter
+
+import (
+	"math"
+	"time"
+
+	"github.com/FeedTheRealm-org/core-service/config"
+	"gorm.io/gorm"
+)
+
+// Limit allows up to Requests requests every Per, refilling continuously (token bucket).
+// A limit with no requests is disabled.
+type Limit struct {
+	Requests int
+	Per      time.Duration
+}
+
+// Decision is the outcome of a rate limit check, RetryAfter is only set when not allowed.
+type Decision struct {
+	Allowed    bool
+	RetryAfter time.Duration
+}
+
+// bucketIdleTTL is how long an untouched bucket is kept, longer than any configured refill window.
+const bucketIdleTTL = time.Hour * 24
+
+type bucket struct {
+	tokens    float64
+	updatedAt time.Time
+}
+
+// NewRateLimiter creates the rate limiter backend selected in the configuration.
+func NewRateLimiter(conf *config.Config, db *gorm.DB) RateLimiter {
+	if conf.RateLimit.Backend == config.PostgresRateLimit {
+		return NewPostgresRateLimiter(db)
+	}
+	return NewMemoryRateLimiter()
+}
+
+func (l Limit) disabled() bool {
+	return l.Requests <= 0 || l.Per <= 0
+}
+
+func (l Limit) refillRate() float64 {
+	return float64(l.Requests) / float64(l.Per)
+}
+
+// take refills the bucket up to now and consumes one token if available.
+func (l Limit) take(b *bucket, now time.Time) Decision {
+	elapsed := now.Sub(b.updatedAt)
+	if elapsed > 0 {
+		b.tokens = math.Min(float64(l.Requests), b.tokens+float64(elapsed)*l.refillRate())
+		b.updatedAt = now
+	}
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return Decision{Allowed: true}
+	}
+
+	missing := (1 - b.tokens) / l.refillRate()
+	return Decision{Allowed: false, RetryAfter: time.Duration(math.Ceil(missing))}
+}
+
+func (l Limit) newBucket(now time.Time) *bucket {
+	return &bucket{tokens: float64(l.Requests), updatedAt: now}
+}
@@ -0,0 +1,89 @@
+package rate_limiter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestMemoryLimiter(now *time.Time) *MemoryRateLimiter {
+	limiter := NewMemoryRateLimiter()
+	limiter.now = func() time.Time { return *now }
+	return limiter
+}
+
+func TestMemoryRateLimiter_AllowsBurstThenDenies(t *testing.T) {
+	now := time.Now()
+	limiter := newTestMemoryLimiter(&now)
+	limit := Limit{Requests: 3, Per: time.Minute}
+
+	for i := 0; i < 3; i++ {
+		decision, err := limiter.Allow("ip:1.2.3.4", limit)
+		require.NoError(t, err)
+		assert.True(t, decision.Allowed)
+	}
+
+	decision, err := limiter.Allow("ip:1.2.3.4", limit)
+	require.NoError(t, err)
+	assert.False(t, decision.Allowed)
+	assert.Equal(t, time.Second*20, decision.RetryAfter)
+}
+
+func TestMemoryRateLimiter_Refills(t *testing.T) {
+	now := time.Now()
+	limiter := newTestMemoryLimiter(&now)
+	limit := Limit{Requests: 2, Per: time.Minute}
+
+	for i := 0; i < 2; i++ {
+		_, _ = limiter.Allow("k", limit)
+	}
+	decision, _ := limiter.Allow("k", limit)
+	assert.False(t, decision.Allowed)
+
+	now = now.Add(time.Second * 30)
+	decision, _ = limiter.Allow("k", limit)
+	assert.True(t, decision.Allowed)
+
+	decision, _ = limiter.Allow("k", limit)
+	assert.False(t, decision.Allowed)
+}
+
+func TestMemoryRateLimiter_KeysAreIndependent(t *testing.T) {
+	now := time.Now()
+	limiter := newTestMemoryLimiter(&now)
+	limit := Limit{Requests: 1, Per: time.Hour}
+
+	decision, _ := limiter.Allow("email:a@example.com", limit)
+	assert.True(t, decision.Allowed)
+	decision, _ = limiter.Allow("email:b@example.com", limit)
+	assert.True(t, decision.Allowed)
+	decision, _ = limiter.Allow("email:a@example.com", limit)
+	assert.False(t, decision.Allowed)
+}
+
+func TestMemoryRateLimiter_DisabledLimit(t *testing.T) {
+	now := time.Now()
+	limiter := newTestMemoryLimiter(&now)
+
+	for i := 0; i < 100; i++ {
+		decision, err := limiter.Allow("k", Limit{Requests: 0, Per: time.Minute})
+		require.NoError(t, err)
+		assert.True(t, decision.Allowed)
+	}
+	assert.Empty(t, limiter.buckets)
+}
+
+func TestMemoryRateLimiter_SweepsIdleBuckets(t *testing.T) {
+	now := time.Now()
+	limiter := newTestMemoryLimiter(&now)
+	limit := Limit{Requests: 1, Per: time.Minute}
+
+	_, _ = limiter.Allow("old", limit)
+	now = now.Add(bucketIdleTTL + time.Minute)
+	_, _ = limiter.Allow("new", limit)
+
+	assert.NotContains(t, limiter.buckets, "old")
+	assert.Contains(t, limiter.buckets, "new")
+}
@@ -5,10 +5,22 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
+// Issuer and audiences of the tokens, services and game servers validating access
+// tokens with the JWKS document should check both.
+const (
+	TOKEN_ISSUER           = "feedtherealm-auth"
+	ACCESS_TOKEN_AUDIENCE  = "feedtherealm"
+	REFRESH_TOKEN_AUDIENCE = "feedtherealm-refresh"
+)
+
+// JWTManager signs access tokens with asymmetric keys so anyone can verify them
+// with the public keys, refresh tokens never leave the authentication service
+// and are signed with a shared secret.
 type JWTManager struct {
-	secretAccessToken    string
+	keys                 *SigningKeys
 	secretRefreshToken   string
 	accessTokenDuration  time.Duration
 	refreshTokenDuration time.Duration
@@ -35,25 +47,42 @@ func (e *JWTExpiredTokenError) Error() string {
 	return "token expired"
 }
 
-func NewJWTManager(secretAccessToken string, secretRefreshToken string, accessTokenDuration time.Duration, refreshTokenDuration time.Duration) *JWTManager {
+func NewJWTManager(keys *SigningKeys, secretRefreshToken string, accessTokenDuration time.Duration, refreshTokenDuration time.Duration) *JWTManager {
 	return &JWTManager{
-		secretAccessToken:    secretAccessToken,
+		keys:                 keys,
 		secretRefreshToken:   secretRefreshToken,
 		accessTokenDuration:  accessTokenDuration,
 		refreshTokenDuration: refreshTokenDuration,
 	}
 }
 
-func (m *JWTManager) GenerateAccessToken(id string, email string, isAdmin bool) (string, error) {
-	accessToken := jwt.NewWithClaims(jwt.SigningMethodHS256, &jwt.MapClaims{
-		"userID":  id,
-		"email":   email,
-		"exp":     time.Now().Add(m.accessTokenDuration).Unix(),
-		"iss":     time.Now().Unix(),
-		"isAdmin": isAdmin,
+// GenerateAccessToken issues an access token bound to a session, the permissions of the
+// staff roles of the user travel in the token so services can check them without a lookup.
+func (m *JWTManager) GenerateAccessToken(id string, email string, isAdmin bool, permissions []string, sessionId string) (string, error) {
+	if permissions == nil {
+		permissions = []string{}
+	}
+	signingKey := m.keys.active
+	if signingKey == nil {
+		return "", &JWTFailedToGenerateError{}
+	}
+
+	now := time.Now()
+	accessToken := jwt.NewWithClaims(signingKey.method, &jwt.MapClaims{
+		"sub":         id,
+		"email":       email,
+		"iss":         TOKEN_ISSUER,
+		"aud":         ACCESS_TOKEN_AUDIENCE,
+		"iat":         now.Unix(),
+		"exp":         now.Add(m.accessTokenDuration).Unix(),
+		"jti":         uuid.NewString(),
+		"isAdmin":     isAdmin,
+		"permissions": permissions,
+		"sid":         sessionId,
 	})
+	accessToken.Header["kid"] = signingKey.Id
 
-	tokenString, err := accessToken.SignedString([]byte(m.secretAccessToken))
+	tokenString, err := accessToken.SignedString(signingKey.private)
 	if err != nil {
 		return "", &JWTFailedToGenerateError{}
 	}
@@ -61,13 +90,20 @@ func (m *JWTManager) GenerateAccessToken(id string, email string, isAdmin bool)
 	return tokenString, nil
 }
 
-func (m *JWTManager) GenerateRefreshToken(id string, email string, isAdmin bool) (string, error) {
+// GenerateRefreshToken issues a refresh token bound to a session, every token
+// carries a unique jti so rotating within the same second never repeats a token.
+func (m *JWTManager) GenerateRefreshToken(id string, email string, isAdmin bool, sessionId string) (string, error) {
+	now := time.Now()
 	refreshToken := jwt.NewWithClaims(jwt.SigningMethodHS256, &jwt.MapClaims{
-		"userID":  id,
+		"sub":     id,
 		"email":   email,
-		"exp":     time.Now().Add(m.refreshTokenDuration).Unix(),
-		"iss":     time.Now().Unix(),
+		"iss":     TOKEN_ISSUER,
+		"aud":     REFRESH_TOKEN_AUDIENCE,
+		"iat":     now.Unix(),
+		"exp":     now.Add(m.refreshTokenDuration).Unix(),
 		"isAdmin": isAdmin,
+		"sid":     sessionId,
+		"jti":     uuid.NewString(),
 	})
 
 	refreshTokenString, err := refreshToken.SignedString([]byte(m.secretRefreshToken))
@@ -78,13 +114,27 @@ func (m *JWTManager) GenerateRefreshToken(id string, email string, isAdmin bool)
 	return refreshTokenString, nil
 }
 
-func isValidToken(tokenString string, now time.Time, signature string, lastUpdate time.Time) (jwt.MapClaims, error) {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (any, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, &JWTInvalidTokenError{}
-		}
-		return []byte(signature), nil
-	})
+func (m *JWTManager) RefreshTokenDuration() time.Duration {
+	return m.refreshTokenDuration
+}
+
+// JWKS returns the public keys access tokens can be verified with.
+func (m *JWTManager) JWKS() JWKSet {
+	return m.keys.JWKS()
+}
+
+// Subject returns the user the token was issued to, refresh tokens issued before
+// the standard claims carry it in userID.
+func Subject(claims jwt.MapClaims) string {
+	if sub, ok := claims["sub"].(string); ok {
+		return sub
+	}
+	userID, _ := claims["userID"].(string)
+	return userID
+}
+
+func isValidToken(tokenString string, now time.Time, keyFunc jwt.Keyfunc, options ...jwt.ParserOption) (jwt.MapClaims, error) {
+	token, err := jwt.Parse(tokenString, keyFunc, options...)
 	if err != nil {
 		if errors.Is(err, jwt.ErrTokenExpired) {
 			return nil, &JWTExpiredTokenError{}
@@ -109,19 +159,33 @@ func isValidToken(tokenString string, now time.Time, signature string, lastUpdat
 	return claims, nil
 }
 
+// IsValidateAccessToken verifies the token with the key named by its kid header,
+// so tokens signed with a key rotated out of the active slot stay valid.
 func (m *JWTManager) IsValidateAccessToken(tokenString string, now time.Time) (jwt.MapClaims, error) {
-	return isValidToken(tokenString, now, m.secretAccessToken, now)
+	return isValidToken(tokenString, now, m.accessTokenKey,
+		jwt.WithValidMethods([]string{jwt.SigningMethodEdDSA.Alg(), jwt.SigningMethodRS256.Alg()}),
+		jwt.WithIssuer(TOKEN_ISSUER),
+		jwt.WithAudience(ACCESS_TOKEN_AUDIENCE),
+	)
 }
 
-func (m *JWTManager) IsValidateRefreshToken(tokenString string, now time.Time, lastUpdate time.Time) (jwt.MapClaims, error) {
-	claims, err := isValidToken(tokenString, now, m.secretRefreshToken, lastUpdate)
-	if err != nil {
-		return nil, err
-	}
+func (m *JWTManager) IsValidateRefreshToken(tokenString string, now time.Time) (jwt.MapClaims, error) {
+	return isValidToken(tokenString, now, func(token *jwt.Token) (any, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, &JWTInvalidTokenError{}
+		}
+		return []byte(m.secretRefreshToken), nil
+	})
+}
 
-	if lastUpdate.Unix() > int64(claims["iss"].(float64)) {
-		return nil, &JWTExpiredTokenError{}
+func (m *JWTManager) accessTokenKey(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := m.keys.keys[kid]
+	if !ok {
+		return nil, &JWTInvalidTokenError{message: "unknown key id"}
 	}
-
-	return claims, nil
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, &JWTInvalidTokenError{message: "signing method does not match the key"}
+	}
+	return key.public, nil
 }
@@ -10,9 +10,9 @@ import (
 )
 
 func TestIsValidateToken_Valid(t *testing.T) {
-	manager := NewJWTManager("my-secret-access-key", "my-secret-refresh-key", time.Minute, time.Hour)
+	manager := NewJWTManager(NewEphemeralSigningKeys(), "my-secret-refresh-key", time.Minute, time.Hour)
 	email := "user@example.com"
-	token, err := manager.GenerateAccessToken(email, email, false)
+	token, err := manager.GenerateAccessToken(email, email, false, nil, "session-id")
 	require.NoError(t, err, "Token generation failed")
 
 	claims, err := manager.IsValidateAccessToken(token, time.Now().Add(time.Minute/2))
@@ -21,10 +21,20 @@ func TestIsValidateToken_Valid(t *testing.T) {
 	assert.NotNil(t, claims, "Expected non-nil claims")
 }
 
+func TestGenerateAccessToken_CarriesPermissions(t *testing.T) {
+	manager := NewJWTManager(NewEphemeralSigningKeys(), "my-secret-refresh-key", time.Minute, time.Hour)
+	token, err := manager.GenerateAccessToken("user-id", "user@example.com", false, []string{"payments.metrics.read"}, "session-id")
+	require.NoError(t, err)
+
+	claims, err := manager.IsValidateAccessToken(token, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, []any{"payments.metrics.read"}, claims["permissions"])
+}
+
 func TestIsValidateToken_Expired(t *testing.T) {
-	manager := NewJWTManager("my-secret-access-key", "my-secret-refresh-key", time.Minute, time.Hour)
+	manager := NewJWTManager(NewEphemeralSigningKeys(), "my-secret-refresh-key", time.Minute, time.Hour)
 	email := "user@example.com"
-	token, err := manager.GenerateAccessToken(email, email, false)
+	token, err := manager.GenerateAccessToken(email, email, false, nil, "session-id")
 	require.NoError(t, err, "Token generation failed")
 
 	_, err = manager.IsValidateAccessToken(token, time.Now().Add(time.Minute*2))
@@ -35,9 +45,9 @@ func TestIsValidateToken_Expired(t *testing.T) {
 }
 
 func TestIsValidateToken_InvalidSigningMethod(t *testing.T) {
-	manager := NewJWTManager("my-secret-access-key", "my-secret-refresh-key", time.Minute, time.Hour)
+	manager := NewJWTManager(NewEphemeralSigningKeys(), "my-secret-refresh-key", time.Minute, time.Hour)
 	email := "user@example.com"
-	token, err := manager.GenerateAccessToken(email, email, false)
+	token, err := manager.GenerateAccessToken(email, email, false, nil, "session-id")
 	require.NoError(t, err, "Token generation failed")
 
 	invalidToken := token + "invalid"
@@ -49,7 +59,7 @@ func TestIsValidateToken_InvalidSigningMethod(t *testing.T) {
 }
 
 func TestIsValidateToken_MalformedToken(t *testing.T) {
-	manager := NewJWTManager("my-secret-access-key", "my-secret-refresh-key", time.Minute, time.Hour)
+	manager := NewJWTManager(NewEphemeralSigningKeys(), "my-secret-refresh-key", time.Minute, time.Hour)
 	invalidToken := "malformed.token.string"
 
 	_, err := manager.IsValidateAccessToken(invalidToken, time.Now())
@@ -60,31 +70,30 @@ func TestIsValidateToken_MalformedToken(t *testing.T) {
 }
 
 func TestIsValidateRefreshToken_Valid(t *testing.T) {
-	manager := NewJWTManager("my-secret-access-key", "my-secret-refresh-key", time.Minute, time.Hour)
+	manager := NewJWTManager(NewEphemeralSigningKeys(), "my-secret-refresh-key", time.Minute, time.Hour)
 	userID := "user-id"
-	token, err := manager.GenerateRefreshToken(userID, "user@example.com", true)
+	token, err := manager.GenerateRefreshToken(userID, "user@example.com", true, "session-id")
 	require.NoError(t, err)
 
-	claims, err := manager.IsValidateRefreshToken(token, time.Now().Add(time.Minute), time.Now())
+	claims, err := manager.IsValidateRefreshToken(token, time.Now().Add(time.Minute))
 	require.NoError(t, err)
-	assert.Equal(t, userID, claims["userID"])
+	assert.Equal(t, userID, Subject(claims))
+	assert.Equal(t, "session-id", claims["sid"])
 }
 
-func TestIsValidateRefreshToken_IssuedBeforeLastUpdate(t *testing.T) {
-	manager := NewJWTManager("my-secret-access-key", "my-secret-refresh-key", time.Minute, time.Hour)
-	token, err := manager.GenerateRefreshToken("user-id", "user@example.com", false)
+func TestGenerateRefreshToken_UniquePerRotation(t *testing.T) {
+	manager := NewJWTManager(NewEphemeralSigningKeys(), "my-secret-refresh-key", time.Minute, time.Hour)
+	first, err := manager.GenerateRefreshToken("user-id", "user@example.com", false, "session-id")
+	require.NoError(t, err)
+	second, err := manager.GenerateRefreshToken("user-id", "user@example.com", false, "session-id")
 	require.NoError(t, err)
 
-	lastUpdate := time.Now().Add(time.Minute)
-	_, err = manager.IsValidateRefreshToken(token, time.Now().Add(time.Minute), lastUpdate)
-	require.Error(t, err)
-	_, isExpired := err.(*JWTExpiredTokenError)
-	assert.True(t, isExpired)
+	assert.NotEqual(t, first, second)
 }
 
 func TestIsValidateAccessToken_InvalidSignature(t *testing.T) {
-	manager := NewJWTManager("my-secret-access-key", "my-secret-refresh-key", time.Minute, time.Hour)
-	refreshToken, err := manager.GenerateRefreshToken("user-id", "user@example.com", false)
+	manager := NewJWTManager(NewEphemeralSigningKeys(), "my-secret-refresh-key", time.Minute, time.Hour)
+	refreshToken, err := manager.GenerateRefreshToken("user-id", "user@example.com", false, "session-id")
 	require.NoError(t, err)
 
 	_, err = manager.IsValidateAccessToken(refreshToken, time.Now())
@@ -94,47 +103,43 @@ func TestIsValidateAccessToken_InvalidSignature(t *testing.T) {
 }
 
 func TestIsValidateAccessToken_InvalidExpClaim(t *testing.T) {
-	secret := "my-secret-access-key"
-	manager := NewJWTManager(secret, "my-secret-refresh-key", time.Minute, time.Hour)
-
-	badToken := jwt.NewWithClaims(jwt.SigningMethodHS256, &jwt.MapClaims{
-		"userID": "user-id",
-		"email":  "user@example.com",
-		"exp":    "not-a-number",
-		"iss":    time.Now().Unix(),
+	keys := NewEphemeralSigningKeys()
+	manager := NewJWTManager(keys, "my-secret-refresh-key", time.Minute, time.Hour)
+
+	tokenString := signWithActiveKey(t, keys, jwt.MapClaims{
+		"sub":   "user-id",
+		"email": "user@example.com",
+		"iss":   TOKEN_ISSUER,
+		"aud":   ACCESS_TOKEN_AUDIENCE,
+		"exp":   "not-a-number",
 	})
 
-	tokenString, err := badToken.SignedString([]byte(secret))
-	require.NoError(t, err)
-
-	_, err = manager.IsValidateAccessToken(tokenString, time.Now())
+	_, err := manager.IsValidateAccessToken(tokenString, time.Now())
 	require.Error(t, err)
 	_, isInvalid := err.(*JWTInvalidTokenError)
 	assert.True(t, isInvalid)
 }
 
 func TestIsValidateAccessToken_ExpiredFromParser(t *testing.T) {
-	secret := "my-secret-access-key"
-	manager := NewJWTManager(secret, "my-secret-refresh-key", time.Minute, time.Hour)
-
-	expiredToken := jwt.NewWithClaims(jwt.SigningMethodHS256, &jwt.MapClaims{
-		"userID": "user-id",
-		"email":  "user@example.com",
-		"exp":    time.Now().Add(-time.Minute).Unix(),
-		"iss":    time.Now().Unix(),
+	keys := NewEphemeralSigningKeys()
+	manager := NewJWTManager(keys, "my-secret-refresh-key", time.Minute, time.Hour)
+
+	tokenString := signWithActiveKey(t, keys, jwt.MapClaims{
+		"sub":   "user-id",
+		"email": "user@example.com",
+		"iss":   TOKEN_ISSUER,
+		"aud":   ACCESS_TOKEN_AUDIENCE,
+		"exp":   time.Now().Add(-time.Minute).Unix(),
 	})
 
-	tokenString, err := expiredToken.SignedString([]byte(secret))
-	require.NoError(t, err)
-
-	_, err = manager.IsValidateAccessToken(tokenString, time.Now())
+	_, err := manager.IsValidateAccessToken(tokenString, time.Now())
 	require.Error(t, err)
 	_, isExpired := err.(*JWTExpiredTokenError)
 	assert.True(t, isExpired)
 }
 
 func TestIsValidateAccessToken_InvalidSigningMethod(t *testing.T) {
-	manager := NewJWTManager("my-secret-access-key", "my-secret-refresh-key", time.Minute, time.Hour)
+	manager := NewJWTManager(NewEphemeralSigningKeys(), "my-secret-refresh-key", time.Minute, time.Hour)
 
 	noneToken := jwt.NewWithClaims(jwt.SigningMethodNone, &jwt.MapClaims{
 		"userID": "user-id",
@@ -151,3 +156,113 @@ func TestIsValidateAccessToken_InvalidSigningMethod(t *testing.T) {
 	_, isInvalid := err.(*JWTInvalidTokenError)
 	assert.True(t, isInvalid)
 }
+
+func TestGenerateAccessToken_StandardClaims(t *testing.T) {
+	keys := NewEphemeralSigningKeys()
+	manager := NewJWTManager(keys, "my-secret-refresh-key", time.Minute, time.Hour)
+
+	token, err := manager.GenerateAccessToken("user-id", "user@example.com", false, nil, "session-id")
+	require.NoError(t, err)
+
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, jwt.MapClaims{})
+	require.NoError(t, err)
+	assert.Equal(t, keys.active.Id, parsed.Header["kid"])
+	assert.Equal(t, jwt.SigningMethodEdDSA.Alg(), parsed.Header["alg"])
+
+	claims, err := manager.IsValidateAccessToken(token, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, "user-id", claims["sub"])
+	assert.Equal(t, TOKEN_ISSUER, claims["iss"])
+	assert.Equal(t, ACCESS_TOKEN_AUDIENCE, claims["aud"])
+	assert.NotEmpty(t, claims["jti"])
+	assert.NotNil(t, claims["iat"])
+	assert.NotContains(t, claims, "userID")
+}
+
+func TestIsValidateAccessToken_RotatedKeyStillValid(t *testing.T) {
+	oldKey := newEd25519Key(t, "old")
+	newKey := newEd25519Key(t, "new")
+
+	before, err := NewSigningKeys("old", oldKey)
+	require.NoError(t, err)
+	token, err := NewJWTManager(before, "refresh", time.Minute, time.Hour).GenerateAccessToken("user-id", "user@example.com", false, nil, "session-id")
+	require.NoError(t, err)
+
+	after, err := NewSigningKeys("new", newKey, oldKey)
+	require.NoError(t, err)
+	_, err = NewJWTManager(after, "refresh", time.Minute, time.Hour).IsValidateAccessToken(token, time.Now())
+	assert.NoError(t, err)
+
+	retired, err := NewSigningKeys("new", newKey)
+	require.NoError(t, err)
+	_, err = NewJWTManager(retired, "refresh", time.Minute, time.Hour).IsValidateAccessToken(token, time.Now())
+	require.Error(t, err)
+	_, isInvalid := err.(*JWTInvalidTokenError)
+	assert.True(t, isInvalid)
+}
+
+func TestIsValidateAccessToken_VerificationOnlyKeys(t *testing.T) {
+	key := newEd25519Key(t, "main")
+	signing, err := NewSigningKeys("main", key)
+	require.NoError(t, err)
+	token, err := NewJWTManager(signing, "refresh", time.Minute, time.Hour).GenerateAccessToken("user-id", "user@example.com", false, nil, "session-id")
+	require.NoError(t, err)
+
+	publicOnly, err := newSigningKey("main", nil, key.public)
+	require.NoError(t, err)
+	verifying, err := NewVerificationKeys(publicOnly)
+	require.NoError(t, err)
+	manager := NewJWTManager(verifying, "refresh", time.Minute, time.Hour)
+
+	_, err = manager.IsValidateAccessToken(token, time.Now())
+	assert.NoError(t, err)
+
+	_, err = manager.GenerateAccessToken("user-id", "user@example.com", false, nil, "session-id")
+	_, failed := err.(*JWTFailedToGenerateError)
+	assert.True(t, failed)
+}
+
+func TestIsValidateAccessToken_WrongAudience(t *testing.T) {
+	keys := NewEphemeralSigningKeys()
+	manager := NewJWTManager(keys, "my-secret-refresh-key", time.Minute, time.Hour)
+
+	tokenString := signWithActiveKey(t, keys, jwt.MapClaims{
+		"sub":   "user-id",
+		"email": "user@example.com",
+		"iss":   TOKEN_ISSUER,
+		"aud":   REFRESH_TOKEN_AUDIENCE,
+		"exp":   time.Now().Add(time.Minute).Unix(),
+	})
+
+	_, err := manager.IsValidateAccessToken(tokenString, time.Now())
+	require.Error(t, err)
+	_, isInvalid := err.(*JWTInvalidTokenError)
+	assert.True(t, isInvalid)
+}
+
+func TestIsValidateRefreshToken_LegacyUserIDClaim(t *testing.T) {
+	manager := NewJWTManager(NewEphemeralSigningKeys(), "my-secret-refresh-key", time.Minute, time.Hour)
+
+	legacy := jwt.NewWithClaims(jwt.SigningMethodHS256, &jwt.MapClaims{
+		"userID": "user-id",
+		"email":  "user@example.com",
+		"exp":    time.Now().Add(time.Minute).Unix(),
+		"iss":    time.Now().Unix(),
+		"sid":    "session-id",
+	})
+	tokenString, err := legacy.SignedString([]byte("my-secret-refresh-key"))
+	require.NoError(t, err)
+
+	claims, err := manager.IsValidateRefreshToken(tokenString, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, "user-id", Subject(claims))
+}
+
+func signWithActiveKey(t *testing.T, keys *SigningKeys, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(keys.active.method, claims)
+	token.Header["kid"] = keys.active.Id
+	tokenString, err := token.SignedString(keys.active.private)
+	require.NoError(t, err)
+	return tokenString
+}
@@ -0,0 +1,203 @@
+package session
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// SigningKey is a key access tokens are signed or verified with, keys loaded
+// from a public key PEM can only verify.
+type SigningKey struct {
+	Id      string
+	method  jwt.SigningMethod
+	private crypto.Signer
+	public  crypto.PublicKey
+}
+
+// SigningKeys holds every key whose tokens are accepted, tokens are signed with
+// the active one. Keeping the previous key around after a rotation lets its
+// tokens expire on their own instead of logging everyone out.
+type SigningKeys struct {
+	active *SigningKey
+	keys   map[string]*SigningKey
+}
+
+// JWK is the public part of a signing key as published in the JWKS document.
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+}
+
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+type SigningKeyError struct {
+	message string
+}
+
+func (e *SigningKeyError) Error() string {
+	return "signing key: " + e.message
+}
+
+// ParseSigningKey reads an Ed25519 or RSA key in PEM, either a PKCS#8 private
+// key or a PKIX public key.
+func ParseSigningKey(id string, pemBytes []byte) (*SigningKey, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, &SigningKeyError{message: id + " is not a PEM file"}
+	}
+
+	if private, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		signer, ok := private.(crypto.Signer)
+		if !ok {
+			return nil, &SigningKeyError{message: id + " has an unsupported key type"}
+		}
+		return newSigningKey(id, signer, signer.Public())
+	}
+
+	public, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, &SigningKeyError{message: id + " is neither a PKCS#8 private key nor a PKIX public key"}
+	}
+	return newSigningKey(id, nil, public)
+}
+
+func newSigningKey(id string, private crypto.Signer, public crypto.PublicKey) (*SigningKey, error) {
+	key := &SigningKey{Id: id, private: private, public: public}
+	switch public.(type) {
+	case ed25519.PublicKey:
+		key.method = jwt.SigningMethodEdDSA
+	case *rsa.PublicKey:
+		key.method = jwt.SigningMethodRS256
+	default:
+		return nil, &SigningKeyError{message: id + " must be an Ed25519 or RSA key"}
+	}
+	return key, nil
+}
+
+// NewSigningKeys builds the key set, activeId must name a key holding a private key.
+func NewSigningKeys(activeId string, keys ...*SigningKey) (*SigningKeys, error) {
+	set := &SigningKeys{keys: make(map[string]*SigningKey, len(keys))}
+	for _, key := range keys {
+		if _, exists := set.keys[key.Id]; exists {
+			return nil, &SigningKeyError{message: "duplicated key id " + key.Id}
+		}
+		set.keys[key.Id] = key
+	}
+
+	active, ok := set.keys[activeId]
+	if !ok {
+		return nil, &SigningKeyError{message: "active key " + activeId + " not found"}
+	}
+	if active.private == nil {
+		return nil, &SigningKeyError{message: "active key " + activeId + " has no private key"}
+	}
+	set.active = active
+	return set, nil
+}
+
+// NewVerificationKeys builds a key set that can verify tokens but not sign them,
+// for processes other than the authentication service.
+func NewVerificationKeys(keys ...*SigningKey) (*SigningKeys, error) {
+	set := &SigningKeys{keys: make(map[string]*SigningKey, len(keys))}
+	for _, key := range keys {
+		if _, exists := set.keys[key.Id]; exists {
+			return nil, &SigningKeyError{message: "duplicated key id " + key.Id}
+		}
+		set.keys[key.Id] = key
+	}
+	return set, nil
+}
+
+// LoadSigningKeys reads every <kid>.pem file in dir. When activeId is empty the
+// keys can only verify tokens.
+func LoadSigningKeys(dir string, activeId string) (*SigningKeys, error) {
+	if dir == "" {
+		return nil, &SigningKeyError{message: "no keys dir configured"}
+	}
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, &SigningKeyError{message: err.Error()}
+	}
+	if len(paths) == 0 {
+		return nil, &SigningKeyError{message: "no .pem files in " + dir}
+	}
+
+	keys := make([]*SigningKey, 0, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, &SigningKeyError{message: fmt.Sprintf("failed to read %s: %v", path, err)}
+		}
+		key, err := ParseSigningKey(strings.TrimSuffix(filepath.Base(path), ".pem"), data)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	if activeId == "" {
+		return NewVerificationKeys(keys...)
+	}
+	return NewSigningKeys(activeId, keys...)
+}
+
+// CanSign reports whether the set has an active key to sign new tokens with.
+func (s *SigningKeys) CanSign() bool {
+	return s.active != nil
+}
+
+// NewEphemeralSigningKeys generates a single Ed25519 key that only lives as long
+// as the process, tokens it signed stop being valid on restart.
+func NewEphemeralSigningKeys() *SigningKeys {
+	_, private, _ := ed25519.GenerateKey(nil)
+	key, _ := newSigningKey(uuid.NewString(), private, private.Public())
+	keys, _ := NewSigningKeys(key.Id, key)
+	return keys
+}
+
+// JWKS returns the public keys in the JSON Web Key Set format, sorted by key id.
+func (s *SigningKeys) JWKS() JWKSet {
+	set := JWKSet{Keys: make([]JWK, 0, len(s.keys))}
+	for _, key := range s.keys {
+		set.Keys = append(set.Keys, key.jwk())
+	}
+	sort.Slice(set.Keys, func(i, j int) bool { return set.Keys[i].Kid < set.Keys[j].Kid })
+	return set
+}
+
+func (k *SigningKey) jwk() JWK {
+	jwk := JWK{Kid: k.Id, Alg: k.method.Alg(), Use: "sig"}
+	switch public := k.public.(type) {
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(public)
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+	}
+	return jwk
+}
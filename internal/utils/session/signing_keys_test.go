@@ -0,0 +1,110 @@
+package session
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newEd25519Key(t *testing.T, id string) *SigningKey {
+	t.Helper()
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	key, err := newSigningKey(id, private, private.Public())
+	require.NoError(t, err)
+	return key
+}
+
+func privatePEM(t *testing.T, private any) []byte {
+	t.Helper()
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+}
+
+func publicPEM(t *testing.T, public any) []byte {
+	t.Helper()
+	der, err := x509.MarshalPKIXPublicKey(public)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
+
+func TestParseSigningKey_RSAPrivateKeySignsRS256(t *testing.T) {
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	key, err := ParseSigningKey("rsa", privatePEM(t, private))
+	require.NoError(t, err)
+	keys, err := NewSigningKeys("rsa", key)
+	require.NoError(t, err)
+
+	manager := NewJWTManager(keys, "refresh", time.Minute, time.Hour)
+	token, err := manager.GenerateAccessToken("user-id", "user@example.com", false, nil, "session-id")
+	require.NoError(t, err)
+	_, err = manager.IsValidateAccessToken(token, time.Now())
+	assert.NoError(t, err)
+
+	jwks := keys.JWKS()
+	require.Len(t, jwks.Keys, 1)
+	assert.Equal(t, JWK{Kty: "RSA", Kid: "rsa", Alg: "RS256", Use: "sig", N: jwks.Keys[0].N, E: "AQAB"}, jwks.Keys[0])
+}
+
+func TestParseSigningKey_Invalid(t *testing.T) {
+	_, err := ParseSigningKey("bad", []byte("not a pem"))
+	require.Error(t, err)
+	_, isKeyErr := err.(*SigningKeyError)
+	assert.True(t, isKeyErr)
+}
+
+func TestNewSigningKeys_ActiveKeyNeedsPrivateKey(t *testing.T) {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	key, err := ParseSigningKey("public", publicPEM(t, private.Public()))
+	require.NoError(t, err)
+
+	_, err = NewSigningKeys("public", key)
+	assert.Error(t, err)
+
+	_, err = NewSigningKeys("missing", key)
+	assert.Error(t, err)
+}
+
+func TestLoadSigningKeys_ReadsEveryPEMInDir(t *testing.T) {
+	dir := t.TempDir()
+	_, current, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	_, previous, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "2026-10.pem"), privatePEM(t, current), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "2026-04.pem"), publicPEM(t, previous.Public()), 0o600))
+
+	keys, err := LoadSigningKeys(dir, "2026-10")
+	require.NoError(t, err)
+	assert.Equal(t, "2026-10", keys.active.Id)
+	assert.True(t, keys.CanSign())
+
+	jwks := keys.JWKS()
+	require.Len(t, jwks.Keys, 2)
+	assert.Equal(t, "2026-04", jwks.Keys[0].Kid)
+	assert.Equal(t, "2026-10", jwks.Keys[1].Kid)
+	assert.Equal(t, "OKP", jwks.Keys[1].Kty)
+	assert.Equal(t, "Ed25519", jwks.Keys[1].Crv)
+	assert.Equal(t, "EdDSA", jwks.Keys[1].Alg)
+
+	verifying, err := LoadSigningKeys(dir, "")
+	require.NoError(t, err)
+	assert.Nil(t, verifying.active)
+	assert.False(t, verifying.CanSign())
+
+	_, err = LoadSigningKeys(t.TempDir(), "2026-10")
+	assert.Error(t, err)
+}
@@ -0,0 +1,65 @@
+package totp
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rfcSecret is the SHA1 test key from RFC 6238 appendix B.
+var rfcSecret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+func TestCodeAt_RFC6238Vectors(t *testing.T) {
+	vectors := map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1111111111: "050471",
+		1234567890: "005924",
+		2000000000: "279037",
+	}
+	for unix, expected := range vectors {
+		code, err := CodeAt(rfcSecret, Step(time.Unix(unix, 0)))
+		require.NoError(t, err)
+		assert.Equal(t, expected, code, "time %d", unix)
+	}
+}
+
+func TestValidate_AcceptsAdjacentSteps(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	previous, err := CodeAt(rfcSecret, Step(now)-1)
+	require.NoError(t, err)
+
+	step, ok := Validate(rfcSecret, previous, now)
+	assert.True(t, ok)
+	assert.Equal(t, Step(now)-1, step)
+
+	old, err := CodeAt(rfcSecret, Step(now)-2)
+	require.NoError(t, err)
+	_, ok = Validate(rfcSecret, old, now)
+	assert.False(t, ok)
+}
+
+func TestValidate_RejectsMalformedCodes(t *testing.T) {
+	now := time.Now()
+	for _, code := range []string{"", "12345", "1234567", "abcdef"} {
+		_, ok := Validate(rfcSecret, code, now)
+		assert.False(t, ok, code)
+	}
+	_, ok := Validate("not base32!", "123456", now)
+	assert.False(t, ok)
+}
+
+func TestGenerateSecret_And_KeyURI(t *testing.T) {
+	secret, err := GenerateSecret()
+	require.NoError(t, err)
+	assert.Len(t, secret, 32)
+
+	uri := KeyURI("Feed The Realm", "admin@example.com", secret)
+	assert.True(t, strings.HasPrefix(uri, "otpauth://totp/Feed%20The%20Realm:admin@example.com?"))
+	assert.Contains(t, uri, "secret="+secret)
+	assert.Contains(t, uri, "issuer=Feed+The+Realm")
+}
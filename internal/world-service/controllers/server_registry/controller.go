@@ -13,9 +13,27 @@ type ServerRegistryController interface {
 	// UnRegisterServer removes the server entry.
 	GetServerAddress(c *gin.Context)
 
-	// UpdateServer is a webhook endpoint for reload server when image is updated.
+	// UpdateServer is a webhook endpoint that rolls out a new server image when one is published.
 	UpdateServer(c *gin.Context)
 
+	// CreateRollout rolls out a server image to every active zone as ADMIN.
+	CreateRollout(c *gin.Context)
+
+	// ListRollouts returns the server image rollouts as ADMIN.
+	ListRollouts(c *gin.Context)
+
+	// GetRollout returns a server image rollout and the progress of its zones as ADMIN.
+	GetRollout(c *gin.Context)
+
+	// PauseRollout stops a rollout from updating more zones as ADMIN.
+	PauseRollout(c *gin.Context)
+
+	// ResumeRollout continues a paused rollout as ADMIN.
+	ResumeRollout(c *gin.Context)
+
+	// AbortRollout stops a rollout and rolls its zones back to the previous image as ADMIN.
+	AbortRollout(c *gin.Context)
+
 	// UpdateStatus is a webhook endpoint for updating server status.
 	UpdateStatus(c *gin.Context)
 
@@ -27,4 +45,16 @@ type ServerRegistryController interface {
 
 	// GetAllWorldPlayerCounts returns the player counts for all worlds.
 	GetAllWorldPlayerCounts(c *gin.Context)
+
+	// Heartbeat records that the game server of a zone is alive and where players reach it.
+	Heartbeat(c *gin.Context)
+
+	// ListServers returns the registered zone servers as ADMIN.
+	ListServers(c *gin.Context)
+
+	// GetReconcileStatus returns the state of the zone reconciler and the report of its last pass.
+	GetReconcileStatus(c *gin.Context)
+
+	// VerifyServerTokenInternal resolves the zone a game server token was issued for, for other services.
+	VerifyServerTokenInternal(c *gin.Context)
 }
@@ -3,12 +3,18 @@ package server_registry
 import (
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/FeedTheRealm-org/core-service/config"
 	"github.com/FeedTheRealm-org/core-service/internal/common_handlers"
 	"github.com/FeedTheRealm-org/core-service/internal/errors"
 	"github.com/FeedTheRealm-org/core-service/internal/utils/logger"
 	"github.com/FeedTheRealm-org/core-service/internal/world-service/dtos"
+	world_errors "github.com/FeedTheRealm-org/core-service/internal/world-service/errors"
+	"github.com/FeedTheRealm-org/core-service/internal/world-service/models"
+	server_registry_repo "github.com/FeedTheRealm-org/core-service/internal/world-service/repositories/server_registry"
+	"github.com/FeedTheRealm-org/core-service/internal/world-service/services/orchestrator"
+	"github.com/FeedTheRealm-org/core-service/internal/world-service/services/rollout"
 	"github.com/FeedTheRealm-org/core-service/internal/world-service/services/server_registry"
 	"github.com/FeedTheRealm-org/core-service/internal/world-service/services/world"
 	"github.com/FeedTheRealm-org/core-service/internal/world-service/services/zones"
@@ -22,14 +28,20 @@ type serverRegistryController struct {
 	worldService          world.WorldService
 	zoneService           zones.ZonesService
 	nomadJobSenderService server_registry.ServerRegistryService
+	credentialsService    server_registry.ServerCredentialsService
+	reconcilerService     server_registry.ReconcilerService
+	rolloutService        rollout.RolloutService
 }
 
-func NewServerRegistryController(conf *config.Config, worldService world.WorldService, zoneService zones.ZonesService, nomadJobSenderService server_registry.ServerRegistryService) ServerRegistryController {
+func NewServerRegistryController(conf *config.Config, worldService world.WorldService, zoneService zones.ZonesService, nomadJobSenderService server_registry.ServerRegistryService, credentialsService server_registry.ServerCredentialsService, reconcilerService server_registry.ReconcilerService, rolloutService rollout.RolloutService) ServerRegistryController {
 	return &serverRegistryController{
 		conf:                  conf,
 		worldService:          worldService,
 		zoneService:           zoneService,
 		nomadJobSenderService: nomadJobSenderService,
+		credentialsService:    credentialsService,
+		reconcilerService:     reconcilerService,
+		rolloutService:        rolloutService,
 	}
 }
 
@@ -151,12 +163,13 @@ func (c *serverRegistryController) GetServerAddress(ctx *gin.Context) {
 
 // UpdateServer godoc
 // @Summary      Update running servers
-// @Description  Webhook to update running world servers
+// @Description  Webhook called by the release workflow with its GitHub OIDC token. Rolls the configured server image, tagged with the tag the workflow ran for, out to every active zone batch by batch with the configured batch settings. Custom images and batch overrides are only accepted by the rollouts endpoint.
 // @Tags         world-service
 // @Produce      json
-// @Success      200  {string}  string "OK"
+// @Success      201  {object}  dtos.RolloutResponse
+// @Failure      400  {object} dtos.ErrorResponse
 // @Failure      401  {object} dtos.ErrorResponse
-// @Failure      404  {object} dtos.ErrorResponse
+// @Failure      409  {object} dtos.ErrorResponse
 // @Failure      500  {object} dtos.ErrorResponse
 // @Router       /world/orchestrator/webhook/servers/update [post]
 func (c *serverRegistryController) UpdateServer(ctx *gin.Context) {
@@ -167,21 +180,259 @@ func (c *serverRegistryController) UpdateServer(ctx *gin.Context) {
 		return
 	}
 
-	activeZones, err := c.worldService.GetActiveWorldZones()
-	if err != nil {
-		_ = ctx.Error(errors.NewNotFoundError("Failed to get active world zones."))
+	// Only the tag signed into the OIDC token is trusted, the body is ignored
+	c.createRollout(ctx, dtos.RolloutRequest{Tag: common_handlers.GetGithubOIDCTag(ctx)}, "github")
+}
+
+// CreateRollout godoc
+// @Summary      Roll out a server image
+// @Description  Rolls a game server image out to every active zone batch by batch, canary zones first, rolling every updated zone back when a batch does not become healthy. Supersedes the running or paused rollout. Requires world.jobs.write.
+// @Tags         world-service
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        request body dtos.RolloutRequest true "Image or tag to roll out and batch overrides"
+// @Success      201  {object}  dtos.RolloutResponse
+// @Failure      400  {object} dtos.ErrorResponse
+// @Failure      403  {object} dtos.ErrorResponse
+// @Failure      409  {object} dtos.ErrorResponse
+// @Failure      500  {object} dtos.ErrorResponse
+// @Router       /world/orchestrator/rollouts [post]
+func (c *serverRegistryController) CreateRollout(ctx *gin.Context) {
+	var req dtos.RolloutRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		_ = ctx.Error(errors.NewBadRequestError("invalid request body: " + err.Error()))
+		return
+	}
+	if req.Image == "" && req.Tag == "" {
+		_ = ctx.Error(errors.NewBadRequestError("image or tag is required"))
 		return
 	}
 
-	for _, zone := range activeZones {
-		err := c.nomadJobSenderService.StartNewJob(zone.WorldID, zone.ID, false)
-		if err != nil {
-			_ = ctx.Error(errors.NewInternalServerError("Failed to start new job."))
-			return
+	c.createRollout(ctx, req, "admin")
+}
+
+func (c *serverRegistryController) createRollout(ctx *gin.Context, req dtos.RolloutRequest, source string) {
+	rolloutReq := rollout.RolloutRequest{
+		Image:     req.Image,
+		Source:    source,
+		BatchSize: req.BatchSize,
+	}
+	if rolloutReq.Image == "" && req.Tag != "" {
+		rolloutReq.Image = rollout.ImageWithTag(c.conf.FTRServerImage, req.Tag)
+	}
+	if rolloutReq.Image == "" {
+		rolloutReq.Image = c.conf.FTRServerImage
+	}
+	if req.BatchPauseSeconds != nil {
+		batchPause := time.Duration(*req.BatchPauseSeconds) * time.Second
+		rolloutReq.BatchPause = &batchPause
+	}
+	if req.CanaryZones != nil {
+		rolloutReq.CanaryZones = make([]orchestrator.ZoneKey, 0, len(req.CanaryZones))
+		for _, zone := range req.CanaryZones {
+			worldId, err := uuid.Parse(zone.WorldID)
+			if err != nil {
+				_ = ctx.Error(errors.NewBadRequestError("invalid canary world_id: " + zone.WorldID))
+				return
+			}
+			rolloutReq.CanaryZones = append(rolloutReq.CanaryZones, orchestrator.ZoneKey{WorldId: worldId, ZoneId: zone.ZoneID})
 		}
 	}
 
-	common_handlers.HandleBodilessResponse(ctx, http.StatusOK)
+	deployment, err := c.rolloutService.CreateRollout(rolloutReq)
+	if err != nil {
+		c.handleRolloutError(ctx, err, "Failed to start rollout.")
+		return
+	}
+
+	common_handlers.SetAuditTarget(ctx, deployment.ID.String())
+	common_handlers.HandleSuccessResponse(ctx, http.StatusCreated, rolloutResponse(deployment))
+}
+
+// ListRollouts godoc
+// @Summary      List server image rollouts
+// @Description  Returns the game server image rollouts from newest to oldest. Requires world.jobs.read.
+// @Tags         world-service
+// @Security     BearerAuth
+// @Produce      json
+// @Param        offset query int false "Offset for pagination"
+// @Param        limit query int false "Max hits per page"
+// @Success      200  {object}  dtos.RolloutsListResponse
+// @Failure      400  {object} dtos.ErrorResponse
+// @Failure      403  {object} dtos.ErrorResponse
+// @Failure      500  {object} dtos.ErrorResponse
+// @Router       /world/orchestrator/rollouts [get]
+func (c *serverRegistryController) ListRollouts(ctx *gin.Context) {
+	offsetStr := ctx.DefaultQuery("offset", "0")
+	limitStr := ctx.DefaultQuery("limit", "20")
+
+	offset, err := strconv.Atoi(offsetStr)
+	if err != nil || offset < 0 {
+		_ = ctx.Error(errors.NewBadRequestError("invalid offset: " + offsetStr))
+		return
+	}
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil || limit <= 0 || limit > 100 {
+		_ = ctx.Error(errors.NewBadRequestError("invalid limit: " + limitStr))
+		return
+	}
+
+	deployments, err := c.rolloutService.ListRollouts(offset, limit)
+	if err != nil {
+		_ = ctx.Error(errors.NewInternalServerError("Failed to list rollouts."))
+		return
+	}
+
+	response := &dtos.RolloutsListResponse{Rollouts: make([]dtos.RolloutResponse, 0, len(deployments))}
+	for _, deployment := range deployments {
+		response.Rollouts = append(response.Rollouts, *rolloutResponse(deployment))
+	}
+
+	common_handlers.HandleSuccessResponse(ctx, http.StatusOK, response)
+}
+
+// GetRollout godoc
+// @Summary      Get server image rollout
+// @Description  Returns a game server image rollout with the batch and update status of each of its zones. Requires world.jobs.read.
+// @Tags         world-service
+// @Security     BearerAuth
+// @Produce      json
+// @Param        rollout_id path string true "Rollout UUID"
+// @Success      200  {object}  dtos.RolloutDetailResponse
+// @Failure      400  {object} dtos.ErrorResponse
+// @Failure      403  {object} dtos.ErrorResponse
+// @Failure      404  {object} dtos.ErrorResponse
+// @Router       /world/orchestrator/rollouts/{rollout_id} [get]
+func (c *serverRegistryController) GetRollout(ctx *gin.Context) {
+	rolloutIdStr := ctx.Param("rollout_id")
+	rolloutId, err := uuid.Parse(rolloutIdStr)
+	if err != nil {
+		_ = ctx.Error(errors.NewBadRequestError("invalid rollout ID: " + rolloutIdStr))
+		return
+	}
+
+	deployment, zones, err := c.rolloutService.GetRollout(rolloutId)
+	if err != nil {
+		c.handleRolloutError(ctx, err, "Failed to get rollout.")
+		return
+	}
+
+	response := &dtos.RolloutDetailResponse{
+		RolloutResponse: *rolloutResponse(deployment),
+		Zones:           make([]dtos.RolloutZoneResponse, 0, len(zones)),
+	}
+	for _, zone := range zones {
+		response.Zones = append(response.Zones, dtos.RolloutZoneResponse{
+			WorldID:   zone.WorldID.String(),
+			ZoneID:    zone.ZoneID,
+			Batch:     zone.Batch,
+			IsCanary:  zone.IsCanary,
+			Status:    zone.Status,
+			Error:     zone.Error,
+			UpdatedAt: zone.UpdatedAt,
+		})
+	}
+
+	common_handlers.HandleSuccessResponse(ctx, http.StatusOK, response)
+}
+
+// PauseRollout godoc
+// @Summary      Pause server image rollout
+// @Description  Stops a running rollout from updating more zones, zones already updating keep going. Requires world.jobs.write.
+// @Tags         world-service
+// @Security     BearerAuth
+// @Produce      json
+// @Param        rollout_id path string true "Rollout UUID"
+// @Success      200  {object}  dtos.RolloutResponse
+// @Failure      400  {object} dtos.ErrorResponse
+// @Failure      403  {object} dtos.ErrorResponse
+// @Failure      404  {object} dtos.ErrorResponse
+// @Failure      409  {object} dtos.ErrorResponse
+// @Router       /world/orchestrator/rollouts/{rollout_id}/pause [post]
+func (c *serverRegistryController) PauseRollout(ctx *gin.Context) {
+	c.changeRollout(ctx, c.rolloutService.PauseRollout)
+}
+
+// ResumeRollout godoc
+// @Summary      Resume server image rollout
+// @Description  Continues a paused rollout where it stopped. Requires world.jobs.write.
+// @Tags         world-service
+// @Security     BearerAuth
+// @Produce      json
+// @Param        rollout_id path string true "Rollout UUID"
+// @Success      200  {object}  dtos.RolloutResponse
+// @Failure      400  {object} dtos.ErrorResponse
+// @Failure      403  {object} dtos.ErrorResponse
+// @Failure      404  {object} dtos.ErrorResponse
+// @Failure      409  {object} dtos.ErrorResponse
+// @Router       /world/orchestrator/rollouts/{rollout_id}/resume [post]
+func (c *serverRegistryController) ResumeRollout(ctx *gin.Context) {
+	c.changeRollout(ctx, c.rolloutService.ResumeRollout)
+}
+
+// AbortRollout godoc
+// @Summary      Abort server image rollout
+// @Description  Stops a running or paused rollout and rolls every zone it updated back to the previous image. Requires world.jobs.write.
+// @Tags         world-service
+// @Security     BearerAuth
+// @Produce      json
+// @Param        rollout_id path string true "Rollout UUID"
+// @Success      200  {object}  dtos.RolloutResponse
+// @Failure      400  {object} dtos.ErrorResponse
+// @Failure      403  {object} dtos.ErrorResponse
+// @Failure      404  {object} dtos.ErrorResponse
+// @Failure      409  {object} dtos.ErrorResponse
+// @Router       /world/orchestrator/rollouts/{rollout_id}/abort [post]
+func (c *serverRegistryController) AbortRollout(ctx *gin.Context) {
+	c.changeRollout(ctx, c.rolloutService.AbortRollout)
+}
+
+func (c *serverRegistryController) changeRollout(ctx *gin.Context, change func(id uuid.UUID) (*models.ServerDeployment, error)) {
+	rolloutIdStr := ctx.Param("rollout_id")
+	rolloutId, err := uuid.Parse(rolloutIdStr)
+	if err != nil {
+		_ = ctx.Error(errors.NewBadRequestError("invalid rollout ID: " + rolloutIdStr))
+		return
+	}
+
+	deployment, err := change(rolloutId)
+	if err != nil {
+		c.handleRolloutError(ctx, err, "Failed to update rollout.")
+		return
+	}
+
+	common_handlers.HandleSuccessResponse(ctx, http.StatusOK, rolloutResponse(deployment))
+}
+
+func (c *serverRegistryController) handleRolloutError(ctx *gin.Context, err error, message string) {
+	switch err.(type) {
+	case *world_errors.DeploymentNotFound:
+		_ = ctx.Error(errors.NewNotFoundError(err.Error()))
+	case *world_errors.DeploymentStateConflict:
+		_ = ctx.Error(errors.NewConflictError(err.Error()))
+	default:
+		logger.Logger.Errorf("rollout request failed: %v", err)
+		_ = ctx.Error(errors.NewInternalServerError(message))
+	}
+}
+
+func rolloutResponse(deployment *models.ServerDeployment) *dtos.RolloutResponse {
+	return &dtos.RolloutResponse{
+		ID:                deployment.ID.String(),
+		Image:             deployment.Image,
+		PreviousImage:     deployment.PreviousImage,
+		Status:            deployment.Status,
+		Source:            deployment.Source,
+		BatchSize:         deployment.BatchSize,
+		BatchPauseSeconds: deployment.BatchPauseSeconds,
+		CurrentBatch:      deployment.CurrentBatch,
+		TotalBatches:      deployment.TotalBatches,
+		Error:             deployment.Error,
+		CreatedAt:         deployment.CreatedAt,
+		UpdatedAt:         deployment.UpdatedAt,
+		FinishedAt:        deployment.FinishedAt,
+	}
 }
 
 // UpdateStatus godoc
@@ -331,3 +582,190 @@ func (c *serverRegistryController) GetAllWorldPlayerCounts(ctx *gin.Context) {
 
 	common_handlers.HandleSuccessResponse(ctx, http.StatusOK, responses)
 }
+
+// VerifyServerTokenInternal godoc
+// @Summary      Verify game server token (internal)
+// @Description  Resolves the world zone a game server token was issued for, only reachable by other services.
+// @Tags         world-service-internal
+// @Accept       json
+// @Produce      json
+// @Param        request body dtos.VerifyServerTokenRequest true "Server token"
+// @Success      200  {object}  dtos.ServerScopeResponse
+// @Failure      400  {object} dtos.ErrorResponse
+// @Failure      401  {object} dtos.ErrorResponse
+// @Router       /world/internal/servers/verify [post]
+func (c *serverRegistryController) VerifyServerTokenInternal(ctx *gin.Context) {
+	var req dtos.VerifyServerTokenRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		_ = ctx.Error(errors.NewBadRequestError("invalid request body: " + err.Error()))
+		return
+	}
+
+	worldId, zoneId, err := c.credentialsService.VerifyCredential(req.Token)
+	if err != nil {
+		if _, ok := err.(*world_errors.ServerCredentialNotFound); ok {
+			_ = ctx.Error(errors.NewUnauthorizedError("invalid server token"))
+			return
+		}
+		logger.Logger.Errorf("failed to verify server token: %v", err)
+		_ = ctx.Error(errors.NewInternalServerError("Failed to verify server token."))
+		return
+	}
+
+	common_handlers.HandleSuccessResponse(ctx, http.StatusOK, dtos.ServerScopeResponse{
+		WorldID: worldId.String(),
+		ZoneID:  zoneId,
+	})
+}
+
+// GetReconcileStatus godoc
+// @Summary      Zone reconciler status
+// @Description  Returns whether the replica answering runs the reconciler that compares Nomad jobs and Consul health with the zone states, and the report of its last pass. Only the leader replica reconciles, the others report is_leader false. Requires world.jobs.read.
+// @Tags         world-service
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200  {object}  dtos.ReconcileStatusResponse
+// @Failure      403  {object} dtos.ErrorResponse
+// @Router       /world/orchestrator/reconcile/status [get]
+func (c *serverRegistryController) GetReconcileStatus(ctx *gin.Context) {
+	status := c.reconcilerService.Status()
+
+	response := &dtos.ReconcileStatusResponse{
+		Enabled:         status.Enabled,
+		IsLeader:        status.IsLeader,
+		IntervalSeconds: int(status.Interval.Seconds()),
+	}
+	if report := status.LastReport; report != nil {
+		response.LastReport = &dtos.ReconcileReportResponse{
+			StartedAt:     report.StartedAt,
+			FinishedAt:    report.FinishedAt,
+			JobsFound:     report.JobsFound,
+			HealthyZones:  report.HealthyZones,
+			Restarted:     zoneKeysResponse(report.Restarted),
+			Stopped:       zoneKeysResponse(report.Stopped),
+			MarkedOnline:  zoneKeysResponse(report.MarkedOnline),
+			MarkedOffline: zoneKeysResponse(report.MarkedOffline),
+			Errors:        report.Errors,
+		}
+		if response.LastReport.Errors == nil {
+			response.LastReport.Errors = []string{}
+		}
+	}
+
+	common_handlers.HandleSuccessResponse(ctx, http.StatusOK, response)
+}
+
+// Heartbeat godoc
+// @Summary      Zone server heartbeat
+// @Description  Game servers report the address players reach them on every few seconds with their zone credential. The address is then served by the address endpoint without asking the orchestrator, and the zone is marked offline once the heartbeats stop for longer than WORLD_SERVER_HEARTBEAT_TIMEOUT.
+// @Tags         world-service
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "World UUID"
+// @Param        zone_id path int true "World Zone Number"
+// @Param        request body dtos.ServerHeartbeatRequest true "Heartbeat payload"
+// @Success      200  {string}  string "OK"
+// @Failure      400  {object} dtos.ErrorResponse
+// @Failure      403  {object} dtos.ErrorResponse
+// @Failure      500  {object} dtos.ErrorResponse
+// @Router       /world/orchestrator/{id}/zones/{zone_id}/heartbeat [post]
+func (c *serverRegistryController) Heartbeat(ctx *gin.Context) {
+	worldIdStr := ctx.Param("id")
+	zoneIdStr := ctx.Param("zone_id")
+
+	worldId, err := uuid.Parse(worldIdStr)
+	if err != nil {
+		_ = ctx.Error(errors.NewBadRequestError("invalid world ID: " + worldIdStr))
+		return
+	}
+
+	zoneId, err := strconv.Atoi(zoneIdStr)
+	if err != nil {
+		_ = ctx.Error(errors.NewBadRequestError("invalid zone ID: " + zoneIdStr))
+		return
+	}
+
+	var req dtos.ServerHeartbeatRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		_ = ctx.Error(errors.NewBadRequestError("invalid request body: " + err.Error()))
+		return
+	}
+
+	err = c.nomadJobSenderService.Heartbeat(worldId, zoneId, server_registry.ZoneServerHeartbeat{
+		AllocID:      req.AllocID,
+		Address:      req.Address,
+		Port:         req.Port,
+		ImageVersion: req.ImageVersion,
+	})
+	if err != nil {
+		logger.Logger.Errorf("failed to record heartbeat: %v", err)
+		_ = ctx.Error(errors.NewInternalServerError("Failed to record heartbeat."))
+		return
+	}
+
+	common_handlers.HandleBodilessResponse(ctx, http.StatusOK)
+}
+
+// ListServers godoc
+// @Summary      List zone servers
+// @Description  Returns the registered zone servers with their job, allocation, address, image and last heartbeat. Requires world.jobs.read.
+// @Tags         world-service
+// @Security     BearerAuth
+// @Produce      json
+// @Param        world_id query string false "Only the servers of this world"
+// @Param        image_version query string false "Only the servers running this image"
+// @Param        stale query bool false "Only the servers whose heartbeats timed out, including servers that never sent one"
+// @Success      200  {object}  dtos.ZoneServersListResponse
+// @Failure      400  {object} dtos.ErrorResponse
+// @Failure      403  {object} dtos.ErrorResponse
+// @Failure      500  {object} dtos.ErrorResponse
+// @Router       /world/orchestrator/servers [get]
+func (c *serverRegistryController) ListServers(ctx *gin.Context) {
+	timedOutBefore := time.Now().UTC().Add(-c.conf.ServerRegistry.HeartbeatTimeout)
+	filter := server_registry_repo.ZoneServerFilter{ImageVersion: ctx.Query("image_version")}
+
+	if worldIdStr := ctx.Query("world_id"); worldIdStr != "" {
+		worldId, err := uuid.Parse(worldIdStr)
+		if err != nil {
+			_ = ctx.Error(errors.NewBadRequestError("invalid world_id: " + worldIdStr))
+			return
+		}
+		filter.WorldID = worldId
+	}
+	if ctx.Query("stale") == "true" {
+		filter.HeartbeatBefore = timedOutBefore
+	}
+
+	servers, err := c.nomadJobSenderService.ListServers(filter)
+	if err != nil {
+		_ = ctx.Error(errors.NewInternalServerError("Failed to list zone servers."))
+		return
+	}
+
+	response := &dtos.ZoneServersListResponse{Servers: make([]dtos.ZoneServerResponse, 0, len(servers))}
+	for _, server := range servers {
+		response.Servers = append(response.Servers, dtos.ZoneServerResponse{
+			WorldID:          server.WorldID.String(),
+			ZoneID:           server.ZoneID,
+			JobID:            server.JobID,
+			AllocID:          server.AllocID,
+			Address:          server.Address,
+			Port:             server.Port,
+			ImageVersion:     server.ImageVersion,
+			StartedAt:        server.StartedAt,
+			LastHeartbeatAt:  server.LastHeartbeatAt,
+			HeartbeatTimeout: server.LastSeenAt().Before(timedOutBefore),
+		})
+	}
+
+	common_handlers.HandleSuccessResponse(ctx, http.StatusOK, response)
+}
+
+func zoneKeysResponse(keys []orchestrator.ZoneKey) []dtos.ZoneKeyResponse {
+	response := make([]dtos.ZoneKeyResponse, 0, len(keys))
+	for _, key := range keys {
+		response = append(response, dtos.ZoneKeyResponse{WorldID: key.WorldId.String(), ZoneID: key.ZoneId})
+	}
+	return response
+}
@@ -165,6 +165,7 @@ func (c *worldController) GetWorld(ctx *gin.Context) {
 	for _, zone := range zones {
 		zoneMetadata = append(zoneMetadata, dtos.WorldZoneMetadata{
 			ZoneID:   zone.ID,
+			Tier:     c.conf.ZoneTiers.TierOrDefault(zone.Tier).Name,
 			IsActive: zone.IsActive,
 			IsOnline: zone.IsOnline,
 		})
@@ -264,6 +265,7 @@ func (c *worldController) GetWorldsList(ctx *gin.Context) {
 		for _, zone := range zones {
 			zoneMetadata = append(zoneMetadata, dtos.WorldZoneMetadata{
 				ZoneID:   zone.ID,
+				Tier:     c.conf.ZoneTiers.TierOrDefault(zone.Tier).Name,
 				IsActive: zone.IsActive,
 				IsOnline: zone.IsOnline,
 			})
@@ -19,6 +19,12 @@ type ZonesController interface {
 	// DeactivateZone stops server orchestration for an active zone.
 	DeactivateZone(c *gin.Context)
 
+	// SetZoneTier selects the resource tier a zone runs on.
+	SetZoneTier(c *gin.Context)
+
+	// GetZoneTiers lists the resource tiers a zone can run on.
+	GetZoneTiers(c *gin.Context)
+
 	// StopAllZonesForUser stops all active zones for a specific user.
 	StopAllJobsForUser(c *gin.Context)
 }
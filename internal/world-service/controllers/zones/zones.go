@@ -3,6 +3,7 @@ package zones
 import (
 	"encoding/json"
 	"net/http"
+	"sort"
 	"strconv"
 
 	"github.com/FeedTheRealm-org/core-service/config"
@@ -98,6 +99,8 @@ func (c *zonesController) PublishZone(ctx *gin.Context) {
 		WorldID:  zone.WorldID.String(),
 		ZoneID:   zone.ID,
 		ZoneData: string(zone.ZoneData),
+		Tier:     c.conf.ZoneTiers.TierOrDefault(zone.Tier).Name,
+		Slots:    zone.Slots,
 		IsActive: zone.IsActive,
 		IsOnline: zone.IsOnline,
 	})
@@ -139,6 +142,7 @@ func (c *zonesController) GetWorldZones(ctx *gin.Context) {
 	for _, zone := range zones {
 		zoneMetadata = append(zoneMetadata, dtos.WorldZoneMetadata{
 			ZoneID:   zone.ID,
+			Tier:     c.conf.ZoneTiers.TierOrDefault(zone.Tier).Name,
 			IsActive: zone.IsActive,
 			IsOnline: zone.IsOnline,
 		})
@@ -193,6 +197,8 @@ func (c *zonesController) GetWorldZoneData(ctx *gin.Context) {
 		WorldID:  worldID.String(),
 		ZoneID:   zone.ID,
 		ZoneData: zone.ZoneData.String(),
+		Tier:     c.conf.ZoneTiers.TierOrDefault(zone.Tier).Name,
+		Slots:    zone.Slots,
 		IsActive: zone.IsActive,
 		IsOnline: zone.IsOnline,
 	})
@@ -200,7 +206,7 @@ func (c *zonesController) GetWorldZoneData(ctx *gin.Context) {
 
 // ActivateZone godoc
 // @Summary      Activate zone
-// @Description  Starts server orchestration for a published zone and consumes one subscription slot.
+// @Description  Starts server orchestration for a published zone and consumes the subscription slots of its tier.
 // @Tags         world-service
 // @Security     BearerAuth
 // @Produce      json
@@ -256,7 +262,7 @@ func (c *zonesController) ActivateZone(ctx *gin.Context) {
 
 // DeactivateZone godoc
 // @Summary      Deactivate zone
-// @Description  Stops server orchestration for an active zone and releases one subscription slot.
+// @Description  Stops server orchestration for an active zone and releases the subscription slots it holds.
 // @Tags         world-service
 // @Security     BearerAuth
 // @Produce      json
@@ -310,6 +316,120 @@ func (c *zonesController) DeactivateZone(ctx *gin.Context) {
 	common_handlers.HandleBodilessResponse(ctx, http.StatusOK)
 }
 
+// SetZoneTier godoc
+// @Summary      Set zone tier
+// @Description  Selects the resource tier a zone runs on. An active zone pays the slot difference and is restarted on the new tier.
+// @Tags         world-service
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "World UUID"
+// @Param        zone_id path int true "Zone ID"
+// @Param        request body dtos.SetZoneTierRequest true "Zone tier"
+// @Success      200  {string}  string "Acknowledge tier change"
+// @Failure      400  {object} dtos.ErrorResponse
+// @Failure      401  {object} dtos.ErrorResponse
+// @Failure      403  {object} dtos.ErrorResponse
+// @Failure      404  {object} dtos.ErrorResponse
+// @Failure      500  {object} dtos.ErrorResponse
+// @Router       /world/{id}/zones/{zone_id}/tier [put]
+func (c *zonesController) SetZoneTier(ctx *gin.Context) {
+	userId, err := common_handlers.GetUserIDFromSession(ctx)
+	if err != nil {
+		_ = ctx.Error(errors.NewUnauthorizedError(err.Error()))
+		return
+	}
+
+	worldID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		_ = ctx.Error(errors.NewBadRequestError("invalid world_id: " + ctx.Param("id")))
+		return
+	}
+
+	zoneID, err := strconv.Atoi(ctx.Param("zone_id"))
+	if err != nil || zoneID <= 0 {
+		_ = ctx.Error(errors.NewBadRequestError("zone_id must be a positive integer"))
+		return
+	}
+
+	var req dtos.SetZoneTierRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		_ = ctx.Error(errors.NewBadRequestError("invalid JSON payload: " + err.Error()))
+		return
+	}
+
+	worldInfo, err := c.zonesService.GetWorld(worldID)
+	if err != nil {
+		if _, ok := err.(*world_errors.WorldInfoNotFound); ok {
+			_ = ctx.Error(errors.NewNotFoundError("world info not found"))
+			return
+		}
+		_ = ctx.Error(err)
+		return
+	}
+
+	if worldInfo.UserId != userId {
+		_ = ctx.Error(errors.NewUnauthorizedError("user does not own this world"))
+		return
+	}
+
+	err = c.zonesService.SetZoneTier(worldID, zoneID, req.Tier)
+	if err != nil {
+		switch err.(type) {
+		case *world_errors.UnknownZoneTier:
+			_ = ctx.Error(errors.NewBadRequestError(err.Error()))
+		case *world_errors.ZoneNotFound:
+			_ = ctx.Error(errors.NewNotFoundError("zone not found"))
+		case *world_errors.ZoneSlotsUnavailable:
+			_ = ctx.Error(errors.NewForbiddenError(err.Error()))
+		default:
+			_ = ctx.Error(err)
+		}
+		return
+	}
+
+	common_handlers.HandleBodilessResponse(ctx, http.StatusOK)
+}
+
+// GetZoneTiers godoc
+// @Summary      List zone tiers
+// @Description  Returns the resource tiers a zone can run on and the subscription slots each one takes.
+// @Tags         world-service
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200  {object}  dtos.ZoneTiersResponse
+// @Failure      401  {object} dtos.ErrorResponse
+// @Router       /world/zone-tiers [get]
+func (c *zonesController) GetZoneTiers(ctx *gin.Context) {
+	if _, err := common_handlers.GetUserIDFromSession(ctx); err != nil {
+		_ = ctx.Error(errors.NewUnauthorizedError(err.Error()))
+		return
+	}
+
+	tiers := make([]dtos.ZoneTierResponse, 0, len(c.conf.ZoneTiers.Tiers))
+	for _, tier := range c.conf.ZoneTiers.Tiers {
+		tiers = append(tiers, dtos.ZoneTierResponse{
+			Name:        tier.Name,
+			CPU:         tier.CPU,
+			MemoryMB:    tier.MemoryMB,
+			MemoryMaxMB: tier.MemoryMaxMB,
+			Datacenter:  tier.Datacenter,
+			Slots:       tier.Slots,
+		})
+	}
+	sort.Slice(tiers, func(i, j int) bool {
+		if tiers[i].Slots != tiers[j].Slots {
+			return tiers[i].Slots < tiers[j].Slots
+		}
+		return tiers[i].CPU < tiers[j].CPU
+	})
+
+	common_handlers.HandleSuccessResponse(ctx, http.StatusOK, &dtos.ZoneTiersResponse{
+		Default: c.conf.ZoneTiers.Default,
+		Tiers:   tiers,
+	})
+}
+
 // StopAllJobsForUser godoc
 // @Summary      Stop all jobs for a user
 // @Description  Internal endpoint to stop all active zones and jobs for a specific user. Triggered internally by the payment service on subscription cancellation.
@@ -16,6 +16,10 @@ type PublishZoneRequest struct {
 	Data any `json:"data"`
 }
 
+type SetZoneTierRequest struct {
+	Tier string `json:"tier" binding:"required"`
+}
+
 type UpdateStatusRequest struct {
 	IsOnline bool `json:"is_online"`
 }
@@ -24,3 +28,27 @@ type UpdatePlayerCountRequest struct {
 	ActivePlayers     int `json:"active_players"`
 	AveragePlayerTime int `json:"average_player_time"`
 }
+
+type ServerHeartbeatRequest struct {
+	Address      string `json:"address" binding:"required"`
+	Port         int    `json:"port" binding:"required,min=1,max=65535"`
+	AllocID      string `json:"alloc_id"`
+	ImageVersion string `json:"image_version"`
+}
+
+type VerifyServerTokenRequest struct {
+	Token string `json:"token"`
+}
+
+type ZoneKeyRequest struct {
+	WorldID string `json:"world_id" binding:"required"`
+	ZoneID  int    `json:"zone_id"`
+}
+
+type RolloutRequest struct {
+	Image             string           `json:"image"`
+	Tag               string           `json:"tag"`
+	BatchSize         int              `json:"batch_size" binding:"min=0"`
+	BatchPauseSeconds *int             `json:"batch_pause_seconds" binding:"omitempty,min=0"`
+	CanaryZones       []ZoneKeyRequest `json:"canary_zones"`
+}
@@ -35,9 +35,10 @@ type WorldMetadata struct {
 }
 
 type WorldZoneMetadata struct {
-	ZoneID   int  `json:"zone_id"`
-	IsActive bool `json:"is_active"`
-	IsOnline bool `json:"is_online"`
+	ZoneID   int    `json:"zone_id"`
+	Tier     string `json:"tier"`
+	IsActive bool   `json:"is_active"`
+	IsOnline bool   `json:"is_online"`
 }
 
 type WorldAddressResponse struct {
@@ -54,15 +55,112 @@ type WorldZoneResponse struct {
 	WorldID           string `json:"world_id"`
 	ZoneID            int    `json:"zone_id"`
 	ZoneData          string `json:"zone_data"`
+	Tier              string `json:"tier"`
+	Slots             int    `json:"slots"`
 	IsActive          bool   `json:"is_active"`
 	IsOnline          bool   `json:"is_online"`
 	ActivePlayers     int    `json:"active_players"`
 	AveragePlayerTime int    `json:"average_player_time"`
 }
 
+type ZoneTierResponse struct {
+	Name        string `json:"name"`
+	CPU         int    `json:"cpu"`
+	MemoryMB    int    `json:"memory_mb"`
+	MemoryMaxMB int    `json:"memory_max_mb"`
+	Datacenter  string `json:"datacenter"`
+	Slots       int    `json:"slots"`
+}
+
+type ZoneTiersResponse struct {
+	Default string             `json:"default"`
+	Tiers   []ZoneTierResponse `json:"tiers"`
+}
+
 type PlayerCountsResponse struct {
 	ActivePlayers        int `json:"active_players"`
 	AveragePlayerTime    int `json:"average_player_time"`
 	MaxActivePlayers     int `json:"max_active_players"`
 	MaxAveragePlayerTime int `json:"max_average_player_time"`
 }
+
+type ServerScopeResponse struct {
+	WorldID string `json:"world_id"`
+	ZoneID  int    `json:"zone_id"`
+}
+
+type ZoneKeyResponse struct {
+	WorldID string `json:"world_id"`
+	ZoneID  int    `json:"zone_id"`
+}
+
+type ReconcileReportResponse struct {
+	StartedAt     time.Time         `json:"started_at"`
+	FinishedAt    time.Time         `json:"finished_at"`
+	JobsFound     int               `json:"jobs_found"`
+	HealthyZones  int               `json:"healthy_zones"`
+	Restarted     []ZoneKeyResponse `json:"restarted"`
+	Stopped       []ZoneKeyResponse `json:"stopped"`
+	MarkedOnline  []ZoneKeyResponse `json:"marked_online"`
+	MarkedOffline []ZoneKeyResponse `json:"marked_offline"`
+	Errors        []string          `json:"errors"`
+}
+
+type ReconcileStatusResponse struct {
+	Enabled         bool                     `json:"enabled"`
+	IsLeader        bool                     `json:"is_leader"`
+	IntervalSeconds int                      `json:"interval_seconds"`
+	LastReport      *ReconcileReportResponse `json:"last_report"`
+}
+
+type ZoneServerResponse struct {
+	WorldID          string     `json:"world_id"`
+	ZoneID           int        `json:"zone_id"`
+	JobID            string     `json:"job_id"`
+	AllocID          string     `json:"alloc_id"`
+	Address          string     `json:"address"`
+	Port             int        `json:"port"`
+	ImageVersion     string     `json:"image_version"`
+	StartedAt        time.Time  `json:"started_at"`
+	LastHeartbeatAt  *time.Time `json:"last_heartbeat_at"`
+	HeartbeatTimeout bool       `json:"heartbeat_timed_out"`
+}
+
+type ZoneServersListResponse struct {
+	Servers []ZoneServerResponse `json:"servers"`
+}
+
+type RolloutResponse struct {
+	ID                string     `json:"id"`
+	Image             string     `json:"image"`
+	PreviousImage     string     `json:"previous_image"`
+	Status            string     `json:"status"`
+	Source            string     `json:"source"`
+	BatchSize         int        `json:"batch_size"`
+	BatchPauseSeconds int        `json:"batch_pause_seconds"`
+	CurrentBatch      int        `json:"current_batch"`
+	TotalBatches      int        `json:"total_batches"`
+	Error             string     `json:"error"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+	FinishedAt        *time.Time `json:"finished_at"`
+}
+
+type RolloutZoneResponse struct {
+	WorldID   string    `json:"world_id"`
+	ZoneID    int       `json:"zone_id"`
+	Batch     int       `json:"batch"`
+	IsCanary  bool      `json:"is_canary"`
+	Status    string    `json:"status"`
+	Error     string    `json:"error"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type RolloutDetailResponse struct {
+	RolloutResponse
+	Zones []RolloutZoneResponse `json:"zones"`
+}
+
+type RolloutsListResponse struct {
+	Rollouts []RolloutResponse `json:"rollouts"`
+}
@@ -15,6 +15,21 @@ func NewWorldNotFound(details string) *WorldInfoNotFound {
 	}
 }
 
+// ZoneNotFound is returned when a world has no zone with the given ID, or the world itself does not exist.
+type ZoneNotFound struct {
+	details string
+}
+
+func (e *ZoneNotFound) Error() string {
+	return e.details
+}
+
+func NewZoneNotFound(details string) *ZoneNotFound {
+	return &ZoneNotFound{
+		details: details,
+	}
+}
+
 // WorldNameTaken is returned when trying to create or update a character with a name that is already taken.
 type WorldNameTaken struct {
 	details string
@@ -29,3 +44,93 @@ func NewWorldNameTaken(details string) *WorldNameTaken {
 		details: details,
 	}
 }
+
+// ServerCredentialNotFound is returned when a game server token was never issued or has been revoked.
+type ServerCredentialNotFound struct {
+	details string
+}
+
+func (e *ServerCredentialNotFound) Error() string {
+	return e.details
+}
+
+func NewServerCredentialNotFound(details string) *ServerCredentialNotFound {
+	return &ServerCredentialNotFound{
+		details: details,
+	}
+}
+
+// ZoneServerNotFound is returned when a zone has no server running, or none able to take players.
+type ZoneServerNotFound struct {
+	details string
+}
+
+func (e *ZoneServerNotFound) Error() string {
+	return e.details
+}
+
+func NewZoneServerNotFound(details string) *ZoneServerNotFound {
+	return &ZoneServerNotFound{
+		details: details,
+	}
+}
+
+// DeploymentNotFound is returned when a game server image rollout does not exist, or none is in progress.
+type DeploymentNotFound struct {
+	details string
+}
+
+func (e *DeploymentNotFound) Error() string {
+	return e.details
+}
+
+func NewDeploymentNotFound(details string) *DeploymentNotFound {
+	return &DeploymentNotFound{
+		details: details,
+	}
+}
+
+// DeploymentStateConflict is returned when a rollout cannot move to the requested state from the one it is in.
+type DeploymentStateConflict struct {
+	details string
+}
+
+func (e *DeploymentStateConflict) Error() string {
+	return e.details
+}
+
+func NewDeploymentStateConflict(details string) *DeploymentStateConflict {
+	return &DeploymentStateConflict{
+		details: details,
+	}
+}
+
+// UnknownZoneTier is returned when a zone is set to a resource tier that is not offered.
+type UnknownZoneTier struct {
+	details string
+}
+
+func (e *UnknownZoneTier) Error() string {
+	return e.details
+}
+
+func NewUnknownZoneTier(details string) *UnknownZoneTier {
+	return &UnknownZoneTier{
+		details: details,
+	}
+}
+
+// ZoneSlotsUnavailable is returned when the subscription of the world owner has no room for the slots a zone needs.
+type ZoneSlotsUnavailable struct {
+	details string
+}
+
+func (e *ZoneSlotsUnavailable) Error() string {
+	return e.details
+}
+
+func NewZoneSlotsUnavailable(details string) *ZoneSlotsUnavailable {
+	return &ZoneSlotsUnavailable{
+		details: details,
+	}
+}
@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ServerCredential lets the game server launched for a world zone act for that zone only.
+// Only the SHA-256 hash of the token is stored, the token itself is handed to the job.
+// A server being started holds the pending token, the running server keeps its token until the new one started.
+type ServerCredential struct {
+	WorldID          uuid.UUID `gorm:"type:uuid;not null;primaryKey" json:"world_id"`
+	ZoneID           int       `gorm:"not null;primaryKey" json:"zone_id"`
+	TokenHash        *string   `gorm:"uniqueIndex" json:"-"`
+	PendingTokenHash *string   `gorm:"uniqueIndex" json:"-"`
+	CreatedAt        time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
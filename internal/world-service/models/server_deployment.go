@@ -0,0 +1,58 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	DEPLOYMENT_RUNNING      = "running"
+	DEPLOYMENT_PAUSED       = "paused"
+	DEPLOYMENT_SUCCEEDED    = "succeeded"
+	DEPLOYMENT_ROLLING_BACK = "rolling_back"
+	DEPLOYMENT_ROLLED_BACK  = "rolled_back"
+	DEPLOYMENT_ABORTING     = "aborting"
+	DEPLOYMENT_ABORTED      = "aborted"
+	DEPLOYMENT_SUPERSEDED   = "superseded"
+)
+
+const (
+	DEPLOYMENT_ZONE_PENDING     = "pending"
+	DEPLOYMENT_ZONE_UPDATING    = "updating"
+	DEPLOYMENT_ZONE_HEALTHY     = "healthy"
+	DEPLOYMENT_ZONE_FAILED      = "failed"
+	DEPLOYMENT_ZONE_SKIPPED     = "skipped"
+	DEPLOYMENT_ZONE_ROLLED_BACK = "rolled_back"
+)
+
+// ServerDeployment is the rollout of a game server image to every active zone, batch by batch.
+type ServerDeployment struct {
+	ID                uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	Image             string    `gorm:"not null" json:"image"`
+	PreviousImage     string    `gorm:"not null;default:''" json:"previous_image"`
+	Status            string    `gorm:"not null" json:"status"`
+	Source            string    `gorm:"not null;default:''" json:"source"`
+	BatchSize         int       `gorm:"not null" json:"batch_size"`
+	BatchPauseSeconds int       `gorm:"not null" json:"batch_pause_seconds"`
+	CurrentBatch      int       `gorm:"not null;default:0" json:"current_batch"`
+	TotalBatches      int       `gorm:"not null;default:0" json:"total_batches"`
+	// BatchFinishedAt is set once every zone of the current batch is healthy, the next batch starts after the pause.
+	BatchFinishedAt *time.Time `json:"batch_finished_at"`
+	Error           string     `gorm:"not null;default:''" json:"error"`
+	CreatedAt       time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt       time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+	FinishedAt      *time.Time `json:"finished_at"`
+}
+
+// DeploymentZone is a zone updated by a deployment and how far its update went.
+type DeploymentZone struct {
+	DeploymentID uuid.UUID `gorm:"type:uuid;not null;primaryKey" json:"deployment_id"`
+	WorldID      uuid.UUID `gorm:"type:uuid;not null;primaryKey" json:"world_id"`
+	ZoneID       int       `gorm:"not null;primaryKey" json:"zone_id"`
+	Batch        int       `gorm:"not null" json:"batch"`
+	IsCanary     bool      `gorm:"not null;default:false" json:"is_canary"`
+	Status       string    `gorm:"not null" json:"status"`
+	Error        string    `gorm:"not null;default:''" json:"error"`
+	UpdatedAt    time.Time `gorm:"not null" json:"updated_at"`
+}
@@ -0,0 +1,12 @@
+package models
+
+// UserData is everything the world-service stores about a user, as handed out in a data export.
+type UserData struct {
+	Worlds []UserWorld `json:"worlds"`
+}
+
+// UserWorld is a world owned by the user along with its zones.
+type UserWorld struct {
+	World *WorldData   `json:"world"`
+	Zones []*WorldZone `json:"zones"`
+}
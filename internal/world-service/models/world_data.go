@@ -8,14 +8,14 @@ import (
 )
 
 type WorldData struct {
-	ID                   uuid.UUID      `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
-	UserId               uuid.UUID      `gorm:"not null"`
-	Name                 string         `gorm:"unique;not null"`
-	Description          string         `gorm:"type:text"`
-	Data                 datatypes.JSON `gorm:"type:jsonb;not null"`
-	CreateableData       datatypes.JSON `gorm:"type:jsonb;not null;default:'{}'"`
-	MaxActivePlayers     int            `gorm:"not null;default:0"`
-	MaxAveragePlayerTime int            `gorm:"not null;default:0"`
-	CreatedAt            time.Time      `gorm:"autoCreateTime"`
-	UpdatedAt            time.Time      `gorm:"autoUpdateTime"`
+	ID                   uuid.UUID      `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	UserId               uuid.UUID      `gorm:"not null" json:"user_id"`
+	Name                 string         `gorm:"unique;not null" json:"name"`
+	Description          string         `gorm:"type:text" json:"description"`
+	Data                 datatypes.JSON `gorm:"type:jsonb;not null" json:"data"`
+	CreateableData       datatypes.JSON `gorm:"type:jsonb;not null;default:'{}'" json:"createable_data"`
+	MaxActivePlayers     int            `gorm:"not null;default:0" json:"max_active_players"`
+	MaxAveragePlayerTime int            `gorm:"not null;default:0" json:"max_average_player_time"`
+	CreatedAt            time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt            time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
 }
@@ -8,12 +8,16 @@ import (
 )
 
 type WorldZone struct {
-	ID                   int            `gorm:"not null;primaryKey"`
-	WorldID              uuid.UUID      `gorm:"type:uuid;not null;primaryKey"`
-	ZoneData             datatypes.JSON `gorm:"type:jsonb;not null"`
-	IsActive             bool           `gorm:"not null;default:false"`
-	IsOnline             bool           `gorm:"not null;default:false"`
-	ActivePlayers        int            `gorm:"not null;default:0"`
-	AveragePlayerTime    int            `gorm:"not null;default:0"`
-	PlayerCountUpdatedAt time.Time      `gorm:"autoUpdateTime"`
+	ID                   int            `gorm:"not null;primaryKey" json:"id"`
+	WorldID              uuid.UUID      `gorm:"type:uuid;not null;primaryKey" json:"world_id"`
+	ZoneData             datatypes.JSON `gorm:"type:jsonb;not null" json:"zone_data"`
+	IsActive             bool           `gorm:"not null;default:false" json:"is_active"`
+	IsOnline             bool           `gorm:"not null;default:false" json:"is_online"`
+	ActivePlayers        int            `gorm:"not null;default:0" json:"active_players"`
+	AveragePlayerTime    int            `gorm:"not null;default:0" json:"average_player_time"`
+	PlayerCountUpdatedAt time.Time      `gorm:"autoUpdateTime" json:"player_count_updated_at"`
+	// Tier is the resource tier the zone server runs on, the configured default one when empty.
+	Tier string `gorm:"not null;default:''" json:"tier"`
+	// Slots is how many subscription slots the zone holds while active, released on deactivation.
+	Slots int `gorm:"not null;default:0" json:"slots"`
 }
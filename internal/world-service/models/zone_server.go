@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ZoneServer is the game server running a world zone, registered when its job starts and
+// kept up to date by the heartbeats the server sends.
+type ZoneServer struct {
+	WorldID         uuid.UUID  `gorm:"type:uuid;not null;primaryKey" json:"world_id"`
+	ZoneID          int        `gorm:"not null;primaryKey" json:"zone_id"`
+	JobID           string     `gorm:"not null" json:"job_id"`
+	AllocID         string     `gorm:"not null;default:''" json:"alloc_id"`
+	Address         string     `gorm:"not null;default:''" json:"address"`
+	Port            int        `gorm:"not null;default:0" json:"port"`
+	ImageVersion    string     `gorm:"not null;default:''" json:"image_version"`
+	StartedAt       time.Time  `gorm:"not null" json:"started_at"`
+	LastHeartbeatAt *time.Time `json:"last_heartbeat_at"`
+}
+
+// LastSeenAt returns the time of the last heartbeat, or the start time for servers that never sent one.
+func (s *ZoneServer) LastSeenAt() time.Time {
+	if s.LastHeartbeatAt != nil {
+		return *s.LastHeartbeatAt
+	}
+	return s.StartedAt
+}
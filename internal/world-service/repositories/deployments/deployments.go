@@ -0,0 +1,123 @@
+package deployments
+
+import (
+	"time"
+
+	"github.com/FeedTheRealm-org/core-service/config"
+	core_errors "github.com/FeedTheRealm-org/core-service/internal/errors"
+	world_errors "github.com/FeedTheRealm-org/core-service/internal/world-service/errors"
+	"github.com/FeedTheRealm-org/core-service/internal/world-service/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var activeStatuses = []string{
+	models.DEPLOYMENT_RUNNING,
+	models.DEPLOYMENT_PAUSED,
+	models.DEPLOYMENT_ROLLING_BACK,
+	models.DEPLOYMENT_ABORTING,
+}
+
+type deploymentsRepository struct {
+	conf *config.Config
+	db   *config.DB
+}
+
+func NewDeploymentsRepository(conf *config.Config, db *config.DB) DeploymentsRepository {
+	return &deploymentsRepository{
+		conf: conf,
+		db:   db,
+	}
+}
+
+func (r *deploymentsRepository) CreateDeployment(deployment *models.ServerDeployment, zones []*models.DeploymentZone) error {
+	return r.db.Conn.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.ServerDeployment{}).
+			Where("status IN ?", []string{models.DEPLOYMENT_RUNNING, models.DEPLOYMENT_PAUSED}).
+			Updates(map[string]any{"status": models.DEPLOYMENT_SUPERSEDED, "finished_at": time.Now().UTC()}).Error
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Create(deployment).Error; err != nil {
+			return err
+		}
+		if len(zones) == 0 {
+			return nil
+		}
+		return tx.Create(&zones).Error
+	})
+}
+
+func (r *deploymentsRepository) GetDeployment(id uuid.UUID) (*models.ServerDeployment, error) {
+	var deployment models.ServerDeployment
+	if err := r.db.Conn.Where("id = ?", id).First(&deployment).Error; err != nil {
+		if core_errors.IsRecordNotFound(err) {
+			return nil, world_errors.NewDeploymentNotFound("deployment not found")
+		}
+		return nil, err
+	}
+	return &deployment, nil
+}
+
+func (r *deploymentsRepository) GetActiveDeployment() (*models.ServerDeployment, error) {
+	var deployment models.ServerDeployment
+	err := r.db.Conn.Where("status IN ?", activeStatuses).Order("created_at DESC").First(&deployment).Error
+	if err != nil {
+		if core_errors.IsRecordNotFound(err) {
+			return nil, world_errors.NewDeploymentNotFound("no deployment in progress")
+		}
+		return nil, err
+	}
+	return &deployment, nil
+}
+
+func (r *deploymentsRepository) GetCurrentImage() (string, error) {
+	var deployment models.ServerDeployment
+	err := r.db.Conn.Where("status = ?", models.DEPLOYMENT_SUCCEEDED).Order("finished_at DESC").First(&deployment).Error
+	if err != nil {
+		if core_errors.IsRecordNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	return deployment.Image, nil
+}
+
+func (r *deploymentsRepository) ListDeployments(offset int, limit int) ([]*models.ServerDeployment, error) {
+	var deployments []*models.ServerDeployment
+	if err := r.db.Conn.Order("created_at DESC").Offset(offset).Limit(limit).Find(&deployments).Error; err != nil {
+		return nil, err
+	}
+	return deployments, nil
+}
+
+func (r *deploymentsRepository) UpdateDeployment(deployment *models.ServerDeployment, fromStatus string) (bool, error) {
+	result := r.db.Conn.Model(&models.ServerDeployment{}).
+		Where("id = ? AND status = ?", deployment.ID, fromStatus).
+		Updates(map[string]any{
+			"status":            deployment.Status,
+			"current_batch":     deployment.CurrentBatch,
+			"batch_finished_at": deployment.BatchFinishedAt,
+			"error":             deployment.Error,
+			"finished_at":       deployment.FinishedAt,
+			"updated_at":        time.Now().UTC(),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *deploymentsRepository) GetDeploymentZones(deploymentID uuid.UUID) ([]*models.DeploymentZone, error) {
+	var zones []*models.DeploymentZone
+	err := r.db.Conn.Where("deployment_id = ?", deploymentID).Order("batch ASC, world_id ASC, zone_id ASC").Find(&zones).Error
+	if err != nil {
+		return nil, err
+	}
+	return zones, nil
+}
+
+func (r *deploymentsRepository) UpdateDeploymentZone(zone *models.DeploymentZone) error {
+	return r.db.Conn.Save(zone).Error
+}
@@ -0,0 +1,34 @@
+package deployments
+
+import (
+	"github.com/FeedTheRealm-org/core-service/internal/world-service/models"
+	"github.com/google/uuid"
+)
+
+// DeploymentsRepository stores the game server image rollouts and the zones they update.
+type DeploymentsRepository interface {
+	// CreateDeployment stores a deployment with its zones, superseding the running or paused deployment.
+	CreateDeployment(deployment *models.ServerDeployment, zones []*models.DeploymentZone) error
+
+	// GetDeployment retrieves a deployment by ID.
+	GetDeployment(id uuid.UUID) (*models.ServerDeployment, error)
+
+	// GetActiveDeployment retrieves the deployment that is running, paused or being rolled back.
+	GetActiveDeployment() (*models.ServerDeployment, error)
+
+	// GetCurrentImage returns the image of the last deployment that succeeded, empty when none has.
+	GetCurrentImage() (string, error)
+
+	// ListDeployments retrieves the deployments from newest to oldest.
+	ListDeployments(offset int, limit int) ([]*models.ServerDeployment, error)
+
+	// UpdateDeployment saves the progress of a deployment only if it is still in fromStatus,
+	// returning false when its status was changed in the meantime.
+	UpdateDeployment(deployment *models.ServerDeployment, fromStatus string) (bool, error)
+
+	// GetDeploymentZones retrieves the zones of a deployment ordered by batch.
+	GetDeploymentZones(deploymentID uuid.UUID) ([]*models.DeploymentZone, error)
+
+	// UpdateDeploymentZone saves the progress of a zone of a deployment.
+	UpdateDeploymentZone(zone *models.DeploymentZone) error
+}
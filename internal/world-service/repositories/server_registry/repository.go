@@ -1,14 +1,51 @@
 package server_registry
 
 import (
+	"time"
+
+	"github.com/FeedTheRealm-org/core-service/internal/world-service/models"
 	"github.com/google/uuid"
 )
 
+// ZoneServerFilter narrows down the listed zone servers, zero values match every server.
+type ZoneServerFilter struct {
+	WorldID      uuid.UUID
+	ImageVersion string
+	// HeartbeatBefore only matches servers that sent no heartbeat since this time,
+	// servers that never sent one count from their start.
+	HeartbeatBefore time.Time
+}
+
 // ServerRegistryRepository business logic for server registration.
 type ServerRegistryRepository interface {
-	// RegisterServer registers server in world-service.
-	RegisterServer(worldId uuid.UUID, zoneId int, address string)
+	// RegisterServer registers the server started for a zone, replacing the previous one.
+	RegisterServer(server *models.ZoneServer) error
+
+	// UnRegisterServer removes the server entry, zones without one are ignored.
+	UnRegisterServer(worldId uuid.UUID, zoneId int) error
+
+	// GetServer retrieves the server of a zone.
+	GetServer(worldId uuid.UUID, zoneId int) (*models.ZoneServer, error)
+
+	// RecordHeartbeat updates the address, allocation, image and last heartbeat of the server of a zone,
+	// registering it when it started without the registry knowing.
+	RecordHeartbeat(server *models.ZoneServer) error
+
+	// ListServers retrieves the servers matching the filter, ordered by world and zone.
+	ListServers(filter ZoneServerFilter) ([]*models.ZoneServer, error)
+
+	// SavePendingServerCredential stores the token of a server being started, the current token of the zone keeps working.
+	SavePendingServerCredential(worldId uuid.UUID, zoneId int, tokenHash string) error
+
+	// ActivatePendingServerCredential makes the pending token the token of the zone, the previous one stops working.
+	ActivatePendingServerCredential(worldId uuid.UUID, zoneId int) error
+
+	// DiscardPendingServerCredential drops the pending token of the zone and keeps its current one.
+	DiscardPendingServerCredential(worldId uuid.UUID, zoneId int) error
+
+	// GetServerCredentialByHash retrieves the credential whose current or pending token has the given hash.
+	GetServerCredentialByHash(tokenHash string) (*models.ServerCredential, error)
 
-	// UnRegisterServer removes the server entry.
-	UnRegisterServer(worldId uuid.UUID, zoneId int)
+	// DeleteServerCredential revokes the credential of a zone, zones without one are ignored.
+	DeleteServerCredential(worldId uuid.UUID, zoneId int) error
 }
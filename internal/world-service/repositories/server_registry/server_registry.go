@@ -1,15 +1,117 @@
 package server_registry
 
 import (
+	"fmt"
+
+	"github.com/FeedTheRealm-org/core-service/config"
+	core_errors "github.com/FeedTheRealm-org/core-service/internal/errors"
+	world_errors "github.com/FeedTheRealm-org/core-service/internal/world-service/errors"
+	"github.com/FeedTheRealm-org/core-service/internal/world-service/models"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
-type serverRegistryRepository struct{}
+type serverRegistryRepository struct {
+	conf *config.Config
+	db   *config.DB
+}
+
+func NewServerRegistryRepository(conf *config.Config, db *config.DB) ServerRegistryRepository {
+	return &serverRegistryRepository{
+		conf: conf,
+		db:   db,
+	}
+}
 
-func NewServerRegistryRepository() ServerRegistryRepository {
-	return &serverRegistryRepository{}
+func (sr *serverRegistryRepository) RegisterServer(server *models.ZoneServer) error {
+	return sr.db.Conn.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "world_id"}, {Name: "zone_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"job_id", "alloc_id", "address", "port", "image_version", "started_at", "last_heartbeat_at"}),
+	}).Create(server).Error
 }
 
-func (sr serverRegistryRepository) RegisterServer(worldId uuid.UUID, zoneId int, address string) {}
+func (sr *serverRegistryRepository) UnRegisterServer(worldId uuid.UUID, zoneId int) error {
+	return sr.db.Conn.Where("world_id = ? AND zone_id = ?", worldId, zoneId).Delete(&models.ZoneServer{}).Error
+}
 
-func (sr serverRegistryRepository) UnRegisterServer(worldId uuid.UUID, zoneId int) {}
+func (sr *serverRegistryRepository) GetServer(worldId uuid.UUID, zoneId int) (*models.ZoneServer, error) {
+	var server models.ZoneServer
+	if err := sr.db.Conn.Where("world_id = ? AND zone_id = ?", worldId, zoneId).First(&server).Error; err != nil {
+		if core_errors.IsRecordNotFound(err) {
+			return nil, world_errors.NewZoneServerNotFound(fmt.Sprintf("no server registered for world %s zone %d", worldId, zoneId))
+		}
+		return nil, err
+	}
+	return &server, nil
+}
+
+func (sr *serverRegistryRepository) RecordHeartbeat(server *models.ZoneServer) error {
+	return sr.db.Conn.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "world_id"}, {Name: "zone_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"alloc_id", "address", "port", "image_version", "last_heartbeat_at"}),
+	}).Create(server).Error
+}
+
+func (sr *serverRegistryRepository) ListServers(filter ZoneServerFilter) ([]*models.ZoneServer, error) {
+	query := sr.db.Conn.Order("world_id ASC, zone_id ASC")
+	if filter.WorldID != uuid.Nil {
+		query = query.Where("world_id = ?", filter.WorldID)
+	}
+	if filter.ImageVersion != "" {
+		query = query.Where("image_version = ?", filter.ImageVersion)
+	}
+	if !filter.HeartbeatBefore.IsZero() {
+		query = query.Where("COALESCE(last_heartbeat_at, started_at) < ?", filter.HeartbeatBefore)
+	}
+
+	var servers []*models.ZoneServer
+	if err := query.Find(&servers).Error; err != nil {
+		return nil, err
+	}
+	return servers, nil
+}
+
+func (sr *serverRegistryRepository) SavePendingServerCredential(worldId uuid.UUID, zoneId int, tokenHash string) error {
+	return sr.db.Conn.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "world_id"}, {Name: "zone_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"pending_token_hash"}),
+	}).Create(&models.ServerCredential{WorldID: worldId, ZoneID: zoneId, PendingTokenHash: &tokenHash}).Error
+}
+
+func (sr *serverRegistryRepository) ActivatePendingServerCredential(worldId uuid.UUID, zoneId int) error {
+	return sr.db.Conn.Model(&models.ServerCredential{}).
+		Where("world_id = ? AND zone_id = ? AND pending_token_hash IS NOT NULL", worldId, zoneId).
+		Updates(map[string]any{
+			"token_hash":         gorm.Expr("pending_token_hash"),
+			"pending_token_hash": nil,
+			"created_at":         gorm.Expr("NOW()"),
+		}).Error
+}
+
+func (sr *serverRegistryRepository) DiscardPendingServerCredential(worldId uuid.UUID, zoneId int) error {
+	return sr.db.Conn.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.ServerCredential{}).
+			Where("world_id = ? AND zone_id = ?", worldId, zoneId).
+			Update("pending_token_hash", nil).Error; err != nil {
+			return err
+		}
+		// Zones whose first server failed to start are left without a credential
+		return tx.Where("world_id = ? AND zone_id = ? AND token_hash IS NULL", worldId, zoneId).Delete(&models.ServerCredential{}).Error
+	})
+}
+
+func (sr *serverRegistryRepository) GetServerCredentialByHash(tokenHash string) (*models.ServerCredential, error) {
+	var credential models.ServerCredential
+	if err := sr.db.Conn.Where("token_hash = ? OR pending_token_hash = ?", tokenHash, tokenHash).First(&credential).Error; err != nil {
+		if core_errors.IsRecordNotFound(err) {
+			return nil, world_errors.NewServerCredentialNotFound("server credential not found")
+		}
+		return nil, err
+	}
+	return &credential, nil
+}
+
+func (sr *serverRegistryRepository) DeleteServerCredential(worldId uuid.UUID, zoneId int) error {
+	return sr.db.Conn.Where("world_id = ? AND zone_id = ?", worldId, zoneId).Delete(&models.ServerCredential{}).Error
+}
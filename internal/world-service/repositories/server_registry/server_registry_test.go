@@ -0,0 +1,148 @@
+package server_registry
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/FeedTheRealm-org/core-service/config"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/logger"
+	world_errors "github.com/FeedTheRealm-org/core-service/internal/world-service/errors"
+	"github.com/FeedTheRealm-org/core-service/internal/world-service/models"
+	"github.com/FeedTheRealm-org/core-service/internal/world-service/repositories/world"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+)
+
+var registryConf *config.Config
+var registryDB *config.DB
+var registryRepo ServerRegistryRepository
+var worldRepo world.WorldRepository
+
+func TestMain(m *testing.M) {
+	logger.InitLogger(false)
+	registryConf = config.CreateConfig()
+	var err error
+	registryDB, err = config.NewDB(registryConf)
+	if err != nil {
+		panic(err)
+	}
+	registryRepo = NewServerRegistryRepository(registryConf, registryDB)
+	worldRepo = world.NewWorldRepository(registryConf, registryDB)
+
+	code := m.Run()
+	os.Exit(code)
+}
+
+// createZones stores a world with the given zones, removed with everything referencing them once the test ends.
+func createZones(t *testing.T, zoneIDs ...int) uuid.UUID {
+	created, err := worldRepo.StoreWorldData(&models.WorldData{
+		ID:             uuid.New(),
+		UserId:         uuid.New(),
+		Name:           "world-" + uuid.NewString(),
+		Description:    "desc",
+		Data:           datatypes.JSON([]byte(`{}`)),
+		CreateableData: datatypes.JSON([]byte(`{}`)),
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = registryDB.Conn.Exec("DELETE FROM world_zones WHERE world_id = ?", created.ID).Error
+		_ = registryDB.Conn.Exec("DELETE FROM world_data WHERE id = ?", created.ID).Error
+	})
+
+	for _, zoneID := range zoneIDs {
+		_, err := worldRepo.UpsertWorldZone(created.ID, zoneID, []byte(`{}`))
+		require.NoError(t, err)
+	}
+	return created.ID
+}
+
+func TestServerRegistryRepository_RegisterAndUnRegister(t *testing.T) {
+	worldID := createZones(t, 1)
+
+	_, err := registryRepo.GetServer(worldID, 1)
+	var notFound *world_errors.ZoneServerNotFound
+	require.True(t, errors.As(err, &notFound))
+
+	require.NoError(t, registryRepo.RegisterServer(&models.ZoneServer{WorldID: worldID, ZoneID: 1, JobID: "job", ImageVersion: "v1", StartedAt: time.Now()}))
+	heartbeatAt := time.Now()
+	require.NoError(t, registryRepo.RecordHeartbeat(&models.ZoneServer{WorldID: worldID, ZoneID: 1, JobID: "job", AllocID: "alloc", Address: "10.0.0.5", Port: 24001, ImageVersion: "v1", StartedAt: time.Now(), LastHeartbeatAt: &heartbeatAt}))
+
+	server, err := registryRepo.GetServer(worldID, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "alloc", server.AllocID)
+	assert.Equal(t, "10.0.0.5", server.Address)
+	assert.Equal(t, 24001, server.Port)
+	require.NotNil(t, server.LastHeartbeatAt)
+
+	// A restarted server has not sent a heartbeat yet
+	require.NoError(t, registryRepo.RegisterServer(&models.ZoneServer{WorldID: worldID, ZoneID: 1, JobID: "job", ImageVersion: "v2", StartedAt: time.Now()}))
+	server, err = registryRepo.GetServer(worldID, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "v2", server.ImageVersion)
+	assert.Nil(t, server.LastHeartbeatAt)
+
+	require.NoError(t, registryRepo.UnRegisterServer(worldID, 1))
+	_, err = registryRepo.GetServer(worldID, 1)
+	require.True(t, errors.As(err, &notFound))
+}
+
+func TestServerRegistryRepository_ListServers(t *testing.T) {
+	worldID := createZones(t, 1, 2, 3, 4)
+	stale := time.Now().Add(-time.Hour)
+	fresh := time.Now()
+	require.NoError(t, registryRepo.RegisterServer(&models.ZoneServer{WorldID: worldID, ZoneID: 1, JobID: "job-1", ImageVersion: "v1", StartedAt: stale, LastHeartbeatAt: &stale}))
+	require.NoError(t, registryRepo.RegisterServer(&models.ZoneServer{WorldID: worldID, ZoneID: 2, JobID: "job-2", ImageVersion: "v2", StartedAt: stale, LastHeartbeatAt: &fresh}))
+	require.NoError(t, registryRepo.RegisterServer(&models.ZoneServer{WorldID: worldID, ZoneID: 3, JobID: "job-3", ImageVersion: "v2", StartedAt: fresh}))
+	require.NoError(t, registryRepo.RegisterServer(&models.ZoneServer{WorldID: worldID, ZoneID: 4, JobID: "job-4", ImageVersion: "v1", StartedAt: stale}))
+
+	servers, err := registryRepo.ListServers(ZoneServerFilter{WorldID: worldID})
+	require.NoError(t, err)
+	require.Len(t, servers, 4)
+	assert.Equal(t, []int{1, 2, 3, 4}, []int{servers[0].ZoneID, servers[1].ZoneID, servers[2].ZoneID, servers[3].ZoneID})
+
+	servers, err = registryRepo.ListServers(ZoneServerFilter{WorldID: worldID, ImageVersion: "v2"})
+	require.NoError(t, err)
+	assert.Len(t, servers, 2)
+
+	// Servers that just started still have time to send their first heartbeat, the ones that never did time out
+	servers, err = registryRepo.ListServers(ZoneServerFilter{WorldID: worldID, HeartbeatBefore: time.Now().Add(-time.Minute)})
+	require.NoError(t, err)
+	require.Len(t, servers, 2)
+	assert.Equal(t, []int{1, 4}, []int{servers[0].ZoneID, servers[1].ZoneID})
+}
+
+func TestServerRegistryRepository_PendingServerCredential(t *testing.T) {
+	worldID := createZones(t, 1, 2)
+
+	require.NoError(t, registryRepo.SavePendingServerCredential(worldID, 1, "running-"+worldID.String()))
+	require.NoError(t, registryRepo.ActivatePendingServerCredential(worldID, 1))
+
+	// The running token keeps working while the new server starts
+	require.NoError(t, registryRepo.SavePendingServerCredential(worldID, 1, "starting-"+worldID.String()))
+	_, err := registryRepo.GetServerCredentialByHash("running-" + worldID.String())
+	require.NoError(t, err)
+	_, err = registryRepo.GetServerCredentialByHash("starting-" + worldID.String())
+	require.NoError(t, err)
+
+	require.NoError(t, registryRepo.DiscardPendingServerCredential(worldID, 1))
+	_, err = registryRepo.GetServerCredentialByHash("running-" + worldID.String())
+	require.NoError(t, err)
+	_, err = registryRepo.GetServerCredentialByHash("starting-" + worldID.String())
+	assert.IsType(t, &world_errors.ServerCredentialNotFound{}, err)
+
+	require.NoError(t, registryRepo.SavePendingServerCredential(worldID, 1, "next-"+worldID.String()))
+	require.NoError(t, registryRepo.ActivatePendingServerCredential(worldID, 1))
+	_, err = registryRepo.GetServerCredentialByHash("running-" + worldID.String())
+	assert.IsType(t, &world_errors.ServerCredentialNotFound{}, err)
+
+	// A zone whose first server failed to start is left without a credential
+	require.NoError(t, registryRepo.SavePendingServerCredential(worldID, 2, "first-"+worldID.String()))
+	require.NoError(t, registryRepo.DiscardPendingServerCredential(worldID, 2))
+	var count int64
+	require.NoError(t, registryDB.Conn.Model(&models.ServerCredential{}).Where("world_id = ? AND zone_id = ?", worldID, 2).Count(&count).Error)
+	assert.Zero(t, count)
+}
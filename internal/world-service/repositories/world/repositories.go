@@ -25,6 +25,9 @@ type WorldRepository interface {
 	// SetWorldZoneActiveState updates the active state for a specific zone.
 	SetWorldZoneActiveState(worldID uuid.UUID, zoneID int, isActive bool) error
 
+	// SetWorldZoneTier updates the resource tier of a zone and the subscription slots it holds.
+	SetWorldZoneTier(worldID uuid.UUID, zoneID int, tier string, slots int) error
+
 	// SetWorldZoneOnlineState updates the online state for a specific zone.
 	SetWorldZoneOnlineState(worldID uuid.UUID, zoneID int, isOnline bool) error
 
@@ -46,6 +49,9 @@ type WorldRepository interface {
 	// GetActiveWorldZones retrieves all active zones across all worlds.
 	GetActiveWorldZones() ([]*models.WorldZone, error)
 
+	// GetActiveOrOnlineWorldZones retrieves the zones that should have a server or are marked as having one.
+	GetActiveOrOnlineWorldZones() ([]*models.WorldZone, error)
+
 	// UpdateWorldZonePlayerCount updates active player counts and average player time for a zone.
 	UpdateWorldZonePlayerCount(worldID uuid.UUID, zoneID int, activePlayers int, averagePlayerTime int) error
 
@@ -126,6 +126,20 @@ func (r *worldRepository) SetWorldZoneActiveState(worldID uuid.UUID, zoneID int,
 	return nil
 }
 
+func (r *worldRepository) SetWorldZoneTier(worldID uuid.UUID, zoneID int, tier string, slots int) error {
+	result := r.db.Conn.Model(&models.WorldZone{}).
+		Where("world_id = ? AND id = ?", worldID, zoneID).
+		Updates(map[string]any{"tier": tier, "slots": slots})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
 func (r *worldRepository) SetWorldZoneOnlineState(worldID uuid.UUID, zoneID int, isOnline bool) error {
 	result := r.db.Conn.Model(&models.WorldZone{}).
 		Where("world_id = ? AND id = ?", worldID, zoneID).
@@ -189,6 +203,9 @@ func (r *worldRepository) GetWorldZones(worldID uuid.UUID) ([]*models.WorldZone,
 func (r *worldRepository) GetWorldZone(worldID uuid.UUID, zoneID int) (*models.WorldZone, error) {
 	var worldZone models.WorldZone
 	if err := r.db.Conn.Where("world_id = ? AND id = ?", worldID, zoneID).First(&worldZone).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, world_errors.NewZoneNotFound(err.Error())
+		}
 		return nil, err
 	}
 	return &worldZone, nil
@@ -225,6 +242,14 @@ func (r *worldRepository) GetActiveWorldZones() ([]*models.WorldZone, error) {
 	return activeZones, nil
 }
 
+func (r *worldRepository) GetActiveOrOnlineWorldZones() ([]*models.WorldZone, error) {
+	var zones []*models.WorldZone
+	if err := r.db.Conn.Where("is_active = ? OR is_online = ?", true, true).Find(&zones).Error; err != nil {
+		return nil, err
+	}
+	return zones, nil
+}
+
 func (r *worldRepository) UpdateWorldZonePlayerCount(worldID uuid.UUID, zoneID int, activePlayers int, averagePlayerTime int) error {
 	return r.db.Conn.Transaction(func(tx *gorm.DB) error {
 		result := tx.Model(&models.WorldZone{}).
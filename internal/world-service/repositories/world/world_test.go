@@ -3,6 +3,7 @@ package world
 import (
 	"errors"
 	"os"
+	"slices"
 	"testing"
 
 	"github.com/FeedTheRealm-org/core-service/config"
@@ -141,6 +142,23 @@ func TestWorldRepository_ZoneState(t *testing.T) {
 	assert.NoError(t, worldRepo.SetWorldZoneOnlineState(created.ID, 2, true))
 }
 
+func TestWorldRepository_SetWorldZoneTier(t *testing.T) {
+	userID := uuid.New()
+	created := createWorld(t, userID)
+
+	_, err := worldRepo.UpsertWorldZone(created.ID, 3, []byte(`{"z":1}`))
+	require.NoError(t, err)
+
+	assert.NoError(t, worldRepo.SetWorldZoneTier(created.ID, 3, "large", 2))
+	zone, err := worldRepo.GetWorldZone(created.ID, 3)
+	require.NoError(t, err)
+	assert.Equal(t, "large", zone.Tier)
+	assert.Equal(t, 2, zone.Slots)
+
+	err = worldRepo.SetWorldZoneTier(uuid.New(), 1, "small", 1)
+	assert.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+}
+
 func TestWorldRepository_SetWorldZoneActiveState_NotFound(t *testing.T) {
 	err := worldRepo.SetWorldZoneActiveState(uuid.New(), 1, true)
 	assert.Error(t, err)
@@ -195,6 +213,12 @@ func TestWorldRepository_ActiveZonesAndCounts(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotEmpty(t, active)
 
+	reconcilable, err := worldRepo.GetActiveOrOnlineWorldZones()
+	assert.NoError(t, err)
+	assert.True(t, slices.ContainsFunc(reconcilable, func(zone *models.WorldZone) bool {
+		return zone.WorldID == created.ID && zone.ID == 2
+	}))
+
 	totalPlayers, avgTime, maxPlayers, maxAvgTime, err := worldRepo.GetWorldZonePlayerCounts(created.ID)
 	assert.NoError(t, err)
 	assert.Equal(t, 8, totalPlayers)
@@ -2,21 +2,29 @@ package router
 
 import (
 	"github.com/FeedTheRealm-org/core-service/config"
+	"github.com/FeedTheRealm-org/core-service/internal/common_handlers"
 	"github.com/FeedTheRealm-org/core-service/internal/middleware"
+	"github.com/FeedTheRealm-org/core-service/internal/service_clients"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/leader_election"
 	"github.com/FeedTheRealm-org/core-service/internal/utils/oidc_validation"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/permissions"
 	server_registry_controller "github.com/FeedTheRealm-org/core-service/internal/world-service/controllers/server_registry"
 	world_controller "github.com/FeedTheRealm-org/core-service/internal/world-service/controllers/world"
 	zones_controller "github.com/FeedTheRealm-org/core-service/internal/world-service/controllers/zones"
+	deployments_repo "github.com/FeedTheRealm-org/core-service/internal/world-service/repositories/deployments"
+	server_registry_repo "github.com/FeedTheRealm-org/core-service/internal/world-service/repositories/server_registry"
 	world_repo "github.com/FeedTheRealm-org/core-service/internal/world-service/repositories/world"
+	"github.com/FeedTheRealm-org/core-service/internal/world-service/services/orchestrator"
+	rollout_service "github.com/FeedTheRealm-org/core-service/internal/world-service/services/rollout"
 	server_registry_service "github.com/FeedTheRealm-org/core-service/internal/world-service/services/server_registry"
 	world_service "github.com/FeedTheRealm-org/core-service/internal/world-service/services/world"
 	zones_service "github.com/FeedTheRealm-org/core-service/internal/world-service/services/zones"
 	"github.com/gin-gonic/gin"
 )
 
-func SetupEndpointsForWorldService(worldGroup *gin.RouterGroup, db *config.DB, conf *config.Config, nomadService server_registry_service.ServerRegistryService) {
+func SetupEndpointsForWorldService(worldGroup *gin.RouterGroup, db *config.DB, conf *config.Config, nomadService server_registry_service.ServerRegistryService, clients *service_clients.Clients) {
 	worldRepo := world_repo.NewWorldRepository(conf, db)
-	worldService := world_service.NewWorldService(conf, worldRepo, nomadService)
+	worldService := world_service.NewWorldService(conf, worldRepo, nomadService, clients.Subscriptions)
 	worldController := world_controller.NewWorldController(conf, worldService)
 
 	worldGroup.POST("", worldController.PublishWorld)
@@ -27,67 +35,136 @@ func SetupEndpointsForWorldService(worldGroup *gin.RouterGroup, db *config.DB, c
 
 	worldGroup.PUT("/:id/createable-data", worldController.UpdateCreateableData)
 
-	worldGroup.DELETE("/reset-database", middleware.AdminCheckMiddleware(), worldController.ResetDatabase)
+	worldGroup.DELETE("/reset-database", middleware.RequirePermission(permissions.WORLD_DATABASE_RESET), middleware.AuditMiddleware(clients.Audit, "world.database.reset", "database", ""), worldController.ResetDatabase)
 }
 
-func SetupEndpointsForZonesService(worldGroup *gin.RouterGroup, db *config.DB, conf *config.Config, nomadService server_registry_service.ServerRegistryService) {
+func SetupEndpointsForZonesService(worldGroup *gin.RouterGroup, internalGroup *gin.RouterGroup, db *config.DB, conf *config.Config, nomadService server_registry_service.ServerRegistryService, clients *service_clients.Clients) {
 	worldRepo := world_repo.NewWorldRepository(conf, db)
-	zonesService := zones_service.NewZonesService(conf, worldRepo, nomadService)
+	zonesService := zones_service.NewZonesService(conf, worldRepo, nomadService, clients.Subscriptions)
+	clients.ProvideWorldJobs(zones_service.NewWorldJobsClient(zonesService))
+	userDataClient := zones_service.NewUserDataClient(zonesService, worldRepo)
+	clients.ProvideUserData(service_clients.WorldUserData, userDataClient)
 	zonesController := zones_controller.NewZonesController(conf, zonesService)
 
+	worldGroup.GET("/zone-tiers", zonesController.GetZoneTiers)
 	worldGroup.PUT("/:id/zones/:zone_id", zonesController.PublishZone)
 	worldGroup.GET("/:id/zones", zonesController.GetWorldZones)
 	worldGroup.GET("/:id/zones/:zone_id", zonesController.GetWorldZoneData)
 	worldGroup.GET("/:id/zones/:zone_id/activate", zonesController.ActivateZone)
 	worldGroup.GET("/:id/zones/:zone_id/deactivate", zonesController.DeactivateZone)
+	worldGroup.PUT("/:id/zones/:zone_id/tier", zonesController.SetZoneTier)
 
-	worldGroup.GET("/internal/users/:user_id/stop-jobs", zonesController.StopAllJobsForUser)
+	// Internal routes, only used when services are split out
+	internalGroup.GET("/users/:user_id/stop-jobs", zonesController.StopAllJobsForUser)
+	internalGroup.GET("/users/:user_id/data", common_handlers.ExportUserDataController(userDataClient))
+	internalGroup.DELETE("/users/:user_id/data", common_handlers.EraseUserDataController(userDataClient))
 }
 
-func SetupEndpointsForServiceRegistry(orchestratorGroup *gin.RouterGroup, db *config.DB, conf *config.Config, nomadService server_registry_service.ServerRegistryService) error {
+func SetupEndpointsForServiceRegistry(orchestratorGroup *gin.RouterGroup, internalGroup *gin.RouterGroup, db *config.DB, conf *config.Config, zoneServers orchestrator.Orchestrator, nomadService server_registry_service.ServerRegistryService, credentialsService server_registry_service.ServerCredentialsService, clients *service_clients.Clients) error {
+	reconcilerService, err := CreateReconcilerService(conf, db, zoneServers, nomadService)
+	if err != nil {
+		return err
+	}
+
+	rolloutService, err := CreateRolloutService(conf, db, zoneServers, nomadService)
+	if err != nil {
+		return err
+	}
+
 	ghv, err := oidc_validation.NewGitHubOIDCVerifier(conf)
 	if err != nil {
 		return err
 	}
 
 	worldRepo := world_repo.NewWorldRepository(conf, db)
-	worldService := world_service.NewWorldService(conf, worldRepo, nomadService)
-	zoneService := zones_service.NewZonesService(conf, worldRepo, nomadService)
-	serverRegistryController := server_registry_controller.NewServerRegistryController(conf, worldService, zoneService, nomadService)
+	worldService := world_service.NewWorldService(conf, worldRepo, nomadService, clients.Subscriptions)
+	zoneService := zones_service.NewZonesService(conf, worldRepo, nomadService, clients.Subscriptions)
+	serverRegistryController := server_registry_controller.NewServerRegistryController(conf, worldService, zoneService, nomadService, credentialsService, reconcilerService, rolloutService)
 
-	orchestratorGroup.GET("/:id/zones/:zone_id/start-job", middleware.AdminCheckMiddleware(), serverRegistryController.StartNewJob)
-	orchestratorGroup.GET("/:id/zones/:zone_id/stop-job", middleware.AdminCheckMiddleware(), serverRegistryController.StopJob)
+	orchestratorGroup.GET("/:id/zones/:zone_id/start-job", middleware.RequirePermission(permissions.WORLD_JOBS_WRITE), middleware.AuditMiddleware(clients.Audit, "world.jobs.start", "zone", "zone_id"), serverRegistryController.StartNewJob)
+	orchestratorGroup.GET("/:id/zones/:zone_id/stop-job", middleware.RequirePermission(permissions.WORLD_JOBS_WRITE), middleware.AuditMiddleware(clients.Audit, "world.jobs.stop", "zone", "zone_id"), serverRegistryController.StopJob)
 	orchestratorGroup.GET("/:id/zones/:zone_id/address", serverRegistryController.GetServerAddress)
 	orchestratorGroup.POST("/:id/zones/:zone_id/players", middleware.ServerCheckMiddleware(), serverRegistryController.UpdatePlayerCount)
 	orchestratorGroup.GET("/:id/players", serverRegistryController.GetWorldPlayerCounts)
 	orchestratorGroup.GET("/players", serverRegistryController.GetAllWorldPlayerCounts)
+	orchestratorGroup.GET("/reconcile/status", middleware.RequirePermission(permissions.WORLD_JOBS_READ), serverRegistryController.GetReconcileStatus)
 	orchestratorGroup.POST("/webhook/servers/update", middleware.GithubOIDCCheck(ghv), serverRegistryController.UpdateServer)
 	orchestratorGroup.PUT("/:id/zones/:zone_id/status", middleware.ServerCheckMiddleware(), serverRegistryController.UpdateStatus)
+	orchestratorGroup.POST("/:id/zones/:zone_id/heartbeat", middleware.ServerCheckMiddleware(), serverRegistryController.Heartbeat)
+	orchestratorGroup.GET("/servers", middleware.RequirePermission(permissions.WORLD_JOBS_READ), serverRegistryController.ListServers)
+	orchestratorGroup.GET("/rollouts", middleware.RequirePermission(permissions.WORLD_JOBS_READ), serverRegistryController.ListRollouts)
+	orchestratorGroup.GET("/rollouts/:rollout_id", middleware.RequirePermission(permissions.WORLD_JOBS_READ), serverRegistryController.GetRollout)
+	orchestratorGroup.POST("/rollouts", middleware.RequirePermission(permissions.WORLD_JOBS_WRITE), middleware.AuditMiddleware(clients.Audit, "world.rollouts.create", "rollout", ""), serverRegistryController.CreateRollout)
+	orchestratorGroup.POST("/rollouts/:rollout_id/pause", middleware.RequirePermission(permissions.WORLD_JOBS_WRITE), middleware.AuditMiddleware(clients.Audit, "world.rollouts.pause", "rollout", "rollout_id"), serverRegistryController.PauseRollout)
+	orchestratorGroup.POST("/rollouts/:rollout_id/resume", middleware.RequirePermission(permissions.WORLD_JOBS_WRITE), middleware.AuditMiddleware(clients.Audit, "world.rollouts.resume", "rollout", "rollout_id"), serverRegistryController.ResumeRollout)
+	orchestratorGroup.POST("/rollouts/:rollout_id/abort", middleware.RequirePermission(permissions.WORLD_JOBS_WRITE), middleware.AuditMiddleware(clients.Audit, "world.rollouts.abort", "rollout", "rollout_id"), serverRegistryController.AbortRollout)
+
+	// Internal routes, only used when services are split out
+	internalGroup.POST("/servers/verify", serverRegistryController.VerifyServerTokenInternal)
 
 	return nil
 }
 
-func CreateNomadService(conf *config.Config, db *config.DB) (server_registry_service.ServerRegistryService, error) {
-	if conf.Server.Environment == config.Production {
-		worldRepo := world_repo.NewWorldRepository(conf, db)
-		return server_registry_service.NewServerRegistryService(conf, worldRepo)
-	} else {
-		return server_registry_service.NewStubServerRegistryService(), nil
+// CreateOrchestrator returns the backend zone servers run on: Nomad in production, local
+// processes or containers, or an in-memory backend that runs nothing.
+func CreateOrchestrator(conf *config.Config) (orchestrator.Orchestrator, error) {
+	switch conf.Orchestrator.Backend {
+	case config.NomadOrchestrator:
+		return orchestrator.NewNomadOrchestrator(conf)
+	case config.LocalOrchestrator:
+		return orchestrator.NewLocalOrchestrator(conf)
+	default:
+		return orchestrator.NewMemoryOrchestrator(), nil
 	}
 }
 
-func SetupWorldServiceRouter(r *gin.Engine, conf *config.Config, db *config.DB) error {
+// CreateReconcilerService starts the zone reconciler unless servers are only kept in memory,
+// those are lost on restart and would otherwise all be restarted with new credentials.
+func CreateReconcilerService(conf *config.Config, db *config.DB, zoneServers orchestrator.Orchestrator, nomadService server_registry_service.ServerRegistryService) (server_registry_service.ReconcilerService, error) {
+	worldRepo := world_repo.NewWorldRepository(conf, db)
+	elector, err := leader_election.NewPostgresElector(db.Conn, "world-zone-reconciler")
+	if err != nil {
+		return nil, err
+	}
+
+	reconcilerService := server_registry_service.NewReconcilerService(conf, worldRepo, nomadService, zoneServers, elector)
+	if conf.Orchestrator.Backend != config.MemoryOrchestrator {
+		reconcilerService.Start()
+	}
+	return reconcilerService, nil
+}
+
+// CreateRolloutService starts advancing server image rollouts on the leader replica.
+func CreateRolloutService(conf *config.Config, db *config.DB, zoneServers orchestrator.Orchestrator, nomadService server_registry_service.ServerRegistryService) (rollout_service.RolloutService, error) {
+	elector, err := leader_election.NewPostgresElector(db.Conn, "world-server-rollout")
+	if err != nil {
+		return nil, err
+	}
+
+	rolloutService := rollout_service.NewRolloutService(conf, deployments_repo.NewDeploymentsRepository(conf, db), world_repo.NewWorldRepository(conf, db), nomadService, zoneServers, elector)
+	rolloutService.Start()
+	return rolloutService, nil
+}
+
+func SetupWorldServiceRouter(r *gin.Engine, internal *gin.RouterGroup, conf *config.Config, db *config.DB, clients *service_clients.Clients) error {
 	worldGroup := r.Group("/world")
 	orchestratorGroup := worldGroup.Group("/orchestrator")
+	worldInternalGroup := internal.Group("/world/internal")
+
+	serverRegistryRepo := server_registry_repo.NewServerRegistryRepository(conf, db)
+	credentialsService := server_registry_service.NewServerCredentialsService(serverRegistryRepo)
+	clients.ProvideServers(server_registry_service.NewServersClient(credentialsService))
 
-	nomadService, err := CreateNomadService(conf, db)
+	zoneServers, err := CreateOrchestrator(conf)
 	if err != nil {
 		return err
 	}
+	nomadService := server_registry_service.NewServerRegistryService(conf, world_repo.NewWorldRepository(conf, db), serverRegistryRepo, deployments_repo.NewDeploymentsRepository(conf, db), zoneServers, credentialsService)
+	nomadService.StartHeartbeatMonitor()
 
-	SetupEndpointsForWorldService(worldGroup, db, conf, nomadService)
-	SetupEndpointsForZonesService(worldGroup, db, conf, nomadService)
-	if err := SetupEndpointsForServiceRegistry(orchestratorGroup, db, conf, nomadService); err != nil {
+	SetupEndpointsForWorldService(worldGroup, db, conf, nomadService, clients)
+	SetupEndpointsForZonesService(worldGroup, worldInternalGroup, db, conf, nomadService, clients)
+	if err := SetupEndpointsForServiceRegistry(orchestratorGroup, worldInternalGroup, db, conf, zoneServers, nomadService, credentialsService, clients); err != nil {
 		return err
 	}
 
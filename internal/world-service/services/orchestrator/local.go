@@ -0,0 +1,216 @@
+package orchestrator
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/FeedTheRealm-org/core-service/config"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/logger"
+	world_errors "github.com/FeedTheRealm-org/core-service/internal/world-service/errors"
+	"github.com/google/uuid"
+)
+
+const localStopTimeout = 10 * time.Second
+
+type localServer struct {
+	ZoneServer
+	port int
+	cmd  *exec.Cmd
+	done chan struct{}
+}
+
+type localOrchestrator struct {
+	conf *config.Config
+
+	mu      sync.Mutex
+	servers map[ZoneKey]*localServer
+	ports   map[int]bool
+}
+
+// NewLocalOrchestrator runs zone servers as processes of this host, the configured command
+// may start a binary or a docker container. It is rendered with the same data as the Nomad
+// job template plus the allocated {{.Port}}.
+func NewLocalOrchestrator(conf *config.Config) (Orchestrator, error) {
+	if strings.TrimSpace(conf.Orchestrator.LocalCommand) == "" {
+		return nil, errors.New("the local orchestrator needs ORCHESTRATOR_LOCAL_COMMAND")
+	}
+	if conf.Orchestrator.LocalPortCount <= 0 {
+		return nil, errors.New("the local orchestrator needs a positive ORCHESTRATOR_LOCAL_PORT_COUNT")
+	}
+	return &localOrchestrator{
+		conf:    conf,
+		servers: make(map[ZoneKey]*localServer),
+		ports:   make(map[int]bool),
+	}, nil
+}
+
+func (l *localOrchestrator) Start(spec ZoneServerSpec) error {
+	key := ZoneKey{WorldId: spec.WorldId, ZoneId: spec.ZoneId}
+	if err := l.Stop(spec.WorldId, spec.ZoneId); err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	port, err := l.allocatePort()
+	if err != nil {
+		return err
+	}
+
+	data := newZoneServerTemplateData(l.conf, spec)
+	data.Port = port
+	rendered, err := renderZoneServerTemplate("local-command", l.conf.Orchestrator.LocalCommand, data)
+	if err != nil {
+		return err
+	}
+	args := strings.Fields(rendered)
+	if len(args) == 0 {
+		return errors.New("the local orchestrator command rendered empty")
+	}
+
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Env = append(os.Environ(),
+		"FTR_SERVER_TOKEN="+spec.ServerToken,
+		"FTR_WORLD_ID="+spec.WorldId.String(),
+		"FTR_ZONE_ID="+strconv.Itoa(spec.ZoneId),
+		"FTR_SERVER_PORT="+strconv.Itoa(port),
+	)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start the server of world %s zone %d: %w", spec.WorldId, spec.ZoneId, err)
+	}
+
+	server := &localServer{
+		ZoneServer: ZoneServer{
+			ZoneKey:   key,
+			Name:      data.JobName,
+			StartedAt: time.Now().UTC(),
+			Image:     data.ImageName,
+		},
+		port: port,
+		cmd:  cmd,
+		done: make(chan struct{}),
+	}
+	l.servers[key] = server
+	l.ports[port] = true
+
+	go l.wait(server)
+
+	logger.Logger.Infof("Started local server %q on port %d", server.Name, port)
+	return nil
+}
+
+// wait forgets the server and frees its port once its process exits.
+func (l *localOrchestrator) wait(server *localServer) {
+	err := server.cmd.Wait()
+	if err != nil {
+		logger.Logger.Warnf("Local server %q exited: %v", server.Name, err)
+	}
+
+	l.mu.Lock()
+	if l.servers[server.ZoneKey] == server {
+		delete(l.servers, server.ZoneKey)
+	}
+	delete(l.ports, server.port)
+	l.mu.Unlock()
+	close(server.done)
+}
+
+func (l *localOrchestrator) Stop(worldId uuid.UUID, zoneId int) error {
+	l.mu.Lock()
+	server, ok := l.servers[ZoneKey{WorldId: worldId, ZoneId: zoneId}]
+	l.mu.Unlock()
+	if !ok {
+		return nil
+	}
+
+	if err := server.cmd.Process.Signal(syscall.SIGTERM); err != nil && !errors.Is(err, os.ErrProcessDone) {
+		return fmt.Errorf("failed to stop local server %q: %w", server.Name, err)
+	}
+	select {
+	case <-server.done:
+	case <-time.After(localStopTimeout):
+		_ = server.cmd.Process.Kill()
+		<-server.done
+	}
+	return nil
+}
+
+func (l *localOrchestrator) Status(worldId uuid.UUID, zoneId int) (*ZoneServer, error) {
+	l.mu.Lock()
+	server, ok := l.servers[ZoneKey{WorldId: worldId, ZoneId: zoneId}]
+	l.mu.Unlock()
+	if !ok {
+		return nil, world_errors.NewZoneServerNotFound(fmt.Sprintf("no server found for world %s zone %d", worldId, zoneId))
+	}
+
+	status := server.ZoneServer
+	status.Healthy = l.isListening(server.port)
+	return &status, nil
+}
+
+func (l *localOrchestrator) Address(worldId uuid.UUID, zoneId int) (string, int, error) {
+	l.mu.Lock()
+	server, ok := l.servers[ZoneKey{WorldId: worldId, ZoneId: zoneId}]
+	l.mu.Unlock()
+	if !ok || !l.isListening(server.port) {
+		return "", 0, world_errors.NewZoneServerNotFound(fmt.Sprintf("no healthy server found for world %s zone %d", worldId, zoneId))
+	}
+	return l.conf.Orchestrator.LocalHost, server.port, nil
+}
+
+func (l *localOrchestrator) List() ([]ZoneServer, error) {
+	l.mu.Lock()
+	running := make([]*localServer, 0, len(l.servers))
+	for _, server := range l.servers {
+		running = append(running, server)
+	}
+	l.mu.Unlock()
+
+	servers := make([]ZoneServer, 0, len(running))
+	for _, server := range running {
+		status := server.ZoneServer
+		status.Healthy = l.isListening(server.port)
+		servers = append(servers, status)
+	}
+	sort.Slice(servers, func(i, j int) bool { return servers[i].Name < servers[j].Name })
+	return servers, nil
+}
+
+// allocatePort returns the first port of the configured range that is neither taken by
+// another server nor bound by some other process, l.mu must be held.
+func (l *localOrchestrator) allocatePort() (int, error) {
+	start := l.conf.Orchestrator.LocalPortStart
+	for port := start; port < start+l.conf.Orchestrator.LocalPortCount; port++ {
+		if l.ports[port] {
+			continue
+		}
+		listener, err := net.Listen("tcp", net.JoinHostPort(l.conf.Orchestrator.LocalHost, strconv.Itoa(port)))
+		if err != nil {
+			continue
+		}
+		_ = listener.Close()
+		return port, nil
+	}
+	return 0, fmt.Errorf("no free port left between %d and %d", start, start+l.conf.Orchestrator.LocalPortCount-1)
+}
+
+func (l *localOrchestrator) isListening(port int) bool {
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(l.conf.Orchestrator.LocalHost, strconv.Itoa(port)), time.Second)
+	if err != nil {
+		return false
+	}
+	_ = conn.Close()
+	return true
+}
@@ -0,0 +1,76 @@
+package orchestrator
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/FeedTheRealm-org/core-service/internal/utils/logger"
+	world_errors "github.com/FeedTheRealm-org/core-service/internal/world-service/errors"
+	"github.com/google/uuid"
+)
+
+type memoryOrchestrator struct {
+	mu      sync.Mutex
+	servers map[ZoneKey]ZoneServer
+}
+
+// NewMemoryOrchestrator creates a backend that only records servers without running them.
+// When testing by hand, make sure to match the port with the zone via this relation: port = 7776 + zoneId
+func NewMemoryOrchestrator() Orchestrator {
+	return &memoryOrchestrator{servers: make(map[ZoneKey]ZoneServer)}
+}
+
+func (m *memoryOrchestrator) Start(spec ZoneServerSpec) error {
+	key := ZoneKey{WorldId: spec.WorldId, ZoneId: spec.ZoneId}
+
+	m.mu.Lock()
+	m.servers[key] = ZoneServer{
+		ZoneKey:   key,
+		Name:      ZoneServerName(spec.WorldId, spec.ZoneId),
+		StartedAt: time.Now().UTC(),
+		Healthy:   true,
+		Image:     spec.Image,
+	}
+	m.mu.Unlock()
+
+	logger.Logger.Infof("Recorded server for world %s zone %d as test=%t, server token: %s",
+		spec.WorldId, spec.ZoneId, spec.IsTest, spec.ServerToken)
+	return nil
+}
+
+func (m *memoryOrchestrator) Stop(worldId uuid.UUID, zoneId int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.servers, ZoneKey{WorldId: worldId, ZoneId: zoneId})
+	return nil
+}
+
+func (m *memoryOrchestrator) Status(worldId uuid.UUID, zoneId int) (*ZoneServer, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	server, ok := m.servers[ZoneKey{WorldId: worldId, ZoneId: zoneId}]
+	if !ok {
+		return nil, world_errors.NewZoneServerNotFound(fmt.Sprintf("no server found for world %s zone %d", worldId, zoneId))
+	}
+	return &server, nil
+}
+
+func (m *memoryOrchestrator) Address(worldId uuid.UUID, zoneId int) (string, int, error) {
+	if _, err := m.Status(worldId, zoneId); err != nil {
+		return "", 0, err
+	}
+	return "127.0.0.1", 7776 + zoneId, nil
+}
+
+func (m *memoryOrchestrator) List() ([]ZoneServer, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	servers := make([]ZoneServer, 0, len(m.servers))
+	for _, server := range m.servers {
+		servers = append(servers, server)
+	}
+	sort.Slice(servers, func(i, j int) bool { return servers[i].Name < servers[j].Name })
+	return servers, nil
+}
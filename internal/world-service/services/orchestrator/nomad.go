@@ -0,0 +1,219 @@
+package orchestrator
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/FeedTheRealm-org/core-service/config"
+	world_errors "github.com/FeedTheRealm-org/core-service/internal/world-service/errors"
+	"github.com/google/uuid"
+	consul_api "github.com/hashicorp/consul/api"
+	nomad_api "github.com/hashicorp/nomad/api"
+)
+
+type nomadOrchestrator struct {
+	conf         *config.Config
+	nomadClient  *nomad_api.Client
+	consulClient *consul_api.Client
+}
+
+// NewNomadOrchestrator runs zone servers as Nomad jobs rendered from the job template,
+// their health and address come from the Consul services they register.
+func NewNomadOrchestrator(conf *config.Config) (Orchestrator, error) {
+	nomadConfig := nomad_api.DefaultConfig()
+	nomadConfig.Address = conf.NomadAddr
+	nomadConfig.SecretID = conf.NomadToken
+	nomadConfig.TLSConfig = &nomad_api.TLSConfig{
+		CACert: conf.NomadCertPath,
+	}
+	nomadClient, err := nomad_api.NewClient(nomadConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create nomad client: %w", err)
+	}
+
+	consulConfig := consul_api.DefaultConfig()
+	consulConfig.Address = conf.ConsulAddr
+	consulClient, err := consul_api.NewClient(consulConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create consul client: %w", err)
+	}
+
+	return &nomadOrchestrator{
+		conf:         conf,
+		nomadClient:  nomadClient,
+		consulClient: consulClient,
+	}, nil
+}
+
+func (n *nomadOrchestrator) Start(spec ZoneServerSpec) error {
+	templateBytes, err := os.ReadFile(n.conf.NomadTemplatePath)
+	if err != nil {
+		return fmt.Errorf("failed to read nomad template file: %w", err)
+	}
+
+	data := newZoneServerTemplateData(n.conf, spec)
+	rendered, err := renderZoneServerTemplate("ftr-server-job", string(templateBytes), data)
+	if err != nil {
+		return err
+	}
+
+	job, err := n.nomadClient.Jobs().ParseHCL(rendered, true)
+	if err != nil {
+		return fmt.Errorf("failed to parse rendered nomad job: %w", err)
+	}
+
+	if _, _, err := n.nomadClient.Jobs().Register(job, nil); err != nil {
+		return fmt.Errorf("failed to register nomad job %q: %w", data.JobName, err)
+	}
+	return nil
+}
+
+func (n *nomadOrchestrator) Stop(worldId uuid.UUID, zoneId int) error {
+	jobName := ZoneServerName(worldId, zoneId)
+	if _, _, err := n.nomadClient.Jobs().Deregister(jobName, true, nil); err != nil {
+		return fmt.Errorf("failed to deregister nomad job %q: %w", jobName, err)
+	}
+	return nil
+}
+
+func (n *nomadOrchestrator) Status(worldId uuid.UUID, zoneId int) (*ZoneServer, error) {
+	jobName := ZoneServerName(worldId, zoneId)
+	stubs, _, err := n.nomadClient.Jobs().PrefixList(jobName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nomad jobs: %w", err)
+	}
+
+	for _, stub := range stubs {
+		if stub.ID != jobName || isStopped(stub) {
+			continue
+		}
+		healthy, err := n.healthyServices(worldId, zoneId)
+		if err != nil {
+			return nil, err
+		}
+		server := &ZoneServer{
+			ZoneKey:   ZoneKey{WorldId: worldId, ZoneId: zoneId},
+			Name:      jobName,
+			StartedAt: time.Unix(0, stub.SubmitTime),
+			Healthy:   len(healthy) > 0,
+		}
+		if server.Healthy {
+			server.Image = healthy[0].Service.Meta["image"]
+		}
+		return server, nil
+	}
+	return nil, world_errors.NewZoneServerNotFound(fmt.Sprintf("no server found for world %s zone %d", worldId, zoneId))
+}
+
+func (n *nomadOrchestrator) Address(worldId uuid.UUID, zoneId int) (string, int, error) {
+	services, err := n.healthyServices(worldId, zoneId)
+	if err != nil {
+		return "", 0, err
+	}
+	if len(services) == 0 {
+		return "", 0, world_errors.NewZoneServerNotFound(fmt.Sprintf("no healthy server found for world %s zone %d", worldId, zoneId))
+	}
+
+	svc := services[0]
+	publicIP, ok := svc.Service.Meta["public_ip"]
+	if !ok || publicIP == "" {
+		return "", 0, fmt.Errorf("server found but missing public_ip metadata for world %s zone %d", worldId, zoneId)
+	}
+	return publicIP, svc.Service.Port, nil
+}
+
+func (n *nomadOrchestrator) List() ([]ZoneServer, error) {
+	stubs, _, err := n.nomadClient.Jobs().PrefixList(zoneServerPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nomad jobs: %w", err)
+	}
+
+	entries, _, err := n.consulClient.Health().Service("zone-server", "", true, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query consul: %w", err)
+	}
+	healthy := make(map[ZoneKey]bool, len(entries))
+	for _, entry := range entries {
+		if key, ok := parseZoneServiceTags(entry.Service.Tags); ok {
+			healthy[key] = true
+		}
+	}
+
+	servers := make([]ZoneServer, 0, len(stubs))
+	for _, stub := range stubs {
+		if isStopped(stub) {
+			continue
+		}
+		key, ok := parseZoneServerName(stub.ID)
+		if !ok {
+			continue
+		}
+		servers = append(servers, ZoneServer{
+			ZoneKey:   key,
+			Name:      stub.ID,
+			StartedAt: time.Unix(0, stub.SubmitTime),
+			Healthy:   healthy[key],
+		})
+	}
+	return servers, nil
+}
+
+func (n *nomadOrchestrator) healthyServices(worldId uuid.UUID, zoneId int) ([]*consul_api.ServiceEntry, error) {
+	filter := fmt.Sprintf(
+		`"world-%s" in Service.Tags and "zone-%d" in Service.Tags`,
+		worldId.String(),
+		zoneId,
+	)
+
+	services, _, err := n.consulClient.Health().Service("zone-server", "", true, &consul_api.QueryOptions{
+		Filter: filter,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query consul: %w", err)
+	}
+	return services, nil
+}
+
+func isStopped(stub *nomad_api.JobListStub) bool {
+	return stub.Stop || stub.Status == "dead"
+}
+
+// parseZoneServerName reads the world and zone out of a zone-server-<world>-<zone> name.
+func parseZoneServerName(name string) (ZoneKey, bool) {
+	rest, ok := strings.CutPrefix(name, zoneServerPrefix)
+	if !ok {
+		return ZoneKey{}, false
+	}
+	separator := strings.LastIndex(rest, "-")
+	if separator < 0 {
+		return ZoneKey{}, false
+	}
+	worldId, err := uuid.Parse(rest[:separator])
+	if err != nil {
+		return ZoneKey{}, false
+	}
+	zoneId, err := strconv.Atoi(rest[separator+1:])
+	if err != nil {
+		return ZoneKey{}, false
+	}
+	return ZoneKey{WorldId: worldId, ZoneId: zoneId}, true
+}
+
+// parseZoneServiceTags reads the world-<world> and zone-<zone> tags zone servers register with.
+func parseZoneServiceTags(tags []string) (ZoneKey, bool) {
+	var key ZoneKey
+	var hasWorld, hasZone bool
+	for _, tag := range tags {
+		if value, ok := strings.CutPrefix(tag, "world-"); ok {
+			worldId, err := uuid.Parse(value)
+			key.WorldId, hasWorld = worldId, err == nil
+		} else if value, ok := strings.CutPrefix(tag, "zone-"); ok {
+			zoneId, err := strconv.Atoi(value)
+			key.ZoneId, hasZone = zoneId, err == nil
+		}
+	}
+	return key, hasWorld && hasZone
+}
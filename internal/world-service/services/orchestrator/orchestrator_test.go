@@ -0,0 +1,185 @@
+package orchestrator
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/FeedTheRealm-org/core-service/config"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/logger"
+	world_errors "github.com/FeedTheRealm-org/core-service/internal/world-service/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	logger.InitLogger(false)
+	os.Exit(m.Run())
+}
+
+func TestMemoryOrchestrator_Lifecycle(t *testing.T) {
+	orchestrator := NewMemoryOrchestrator()
+	worldId := uuid.New()
+
+	_, _, err := orchestrator.Address(worldId, 1)
+	var notFound *world_errors.ZoneServerNotFound
+	require.ErrorAs(t, err, &notFound)
+
+	require.NoError(t, orchestrator.Start(ZoneServerSpec{WorldId: worldId, ZoneId: 1, ServerToken: "token"}))
+
+	host, port, err := orchestrator.Address(worldId, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "127.0.0.1", host)
+	assert.Equal(t, 7777, port)
+
+	servers, err := orchestrator.List()
+	require.NoError(t, err)
+	require.Len(t, servers, 1)
+	assert.Equal(t, ZoneKey{WorldId: worldId, ZoneId: 1}, servers[0].ZoneKey)
+	assert.True(t, servers[0].Healthy)
+
+	require.NoError(t, orchestrator.Stop(worldId, 1))
+	_, err = orchestrator.Status(worldId, 1)
+	require.ErrorAs(t, err, &notFound)
+}
+
+// TestLocalHelperServer is the zone server the local orchestrator tests start, it only
+// runs when re-executed by them.
+func TestLocalHelperServer(t *testing.T) {
+	if os.Getenv("FTR_LOCAL_HELPER") != "1" {
+		t.Skip("only runs as a helper process")
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:"+os.Getenv("FTR_SERVER_PORT"))
+	if err != nil {
+		os.Exit(1)
+	}
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			os.Exit(0)
+		}
+		_ = conn.Close()
+	}
+}
+
+func newTestLocalOrchestrator(t *testing.T) Orchestrator {
+	t.Setenv("FTR_LOCAL_HELPER", "1")
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	portStart := listener.Addr().(*net.TCPAddr).Port
+	require.NoError(t, listener.Close())
+
+	conf := &config.Config{Orchestrator: &config.OrchestratorConfig{
+		LocalCommand:   os.Args[0] + " -test.run=^TestLocalHelperServer$ -- --world-id {{.WorldID}} --zone-id {{.ZoneID}}",
+		LocalHost:      "127.0.0.1",
+		LocalPortStart: portStart,
+		LocalPortCount: 10,
+	}}
+	orchestrator, err := NewLocalOrchestrator(conf)
+	require.NoError(t, err)
+	return orchestrator
+}
+
+func TestLocalOrchestrator_StartsAndStopsServers(t *testing.T) {
+	orchestrator := newTestLocalOrchestrator(t)
+	worldId := uuid.New()
+
+	require.NoError(t, orchestrator.Start(ZoneServerSpec{WorldId: worldId, ZoneId: 1, ServerToken: "token"}))
+	require.NoError(t, orchestrator.Start(ZoneServerSpec{WorldId: worldId, ZoneId: 2, ServerToken: "token"}))
+	defer func() { _ = orchestrator.Stop(worldId, 2) }()
+
+	host, port, err := waitForAddress(orchestrator, worldId, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "127.0.0.1", host)
+
+	_, otherPort, err := waitForAddress(orchestrator, worldId, 2)
+	require.NoError(t, err)
+	assert.NotEqual(t, port, otherPort)
+
+	servers, err := orchestrator.List()
+	require.NoError(t, err)
+	assert.Len(t, servers, 2)
+
+	require.NoError(t, orchestrator.Stop(worldId, 1))
+	_, err = orchestrator.Status(worldId, 1)
+	var notFound *world_errors.ZoneServerNotFound
+	require.ErrorAs(t, err, &notFound)
+
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(host, strconv.Itoa(port)), time.Second)
+	if err == nil {
+		_ = conn.Close()
+	}
+	assert.Error(t, err, "the stopped server should no longer listen")
+}
+
+func TestLocalOrchestrator_RequiresCommand(t *testing.T) {
+	_, err := NewLocalOrchestrator(&config.Config{Orchestrator: &config.OrchestratorConfig{LocalPortCount: 1}})
+	assert.Error(t, err)
+}
+
+func waitForAddress(orchestrator Orchestrator, worldId uuid.UUID, zoneId int) (string, int, error) {
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		host, port, err := orchestrator.Address(worldId, zoneId)
+		if err == nil || time.Now().After(deadline) {
+			return host, port, err
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func TestParseZoneServerName(t *testing.T) {
+	worldId := uuid.New()
+
+	key, ok := parseZoneServerName(ZoneServerName(worldId, 12))
+	require.True(t, ok)
+	assert.Equal(t, ZoneKey{WorldId: worldId, ZoneId: 12}, key)
+
+	_, ok = parseZoneServerName("zone-server-not-a-world-1")
+	assert.False(t, ok)
+	_, ok = parseZoneServerName(fmt.Sprintf("other-job-%s-1", worldId))
+	assert.False(t, ok)
+}
+
+func TestParseZoneServiceTags(t *testing.T) {
+	worldId := uuid.New()
+
+	key, ok := parseZoneServiceTags([]string{"ftr", "world-" + worldId.String(), "zone-3"})
+	require.True(t, ok)
+	assert.Equal(t, ZoneKey{WorldId: worldId, ZoneId: 3}, key)
+
+	_, ok = parseZoneServiceTags([]string{"world-" + worldId.String()})
+	assert.False(t, ok)
+}
+
+func TestNomadTemplate_RendersTheZoneTier(t *testing.T) {
+	templateBytes, err := os.ReadFile("../../nomad/ftr-server-job.nomad")
+	require.NoError(t, err)
+
+	conf := &config.Config{
+		FTRServerImage: "ftr-server:1.2.0",
+		ZoneTiers: &config.ZoneTiersConfig{
+			Default: "medium",
+			Tiers: map[string]config.ZoneTier{
+				"medium": {Name: "medium", CPU: 900, MemoryMB: 470, MemoryMaxMB: 512, Datacenter: "dc1"},
+			},
+		},
+	}
+	large := config.ZoneTier{Name: "large", CPU: 1800, MemoryMB: 1024, MemoryMaxMB: 1200, Datacenter: "dc-large"}
+
+	rendered, err := renderZoneServerTemplate("ftr-server-job", string(templateBytes), newZoneServerTemplateData(conf, ZoneServerSpec{WorldId: uuid.New(), ZoneId: 1, Tier: large}))
+	require.NoError(t, err)
+	assert.Contains(t, rendered, `datacenters = ["dc-large"]`)
+	assert.Contains(t, rendered, "cpu        = 1800")
+	assert.Contains(t, rendered, "memory     = 1024")
+	assert.Contains(t, rendered, "memory_max = 1200")
+
+	rendered, err = renderZoneServerTemplate("ftr-server-job", string(templateBytes), newZoneServerTemplateData(conf, ZoneServerSpec{WorldId: uuid.New(), ZoneId: 1}))
+	require.NoError(t, err)
+	assert.Contains(t, rendered, `datacenters = ["dc1"]`)
+	assert.Contains(t, rendered, "cpu        = 900")
+}
@@ -0,0 +1,63 @@
+package orchestrator
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/FeedTheRealm-org/core-service/config"
+	"github.com/google/uuid"
+)
+
+// Orchestrator runs the game servers of world zones.
+type Orchestrator interface {
+	// Start starts the server of a zone, replacing the one it already has.
+	Start(spec ZoneServerSpec) error
+
+	// Stop stops the server of a zone.
+	Stop(worldId uuid.UUID, zoneId int) error
+
+	// Status returns the server of a zone, ZoneServerNotFound when it has none.
+	Status(worldId uuid.UUID, zoneId int) (*ZoneServer, error)
+
+	// Address returns the IP and port the healthy server of a zone takes players on, ZoneServerNotFound when it has none.
+	Address(worldId uuid.UUID, zoneId int) (string, int, error)
+
+	// List returns every server that has not been stopped.
+	List() ([]ZoneServer, error)
+}
+
+// ZoneKey identifies a zone of a world.
+type ZoneKey struct {
+	WorldId uuid.UUID
+	ZoneId  int
+}
+
+// ZoneServerSpec is what a zone server is started with.
+type ZoneServerSpec struct {
+	WorldId     uuid.UUID
+	ZoneId      int
+	IsTest      bool
+	ServerToken string
+	// Image is the game server image to run, the configured FTR_SERVER_IMAGE when empty.
+	Image string
+	// Tier is the resources the server runs with, the configured default tier when empty.
+	Tier config.ZoneTier
+}
+
+// ZoneServer is a zone server known to the orchestrator.
+type ZoneServer struct {
+	ZoneKey
+	Name      string
+	StartedAt time.Time
+	// Healthy reports whether the server passes its health checks and can take players.
+	Healthy bool
+	// Image is the image of the healthy server, empty when it is not known.
+	Image string
+}
+
+// ZoneServerName is the name of the job or process running the server of a zone.
+func ZoneServerName(worldId uuid.UUID, zoneId int) string {
+	return fmt.Sprintf("%s%s-%d", zoneServerPrefix, worldId.String(), zoneId)
+}
+
+const zoneServerPrefix = "zone-server-"
@@ -0,0 +1,66 @@
+package orchestrator
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"text/template"
+	"time"
+
+	"github.com/FeedTheRealm-org/core-service/config"
+)
+
+// zoneServerTemplateData is given to the Nomad job template and to the local command template.
+type zoneServerTemplateData struct {
+	JobName     string
+	WorldID     string
+	ZoneID      int
+	IsTestWorld string
+	ImageName   string
+	DeployedAt  string
+	ServerToken string
+	Port        int
+	Tier        string
+	CPU         int
+	Memory      int
+	MemoryMax   int
+	Datacenter  string
+}
+
+func newZoneServerTemplateData(conf *config.Config, spec ZoneServerSpec) zoneServerTemplateData {
+	image := spec.Image
+	if image == "" {
+		image = conf.FTRServerImage
+	}
+	tier := spec.Tier
+	if tier.Name == "" && conf.ZoneTiers != nil {
+		tier, _ = conf.ZoneTiers.Tier("")
+	}
+	return zoneServerTemplateData{
+		JobName:     ZoneServerName(spec.WorldId, spec.ZoneId),
+		WorldID:     spec.WorldId.String(),
+		ZoneID:      spec.ZoneId,
+		IsTestWorld: strconv.FormatBool(spec.IsTest),
+		ImageName:   image,
+		DeployedAt:  time.Now().UTC().Format(time.RFC3339),
+		ServerToken: spec.ServerToken,
+		Tier:        tier.Name,
+		CPU:         tier.CPU,
+		Memory:      tier.MemoryMB,
+		MemoryMax:   tier.MemoryMaxMB,
+		Datacenter:  tier.Datacenter,
+	}
+}
+
+func renderZoneServerTemplate(name string, text string, data zoneServerTemplateData) (string, error) {
+	parsed, err := template.New(name).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse %s template: %w", name, err)
+	}
+
+	var rendered bytes.Buffer
+	if err := parsed.Execute(&rendered, data); err != nil {
+		return "", fmt.Errorf("failed to render %s template: %w", name, err)
+	}
+	return rendered.String(), nil
+}
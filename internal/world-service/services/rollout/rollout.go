@@ -0,0 +1,448 @@
+package rollout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/FeedTheRealm-org/core-service/config"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/leader_election"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/logger"
+	world_errors "github.com/FeedTheRealm-org/core-service/internal/world-service/errors"
+	"github.com/FeedTheRealm-org/core-service/internal/world-service/models"
+	"github.com/FeedTheRealm-org/core-service/internal/world-service/repositories/deployments"
+	"github.com/FeedTheRealm-org/core-service/internal/world-service/repositories/world"
+	"github.com/FeedTheRealm-org/core-service/internal/world-service/services/orchestrator"
+	"github.com/FeedTheRealm-org/core-service/internal/world-service/services/server_registry"
+	"github.com/google/uuid"
+)
+
+type rolloutService struct {
+	conf            *config.Config
+	repo            deployments.DeploymentsRepository
+	worldRepository world.WorldRepository
+	registry        server_registry.ServerRegistryService
+	orchestrator    orchestrator.Orchestrator
+	elector         leader_election.Elector
+}
+
+func NewRolloutService(conf *config.Config, repo deployments.DeploymentsRepository, worldRepository world.WorldRepository, registry server_registry.ServerRegistryService, orchestrator orchestrator.Orchestrator, elector leader_election.Elector) RolloutService {
+	return &rolloutService{
+		conf:            conf,
+		repo:            repo,
+		worldRepository: worldRepository,
+		registry:        registry,
+		orchestrator:    orchestrator,
+		elector:         elector,
+	}
+}
+
+func (r *rolloutService) CreateRollout(req RolloutRequest) (*models.ServerDeployment, error) {
+	if strings.TrimSpace(req.Image) == "" {
+		return nil, errors.New("no image to roll out")
+	}
+
+	active, err := r.repo.GetActiveDeployment()
+	if err != nil {
+		var notFound *world_errors.DeploymentNotFound
+		if !errors.As(err, &notFound) {
+			return nil, err
+		}
+	} else if active.Status == models.DEPLOYMENT_ROLLING_BACK || active.Status == models.DEPLOYMENT_ABORTING {
+		return nil, world_errors.NewDeploymentStateConflict(fmt.Sprintf("rollout %s is being rolled back", active.ID))
+	}
+
+	previousImage, err := r.repo.GetCurrentImage()
+	if err != nil {
+		return nil, err
+	}
+	if previousImage == "" {
+		previousImage = r.conf.FTRServerImage
+	}
+
+	batchSize := req.BatchSize
+	if batchSize <= 0 {
+		batchSize = r.conf.Rollout.BatchSize
+	}
+	batchSize = max(batchSize, 1)
+	batchPause := r.conf.Rollout.BatchPause
+	if req.BatchPause != nil {
+		batchPause = *req.BatchPause
+	}
+	canaryZones := req.CanaryZones
+	if canaryZones == nil {
+		canaryZones = ParseZoneKeys(r.conf.Rollout.CanaryZones)
+	}
+
+	activeZones, err := r.worldRepository.GetActiveWorldZones()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active world zones: %w", err)
+	}
+
+	now := time.Now().UTC()
+	deployment := &models.ServerDeployment{
+		ID:                uuid.New(),
+		Image:             req.Image,
+		PreviousImage:     previousImage,
+		Status:            models.DEPLOYMENT_RUNNING,
+		Source:            req.Source,
+		BatchSize:         batchSize,
+		BatchPauseSeconds: int(batchPause.Seconds()),
+	}
+	zones := planBatches(deployment.ID, activeZones, canaryZones, batchSize, now)
+	for _, zone := range zones {
+		deployment.TotalBatches = max(deployment.TotalBatches, zone.Batch+1)
+	}
+	// With no active zone there is nothing to update, the image only becomes the current one
+	if len(zones) == 0 {
+		deployment.Status = models.DEPLOYMENT_SUCCEEDED
+		deployment.FinishedAt = &now
+	}
+
+	if err := r.repo.CreateDeployment(deployment, zones); err != nil {
+		return nil, fmt.Errorf("failed to store rollout: %w", err)
+	}
+
+	logger.Logger.Infof("Rolling out %s to %d zones in %d batches, replacing %s", deployment.Image, len(zones), deployment.TotalBatches, previousImage)
+	return deployment, nil
+}
+
+// planBatches puts the active canary zones in a batch of their own and splits the other zones in batches of batchSize.
+func planBatches(deploymentID uuid.UUID, activeZones []*models.WorldZone, canaryZones []orchestrator.ZoneKey, batchSize int, now time.Time) []*models.DeploymentZone {
+	isCanary := make(map[orchestrator.ZoneKey]bool, len(canaryZones))
+	for _, key := range canaryZones {
+		isCanary[key] = true
+	}
+
+	zones := make([]*models.DeploymentZone, 0, len(activeZones))
+	var others []*models.WorldZone
+	for _, zone := range activeZones {
+		if !isCanary[orchestrator.ZoneKey{WorldId: zone.WorldID, ZoneId: zone.ID}] {
+			others = append(others, zone)
+			continue
+		}
+		zones = append(zones, &models.DeploymentZone{
+			DeploymentID: deploymentID,
+			WorldID:      zone.WorldID,
+			ZoneID:       zone.ID,
+			Batch:        0,
+			IsCanary:     true,
+			Status:       models.DEPLOYMENT_ZONE_PENDING,
+			UpdatedAt:    now,
+		})
+	}
+
+	firstBatch := 0
+	if len(zones) > 0 {
+		firstBatch = 1
+	}
+	for i, zone := range others {
+		zones = append(zones, &models.DeploymentZone{
+			DeploymentID: deploymentID,
+			WorldID:      zone.WorldID,
+			ZoneID:       zone.ID,
+			Batch:        firstBatch + i/batchSize,
+			Status:       models.DEPLOYMENT_ZONE_PENDING,
+			UpdatedAt:    now,
+		})
+	}
+	return zones
+}
+
+func (r *rolloutService) GetRollout(id uuid.UUID) (*models.ServerDeployment, []*models.DeploymentZone, error) {
+	deployment, err := r.repo.GetDeployment(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	zones, err := r.repo.GetDeploymentZones(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	return deployment, zones, nil
+}
+
+func (r *rolloutService) ListRollouts(offset int, limit int) ([]*models.ServerDeployment, error) {
+	return r.repo.ListDeployments(offset, limit)
+}
+
+func (r *rolloutService) PauseRollout(id uuid.UUID) (*models.ServerDeployment, error) {
+	return r.transition(id, models.DEPLOYMENT_RUNNING, func(deployment *models.ServerDeployment) error {
+		deployment.Status = models.DEPLOYMENT_PAUSED
+		return nil
+	})
+}
+
+func (r *rolloutService) ResumeRollout(id uuid.UUID) (*models.ServerDeployment, error) {
+	return r.transition(id, models.DEPLOYMENT_PAUSED, func(deployment *models.ServerDeployment) error {
+		// Zones left updating get a fresh health window, the pause is not their fault
+		zones, err := r.repo.GetDeploymentZones(id)
+		if err != nil {
+			return err
+		}
+		now := time.Now().UTC()
+		for _, zone := range zones {
+			if zone.Status != models.DEPLOYMENT_ZONE_UPDATING {
+				continue
+			}
+			zone.UpdatedAt = now
+			if err := r.repo.UpdateDeploymentZone(zone); err != nil {
+				return err
+			}
+		}
+		deployment.Status = models.DEPLOYMENT_RUNNING
+		return nil
+	})
+}
+
+func (r *rolloutService) AbortRollout(id uuid.UUID) (*models.ServerDeployment, error) {
+	deployment, err := r.repo.GetDeployment(id)
+	if err != nil {
+		return nil, err
+	}
+	if deployment.Status != models.DEPLOYMENT_RUNNING && deployment.Status != models.DEPLOYMENT_PAUSED {
+		return nil, world_errors.NewDeploymentStateConflict(fmt.Sprintf("rollout %s is %s", id, deployment.Status))
+	}
+
+	// The leader rolls the updated zones back on its next step
+	return r.transition(id, deployment.Status, func(deployment *models.ServerDeployment) error {
+		deployment.Status = models.DEPLOYMENT_ABORTING
+		deployment.Error = "aborted"
+		return nil
+	})
+}
+
+// transition applies change to a rollout that is in the from status, failing when it is not.
+func (r *rolloutService) transition(id uuid.UUID, from string, change func(deployment *models.ServerDeployment) error) (*models.ServerDeployment, error) {
+	deployment, err := r.repo.GetDeployment(id)
+	if err != nil {
+		return nil, err
+	}
+	if deployment.Status != from {
+		return nil, world_errors.NewDeploymentStateConflict(fmt.Sprintf("rollout %s is %s", id, deployment.Status))
+	}
+
+	if err := change(deployment); err != nil {
+		return nil, err
+	}
+	updated, err := r.repo.UpdateDeployment(deployment, from)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, world_errors.NewDeploymentStateConflict(fmt.Sprintf("rollout %s changed state in the meantime", id))
+	}
+	return deployment, nil
+}
+
+func (r *rolloutService) Advance() error {
+	deployment, err := r.repo.GetActiveDeployment()
+	if err != nil {
+		var notFound *world_errors.DeploymentNotFound
+		if errors.As(err, &notFound) {
+			return nil
+		}
+		return err
+	}
+
+	switch deployment.Status {
+	case models.DEPLOYMENT_RUNNING:
+		return r.advanceBatch(deployment)
+	case models.DEPLOYMENT_ROLLING_BACK, models.DEPLOYMENT_ABORTING:
+		return r.rollBack(deployment)
+	}
+	return nil
+}
+
+// advanceBatch starts the pending zones of the current batch and checks the health of the updating ones,
+// moving to the next batch once the pause after a healthy batch is over.
+func (r *rolloutService) advanceBatch(deployment *models.ServerDeployment) error {
+	now := time.Now().UTC()
+	if deployment.BatchFinishedAt != nil {
+		if now.Before(deployment.BatchFinishedAt.Add(time.Duration(deployment.BatchPauseSeconds) * time.Second)) {
+			return nil
+		}
+		deployment.CurrentBatch++
+		deployment.BatchFinishedAt = nil
+		_, err := r.repo.UpdateDeployment(deployment, models.DEPLOYMENT_RUNNING)
+		return err
+	}
+
+	zones, err := r.repo.GetDeploymentZones(deployment.ID)
+	if err != nil {
+		return err
+	}
+
+	done := true
+	var failures []string
+	for _, zone := range zones {
+		if zone.Batch != deployment.CurrentBatch {
+			continue
+		}
+		switch zone.Status {
+		case models.DEPLOYMENT_ZONE_PENDING:
+			err = r.startZone(deployment, zone, now)
+		case models.DEPLOYMENT_ZONE_UPDATING:
+			err = r.checkZone(deployment, zone, now)
+		}
+		if err != nil {
+			return err
+		}
+
+		switch zone.Status {
+		case models.DEPLOYMENT_ZONE_PENDING, models.DEPLOYMENT_ZONE_UPDATING:
+			done = false
+		case models.DEPLOYMENT_ZONE_FAILED:
+			failures = append(failures, fmt.Sprintf("world %s zone %d: %s", zone.WorldID, zone.ZoneID, zone.Error))
+		}
+	}
+
+	if len(failures) > 0 {
+		deployment.Status = models.DEPLOYMENT_ROLLING_BACK
+		deployment.Error = fmt.Sprintf("batch %d failed, %s", deployment.CurrentBatch, strings.Join(failures, ", "))
+		logger.Logger.Warnf("Rolling back %s: %s", deployment.Image, deployment.Error)
+		_, err := r.repo.UpdateDeployment(deployment, models.DEPLOYMENT_RUNNING)
+		return err
+	}
+	if !done {
+		return nil
+	}
+
+	if deployment.CurrentBatch >= deployment.TotalBatches-1 {
+		deployment.Status = models.DEPLOYMENT_SUCCEEDED
+		deployment.FinishedAt = &now
+		logger.Logger.Infof("Rolled out %s to every active zone", deployment.Image)
+	} else {
+		deployment.BatchFinishedAt = &now
+	}
+	_, err = r.repo.UpdateDeployment(deployment, models.DEPLOYMENT_RUNNING)
+	return err
+}
+
+func (r *rolloutService) startZone(deployment *models.ServerDeployment, zone *models.DeploymentZone, now time.Time) error {
+	worldZone, err := r.worldRepository.GetWorldZone(zone.WorldID, zone.ZoneID)
+	switch {
+	case err != nil || !worldZone.IsActive:
+		// Deactivated since the rollout started, it gets the current image when activated again
+		zone.Status = models.DEPLOYMENT_ZONE_SKIPPED
+	case r.registry.StartJobWithImage(zone.WorldID, zone.ZoneID, false, deployment.Image) != nil:
+		zone.Status = models.DEPLOYMENT_ZONE_FAILED
+		zone.Error = "failed to start the server"
+	default:
+		zone.Status = models.DEPLOYMENT_ZONE_UPDATING
+	}
+	zone.UpdatedAt = now
+	return r.repo.UpdateDeploymentZone(zone)
+}
+
+// checkZone marks an updating zone healthy once its server passes its health checks on the new image,
+// or failed when it does not within the health timeout.
+func (r *rolloutService) checkZone(deployment *models.ServerDeployment, zone *models.DeploymentZone, now time.Time) error {
+	status, err := r.orchestrator.Status(zone.WorldID, zone.ZoneID)
+	switch {
+	case err == nil && status.Healthy && status.Image == deployment.Image:
+		zone.Status = models.DEPLOYMENT_ZONE_HEALTHY
+	case now.Sub(zone.UpdatedAt) > r.conf.Rollout.HealthTimeout:
+		zone.Status = models.DEPLOYMENT_ZONE_FAILED
+		zone.Error = fmt.Sprintf("not healthy on %s within %s", deployment.Image, r.conf.Rollout.HealthTimeout)
+	default:
+		return nil
+	}
+	zone.UpdatedAt = now
+	return r.repo.UpdateDeploymentZone(zone)
+}
+
+// rollBack restarts every zone the rollout touched on the previous image, then finishes the rollout.
+func (r *rolloutService) rollBack(deployment *models.ServerDeployment) error {
+	zones, err := r.repo.GetDeploymentZones(deployment.ID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	var failures []string
+	for _, zone := range zones {
+		if zone.Status != models.DEPLOYMENT_ZONE_UPDATING && zone.Status != models.DEPLOYMENT_ZONE_HEALTHY && zone.Status != models.DEPLOYMENT_ZONE_FAILED {
+			continue
+		}
+		if deployment.PreviousImage == "" {
+			failures = append(failures, "no previous image to roll back to")
+			break
+		}
+
+		worldZone, err := r.worldRepository.GetWorldZone(zone.WorldID, zone.ZoneID)
+		if err == nil && worldZone.IsActive {
+			if err := r.registry.StartJobWithImage(zone.WorldID, zone.ZoneID, false, deployment.PreviousImage); err != nil {
+				zone.Error = strings.TrimPrefix(zone.Error+", failed to roll back", ", ")
+				failures = append(failures, fmt.Sprintf("world %s zone %d: %v", zone.WorldID, zone.ZoneID, err))
+			}
+		}
+		zone.Status = models.DEPLOYMENT_ZONE_ROLLED_BACK
+		zone.UpdatedAt = now
+		if err := r.repo.UpdateDeploymentZone(zone); err != nil {
+			return err
+		}
+	}
+
+	from := deployment.Status
+	deployment.Status = models.DEPLOYMENT_ROLLED_BACK
+	if from == models.DEPLOYMENT_ABORTING {
+		deployment.Status = models.DEPLOYMENT_ABORTED
+	}
+	if len(failures) > 0 {
+		deployment.Error += ", rollback failed for " + strings.Join(failures, ", ")
+	}
+	deployment.FinishedAt = &now
+	logger.Logger.Infof("Rolled %s back to %s", deployment.Image, deployment.PreviousImage)
+	_, err = r.repo.UpdateDeployment(deployment, from)
+	return err
+}
+
+func (r *rolloutService) Start() {
+	interval := r.conf.Rollout.CheckInterval
+	if interval <= 0 {
+		logger.Logger.Info("Server image rollouts disabled")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			// Only one replica starts and checks zones, any replica can pause or abort through the database
+			if !r.elector.IsLeader(context.Background()) {
+				continue
+			}
+			if err := r.Advance(); err != nil {
+				logger.Logger.Errorf("Server image rollout step failed: %v", err)
+			}
+		}
+	}()
+}
+
+// ParseZoneKeys reads <world id>:<zone id> entries, invalid entries are logged and skipped.
+func ParseZoneKeys(entries []string) []orchestrator.ZoneKey {
+	keys := make([]orchestrator.ZoneKey, 0, len(entries))
+	for _, entry := range entries {
+		worldIdStr, zoneIdStr, ok := strings.Cut(entry, ":")
+		worldId, worldErr := uuid.Parse(worldIdStr)
+		zoneId, zoneErr := strconv.Atoi(zoneIdStr)
+		if !ok || worldErr != nil || zoneErr != nil {
+			logger.Logger.Warnf("Ignoring invalid canary zone %q, expected <world id>:<zone id>", entry)
+			continue
+		}
+		keys = append(keys, orchestrator.ZoneKey{WorldId: worldId, ZoneId: zoneId})
+	}
+	return keys
+}
+
+// ImageWithTag replaces the tag of image, keeping the registry port of images without one.
+func ImageWithTag(image string, tag string) string {
+	separator := strings.LastIndex(image, ":")
+	if separator > strings.LastIndex(image, "/") {
+		image = image[:separator]
+	}
+	return image + ":" + tag
+}
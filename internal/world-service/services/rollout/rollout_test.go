@@ -0,0 +1,375 @@
+package rollout
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/FeedTheRealm-org/core-service/config"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/logger"
+	world_errors "github.com/FeedTheRealm-org/core-service/internal/world-service/errors"
+	"github.com/FeedTheRealm-org/core-service/internal/world-service/models"
+	"github.com/FeedTheRealm-org/core-service/internal/world-service/repositories/world"
+	"github.com/FeedTheRealm-org/core-service/internal/world-service/services/orchestrator"
+	"github.com/FeedTheRealm-org/core-service/internal/world-service/services/server_registry"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeDeploymentsRepo struct {
+	deployments []*models.ServerDeployment
+	zones       map[uuid.UUID][]*models.DeploymentZone
+}
+
+func newFakeDeploymentsRepo() *fakeDeploymentsRepo {
+	return &fakeDeploymentsRepo{zones: map[uuid.UUID][]*models.DeploymentZone{}}
+}
+
+func (f *fakeDeploymentsRepo) CreateDeployment(deployment *models.ServerDeployment, zones []*models.DeploymentZone) error {
+	for _, existing := range f.deployments {
+		if existing.Status == models.DEPLOYMENT_RUNNING || existing.Status == models.DEPLOYMENT_PAUSED {
+			existing.Status = models.DEPLOYMENT_SUPERSEDED
+		}
+	}
+	stored := *deployment
+	f.deployments = append(f.deployments, &stored)
+	for _, zone := range zones {
+		storedZone := *zone
+		f.zones[deployment.ID] = append(f.zones[deployment.ID], &storedZone)
+	}
+	return nil
+}
+
+func (f *fakeDeploymentsRepo) GetDeployment(id uuid.UUID) (*models.ServerDeployment, error) {
+	for _, deployment := range f.deployments {
+		if deployment.ID == id {
+			found := *deployment
+			return &found, nil
+		}
+	}
+	return nil, world_errors.NewDeploymentNotFound("deployment not found")
+}
+
+func (f *fakeDeploymentsRepo) GetActiveDeployment() (*models.ServerDeployment, error) {
+	for i := len(f.deployments) - 1; i >= 0; i-- {
+		switch f.deployments[i].Status {
+		case models.DEPLOYMENT_RUNNING, models.DEPLOYMENT_PAUSED, models.DEPLOYMENT_ROLLING_BACK, models.DEPLOYMENT_ABORTING:
+			found := *f.deployments[i]
+			return &found, nil
+		}
+	}
+	return nil, world_errors.NewDeploymentNotFound("no deployment in progress")
+}
+
+func (f *fakeDeploymentsRepo) GetCurrentImage() (string, error) {
+	for i := len(f.deployments) - 1; i >= 0; i-- {
+		if f.deployments[i].Status == models.DEPLOYMENT_SUCCEEDED {
+			return f.deployments[i].Image, nil
+		}
+	}
+	return "", nil
+}
+
+func (f *fakeDeploymentsRepo) ListDeployments(offset int, limit int) ([]*models.ServerDeployment, error) {
+	return f.deployments, nil
+}
+
+func (f *fakeDeploymentsRepo) UpdateDeployment(deployment *models.ServerDeployment, fromStatus string) (bool, error) {
+	for i, existing := range f.deployments {
+		if existing.ID == deployment.ID {
+			if existing.Status != fromStatus {
+				return false, nil
+			}
+			stored := *deployment
+			f.deployments[i] = &stored
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (f *fakeDeploymentsRepo) GetDeploymentZones(deploymentID uuid.UUID) ([]*models.DeploymentZone, error) {
+	zones := make([]*models.DeploymentZone, 0, len(f.zones[deploymentID]))
+	for _, zone := range f.zones[deploymentID] {
+		found := *zone
+		zones = append(zones, &found)
+	}
+	sort.SliceStable(zones, func(i, j int) bool { return zones[i].Batch < zones[j].Batch })
+	return zones, nil
+}
+
+func (f *fakeDeploymentsRepo) UpdateDeploymentZone(zone *models.DeploymentZone) error {
+	for _, existing := range f.zones[zone.DeploymentID] {
+		if existing.WorldID == zone.WorldID && existing.ZoneID == zone.ZoneID {
+			*existing = *zone
+		}
+	}
+	return nil
+}
+
+// ageUpdatingZones makes the health timeout of the updating zones run out.
+func (f *fakeDeploymentsRepo) ageUpdatingZones() {
+	for _, zones := range f.zones {
+		for _, zone := range zones {
+			if zone.Status == models.DEPLOYMENT_ZONE_UPDATING {
+				zone.UpdatedAt = zone.UpdatedAt.Add(-time.Hour)
+			}
+		}
+	}
+}
+
+type fakeWorldRepo struct {
+	world.WorldRepository
+	zones []*models.WorldZone
+}
+
+func (f *fakeWorldRepo) GetActiveWorldZones() ([]*models.WorldZone, error) {
+	var active []*models.WorldZone
+	for _, zone := range f.zones {
+		if zone.IsActive {
+			active = append(active, zone)
+		}
+	}
+	return active, nil
+}
+
+func (f *fakeWorldRepo) GetWorldZone(worldID uuid.UUID, zoneID int) (*models.WorldZone, error) {
+	for _, zone := range f.zones {
+		if zone.WorldID == worldID && zone.ID == zoneID {
+			return zone, nil
+		}
+	}
+	return nil, world_errors.NewWorldNotFound("zone not found")
+}
+
+// unhealthyOrchestrator is an in-memory orchestrator whose servers of some images never pass their health checks.
+type unhealthyOrchestrator struct {
+	orchestrator.Orchestrator
+	unhealthy map[string]bool
+}
+
+func (o *unhealthyOrchestrator) Status(worldId uuid.UUID, zoneId int) (*orchestrator.ZoneServer, error) {
+	server, err := o.Orchestrator.Status(worldId, zoneId)
+	if err == nil && o.unhealthy[server.Image] {
+		server.Healthy = false
+	}
+	return server, err
+}
+
+type fakeRegistry struct {
+	server_registry.ServerRegistryService
+	zoneServers orchestrator.Orchestrator
+	started     []string
+}
+
+func (f *fakeRegistry) StartJobWithImage(worldId uuid.UUID, zoneId int, isTest bool, image string) error {
+	f.started = append(f.started, image)
+	return f.zoneServers.Start(orchestrator.ZoneServerSpec{WorldId: worldId, ZoneId: zoneId, Image: image})
+}
+
+func (f *fakeRegistry) imageOf(t *testing.T, worldId uuid.UUID, zoneId int) string {
+	server, err := f.zoneServers.Status(worldId, zoneId)
+	require.NoError(t, err)
+	return server.Image
+}
+
+func newTestRollout(worldRepo *fakeWorldRepo, canaries ...string) (*rolloutService, *fakeDeploymentsRepo, *fakeRegistry, *unhealthyOrchestrator) {
+	logger.InitLogger(false)
+	conf := &config.Config{
+		FTRServerImage: "ghcr.io/feedtherealm/ftr-server:1.0.0",
+		Rollout: &config.RolloutConfig{
+			BatchSize:     2,
+			HealthTimeout: time.Minute,
+			CheckInterval: time.Second,
+			CanaryZones:   canaries,
+		},
+	}
+	repo := newFakeDeploymentsRepo()
+	zoneServers := &unhealthyOrchestrator{Orchestrator: orchestrator.NewMemoryOrchestrator(), unhealthy: map[string]bool{}}
+	registry := &fakeRegistry{zoneServers: zoneServers}
+	return NewRolloutService(conf, repo, worldRepo, registry, zoneServers, nil).(*rolloutService), repo, registry, zoneServers
+}
+
+func advanceUntilDone(t *testing.T, service *rolloutService, id uuid.UUID) *models.ServerDeployment {
+	for range 50 {
+		require.NoError(t, service.Advance())
+		deployment, _, err := service.GetRollout(id)
+		require.NoError(t, err)
+		if deployment.FinishedAt != nil {
+			return deployment
+		}
+	}
+	t.Fatal("rollout did not finish")
+	return nil
+}
+
+func TestRollout_UpdatesCanariesFirstThenEveryBatch(t *testing.T) {
+	worldId := uuid.New()
+	worldRepo := &fakeWorldRepo{zones: []*models.WorldZone{
+		{WorldID: worldId, ID: 1, IsActive: true},
+		{WorldID: worldId, ID: 2, IsActive: true},
+		{WorldID: worldId, ID: 3, IsActive: true},
+		{WorldID: worldId, ID: 4, IsActive: false},
+	}}
+	service, repo, registry, _ := newTestRollout(worldRepo, worldId.String()+":3")
+
+	noPause := time.Duration(0)
+	deployment, err := service.CreateRollout(RolloutRequest{Image: "ghcr.io/feedtherealm/ftr-server:1.1.0", BatchPause: &noPause})
+	require.NoError(t, err)
+	assert.Equal(t, "ghcr.io/feedtherealm/ftr-server:1.0.0", deployment.PreviousImage)
+	assert.Equal(t, 2, deployment.TotalBatches)
+
+	_, zones, err := service.GetRollout(deployment.ID)
+	require.NoError(t, err)
+	require.Len(t, zones, 3)
+	assert.Equal(t, 3, zones[0].ZoneID)
+	assert.True(t, zones[0].IsCanary)
+	assert.Equal(t, 0, zones[0].Batch)
+
+	// The first step only starts the canary
+	require.NoError(t, service.Advance())
+	assert.Len(t, registry.started, 1)
+	assert.Equal(t, "ghcr.io/feedtherealm/ftr-server:1.1.0", registry.imageOf(t, worldId, 3))
+
+	finished := advanceUntilDone(t, service, deployment.ID)
+	assert.Equal(t, models.DEPLOYMENT_SUCCEEDED, finished.Status)
+	assert.Len(t, registry.started, 3)
+	for _, zoneId := range []int{1, 2, 3} {
+		assert.Equal(t, "ghcr.io/feedtherealm/ftr-server:1.1.0", registry.imageOf(t, worldId, zoneId))
+	}
+
+	current, err := repo.GetCurrentImage()
+	require.NoError(t, err)
+	assert.Equal(t, "ghcr.io/feedtherealm/ftr-server:1.1.0", current)
+}
+
+func TestRollout_WaitsForTheBatchPause(t *testing.T) {
+	worldId := uuid.New()
+	worldRepo := &fakeWorldRepo{zones: []*models.WorldZone{
+		{WorldID: worldId, ID: 1, IsActive: true},
+		{WorldID: worldId, ID: 2, IsActive: true},
+	}}
+	service, _, registry, _ := newTestRollout(worldRepo)
+
+	pause := time.Hour
+	deployment, err := service.CreateRollout(RolloutRequest{Image: "ftr-server:1.1.0", BatchSize: 1, BatchPause: &pause})
+	require.NoError(t, err)
+
+	for range 5 {
+		require.NoError(t, service.Advance())
+	}
+
+	deployment, _, err = service.GetRollout(deployment.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.DEPLOYMENT_RUNNING, deployment.Status)
+	assert.Equal(t, 0, deployment.CurrentBatch)
+	assert.NotNil(t, deployment.BatchFinishedAt)
+	assert.Len(t, registry.started, 1)
+}
+
+func TestRollout_RollsBackWhenABatchIsNotHealthy(t *testing.T) {
+	worldId := uuid.New()
+	worldRepo := &fakeWorldRepo{zones: []*models.WorldZone{
+		{WorldID: worldId, ID: 1, IsActive: true},
+		{WorldID: worldId, ID: 2, IsActive: true},
+		{WorldID: worldId, ID: 3, IsActive: true},
+	}}
+	service, repo, registry, zoneServers := newTestRollout(worldRepo, worldId.String()+":1")
+	zoneServers.unhealthy["ftr-server:broken"] = true
+
+	deployment, err := service.CreateRollout(RolloutRequest{Image: "ftr-server:broken"})
+	require.NoError(t, err)
+
+	require.NoError(t, service.Advance())
+	require.NoError(t, service.Advance())
+	deployment, _, err = service.GetRollout(deployment.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.DEPLOYMENT_RUNNING, deployment.Status, "the canary still has time to become healthy")
+
+	repo.ageUpdatingZones()
+	finished := advanceUntilDone(t, service, deployment.ID)
+	assert.Equal(t, models.DEPLOYMENT_ROLLED_BACK, finished.Status)
+	assert.Contains(t, finished.Error, "batch 0 failed")
+
+	// Only the canary was touched, and it is back on the previous image
+	assert.Equal(t, []string{"ftr-server:broken", "ghcr.io/feedtherealm/ftr-server:1.0.0"}, registry.started)
+	assert.Equal(t, "ghcr.io/feedtherealm/ftr-server:1.0.0", registry.imageOf(t, worldId, 1))
+
+	_, zones, err := service.GetRollout(deployment.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.DEPLOYMENT_ZONE_ROLLED_BACK, zones[0].Status)
+	assert.Equal(t, models.DEPLOYMENT_ZONE_PENDING, zones[1].Status)
+
+	current, err := repo.GetCurrentImage()
+	require.NoError(t, err)
+	assert.Empty(t, current)
+}
+
+func TestRollout_PauseResumeAndAbort(t *testing.T) {
+	worldId := uuid.New()
+	worldRepo := &fakeWorldRepo{zones: []*models.WorldZone{
+		{WorldID: worldId, ID: 1, IsActive: true},
+		{WorldID: worldId, ID: 2, IsActive: true},
+	}}
+	service, _, registry, _ := newTestRollout(worldRepo)
+
+	deployment, err := service.CreateRollout(RolloutRequest{Image: "ftr-server:1.1.0", BatchSize: 1})
+	require.NoError(t, err)
+
+	paused, err := service.PauseRollout(deployment.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.DEPLOYMENT_PAUSED, paused.Status)
+	_, err = service.PauseRollout(deployment.ID)
+	assert.IsType(t, &world_errors.DeploymentStateConflict{}, err)
+
+	require.NoError(t, service.Advance())
+	assert.Empty(t, registry.started, "a paused rollout starts no zone")
+
+	_, err = service.ResumeRollout(deployment.ID)
+	require.NoError(t, err)
+	require.NoError(t, service.Advance())
+	assert.Len(t, registry.started, 1)
+
+	aborted, err := service.AbortRollout(deployment.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.DEPLOYMENT_ABORTING, aborted.Status)
+
+	_, err = service.CreateRollout(RolloutRequest{Image: "ftr-server:1.2.0"})
+	assert.IsType(t, &world_errors.DeploymentStateConflict{}, err, "no rollout starts while one is being rolled back")
+
+	finished := advanceUntilDone(t, service, deployment.ID)
+	assert.Equal(t, models.DEPLOYMENT_ABORTED, finished.Status)
+	assert.Equal(t, "ghcr.io/feedtherealm/ftr-server:1.0.0", registry.imageOf(t, worldId, 1))
+	assert.Len(t, registry.started, 2)
+}
+
+func TestRollout_SupersedesTheRunningRollout(t *testing.T) {
+	worldId := uuid.New()
+	worldRepo := &fakeWorldRepo{zones: []*models.WorldZone{{WorldID: worldId, ID: 1, IsActive: true}}}
+	service, _, _, _ := newTestRollout(worldRepo)
+
+	first, err := service.CreateRollout(RolloutRequest{Image: "ftr-server:1.1.0"})
+	require.NoError(t, err)
+	second, err := service.CreateRollout(RolloutRequest{Image: "ftr-server:1.2.0"})
+	require.NoError(t, err)
+
+	first, _, err = service.GetRollout(first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.DEPLOYMENT_SUPERSEDED, first.Status)
+
+	finished := advanceUntilDone(t, service, second.ID)
+	assert.Equal(t, models.DEPLOYMENT_SUCCEEDED, finished.Status)
+}
+
+func TestParseZoneKeys(t *testing.T) {
+	worldId := uuid.New()
+	keys := ParseZoneKeys([]string{worldId.String() + ":2", "not-a-world:1", worldId.String(), worldId.String() + ":x"})
+	assert.Equal(t, []orchestrator.ZoneKey{{WorldId: worldId, ZoneId: 2}}, keys)
+}
+
+func TestImageWithTag(t *testing.T) {
+	assert.Equal(t, "ghcr.io/feedtherealm/ftr-server:v1.2.0", ImageWithTag("ghcr.io/feedtherealm/ftr-server:latest", "v1.2.0"))
+	assert.Equal(t, "ftr-server:v1.2.0", ImageWithTag("ftr-server", "v1.2.0"))
+	assert.Equal(t, "localhost:5000/ftr-server:v1.2.0", ImageWithTag("localhost:5000/ftr-server", "v1.2.0"))
+}
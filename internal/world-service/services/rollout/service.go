@@ -0,0 +1,48 @@
+package rollout
+
+import (
+	"time"
+
+	"github.com/FeedTheRealm-org/core-service/internal/world-service/models"
+	"github.com/FeedTheRealm-org/core-service/internal/world-service/services/orchestrator"
+	"github.com/google/uuid"
+)
+
+// RolloutRequest is the image to roll out to every active zone, the zero values of the other
+// fields fall back to the configured ones.
+type RolloutRequest struct {
+	Image       string
+	Source      string
+	BatchSize   int
+	BatchPause  *time.Duration
+	CanaryZones []orchestrator.ZoneKey
+}
+
+// RolloutService updates the game server image of every active zone batch by batch, canary zones first.
+// A batch only starts once the previous one is healthy on the new image, a batch that is not rolls every
+// updated zone back to the previous image.
+type RolloutService interface {
+	// CreateRollout starts rolling out an image, superseding the running or paused rollout.
+	CreateRollout(req RolloutRequest) (*models.ServerDeployment, error)
+
+	// GetRollout returns a rollout and the progress of each of its zones.
+	GetRollout(id uuid.UUID) (*models.ServerDeployment, []*models.DeploymentZone, error)
+
+	// ListRollouts returns the rollouts from newest to oldest.
+	ListRollouts(offset int, limit int) ([]*models.ServerDeployment, error)
+
+	// PauseRollout stops a running rollout from starting new zones.
+	PauseRollout(id uuid.UUID) (*models.ServerDeployment, error)
+
+	// ResumeRollout continues a paused rollout.
+	ResumeRollout(id uuid.UUID) (*models.ServerDeployment, error)
+
+	// AbortRollout stops a running or paused rollout and rolls the zones it updated back to the previous image.
+	AbortRollout(id uuid.UUID) (*models.ServerDeployment, error)
+
+	// Advance moves the active rollout one step forward.
+	Advance() error
+
+	// Start runs Advance every configured interval while this replica is the leader.
+	Start()
+}
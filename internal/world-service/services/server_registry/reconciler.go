@@ -0,0 +1,210 @@
+package server_registry
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/FeedTheRealm-org/core-service/config"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/leader_election"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/logger"
+	"github.com/FeedTheRealm-org/core-service/internal/world-service/repositories/server_registry"
+	"github.com/FeedTheRealm-org/core-service/internal/world-service/repositories/world"
+	"github.com/FeedTheRealm-org/core-service/internal/world-service/services/orchestrator"
+)
+
+// ReconcileReport describes what a reconciliation pass found and changed.
+type ReconcileReport struct {
+	StartedAt     time.Time
+	FinishedAt    time.Time
+	JobsFound     int
+	HealthyZones  int
+	Restarted     []orchestrator.ZoneKey
+	Stopped       []orchestrator.ZoneKey
+	MarkedOnline  []orchestrator.ZoneKey
+	MarkedOffline []orchestrator.ZoneKey
+	Errors        []string
+}
+
+// ReconcileStatus is the state of the reconciler on this replica.
+type ReconcileStatus struct {
+	Enabled    bool
+	IsLeader   bool
+	Interval   time.Duration
+	LastReport *ReconcileReport
+}
+
+type reconcilerService struct {
+	conf            *config.Config
+	worldRepository world.WorldRepository
+	registry        ServerRegistryService
+	orchestrator    orchestrator.Orchestrator
+	elector         leader_election.Elector
+
+	mu         sync.Mutex
+	started    bool
+	isLeader   bool
+	lastReport *ReconcileReport
+}
+
+func NewReconcilerService(conf *config.Config, worldRepository world.WorldRepository, registry ServerRegistryService, orchestrator orchestrator.Orchestrator, elector leader_election.Elector) ReconcilerService {
+	return &reconcilerService{
+		conf:            conf,
+		worldRepository: worldRepository,
+		registry:        registry,
+		orchestrator:    orchestrator,
+		elector:         elector,
+	}
+}
+
+func (r *reconcilerService) Reconcile() (*ReconcileReport, error) {
+	report := &ReconcileReport{StartedAt: time.Now().UTC()}
+
+	servers, err := r.orchestrator.List()
+	if err != nil {
+		return nil, err
+	}
+	// Servers that stopped sending heartbeats are not taking players even when their health check passes
+	timedOutServers, err := r.registry.ListServers(server_registry.ZoneServerFilter{
+		HeartbeatBefore: report.StartedAt.Add(-r.conf.ServerRegistry.HeartbeatTimeout),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list timed out servers: %w", err)
+	}
+	timedOut := make(map[orchestrator.ZoneKey]bool, len(timedOutServers))
+	for _, server := range timedOutServers {
+		timedOut[orchestrator.ZoneKey{WorldId: server.WorldID, ZoneId: server.ZoneID}] = true
+	}
+
+	zones, err := r.worldRepository.GetActiveOrOnlineWorldZones()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get world zones: %w", err)
+	}
+	report.JobsFound = len(servers)
+
+	running := make(map[orchestrator.ZoneKey]orchestrator.ZoneServer, len(servers))
+	for _, server := range servers {
+		running[server.ZoneKey] = server
+		if server.Healthy {
+			report.HealthyZones++
+		}
+	}
+	active := make(map[orchestrator.ZoneKey]bool, len(zones))
+
+	for _, zone := range zones {
+		key := orchestrator.ZoneKey{WorldId: zone.WorldID, ZoneId: zone.ID}
+		if zone.IsActive {
+			active[key] = true
+			if _, ok := running[key]; !ok {
+				if err := r.registry.StartNewJob(key.WorldId, key.ZoneId, false); err != nil {
+					report.Errors = append(report.Errors, fmt.Sprintf("failed to restart the server of world %s zone %d: %v", key.WorldId, key.ZoneId, err))
+				} else {
+					report.Restarted = append(report.Restarted, key)
+				}
+			}
+		}
+
+		isOnline := zone.IsActive && running[key].Healthy && !timedOut[key]
+		if isOnline == zone.IsOnline {
+			continue
+		}
+		if err := r.worldRepository.SetWorldZoneOnlineState(key.WorldId, key.ZoneId, isOnline); err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("failed to update the online state of world %s zone %d: %v", key.WorldId, key.ZoneId, err))
+		} else if isOnline {
+			report.MarkedOnline = append(report.MarkedOnline, key)
+		} else {
+			report.MarkedOffline = append(report.MarkedOffline, key)
+		}
+	}
+
+	for key, server := range running {
+		if active[key] {
+			continue
+		}
+		// Activating a zone starts its server before marking it active
+		if report.StartedAt.Sub(server.StartedAt) < r.conf.Reconciler.OrphanGracePeriod {
+			continue
+		}
+		if err := r.registry.StopJob(key.WorldId, key.ZoneId); err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("failed to stop the orphaned server %s: %v", server.Name, err))
+		} else {
+			report.Stopped = append(report.Stopped, key)
+		}
+	}
+
+	sortZoneKeys(report.Restarted)
+	sortZoneKeys(report.Stopped)
+	sortZoneKeys(report.MarkedOnline)
+	sortZoneKeys(report.MarkedOffline)
+	report.FinishedAt = time.Now().UTC()
+	return report, nil
+}
+
+func (r *reconcilerService) Start() {
+	interval := r.conf.Reconciler.Interval
+	if interval <= 0 {
+		logger.Logger.Info("Zone reconciler disabled")
+		return
+	}
+
+	r.mu.Lock()
+	r.started = true
+	r.mu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		r.reconcileIfLeader()
+		for range ticker.C {
+			r.reconcileIfLeader()
+		}
+	}()
+}
+
+// reconcileIfLeader runs a pass when this replica holds the leadership, so replicas never
+// start or stop the same servers at once.
+func (r *reconcilerService) reconcileIfLeader() {
+	isLeader := r.elector.IsLeader(context.Background())
+	r.mu.Lock()
+	r.isLeader = isLeader
+	r.mu.Unlock()
+	if !isLeader {
+		return
+	}
+
+	startedAt := time.Now().UTC()
+	report, err := r.Reconcile()
+	if err != nil {
+		logger.Logger.Errorf("Zone reconciliation failed: %v", err)
+		report = &ReconcileReport{StartedAt: startedAt, FinishedAt: time.Now().UTC(), Errors: []string{err.Error()}}
+	} else if len(report.Restarted)+len(report.Stopped)+len(report.MarkedOnline)+len(report.MarkedOffline) > 0 || len(report.Errors) > 0 {
+		logger.Logger.Infof("Zone reconciliation restarted %d servers, stopped %d servers, marked %d zones online and %d offline, %d errors",
+			len(report.Restarted), len(report.Stopped), len(report.MarkedOnline), len(report.MarkedOffline), len(report.Errors))
+	}
+
+	r.mu.Lock()
+	r.lastReport = report
+	r.mu.Unlock()
+}
+
+func (r *reconcilerService) Status() ReconcileStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return ReconcileStatus{
+		Enabled:    r.started,
+		IsLeader:   r.isLeader,
+		Interval:   r.conf.Reconciler.Interval,
+		LastReport: r.lastReport,
+	}
+}
+
+func sortZoneKeys(keys []orchestrator.ZoneKey) {
+	slices.SortFunc(keys, func(a, b orchestrator.ZoneKey) int {
+		if c := slices.Compare(a.WorldId[:], b.WorldId[:]); c != 0 {
+			return c
+		}
+		return a.ZoneId - b.ZoneId
+	})
+}
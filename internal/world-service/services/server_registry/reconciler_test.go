@@ -0,0 +1,261 @@
+package server_registry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/FeedTheRealm-org/core-service/config"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/logger"
+	"github.com/FeedTheRealm-org/core-service/internal/world-service/models"
+	"github.com/FeedTheRealm-org/core-service/internal/world-service/repositories/server_registry"
+	"github.com/FeedTheRealm-org/core-service/internal/world-service/repositories/world"
+	"github.com/FeedTheRealm-org/core-service/internal/world-service/services/orchestrator"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeOrchestrator struct {
+	orchestrator.Orchestrator
+	servers []orchestrator.ZoneServer
+	err     error
+}
+
+func (f *fakeOrchestrator) List() ([]orchestrator.ZoneServer, error) {
+	return f.servers, f.err
+}
+
+type fakeJobsRegistry struct {
+	ServerRegistryService
+	timedOut []*models.ZoneServer
+	started  []orchestrator.ZoneKey
+	stopped  []orchestrator.ZoneKey
+	startErr error
+}
+
+func (f *fakeJobsRegistry) StartNewJob(worldId uuid.UUID, zoneId int, isTest bool) error {
+	if f.startErr != nil {
+		return f.startErr
+	}
+	f.started = append(f.started, orchestrator.ZoneKey{WorldId: worldId, ZoneId: zoneId})
+	return nil
+}
+
+func (f *fakeJobsRegistry) StopJob(worldId uuid.UUID, zoneId int) error {
+	f.stopped = append(f.stopped, orchestrator.ZoneKey{WorldId: worldId, ZoneId: zoneId})
+	return nil
+}
+
+func (f *fakeJobsRegistry) ListServers(filter server_registry.ZoneServerFilter) ([]*models.ZoneServer, error) {
+	return f.timedOut, nil
+}
+
+type fakeReconcileWorldRepo struct {
+	world.WorldRepository
+	zones []*models.WorldZone
+}
+
+func (f *fakeReconcileWorldRepo) GetActiveOrOnlineWorldZones() ([]*models.WorldZone, error) {
+	return f.zones, nil
+}
+
+func (f *fakeReconcileWorldRepo) SetWorldZoneOnlineState(worldID uuid.UUID, zoneID int, isOnline bool) error {
+	for _, zone := range f.zones {
+		if zone.WorldID == worldID && zone.ID == zoneID {
+			zone.IsOnline = isOnline
+		}
+	}
+	return nil
+}
+
+type fakeElector struct {
+	leader bool
+}
+
+func (f *fakeElector) IsLeader(ctx context.Context) bool {
+	return f.leader
+}
+
+func (f *fakeElector) Release() {}
+
+func newTestReconciler(repo *fakeReconcileWorldRepo, registry *fakeJobsRegistry, servers *fakeOrchestrator, elector *fakeElector) *reconcilerService {
+	logger.InitLogger(false)
+	conf := &config.Config{
+		Reconciler:     &config.ReconcilerConfig{Interval: time.Minute, OrphanGracePeriod: time.Minute * 2},
+		ServerRegistry: &config.ServerRegistryConfig{HeartbeatTimeout: time.Minute},
+	}
+	return NewReconcilerService(conf, repo, registry, servers, elector).(*reconcilerService)
+}
+
+func TestReconcile_RestartsMissingJobsOfActiveZones(t *testing.T) {
+	worldId := uuid.New()
+	repo := &fakeReconcileWorldRepo{zones: []*models.WorldZone{
+		{WorldID: worldId, ID: 1, IsActive: true},
+		{WorldID: worldId, ID: 2, IsActive: true},
+	}}
+	registry := &fakeJobsRegistry{}
+	servers := &fakeOrchestrator{servers: []orchestrator.ZoneServer{{ZoneKey: orchestrator.ZoneKey{WorldId: worldId, ZoneId: 1}, StartedAt: time.Now()}}}
+	reconciler := newTestReconciler(repo, registry, servers, &fakeElector{})
+
+	report, err := reconciler.Reconcile()
+	require.NoError(t, err)
+
+	assert.Equal(t, []orchestrator.ZoneKey{{WorldId: worldId, ZoneId: 2}}, registry.started)
+	assert.Equal(t, []orchestrator.ZoneKey{{WorldId: worldId, ZoneId: 2}}, report.Restarted)
+	assert.Equal(t, 1, report.JobsFound)
+	assert.Empty(t, registry.stopped)
+}
+
+func TestReconcile_StopsOrphanedJobsAfterGracePeriod(t *testing.T) {
+	inactiveWorld := uuid.New()
+	deletedWorld := uuid.New()
+	activatingWorld := uuid.New()
+	repo := &fakeReconcileWorldRepo{zones: []*models.WorldZone{
+		{WorldID: inactiveWorld, ID: 1, IsActive: false, IsOnline: true},
+	}}
+	registry := &fakeJobsRegistry{}
+	servers := &fakeOrchestrator{servers: []orchestrator.ZoneServer{
+		{ZoneKey: orchestrator.ZoneKey{WorldId: inactiveWorld, ZoneId: 1}, StartedAt: time.Now().Add(-time.Hour)},
+		{ZoneKey: orchestrator.ZoneKey{WorldId: deletedWorld, ZoneId: 4}, StartedAt: time.Now().Add(-time.Hour)},
+		{ZoneKey: orchestrator.ZoneKey{WorldId: activatingWorld, ZoneId: 1}, StartedAt: time.Now()},
+	}}
+	reconciler := newTestReconciler(repo, registry, servers, &fakeElector{})
+
+	report, err := reconciler.Reconcile()
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []orchestrator.ZoneKey{{WorldId: inactiveWorld, ZoneId: 1}, {WorldId: deletedWorld, ZoneId: 4}}, registry.stopped)
+	assert.ElementsMatch(t, registry.stopped, report.Stopped)
+	assert.Equal(t, []orchestrator.ZoneKey{{WorldId: inactiveWorld, ZoneId: 1}}, report.MarkedOffline)
+	assert.False(t, repo.zones[0].IsOnline)
+	assert.Empty(t, registry.started)
+}
+
+func TestReconcile_CorrectsOnlineState(t *testing.T) {
+	worldId := uuid.New()
+	repo := &fakeReconcileWorldRepo{zones: []*models.WorldZone{
+		{WorldID: worldId, ID: 1, IsActive: true, IsOnline: false},
+		{WorldID: worldId, ID: 2, IsActive: true, IsOnline: true},
+		{WorldID: worldId, ID: 3, IsActive: true, IsOnline: true},
+	}}
+	servers := &fakeOrchestrator{servers: []orchestrator.ZoneServer{
+		{ZoneKey: orchestrator.ZoneKey{WorldId: worldId, ZoneId: 1}, Healthy: true},
+		{ZoneKey: orchestrator.ZoneKey{WorldId: worldId, ZoneId: 2}},
+		{ZoneKey: orchestrator.ZoneKey{WorldId: worldId, ZoneId: 3}, Healthy: true},
+	}}
+	reconciler := newTestReconciler(repo, &fakeJobsRegistry{}, servers, &fakeElector{})
+
+	report, err := reconciler.Reconcile()
+	require.NoError(t, err)
+
+	assert.Equal(t, []orchestrator.ZoneKey{{WorldId: worldId, ZoneId: 1}}, report.MarkedOnline)
+	assert.Equal(t, []orchestrator.ZoneKey{{WorldId: worldId, ZoneId: 2}}, report.MarkedOffline)
+	assert.True(t, repo.zones[0].IsOnline)
+	assert.False(t, repo.zones[1].IsOnline)
+	assert.True(t, repo.zones[2].IsOnline)
+}
+
+func TestReconcile_ReportIsSorted(t *testing.T) {
+	worldId := uuid.New()
+	// The repository order must not leak into the report
+	repo := &fakeReconcileWorldRepo{zones: []*models.WorldZone{
+		{WorldID: worldId, ID: 3, IsActive: true, IsOnline: true},
+		{WorldID: worldId, ID: 2, IsActive: true, IsOnline: true},
+		{WorldID: worldId, ID: 1, IsActive: true, IsOnline: true},
+	}}
+	reconciler := newTestReconciler(repo, &fakeJobsRegistry{}, &fakeOrchestrator{}, &fakeElector{})
+
+	report, err := reconciler.Reconcile()
+	require.NoError(t, err)
+
+	sorted := []orchestrator.ZoneKey{{WorldId: worldId, ZoneId: 1}, {WorldId: worldId, ZoneId: 2}, {WorldId: worldId, ZoneId: 3}}
+	assert.Equal(t, sorted, report.Restarted)
+	assert.Equal(t, sorted, report.MarkedOffline)
+}
+
+func TestReconcile_TimedOutServersAreOffline(t *testing.T) {
+	worldId := uuid.New()
+	repo := &fakeReconcileWorldRepo{zones: []*models.WorldZone{{WorldID: worldId, ID: 1, IsActive: true, IsOnline: true}}}
+	registry := &fakeJobsRegistry{timedOut: []*models.ZoneServer{{WorldID: worldId, ZoneID: 1}}}
+	servers := &fakeOrchestrator{servers: []orchestrator.ZoneServer{
+		{ZoneKey: orchestrator.ZoneKey{WorldId: worldId, ZoneId: 1}, Healthy: true},
+	}}
+	reconciler := newTestReconciler(repo, registry, servers, &fakeElector{})
+
+	report, err := reconciler.Reconcile()
+	require.NoError(t, err)
+	assert.Equal(t, []orchestrator.ZoneKey{{WorldId: worldId, ZoneId: 1}}, report.MarkedOffline)
+	assert.False(t, repo.zones[0].IsOnline)
+}
+
+func TestReconcile_RestartFailureIsReported(t *testing.T) {
+	worldId := uuid.New()
+	repo := &fakeReconcileWorldRepo{zones: []*models.WorldZone{{WorldID: worldId, ID: 1, IsActive: true}}}
+	registry := &fakeJobsRegistry{startErr: errors.New("nomad unavailable")}
+	reconciler := newTestReconciler(repo, registry, &fakeOrchestrator{}, &fakeElector{})
+
+	report, err := reconciler.Reconcile()
+	require.NoError(t, err)
+	assert.Empty(t, report.Restarted)
+	require.Len(t, report.Errors, 1)
+	assert.Contains(t, report.Errors[0], "nomad unavailable")
+}
+
+func TestReconcileIfLeader_OnlyTheLeaderReconciles(t *testing.T) {
+	worldId := uuid.New()
+	repo := &fakeReconcileWorldRepo{zones: []*models.WorldZone{{WorldID: worldId, ID: 1, IsActive: true}}}
+	registry := &fakeJobsRegistry{}
+	elector := &fakeElector{leader: false}
+	reconciler := newTestReconciler(repo, registry, &fakeOrchestrator{}, elector)
+
+	reconciler.reconcileIfLeader()
+	assert.Empty(t, registry.started)
+	status := reconciler.Status()
+	assert.False(t, status.IsLeader)
+	assert.Nil(t, status.LastReport)
+
+	elector.leader = true
+	reconciler.reconcileIfLeader()
+	assert.Len(t, registry.started, 1)
+	status = reconciler.Status()
+	assert.True(t, status.IsLeader)
+	require.NotNil(t, status.LastReport)
+	assert.Len(t, status.LastReport.Restarted, 1)
+}
+
+func TestReconcileIfLeader_ListFailureIsReported(t *testing.T) {
+	servers := &fakeOrchestrator{err: errors.New("failed to list nomad jobs")}
+	reconciler := newTestReconciler(&fakeReconcileWorldRepo{}, &fakeJobsRegistry{}, servers, &fakeElector{leader: true})
+
+	reconciler.reconcileIfLeader()
+
+	status := reconciler.Status()
+	require.NotNil(t, status.LastReport)
+	assert.Equal(t, []string{"failed to list nomad jobs"}, status.LastReport.Errors)
+}
+
+func TestReconcile_AgainstMemoryOrchestrator(t *testing.T) {
+	worldId := uuid.New()
+	repo := &fakeReconcileWorldRepo{zones: []*models.WorldZone{{WorldID: worldId, ID: 1, IsActive: true}}}
+	servers := orchestrator.NewMemoryOrchestrator()
+	logger.InitLogger(false)
+	conf := &config.Config{
+		Reconciler:     &config.ReconcilerConfig{Interval: time.Minute},
+		ServerRegistry: &config.ServerRegistryConfig{HeartbeatTimeout: time.Minute},
+		ZoneTiers:      testZoneTiers,
+	}
+	registry := NewServerRegistryService(conf, repo, newFakeServerRegistryRepo(), &fakeCurrentImage{}, servers, &fakeCredentials{})
+	reconciler := NewReconcilerService(conf, repo, registry, servers, &fakeElector{leader: true})
+
+	report, err := reconciler.Reconcile()
+	require.NoError(t, err)
+	assert.Equal(t, []orchestrator.ZoneKey{{WorldId: worldId, ZoneId: 1}}, report.Restarted)
+
+	report, err = reconciler.Reconcile()
+	require.NoError(t, err)
+	assert.Empty(t, report.Restarted)
+	assert.Equal(t, []orchestrator.ZoneKey{{WorldId: worldId, ZoneId: 1}}, report.MarkedOnline)
+	assert.True(t, repo.zones[0].IsOnline)
+}
@@ -0,0 +1,66 @@
+package server_registry
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/FeedTheRealm-org/core-service/internal/service_clients"
+	world_errors "github.com/FeedTheRealm-org/core-service/internal/world-service/errors"
+	"github.com/FeedTheRealm-org/core-service/internal/world-service/repositories/server_registry"
+	"github.com/google/uuid"
+)
+
+type serverCredentialsService struct {
+	repo server_registry.ServerRegistryRepository
+}
+
+func NewServerCredentialsService(repo server_registry.ServerRegistryRepository) ServerCredentialsService {
+	return &serverCredentialsService{repo: repo}
+}
+
+func (s *serverCredentialsService) IssueCredential(worldId uuid.UUID, zoneId int) (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("failed to generate server token: %w", err)
+	}
+	token := service_clients.SERVER_TOKEN_PREFIX + base64.RawURLEncoding.EncodeToString(secret)
+
+	if err := s.repo.SavePendingServerCredential(worldId, zoneId, hashServerToken(token)); err != nil {
+		return "", fmt.Errorf("failed to store server credential for world %s zone %d: %w", worldId, zoneId, err)
+	}
+
+	return token, nil
+}
+
+func (s *serverCredentialsService) ActivateCredential(worldId uuid.UUID, zoneId int) error {
+	return s.repo.ActivatePendingServerCredential(worldId, zoneId)
+}
+
+func (s *serverCredentialsService) DiscardCredential(worldId uuid.UUID, zoneId int) error {
+	return s.repo.DiscardPendingServerCredential(worldId, zoneId)
+}
+
+func (s *serverCredentialsService) RevokeCredential(worldId uuid.UUID, zoneId int) error {
+	return s.repo.DeleteServerCredential(worldId, zoneId)
+}
+
+func (s *serverCredentialsService) VerifyCredential(token string) (uuid.UUID, int, error) {
+	if !strings.HasPrefix(token, service_clients.SERVER_TOKEN_PREFIX) {
+		return uuid.Nil, 0, world_errors.NewServerCredentialNotFound("server credential not found")
+	}
+
+	credential, err := s.repo.GetServerCredentialByHash(hashServerToken(token))
+	if err != nil {
+		return uuid.Nil, 0, err
+	}
+	return credential.WorldID, credential.ZoneID, nil
+}
+
+func hashServerToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,125 @@
+package server_registry
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/FeedTheRealm-org/core-service/internal/service_clients"
+	world_errors "github.com/FeedTheRealm-org/core-service/internal/world-service/errors"
+	"github.com/FeedTheRealm-org/core-service/internal/world-service/models"
+	"github.com/FeedTheRealm-org/core-service/internal/world-service/repositories/server_registry"
+	"github.com/FeedTheRealm-org/core-service/internal/world-service/services/orchestrator"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeCredentialsRepo struct {
+	server_registry.ServerRegistryRepository
+	credentials map[orchestrator.ZoneKey]*models.ServerCredential
+}
+
+func newFakeCredentialsRepo() *fakeCredentialsRepo {
+	return &fakeCredentialsRepo{credentials: map[orchestrator.ZoneKey]*models.ServerCredential{}}
+}
+
+func (f *fakeCredentialsRepo) SavePendingServerCredential(worldId uuid.UUID, zoneId int, tokenHash string) error {
+	key := orchestrator.ZoneKey{WorldId: worldId, ZoneId: zoneId}
+	if _, ok := f.credentials[key]; !ok {
+		f.credentials[key] = &models.ServerCredential{WorldID: worldId, ZoneID: zoneId}
+	}
+	f.credentials[key].PendingTokenHash = &tokenHash
+	return nil
+}
+
+func (f *fakeCredentialsRepo) ActivatePendingServerCredential(worldId uuid.UUID, zoneId int) error {
+	if credential, ok := f.credentials[orchestrator.ZoneKey{WorldId: worldId, ZoneId: zoneId}]; ok && credential.PendingTokenHash != nil {
+		credential.TokenHash, credential.PendingTokenHash = credential.PendingTokenHash, nil
+	}
+	return nil
+}
+
+func (f *fakeCredentialsRepo) DiscardPendingServerCredential(worldId uuid.UUID, zoneId int) error {
+	key := orchestrator.ZoneKey{WorldId: worldId, ZoneId: zoneId}
+	if credential, ok := f.credentials[key]; ok {
+		credential.PendingTokenHash = nil
+		if credential.TokenHash == nil {
+			delete(f.credentials, key)
+		}
+	}
+	return nil
+}
+
+func (f *fakeCredentialsRepo) GetServerCredentialByHash(tokenHash string) (*models.ServerCredential, error) {
+	for _, credential := range f.credentials {
+		if (credential.TokenHash != nil && *credential.TokenHash == tokenHash) ||
+			(credential.PendingTokenHash != nil && *credential.PendingTokenHash == tokenHash) {
+			return credential, nil
+		}
+	}
+	return nil, world_errors.NewServerCredentialNotFound("server credential not found")
+}
+
+func (f *fakeCredentialsRepo) DeleteServerCredential(worldId uuid.UUID, zoneId int) error {
+	delete(f.credentials, orchestrator.ZoneKey{WorldId: worldId, ZoneId: zoneId})
+	return nil
+}
+
+func TestServerCredentialsService_IssueVerifyRevoke(t *testing.T) {
+	repo := newFakeCredentialsRepo()
+	svc := NewServerCredentialsService(repo)
+	worldId := uuid.New()
+
+	first, err := svc.IssueCredential(worldId, 2)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(first, service_clients.SERVER_TOKEN_PREFIX))
+	for _, credential := range repo.credentials {
+		assert.NotContains(t, *credential.PendingTokenHash, first, "only the hash of the token is stored")
+	}
+
+	gotWorld, gotZone, err := svc.VerifyCredential(first)
+	require.NoError(t, err, "a server works with its pending token as soon as it starts")
+	assert.Equal(t, worldId, gotWorld)
+	assert.Equal(t, 2, gotZone)
+	require.NoError(t, svc.ActivateCredential(worldId, 2))
+
+	second, err := svc.IssueCredential(worldId, 2)
+	require.NoError(t, err)
+	_, _, err = svc.VerifyCredential(first)
+	assert.NoError(t, err, "the running server keeps its token while the new one starts")
+	require.NoError(t, svc.ActivateCredential(worldId, 2))
+	_, _, err = svc.VerifyCredential(first)
+	assert.IsType(t, &world_errors.ServerCredentialNotFound{}, err, "restarting a job replaces its token")
+
+	failed, err := svc.IssueCredential(worldId, 2)
+	require.NoError(t, err)
+	require.NoError(t, svc.DiscardCredential(worldId, 2))
+	_, _, err = svc.VerifyCredential(failed)
+	assert.IsType(t, &world_errors.ServerCredentialNotFound{}, err)
+	_, _, err = svc.VerifyCredential(second)
+	assert.NoError(t, err, "a failed restart keeps the token of the running server")
+
+	require.NoError(t, svc.RevokeCredential(worldId, 2))
+	_, _, err = svc.VerifyCredential(second)
+	assert.IsType(t, &world_errors.ServerCredentialNotFound{}, err)
+
+	_, _, err = svc.VerifyCredential("not-a-server-token")
+	assert.IsType(t, &world_errors.ServerCredentialNotFound{}, err)
+}
+
+func TestServersClient_VerifyServerToken(t *testing.T) {
+	credentials := NewServerCredentialsService(newFakeCredentialsRepo())
+	client := NewServersClient(credentials)
+	worldId := uuid.New()
+
+	token, err := credentials.IssueCredential(worldId, 1)
+	require.NoError(t, err)
+
+	scope, err := client.VerifyServerToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, &service_clients.ServerScope{WorldId: worldId, ZoneId: 1}, scope)
+
+	_, err = client.VerifyServerToken(service_clients.SERVER_TOKEN_PREFIX + "unknown")
+	var invalid *service_clients.ServerCredentialInvalid
+	assert.ErrorAs(t, err, &invalid)
+}
@@ -1,157 +1,262 @@
 package server_registry
 
 import (
-	"bytes"
+	"errors"
 	"fmt"
-	"os"
-	"strconv"
-	"text/template"
 	"time"
 
 	"github.com/FeedTheRealm-org/core-service/config"
 	"github.com/FeedTheRealm-org/core-service/internal/utils/logger"
+	world_errors "github.com/FeedTheRealm-org/core-service/internal/world-service/errors"
+	"github.com/FeedTheRealm-org/core-service/internal/world-service/models"
+	"github.com/FeedTheRealm-org/core-service/internal/world-service/repositories/deployments"
+	"github.com/FeedTheRealm-org/core-service/internal/world-service/repositories/server_registry"
 	"github.com/FeedTheRealm-org/core-service/internal/world-service/repositories/world"
+	"github.com/FeedTheRealm-org/core-service/internal/world-service/services/orchestrator"
 	"github.com/google/uuid"
-	consul_api "github.com/hashicorp/consul/api"
-	nomad_api "github.com/hashicorp/nomad/api"
 )
 
 type serverRegistryService struct {
 	conf            *config.Config
 	worldRepository world.WorldRepository
-	nomadClient     *nomad_api.Client
-	consulClient    *consul_api.Client
+	repo            server_registry.ServerRegistryRepository
+	deployments     deployments.DeploymentsRepository
+	orchestrator    orchestrator.Orchestrator
+	credentials     ServerCredentialsService
 }
 
-func NewServerRegistryService(conf *config.Config, worldRepository world.WorldRepository) (ServerRegistryService, error) {
-	nomadConfig := nomad_api.DefaultConfig()
-	nomadConfig.Address = conf.NomadAddr
-	nomadConfig.SecretID = conf.NomadToken
-	nomadConfig.TLSConfig = &nomad_api.TLSConfig{
-		CACert: conf.NomadCertPath,
-	}
-	nomadClient, err := nomad_api.NewClient(nomadConfig)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create nomad client: %w", err)
-	}
-
-	consulConfig := consul_api.DefaultConfig()
-	consulConfig.Address = conf.ConsulAddr
-	consulClient, err := consul_api.NewClient(consulConfig)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create consul client: %w", err)
-	}
-
+func NewServerRegistryService(conf *config.Config, worldRepository world.WorldRepository, repo server_registry.ServerRegistryRepository, deployments deployments.DeploymentsRepository, orchestrator orchestrator.Orchestrator, credentials ServerCredentialsService) ServerRegistryService {
 	return &serverRegistryService{
 		conf:            conf,
 		worldRepository: worldRepository,
-		nomadClient:     nomadClient,
-		consulClient:    consulClient,
-	}, nil
+		repo:            repo,
+		deployments:     deployments,
+		orchestrator:    orchestrator,
+		credentials:     credentials,
+	}
 }
 
 func (s *serverRegistryService) StartNewJob(worldId uuid.UUID, zoneId int, isTest bool) error {
-	templateBytes, err := os.ReadFile(s.conf.NomadTemplatePath)
+	image, err := s.currentImage()
 	if err != nil {
-		return fmt.Errorf("failed to read nomad template file: %w", err)
+		return err
 	}
+	return s.StartJobWithImage(worldId, zoneId, isTest, image)
+}
 
-	jobTemplate, err := template.New("ftr-server-job").Parse(string(templateBytes))
+func (s *serverRegistryService) StartJobWithImage(worldId uuid.UUID, zoneId int, isTest bool, image string) error {
+	// A restarted server gets a pending token, the previous server keeps its own until the new one started
+	serverToken, err := s.credentials.IssueCredential(worldId, zoneId)
 	if err != nil {
-		return fmt.Errorf("failed to parse nomad template: %w", err)
+		return err
 	}
 
-	jobName := fmt.Sprintf("zone-server-%s-%d", worldId.String(), zoneId)
-	templateData := struct {
-		JobName     string
-		WorldID     string
-		ZoneID      int
-		IsTestWorld string
-		ImageName   string
-		DeployedAt  string
-	}{
-		JobName:     jobName,
-		WorldID:     worldId.String(),
-		ZoneID:      zoneId,
-		IsTestWorld: strconv.FormatBool(isTest),
-		ImageName:   s.conf.FTRServerImage,
-		DeployedAt:  time.Now().UTC().Format(time.RFC3339),
+	err = s.orchestrator.Start(orchestrator.ZoneServerSpec{
+		WorldId:     worldId,
+		ZoneId:      zoneId,
+		IsTest:      isTest,
+		ServerToken: serverToken,
+		Image:       image,
+		Tier:        s.zoneTier(worldId, zoneId),
+	})
+	if err != nil {
+		if discardErr := s.credentials.DiscardCredential(worldId, zoneId); discardErr != nil {
+			logger.Logger.Errorf("failed to discard pending server credential for world %s zone %d: %v", worldId, zoneId, discardErr)
+		}
+		return err
 	}
 
-	var rendered bytes.Buffer
-	if err := jobTemplate.Execute(&rendered, templateData); err != nil {
-		return fmt.Errorf("failed to render nomad template: %w", err)
+	// The new server already works with its pending token, a failed swap only keeps the old token valid longer
+	if err := s.credentials.ActivateCredential(worldId, zoneId); err != nil {
+		logger.Logger.Errorf("failed to activate server credential for world %s zone %d: %v", worldId, zoneId, err)
 	}
 
-	job, err := s.nomadClient.Jobs().ParseHCL(rendered.String(), true)
+	// The address is only known once the server sends its first heartbeat
+	err = s.repo.RegisterServer(&models.ZoneServer{
+		WorldID:      worldId,
+		ZoneID:       zoneId,
+		JobID:        orchestrator.ZoneServerName(worldId, zoneId),
+		ImageVersion: image,
+		StartedAt:    time.Now().UTC(),
+	})
 	if err != nil {
-		return fmt.Errorf("failed to parse rendered nomad job: %w", err)
+		logger.Logger.Errorf("failed to register server for world %s zone %d: %v", worldId, zoneId, err)
 	}
 
-	_, _, err = s.nomadClient.Jobs().Register(job, nil)
-	if err != nil {
-		return fmt.Errorf("failed to register nomad job %q: %w", jobName, err)
-	}
+	logger.Logger.Infof("Successfully started server for world %s zone %d on %s as test=%t", worldId, zoneId, image, isTest)
+	return nil
+}
 
-	logger.Logger.Infof("Successfully started nomad job %q for world %s zone %d as test=%s",
-		jobName, worldId, zoneId, strconv.FormatBool(isTest))
+func (s *serverRegistryService) StopJob(worldId uuid.UUID, zoneId int) error {
+	if err := s.orchestrator.Stop(worldId, zoneId); err != nil {
+		return err
+	}
+	s.revokeCredential(worldId, zoneId)
+	if err := s.repo.UnRegisterServer(worldId, zoneId); err != nil {
+		logger.Logger.Errorf("failed to unregister server for world %s zone %d: %v", worldId, zoneId, err)
+	}
 
+	logger.Logger.Infof("Stopping server for world %s zone %d", worldId, zoneId)
 	return nil
 }
 
-func (s *serverRegistryService) StopJob(worldId uuid.UUID, zoneId int) error {
-	jobName := fmt.Sprintf("zone-server-%s-%d", worldId.String(), zoneId)
+func (s *serverRegistryService) GetServerAddress(worldId uuid.UUID, zoneId int) (string, int, error) {
+	// Servers that sent a recent heartbeat already keep their zone online
+	if server, err := s.repo.GetServer(worldId, zoneId); err == nil && s.isReachable(server) {
+		return server.Address, server.Port, nil
+	}
+
+	host, port, err := s.orchestrator.Address(worldId, zoneId)
+	if err != nil {
+		var notFound *world_errors.ZoneServerNotFound
+		if !errors.As(err, &notFound) {
+			return "", 0, err
+		}
+		zone, zoneErr := s.worldRepository.GetWorldZone(worldId, zoneId)
+		if zoneErr == nil && zone.IsActive && zone.IsOnline {
+			if updateErr := s.worldRepository.SetWorldZoneOnlineState(worldId, zoneId, false); updateErr != nil {
+				return "", 0, fmt.Errorf("failed to update online state: %w", updateErr)
+			}
+		}
+		return "", 0, err
+	}
 
-	_, _, err := s.nomadClient.Jobs().Deregister(jobName, true, nil)
+	zone, err := s.worldRepository.GetWorldZone(worldId, zoneId)
 	if err != nil {
-		return fmt.Errorf("failed to deregister nomad job %q: %w", jobName, err)
+		logger.Logger.Errorf("failed to retrieve world zone from repository: %v", err)
+	} else if zone.IsActive && !zone.IsOnline {
+		if updateErr := s.worldRepository.SetWorldZoneOnlineState(worldId, zoneId, true); updateErr != nil {
+			logger.Logger.Errorf("failed to update online state: %v", updateErr)
+		}
 	}
 
-	logger.Logger.Infof("Stopping job for world %s zone %d", worldId, zoneId)
+	return host, port, nil
+}
+
+func (s *serverRegistryService) Heartbeat(worldId uuid.UUID, zoneId int, heartbeat ZoneServerHeartbeat) error {
+	now := time.Now().UTC()
+	err := s.repo.RecordHeartbeat(&models.ZoneServer{
+		WorldID:         worldId,
+		ZoneID:          zoneId,
+		JobID:           orchestrator.ZoneServerName(worldId, zoneId),
+		AllocID:         heartbeat.AllocID,
+		Address:         heartbeat.Address,
+		Port:            heartbeat.Port,
+		ImageVersion:    heartbeat.ImageVersion,
+		StartedAt:       now,
+		LastHeartbeatAt: &now,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record heartbeat for world %s zone %d: %w", worldId, zoneId, err)
+	}
 
+	zone, err := s.worldRepository.GetWorldZone(worldId, zoneId)
+	if err != nil {
+		return err
+	}
+	if zone.IsActive && !zone.IsOnline {
+		if err := s.worldRepository.SetWorldZoneOnlineState(worldId, zoneId, true); err != nil {
+			return fmt.Errorf("failed to update online state: %w", err)
+		}
+	}
 	return nil
 }
 
-func (s *serverRegistryService) GetServerAddress(worldId uuid.UUID, zoneId int) (string, int, error) {
-	filter := fmt.Sprintf(
-		`"world-%s" in Service.Tags and "zone-%d" in Service.Tags`,
-		worldId.String(),
-		zoneId,
-	)
-
-	services, _, err := s.consulClient.Health().Service("zone-server", "", true, &consul_api.QueryOptions{
-		Filter: filter,
+func (s *serverRegistryService) ListServers(filter server_registry.ZoneServerFilter) ([]*models.ZoneServer, error) {
+	return s.repo.ListServers(filter)
+}
+
+func (s *serverRegistryService) ExpireHeartbeats() ([]orchestrator.ZoneKey, error) {
+	servers, err := s.repo.ListServers(server_registry.ZoneServerFilter{
+		HeartbeatBefore: time.Now().UTC().Add(-s.conf.ServerRegistry.HeartbeatTimeout),
 	})
 	if err != nil {
-		return "", 0, fmt.Errorf("failed to query consul: %w", err)
+		return nil, fmt.Errorf("failed to list timed out servers: %w", err)
 	}
 
-	if len(services) == 0 {
-		zone, err := s.worldRepository.GetWorldZone(worldId, zoneId)
-		if err == nil && zone.IsActive {
-			updateErr := s.worldRepository.SetWorldZoneOnlineState(worldId, zoneId, false)
-			if updateErr != nil {
-				return "", 0, fmt.Errorf("failed to update online state: %w", updateErr)
+	// One server failing must not keep the ones after it online, its error is logged and the pass goes on
+	markedOffline := make([]orchestrator.ZoneKey, 0)
+	for _, server := range servers {
+		zone, err := s.worldRepository.GetWorldZone(server.WorldID, server.ZoneID)
+		var zoneNotFound *world_errors.ZoneNotFound
+		if errors.As(err, &zoneNotFound) || (err == nil && !zone.IsActive) {
+			if err := s.repo.UnRegisterServer(server.WorldID, server.ZoneID); err != nil {
+				logger.Logger.Errorf("Failed to unregister server of world %s zone %d: %v", server.WorldID, server.ZoneID, err)
 			}
+			continue
+		}
+		if err != nil {
+			logger.Logger.Errorf("Failed to get zone %d of world %s to expire its server: %v", server.ZoneID, server.WorldID, err)
+			continue
 		}
-		return "", 0, fmt.Errorf("no healthy server found for world %s zone %d", worldId, zoneId)
+		if !zone.IsOnline {
+			continue
+		}
+		if err := s.worldRepository.SetWorldZoneOnlineState(server.WorldID, server.ZoneID, false); err != nil {
+			logger.Logger.Errorf("Failed to mark world %s zone %d offline: %v", server.WorldID, server.ZoneID, err)
+			continue
+		}
+		markedOffline = append(markedOffline, orchestrator.ZoneKey{WorldId: server.WorldID, ZoneId: server.ZoneID})
+	}
+	return markedOffline, nil
+}
+
+func (s *serverRegistryService) StartHeartbeatMonitor() {
+	interval := s.conf.ServerRegistry.HeartbeatCheckInterval
+	if interval <= 0 {
+		logger.Logger.Info("Zone server heartbeat monitor disabled")
+		return
 	}
 
-	svc := services[0]
-	publicIP, ok := svc.Service.Meta["public_ip"]
-	if !ok || publicIP == "" {
-		return "", 0, fmt.Errorf("server found but missing public_ip metadata for world %s zone %d", worldId, zoneId)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			markedOffline, err := s.ExpireHeartbeats()
+			if err != nil {
+				logger.Logger.Errorf("Zone server heartbeat check failed: %v", err)
+			}
+			if len(markedOffline) > 0 {
+				logger.Logger.Infof("Marked %d zones offline after their server missed its heartbeats", len(markedOffline))
+			}
+		}
+	}()
+}
+
+// currentImage is the image of the last rollout that succeeded, the configured image before the first one.
+func (s *serverRegistryService) currentImage() (string, error) {
+	image, err := s.deployments.GetCurrentImage()
+	if err != nil {
+		return "", fmt.Errorf("failed to get the current server image: %w", err)
 	}
+	if image == "" {
+		image = s.conf.FTRServerImage
+	}
+	return image, nil
+}
 
+// zoneTier returns the resource tier selected for a zone, the default one when it cannot be read.
+func (s *serverRegistryService) zoneTier(worldId uuid.UUID, zoneId int) config.ZoneTier {
 	zone, err := s.worldRepository.GetWorldZone(worldId, zoneId)
 	if err != nil {
-		logger.Logger.Errorf("failed to retrieve world zone from repository: %v", err)
-	} else if zone.IsActive && !zone.IsOnline {
-		updateErr := s.worldRepository.SetWorldZoneOnlineState(worldId, zoneId, true)
-		if updateErr != nil {
-			logger.Logger.Errorf("failed to update online state: %v", updateErr)
-		}
+		logger.Logger.Warnf("failed to get the tier of world %s zone %d, using the default one: %v", worldId, zoneId, err)
+		return s.conf.ZoneTiers.TierOrDefault("")
 	}
+	return s.conf.ZoneTiers.TierOrDefault(zone.Tier)
+}
 
-	return publicIP, svc.Service.Port, nil
+// isReachable reports whether the server sent its address in a heartbeat that has not timed out.
+func (s *serverRegistryService) isReachable(server *models.ZoneServer) bool {
+	if server.Address == "" || server.Port == 0 || server.LastHeartbeatAt == nil {
+		return false
+	}
+	return time.Since(*server.LastHeartbeatAt) < s.conf.ServerRegistry.HeartbeatTimeout
+}
+
+// revokeCredential stops the token of a zone whose server is not running, failures are only logged
+// since the token is replaced the next time the server starts.
+func (s *serverRegistryService) revokeCredential(worldId uuid.UUID, zoneId int) {
+	if err := s.credentials.RevokeCredential(worldId, zoneId); err != nil {
+		logger.Logger.Errorf("failed to revoke server credential for world %s zone %d: %v", worldId, zoneId, err)
+	}
 }
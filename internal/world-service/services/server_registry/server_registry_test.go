@@ -0,0 +1,333 @@
+package server_registry
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/FeedTheRealm-org/core-service/config"
+	"github.com/FeedTheRealm-org/core-service/internal/utils/logger"
+	world_errors "github.com/FeedTheRealm-org/core-service/internal/world-service/errors"
+	"github.com/FeedTheRealm-org/core-service/internal/world-service/models"
+	"github.com/FeedTheRealm-org/core-service/internal/world-service/repositories/deployments"
+	"github.com/FeedTheRealm-org/core-service/internal/world-service/repositories/server_registry"
+	"github.com/FeedTheRealm-org/core-service/internal/world-service/services/orchestrator"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeCredentials struct {
+	issued    int
+	activated int
+	discarded int
+	revoked   int
+}
+
+func (f *fakeCredentials) IssueCredential(worldId uuid.UUID, zoneId int) (string, error) {
+	f.issued++
+	return "token", nil
+}
+
+func (f *fakeCredentials) ActivateCredential(worldId uuid.UUID, zoneId int) error {
+	f.activated++
+	return nil
+}
+
+func (f *fakeCredentials) DiscardCredential(worldId uuid.UUID, zoneId int) error {
+	f.discarded++
+	return nil
+}
+
+func (f *fakeCredentials) RevokeCredential(worldId uuid.UUID, zoneId int) error {
+	f.revoked++
+	return nil
+}
+
+func (f *fakeCredentials) VerifyCredential(token string) (uuid.UUID, int, error) {
+	return uuid.Nil, 0, errors.New("not implemented")
+}
+
+type failingOrchestrator struct {
+	orchestrator.Orchestrator
+}
+
+func (f *failingOrchestrator) Start(spec orchestrator.ZoneServerSpec) error {
+	return errors.New("orchestrator unavailable")
+}
+
+// fakeServerRegistryRepo keeps the registered servers in memory.
+type fakeServerRegistryRepo struct {
+	server_registry.ServerRegistryRepository
+	servers map[orchestrator.ZoneKey]*models.ZoneServer
+}
+
+func newFakeServerRegistryRepo() *fakeServerRegistryRepo {
+	return &fakeServerRegistryRepo{servers: make(map[orchestrator.ZoneKey]*models.ZoneServer)}
+}
+
+func (f *fakeServerRegistryRepo) RegisterServer(server *models.ZoneServer) error {
+	f.servers[orchestrator.ZoneKey{WorldId: server.WorldID, ZoneId: server.ZoneID}] = server
+	return nil
+}
+
+func (f *fakeServerRegistryRepo) UnRegisterServer(worldId uuid.UUID, zoneId int) error {
+	delete(f.servers, orchestrator.ZoneKey{WorldId: worldId, ZoneId: zoneId})
+	return nil
+}
+
+func (f *fakeServerRegistryRepo) GetServer(worldId uuid.UUID, zoneId int) (*models.ZoneServer, error) {
+	server, ok := f.servers[orchestrator.ZoneKey{WorldId: worldId, ZoneId: zoneId}]
+	if !ok {
+		return nil, world_errors.NewZoneServerNotFound(fmt.Sprintf("no server registered for world %s zone %d", worldId, zoneId))
+	}
+	return server, nil
+}
+
+func (f *fakeServerRegistryRepo) RecordHeartbeat(server *models.ZoneServer) error {
+	existing, ok := f.servers[orchestrator.ZoneKey{WorldId: server.WorldID, ZoneId: server.ZoneID}]
+	if !ok {
+		return f.RegisterServer(server)
+	}
+	existing.AllocID = server.AllocID
+	existing.Address = server.Address
+	existing.Port = server.Port
+	existing.ImageVersion = server.ImageVersion
+	existing.LastHeartbeatAt = server.LastHeartbeatAt
+	return nil
+}
+
+func (f *fakeServerRegistryRepo) ListServers(filter server_registry.ZoneServerFilter) ([]*models.ZoneServer, error) {
+	servers := make([]*models.ZoneServer, 0, len(f.servers))
+	for _, server := range f.servers {
+		if !filter.HeartbeatBefore.IsZero() && !server.LastSeenAt().Before(filter.HeartbeatBefore) {
+			continue
+		}
+		servers = append(servers, server)
+	}
+	// Same order as the repository
+	sort.Slice(servers, func(i, j int) bool {
+		if servers[i].WorldID != servers[j].WorldID {
+			return servers[i].WorldID.String() < servers[j].WorldID.String()
+		}
+		return servers[i].ZoneID < servers[j].ZoneID
+	})
+	return servers, nil
+}
+
+type fakeCurrentImage struct {
+	deployments.DeploymentsRepository
+	image string
+}
+
+func (f *fakeCurrentImage) GetCurrentImage() (string, error) {
+	return f.image, nil
+}
+
+func (f *fakeReconcileWorldRepo) GetWorldZone(worldID uuid.UUID, zoneID int) (*models.WorldZone, error) {
+	for _, zone := range f.zones {
+		if zone.WorldID == worldID && zone.ID == zoneID {
+			return zone, nil
+		}
+	}
+	return nil, world_errors.NewZoneNotFound("zone not found")
+}
+
+var testZoneTiers = &config.ZoneTiersConfig{
+	Default: "medium",
+	Tiers: map[string]config.ZoneTier{
+		"medium": {Name: "medium", CPU: 900, MemoryMB: 470, MemoryMaxMB: 512, Datacenter: "dc1", Slots: 1},
+		"large":  {Name: "large", CPU: 1800, MemoryMB: 1024, MemoryMaxMB: 1200, Datacenter: "dc1", Slots: 2},
+	},
+}
+
+// recordingOrchestrator is an in-memory orchestrator that keeps the specs servers were started with.
+type recordingOrchestrator struct {
+	orchestrator.Orchestrator
+	specs []orchestrator.ZoneServerSpec
+}
+
+func (o *recordingOrchestrator) Start(spec orchestrator.ZoneServerSpec) error {
+	o.specs = append(o.specs, spec)
+	return o.Orchestrator.Start(spec)
+}
+
+func newTestRegistry(repo *fakeReconcileWorldRepo, servers *fakeServerRegistryRepo, zoneServers orchestrator.Orchestrator, credentials *fakeCredentials) ServerRegistryService {
+	logger.InitLogger(false)
+	conf := &config.Config{
+		FTRServerImage: "ftr-server:1.2.0",
+		ServerRegistry: &config.ServerRegistryConfig{HeartbeatTimeout: time.Minute},
+		ZoneTiers:      testZoneTiers,
+	}
+	return NewServerRegistryService(conf, repo, servers, &fakeCurrentImage{}, zoneServers, credentials)
+}
+
+func TestServerRegistry_StartAndStop(t *testing.T) {
+	worldId := uuid.New()
+	repo := &fakeReconcileWorldRepo{zones: []*models.WorldZone{{WorldID: worldId, ID: 1, IsActive: true}}}
+	servers := newFakeServerRegistryRepo()
+	credentials := &fakeCredentials{}
+	registry := newTestRegistry(repo, servers, orchestrator.NewMemoryOrchestrator(), credentials)
+
+	require.NoError(t, registry.StartNewJob(worldId, 1, false))
+	assert.Equal(t, 1, credentials.issued)
+	assert.Equal(t, 1, credentials.activated)
+	registered, err := servers.GetServer(worldId, 1)
+	require.NoError(t, err)
+	assert.Equal(t, orchestrator.ZoneServerName(worldId, 1), registered.JobID)
+	assert.Equal(t, "ftr-server:1.2.0", registered.ImageVersion)
+
+	host, port, err := registry.GetServerAddress(worldId, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "127.0.0.1", host)
+	assert.Equal(t, 7777, port)
+	assert.True(t, repo.zones[0].IsOnline)
+
+	require.NoError(t, registry.StopJob(worldId, 1))
+	assert.Equal(t, 1, credentials.revoked)
+	assert.Empty(t, servers.servers)
+
+	_, _, err = registry.GetServerAddress(worldId, 1)
+	var notFound *world_errors.ZoneServerNotFound
+	require.ErrorAs(t, err, &notFound)
+	assert.False(t, repo.zones[0].IsOnline)
+}
+
+func TestServerRegistry_StartsTheImageOfTheLastRollout(t *testing.T) {
+	worldId := uuid.New()
+	servers := newFakeServerRegistryRepo()
+	zoneServers := orchestrator.NewMemoryOrchestrator()
+	logger.InitLogger(false)
+	conf := &config.Config{
+		FTRServerImage: "ftr-server:1.2.0",
+		ServerRegistry: &config.ServerRegistryConfig{HeartbeatTimeout: time.Minute},
+		ZoneTiers:      testZoneTiers,
+	}
+	registry := NewServerRegistryService(conf, &fakeReconcileWorldRepo{}, servers, &fakeCurrentImage{image: "ftr-server:1.3.0"}, zoneServers, &fakeCredentials{})
+
+	require.NoError(t, registry.StartNewJob(worldId, 1, false))
+
+	status, err := zoneServers.Status(worldId, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "ftr-server:1.3.0", status.Image)
+	assert.Equal(t, "ftr-server:1.3.0", servers.servers[orchestrator.ZoneKey{WorldId: worldId, ZoneId: 1}].ImageVersion)
+}
+
+func TestServerRegistry_StartsTheTierOfTheZone(t *testing.T) {
+	worldId := uuid.New()
+	repo := &fakeReconcileWorldRepo{zones: []*models.WorldZone{
+		{WorldID: worldId, ID: 1, IsActive: true, Tier: "large"},
+		{WorldID: worldId, ID: 2, IsActive: true},
+		{WorldID: worldId, ID: 3, IsActive: true, Tier: "removed"},
+	}}
+	zoneServers := &recordingOrchestrator{Orchestrator: orchestrator.NewMemoryOrchestrator()}
+	registry := newTestRegistry(repo, newFakeServerRegistryRepo(), zoneServers, &fakeCredentials{})
+
+	for zoneId := 1; zoneId <= 4; zoneId++ {
+		require.NoError(t, registry.StartNewJob(worldId, zoneId, false))
+	}
+
+	require.Len(t, zoneServers.specs, 4)
+	assert.Equal(t, "large", zoneServers.specs[0].Tier.Name)
+	assert.Equal(t, 1800, zoneServers.specs[0].Tier.CPU)
+	// Zones without a known tier run on the default one
+	for _, spec := range zoneServers.specs[1:] {
+		assert.Equal(t, "medium", spec.Tier.Name)
+	}
+}
+
+func TestServerRegistry_StartFailureKeepsTheRunningCredential(t *testing.T) {
+	credentials := &fakeCredentials{}
+	servers := newFakeServerRegistryRepo()
+	registry := newTestRegistry(&fakeReconcileWorldRepo{}, servers, &failingOrchestrator{}, credentials)
+
+	err := registry.StartNewJob(uuid.New(), 1, false)
+	assert.Error(t, err)
+	assert.Equal(t, 1, credentials.issued)
+	assert.Equal(t, 1, credentials.discarded)
+	assert.Zero(t, credentials.activated)
+	assert.Zero(t, credentials.revoked, "the previous server keeps acting for its zone")
+	assert.Empty(t, servers.servers)
+}
+
+func TestServerRegistry_HeartbeatIsTheAddressFastPath(t *testing.T) {
+	worldId := uuid.New()
+	repo := &fakeReconcileWorldRepo{zones: []*models.WorldZone{{WorldID: worldId, ID: 1, IsActive: true}}}
+	// The orchestrator knows no server, only the heartbeat can answer
+	registry := newTestRegistry(repo, newFakeServerRegistryRepo(), orchestrator.NewMemoryOrchestrator(), &fakeCredentials{})
+
+	err := registry.Heartbeat(worldId, 1, ZoneServerHeartbeat{AllocID: "alloc", Address: "10.0.0.5", Port: 24001, ImageVersion: "ftr-server:1.2.0"})
+	require.NoError(t, err)
+	assert.True(t, repo.zones[0].IsOnline)
+
+	host, port, err := registry.GetServerAddress(worldId, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "10.0.0.5", host)
+	assert.Equal(t, 24001, port)
+}
+
+func TestServerRegistry_ExpireHeartbeats(t *testing.T) {
+	worldId := uuid.New()
+	repo := &fakeReconcileWorldRepo{zones: []*models.WorldZone{
+		{WorldID: worldId, ID: 1, IsActive: true, IsOnline: true},
+		{WorldID: worldId, ID: 2, IsActive: false},
+		{WorldID: worldId, ID: 3, IsActive: true, IsOnline: true},
+	}}
+	servers := newFakeServerRegistryRepo()
+	stale := time.Now().Add(-time.Hour)
+	fresh := time.Now()
+	_ = servers.RegisterServer(&models.ZoneServer{WorldID: worldId, ZoneID: 1, Address: "10.0.0.5", Port: 1, LastHeartbeatAt: &stale})
+	_ = servers.RegisterServer(&models.ZoneServer{WorldID: worldId, ZoneID: 2, LastHeartbeatAt: &stale})
+	_ = servers.RegisterServer(&models.ZoneServer{WorldID: worldId, ZoneID: 3, LastHeartbeatAt: &fresh})
+	registry := newTestRegistry(repo, servers, orchestrator.NewMemoryOrchestrator(), &fakeCredentials{})
+
+	markedOffline, err := registry.ExpireHeartbeats()
+	require.NoError(t, err)
+	assert.Equal(t, []orchestrator.ZoneKey{{WorldId: worldId, ZoneId: 1}}, markedOffline)
+	assert.False(t, repo.zones[0].IsOnline)
+	assert.True(t, repo.zones[2].IsOnline)
+	assert.NotContains(t, servers.servers, orchestrator.ZoneKey{WorldId: worldId, ZoneId: 2})
+
+	// A timed out heartbeat is no longer used as the address of the zone
+	_, _, err = registry.GetServerAddress(worldId, 1)
+	assert.Error(t, err)
+}
+
+func TestServerRegistry_ExpireHeartbeats_DeletedZoneDoesNotStopThePass(t *testing.T) {
+	logger.InitLogger(false)
+	worldId := uuid.New()
+	// Zone 1 was deleted but its server is still registered, it is listed before zone 2
+	repo := &fakeReconcileWorldRepo{zones: []*models.WorldZone{
+		{WorldID: worldId, ID: 2, IsActive: true, IsOnline: true},
+	}}
+	servers := newFakeServerRegistryRepo()
+	stale := time.Now().Add(-time.Hour)
+	_ = servers.RegisterServer(&models.ZoneServer{WorldID: worldId, ZoneID: 1, LastHeartbeatAt: &stale})
+	_ = servers.RegisterServer(&models.ZoneServer{WorldID: worldId, ZoneID: 2, LastHeartbeatAt: &stale})
+	registry := newTestRegistry(repo, servers, orchestrator.NewMemoryOrchestrator(), &fakeCredentials{})
+
+	markedOffline, err := registry.ExpireHeartbeats()
+	require.NoError(t, err)
+	assert.Equal(t, []orchestrator.ZoneKey{{WorldId: worldId, ZoneId: 2}}, markedOffline)
+	assert.False(t, repo.zones[0].IsOnline)
+	assert.NotContains(t, servers.servers, orchestrator.ZoneKey{WorldId: worldId, ZoneId: 1})
+}
+
+func TestServerRegistry_ExpireHeartbeats_ServerThatNeverSentOne(t *testing.T) {
+	worldId := uuid.New()
+	repo := &fakeReconcileWorldRepo{zones: []*models.WorldZone{
+		{WorldID: worldId, ID: 1, IsActive: true, IsOnline: true},
+		{WorldID: worldId, ID: 2, IsActive: true, IsOnline: true},
+	}}
+	servers := newFakeServerRegistryRepo()
+	_ = servers.RegisterServer(&models.ZoneServer{WorldID: worldId, ZoneID: 1, StartedAt: time.Now().Add(-time.Hour)})
+	_ = servers.RegisterServer(&models.ZoneServer{WorldID: worldId, ZoneID: 2, StartedAt: time.Now()})
+	registry := newTestRegistry(repo, servers, orchestrator.NewMemoryOrchestrator(), &fakeCredentials{})
+
+	markedOffline, err := registry.ExpireHeartbeats()
+	require.NoError(t, err)
+	assert.Equal(t, []orchestrator.ZoneKey{{WorldId: worldId, ZoneId: 1}}, markedOffline)
+	assert.True(t, repo.zones[1].IsOnline, "a server that just started still has time to send its first heartbeat")
+}
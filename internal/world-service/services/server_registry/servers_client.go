@@ -0,0 +1,26 @@
+package server_registry
+
+import (
+	"github.com/FeedTheRealm-org/core-service/internal/service_clients"
+	world_errors "github.com/FeedTheRealm-org/core-service/internal/world-service/errors"
+)
+
+type serversClient struct {
+	credentials ServerCredentialsService
+}
+
+// NewServersClient exposes the game server credentials to the other services running in this process.
+func NewServersClient(credentials ServerCredentialsService) service_clients.ServerCredentialsClient {
+	return &serversClient{credentials: credentials}
+}
+
+func (c *serversClient) VerifyServerToken(token string) (*service_clients.ServerScope, error) {
+	worldId, zoneId, err := c.credentials.VerifyCredential(token)
+	if err != nil {
+		if _, ok := err.(*world_errors.ServerCredentialNotFound); ok {
+			return nil, service_clients.NewServerCredentialInvalid("invalid server token")
+		}
+		return nil, err
+	}
+	return &service_clients.ServerScope{WorldId: worldId, ZoneId: zoneId}, nil
+}
@@ -1,17 +1,77 @@
 package server_registry
 
 import (
+	"github.com/FeedTheRealm-org/core-service/internal/world-service/models"
+	"github.com/FeedTheRealm-org/core-service/internal/world-service/repositories/server_registry"
+	"github.com/FeedTheRealm-org/core-service/internal/world-service/services/orchestrator"
 	"github.com/google/uuid"
 )
 
-// ServerRegistryService defines the interface for nomad job api calls
+// ZoneServerHeartbeat is what a game server reports about itself on every heartbeat.
+type ZoneServerHeartbeat struct {
+	AllocID      string
+	Address      string
+	Port         int
+	ImageVersion string
+}
+
+// ServerRegistryService starts and stops the servers of world zones through the orchestrator
 type ServerRegistryService interface {
-	// Starts new or restarts nomad service for a world and zone, this will be called when a world is published
+	// Starts new or restarts the server of a world and zone, this will be called when a world is published
 	StartNewJob(worldId uuid.UUID, zoneId int, isTest bool) error
 
-	// StopJob stops the nomad job for a world and zone, this will be called when a world is unpublished or deleted
+	// StartJobWithImage starts new or restarts the server of a world and zone on the given image
+	StartJobWithImage(worldId uuid.UUID, zoneId int, isTest bool, image string) error
+
+	// StopJob stops the server of a world and zone, this will be called when a world is unpublished or deleted
 	StopJob(worldId uuid.UUID, zoneId int) error
 
 	// GetServerAddress returns the IP and port of the server running the world - zone
 	GetServerAddress(worldId uuid.UUID, zoneId int) (string, int, error)
+
+	// Heartbeat records that the server of a zone is alive and marks the zone online when it is active.
+	Heartbeat(worldId uuid.UUID, zoneId int, heartbeat ZoneServerHeartbeat) error
+
+	// ListServers returns the registered servers matching the filter.
+	ListServers(filter server_registry.ZoneServerFilter) ([]*models.ZoneServer, error)
+
+	// ExpireHeartbeats marks offline the zones whose server stopped sending heartbeats and forgets the
+	// timed out servers of inactive or deleted zones, returning the zones marked offline. Failures on a
+	// single server are logged, only failing to list the servers is returned.
+	ExpireHeartbeats() ([]orchestrator.ZoneKey, error)
+
+	// StartHeartbeatMonitor runs ExpireHeartbeats every configured interval for the lifetime of the process.
+	StartHeartbeatMonitor()
+}
+
+// ServerCredentialsService issues the tokens game servers use to act for the world zone they were launched for.
+type ServerCredentialsService interface {
+	// IssueCredential creates a pending token for a server being started, the token of the running server
+	// keeps working until ActivateCredential.
+	IssueCredential(worldId uuid.UUID, zoneId int) (string, error)
+
+	// ActivateCredential makes the pending token the only token of the zone once its server started.
+	ActivateCredential(worldId uuid.UUID, zoneId int) error
+
+	// DiscardCredential drops the pending token of a server that failed to start, the running server keeps its token.
+	DiscardCredential(worldId uuid.UUID, zoneId int) error
+
+	// RevokeCredential stops the token of the zone from working.
+	RevokeCredential(worldId uuid.UUID, zoneId int) error
+
+	// VerifyCredential returns the world and zone the token was issued for.
+	VerifyCredential(token string) (uuid.UUID, int, error)
+}
+
+// ReconcilerService keeps the zone servers and the zone states in agreement.
+type ReconcilerService interface {
+	// Reconcile restarts the servers of active zones that have none, stops the servers of inactive or deleted
+	// zones and corrects the online state of every zone.
+	Reconcile() (*ReconcileReport, error)
+
+	// Start runs Reconcile every configured interval while this replica is the leader.
+	Start()
+
+	// Status returns whether the reconciler runs on this replica and the report of its last pass.
+	Status() ReconcileStatus
 }
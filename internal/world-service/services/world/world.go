@@ -1,13 +1,10 @@
 package world
 
 import (
-	"bytes"
-	"encoding/json"
 	"errors"
-	"fmt"
-	"net/http"
 
 	"github.com/FeedTheRealm-org/core-service/config"
+	"github.com/FeedTheRealm-org/core-service/internal/service_clients"
 	"github.com/FeedTheRealm-org/core-service/internal/world-service/models"
 	"github.com/FeedTheRealm-org/core-service/internal/world-service/repositories/world"
 	"github.com/FeedTheRealm-org/core-service/internal/world-service/services/server_registry"
@@ -19,17 +16,20 @@ type worldService struct {
 	conf                  *config.Config
 	worldRepository       world.WorldRepository
 	serverRegistryService server_registry.ServerRegistryService
+	subscriptionsClient   service_clients.SubscriptionsClient
 }
 
 func NewWorldService(
 	conf *config.Config,
 	worldRepository world.WorldRepository,
 	serverRegistryService server_registry.ServerRegistryService,
+	subscriptionsClient service_clients.SubscriptionsClient,
 ) WorldService {
 	return &worldService{
 		conf:                  conf,
 		worldRepository:       worldRepository,
 		serverRegistryService: serverRegistryService,
+		subscriptionsClient:   subscriptionsClient,
 	}
 }
 
@@ -64,33 +64,7 @@ func (cs *worldService) UpdateCreateableData(worldID uuid.UUID, userId uuid.UUID
 }
 
 func (cs *worldService) UpdateUsedSlots(userId uuid.UUID, numberOfSlots int, areUsed bool) error {
-	payload := map[string]any{"slots": numberOfSlots, "are_used": areUsed}
-	jsonData, err := json.Marshal(payload)
-	if err != nil {
-		return err
-	}
-
-	url := fmt.Sprintf("http://127.0.0.1:%d/subscriptions/internal/users/%s/used-slots", cs.conf.Server.Port, userId)
-	req, err := http.NewRequest(http.MethodPut, url, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Content-Type", "application/json")
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return errors.New("failed to reach payment service to update used slots")
-	}
-	defer func() {
-		_ = resp.Body.Close()
-	}()
-
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to update used slots, payment service returned status: %d", resp.StatusCode)
-	}
-
-	return nil
+	return cs.subscriptionsClient.UpdateUsedSlots(userId, numberOfSlots, areUsed)
 }
 
 func (cs *worldService) DeleteWorld(worldID uuid.UUID, userId uuid.UUID) error {
@@ -111,7 +85,7 @@ func (cs *worldService) DeleteWorld(worldID uuid.UUID, userId uuid.UUID) error {
 		return err
 	}
 
-	activeZonesCount := 0
+	heldSlots := 0
 	for _, zone := range zones {
 		if !zone.IsActive {
 			continue
@@ -121,11 +95,11 @@ func (cs *worldService) DeleteWorld(worldID uuid.UUID, userId uuid.UUID) error {
 			return err
 		}
 
-		activeZonesCount++
+		heldSlots += zone.Slots
 	}
 
-	if cs.conf.Server.SubscriptionOn && activeZonesCount > 0 {
-		if err := cs.UpdateUsedSlots(userId, activeZonesCount, false); err != nil {
+	if cs.conf.Server.SubscriptionOn && heldSlots > 0 {
+		if err := cs.UpdateUsedSlots(userId, heldSlots, false); err != nil {
 			return err
 		}
 	}
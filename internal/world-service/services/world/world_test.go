@@ -2,14 +2,13 @@ package world
 
 import (
 	"errors"
-	"net/http"
-	"net/http/httptest"
 	"strconv"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/FeedTheRealm-org/core-service/config"
+	"github.com/FeedTheRealm-org/core-service/internal/service_clients"
 	"github.com/FeedTheRealm-org/core-service/internal/world-service/models"
 	"github.com/FeedTheRealm-org/core-service/internal/world-service/services/server_registry"
 	"github.com/google/uuid"
@@ -18,6 +17,31 @@ import (
 	"gorm.io/datatypes"
 )
 
+type fakeSubscriptionsClient struct {
+	updateErr   error
+	updateCalls []int
+}
+
+func (f *fakeSubscriptionsClient) CheckAvailability(userId uuid.UUID) (*service_clients.SlotsAvailability, error) {
+	return &service_clients.SlotsAvailability{Allowed: true, FreeSlots: 1}, nil
+}
+
+func (f *fakeSubscriptionsClient) UpdateUsedSlots(userId uuid.UUID, slots int, areUsed bool) error {
+	if f.updateErr != nil {
+		return f.updateErr
+	}
+	f.updateCalls = append(f.updateCalls, slots)
+	return nil
+}
+
+func (f *fakeSubscriptionsClient) CancelSubscription(userId uuid.UUID) error {
+	return nil
+}
+
+func (f *fakeSubscriptionsClient) UpdateCustomerEmail(userId uuid.UUID, email string) error {
+	return nil
+}
+
 type fakeWorldRepo struct {
 	storeArg              *models.WorldData
 	storeErr              error
@@ -70,6 +94,10 @@ func (f *fakeWorldRepo) UpsertWorldZone(worldID uuid.UUID, zoneID int, zoneData
 	return &models.WorldZone{ID: zoneID, WorldID: worldID}, nil
 }
 
+func (f *fakeWorldRepo) SetWorldZoneTier(worldID uuid.UUID, zoneID int, tier string, slots int) error {
+	return nil
+}
+
 func (f *fakeWorldRepo) SetWorldZoneActiveState(worldID uuid.UUID, zoneID int, isActive bool) error {
 	return nil
 }
@@ -106,6 +134,10 @@ func (f *fakeWorldRepo) GetActiveWorldZones() ([]*models.WorldZone, error) {
 	return nil, nil
 }
 
+func (f *fakeWorldRepo) GetActiveOrOnlineWorldZones() ([]*models.WorldZone, error) {
+	return nil, nil
+}
+
 func (f *fakeWorldRepo) UpdateWorldZonePlayerCount(worldID uuid.UUID, zoneID int, activePlayers int, averagePlayerTime int) error {
 	return nil
 }
@@ -135,6 +167,7 @@ func (f *fakeWorldRepo) ClearDatabase() error {
 }
 
 type fakeServerRegistry struct {
+	server_registry.ServerRegistryService
 	stopCalls []string
 	stopErr   error
 }
@@ -159,7 +192,7 @@ func TestWorldService_PublishWorld_DefaultCreateableData(t *testing.T) {
 	repo := &fakeWorldRepo{}
 	registry := &fakeServerRegistry{}
 	conf := config.CreateConfig()
-	svc := NewWorldService(conf, repo, registry)
+	svc := NewWorldService(conf, repo, registry, &fakeSubscriptionsClient{})
 
 	data := &models.WorldData{
 		ID:          uuid.New(),
@@ -184,7 +217,7 @@ func TestWorldService_DeleteWorld_NotOwner(t *testing.T) {
 	}
 	registry := &fakeServerRegistry{}
 	conf := config.CreateConfig()
-	svc := NewWorldService(conf, repo, registry)
+	svc := NewWorldService(conf, repo, registry, &fakeSubscriptionsClient{})
 
 	err := svc.DeleteWorld(repo.getWorld.ID, uuid.New())
 	assert.Error(t, err)
@@ -201,7 +234,7 @@ func TestWorldService_DeleteWorld_StopsActiveZones(t *testing.T) {
 	registry := &fakeServerRegistry{}
 	conf := config.CreateConfig()
 	conf.Server.SubscriptionOn = false
-	svc := NewWorldService(conf, repo, registry)
+	svc := NewWorldService(conf, repo, registry, &fakeSubscriptionsClient{})
 
 	err := svc.DeleteWorld(worldID, ownerID)
 	assert.NoError(t, err)
@@ -209,45 +242,40 @@ func TestWorldService_DeleteWorld_StopsActiveZones(t *testing.T) {
 	assert.Len(t, registry.stopCalls, 1)
 }
 
-func TestWorldService_UpdateUsedSlots_HTTP(t *testing.T) {
-	userID := uuid.New()
-
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != http.MethodPut {
-			w.WriteHeader(http.StatusMethodNotAllowed)
-			return
-		}
-		if !strings.Contains(r.URL.Path, "/subscriptions/internal/users/") {
-			w.WriteHeader(http.StatusNotFound)
-			return
-		}
-		w.WriteHeader(http.StatusOK)
-	}))
-	defer server.Close()
-
+func TestWorldService_DeleteWorld_ReleasesTheSlotsOfItsZones(t *testing.T) {
+	ownerID := uuid.New()
+	worldID := uuid.New()
+	repo := &fakeWorldRepo{
+		getWorld: &models.WorldData{ID: worldID, UserId: ownerID},
+		zones: []*models.WorldZone{
+			{ID: 1, WorldID: worldID, IsActive: true, Tier: "large", Slots: 2},
+			{ID: 2, WorldID: worldID, IsActive: true, Slots: 1},
+			{ID: 3, WorldID: worldID, IsActive: false},
+		},
+	}
 	conf := config.CreateConfig()
-	portStr := strings.TrimPrefix(server.URL, "http://127.0.0.1:")
-	port, _ := strconv.Atoi(portStr)
-	conf.Server.Port = port
+	conf.Server.SubscriptionOn = true
+	subscriptions := &fakeSubscriptionsClient{}
+	svc := NewWorldService(conf, repo, &fakeServerRegistry{}, subscriptions)
 
-	svc := NewWorldService(conf, &fakeWorldRepo{}, server_registry.NewStubServerRegistryService()).(*worldService)
-	assert.NoError(t, svc.UpdateUsedSlots(userID, 2, true))
+	require.NoError(t, svc.DeleteWorld(worldID, ownerID))
+	assert.Equal(t, []int{3}, subscriptions.updateCalls)
 }
 
-func TestWorldService_UpdateUsedSlots_HTTPError(t *testing.T) {
+func TestWorldService_UpdateUsedSlots(t *testing.T) {
 	userID := uuid.New()
+	subscriptions := &fakeSubscriptionsClient{}
 
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusInternalServerError)
-	}))
-	defer server.Close()
+	svc := NewWorldService(config.CreateConfig(), &fakeWorldRepo{}, &fakeServerRegistry{}, subscriptions).(*worldService)
+	assert.NoError(t, svc.UpdateUsedSlots(userID, 2, true))
+	assert.Equal(t, []int{2}, subscriptions.updateCalls)
+}
 
-	conf := config.CreateConfig()
-	portStr := strings.TrimPrefix(server.URL, "http://127.0.0.1:")
-	port, _ := strconv.Atoi(portStr)
-	conf.Server.Port = port
+func TestWorldService_UpdateUsedSlots_ClientError(t *testing.T) {
+	userID := uuid.New()
+	subscriptions := &fakeSubscriptionsClient{updateErr: errors.New("boom")}
 
-	svc := NewWorldService(conf, &fakeWorldRepo{}, server_registry.NewStubServerRegistryService()).(*worldService)
+	svc := NewWorldService(config.CreateConfig(), &fakeWorldRepo{}, &fakeServerRegistry{}, subscriptions).(*worldService)
 	err := svc.UpdateUsedSlots(userID, 1, false)
 	assert.Error(t, err)
 }
@@ -255,7 +283,7 @@ func TestWorldService_UpdateUsedSlots_HTTPError(t *testing.T) {
 func TestWorldService_UpdateWorld_RepoError(t *testing.T) {
 	repo := &fakeWorldRepo{updateWorldErr: errors.New("boom")}
 	conf := config.CreateConfig()
-	svc := NewWorldService(conf, repo, &fakeServerRegistry{})
+	svc := NewWorldService(conf, repo, &fakeServerRegistry{}, &fakeSubscriptionsClient{})
 
 	updated, err := svc.UpdateWorld(uuid.New(), uuid.New(), []byte(`{"a":1}`), "desc")
 	assert.Error(t, err)
@@ -265,7 +293,7 @@ func TestWorldService_UpdateWorld_RepoError(t *testing.T) {
 func TestWorldService_UpdateCreateableData_RepoError(t *testing.T) {
 	repo := &fakeWorldRepo{updateCreateableErr: errors.New("boom")}
 	conf := config.CreateConfig()
-	svc := NewWorldService(conf, repo, &fakeServerRegistry{})
+	svc := NewWorldService(conf, repo, &fakeServerRegistry{}, &fakeSubscriptionsClient{})
 
 	updated, err := svc.UpdateCreateableData(uuid.New(), uuid.New(), []byte(`{"a":1}`))
 	assert.Error(t, err)
@@ -279,7 +307,7 @@ func TestWorldService_DeleteWorld_ZonesError(t *testing.T) {
 		zonesErr: errors.New("boom"),
 	}
 	conf := config.CreateConfig()
-	svc := NewWorldService(conf, repo, &fakeServerRegistry{})
+	svc := NewWorldService(conf, repo, &fakeServerRegistry{}, &fakeSubscriptionsClient{})
 
 	err := svc.DeleteWorld(repo.getWorld.ID, ownerID)
 	assert.Error(t, err)
@@ -296,7 +324,7 @@ func TestWorldService_DeleteWorld_StopJobError(t *testing.T) {
 	registry := &fakeServerRegistry{stopErr: errors.New("boom")}
 	conf := config.CreateConfig()
 	conf.Server.SubscriptionOn = false
-	svc := NewWorldService(conf, repo, registry)
+	svc := NewWorldService(conf, repo, registry, &fakeSubscriptionsClient{})
 
 	err := svc.DeleteWorld(worldID, ownerID)
 	assert.Error(t, err)
@@ -307,21 +335,15 @@ func TestWorldService_DeleteWorld_UpdateUsedSlotsError(t *testing.T) {
 	worldID := uuid.New()
 	repo := &fakeWorldRepo{
 		getWorld: &models.WorldData{ID: worldID, UserId: ownerID},
-		zones:    []*models.WorldZone{{ID: 1, WorldID: worldID, IsActive: true}},
+		zones:    []*models.WorldZone{{ID: 1, WorldID: worldID, IsActive: true, Slots: 1}},
 	}
 	registry := &fakeServerRegistry{}
 	conf := config.CreateConfig()
 	conf.Server.SubscriptionOn = true
 
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusInternalServerError)
-	}))
-	defer server.Close()
-	portStr := strings.TrimPrefix(server.URL, "http://127.0.0.1:")
-	port, _ := strconv.Atoi(portStr)
-	conf.Server.Port = port
+	subscriptions := &fakeSubscriptionsClient{updateErr: errors.New("boom")}
 
-	svc := NewWorldService(conf, repo, registry)
+	svc := NewWorldService(conf, repo, registry, subscriptions)
 	err := svc.DeleteWorld(worldID, ownerID)
 	assert.Error(t, err)
 }
@@ -329,7 +351,7 @@ func TestWorldService_DeleteWorld_UpdateUsedSlotsError(t *testing.T) {
 func TestWorldService_PublishWorld_WithCreateableData(t *testing.T) {
 	repo := &fakeWorldRepo{}
 	conf := config.CreateConfig()
-	svc := NewWorldService(conf, repo, &fakeServerRegistry{})
+	svc := NewWorldService(conf, repo, &fakeServerRegistry{}, &fakeSubscriptionsClient{})
 
 	// CreateableData ya viene relleno → no debe sobreescribirse con "{}"
 	data := &models.WorldData{
@@ -346,7 +368,7 @@ func TestWorldService_PublishWorld_WithCreateableData(t *testing.T) {
 func TestWorldService_PublishWorld_RepoError(t *testing.T) {
 	repo := &fakeWorldRepo{storeErr: errors.New("db error")}
 	conf := config.CreateConfig()
-	svc := NewWorldService(conf, repo, &fakeServerRegistry{})
+	svc := NewWorldService(conf, repo, &fakeServerRegistry{}, &fakeSubscriptionsClient{})
 
 	_, err := svc.PublishWorld(&models.WorldData{})
 	assert.Error(t, err)
@@ -356,7 +378,7 @@ func TestWorldService_GetWorld_Success(t *testing.T) {
 	worldID := uuid.New()
 	repo := &fakeWorldRepo{getWorld: &models.WorldData{ID: worldID}}
 	conf := config.CreateConfig()
-	svc := NewWorldService(conf, repo, &fakeServerRegistry{})
+	svc := NewWorldService(conf, repo, &fakeServerRegistry{}, &fakeSubscriptionsClient{})
 
 	w, err := svc.GetWorld(worldID)
 	assert.NoError(t, err)
@@ -366,7 +388,7 @@ func TestWorldService_GetWorld_Success(t *testing.T) {
 func TestWorldService_GetWorld_Error(t *testing.T) {
 	repo := &fakeWorldRepo{getErr: errors.New("not found")}
 	conf := config.CreateConfig()
-	svc := NewWorldService(conf, repo, &fakeServerRegistry{})
+	svc := NewWorldService(conf, repo, &fakeServerRegistry{}, &fakeSubscriptionsClient{})
 
 	w, err := svc.GetWorld(uuid.New())
 	assert.Error(t, err)
@@ -377,7 +399,7 @@ func TestWorldService_UpdateWorld_Success(t *testing.T) {
 	expected := &models.WorldData{ID: uuid.New()}
 	repo := &fakeWorldRepo{updateWorld: expected}
 	conf := config.CreateConfig()
-	svc := NewWorldService(conf, repo, &fakeServerRegistry{})
+	svc := NewWorldService(conf, repo, &fakeServerRegistry{}, &fakeSubscriptionsClient{})
 
 	w, err := svc.UpdateWorld(uuid.New(), uuid.New(), []byte(`{}`), "desc")
 	assert.NoError(t, err)
@@ -388,7 +410,7 @@ func TestWorldService_UpdateCreateableData_Success(t *testing.T) {
 	expected := &models.WorldData{ID: uuid.New()}
 	repo := &fakeWorldRepo{updateCreateable: expected}
 	conf := config.CreateConfig()
-	svc := NewWorldService(conf, repo, &fakeServerRegistry{})
+	svc := NewWorldService(conf, repo, &fakeServerRegistry{}, &fakeSubscriptionsClient{})
 
 	w, err := svc.UpdateCreateableData(uuid.New(), uuid.New(), []byte(`{}`))
 	assert.NoError(t, err)
@@ -398,7 +420,7 @@ func TestWorldService_UpdateCreateableData_Success(t *testing.T) {
 func TestWorldService_GetWorldsList(t *testing.T) {
 	repo := &fakeWorldRepo{}
 	conf := config.CreateConfig()
-	svc := NewWorldService(conf, repo, &fakeServerRegistry{})
+	svc := NewWorldService(conf, repo, &fakeServerRegistry{}, &fakeSubscriptionsClient{})
 
 	list, err := svc.GetWorldsList(0, 10, "", uuid.New())
 	assert.NoError(t, err)
@@ -411,7 +433,7 @@ func TestWorldService_GetWorldZones(t *testing.T) {
 		zones: []*models.WorldZone{{ID: 1, WorldID: worldID}},
 	}
 	conf := config.CreateConfig()
-	svc := NewWorldService(conf, repo, &fakeServerRegistry{})
+	svc := NewWorldService(conf, repo, &fakeServerRegistry{}, &fakeSubscriptionsClient{})
 
 	zones, err := svc.GetWorldZones(worldID)
 	assert.NoError(t, err)
@@ -421,7 +443,7 @@ func TestWorldService_GetWorldZones(t *testing.T) {
 func TestWorldService_GetActiveWorldZones(t *testing.T) {
 	repo := &fakeWorldRepo{}
 	conf := config.CreateConfig()
-	svc := NewWorldService(conf, repo, &fakeServerRegistry{})
+	svc := NewWorldService(conf, repo, &fakeServerRegistry{}, &fakeSubscriptionsClient{})
 
 	zones, err := svc.GetActiveWorldZones()
 	assert.NoError(t, err)
@@ -431,7 +453,7 @@ func TestWorldService_GetActiveWorldZones(t *testing.T) {
 func TestWorldService_ClearDatabase(t *testing.T) {
 	repo := &fakeWorldRepo{}
 	conf := config.CreateConfig()
-	svc := NewWorldService(conf, repo, &fakeServerRegistry{})
+	svc := NewWorldService(conf, repo, &fakeServerRegistry{}, &fakeSubscriptionsClient{})
 
 	assert.NoError(t, svc.ClearDatabase())
 }
@@ -439,7 +461,7 @@ func TestWorldService_ClearDatabase(t *testing.T) {
 func TestWorldService_DeleteWorld_GetWorldError(t *testing.T) {
 	repo := &fakeWorldRepo{getErr: errors.New("not found")}
 	conf := config.CreateConfig()
-	svc := NewWorldService(conf, repo, &fakeServerRegistry{})
+	svc := NewWorldService(conf, repo, &fakeServerRegistry{}, &fakeSubscriptionsClient{})
 
 	err := svc.DeleteWorld(uuid.New(), uuid.New())
 	assert.Error(t, err)
@@ -454,7 +476,7 @@ func TestWorldService_DeleteWorld_DeleteRepoError(t *testing.T) {
 		deleteErr: errors.New("delete failed"),
 	}
 	conf := config.CreateConfig()
-	svc := NewWorldService(conf, repo, &fakeServerRegistry{})
+	svc := NewWorldService(conf, repo, &fakeServerRegistry{}, &fakeSubscriptionsClient{})
 
 	err := svc.DeleteWorld(worldID, ownerID)
 	assert.Error(t, err)
@@ -469,7 +491,7 @@ func TestWorldService_DeleteWorld_NoActiveZones_SubscriptionOn(t *testing.T) {
 	}
 	conf := config.CreateConfig()
 	conf.Server.SubscriptionOn = true
-	svc := NewWorldService(conf, repo, &fakeServerRegistry{})
+	svc := NewWorldService(conf, repo, &fakeServerRegistry{}, &fakeSubscriptionsClient{})
 
 	// Sin zonas activas, no llama UpdateUsedSlots aunque SubscriptionOn=true
 	err := svc.DeleteWorld(worldID, ownerID)
@@ -19,6 +19,9 @@ type ZonesService interface {
 	// DeactivateZone stops orchestration for a zone and marks it inactive.
 	DeactivateZone(worldID uuid.UUID, zoneID int) error
 
+	// SetZoneTier selects the resource tier of a zone, an active zone pays the slot difference and is restarted on it.
+	SetZoneTier(worldID uuid.UUID, zoneID int, tier string) error
+
 	// GetWorldZones returns zones for a world.
 	GetWorldZones(worldID uuid.UUID) ([]*models.WorldZone, error)
 
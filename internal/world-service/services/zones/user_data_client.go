@@ -0,0 +1,61 @@
+package zones
+
+import (
+	"encoding/json"
+
+	"github.com/FeedTheRealm-org/core-service/internal/service_clients"
+	"github.com/FeedTheRealm-org/core-service/internal/world-service/models"
+	world_repo "github.com/FeedTheRealm-org/core-service/internal/world-service/repositories/world"
+	"github.com/google/uuid"
+)
+
+type userDataClient struct {
+	zonesService    ZonesService
+	worldRepository world_repo.WorldRepository
+}
+
+// NewUserDataClient exposes the export and erasure of the worlds of a user to the authentication-service.
+func NewUserDataClient(zonesService ZonesService, worldRepository world_repo.WorldRepository) service_clients.UserDataClient {
+	return &userDataClient{zonesService: zonesService, worldRepository: worldRepository}
+}
+
+func (c *userDataClient) ExportUserData(userId uuid.UUID) (json.RawMessage, error) {
+	worldIDs, err := c.worldRepository.GetWorldIdsByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	data := &models.UserData{Worlds: make([]models.UserWorld, 0, len(worldIDs))}
+	for _, worldID := range worldIDs {
+		world, err := c.worldRepository.GetWorldData(worldID)
+		if err != nil {
+			return nil, err
+		}
+		zones, err := c.worldRepository.GetWorldZones(worldID)
+		if err != nil {
+			return nil, err
+		}
+		data.Worlds = append(data.Worlds, models.UserWorld{World: world, Zones: zones})
+	}
+
+	return json.Marshal(data)
+}
+
+// EraseUserData stops the game servers of the user before deleting their worlds, zones are deleted along with them.
+func (c *userDataClient) EraseUserData(userId uuid.UUID) error {
+	if err := c.zonesService.StopAllZonesForUser(userId); err != nil {
+		return err
+	}
+
+	worldIDs, err := c.worldRepository.GetWorldIdsByUserId(userId)
+	if err != nil {
+		return err
+	}
+	for _, worldID := range worldIDs {
+		if err := c.worldRepository.DeleteWorldData(worldID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package zones
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/FeedTheRealm-org/core-service/config"
+	"github.com/FeedTheRealm-org/core-service/internal/world-service/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+)
+
+func newUserDataTestRepo(userID uuid.UUID) (*fakeZonesRepo, uuid.UUID) {
+	repo := newFakeZonesRepo()
+	worldID := uuid.New()
+	repo.worlds[worldID] = &models.WorldData{ID: worldID, UserId: userID, Name: "my-world", Data: datatypes.JSON(`{}`)}
+	repo.userByWorld[worldID] = userID
+	repo.zones[worldID] = []*models.WorldZone{{ID: 1, WorldID: worldID, IsActive: true}}
+	repo.active[zoneKey(worldID, 1)] = true
+	return repo, worldID
+}
+
+func TestUserDataClient_ExportUserData(t *testing.T) {
+	userID := uuid.New()
+	repo, worldID := newUserDataTestRepo(userID)
+	conf := config.CreateConfig()
+	client := NewUserDataClient(NewZonesService(conf, repo, &fakeZonesRegistry{}, &fakeSubscriptionsClient{}), repo)
+
+	raw, err := client.ExportUserData(userID)
+	require.NoError(t, err)
+
+	var exported models.UserData
+	require.NoError(t, json.Unmarshal(raw, &exported))
+	require.Len(t, exported.Worlds, 1)
+	assert.Equal(t, worldID, exported.Worlds[0].World.ID)
+	assert.Len(t, exported.Worlds[0].Zones, 1)
+}
+
+func TestUserDataClient_EraseUserData(t *testing.T) {
+	userID := uuid.New()
+	repo, worldID := newUserDataTestRepo(userID)
+	registry := &fakeZonesRegistry{}
+	conf := config.CreateConfig()
+	conf.Server.SubscriptionOn = false
+	client := NewUserDataClient(NewZonesService(conf, repo, registry, &fakeSubscriptionsClient{}), repo)
+
+	require.NoError(t, client.EraseUserData(userID))
+	assert.Len(t, registry.stopCalls, 1)
+	assert.NotContains(t, repo.worlds, worldID)
+}
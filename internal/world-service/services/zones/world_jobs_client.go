@@ -0,0 +1,19 @@
+package zones
+
+import (
+	"github.com/FeedTheRealm-org/core-service/internal/service_clients"
+	"github.com/google/uuid"
+)
+
+type worldJobsClient struct {
+	zonesService ZonesService
+}
+
+// NewWorldJobsClient exposes the zones service to the other services running in this process.
+func NewWorldJobsClient(zonesService ZonesService) service_clients.WorldJobsClient {
+	return &worldJobsClient{zonesService: zonesService}
+}
+
+func (c *worldJobsClient) StopAllJobsForUser(userId uuid.UUID) error {
+	return c.zonesService.StopAllZonesForUser(userId)
+}
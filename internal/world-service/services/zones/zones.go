@@ -1,13 +1,11 @@
 package zones
 
 import (
-	"bytes"
-	"encoding/json"
-	"errors"
 	"fmt"
-	"net/http"
 
 	"github.com/FeedTheRealm-org/core-service/config"
+	"github.com/FeedTheRealm-org/core-service/internal/service_clients"
+	world_errors "github.com/FeedTheRealm-org/core-service/internal/world-service/errors"
 	"github.com/FeedTheRealm-org/core-service/internal/world-service/models"
 	world_repository "github.com/FeedTheRealm-org/core-service/internal/world-service/repositories/world"
 	"github.com/FeedTheRealm-org/core-service/internal/world-service/services/server_registry"
@@ -18,17 +16,20 @@ type zonesService struct {
 	conf                  *config.Config
 	worldRepository       world_repository.WorldRepository
 	serverRegistryService server_registry.ServerRegistryService
+	subscriptionsClient   service_clients.SubscriptionsClient
 }
 
 func NewZonesService(
 	conf *config.Config,
 	worldRepository world_repository.WorldRepository,
 	serverRegistryService server_registry.ServerRegistryService,
+	subscriptionsClient service_clients.SubscriptionsClient,
 ) ZonesService {
 	return &zonesService{
 		conf:                  conf,
 		worldRepository:       worldRepository,
 		serverRegistryService: serverRegistryService,
+		subscriptionsClient:   subscriptionsClient,
 	}
 }
 
@@ -61,8 +62,14 @@ func (zs *zonesService) ActivateZone(worldID uuid.UUID, zoneID int) error {
 		return nil
 	}
 
+	zone, err := zs.worldRepository.GetWorldZone(worldID, zoneID)
+	if err != nil {
+		return err
+	}
+	tier := zs.conf.ZoneTiers.TierOrDefault(zone.Tier)
+
 	if zs.conf.Server.SubscriptionOn {
-		if err := zs.checkAvailableZonesForActivation(worldID); err != nil {
+		if err := zs.checkAvailableZonesForActivation(worldID, tier.Slots); err != nil {
 			return err
 		}
 	}
@@ -84,7 +91,15 @@ func (zs *zonesService) ActivateZone(worldID uuid.UUID, zoneID int) error {
 			return err
 		}
 
-		if err := zs.updateUsedSlots(userID, 1, true); err != nil {
+		if err := zs.updateUsedSlots(userID, tier.Slots, true); err != nil {
+			_ = zs.serverRegistryService.StopJob(worldID, zoneID)
+			_ = zs.worldRepository.SetWorldZoneActiveState(worldID, zoneID, false)
+			return err
+		}
+
+		// The slots held are released on deactivation even if the tier prices change meanwhile
+		if err := zs.worldRepository.SetWorldZoneTier(worldID, zoneID, zone.Tier, tier.Slots); err != nil {
+			_ = zs.updateUsedSlots(userID, tier.Slots, false)
 			_ = zs.serverRegistryService.StopJob(worldID, zoneID)
 			_ = zs.worldRepository.SetWorldZoneActiveState(worldID, zoneID, false)
 			return err
@@ -118,7 +133,18 @@ func (zs *zonesService) DeactivateZone(worldID uuid.UUID, zoneID int) error {
 			return err
 		}
 
-		if err := zs.updateUsedSlots(userID, 1, false); err != nil {
+		zone, err := zs.worldRepository.GetWorldZone(worldID, zoneID)
+		if err != nil {
+			return err
+		}
+
+		if zone.Slots > 0 {
+			if err := zs.updateUsedSlots(userID, zone.Slots, false); err != nil {
+				return err
+			}
+		}
+
+		if err := zs.worldRepository.SetWorldZoneTier(worldID, zoneID, zone.Tier, 0); err != nil {
 			return err
 		}
 	}
@@ -126,6 +152,33 @@ func (zs *zonesService) DeactivateZone(worldID uuid.UUID, zoneID int) error {
 	return nil
 }
 
+func (zs *zonesService) SetZoneTier(worldID uuid.UUID, zoneID int, tierName string) error {
+	tier, ok := zs.conf.ZoneTiers.Tier(tierName)
+	if !ok || tierName == "" {
+		return world_errors.NewUnknownZoneTier(fmt.Sprintf("unknown zone tier %q", tierName))
+	}
+
+	zone, err := zs.worldRepository.GetWorldZone(worldID, zoneID)
+	if err != nil {
+		return err
+	}
+
+	if !zone.IsActive {
+		return zs.worldRepository.SetWorldZoneTier(worldID, zoneID, tierName, zone.Slots)
+	}
+
+	if zs.conf.Server.SubscriptionOn {
+		if err := zs.chargeTierChange(worldID, zoneID, zone, tierName, tier); err != nil {
+			return err
+		}
+	} else if err := zs.worldRepository.SetWorldZoneTier(worldID, zoneID, tierName, zone.Slots); err != nil {
+		return err
+	}
+
+	// The running server only gets the resources of the new tier once restarted
+	return zs.serverRegistryService.StartNewJob(worldID, zoneID, false)
+}
+
 func (zs *zonesService) GetWorldZones(worldID uuid.UUID) ([]*models.WorldZone, error) {
 	return zs.worldRepository.GetWorldZones(worldID)
 }
@@ -134,77 +187,67 @@ func (zs *zonesService) GetWorldZone(worldID uuid.UUID, zoneID int) (*models.Wor
 	return zs.worldRepository.GetWorldZone(worldID, zoneID)
 }
 
-func (zs *zonesService) checkAvailableZonesForActivation(worldID uuid.UUID) error {
+func (zs *zonesService) checkAvailableZonesForActivation(worldID uuid.UUID, slots int) error {
 	userID, err := zs.worldRepository.GetUserIdByWorldId(worldID)
 	if err != nil {
 		return err
 	}
 
-	url := fmt.Sprintf("http://127.0.0.1:%d/subscriptions/internal/users/%s/status", zs.conf.Server.Port, userID)
-	resp, err := http.Get(url)
+	availability, err := zs.subscriptionsClient.CheckAvailability(userID)
 	if err != nil {
-		return errors.New("failed to reach payment service to verify slots")
-	}
-	defer func() {
-		_ = resp.Body.Close()
-	}()
-
-	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusNotFound {
-		return errors.New("forbidden: active slots subscription required")
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to reach payment service to verify slots: status %d", resp.StatusCode)
-	}
-
-	var slotsData struct {
-		Data struct {
-			Allowed   bool `json:"allowed"`
-			FreeSlots int  `json:"free_slots"`
-		} `json:"data"`
-	}
-	if err := json.NewDecoder(resp.Body).Decode(&slotsData); err != nil {
-		return errors.New("failed to decode slots response")
+		if _, ok := err.(*service_clients.SubscriptionNotFound); ok {
+			return world_errors.NewZoneSlotsUnavailable("forbidden: active slots subscription required")
+		}
+		return err
 	}
 
-	if !slotsData.Data.Allowed {
-		return errors.New("active slots subscription required")
+	if !availability.Allowed {
+		return world_errors.NewZoneSlotsUnavailable("active slots subscription required")
 	}
 
-	if slotsData.Data.FreeSlots <= 0 {
-		return fmt.Errorf("forbidden: you have %d free zones available. Please upgrade your subscription to activate more zones", slotsData.Data.FreeSlots)
+	if availability.FreeSlots < slots {
+		return world_errors.NewZoneSlotsUnavailable(fmt.Sprintf("forbidden: you have %d free zones available and this zone tier takes %d. Please upgrade your subscription to activate more zones", availability.FreeSlots, slots))
 	}
 
 	return nil
 }
 
 func (zs *zonesService) updateUsedSlots(userID uuid.UUID, numberOfSlots int, areUsed bool) error {
-	payload := map[string]interface{}{"slots": numberOfSlots, "are_used": areUsed}
-	jsonData, err := json.Marshal(payload)
+	return zs.subscriptionsClient.UpdateUsedSlots(userID, numberOfSlots, areUsed)
+}
+
+// chargeTierChange moves an active zone to a tier, taking or releasing the difference in slots between both tiers.
+func (zs *zonesService) chargeTierChange(worldID uuid.UUID, zoneID int, zone *models.WorldZone, tierName string, tier config.ZoneTier) error {
+	userID, err := zs.worldRepository.GetUserIdByWorldId(worldID)
 	if err != nil {
 		return err
 	}
 
-	url := fmt.Sprintf("http://127.0.0.1:%d/subscriptions/internal/users/%s/used-slots", zs.conf.Server.Port, userID)
-	req, err := http.NewRequest(http.MethodPut, url, bytes.NewBuffer(jsonData))
-	if err != nil {
+	difference := tier.Slots - zone.Slots
+	if difference > 0 {
+		if err := zs.checkAvailableZonesForActivation(worldID, difference); err != nil {
+			return err
+		}
+	}
+	if err := zs.adjustUsedSlots(userID, difference); err != nil {
 		return err
 	}
-	req.Header.Set("Content-Type", "application/json")
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return errors.New("failed to reach payment service to update used slots")
+	if err := zs.worldRepository.SetWorldZoneTier(worldID, zoneID, tierName, tier.Slots); err != nil {
+		_ = zs.adjustUsedSlots(userID, -difference)
+		return err
 	}
-	defer func() {
-		_ = resp.Body.Close()
-	}()
+	return nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to update used slots, payment service returned status: %d", resp.StatusCode)
+// adjustUsedSlots takes the slots of a positive difference and releases those of a negative one.
+func (zs *zonesService) adjustUsedSlots(userID uuid.UUID, difference int) error {
+	switch {
+	case difference > 0:
+		return zs.updateUsedSlots(userID, difference, true)
+	case difference < 0:
+		return zs.updateUsedSlots(userID, -difference, false)
 	}
-
 	return nil
 }
 
@@ -2,14 +2,14 @@ package zones
 
 import (
 	"errors"
-	"net/http"
-	"net/http/httptest"
 	"strconv"
-	"strings"
 	"testing"
 
 	"github.com/FeedTheRealm-org/core-service/config"
+	"github.com/FeedTheRealm-org/core-service/internal/service_clients"
+	world_errors "github.com/FeedTheRealm-org/core-service/internal/world-service/errors"
 	"github.com/FeedTheRealm-org/core-service/internal/world-service/models"
+	"github.com/FeedTheRealm-org/core-service/internal/world-service/services/server_registry"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -91,6 +91,17 @@ func (f *fakeZonesRepo) SetWorldZoneActiveState(worldID uuid.UUID, zoneID int, i
 	return nil
 }
 
+func (f *fakeZonesRepo) SetWorldZoneTier(worldID uuid.UUID, zoneID int, tier string, slots int) error {
+	for _, zone := range f.zones[worldID] {
+		if zone.ID == zoneID {
+			zone.Tier = tier
+			zone.Slots = slots
+			return nil
+		}
+	}
+	return errors.New("not found")
+}
+
 func (f *fakeZonesRepo) SetWorldZoneOnlineState(worldID uuid.UUID, zoneID int, isOnline bool) error {
 	if f.setOnlineErr != nil {
 		return f.setOnlineErr
@@ -113,6 +124,9 @@ func (f *fakeZonesRepo) GetWorldZoneActiveState(worldID uuid.UUID, zoneID int) (
 }
 
 func (f *fakeZonesRepo) DeleteWorldData(worldID uuid.UUID) error {
+	delete(f.worlds, worldID)
+	delete(f.zones, worldID)
+	delete(f.userByWorld, worldID)
 	return nil
 }
 
@@ -133,13 +147,17 @@ func (f *fakeZonesRepo) GetWorldZone(worldID uuid.UUID, zoneID int) (*models.Wor
 			return zone, nil
 		}
 	}
-	return nil, errors.New("not found")
+	return nil, world_errors.NewZoneNotFound("not found")
 }
 
 func (f *fakeZonesRepo) GetActiveWorldZones() ([]*models.WorldZone, error) {
 	return nil, nil
 }
 
+func (f *fakeZonesRepo) GetActiveOrOnlineWorldZones() ([]*models.WorldZone, error) {
+	return nil, nil
+}
+
 func (f *fakeZonesRepo) UpdateWorldZonePlayerCount(worldID uuid.UUID, zoneID int, activePlayers int, averagePlayerTime int) error {
 	if f.updateCountErr != nil {
 		return f.updateCountErr
@@ -226,6 +244,7 @@ func (f *fakeZonesRepo) ClearDatabase() error {
 }
 
 type fakeZonesRegistry struct {
+	server_registry.ServerRegistryService
 	startCalls []string
 	stopCalls  []string
 	startErr   error
@@ -252,6 +271,36 @@ func (f *fakeZonesRegistry) GetServerAddress(worldId uuid.UUID, zoneId int) (str
 	return "", 0, nil
 }
 
+type fakeSubscriptionsClient struct {
+	availability *service_clients.SlotsAvailability
+	checkErr     error
+	updateErr    error
+	updateCalls  []string
+}
+
+func (f *fakeSubscriptionsClient) CheckAvailability(userId uuid.UUID) (*service_clients.SlotsAvailability, error) {
+	if f.checkErr != nil {
+		return nil, f.checkErr
+	}
+	return f.availability, nil
+}
+
+func (f *fakeSubscriptionsClient) UpdateUsedSlots(userId uuid.UUID, slots int, areUsed bool) error {
+	if f.updateErr != nil {
+		return f.updateErr
+	}
+	f.updateCalls = append(f.updateCalls, userId.String()+":"+strconv.Itoa(slots)+":"+strconv.FormatBool(areUsed))
+	return nil
+}
+
+func (f *fakeSubscriptionsClient) CancelSubscription(userId uuid.UUID) error {
+	return nil
+}
+
+func (f *fakeSubscriptionsClient) UpdateCustomerEmail(userId uuid.UUID, email string) error {
+	return nil
+}
+
 // ─── ActivateZone ────────────────────────────────────────────────────────────
 
 func TestZonesService_ActivateZone_AlreadyActive(t *testing.T) {
@@ -262,7 +311,7 @@ func TestZonesService_ActivateZone_AlreadyActive(t *testing.T) {
 	registry := &fakeZonesRegistry{}
 	conf := config.CreateConfig()
 	conf.Server.SubscriptionOn = false
-	svc := NewZonesService(conf, repo, registry)
+	svc := NewZonesService(conf, repo, registry, &fakeSubscriptionsClient{})
 
 	err := svc.ActivateZone(worldID, 1)
 	assert.NoError(t, err)
@@ -277,7 +326,7 @@ func TestZonesService_ActivateZone_GetActiveStateError(t *testing.T) {
 	registry := &fakeZonesRegistry{}
 	conf := config.CreateConfig()
 	conf.Server.SubscriptionOn = false
-	svc := NewZonesService(conf, repo, registry)
+	svc := NewZonesService(conf, repo, registry, &fakeSubscriptionsClient{})
 
 	err := svc.ActivateZone(worldID, 1)
 	assert.Error(t, err)
@@ -287,12 +336,13 @@ func TestZonesService_ActivateZone_GetActiveStateError(t *testing.T) {
 func TestZonesService_ActivateZone_StartsAndSetsActive(t *testing.T) {
 	repo := newFakeZonesRepo()
 	worldID := uuid.New()
+	repo.zones[worldID] = []*models.WorldZone{{ID: 1, WorldID: worldID}}
 	repo.active[zoneKey(worldID, 1)] = false
 
 	registry := &fakeZonesRegistry{}
 	conf := config.CreateConfig()
 	conf.Server.SubscriptionOn = false
-	svc := NewZonesService(conf, repo, registry)
+	svc := NewZonesService(conf, repo, registry, &fakeSubscriptionsClient{})
 
 	err := svc.ActivateZone(worldID, 1)
 	assert.NoError(t, err)
@@ -307,7 +357,7 @@ func TestZonesService_ActivateZone_StartJobError(t *testing.T) {
 	registry := &fakeZonesRegistry{startErr: errors.New("start failed")}
 	conf := config.CreateConfig()
 	conf.Server.SubscriptionOn = false
-	svc := NewZonesService(conf, repo, registry)
+	svc := NewZonesService(conf, repo, registry, &fakeSubscriptionsClient{})
 
 	err := svc.ActivateZone(worldID, 1)
 	assert.Error(t, err)
@@ -317,12 +367,13 @@ func TestZonesService_ActivateZone_StartJobError(t *testing.T) {
 func TestZonesService_ActivateZone_SetActiveFails(t *testing.T) {
 	repo := newFakeZonesRepo()
 	worldID := uuid.New()
+	repo.zones[worldID] = []*models.WorldZone{{ID: 1, WorldID: worldID}}
 	repo.setActiveErr = errors.New("fail")
 
 	registry := &fakeZonesRegistry{}
 	conf := config.CreateConfig()
 	conf.Server.SubscriptionOn = false
-	svc := NewZonesService(conf, repo, registry)
+	svc := NewZonesService(conf, repo, registry, &fakeSubscriptionsClient{})
 
 	err := svc.ActivateZone(worldID, 1)
 	assert.Error(t, err)
@@ -338,16 +389,9 @@ func TestZonesService_ActivateZone_SubscriptionUserIdError(t *testing.T) {
 	conf := config.CreateConfig()
 	conf.Server.SubscriptionOn = true
 
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		_, _ = w.Write([]byte(`{"data":{"allowed":true,"free_slots":1}}`))
-	}))
-	defer server.Close()
-	portStr := strings.TrimPrefix(server.URL, "http://127.0.0.1:")
-	port, _ := strconv.Atoi(portStr)
-	conf.Server.Port = port
+	subscriptions := &fakeSubscriptionsClient{availability: &service_clients.SlotsAvailability{Allowed: true, FreeSlots: 1}}
 
-	svc := NewZonesService(conf, repo, registry)
+	svc := NewZonesService(conf, repo, registry, subscriptions)
 	err := svc.ActivateZone(worldID, 1)
 	assert.Error(t, err)
 	assert.Len(t, registry.stopCalls, 0)
@@ -357,6 +401,7 @@ func TestZonesService_ActivateZone_SubscriptionUserIdError(t *testing.T) {
 func TestZonesService_ActivateZone_SubscriptionUpdateUsedSlotsError(t *testing.T) {
 	repo := newFakeZonesRepo()
 	worldID := uuid.New()
+	repo.zones[worldID] = []*models.WorldZone{{ID: 1, WorldID: worldID}}
 	userID := uuid.New()
 	repo.userByWorld[worldID] = userID
 	repo.active[zoneKey(worldID, 1)] = false
@@ -365,32 +410,84 @@ func TestZonesService_ActivateZone_SubscriptionUpdateUsedSlotsError(t *testing.T
 	conf := config.CreateConfig()
 	conf.Server.SubscriptionOn = true
 
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if strings.Contains(r.URL.Path, "/status") {
-			w.Header().Set("Content-Type", "application/json")
-			_, _ = w.Write([]byte(`{"data":{"allowed":true,"free_slots":1}}`))
-			return
-		}
-		if strings.Contains(r.URL.Path, "/used-slots") {
-			w.WriteHeader(http.StatusInternalServerError)
-			return
-		}
-		w.WriteHeader(http.StatusNotFound)
-	}))
-	defer server.Close()
-	portStr := strings.TrimPrefix(server.URL, "http://127.0.0.1:")
-	port, _ := strconv.Atoi(portStr)
-	conf.Server.Port = port
-
-	svc := NewZonesService(conf, repo, registry)
+	subscriptions := &fakeSubscriptionsClient{
+		availability: &service_clients.SlotsAvailability{Allowed: true, FreeSlots: 1},
+		updateErr:    errors.New("failed to update used slots"),
+	}
+
+	svc := NewZonesService(conf, repo, registry, subscriptions)
 	err := svc.ActivateZone(worldID, 1)
 	assert.Error(t, err)
 	assert.Len(t, registry.stopCalls, 1)
 	assert.False(t, repo.active[zoneKey(worldID, 1)])
 }
 
+func TestZonesService_ActivateZone_ConsumesSubscriptionSlot(t *testing.T) {
+	repo := newFakeZonesRepo()
+	worldID := uuid.New()
+	repo.zones[worldID] = []*models.WorldZone{{ID: 1, WorldID: worldID}}
+	userID := uuid.New()
+	repo.userByWorld[worldID] = userID
+
+	registry := &fakeZonesRegistry{}
+	conf := config.CreateConfig()
+	conf.Server.SubscriptionOn = true
+
+	subscriptions := &fakeSubscriptionsClient{availability: &service_clients.SlotsAvailability{Allowed: true, FreeSlots: 1}}
+
+	svc := NewZonesService(conf, repo, registry, subscriptions)
+	err := svc.ActivateZone(worldID, 1)
+	assert.NoError(t, err)
+	assert.Len(t, registry.startCalls, 1)
+	assert.Equal(t, []string{userID.String() + ":1:true"}, subscriptions.updateCalls)
+}
+
+func TestZonesService_ActivateZone_TakesTheSlotsOfItsTier(t *testing.T) {
+	repo := newFakeZonesRepo()
+	worldID := uuid.New()
+	userID := uuid.New()
+	repo.userByWorld[worldID] = userID
+	repo.zones[worldID] = []*models.WorldZone{{ID: 1, WorldID: worldID, Tier: "large"}}
+
+	registry := &fakeZonesRegistry{}
+	conf := config.CreateConfig()
+	conf.Server.SubscriptionOn = true
+
+	subscriptions := &fakeSubscriptionsClient{availability: &service_clients.SlotsAvailability{Allowed: true, FreeSlots: 1}}
+	svc := NewZonesService(conf, repo, registry, subscriptions)
+
+	err := svc.ActivateZone(worldID, 1)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "free zones available")
+	assert.Empty(t, registry.startCalls)
+
+	subscriptions.availability.FreeSlots = 2
+	require.NoError(t, svc.ActivateZone(worldID, 1))
+	assert.Equal(t, []string{userID.String() + ":2:true"}, subscriptions.updateCalls)
+	assert.Equal(t, 2, repo.zones[worldID][0].Slots)
+}
+
 // ─── DeactivateZone ──────────────────────────────────────────────────────────
 
+func TestZonesService_DeactivateZone_ReleasesTheSlotsItHolds(t *testing.T) {
+	repo := newFakeZonesRepo()
+	worldID := uuid.New()
+	userID := uuid.New()
+	repo.userByWorld[worldID] = userID
+	repo.zones[worldID] = []*models.WorldZone{{ID: 1, WorldID: worldID, IsActive: true, Tier: "large", Slots: 2}}
+	repo.active[zoneKey(worldID, 1)] = true
+
+	conf := config.CreateConfig()
+	conf.Server.SubscriptionOn = true
+	subscriptions := &fakeSubscriptionsClient{}
+	svc := NewZonesService(conf, repo, &fakeZonesRegistry{}, subscriptions)
+
+	require.NoError(t, svc.DeactivateZone(worldID, 1))
+	assert.Equal(t, []string{userID.String() + ":2:false"}, subscriptions.updateCalls)
+	assert.Equal(t, 0, repo.zones[worldID][0].Slots)
+	assert.Equal(t, "large", repo.zones[worldID][0].Tier)
+}
+
 func TestZonesService_DeactivateZone_HappyPath(t *testing.T) {
 	repo := newFakeZonesRepo()
 	worldID := uuid.New()
@@ -399,7 +496,7 @@ func TestZonesService_DeactivateZone_HappyPath(t *testing.T) {
 	registry := &fakeZonesRegistry{}
 	conf := config.CreateConfig()
 	conf.Server.SubscriptionOn = false
-	svc := NewZonesService(conf, repo, registry)
+	svc := NewZonesService(conf, repo, registry, &fakeSubscriptionsClient{})
 
 	err := svc.DeactivateZone(worldID, 1)
 	assert.NoError(t, err)
@@ -414,7 +511,7 @@ func TestZonesService_DeactivateZone_NotActive(t *testing.T) {
 	registry := &fakeZonesRegistry{}
 	conf := config.CreateConfig()
 	conf.Server.SubscriptionOn = false
-	svc := NewZonesService(conf, repo, registry)
+	svc := NewZonesService(conf, repo, registry, &fakeSubscriptionsClient{})
 
 	err := svc.DeactivateZone(worldID, 1)
 	assert.NoError(t, err)
@@ -429,7 +526,7 @@ func TestZonesService_DeactivateZone_GetActiveStateError(t *testing.T) {
 	registry := &fakeZonesRegistry{}
 	conf := config.CreateConfig()
 	conf.Server.SubscriptionOn = false
-	svc := NewZonesService(conf, repo, registry)
+	svc := NewZonesService(conf, repo, registry, &fakeSubscriptionsClient{})
 
 	err := svc.DeactivateZone(worldID, 1)
 	assert.Error(t, err)
@@ -443,7 +540,7 @@ func TestZonesService_DeactivateZone_StopJobError(t *testing.T) {
 	registry := &fakeZonesRegistry{stopErr: errors.New("stop failed")}
 	conf := config.CreateConfig()
 	conf.Server.SubscriptionOn = false
-	svc := NewZonesService(conf, repo, registry)
+	svc := NewZonesService(conf, repo, registry, &fakeSubscriptionsClient{})
 
 	err := svc.DeactivateZone(worldID, 1)
 	assert.Error(t, err)
@@ -458,7 +555,7 @@ func TestZonesService_DeactivateZone_SetActiveFails(t *testing.T) {
 	registry := &fakeZonesRegistry{}
 	conf := config.CreateConfig()
 	conf.Server.SubscriptionOn = false
-	svc := NewZonesService(conf, repo, registry)
+	svc := NewZonesService(conf, repo, registry, &fakeSubscriptionsClient{})
 
 	err := svc.DeactivateZone(worldID, 1)
 	assert.Error(t, err)
@@ -474,7 +571,7 @@ func TestZonesService_DeactivateZone_SubscriptionUserIdError(t *testing.T) {
 	conf := config.CreateConfig()
 	conf.Server.SubscriptionOn = true
 
-	svc := NewZonesService(conf, repo, registry)
+	svc := NewZonesService(conf, repo, registry, &fakeSubscriptionsClient{})
 	err := svc.DeactivateZone(worldID, 1)
 	assert.Error(t, err)
 }
@@ -484,25 +581,85 @@ func TestZonesService_DeactivateZone_SubscriptionUpdateUsedSlotsError(t *testing
 	worldID := uuid.New()
 	userID := uuid.New()
 	repo.userByWorld[worldID] = userID
+	repo.zones[worldID] = []*models.WorldZone{{ID: 1, WorldID: worldID, IsActive: true, Slots: 1}}
 	repo.active[zoneKey(worldID, 1)] = true
 
 	registry := &fakeZonesRegistry{}
 	conf := config.CreateConfig()
 	conf.Server.SubscriptionOn = true
 
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusInternalServerError)
-	}))
-	defer server.Close()
-	portStr := strings.TrimPrefix(server.URL, "http://127.0.0.1:")
-	port, _ := strconv.Atoi(portStr)
-	conf.Server.Port = port
+	subscriptions := &fakeSubscriptionsClient{updateErr: errors.New("failed to update used slots")}
 
-	svc := NewZonesService(conf, repo, registry)
+	svc := NewZonesService(conf, repo, registry, subscriptions)
 	err := svc.DeactivateZone(worldID, 1)
 	assert.Error(t, err)
 }
 
+// ─── SetZoneTier ─────────────────────────────────────────────────────────────
+
+func TestZonesService_SetZoneTier_UnknownTier(t *testing.T) {
+	repo := newFakeZonesRepo()
+	worldID := uuid.New()
+	repo.zones[worldID] = []*models.WorldZone{{ID: 1, WorldID: worldID}}
+
+	svc := NewZonesService(config.CreateConfig(), repo, &fakeZonesRegistry{}, &fakeSubscriptionsClient{})
+	err := svc.SetZoneTier(worldID, 1, "huge")
+	assert.IsType(t, &world_errors.UnknownZoneTier{}, err)
+	assert.Empty(t, repo.zones[worldID][0].Tier)
+}
+
+func TestZonesService_SetZoneTier_UnknownZone(t *testing.T) {
+	svc := NewZonesService(config.CreateConfig(), newFakeZonesRepo(), &fakeZonesRegistry{}, &fakeSubscriptionsClient{})
+	err := svc.SetZoneTier(uuid.New(), 1, "large")
+	assert.IsType(t, &world_errors.ZoneNotFound{}, err)
+}
+
+func TestZonesService_SetZoneTier_InactiveZone(t *testing.T) {
+	repo := newFakeZonesRepo()
+	worldID := uuid.New()
+	repo.zones[worldID] = []*models.WorldZone{{ID: 1, WorldID: worldID}}
+
+	registry := &fakeZonesRegistry{}
+	conf := config.CreateConfig()
+	conf.Server.SubscriptionOn = true
+	subscriptions := &fakeSubscriptionsClient{}
+	svc := NewZonesService(conf, repo, registry, subscriptions)
+
+	require.NoError(t, svc.SetZoneTier(worldID, 1, "large"))
+	assert.Equal(t, "large", repo.zones[worldID][0].Tier)
+	assert.Empty(t, subscriptions.updateCalls, "slots are only taken on activation")
+	assert.Empty(t, registry.startCalls)
+}
+
+func TestZonesService_SetZoneTier_ActiveZonePaysTheDifference(t *testing.T) {
+	repo := newFakeZonesRepo()
+	worldID := uuid.New()
+	userID := uuid.New()
+	repo.userByWorld[worldID] = userID
+	repo.zones[worldID] = []*models.WorldZone{{ID: 1, WorldID: worldID, IsActive: true, Slots: 1}}
+
+	registry := &fakeZonesRegistry{}
+	conf := config.CreateConfig()
+	conf.Server.SubscriptionOn = true
+	subscriptions := &fakeSubscriptionsClient{availability: &service_clients.SlotsAvailability{Allowed: true, FreeSlots: 0}}
+	svc := NewZonesService(conf, repo, registry, subscriptions)
+
+	err := svc.SetZoneTier(worldID, 1, "large")
+	assert.IsType(t, &world_errors.ZoneSlotsUnavailable{}, err)
+	assert.Empty(t, repo.zones[worldID][0].Tier)
+
+	subscriptions.availability.FreeSlots = 1
+	require.NoError(t, svc.SetZoneTier(worldID, 1, "large"))
+	assert.Equal(t, "large", repo.zones[worldID][0].Tier)
+	assert.Equal(t, 2, repo.zones[worldID][0].Slots)
+	assert.Len(t, registry.startCalls, 1, "the server is restarted on the new tier")
+
+	require.NoError(t, svc.SetZoneTier(worldID, 1, "small"))
+	assert.Equal(t, 1, repo.zones[worldID][0].Slots)
+	assert.Equal(t, []string{userID.String() + ":1:true", userID.String() + ":1:false"}, subscriptions.updateCalls)
+	assert.Len(t, registry.startCalls, 2)
+}
+
 // ─── checkAvailableZonesForActivation ────────────────────────────────────────
 
 func TestZonesService_CheckAvailableZones_Success(t *testing.T) {
@@ -511,29 +668,15 @@ func TestZonesService_CheckAvailableZones_Success(t *testing.T) {
 	repo := newFakeZonesRepo()
 	repo.userByWorld[worldID] = userID
 
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if strings.Contains(r.URL.Path, "/status") {
-			w.Header().Set("Content-Type", "application/json")
-			_, _ = w.Write([]byte(`{"data":{"allowed":true,"free_slots":1}}`))
-			return
-		}
-		if r.Method == http.MethodPut {
-			w.WriteHeader(http.StatusOK)
-			return
-		}
-		w.WriteHeader(http.StatusNotFound)
-	}))
-	defer server.Close()
+	subscriptions := &fakeSubscriptionsClient{availability: &service_clients.SlotsAvailability{Allowed: true, FreeSlots: 1}}
 
 	conf := config.CreateConfig()
-	portStr := strings.TrimPrefix(server.URL, "http://127.0.0.1:")
-	port, _ := strconv.Atoi(portStr)
-	conf.Server.Port = port
 	conf.Server.SubscriptionOn = true
 
-	svc := NewZonesService(conf, repo, &fakeZonesRegistry{}).(*zonesService)
-	assert.NoError(t, svc.checkAvailableZonesForActivation(worldID))
+	svc := NewZonesService(conf, repo, &fakeZonesRegistry{}, subscriptions).(*zonesService)
+	assert.NoError(t, svc.checkAvailableZonesForActivation(worldID, 1))
 	assert.NoError(t, svc.updateUsedSlots(userID, 1, true))
+	assert.Len(t, subscriptions.updateCalls, 1)
 }
 
 func TestZonesService_CheckAvailableZones_Denied(t *testing.T) {
@@ -542,20 +685,13 @@ func TestZonesService_CheckAvailableZones_Denied(t *testing.T) {
 	repo := newFakeZonesRepo()
 	repo.userByWorld[worldID] = userID
 
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		_, _ = w.Write([]byte(`{"data":{"allowed":false,"free_slots":0}}`))
-	}))
-	defer server.Close()
+	subscriptions := &fakeSubscriptionsClient{availability: &service_clients.SlotsAvailability{Allowed: false, FreeSlots: 0}}
 
 	conf := config.CreateConfig()
-	portStr := strings.TrimPrefix(server.URL, "http://127.0.0.1:")
-	port, _ := strconv.Atoi(portStr)
-	conf.Server.Port = port
 	conf.Server.SubscriptionOn = true
 
-	svc := NewZonesService(conf, repo, &fakeZonesRegistry{}).(*zonesService)
-	err := svc.checkAvailableZonesForActivation(worldID)
+	svc := NewZonesService(conf, repo, &fakeZonesRegistry{}, subscriptions).(*zonesService)
+	err := svc.checkAvailableZonesForActivation(worldID, 1)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "subscription required")
 }
@@ -566,113 +702,58 @@ func TestZonesService_CheckAvailableZones_NoFreeSlots(t *testing.T) {
 	repo := newFakeZonesRepo()
 	repo.userByWorld[worldID] = userID
 
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		_, _ = w.Write([]byte(`{"data":{"allowed":true,"free_slots":0}}`))
-	}))
-	defer server.Close()
-
-	conf := config.CreateConfig()
-	portStr := strings.TrimPrefix(server.URL, "http://127.0.0.1:")
-	port, _ := strconv.Atoi(portStr)
-	conf.Server.Port = port
+	subscriptions := &fakeSubscriptionsClient{availability: &service_clients.SlotsAvailability{Allowed: true, FreeSlots: 0}}
 
-	svc := NewZonesService(conf, repo, &fakeZonesRegistry{}).(*zonesService)
-	err := svc.checkAvailableZonesForActivation(worldID)
+	svc := NewZonesService(config.CreateConfig(), repo, &fakeZonesRegistry{}, subscriptions).(*zonesService)
+	err := svc.checkAvailableZonesForActivation(worldID, 1)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "free zones available")
 }
 
-func TestZonesService_CheckAvailableZones_Unauthorized(t *testing.T) {
+func TestZonesService_CheckAvailableZones_SubscriptionNotFound(t *testing.T) {
 	userID := uuid.New()
 	worldID := uuid.New()
 	repo := newFakeZonesRepo()
 	repo.userByWorld[worldID] = userID
 
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusUnauthorized)
-	}))
-	defer server.Close()
-
-	conf := config.CreateConfig()
-	portStr := strings.TrimPrefix(server.URL, "http://127.0.0.1:")
-	port, _ := strconv.Atoi(portStr)
-	conf.Server.Port = port
+	subscriptions := &fakeSubscriptionsClient{checkErr: service_clients.NewSubscriptionNotFound("user does not have an active subscription")}
 
-	svc := NewZonesService(conf, repo, &fakeZonesRegistry{}).(*zonesService)
-	err := svc.checkAvailableZonesForActivation(worldID)
+	svc := NewZonesService(config.CreateConfig(), repo, &fakeZonesRegistry{}, subscriptions).(*zonesService)
+	err := svc.checkAvailableZonesForActivation(worldID, 1)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "forbidden")
 }
 
-func TestZonesService_CheckAvailableZones_ServerError(t *testing.T) {
+func TestZonesService_CheckAvailableZones_ClientError(t *testing.T) {
 	userID := uuid.New()
 	worldID := uuid.New()
 	repo := newFakeZonesRepo()
 	repo.userByWorld[worldID] = userID
 
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusInternalServerError)
-	}))
-	defer server.Close()
-
-	conf := config.CreateConfig()
-	portStr := strings.TrimPrefix(server.URL, "http://127.0.0.1:")
-	port, _ := strconv.Atoi(portStr)
-	conf.Server.Port = port
+	subscriptions := &fakeSubscriptionsClient{checkErr: service_clients.NewServiceUnavailable("failed to reach payment service to verify slots")}
 
-	svc := NewZonesService(conf, repo, &fakeZonesRegistry{}).(*zonesService)
-	err := svc.checkAvailableZonesForActivation(worldID)
+	svc := NewZonesService(config.CreateConfig(), repo, &fakeZonesRegistry{}, subscriptions).(*zonesService)
+	err := svc.checkAvailableZonesForActivation(worldID, 1)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to reach payment service")
 }
 
-func TestZonesService_CheckAvailableZones_DecodeError(t *testing.T) {
-	userID := uuid.New()
-	worldID := uuid.New()
-	repo := newFakeZonesRepo()
-	repo.userByWorld[worldID] = userID
-
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		_, _ = w.Write([]byte(`not-valid-json`))
-	}))
-	defer server.Close()
-
-	conf := config.CreateConfig()
-	portStr := strings.TrimPrefix(server.URL, "http://127.0.0.1:")
-	port, _ := strconv.Atoi(portStr)
-	conf.Server.Port = port
-
-	svc := NewZonesService(conf, repo, &fakeZonesRegistry{}).(*zonesService)
-	err := svc.checkAvailableZonesForActivation(worldID)
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "failed to decode")
-}
-
 func TestZonesService_CheckAvailableZones_UserIdError(t *testing.T) {
 	repo := newFakeZonesRepo()
 	worldID := uuid.New()
 	// Sin userByWorld → GetUserIdByWorldId falla
 
-	svc := NewZonesService(config.CreateConfig(), repo, &fakeZonesRegistry{}).(*zonesService)
-	err := svc.checkAvailableZonesForActivation(worldID)
+	svc := NewZonesService(config.CreateConfig(), repo, &fakeZonesRegistry{}, &fakeSubscriptionsClient{}).(*zonesService)
+	err := svc.checkAvailableZonesForActivation(worldID, 1)
 	assert.Error(t, err)
 }
 
-func TestZonesService_UpdateUsedSlots_ConnectionError(t *testing.T) {
+func TestZonesService_UpdateUsedSlots_ClientError(t *testing.T) {
 	userID := uuid.New()
 
-	// Servidor cerrado inmediatamente → error de conexión
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
-	server.Close()
-
-	conf := config.CreateConfig()
-	portStr := strings.TrimPrefix(server.URL, "http://127.0.0.1:")
-	port, _ := strconv.Atoi(portStr)
-	conf.Server.Port = port
+	subscriptions := &fakeSubscriptionsClient{updateErr: service_clients.NewServiceUnavailable("failed to reach payment service to update used slots")}
 
-	svc := NewZonesService(conf, newFakeZonesRepo(), &fakeZonesRegistry{}).(*zonesService)
+	svc := NewZonesService(config.CreateConfig(), newFakeZonesRepo(), &fakeZonesRegistry{}, subscriptions).(*zonesService)
 	err := svc.updateUsedSlots(userID, 1, true)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to reach payment service")
@@ -691,7 +772,7 @@ func TestZonesService_StopAllZonesForUser(t *testing.T) {
 	registry := &fakeZonesRegistry{}
 	conf := config.CreateConfig()
 	conf.Server.SubscriptionOn = false
-	svc := NewZonesService(conf, repo, registry)
+	svc := NewZonesService(conf, repo, registry, &fakeSubscriptionsClient{})
 
 	err := svc.StopAllZonesForUser(userID)
 	assert.NoError(t, err)
@@ -708,7 +789,7 @@ func TestZonesService_StopAllZonesForUser_GetZonesError(t *testing.T) {
 	registry := &fakeZonesRegistry{}
 	conf := config.CreateConfig()
 	conf.Server.SubscriptionOn = false
-	svc := NewZonesService(conf, repo, registry)
+	svc := NewZonesService(conf, repo, registry, &fakeSubscriptionsClient{})
 
 	err := svc.StopAllZonesForUser(userID)
 	assert.Error(t, err)
@@ -723,7 +804,7 @@ func TestZonesService_PlayerCounts(t *testing.T) {
 
 	registry := &fakeZonesRegistry{}
 	conf := config.CreateConfig()
-	svc := NewZonesService(conf, repo, registry)
+	svc := NewZonesService(conf, repo, registry, &fakeSubscriptionsClient{})
 
 	total, avg, maxTotal, maxAvg, err := svc.GetWorldZonePlayerCounts(worldID)
 	assert.NoError(t, err)
@@ -747,7 +828,7 @@ func TestZonesService_UpdateZoneStatusAndPlayerCount(t *testing.T) {
 
 	registry := &fakeZonesRegistry{}
 	conf := config.CreateConfig()
-	svc := NewZonesService(conf, repo, registry)
+	svc := NewZonesService(conf, repo, registry, &fakeSubscriptionsClient{})
 
 	assert.NoError(t, svc.UpdateZoneStatus(worldID, 1, true))
 	assert.True(t, repo.online[zoneKey(worldID, 1)])
@@ -766,7 +847,7 @@ func TestZonesService_GetWorldAndPublishZone(t *testing.T) {
 
 	registry := &fakeZonesRegistry{}
 	conf := config.CreateConfig()
-	svc := NewZonesService(conf, repo, registry)
+	svc := NewZonesService(conf, repo, registry, &fakeSubscriptionsClient{})
 
 	world, err := svc.GetWorld(worldID)
 	assert.NoError(t, err)
@@ -782,7 +863,7 @@ func TestZonesService_GetWorldZone(t *testing.T) {
 	worldID := uuid.New()
 	repo.zones[worldID] = []*models.WorldZone{{ID: 1, WorldID: worldID}}
 
-	svc := NewZonesService(config.CreateConfig(), repo, &fakeZonesRegistry{})
+	svc := NewZonesService(config.CreateConfig(), repo, &fakeZonesRegistry{}, &fakeSubscriptionsClient{})
 	zone, err := svc.GetWorldZone(worldID, 1)
 	assert.NoError(t, err)
 	assert.Equal(t, 1, zone.ID)
@@ -793,7 +874,7 @@ func TestZonesService_GetWorldZones(t *testing.T) {
 	worldID := uuid.New()
 	repo.zones[worldID] = []*models.WorldZone{{ID: 1, WorldID: worldID}, {ID: 2, WorldID: worldID}}
 
-	svc := NewZonesService(config.CreateConfig(), repo, &fakeZonesRegistry{})
+	svc := NewZonesService(config.CreateConfig(), repo, &fakeZonesRegistry{}, &fakeSubscriptionsClient{})
 	zones, err := svc.GetWorldZones(worldID)
 	assert.NoError(t, err)
 	assert.Len(t, zones, 2)
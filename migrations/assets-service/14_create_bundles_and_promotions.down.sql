@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP TABLE IF EXISTS cosmetic_promotions;
+DROP TABLE IF EXISTS cosmetic_bundle_items;
+DROP TABLE IF EXISTS cosmetic_bundles;
+
+COMMIT;
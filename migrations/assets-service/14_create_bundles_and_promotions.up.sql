@@ -0,0 +1,40 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS cosmetic_bundles (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    world_id UUID NOT NULL,
+    name TEXT NOT NULL,
+    price BIGINT NOT NULL CHECK (price > 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_by UUID NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_cosmetic_bundles_world_id ON cosmetic_bundles(world_id);
+
+CREATE TABLE IF NOT EXISTS cosmetic_bundle_items (
+    bundle_id UUID NOT NULL REFERENCES cosmetic_bundles(id) ON DELETE CASCADE,
+    cosmetic_id UUID NOT NULL REFERENCES cosmetics(id) ON DELETE CASCADE,
+    PRIMARY KEY (bundle_id, cosmetic_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_cosmetic_bundle_items_cosmetic_id ON cosmetic_bundle_items(cosmetic_id);
+
+CREATE TABLE IF NOT EXISTS cosmetic_promotions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    world_id UUID NOT NULL,
+    cosmetic_id UUID REFERENCES cosmetics(id) ON DELETE CASCADE,
+    bundle_id UUID REFERENCES cosmetic_bundles(id) ON DELETE CASCADE,
+    percent_off INT NOT NULL CHECK (percent_off BETWEEN 1 AND 99),
+    starts_at TIMESTAMPTZ NOT NULL,
+    ends_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_by UUID NOT NULL,
+    CHECK (ends_at > starts_at),
+    CHECK (cosmetic_id IS NULL OR bundle_id IS NULL)
+);
+
+CREATE INDEX IF NOT EXISTS idx_cosmetic_promotions_world_window ON cosmetic_promotions(world_id, starts_at, ends_at);
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_purchases_gifted_by;
+
+ALTER TABLE purchases DROP COLUMN IF EXISTS gifted_by;
+
+COMMIT;
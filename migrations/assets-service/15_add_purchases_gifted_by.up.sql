@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE purchases ADD COLUMN IF NOT EXISTS gifted_by UUID;
+
+CREATE INDEX IF NOT EXISTS idx_purchases_gifted_by ON purchases(gifted_by) WHERE gifted_by IS NOT NULL;
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+
+ALTER TABLE users
+ADD COLUMN IF NOT EXISTS refresh_token_updated_at TIMESTAMPTZ DEFAULT NOW();
+
+DROP INDEX IF EXISTS idx_sessions_user_id_active;
+DROP TABLE IF EXISTS sessions;
+
+COMMIT;
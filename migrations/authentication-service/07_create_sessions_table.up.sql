@@ -0,0 +1,27 @@
+BEGIN;
+
+CREATE TABLE sessions (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL,
+    refresh_token_hash TEXT NOT NULL,
+    user_agent TEXT NOT NULL DEFAULT '',
+    ip_address TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_used_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMPTZ NOT NULL,
+    revoked_at TIMESTAMPTZ,
+    revoked_reason TEXT NOT NULL DEFAULT '',
+
+    CONSTRAINT fk_session_user
+        FOREIGN KEY (user_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX idx_sessions_user_id_active ON sessions(user_id) WHERE revoked_at IS NULL;
+
+-- Refresh tokens are now tracked per session, existing tokens carry no session and stop working.
+ALTER TABLE users
+DROP COLUMN IF EXISTS refresh_token_updated_at;
+
+COMMIT;
@@ -0,0 +1,13 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_users_unlock_token_hash;
+
+ALTER TABLE users
+DROP COLUMN IF EXISTS unlock_token_hash,
+DROP COLUMN IF EXISTS locked_until,
+DROP COLUMN IF EXISTS lockout_count,
+DROP COLUMN IF EXISTS failed_login_attempts;
+
+DROP TABLE IF EXISTS rate_limit_buckets;
+
+COMMIT;
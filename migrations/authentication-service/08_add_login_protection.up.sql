@@ -0,0 +1,20 @@
+BEGIN;
+
+-- Token buckets shared by every instance when RATE_LIMIT_BACKEND=postgres.
+CREATE TABLE rate_limit_buckets (
+    key TEXT PRIMARY KEY,
+    tokens DOUBLE PRECISION NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_rate_limit_buckets_updated_at ON rate_limit_buckets(updated_at);
+
+ALTER TABLE users
+ADD COLUMN failed_login_attempts INT NOT NULL DEFAULT 0,
+ADD COLUMN lockout_count INT NOT NULL DEFAULT 0,
+ADD COLUMN locked_until TIMESTAMPTZ,
+ADD COLUMN unlock_token_hash TEXT;
+
+CREATE UNIQUE INDEX idx_users_unlock_token_hash ON users(unlock_token_hash) WHERE unlock_token_hash IS NOT NULL;
+
+COMMIT;
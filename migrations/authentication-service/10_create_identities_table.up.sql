@@ -0,0 +1,39 @@
+BEGIN;
+
+-- Accounts signed in with a third-party OIDC provider, subject is the provider user id.
+CREATE TABLE identities (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL,
+    provider TEXT NOT NULL,
+    subject TEXT NOT NULL,
+    email TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT fk_identity_user
+        FOREIGN KEY (user_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE,
+    CONSTRAINT uq_identities_provider_subject UNIQUE (provider, subject),
+    CONSTRAINT uq_identities_user_provider UNIQUE (user_id, provider)
+);
+
+-- Pending authorization requests, consumed when the provider redirects back.
+CREATE TABLE oidc_login_states (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    state_hash TEXT NOT NULL UNIQUE,
+    provider TEXT NOT NULL,
+    code_verifier TEXT NOT NULL,
+    nonce TEXT NOT NULL,
+    link_user_id UUID,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT fk_oidc_login_state_user
+        FOREIGN KEY (link_user_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX idx_oidc_login_states_expires_at ON oidc_login_states(expires_at);
+
+COMMIT;
@@ -0,0 +1,10 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_users_deletion_cancel_token_hash;
+DROP INDEX IF EXISTS idx_users_deletion_scheduled_at;
+
+ALTER TABLE users
+DROP COLUMN IF EXISTS deletion_cancel_token_hash,
+DROP COLUMN IF EXISTS deletion_scheduled_at;
+
+COMMIT;
@@ -0,0 +1,11 @@
+BEGIN;
+
+-- Accounts pending deletion keep working rows until the grace period ends and the purge erases them.
+ALTER TABLE users
+ADD COLUMN deletion_scheduled_at TIMESTAMPTZ,
+ADD COLUMN deletion_cancel_token_hash TEXT;
+
+CREATE INDEX idx_users_deletion_scheduled_at ON users(deletion_scheduled_at) WHERE deletion_scheduled_at IS NOT NULL;
+CREATE UNIQUE INDEX idx_users_deletion_cancel_token_hash ON users(deletion_cancel_token_hash) WHERE deletion_cancel_token_hash IS NOT NULL;
+
+COMMIT;
@@ -0,0 +1,17 @@
+BEGIN;
+
+CREATE TABLE email_changes (
+    user_id UUID PRIMARY KEY,
+    new_email TEXT NOT NULL,
+    verification_code TEXT NOT NULL DEFAULT '',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMPTZ NOT NULL,
+
+    CONSTRAINT fk_user
+        FOREIGN KEY (user_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE
+);
+
+COMMIT;
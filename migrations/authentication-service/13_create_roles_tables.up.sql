@@ -0,0 +1,56 @@
+BEGIN;
+
+CREATE TABLE roles (
+    name TEXT PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE role_permissions (
+    role_name TEXT NOT NULL,
+    permission TEXT NOT NULL,
+
+    PRIMARY KEY (role_name, permission),
+    CONSTRAINT fk_role
+        FOREIGN KEY (role_name)
+        REFERENCES roles(name)
+        ON DELETE CASCADE
+);
+
+CREATE TABLE user_roles (
+    user_id UUID NOT NULL,
+    role_name TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (user_id, role_name),
+    CONSTRAINT fk_user
+        FOREIGN KEY (user_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_role
+        FOREIGN KEY (role_name)
+        REFERENCES roles(name)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX idx_user_roles_role_name ON user_roles(role_name);
+
+-- Staff roles, admins keep every permission through is_admin
+INSERT INTO roles (name, description) VALUES
+    ('support', 'Views users, balances and subscriptions'),
+    ('moderator', 'Removes cosmetics and items'),
+    ('finance', 'Views payment metrics, sales and payouts'),
+    ('operator', 'Starts and stops world server jobs');
+
+INSERT INTO role_permissions (role_name, permission) VALUES
+    ('support', 'auth.users.read'),
+    ('support', 'payments.balances.read'),
+    ('support', 'payments.subscriptions.read'),
+    ('moderator', 'assets.content.delete'),
+    ('finance', 'payments.metrics.read'),
+    ('finance', 'payments.sales.read'),
+    ('finance', 'payments.payouts.read'),
+    ('finance', 'payments.balances.read'),
+    ('operator', 'world.jobs.write');
+
+COMMIT;
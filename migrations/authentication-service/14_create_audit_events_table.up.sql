@@ -0,0 +1,32 @@
+BEGIN;
+
+CREATE TABLE audit_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    actor_id UUID NOT NULL,
+    action TEXT NOT NULL,
+    target_type TEXT NOT NULL DEFAULT '',
+    target_id TEXT NOT NULL DEFAULT '',
+    before JSONB,
+    after JSONB,
+    request_id TEXT NOT NULL DEFAULT '',
+    ip_address TEXT NOT NULL DEFAULT '',
+    occurred_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX idx_audit_events_occurred_at ON audit_events(occurred_at DESC);
+CREATE INDEX idx_audit_events_actor_id ON audit_events(actor_id, occurred_at DESC);
+CREATE INDEX idx_audit_events_action ON audit_events(action, occurred_at DESC);
+CREATE INDEX idx_audit_events_target ON audit_events(target_type, target_id, occurred_at DESC);
+
+-- The audit log is append-only, rows can never be changed or removed
+CREATE FUNCTION audit_events_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_events_append_only
+    BEFORE UPDATE OR DELETE ON audit_events
+    FOR EACH ROW EXECUTE FUNCTION audit_events_append_only();
+
+COMMIT;
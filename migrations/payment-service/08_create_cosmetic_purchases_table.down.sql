@@ -0,0 +1,8 @@
+BEGIN;
+
+ALTER TABLE gem_balances
+  DROP CONSTRAINT IF EXISTS gem_balances_gems_non_negative;
+
+DROP TABLE IF EXISTS cosmetic_purchases;
+
+COMMIT;
@@ -0,0 +1,24 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS cosmetic_purchases (
+  id               UUID          PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id          UUID          NOT NULL,
+  cosmetic_id      UUID          NOT NULL,
+  creator_id       UUID,
+  price            BIGINT        NOT NULL,
+  creator_earnings NUMERIC(10,2) NOT NULL DEFAULT 0,
+  status           VARCHAR(20)   NOT NULL DEFAULT 'pending',
+  created_at       TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+  updated_at       TIMESTAMPTZ   NOT NULL DEFAULT NOW()
+);
+
+-- A user can only hold one non-refunded purchase of the same cosmetic
+CREATE UNIQUE INDEX IF NOT EXISTS cosmetic_purchases_user_cosmetic_active_idx
+  ON cosmetic_purchases (user_id, cosmetic_id)
+  WHERE status <> 'refunded';
+
+-- NOT VALID so rows overdrawn before this migration do not block it, new writes are still checked
+ALTER TABLE gem_balances
+  ADD CONSTRAINT gem_balances_gems_non_negative CHECK (gems >= 0) NOT VALID;
+
+COMMIT;
@@ -0,0 +1,22 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS gem_transactions (
+  id           UUID         PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id      UUID         NOT NULL,
+  type         VARCHAR(32)  NOT NULL,
+  amount       BIGINT       NOT NULL,
+  reference_id VARCHAR(255) NOT NULL DEFAULT '',
+  actor_id     UUID,
+  created_at   TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS gem_transactions_user_created_idx
+  ON gem_transactions (user_id, created_at DESC);
+
+-- Balances that existed before the ledger get an opening entry so the ledger reconciles with them
+INSERT INTO gem_transactions (user_id, type, amount, reference_id, created_at)
+SELECT user_id, 'admin_adjustment', gems, 'opening_balance', NOW()
+FROM gem_balances
+WHERE gems <> 0;
+
+COMMIT;
@@ -0,0 +1,19 @@
+BEGIN;
+
+DROP INDEX IF EXISTS gem_transactions_reference_idx;
+DROP INDEX IF EXISTS processed_stripe_webhook_events_session_idx;
+DROP INDEX IF EXISTS processed_stripe_webhook_events_credit_session_idx;
+
+DELETE FROM processed_stripe_webhook_events
+WHERE event_type <> 'checkout.session.completed';
+
+ALTER TABLE processed_stripe_webhook_events
+  ADD CONSTRAINT processed_stripe_webhook_events_session_id_key UNIQUE (session_id);
+
+ALTER TABLE gem_balances
+  DROP COLUMN IF EXISTS locked;
+
+ALTER TABLE gem_balances
+  ADD CONSTRAINT gem_balances_gems_non_negative CHECK (gems >= 0) NOT VALID;
+
+COMMIT;
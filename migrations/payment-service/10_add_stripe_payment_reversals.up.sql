@@ -0,0 +1,27 @@
+BEGIN;
+
+-- Refunds and chargebacks claw back gems that may already be spent, so balances can go negative.
+-- Purchases keep refusing to overdraw through their conditional debit.
+ALTER TABLE gem_balances
+  DROP CONSTRAINT IF EXISTS gem_balances_gems_non_negative;
+
+-- Locked while a payment dispute is open
+ALTER TABLE gem_balances
+  ADD COLUMN IF NOT EXISTS locked BOOLEAN NOT NULL DEFAULT false;
+
+-- Refund and dispute events reference the same payment intent as the credit they reverse,
+-- only the credit itself must stay unique per payment intent
+ALTER TABLE processed_stripe_webhook_events
+  DROP CONSTRAINT IF EXISTS processed_stripe_webhook_events_session_id_key;
+
+CREATE UNIQUE INDEX IF NOT EXISTS processed_stripe_webhook_events_credit_session_idx
+  ON processed_stripe_webhook_events (session_id)
+  WHERE event_type = 'checkout.session.completed';
+
+CREATE INDEX IF NOT EXISTS processed_stripe_webhook_events_session_idx
+  ON processed_stripe_webhook_events (session_id);
+
+CREATE INDEX IF NOT EXISTS gem_transactions_reference_idx
+  ON gem_transactions (reference_id);
+
+COMMIT;
@@ -0,0 +1,10 @@
+BEGIN;
+
+DROP TABLE IF EXISTS creator_payout_events;
+DROP TABLE IF EXISTS creator_payouts;
+
+ALTER TABLE creator_balances
+  DROP COLUMN IF EXISTS payout_account_id,
+  DROP COLUMN IF EXISTS held_balance;
+
+COMMIT;
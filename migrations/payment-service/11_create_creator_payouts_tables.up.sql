@@ -0,0 +1,39 @@
+BEGIN;
+
+ALTER TABLE creator_balances
+  ADD COLUMN IF NOT EXISTS held_balance NUMERIC(10,2) NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS payout_account_id VARCHAR(255) NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS creator_payouts (
+  id                 UUID          PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id            UUID          NOT NULL,
+  amount             NUMERIC(10,2) NOT NULL CHECK (amount > 0),
+  status             VARCHAR(20)   NOT NULL DEFAULT 'requested',
+  payout_account_id  VARCHAR(255)  NOT NULL,
+  provider_reference VARCHAR(255)  NOT NULL DEFAULT '',
+  rejection_reason   TEXT          NOT NULL DEFAULT '',
+  created_at         TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+  updated_at         TIMESTAMPTZ   NOT NULL DEFAULT NOW()
+);
+
+-- A creator can only have one payout waiting for review or payment
+CREATE UNIQUE INDEX IF NOT EXISTS creator_payouts_user_pending_idx
+  ON creator_payouts (user_id)
+  WHERE status IN ('requested', 'approved');
+
+CREATE INDEX IF NOT EXISTS creator_payouts_status_created_idx
+  ON creator_payouts (status, created_at);
+
+CREATE TABLE IF NOT EXISTS creator_payout_events (
+  id         UUID        PRIMARY KEY DEFAULT gen_random_uuid(),
+  payout_id  UUID        NOT NULL REFERENCES creator_payouts(id) ON DELETE CASCADE,
+  status     VARCHAR(20) NOT NULL,
+  actor_id   UUID,
+  note       TEXT        NOT NULL DEFAULT '',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS creator_payout_events_payout_idx
+  ON creator_payout_events (payout_id, created_at);
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+DROP TABLE IF EXISTS cosmetic_sales;
+
+ALTER TABLE cosmetic_purchases
+  DROP COLUMN IF EXISTS world_id;
+
+COMMIT;
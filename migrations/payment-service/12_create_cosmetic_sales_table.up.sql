@@ -0,0 +1,30 @@
+BEGIN;
+
+ALTER TABLE cosmetic_purchases
+  ADD COLUMN IF NOT EXISTS world_id UUID;
+
+CREATE TABLE IF NOT EXISTS cosmetic_sales (
+  id            UUID          PRIMARY KEY DEFAULT gen_random_uuid(),
+  purchase_id   UUID          NOT NULL UNIQUE REFERENCES cosmetic_purchases(id),
+  cosmetic_id   UUID          NOT NULL,
+  world_id      UUID,
+  buyer_id      UUID          NOT NULL,
+  creator_id    UUID,
+  price         BIGINT        NOT NULL,
+  creator_share NUMERIC(10,2) NOT NULL DEFAULT 0,
+  created_at    TIMESTAMPTZ   NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS cosmetic_sales_created_idx ON cosmetic_sales (created_at);
+CREATE INDEX IF NOT EXISTS cosmetic_sales_cosmetic_created_idx ON cosmetic_sales (cosmetic_id, created_at);
+CREATE INDEX IF NOT EXISTS cosmetic_sales_world_created_idx ON cosmetic_sales (world_id, created_at);
+CREATE INDEX IF NOT EXISTS cosmetic_sales_creator_created_idx ON cosmetic_sales (creator_id, created_at);
+
+-- Purchases completed before this migration become sales without a world
+INSERT INTO cosmetic_sales (purchase_id, cosmetic_id, buyer_id, creator_id, price, creator_share, created_at)
+SELECT id, cosmetic_id, user_id, creator_id, price, creator_earnings, updated_at
+FROM cosmetic_purchases
+WHERE status = 'completed'
+ON CONFLICT (purchase_id) DO NOTHING;
+
+COMMIT;
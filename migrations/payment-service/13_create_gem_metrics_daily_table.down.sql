@@ -0,0 +1,19 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS gem_metrics (
+  id SMALLINT PRIMARY KEY DEFAULT 1,
+  gems_bought BIGINT NOT NULL DEFAULT 0,
+  gems_spent BIGINT NOT NULL DEFAULT 0,
+  gems_revenue NUMERIC(12,2) NOT NULL DEFAULT 0,
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+INSERT INTO gem_metrics (id, gems_bought, gems_spent, gems_revenue, created_at)
+SELECT 1, COALESCE(SUM(gems_bought), 0), COALESCE(SUM(gems_spent), 0), COALESCE(SUM(gems_revenue), 0), COALESCE(MIN(created_at), NOW())
+FROM gem_metrics_daily
+ON CONFLICT (id) DO NOTHING;
+
+DROP TABLE IF EXISTS gem_metrics_daily;
+
+COMMIT;
@@ -0,0 +1,21 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS gem_metrics_daily (
+  day          DATE          PRIMARY KEY,
+  gems_bought  BIGINT        NOT NULL DEFAULT 0,
+  gems_spent   BIGINT        NOT NULL DEFAULT 0,
+  gems_revenue NUMERIC(12,2) NOT NULL DEFAULT 0,
+  created_at   TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+  updated_at   TIMESTAMPTZ   NOT NULL DEFAULT NOW()
+);
+
+-- Lifetime totals land in the bucket of the day they started counting, using the default STRIPE_BILLING_TIMEZONE
+INSERT INTO gem_metrics_daily (day, gems_bought, gems_spent, gems_revenue)
+SELECT (COALESCE(created_at, NOW()) AT TIME ZONE 'America/Argentina/Buenos_Aires')::date, gems_bought, gems_spent, gems_revenue
+FROM gem_metrics
+WHERE id = 1
+ON CONFLICT (day) DO NOTHING;
+
+DROP TABLE IF EXISTS gem_metrics;
+
+COMMIT;
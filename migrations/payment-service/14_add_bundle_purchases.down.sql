@@ -0,0 +1,19 @@
+BEGIN;
+
+DELETE FROM cosmetic_sales WHERE bundle_id IS NOT NULL;
+DELETE FROM cosmetic_purchases WHERE bundle_id IS NOT NULL;
+
+DROP INDEX IF EXISTS cosmetic_sales_bundle_created_idx;
+
+ALTER TABLE cosmetic_sales
+  DROP COLUMN IF EXISTS bundle_id,
+  ALTER COLUMN cosmetic_id SET NOT NULL;
+
+DROP INDEX IF EXISTS cosmetic_purchases_user_bundle_active_idx;
+
+ALTER TABLE cosmetic_purchases
+  DROP CONSTRAINT IF EXISTS cosmetic_purchases_single_item,
+  DROP COLUMN IF EXISTS bundle_id,
+  ALTER COLUMN cosmetic_id SET NOT NULL;
+
+COMMIT;
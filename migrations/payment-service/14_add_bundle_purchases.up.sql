@@ -0,0 +1,20 @@
+BEGIN;
+
+-- A purchase is either a single cosmetic or a bundle
+ALTER TABLE cosmetic_purchases
+  ALTER COLUMN cosmetic_id DROP NOT NULL,
+  ADD COLUMN IF NOT EXISTS bundle_id UUID,
+  ADD CONSTRAINT cosmetic_purchases_single_item CHECK ((cosmetic_id IS NULL) <> (bundle_id IS NULL));
+
+-- A user can only hold one non-refunded purchase of the same bundle
+CREATE UNIQUE INDEX IF NOT EXISTS cosmetic_purchases_user_bundle_active_idx
+  ON cosmetic_purchases (user_id, bundle_id)
+  WHERE status <> 'refunded' AND bundle_id IS NOT NULL;
+
+ALTER TABLE cosmetic_sales
+  ALTER COLUMN cosmetic_id DROP NOT NULL,
+  ADD COLUMN IF NOT EXISTS bundle_id UUID;
+
+CREATE INDEX IF NOT EXISTS cosmetic_sales_bundle_created_idx ON cosmetic_sales (bundle_id, created_at);
+
+COMMIT;
@@ -0,0 +1,18 @@
+BEGIN;
+
+DROP INDEX IF EXISTS cosmetic_purchases_gifts_sent_idx;
+
+-- Gifts would collide with the buyer's own purchases under the old index
+DELETE FROM cosmetic_sales WHERE purchase_id IN (SELECT id FROM cosmetic_purchases WHERE recipient_id IS NOT NULL);
+DELETE FROM cosmetic_purchases WHERE recipient_id IS NOT NULL;
+
+DROP INDEX IF EXISTS cosmetic_purchases_owner_cosmetic_active_idx;
+CREATE UNIQUE INDEX IF NOT EXISTS cosmetic_purchases_user_cosmetic_active_idx
+  ON cosmetic_purchases (user_id, cosmetic_id)
+  WHERE status <> 'refunded';
+
+ALTER TABLE cosmetic_purchases
+  DROP COLUMN IF EXISTS gift_message,
+  DROP COLUMN IF EXISTS recipient_id;
+
+COMMIT;
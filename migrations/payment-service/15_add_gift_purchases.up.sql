@@ -0,0 +1,19 @@
+BEGIN;
+
+-- A gift is paid by user_id and granted to recipient_id
+ALTER TABLE cosmetic_purchases
+  ADD COLUMN IF NOT EXISTS recipient_id UUID,
+  ADD COLUMN IF NOT EXISTS gift_message VARCHAR(200) NOT NULL DEFAULT '';
+
+-- Ownership is tracked on whoever receives the cosmetic
+DROP INDEX IF EXISTS cosmetic_purchases_user_cosmetic_active_idx;
+CREATE UNIQUE INDEX IF NOT EXISTS cosmetic_purchases_owner_cosmetic_active_idx
+  ON cosmetic_purchases (COALESCE(recipient_id, user_id), cosmetic_id)
+  WHERE status <> 'refunded';
+
+-- Backs the daily gift limit count
+CREATE INDEX IF NOT EXISTS cosmetic_purchases_gifts_sent_idx
+  ON cosmetic_purchases (user_id, created_at)
+  WHERE recipient_id IS NOT NULL;
+
+COMMIT;
@@ -0,0 +1,14 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS server_credentials (
+    world_id UUID NOT NULL,
+    zone_id INTEGER NOT NULL,
+    token_hash TEXT NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (world_id, zone_id),
+    CONSTRAINT fk_server_credentials_world_zones FOREIGN KEY (world_id, zone_id)
+        REFERENCES world_zones(world_id, id)
+        ON DELETE CASCADE
+);
+
+COMMIT;
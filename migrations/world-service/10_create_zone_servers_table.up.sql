@@ -0,0 +1,21 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS zone_servers (
+    world_id UUID NOT NULL,
+    zone_id INTEGER NOT NULL,
+    job_id TEXT NOT NULL,
+    alloc_id TEXT NOT NULL DEFAULT '',
+    address TEXT NOT NULL DEFAULT '',
+    port INTEGER NOT NULL DEFAULT 0,
+    image_version TEXT NOT NULL DEFAULT '',
+    started_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_heartbeat_at TIMESTAMPTZ,
+    PRIMARY KEY (world_id, zone_id),
+    CONSTRAINT fk_zone_servers_world_zones FOREIGN KEY (world_id, zone_id)
+        REFERENCES world_zones(world_id, id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_zone_servers_last_heartbeat_at ON zone_servers(last_heartbeat_at);
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS deployment_zones;
+DROP TABLE IF EXISTS server_deployments;
+
+COMMIT;
@@ -0,0 +1,38 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS server_deployments (
+    id UUID PRIMARY KEY,
+    image TEXT NOT NULL,
+    previous_image TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL,
+    source TEXT NOT NULL DEFAULT '',
+    batch_size INTEGER NOT NULL,
+    batch_pause_seconds INTEGER NOT NULL,
+    current_batch INTEGER NOT NULL DEFAULT 0,
+    total_batches INTEGER NOT NULL DEFAULT 0,
+    batch_finished_at TIMESTAMPTZ,
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    finished_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_server_deployments_status ON server_deployments(status);
+CREATE INDEX IF NOT EXISTS idx_server_deployments_created_at ON server_deployments(created_at);
+
+CREATE TABLE IF NOT EXISTS deployment_zones (
+    deployment_id UUID NOT NULL REFERENCES server_deployments(id) ON DELETE CASCADE,
+    world_id UUID NOT NULL,
+    zone_id INTEGER NOT NULL,
+    batch INTEGER NOT NULL,
+    is_canary BOOLEAN NOT NULL DEFAULT FALSE,
+    status TEXT NOT NULL,
+    error TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (deployment_id, world_id, zone_id),
+    CONSTRAINT fk_deployment_zones_world_zones FOREIGN KEY (world_id, zone_id)
+        REFERENCES world_zones(world_id, id)
+        ON DELETE CASCADE
+);
+
+COMMIT;
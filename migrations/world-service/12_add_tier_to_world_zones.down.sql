@@ -0,0 +1,3 @@
+ALTER TABLE world_zones
+DROP COLUMN IF EXISTS slots,
+DROP COLUMN IF EXISTS tier;
@@ -0,0 +1,6 @@
+ALTER TABLE world_zones
+ADD COLUMN tier VARCHAR(32) NOT NULL DEFAULT '',
+ADD COLUMN slots INTEGER NOT NULL DEFAULT 0;
+
+-- Zones active before tiers existed hold the single slot they were charged
+UPDATE world_zones SET slots = 1 WHERE is_active;
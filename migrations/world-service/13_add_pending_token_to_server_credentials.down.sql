@@ -0,0 +1,8 @@
+BEGIN;
+
+UPDATE server_credentials SET token_hash = pending_token_hash WHERE token_hash IS NULL;
+DELETE FROM server_credentials WHERE token_hash IS NULL;
+ALTER TABLE server_credentials DROP COLUMN IF EXISTS pending_token_hash;
+ALTER TABLE server_credentials ALTER COLUMN token_hash SET NOT NULL;
+
+COMMIT;
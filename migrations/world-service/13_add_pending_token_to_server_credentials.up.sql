@@ -0,0 +1,7 @@
+BEGIN;
+
+-- A restarted server gets a pending token, the token of the running server keeps working until it started
+ALTER TABLE server_credentials ALTER COLUMN token_hash DROP NOT NULL;
+ALTER TABLE server_credentials ADD COLUMN IF NOT EXISTS pending_token_hash TEXT UNIQUE;
+
+COMMIT;